@@ -0,0 +1,464 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+const (
+	// DefaultAppendBlockSize is the number of bytes an Appender buffers
+	// before committing them as a block.
+	DefaultAppendBlockSize = 1 << 20
+	// DefaultEmulatedAppendMaxSize is the size at which an Appender rolls
+	// over to a new object on drivers without native append support. Every
+	// block rewrites the whole object there, so objects are kept small.
+	DefaultEmulatedAppendMaxSize = 16 << 20
+	// defaultAppendContentType is the content type of append objects if
+	// AppenderOptions.ContentType is empty.
+	defaultAppendContentType = "application/octet-stream"
+)
+
+var errAppenderClosed = kerr.Newf(kerr.FailedPrecondition, nil, "blob: Appender has been closed")
+
+// AppenderOptions sets options for NewAppender.
+type AppenderOptions struct {
+	// ContentType is the MIME type of the objects. Defaults to
+	// "application/octet-stream".
+	ContentType string
+	// Metadata holds key/value strings to be associated with newly created
+	// objects.
+	Metadata map[string]string
+	// BlockSize is the number of bytes buffered before they are committed
+	// as a block. It is capped at the block size limit of the driver.
+	// Defaults to DefaultAppendBlockSize.
+	BlockSize int
+	// MaxSize is the size in bytes at which the Appender rolls over to a new
+	// object. The limit of the driver applies if it is lower or MaxSize is
+	// zero.
+	MaxSize int64
+	// MaxBlocks is the number of blocks at which the Appender rolls over to
+	// a new object. The limit of the driver applies if it is lower or
+	// MaxBlocks is zero.
+	MaxBlocks int
+	// RolloverKey returns the key of the n-th object (n >= 1) following the
+	// object at key. Defaults to key + "." + n, e.g. "audit/2025-01-02.log.1".
+	RolloverKey func(key string, n int) string
+}
+
+// Appender writes a stream such as an audit log to append objects. Written
+// bytes are buffered and committed as blocks at the end of the current
+// object; when the object reaches its size or block limit, the Appender rolls
+// over to a new key.
+//
+// A single Write of at most BlockSize bytes is never split across blocks or
+// objects, so writing one record per call keeps records intact.
+//
+// Drivers implementing driver.Appender, such as Azure append blobs, commit
+// blocks natively. Other drivers are emulated by rewriting the object with
+// every block, which is only suitable for small volumes; see
+// DefaultEmulatedAppendMaxSize.
+//
+// An Appender is safe for concurrent use. It must be closed to commit the
+// buffered bytes.
+type Appender struct {
+	b           *Bucket
+	d           driver.Appender
+	ctx         context.Context
+	base        string
+	contentType string
+	dopts       *driver.AppendOptions
+	opts        AppenderOptions
+
+	mu        sync.Mutex
+	key       string
+	n         int
+	info      driver.AppendInfo
+	blockSize int
+	maxSize   int64
+	maxBlocks int
+	buf       []byte
+	closed    bool
+}
+
+// NewAppender returns an Appender that appends to the object at key, creating
+// it if it does not exist. If the object is already full, e.g. after a
+// restart, the Appender continues with the first rollover key that is not.
+// A nil AppenderOptions is treated the same as the zero value.
+//
+// The context is used for all appends; canceling it aborts them.
+//
+// Example:
+//
+//	a, err := bucket.NewAppender(ctx, "audit/2025-01-02.log", &blob.AppenderOptions{
+//	    ContentType: "application/x-ndjson",
+//	    MaxSize:     64 << 20,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer a.Close()
+//
+//	_, err = a.Write(append(record, '\n'))
+func (b *Bucket) NewAppender(ctx context.Context, key string, opts *AppenderOptions) (*Appender, error) {
+	if err := validateSnapshotKey("NewAppender", key); err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = &AppenderOptions{}
+	}
+
+	switch {
+	case opts.BlockSize < 0:
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: NewAppender BlockSize must be non-negative: %d", opts.BlockSize)
+	case opts.MaxSize < 0:
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: NewAppender MaxSize must be non-negative: %d", opts.MaxSize)
+	case opts.MaxBlocks < 0:
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: NewAppender MaxBlocks must be non-negative: %d", opts.MaxBlocks)
+	}
+
+	md, err := appendMetadata(opts.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Appender{
+		b:           b,
+		ctx:         ctx,
+		base:        key,
+		key:         key,
+		contentType: opts.ContentType,
+		dopts:       &driver.AppendOptions{Metadata: md},
+		opts:        *opts,
+	}
+
+	if a.contentType == "" {
+		a.contentType = defaultAppendContentType
+	}
+
+	if a.opts.BlockSize == 0 {
+		a.opts.BlockSize = DefaultAppendBlockSize
+	}
+
+	if a.opts.RolloverKey == nil {
+		a.opts.RolloverKey = func(key string, n int) string {
+			return fmt.Sprintf("%s.%d", key, n)
+		}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, errClosed
+	}
+
+	if b.quota != nil {
+		if err := b.quota.check(ctx, b.b); err != nil {
+			return nil, err
+		}
+	}
+
+	if d, ok := b.b.(driver.Appender); ok {
+		a.d = d
+	} else {
+		a.d = &emulatedAppender{b: b.b, contentType: a.contentType, metadata: md}
+	}
+
+	if err := a.open(); err != nil {
+		return nil, err
+	}
+
+	for a.full(1) {
+		if err := a.rollover(); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// Key returns the key of the object the Appender currently appends to.
+func (a *Appender) Key() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.key
+}
+
+// Write buffers p and commits full blocks. If p does not fit into the
+// current block, the buffered bytes are committed first, so that p starts a
+// new block. p is only split if it is larger than the block size.
+func (a *Appender) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return 0, errAppenderClosed
+	}
+
+	if len(a.buf) > 0 && len(a.buf)+len(p) > a.blockSize {
+		if err := a.flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := 0
+
+	for len(p)-n > a.blockSize {
+		if err := a.commit(p[n : n+a.blockSize]); err != nil {
+			return n, err
+		}
+
+		n += a.blockSize
+	}
+
+	a.buf = append(a.buf, p[n:]...)
+
+	if len(a.buf) == a.blockSize {
+		if err := a.flush(); err != nil {
+			// The bytes are buffered and committed by the next Flush.
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush commits the buffered bytes as a block.
+func (a *Appender) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return errAppenderClosed
+	}
+
+	return a.flush()
+}
+
+// Close commits the buffered bytes. The Appender cannot be used afterwards.
+func (a *Appender) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return errAppenderClosed
+	}
+
+	a.closed = true
+
+	return a.flush()
+}
+
+// flush commits the buffered bytes. The caller must hold a.mu.
+func (a *Appender) flush() error {
+	if len(a.buf) == 0 {
+		return nil
+	}
+
+	if err := a.commit(a.buf); err != nil {
+		return err
+	}
+
+	a.buf = a.buf[:0]
+
+	return nil
+}
+
+// commit appends p as a single block, rolling over first if p does not fit
+// into the current object. The caller must hold a.mu.
+func (a *Appender) commit(p []byte) error {
+	a.b.mu.RLock()
+	defer a.b.mu.RUnlock()
+
+	if a.b.closed {
+		return errClosed
+	}
+
+	for a.full(len(p)) {
+		if err := a.rollover(); err != nil {
+			return err
+		}
+	}
+
+	quota := a.b.quota
+	if quota != nil {
+		if err := quota.reserve(int64(len(p))); err != nil {
+			return err
+		}
+	}
+
+	if err := a.d.AppendBlock(a.ctx, a.key, a.info.Size, p); err != nil {
+		if quota != nil {
+			quota.release(int64(len(p)))
+		}
+
+		return wrapError(a.b.b, err, a.key)
+	}
+
+	if quota != nil {
+		quota.commit(int64(len(p)))
+	}
+
+	a.info.Size += int64(len(p))
+	a.info.Blocks++
+
+	return nil
+}
+
+// full reports whether a block of n bytes exceeds the limits of the current
+// object. An empty object is never full.
+func (a *Appender) full(n int) bool {
+	if a.info.Size == 0 && a.info.Blocks == 0 {
+		return false
+	}
+
+	return (a.maxBlocks > 0 && a.info.Blocks >= a.maxBlocks) ||
+		(a.maxSize > 0 && a.info.Size+int64(n) > a.maxSize)
+}
+
+// rollover continues with the next rollover key.
+func (a *Appender) rollover() error {
+	a.n++
+	a.key = a.opts.RolloverKey(a.base, a.n)
+
+	if a.key == "" || !utf8.ValidString(a.key) {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: Appender rollover key must be a non-empty, valid UTF-8 string: %q", a.key)
+	}
+
+	return a.open()
+}
+
+// open opens the object at a.key and applies the limits of the driver.
+func (a *Appender) open() error {
+	info, err := a.d.OpenAppend(a.ctx, a.key, a.contentType, a.dopts)
+	if err != nil {
+		return wrapError(a.b.b, err, a.key)
+	}
+
+	a.info = *info
+	a.blockSize = a.opts.BlockSize
+	a.maxSize = limit(a.opts.MaxSize, info.MaxSize)
+	a.maxBlocks = int(limit(int64(a.opts.MaxBlocks), int64(info.MaxBlocks)))
+
+	if info.MaxBlockSize > 0 {
+		a.blockSize = min(a.blockSize, info.MaxBlockSize)
+	}
+
+	if a.maxSize > 0 && int64(a.blockSize) > a.maxSize {
+		a.blockSize = int(a.maxSize)
+	}
+
+	return nil
+}
+
+// limit returns the lower of two limits, where zero means unlimited.
+func limit(a, b int64) int64 {
+	switch {
+	case a == 0:
+		return b
+	case b == 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
+
+// appendMetadata validates metadata and lowercases its keys like NewWriter.
+func appendMetadata(metadata map[string]string) (map[string]string, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	md := make(map[string]string, len(metadata))
+
+	for k, v := range metadata {
+		if k == "" || !utf8.ValidString(k) || !utf8.ValidString(v) {
+			return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: AppenderOptions.Metadata must have non-empty, valid UTF-8 keys and values: %q", k)
+		}
+
+		lowerK := strings.ToLower(k)
+		if _, found := md[lowerK]; found {
+			return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: AppenderOptions.Metadata has a duplicate case-insensitive metadata key: %q", lowerK)
+		}
+
+		md[lowerK] = v
+	}
+
+	return md, nil
+}
+
+// emulatedAppender implements driver.Appender for drivers without append
+// support by rewriting the object with every block.
+type emulatedAppender struct {
+	b           driver.Bucket
+	contentType string
+	metadata    map[string]string
+}
+
+// OpenAppend implements driver.Appender. Objects are created with the first
+// block.
+func (e *emulatedAppender) OpenAppend(ctx context.Context, key, _ string, _ *driver.AppendOptions) (*driver.AppendInfo, error) {
+	info := &driver.AppendInfo{MaxSize: DefaultEmulatedAppendMaxSize}
+
+	r, err := e.b.NewRangeReader(ctx, key, 0, 0, &driver.ReaderOptions{})
+	if err != nil {
+		if errorCode(e.b, err) == kerr.NotFound {
+			return info, nil
+		}
+
+		return nil, err
+	}
+	defer r.Close()
+
+	info.Size = r.Attributes().Size
+
+	return info, nil
+}
+
+// AppendBlock implements driver.Appender.
+func (e *emulatedAppender) AppendBlock(ctx context.Context, key string, offset int64, p []byte) error {
+	var current []byte
+
+	if offset > 0 {
+		r, err := e.b.NewRangeReader(ctx, key, 0, -1, &driver.ReaderOptions{})
+		if err != nil {
+			return err
+		}
+
+		current, err = io.ReadAll(r)
+		_ = r.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if int64(len(current)) != offset {
+		return kerr.Newf(kerr.FailedPrecondition, nil, "blob: append object %q has size %d, expected %d", key, len(current), offset)
+	}
+
+	w, err := e.b.NewTypedWriter(ctx, key, e.contentType, &driver.WriterOptions{Metadata: e.metadata})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, io.MultiReader(bytes.NewReader(current), bytes.NewReader(p))); err != nil {
+		_ = w.Close()
+		return err
+	}
+
+	return w.Close()
+}
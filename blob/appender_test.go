@@ -0,0 +1,223 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// memAppender is an in-memory driver.Appender with configurable limits.
+type memAppender struct {
+	limits  driver.AppendInfo
+	objects map[string][]byte
+	blocks  map[string][][]byte
+}
+
+func newMemAppender(limits driver.AppendInfo) *memAppender {
+	return &memAppender{limits: limits, objects: map[string][]byte{}, blocks: map[string][][]byte{}}
+}
+
+func (m *memAppender) OpenAppend(_ context.Context, key, _ string, _ *driver.AppendOptions) (*driver.AppendInfo, error) {
+	if _, ok := m.objects[key]; !ok {
+		m.objects[key] = []byte{}
+	}
+
+	info := m.limits
+	info.Size = int64(len(m.objects[key]))
+	info.Blocks = len(m.blocks[key])
+
+	return &info, nil
+}
+
+func (m *memAppender) AppendBlock(_ context.Context, key string, offset int64, p []byte) error {
+	if int64(len(m.objects[key])) != offset {
+		return kerr.Newf(kerr.FailedPrecondition, nil, "unexpected offset %d", offset)
+	}
+
+	m.objects[key] = append(m.objects[key], p...)
+	m.blocks[key] = append(m.blocks[key], bytes.Clone(p))
+
+	return nil
+}
+
+// appenderDriver is a driver.Bucket that also implements driver.Appender.
+type appenderDriver struct {
+	*MockBucket
+	*memAppender
+}
+
+func newAppenderBucket(t *testing.T, limits driver.AppendInfo) (*blob.Bucket, *memAppender) {
+	t.Helper()
+
+	m := newMemAppender(limits)
+
+	return blob.NewBucketForTest(&appenderDriver{MockBucket: NewMockBucket(gomock.NewController(t)), memAppender: m}), m
+}
+
+func TestAppender_Blocks(t *testing.T) {
+	bucket, m := newAppenderBucket(t, driver.AppendInfo{})
+	ctx := context.Background()
+
+	a, err := bucket.NewAppender(ctx, "audit.log", &blob.AppenderOptions{BlockSize: 4})
+	require.NoError(t, err)
+
+	_, err = a.Write([]byte("ab"))
+	require.NoError(t, err)
+	_, err = a.Write([]byte("cd"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("abcd")}, m.blocks["audit.log"])
+
+	// a write that does not fit into the current block starts a new one
+	_, err = a.Write([]byte("e"))
+	require.NoError(t, err)
+	_, err = a.Write([]byte("fgh"))
+	require.NoError(t, err)
+	_, err = a.Write([]byte("ij"))
+	require.NoError(t, err)
+
+	// a write larger than a block is split
+	_, err = a.Write([]byte("klmnopq"))
+	require.NoError(t, err)
+
+	require.NoError(t, a.Close())
+	assert.Equal(t, "abcdefghijklmnopq", string(m.objects["audit.log"]))
+	assert.Equal(t, []string{"abcd", "efgh", "ij", "klmn", "opq"}, blockStrings(m.blocks["audit.log"]))
+
+	_, err = a.Write([]byte("x"))
+	assert.True(t, kerr.Is(err, kerr.FailedPrecondition))
+}
+
+func TestAppender_Rollover(t *testing.T) {
+	t.Run("size limit", func(t *testing.T) {
+		bucket, m := newAppenderBucket(t, driver.AppendInfo{})
+		ctx := context.Background()
+
+		a, err := bucket.NewAppender(ctx, "audit.log", &blob.AppenderOptions{BlockSize: 4, MaxSize: 6})
+		require.NoError(t, err)
+
+		for _, rec := range []string{"aaa\n", "bb\n", "cc\n", "dddd"} {
+			_, err = a.Write([]byte(rec))
+			require.NoError(t, err)
+			require.NoError(t, a.Flush())
+		}
+
+		require.NoError(t, a.Close())
+		assert.Equal(t, "aaa\n", string(m.objects["audit.log"]))
+		assert.Equal(t, "bb\ncc\n", string(m.objects["audit.log.1"]))
+		assert.Equal(t, "dddd", string(m.objects["audit.log.2"]))
+		assert.Equal(t, "audit.log.2", a.Key())
+	})
+
+	t.Run("driver block limit", func(t *testing.T) {
+		bucket, m := newAppenderBucket(t, driver.AppendInfo{MaxBlocks: 2, MaxBlockSize: 2})
+		ctx := context.Background()
+
+		a, err := bucket.NewAppender(ctx, "log", &blob.AppenderOptions{
+			RolloverKey: func(key string, n int) string { return key + "-" + string(rune('a'+n-1)) },
+		})
+		require.NoError(t, err)
+
+		_, err = a.Write([]byte("1234567"))
+		require.NoError(t, err)
+		require.NoError(t, a.Close())
+
+		assert.Equal(t, []string{"12", "34"}, blockStrings(m.blocks["log"]))
+		assert.Equal(t, []string{"56", "7"}, blockStrings(m.blocks["log-a"]))
+	})
+
+	t.Run("continues after full objects", func(t *testing.T) {
+		bucket, m := newAppenderBucket(t, driver.AppendInfo{MaxSize: 4})
+		m.objects["audit.log"] = []byte("full")
+		m.objects["audit.log.1"] = []byte("full")
+		ctx := context.Background()
+
+		a, err := bucket.NewAppender(ctx, "audit.log", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "audit.log.2", a.Key())
+	})
+}
+
+func TestAppender_Emulated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDriver := NewMockBucket(ctrl)
+	stored := map[string]*storedBlob{}
+	ctx := context.Background()
+
+	mockDriver.EXPECT().NewRangeReader(gomock.Any(), gomock.Any(), int64(0), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string, _, _ int64, _ *driver.ReaderOptions) (driver.Reader, error) {
+			b, ok := stored[key]
+			if !ok {
+				return nil, kerr.New(kerr.NotFound, "not found")
+			}
+
+			data := bytes.Clone(b.data.Bytes())
+
+			return &memReader{Reader: bytes.NewReader(data), attrs: driver.ReaderAttributes{Size: int64(len(data))}}, nil
+		}).AnyTimes()
+	mockDriver.EXPECT().NewTypedWriter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key, contentType string, _ *driver.WriterOptions) (driver.Writer, error) {
+			b := &storedBlob{contentType: contentType}
+			stored[key] = b
+
+			return storedWriter{b: b}, nil
+		}).AnyTimes()
+
+	bucket := blob.NewBucketForTest(mockDriver)
+	assert.False(t, bucket.Capabilities().Append)
+
+	a, err := bucket.NewAppender(ctx, "audit.log", &blob.AppenderOptions{ContentType: "application/x-ndjson", BlockSize: 8})
+	require.NoError(t, err)
+
+	_, err = a.Write([]byte(`{"a":1}` + "\n"))
+	require.NoError(t, err)
+	_, err = a.Write([]byte(`{"b":2}` + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, a.Close())
+
+	require.Contains(t, stored, "audit.log")
+	assert.Equal(t, "{\"a\":1}\n{\"b\":2}\n", stored["audit.log"].data.String())
+	assert.Equal(t, "application/x-ndjson", stored["audit.log"].contentType)
+
+	// a new Appender continues at the end of the object
+	a, err = bucket.NewAppender(ctx, "audit.log", &blob.AppenderOptions{ContentType: "application/x-ndjson"})
+	require.NoError(t, err)
+	_, err = a.Write([]byte(`{"c":3}` + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, a.Close())
+	assert.Equal(t, "{\"a\":1}\n{\"b\":2}\n{\"c\":3}\n", stored["audit.log"].data.String())
+}
+
+func TestAppender_InvalidArguments(t *testing.T) {
+	bucket, _ := newAppenderBucket(t, driver.AppendInfo{})
+	ctx := context.Background()
+
+	_, err := bucket.NewAppender(ctx, "", nil)
+	assert.True(t, kerr.Is(err, kerr.InvalidArgument))
+
+	_, err = bucket.NewAppender(ctx, "key", &blob.AppenderOptions{MaxSize: -1})
+	assert.True(t, kerr.Is(err, kerr.InvalidArgument))
+
+	_, err = bucket.NewAppender(ctx, "key", &blob.AppenderOptions{Metadata: map[string]string{"A": "1", "a": "2"}})
+	assert.True(t, kerr.Is(err, kerr.InvalidArgument))
+
+	assert.True(t, bucket.Capabilities().Append)
+}
+
+func blockStrings(blocks [][]byte) []string {
+	s := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		s = append(s, string(b))
+	}
+
+	return s
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/appendblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// Ensure that AzureStore implements driver.Appender.
+var _ driver.Appender = (*AzureStore)(nil)
+
+// OpenAppend implements driver.Appender using append blobs.
+func (store *AzureStore) OpenAppend(ctx context.Context, key, contentType string, opts *driver.AppendOptions) (*driver.AppendInfo, error) {
+	blob, err := store.Service.NewBlob(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blob.OpenAppend(ctx, contentType, opts)
+}
+
+// AppendBlock implements driver.Appender using append blobs.
+func (store *AzureStore) AppendBlock(ctx context.Context, key string, offset int64, p []byte) error {
+	blob, err := store.Service.NewBlob(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return blob.AppendBlock(ctx, offset, p)
+}
+
+// OpenAppend creates the append blob unless it exists and returns its size
+// and block count.
+func (blockBlob *BlockBlob) OpenAppend(ctx context.Context, contentType string, opts *driver.AppendOptions) (*driver.AppendInfo, error) {
+	md, err := escapeMetadata(opts.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	etagAny := azcore.ETagAny
+	client := blockBlob.appendClient()
+
+	_, err = client.Create(ctx, &appendblob.CreateOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+		Metadata:    md,
+		AccessConditions: &blob.AccessConditions{
+			ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfNoneMatch: &etagAny},
+		},
+	})
+
+	info := &driver.AppendInfo{
+		MaxBlockSize: maxAppendBlockSize,
+		MaxBlocks:    maxAppendBlocks,
+		MaxSize:      maxAppendBlobSize,
+	}
+
+	switch {
+	case err == nil:
+		return info, nil
+	case !bloberror.HasCode(err, bloberror.BlobAlreadyExists, bloberror.ConditionNotMet):
+		return nil, err
+	}
+
+	props, err := client.GetProperties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if props.BlobType == nil || *props.BlobType != blob.BlobTypeAppendBlob {
+		return nil, kerr.Newf(kerr.FailedPrecondition, nil, "blob %q is not an append blob", blockBlob.blobName)
+	}
+
+	if props.ContentLength != nil {
+		info.Size = *props.ContentLength
+	}
+
+	if props.BlobCommittedBlockCount != nil {
+		info.Blocks = int(*props.BlobCommittedBlockCount)
+	}
+
+	return info, nil
+}
+
+// AppendBlock commits p at offset of the append blob. Azure rejects the block
+// with 412 if the blob has a different size.
+func (blockBlob *BlockBlob) AppendBlock(ctx context.Context, offset int64, p []byte) error {
+	_, err := blockBlob.appendClient().AppendBlock(ctx, streaming.NopCloser(bytes.NewReader(p)), &appendblob.AppendBlockOptions{
+		AppendPositionAccessConditions: &appendblob.AppendPositionAccessConditions{AppendPosition: &offset},
+	})
+
+	return err
+}
+
+// appendClient returns a client for the blob as an append blob.
+func (blockBlob *BlockBlob) appendClient() *appendblob.Client {
+	return blockBlob.ContainerClient.NewAppendBlobClient(blockBlob.blobName)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob/azurestore"
+	"github.com/kopexa-grc/common/blob/driver"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestAppend(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	opts := &driver.AppendOptions{}
+	info := &driver.AppendInfo{Size: 3, Blocks: 1, MaxBlockSize: 4 << 20, MaxBlocks: 50_000}
+
+	mockService := NewMockAzService(mockCtrl)
+	mockBlob := NewMockAzBlob(mockCtrl)
+
+	gomock.InOrder(
+		mockService.EXPECT().NewBlob(ctx, mockID).Return(mockBlob, nil).Times(1),
+		mockBlob.EXPECT().OpenAppend(ctx, "text/plain", opts).Return(info, nil).Times(1),
+		mockService.EXPECT().NewBlob(ctx, mockID).Return(mockBlob, nil).Times(1),
+		mockBlob.EXPECT().AppendBlock(ctx, int64(3), []byte("abc")).Return(nil).Times(1),
+	)
+
+	store := azurestore.New(mockService)
+
+	got, err := store.OpenAppend(ctx, mockID, "text/plain", opts)
+	assert.NoError(err)
+	assert.Equal(info, got)
+
+	assert.NoError(store.AppendBlock(ctx, mockID, 3, []byte("abc")))
+}
@@ -107,13 +107,18 @@ func (store *AzureStore) Copy(ctx context.Context, dstKey, srcKey string, opts *
 		return err
 	}
 
+	return waitForCopy(ctx, dstBlobClient, resp)
+}
+
+// waitForCopy polls dst until the copy started with resp has finished.
+func waitForCopy(ctx context.Context, dst AzBlob, resp blob.StartCopyFromURLResponse) error {
 	nErrors := 0
 
 	copyStatus := *resp.CopyStatus
 	for copyStatus == blob.CopyStatusTypePending {
 		time.Sleep(defaultCopyPollMs * time.Millisecond)
 
-		propertiesResp, err := dstBlobClient.GetProperties(ctx, nil)
+		propertiesResp, err := dst.GetProperties(ctx, nil)
 		if err != nil {
 			nErrors++
 			if ctx.Err() != nil || nErrors == 3 {
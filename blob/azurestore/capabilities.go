@@ -0,0 +1,14 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore
+
+import "github.com/kopexa-grc/common/blob/driver"
+
+var _ driver.CapabilityReporter = (*AzureStore)(nil)
+
+// Capabilities implements driver.CapabilityReporter. Azure Blob Storage
+// supports SAS URLs and conditional writes with If-None-Match.
+func (store *AzureStore) Capabilities() driver.Capability {
+	return driver.CapSignedURL | driver.CapIfNotExist
+}
@@ -0,0 +1,31 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore_test
+
+import (
+	"testing"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/azurestore"
+	"github.com/kopexa-grc/common/blob/driver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilities(t *testing.T) {
+	store := &azurestore.AzureStore{}
+
+	caps := store.Capabilities()
+	assert.True(t, caps.Has(driver.CapSignedURL|driver.CapIfNotExist))
+
+	assert.Equal(t, blob.Capabilities{
+		SignedURL:  true,
+		IfNotExist: true,
+		Snapshots:  true,
+		Tags:       true,
+		List:       true,
+		UploadForm: true,
+		Append:     true,
+		Encryption: true,
+	}, blob.NewBucketForTest(store).Capabilities())
+}
@@ -17,3 +17,14 @@ const (
 	defaultUploadBlockSize = 8 * 1024 * 1024 // configure the upload buffer size
 	defaultUploadBuffers   = 5               // configure the number of rotating buffers that are used when uploading (for degree of parallelism)
 )
+
+// maxListPageSize is the largest page Azure returns for a flat blob listing.
+const maxListPageSize = 5000
+
+// Limits of append blobs. Service versions before 2022-11-02 accept blocks
+// of at most 4 MiB, so larger blocks are not used.
+const (
+	maxAppendBlockSize = 4 * 1024 * 1024
+	maxAppendBlocks    = 50_000
+	maxAppendBlobSize  = maxAppendBlockSize * maxAppendBlocks
+)
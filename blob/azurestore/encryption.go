@@ -0,0 +1,76 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore
+
+import (
+	"context"
+
+	"github.com/kopexa-grc/common/blob/driver"
+)
+
+// Encryption settings of Azure Storage. Storage service encryption cannot be
+// disabled; objects are encrypted with AES-256 using the account key unless
+// an encryption scope is selected.
+const (
+	encryptionAlgorithm  = "AES256"
+	accountEncryptionKey = "$account-encryption-key"
+
+	// KeySourceAccount is the key source of containers encrypted with the
+	// key of the storage account.
+	KeySourceAccount = "account"
+	// KeySourceEncryptionScope is the key source of containers encrypted
+	// with a named encryption scope.
+	KeySourceEncryptionScope = "encryption-scope"
+)
+
+// Ensure that AzureStore implements driver.EncryptionReporter.
+var _ driver.EncryptionReporter = (*AzureStore)(nil)
+
+// EncryptionInfo implements driver.EncryptionReporter.
+func (store *AzureStore) EncryptionInfo(ctx context.Context) (*driver.EncryptionInfo, error) {
+	return store.Service.EncryptionInfo(ctx)
+}
+
+// EncryptionInfo reports the default encryption scope of the container.
+// Whether the account key or the scope is managed by Microsoft or by the
+// customer is configured on the account and not visible through the blob
+// API.
+func (service *azService) EncryptionInfo(ctx context.Context) (*driver.EncryptionInfo, error) {
+	if err := service.ensureContainer(ctx); err != nil {
+		return nil, err
+	}
+
+	props, err := service.ContainerClient.GetProperties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var scope string
+	if props.DefaultEncryptionScope != nil {
+		scope = *props.DefaultEncryptionScope
+	}
+
+	return NewEncryptionInfo(scope, props.DenyEncryptionScopeOverride != nil && *props.DenyEncryptionScopeOverride), nil
+}
+
+// NewEncryptionInfo returns the encryption info of a container with the
+// given default encryption scope. An empty scope stands for the account key.
+func NewEncryptionInfo(scope string, scopeEnforced bool) *driver.EncryptionInfo {
+	if scope == "" {
+		scope = accountEncryptionKey
+	}
+
+	keySource := KeySourceEncryptionScope
+	if scope == accountEncryptionKey {
+		keySource = KeySourceAccount
+	}
+
+	return &driver.EncryptionInfo{
+		Encrypted:     true,
+		Algorithm:     encryptionAlgorithm,
+		Scope:         scope,
+		ScopeEnforced: scopeEnforced,
+		KeySource:     keySource,
+	}
+}
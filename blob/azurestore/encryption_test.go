@@ -0,0 +1,46 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob/azurestore"
+	"github.com/kopexa-grc/common/blob/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestEncryptionInfo(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	want := azurestore.NewEncryptionInfo("grc-cmk", true)
+
+	mockService := NewMockAzService(mockCtrl)
+	mockService.EXPECT().EncryptionInfo(ctx).Return(want, nil).Times(1)
+
+	got, err := azurestore.New(mockService).EncryptionInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &driver.EncryptionInfo{
+		Encrypted:     true,
+		Algorithm:     "AES256",
+		Scope:         "grc-cmk",
+		ScopeEnforced: true,
+		KeySource:     azurestore.KeySourceEncryptionScope,
+	}, got)
+}
+
+func TestNewEncryptionInfo_AccountKey(t *testing.T) {
+	for _, scope := range []string{"", "$account-encryption-key"} {
+		info := azurestore.NewEncryptionInfo(scope, false)
+
+		assert.True(t, info.Encrypted)
+		assert.Equal(t, "$account-encryption-key", info.Scope)
+		assert.Equal(t, azurestore.KeySourceAccount, info.KeySource)
+		assert.False(t, info.ScopeEnforced)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore
+
+import (
+	"errors"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+var _ driver.ErrorCoder = (*AzureStore)(nil)
+
+// ErrorCode implements driver.ErrorCoder. Azure responses are classified by
+// their HTTP status, e.g. 404 for BlobNotFound and 412 for a failed
+// If-None-Match condition.
+func (store *AzureStore) ErrorCode(err error) kerr.ErrorCode {
+	var re *azcore.ResponseError
+	if errors.As(err, &re) {
+		return driver.CodeFromHTTPStatus(re.StatusCode)
+	}
+
+	return ""
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/azurestore"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestErrorCode(t *testing.T) {
+	store := &azurestore.AzureStore{}
+
+	tests := []struct {
+		status int
+		code   kerr.ErrorCode
+	}{
+		{http.StatusNotFound, kerr.NotFound},
+		{http.StatusConflict, kerr.FailedPrecondition},
+		{http.StatusPreconditionFailed, kerr.FailedPrecondition},
+		{http.StatusForbidden, kerr.Forbidden},
+		{http.StatusTooManyRequests, kerr.TooManyRequests},
+		{http.StatusInternalServerError, kerr.UnexpectedFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := fmt.Errorf("delete: %w", &azcore.ResponseError{StatusCode: tt.status})
+			assert.Equal(t, tt.code, store.ErrorCode(err))
+		})
+	}
+
+	assert.Empty(t, store.ErrorCode(errors.New("boom")))
+}
+
+func TestBucket_AzureErrors(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	ctx := t.Context()
+
+	service := NewMockAzService(mockCtrl)
+	bucket := blob.NewBucketForTest(azurestore.New(service))
+
+	blockBlob := NewMockAzBlob(mockCtrl)
+	service.EXPECT().NewBlob(ctx, "missing").Return(blockBlob, nil)
+	blockBlob.EXPECT().Delete(ctx).Return(&azcore.ResponseError{StatusCode: http.StatusNotFound, ErrorCode: "BlobNotFound"})
+
+	err := bucket.Delete(ctx, "missing")
+	require.Error(t, err)
+	assert.True(t, kerr.IsNotFound(err))
+	assert.Equal(t, http.StatusNotFound, kerr.Status(err))
+
+	var re *azcore.ResponseError
+	assert.ErrorAs(t, err, &re)
+}
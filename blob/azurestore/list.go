@@ -0,0 +1,84 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/kopexa-grc/common/blob/driver"
+	"github.com/kopexa-grc/common/blob/internal/escape"
+)
+
+// Ensure that AzureStore implements driver.Lister.
+var _ driver.Lister = (*AzureStore)(nil)
+
+// ListPaged implements driver.Lister.
+func (store *AzureStore) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	return store.Service.ListBlobs(ctx, opts)
+}
+
+// ListBlobs lists a single page of blobs in the container.
+// If opts.Tags is set, only blobs carrying all tags are listed.
+func (service *azService) ListBlobs(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	if err := service.ensureContainer(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(opts.Tags) > 0 {
+		return service.filterBlobs(ctx, opts)
+	}
+
+	listOpts := &container.ListBlobsFlatOptions{}
+
+	if opts.Prefix != "" {
+		listOpts.Prefix = to.Ptr(escapeKey(opts.Prefix, true))
+	}
+
+	if opts.PageSize > 0 {
+		listOpts.MaxResults = to.Ptr(int32(min(opts.PageSize, maxListPageSize))) //nolint:gosec // bounded by maxListPageSize
+	}
+
+	if len(opts.PageToken) > 0 {
+		listOpts.Marker = to.Ptr(string(opts.PageToken))
+	}
+
+	pager := service.ContainerClient.NewListBlobsFlatPager(listOpts)
+
+	resp, err := pager.NextPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &driver.ListPage{}
+
+	if resp.Segment != nil {
+		for _, item := range resp.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+
+			obj := &driver.ListObject{Key: escape.HexUnescape(*item.Name)}
+
+			if item.Properties != nil {
+				if item.Properties.ContentLength != nil {
+					obj.Size = *item.Properties.ContentLength
+				}
+
+				if item.Properties.LastModified != nil {
+					obj.ModTime = *item.Properties.LastModified
+				}
+			}
+
+			page.Objects = append(page.Objects, obj)
+		}
+	}
+
+	if resp.NextMarker != nil && *resp.NextMarker != "" {
+		page.NextPageToken = []byte(*resp.NextMarker)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+)
+
+// ServicePool shares Azure clients between services. Services for the same
+// account reuse one client and its HTTP connection pool, and services for the
+// same container are created once, so that the container is created at most
+// once per process. It is safe for concurrent use.
+type ServicePool struct {
+	mu       sync.Mutex
+	clients  map[string]*pooledClient
+	services map[string]*azService
+}
+
+type pooledClient struct {
+	client *service.Client
+	cred   *azblob.SharedKeyCredential
+}
+
+// NewServicePool returns an empty ServicePool.
+func NewServicePool() *ServicePool {
+	return &ServicePool{
+		clients:  make(map[string]*pooledClient),
+		services: make(map[string]*azService),
+	}
+}
+
+// Service returns the service for the container of config, creating it and
+// the client of its account on first use. Like NewAzureService, it does not
+// contact Azure; the container is created lazily.
+func (p *ServicePool) Service(config *AzConfig) (AzService, error) {
+	clientKey := poolKey(config.Endpoint, config.AccountName)
+	serviceKey := poolKey(clientKey, config.ContainerName, config.ContainerAccessType, config.BlobAccessTier,
+		strconv.FormatBool(config.DisableContainerCreation))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if svc, ok := p.services[serviceKey]; ok {
+		return svc, nil
+	}
+
+	pc, ok := p.clients[clientKey]
+	if !ok {
+		client, cred, err := newServiceClient(config)
+		if err != nil {
+			return nil, err
+		}
+
+		pc = &pooledClient{client: client, cred: cred}
+		p.clients[clientKey] = pc
+	}
+
+	svc := newAzService(pc.client, pc.cred, config)
+	p.services[serviceKey] = svc
+
+	return svc, nil
+}
+
+// poolKey joins parts with a separator that cannot occur in URLs or names.
+func poolKey(parts ...string) string {
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob/azurestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// containerServer fakes the Azure endpoint and counts container creations.
+func containerServer(t *testing.T, creates *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Query().Get("restype") == "container" {
+			creates.Add(1)
+			w.WriteHeader(http.StatusCreated)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func testConfig(endpoint, containerName string) *azurestore.AzConfig {
+	return &azurestore.AzConfig{
+		AccountName:   "account",
+		AccountKey:    base64.StdEncoding.EncodeToString([]byte("key")),
+		Endpoint:      endpoint,
+		ContainerName: containerName,
+	}
+}
+
+func TestAzureService_CreatesContainerOnce(t *testing.T) {
+	var creates atomic.Int32
+
+	srv := containerServer(t, &creates)
+
+	service, err := azurestore.NewAzureService(testConfig(srv.URL, "space-1"))
+	require.NoError(t, err)
+	assert.Zero(t, creates.Load(), "container must not be created eagerly")
+
+	for range 2 {
+		_, err = service.NewBlob(context.Background(), "key")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), creates.Load())
+}
+
+func TestAzureService_DisableContainerCreation(t *testing.T) {
+	var creates atomic.Int32
+
+	srv := containerServer(t, &creates)
+
+	config := testConfig(srv.URL, "space-1")
+	config.DisableContainerCreation = true
+
+	service, err := azurestore.NewAzureService(config)
+	require.NoError(t, err)
+
+	_, err = service.NewBlob(context.Background(), "key")
+	require.NoError(t, err)
+	assert.Zero(t, creates.Load())
+}
+
+func TestServicePool(t *testing.T) {
+	var creates atomic.Int32
+
+	srv := containerServer(t, &creates)
+	pool := azurestore.NewServicePool()
+
+	first, err := pool.Service(testConfig(srv.URL, "space-1"))
+	require.NoError(t, err)
+
+	second, err := pool.Service(testConfig(srv.URL, "space-1"))
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	other, err := pool.Service(testConfig(srv.URL, "space-2"))
+	require.NoError(t, err)
+	assert.NotSame(t, first, other)
+
+	for _, service := range []azurestore.AzService{first, second, other} {
+		_, err = service.NewBlob(context.Background(), "key")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), creates.Load())
+}
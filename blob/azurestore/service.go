@@ -5,11 +5,12 @@ package azurestore
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -20,9 +21,11 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
 	"github.com/kopexa-grc/common/blob/driver"
 	"github.com/kopexa-grc/common/blob/internal/escape"
 	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/kopexa-grc/common/ptr"
 	"github.com/rs/zerolog/log"
 )
 
@@ -34,19 +37,30 @@ type AzBlob interface {
 	URL() string
 	NewRangeReader(ctx context.Context, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error)
 	NewTypedWriter(ctx context.Context, contentType string, opts *driver.WriterOptions) (driver.Writer, error)
+	CreateSnapshot(ctx context.Context) (string, error)
+	ListSnapshots(ctx context.Context) ([]*driver.SnapshotInfo, error)
+	NewSnapshotReader(ctx context.Context, snapshotID string, opts *driver.ReaderOptions) (driver.Reader, error)
+	SnapshotURL(snapshotID string) (string, error)
+	SetTags(ctx context.Context, tags map[string]string) error
+	GetTags(ctx context.Context) (map[string]string, error)
+	OpenAppend(ctx context.Context, contentType string, opts *driver.AppendOptions) (*driver.AppendInfo, error)
+	AppendBlock(ctx context.Context, offset int64, p []byte) error
 }
 
 type BlockBlob struct {
-	BlobClient     *blockblob.Client
-	Indexes        []int
-	BlobAccessTier *blob.AccessTier
-	credential     *azblob.SharedKeyCredential // unexported for security
-	containerName  string                      // unexported for security
-	blobName       string                      // unexported for security
+	BlobClient      *blockblob.Client
+	ContainerClient *container.Client
+	Indexes         []int
+	BlobAccessTier  *blob.AccessTier
+	credential      *azblob.SharedKeyCredential // unexported for security
+	containerName   string                      // unexported for security
+	blobName        string                      // unexported for security
 }
 
 type AzService interface {
 	NewBlob(ctx context.Context, name string) (AzBlob, error)
+	ListBlobs(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error)
+	EncryptionInfo(ctx context.Context) (*driver.EncryptionInfo, error)
 }
 
 type azService struct {
@@ -54,6 +68,12 @@ type azService struct {
 	ContainerName   string
 	BlobAccessTier  *blob.AccessTier
 	credential      *azblob.SharedKeyCredential // unexported for security
+
+	// createOpts are the options used to create the container on first use;
+	// nil disables container creation.
+	createOpts *container.CreateOptions
+	createMu   sync.Mutex
+	created    atomic.Bool
 }
 
 type AzConfig struct {
@@ -63,6 +83,10 @@ type AzConfig struct {
 	ContainerName       string
 	ContainerAccessType string
 	Endpoint            string
+	// DisableContainerCreation skips creating the container on first use.
+	// Use it in production, where containers are provisioned up front and
+	// the credentials may lack the permission to create them.
+	DisableContainerCreation bool
 }
 
 const (
@@ -72,47 +96,60 @@ const (
 	defaultCopyPollMs    = 500  // ms
 )
 
+// NewAzureService returns a service for the container of config with its own
+// client. The container is created on first use unless
+// DisableContainerCreation is set. Use a ServicePool to share clients between
+// services.
 func NewAzureService(config *AzConfig) (AzService, error) {
-	cred, err := azblob.NewSharedKeyCredential(config.AccountName, config.AccountKey)
+	client, cred, err := newServiceClient(config)
 	if err != nil {
 		return nil, err
 	}
 
-	serviceURL := fmt.Sprintf("%s/%s", config.Endpoint, config.ContainerName)
+	return newAzService(client, cred, config), nil
+}
+
+// newServiceClient creates an account-level client for config.
+func newServiceClient(config *AzConfig) (*service.Client, *azblob.SharedKeyCredential, error) {
+	cred, err := azblob.NewSharedKeyCredential(config.AccountName, config.AccountKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	retryOpts := policy.RetryOptions{
 		MaxRetries:    maxRetries,
 		RetryDelay:    retryDelay,    // Retry after 100ms initially
 		MaxRetryDelay: maxRetryDelay, // Max retry delay 5 seconds
 	}
 
-	containerClient, err := container.NewClientWithSharedKeyCredential(serviceURL, cred, &container.ClientOptions{
+	client, err := service.NewClientWithSharedKeyCredential(config.Endpoint, cred, &service.ClientOptions{
 		ClientOptions: azcore.ClientOptions{
 			Retry: retryOpts,
 		},
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	containerCreateOptions := &container.CreateOptions{}
-
-	switch config.ContainerAccessType {
-	case "container":
-		containerCreateOptions.Access = to.Ptr(container.PublicAccessTypeContainer)
-	case "blob":
-		containerCreateOptions.Access = to.Ptr(container.PublicAccessTypeBlob)
-	default:
-		// Leaving Access nil will default to private access
-	}
+	return client, cred, nil
+}
 
-	_, err = containerClient.Create(context.Background(), containerCreateOptions)
-	//nolint:gocritic
-	if err != nil && !strings.Contains(err.Error(), "ContainerAlreadyExists") {
-		return nil, err
-	} else if err == nil {
-		log.Info().Str("container", config.ContainerName).Msg("Azure Blob container created")
-	} else {
-		log.Debug().Str("container", config.ContainerName).Msg("Azure Blob container already exists")
+// newAzService creates a service for the container of config, sharing the
+// pipeline of client.
+func newAzService(client *service.Client, cred *azblob.SharedKeyCredential, config *AzConfig) *azService {
+	var createOpts *container.CreateOptions
+
+	if !config.DisableContainerCreation {
+		createOpts = &container.CreateOptions{}
+
+		switch config.ContainerAccessType {
+		case "container":
+			createOpts.Access = to.Ptr(container.PublicAccessTypeContainer)
+		case "blob":
+			createOpts.Access = to.Ptr(container.PublicAccessTypeBlob)
+		default:
+			// Leaving Access nil will default to private access
+		}
 	}
 
 	var blobAccessTier *blob.AccessTier
@@ -127,25 +164,60 @@ func NewAzureService(config *AzConfig) (AzService, error) {
 	}
 
 	return &azService{
-		ContainerClient: containerClient,
+		ContainerClient: client.NewContainerClient(config.ContainerName),
 		ContainerName:   config.ContainerName,
 		BlobAccessTier:  blobAccessTier,
 		credential:      cred,
-	}, nil
+		createOpts:      createOpts,
+	}
+}
+
+// ensureContainer creates the container once. Failed attempts are retried on
+// the next call.
+func (service *azService) ensureContainer(ctx context.Context) error {
+	if service.createOpts == nil || service.created.Load() {
+		return nil
+	}
+
+	service.createMu.Lock()
+	defer service.createMu.Unlock()
+
+	if service.created.Load() {
+		return nil
+	}
+
+	_, err := service.ContainerClient.Create(ctx, service.createOpts)
+	//nolint:gocritic
+	if err != nil && !strings.Contains(err.Error(), "ContainerAlreadyExists") {
+		return err
+	} else if err == nil {
+		log.Info().Str("container", service.ContainerName).Msg("Azure Blob container created")
+	} else {
+		log.Debug().Str("container", service.ContainerName).Msg("Azure Blob container already exists")
+	}
+
+	service.created.Store(true)
+
+	return nil
 }
 
 // Determine if we return a InfoBlob or BlockBlob, based on the name
-func (service *azService) NewBlob(_ context.Context, name string) (AzBlob, error) {
+func (service *azService) NewBlob(ctx context.Context, name string) (AzBlob, error) {
+	if err := service.ensureContainer(ctx); err != nil {
+		return nil, err
+	}
+
 	escapedName := escapeKey(name, false)
 	blobClient := service.ContainerClient.NewBlockBlobClient(escapedName)
 
 	return &BlockBlob{
-		BlobClient:     blobClient,
-		Indexes:        []int{},
-		BlobAccessTier: service.BlobAccessTier,
-		credential:     service.credential,
-		containerName:  service.ContainerName,
-		blobName:       escapedName,
+		BlobClient:      blobClient,
+		ContainerClient: service.ContainerClient,
+		Indexes:         []int{},
+		BlobAccessTier:  service.BlobAccessTier,
+		credential:      service.credential,
+		containerName:   service.ContainerName,
+		blobName:        escapedName,
 	}, nil
 }
 
@@ -264,7 +336,7 @@ func (r *reader) Attributes() *driver.ReaderAttributes {
 
 func (r *reader) As(i any) bool {
 	p, ok := i.(*azblob.DownloadStreamResponse)
-	if !ok {
+	if !ok || r.raw == nil {
 		return false
 	}
 
@@ -275,8 +347,12 @@ func (r *reader) As(i any) bool {
 
 // NewRangeReader implements driver.NewRangeReader.
 func (blockBlob *BlockBlob) NewRangeReader(ctx context.Context, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
-	blobClient := blockBlob.BlobClient
+	return newRangeReader(ctx, blockBlob.BlobClient, offset, length, opts)
+}
 
+// newRangeReader reads a range of the blob addressed by blobClient, which may
+// refer to the base blob or one of its snapshots.
+func newRangeReader(ctx context.Context, blobClient *blockblob.Client, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
 	downloadOpts := azblob.DownloadStreamOptions{}
 	if offset != 0 {
 		downloadOpts.Range.Offset = offset
@@ -300,6 +376,28 @@ func (blockBlob *BlockBlob) NewRangeReader(ctx context.Context, offset, length i
 		}
 	}
 
+	// A zero-length read only needs the attributes. DownloadStream cannot
+	// express an empty range and would transfer the whole blob.
+	if length == 0 {
+		props, err := blobClient.GetProperties(ctx, &blob.GetPropertiesOptions{
+			AccessConditions: downloadOpts.AccessConditions,
+			CPKInfo:          downloadOpts.CPKInfo,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &reader{
+			body: http.NoBody,
+			attrs: driver.ReaderAttributes{
+				ContentType: ptr.Deref(props.ContentType, ""),
+				Size:        ptr.Deref(props.ContentLength, 0),
+				ModTime:     ptr.Deref(props.LastModified, time.Time{}),
+				ContentMD5:  props.ContentMD5,
+			},
+		}, nil
+	}
+
 	blobDownloadResponse, err := blobClient.DownloadStream(ctx, &downloadOpts)
 	if err != nil {
 		return nil, err
@@ -309,17 +407,11 @@ func (blockBlob *BlockBlob) NewRangeReader(ctx context.Context, offset, length i
 		ContentType: *blobDownloadResponse.ContentType,
 		Size:        getSize(blobDownloadResponse.ContentLength, *blobDownloadResponse.ContentRange),
 		ModTime:     *blobDownloadResponse.LastModified,
-	}
-
-	var body io.ReadCloser
-	if length == 0 {
-		body = http.NoBody
-	} else {
-		body = blobDownloadResponse.Body
+		ContentMD5:  getContentMD5(blobDownloadResponse, offset, length),
 	}
 
 	return &reader{
-		body:  body,
+		body:  blobDownloadResponse.Body,
 		attrs: attrs,
 		raw:   &blobDownloadResponse,
 	}, nil
@@ -336,31 +428,9 @@ func (blockBlob *BlockBlob) NewTypedWriter(ctx context.Context, contentType stri
 		opts.MaxConcurrency = defaultUploadBuffers
 	}
 
-	md := make(map[string]*string, len(opts.Metadata))
-
-	for k, v := range opts.Metadata {
-		// See the package comments for more details on escaping of metadata
-		// keys & values.
-		e := escape.HexEscape(k, func(runes []rune, i int) bool {
-			c := runes[i]
-
-			switch {
-			case i == 0 && c >= '0' && c <= '9':
-				return true
-			case escape.IsASCIIAlphanumeric(c):
-				return false
-			case c == '_':
-				return false
-			}
-
-			return true
-		})
-		if _, ok := md[e]; ok {
-			return nil, kerr.Newf(kerr.InvalidArgument, nil, "duplicate keys after escaping: %q => %q", k, e)
-		}
-
-		escaped := escape.URLEscape(v)
-		md[e] = &escaped
+	md, err := escapeMetadata(opts.Metadata)
+	if err != nil {
+		return nil, err
 	}
 
 	uploadOpts := &azblob.UploadStreamOptions{
@@ -410,6 +480,38 @@ func (blockBlob *BlockBlob) NewTypedWriter(ctx context.Context, contentType stri
 	}, nil
 }
 
+// escapeMetadata escapes the keys and values of metadata for Azure.
+func escapeMetadata(metadata map[string]string) (map[string]*string, error) {
+	md := make(map[string]*string, len(metadata))
+
+	for k, v := range metadata {
+		// See the package comments for more details on escaping of metadata
+		// keys & values.
+		e := escape.HexEscape(k, func(runes []rune, i int) bool {
+			c := runes[i]
+
+			switch {
+			case i == 0 && c >= '0' && c <= '9':
+				return true
+			case escape.IsASCIIAlphanumeric(c):
+				return false
+			case c == '_':
+				return false
+			}
+
+			return true
+		})
+		if _, ok := md[e]; ok {
+			return nil, kerr.Newf(kerr.InvalidArgument, nil, "duplicate keys after escaping: %q => %q", k, e)
+		}
+
+		escaped := escape.URLEscape(v)
+		md[e] = &escaped
+	}
+
+	return md, nil
+}
+
 func getSize(contentLength *int64, contentRange string) int64 {
 	var size int64
 	// Default size to ContentLength, but that's incorrect for partial-length reads,
@@ -435,6 +537,21 @@ func getSize(contentLength *int64, contentRange string) int64 {
 	return size
 }
 
+// getContentMD5 returns the MD5 digest of the whole blob. For range reads Azure
+// reports the digest of the full blob in BlobContentMD5; for full reads it is
+// only present in ContentMD5.
+func getContentMD5(resp azblob.DownloadStreamResponse, offset, length int64) []byte {
+	if len(resp.BlobContentMD5) > 0 {
+		return resp.BlobContentMD5
+	}
+
+	if offset == 0 && length < 0 {
+		return resp.ContentMD5
+	}
+
+	return nil
+}
+
 // escapeKey does all required escaping for UTF-8 strings to work
 // with Azure. isPrefix indicated whether this is a prefix/delimeter or the full key.
 func escapeKey(key string, isPrefix bool) string {
@@ -0,0 +1,141 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/kopexa-grc/common/blob/driver"
+)
+
+// Ensure that AzureStore implements driver.Snapshotter.
+var _ driver.Snapshotter = (*AzureStore)(nil)
+
+// Snapshot implements driver.Snapshotter.
+func (store *AzureStore) Snapshot(ctx context.Context, key string) (string, error) {
+	blob, err := store.Service.NewBlob(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	return blob.CreateSnapshot(ctx)
+}
+
+// ListSnapshots implements driver.Snapshotter.
+func (store *AzureStore) ListSnapshots(ctx context.Context, key string) ([]*driver.SnapshotInfo, error) {
+	blob, err := store.Service.NewBlob(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blob.ListSnapshots(ctx)
+}
+
+// NewSnapshotReader implements driver.Snapshotter.
+func (store *AzureStore) NewSnapshotReader(ctx context.Context, key, snapshotID string, opts *driver.ReaderOptions) (driver.Reader, error) {
+	blob, err := store.Service.NewBlob(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blob.NewSnapshotReader(ctx, snapshotID, opts)
+}
+
+// PromoteSnapshot implements driver.Snapshotter by copying the snapshot over
+// the base blob.
+func (store *AzureStore) PromoteSnapshot(ctx context.Context, key, snapshotID string) error {
+	blob, err := store.Service.NewBlob(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	srcURL, err := blob.SnapshotURL(snapshotID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := blob.StartCopyFromURL(ctx, srcURL, &driver.CopyOptions{})
+	if err != nil {
+		return err
+	}
+
+	return waitForCopy(ctx, blob, resp)
+}
+
+// CreateSnapshot creates a snapshot of the blockBlob and returns its ID.
+func (blockBlob *BlockBlob) CreateSnapshot(ctx context.Context) (string, error) {
+	resp, err := blockBlob.BlobClient.CreateSnapshot(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.Snapshot == nil {
+		return "", nil
+	}
+
+	return *resp.Snapshot, nil
+}
+
+// ListSnapshots lists the snapshots of the blockBlob, oldest first.
+func (blockBlob *BlockBlob) ListSnapshots(ctx context.Context) ([]*driver.SnapshotInfo, error) {
+	pager := blockBlob.ContainerClient.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix:  &blockBlob.blobName,
+		Include: container.ListBlobsInclude{Snapshots: true},
+	})
+
+	var snapshots []*driver.SnapshotInfo
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil || *item.Name != blockBlob.blobName {
+				continue
+			}
+
+			if item.Snapshot == nil || *item.Snapshot == "" {
+				continue
+			}
+
+			info := &driver.SnapshotInfo{ID: *item.Snapshot}
+
+			if t, err := time.Parse(time.RFC3339Nano, *item.Snapshot); err == nil {
+				info.CreatedAt = t
+			}
+
+			if item.Properties != nil && item.Properties.ContentLength != nil {
+				info.Size = *item.Properties.ContentLength
+			}
+
+			snapshots = append(snapshots, info)
+		}
+	}
+
+	return snapshots, nil
+}
+
+// NewSnapshotReader returns a reader for the full content of the given snapshot.
+func (blockBlob *BlockBlob) NewSnapshotReader(ctx context.Context, snapshotID string, opts *driver.ReaderOptions) (driver.Reader, error) {
+	snapshotClient, err := blockBlob.BlobClient.WithSnapshot(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRangeReader(ctx, snapshotClient, 0, -1, opts)
+}
+
+// SnapshotURL returns the URL addressing the given snapshot of the blockBlob.
+func (blockBlob *BlockBlob) SnapshotURL(snapshotID string) (string, error) {
+	snapshotClient, err := blockBlob.BlobClient.WithSnapshot(snapshotID)
+	if err != nil {
+		return "", err
+	}
+
+	return snapshotClient.URL(), nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/kopexa-grc/common/blob/azurestore"
+	"github.com/kopexa-grc/common/blob/driver"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+const mockSnapshotID = "2025-01-02T03:04:05.0000000Z"
+
+func TestSnapshot(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	assert := assert.New(t)
+
+	ctx := context.Background()
+
+	mockService := NewMockAzService(mockCtrl)
+	mockBlob := NewMockAzBlob(mockCtrl)
+
+	gomock.InOrder(
+		mockService.EXPECT().NewBlob(ctx, mockID).Return(mockBlob, nil).Times(1),
+		mockBlob.EXPECT().CreateSnapshot(ctx).Return(mockSnapshotID, nil).Times(1),
+	)
+
+	store := azurestore.New(mockService)
+
+	id, err := store.Snapshot(ctx, mockID)
+	assert.NoError(err)
+	assert.Equal(mockSnapshotID, id)
+}
+
+func TestListSnapshots(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	expected := []*driver.SnapshotInfo{{ID: mockSnapshotID, Size: 42}}
+
+	mockService := NewMockAzService(mockCtrl)
+	mockBlob := NewMockAzBlob(mockCtrl)
+
+	gomock.InOrder(
+		mockService.EXPECT().NewBlob(ctx, mockID).Return(mockBlob, nil).Times(1),
+		mockBlob.EXPECT().ListSnapshots(ctx).Return(expected, nil).Times(1),
+	)
+
+	store := azurestore.New(mockService)
+
+	snaps, err := store.ListSnapshots(ctx, mockID)
+	assert.NoError(err)
+	assert.Equal(expected, snaps)
+}
+
+func TestPromoteSnapshot(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	snapshotURL := "https://storage.example.com/kopexa/123?snapshot=" + mockSnapshotID
+
+	mockService := NewMockAzService(mockCtrl)
+	mockBlob := NewMockAzBlob(mockCtrl)
+
+	gomock.InOrder(
+		mockService.EXPECT().NewBlob(ctx, mockID).Return(mockBlob, nil).Times(1),
+		mockBlob.EXPECT().SnapshotURL(mockSnapshotID).Return(snapshotURL, nil).Times(1),
+		mockBlob.EXPECT().
+			StartCopyFromURL(ctx, snapshotURL, gomock.Any()).
+			Return(blob.StartCopyFromURLResponse{CopyStatus: to.Ptr(blob.CopyStatusTypeSuccess)}, nil).
+			Times(1),
+	)
+
+	store := azurestore.New(mockService)
+
+	assert.NoError(store.PromoteSnapshot(ctx, mockID, mockSnapshotID))
+}
+
+func TestPromoteSnapshotCopyFailed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ctx := context.Background()
+
+	mockService := NewMockAzService(mockCtrl)
+	mockBlob := NewMockAzBlob(mockCtrl)
+
+	mockService.EXPECT().NewBlob(ctx, mockID).Return(mockBlob, nil)
+	mockBlob.EXPECT().SnapshotURL(mockSnapshotID).Return("https://storage.example.com/snap", nil)
+	mockBlob.EXPECT().
+		StartCopyFromURL(ctx, gomock.Any(), gomock.Any()).
+		Return(blob.StartCopyFromURLResponse{CopyStatus: to.Ptr(blob.CopyStatusTypeFailed)}, nil)
+
+	store := azurestore.New(mockService)
+
+	err := store.PromoteSnapshot(ctx, mockID, mockSnapshotID)
+	assert.ErrorIs(t, err, driver.ErrCopyFailed)
+}
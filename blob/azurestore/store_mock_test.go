@@ -43,6 +43,35 @@ func (m *MockAzBlob) EXPECT() *MockAzBlobMockRecorder {
 	return m.recorder
 }
 
+// AppendBlock mocks base method.
+func (m *MockAzBlob) AppendBlock(ctx context.Context, offset int64, p []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendBlock", ctx, offset, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendBlock indicates an expected call of AppendBlock.
+func (mr *MockAzBlobMockRecorder) AppendBlock(ctx, offset, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendBlock", reflect.TypeOf((*MockAzBlob)(nil).AppendBlock), ctx, offset, p)
+}
+
+// CreateSnapshot mocks base method.
+func (m *MockAzBlob) CreateSnapshot(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshot", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnapshot indicates an expected call of CreateSnapshot.
+func (mr *MockAzBlobMockRecorder) CreateSnapshot(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockAzBlob)(nil).CreateSnapshot), ctx)
+}
+
 // Delete mocks base method.
 func (m *MockAzBlob) Delete(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -72,6 +101,36 @@ func (mr *MockAzBlobMockRecorder) GetProperties(ctx, o any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProperties", reflect.TypeOf((*MockAzBlob)(nil).GetProperties), ctx, o)
 }
 
+// GetTags mocks base method.
+func (m *MockAzBlob) GetTags(ctx context.Context) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockAzBlobMockRecorder) GetTags(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockAzBlob)(nil).GetTags), ctx)
+}
+
+// ListSnapshots mocks base method.
+func (m *MockAzBlob) ListSnapshots(ctx context.Context) ([]*driver.SnapshotInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSnapshots", ctx)
+	ret0, _ := ret[0].([]*driver.SnapshotInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSnapshots indicates an expected call of ListSnapshots.
+func (mr *MockAzBlobMockRecorder) ListSnapshots(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSnapshots", reflect.TypeOf((*MockAzBlob)(nil).ListSnapshots), ctx)
+}
+
 // NewRangeReader mocks base method.
 func (m *MockAzBlob) NewRangeReader(ctx context.Context, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
 	m.ctrl.T.Helper()
@@ -87,6 +146,21 @@ func (mr *MockAzBlobMockRecorder) NewRangeReader(ctx, offset, length, opts any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewRangeReader", reflect.TypeOf((*MockAzBlob)(nil).NewRangeReader), ctx, offset, length, opts)
 }
 
+// NewSnapshotReader mocks base method.
+func (m *MockAzBlob) NewSnapshotReader(ctx context.Context, snapshotID string, opts *driver.ReaderOptions) (driver.Reader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewSnapshotReader", ctx, snapshotID, opts)
+	ret0, _ := ret[0].(driver.Reader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewSnapshotReader indicates an expected call of NewSnapshotReader.
+func (mr *MockAzBlobMockRecorder) NewSnapshotReader(ctx, snapshotID, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSnapshotReader", reflect.TypeOf((*MockAzBlob)(nil).NewSnapshotReader), ctx, snapshotID, opts)
+}
+
 // NewTypedWriter mocks base method.
 func (m *MockAzBlob) NewTypedWriter(ctx context.Context, contentType string, opts *driver.WriterOptions) (driver.Writer, error) {
 	m.ctrl.T.Helper()
@@ -102,6 +176,35 @@ func (mr *MockAzBlobMockRecorder) NewTypedWriter(ctx, contentType, opts any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewTypedWriter", reflect.TypeOf((*MockAzBlob)(nil).NewTypedWriter), ctx, contentType, opts)
 }
 
+// OpenAppend mocks base method.
+func (m *MockAzBlob) OpenAppend(ctx context.Context, contentType string, opts *driver.AppendOptions) (*driver.AppendInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenAppend", ctx, contentType, opts)
+	ret0, _ := ret[0].(*driver.AppendInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenAppend indicates an expected call of OpenAppend.
+func (mr *MockAzBlobMockRecorder) OpenAppend(ctx, contentType, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenAppend", reflect.TypeOf((*MockAzBlob)(nil).OpenAppend), ctx, contentType, opts)
+}
+
+// SetTags mocks base method.
+func (m *MockAzBlob) SetTags(ctx context.Context, tags map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTags", ctx, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTags indicates an expected call of SetTags.
+func (mr *MockAzBlobMockRecorder) SetTags(ctx, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTags", reflect.TypeOf((*MockAzBlob)(nil).SetTags), ctx, tags)
+}
+
 // SignedURL mocks base method.
 func (m *MockAzBlob) SignedURL(ctx context.Context, opts *driver.SignedURLOptions) (string, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +220,21 @@ func (mr *MockAzBlobMockRecorder) SignedURL(ctx, opts any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignedURL", reflect.TypeOf((*MockAzBlob)(nil).SignedURL), ctx, opts)
 }
 
+// SnapshotURL mocks base method.
+func (m *MockAzBlob) SnapshotURL(snapshotID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotURL", snapshotID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotURL indicates an expected call of SnapshotURL.
+func (mr *MockAzBlobMockRecorder) SnapshotURL(snapshotID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotURL", reflect.TypeOf((*MockAzBlob)(nil).SnapshotURL), snapshotID)
+}
+
 // StartCopyFromURL mocks base method.
 func (m *MockAzBlob) StartCopyFromURL(ctx context.Context, url string, opts *driver.CopyOptions) (blob.StartCopyFromURLResponse, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +288,36 @@ func (m *MockAzService) EXPECT() *MockAzServiceMockRecorder {
 	return m.recorder
 }
 
+// EncryptionInfo mocks base method.
+func (m *MockAzService) EncryptionInfo(ctx context.Context) (*driver.EncryptionInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptionInfo", ctx)
+	ret0, _ := ret[0].(*driver.EncryptionInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptionInfo indicates an expected call of EncryptionInfo.
+func (mr *MockAzServiceMockRecorder) EncryptionInfo(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptionInfo", reflect.TypeOf((*MockAzService)(nil).EncryptionInfo), ctx)
+}
+
+// ListBlobs mocks base method.
+func (m *MockAzService) ListBlobs(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlobs", ctx, opts)
+	ret0, _ := ret[0].(*driver.ListPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlobs indicates an expected call of ListBlobs.
+func (mr *MockAzServiceMockRecorder) ListBlobs(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlobs", reflect.TypeOf((*MockAzService)(nil).ListBlobs), ctx, opts)
+}
+
 // NewBlob mocks base method.
 func (m *MockAzService) NewBlob(ctx context.Context, name string) (azurestore.AzBlob, error) {
 	m.ctrl.T.Helper()
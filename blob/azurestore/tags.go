@@ -0,0 +1,127 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/kopexa-grc/common/blob/driver"
+	"github.com/kopexa-grc/common/blob/internal/escape"
+)
+
+// Ensure that AzureStore implements driver.Tagger.
+var _ driver.Tagger = (*AzureStore)(nil)
+
+// SetTags implements driver.Tagger using blob index tags.
+func (store *AzureStore) SetTags(ctx context.Context, key string, tags map[string]string) error {
+	blob, err := store.Service.NewBlob(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return blob.SetTags(ctx, tags)
+}
+
+// GetTags implements driver.Tagger using blob index tags.
+func (store *AzureStore) GetTags(ctx context.Context, key string) (map[string]string, error) {
+	blob, err := store.Service.NewBlob(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blob.GetTags(ctx)
+}
+
+// SetTags replaces the index tags of the blockBlob.
+func (blockBlob *BlockBlob) SetTags(ctx context.Context, tags map[string]string) error {
+	_, err := blockBlob.BlobClient.SetTags(ctx, tags, nil)
+
+	return err
+}
+
+// GetTags returns the index tags of the blockBlob.
+func (blockBlob *BlockBlob) GetTags(ctx context.Context) (map[string]string, error) {
+	resp, err := blockBlob.BlobClient.GetTags(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(resp.BlobTagSet))
+
+	for _, tag := range resp.BlobTagSet {
+		if tag == nil || tag.Key == nil {
+			continue
+		}
+
+		var value string
+		if tag.Value != nil {
+			value = *tag.Value
+		}
+
+		tags[*tag.Key] = value
+	}
+
+	return tags, nil
+}
+
+// filterBlobs lists a single page of blobs carrying all of opts.Tags. The
+// Find Blobs by Tags operation does not report blob properties, so Size and
+// ModTime of the returned objects are zero. It cannot filter by prefix either;
+// opts.Prefix is applied to the returned page instead.
+func (service *azService) filterBlobs(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	filterOpts := &container.FilterBlobsOptions{}
+
+	if opts.PageSize > 0 {
+		filterOpts.MaxResults = to.Ptr(int32(min(opts.PageSize, maxListPageSize))) //nolint:gosec // bounded by maxListPageSize
+	}
+
+	if len(opts.PageToken) > 0 {
+		filterOpts.Marker = to.Ptr(string(opts.PageToken))
+	}
+
+	resp, err := service.ContainerClient.FilterBlobs(ctx, tagFilterExpression(opts.Tags), filterOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &driver.ListPage{}
+
+	for _, item := range resp.Blobs {
+		if item == nil || item.Name == nil {
+			continue
+		}
+
+		key := escape.HexUnescape(*item.Name)
+		if !strings.HasPrefix(key, opts.Prefix) {
+			continue
+		}
+
+		page.Objects = append(page.Objects, &driver.ListObject{Key: key})
+	}
+
+	if resp.NextMarker != nil && *resp.NextMarker != "" {
+		page.NextPageToken = []byte(*resp.NextMarker)
+	}
+
+	return page, nil
+}
+
+// tagFilterExpression builds the where expression of a Find Blobs by Tags
+// request matching blobs that carry all tags. Keys are sorted so equal
+// filters yield equal expressions.
+func tagFilterExpression(tags map[string]string) string {
+	keys := slices.Sorted(maps.Keys(tags))
+	clauses := make([]string, 0, len(keys))
+
+	for _, k := range keys {
+		clauses = append(clauses, `"`+strings.ReplaceAll(k, `"`, `""`)+`"='`+strings.ReplaceAll(tags[k], "'", "''")+"'")
+	}
+
+	return strings.Join(clauses, " AND ")
+}
@@ -0,0 +1,41 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob/azurestore"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	tags := map[string]string{"status": "approved"}
+
+	mockService := NewMockAzService(mockCtrl)
+	mockBlob := NewMockAzBlob(mockCtrl)
+
+	gomock.InOrder(
+		mockService.EXPECT().NewBlob(ctx, mockID).Return(mockBlob, nil).Times(1),
+		mockBlob.EXPECT().SetTags(ctx, tags).Return(nil).Times(1),
+		mockService.EXPECT().NewBlob(ctx, mockID).Return(mockBlob, nil).Times(1),
+		mockBlob.EXPECT().GetTags(ctx).Return(tags, nil).Times(1),
+	)
+
+	store := azurestore.New(mockService)
+
+	assert.NoError(store.SetTags(ctx, mockID, tags))
+
+	got, err := store.GetTags(ctx, mockID)
+	assert.NoError(err)
+	assert.Equal(tags, got)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/kopexa-grc/common/blob/driver"
+)
+
+// Ensure that AzureStore implements driver.UploadFormSigner.
+var _ driver.UploadFormSigner = (*AzureStore)(nil)
+
+// SignedUploadForm implements driver.UploadFormSigner.
+//
+// Azure Blob Storage has no form upload endpoint, so the returned form is a
+// PUT of the raw file to a SAS URL with create and write permissions. SAS
+// tokens cannot restrict the size or content type of the upload; neither
+// condition is enforced by the service.
+func (store *AzureStore) SignedUploadForm(ctx context.Context, key string, opts *driver.UploadFormOptions) (*driver.UploadForm, error) {
+	blob, err := store.Service.NewBlob(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().UTC().Add(opts.Expiry)
+
+	url, err := blob.SignedURL(ctx, &driver.SignedURLOptions{
+		Expiry:     opts.Expiry,
+		Method:     http.MethodPut,
+		BeforeSign: opts.BeforeSign,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{"x-ms-blob-type": "BlockBlob"}
+
+	if len(opts.ContentTypes) == 1 {
+		headers["Content-Type"] = opts.ContentTypes[0]
+	}
+
+	return &driver.UploadForm{
+		URL:       url,
+		Method:    http.MethodPut,
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}, nil
+}
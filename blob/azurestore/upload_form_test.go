@@ -0,0 +1,57 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package azurestore_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/blob/azurestore"
+	"github.com/kopexa-grc/common/blob/driver"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestSignedUploadForm(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	signedURL := "https://account.blob.core.windows.net/container/123?sig=abc"
+
+	mockService := NewMockAzService(mockCtrl)
+	mockBlob := NewMockAzBlob(mockCtrl)
+
+	gomock.InOrder(
+		mockService.EXPECT().NewBlob(ctx, mockID).Return(mockBlob, nil).Times(1),
+		mockBlob.EXPECT().
+			SignedURL(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, opts *driver.SignedURLOptions) (string, error) {
+				assert.Equal(http.MethodPut, opts.Method)
+				assert.Equal(time.Minute, opts.Expiry)
+
+				return signedURL, nil
+			}).
+			Times(1),
+	)
+
+	store := azurestore.New(mockService)
+
+	form, err := store.SignedUploadForm(ctx, mockID, &driver.UploadFormOptions{
+		Expiry:       time.Minute,
+		MaxSize:      1024,
+		ContentTypes: []string{"application/pdf"},
+	})
+	assert.NoError(err)
+	assert.Equal(signedURL, form.URL)
+	assert.Equal(http.MethodPut, form.Method)
+	assert.Equal("BlockBlob", form.Headers["x-ms-blob-type"])
+	assert.Equal("application/pdf", form.Headers["Content-Type"])
+	assert.False(form.EnforcesMaxSize)
+	assert.False(form.EnforcesContentType)
+}
@@ -59,6 +59,7 @@ package blob
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/kopexa-grc/common/blob/azurestore"
 )
@@ -84,6 +85,14 @@ type Config struct {
 	// Azure contains the configuration for Azure Blob Storage.
 	// This is the primary supported storage backend.
 	Azure AzureConfig
+
+	// CDN configures how objects of the public bucket are served through a
+	// CDN. Optional; without it Bucket.PublicURL is not available.
+	CDN *CDNConfig
+
+	// SpaceQuota limits the number of bytes each space bucket may store.
+	// Optional; without it space buckets are unlimited.
+	SpaceQuota *QuotaConfig
 }
 
 // AzureConfig contains the configuration parameters for Azure Blob Storage.
@@ -108,6 +117,12 @@ type AzureConfig struct {
 	// https://{account-name}.blob.core.windows.net
 	// For Azure Government or other sovereign clouds, the endpoint may differ.
 	Endpoint string
+
+	// DisableContainerCreation stops buckets from creating their container
+	// on first use. Enable it in production, where containers are
+	// provisioned ahead of time and the account key may not be allowed to
+	// create them; operations on missing containers then fail.
+	DisableContainerCreation bool
 }
 
 // BucketProvider provides access to different types of blob storage buckets.
@@ -121,6 +136,15 @@ type BucketProvider struct {
 	// config holds the storage configuration used to create bucket instances.
 	// The configuration is immutable after creation.
 	config *Config
+
+	// quotas holds one *QuotaEnforcer per space ID, so that all buckets of a
+	// space share their usage and reservations.
+	quotas sync.Map
+
+	// pool shares Azure clients and containers between the buckets of the
+	// provider, so that opening a bucket neither builds a new client nor
+	// contacts Azure.
+	pool *azurestore.ServicePool
 }
 
 // New creates a new BucketProvider with the specified configuration.
@@ -163,7 +187,19 @@ func New(config *Config) (*BucketProvider, error) {
 		return nil, fmt.Errorf("%w", ErrMissingEndpoint)
 	}
 
-	return &BucketProvider{config: config}, nil
+	if config.CDN != nil {
+		if err := config.CDN.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.SpaceQuota != nil {
+		if err := config.SpaceQuota.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &BucketProvider{config: config, pool: azurestore.NewServicePool()}, nil
 }
 
 // Public returns a bucket for public blob storage.
@@ -176,8 +212,11 @@ func New(config *Config) (*BucketProvider, error) {
 // files to have different access permissions while maintaining the overall
 // public nature of the bucket.
 //
-// Returns an error if the bucket cannot be created due to configuration
-// issues or connectivity problems with the underlying storage service.
+// Buckets share their Azure client with the other buckets of the provider.
+// The container is created on first use unless
+// AzureConfig.DisableContainerCreation is set, so Public does not contact
+// Azure. Returns an error if the bucket cannot be created due to configuration
+// issues.
 //
 // Example:
 //
@@ -192,22 +231,23 @@ func New(config *Config) (*BucketProvider, error) {
 //	err = publicBucket.Upload(ctx, "images/logo.jpg", file, nil)
 func (p *BucketProvider) Public() (*Bucket, error) {
 	azConfig := &azurestore.AzConfig{
-		AccountName:         p.config.Azure.AccountName,
-		AccountKey:          p.config.Azure.AccountKey,
-		Endpoint:            p.config.Azure.Endpoint,
-		ContainerName:       PublicContainer,
-		ContainerAccessType: blobAccessType,
-		BlobAccessTier:      hotAccessTier,
+		AccountName:              p.config.Azure.AccountName,
+		AccountKey:               p.config.Azure.AccountKey,
+		Endpoint:                 p.config.Azure.Endpoint,
+		ContainerName:            PublicContainer,
+		ContainerAccessType:      blobAccessType,
+		BlobAccessTier:           hotAccessTier,
+		DisableContainerCreation: p.config.Azure.DisableContainerCreation,
 	}
 
-	azService, err := azurestore.NewAzureService(azConfig)
+	azService, err := p.pool.Service(azConfig)
 	if err != nil {
 		return nil, fmt.Errorf("blob: failed to create Azure service: %w", err)
 	}
 
 	store := azurestore.New(azService)
 
-	return &Bucket{b: store}, nil
+	return &Bucket{b: store, cdn: p.config.CDN}, nil
 }
 
 // Space returns a bucket for space-specific blob storage.
@@ -225,9 +265,9 @@ func (p *BucketProvider) Public() (*Bucket, error) {
 // for all operations. This provides security and isolation for
 // workspace-specific data.
 //
-// Returns an error if the bucket cannot be created due to configuration
-// issues, invalid spaceID, or connectivity problems with the underlying
-// storage service.
+// As with Public, the container is created on first use. Returns an error if
+// the bucket cannot be created due to configuration issues or an invalid
+// spaceID.
 //
 // The spaceID parameter must be a valid string that can be used in
 // Azure container names. Invalid characters will result in an error.
@@ -249,20 +289,44 @@ func (p *BucketProvider) Space(spaceID string) (*Bucket, error) {
 	}
 
 	azConfig := &azurestore.AzConfig{
-		AccountName:         p.config.Azure.AccountName,
-		AccountKey:          p.config.Azure.AccountKey,
-		Endpoint:            p.config.Azure.Endpoint,
-		ContainerName:       fmt.Sprintf("space-%s", spaceID),
-		ContainerAccessType: privateAccessType,
-		BlobAccessTier:      hotAccessTier,
+		AccountName:              p.config.Azure.AccountName,
+		AccountKey:               p.config.Azure.AccountKey,
+		Endpoint:                 p.config.Azure.Endpoint,
+		ContainerName:            spaceContainer(spaceID),
+		ContainerAccessType:      privateAccessType,
+		BlobAccessTier:           hotAccessTier,
+		DisableContainerCreation: p.config.Azure.DisableContainerCreation,
 	}
 
-	azService, err := azurestore.NewAzureService(azConfig)
+	azService, err := p.pool.Service(azConfig)
 	if err != nil {
 		return nil, fmt.Errorf("blob: failed to create Azure service: %w", err)
 	}
 
 	store := azurestore.New(azService)
 
-	return &Bucket{b: store}, nil
+	return &Bucket{b: store, quota: p.spaceQuota(spaceID)}, nil
+}
+
+// spaceContainer returns the name of the container of the given space.
+func spaceContainer(spaceID string) string {
+	return "space-" + spaceID
+}
+
+// spaceQuota returns the QuotaEnforcer of the given space, or nil if no
+// space quota is configured.
+func (p *BucketProvider) spaceQuota(spaceID string) *QuotaEnforcer {
+	if p.config.SpaceQuota == nil {
+		return nil
+	}
+
+	if q, ok := p.quotas.Load(spaceID); ok {
+		return q.(*QuotaEnforcer) //nolint:forcetypeassert // only *QuotaEnforcer is stored
+	}
+
+	// The configuration was validated in New.
+	q, _ := NewQuotaEnforcer(*p.config.SpaceQuota)
+	actual, _ := p.quotas.LoadOrStore(spaceID, q)
+
+	return actual.(*QuotaEnforcer) //nolint:forcetypeassert // only *QuotaEnforcer is stored
 }
@@ -28,6 +28,12 @@ import (
 type Bucket struct {
 	b driver.Bucket
 
+	// cdn configures PublicURL; nil if no CDN is used.
+	cdn *CDNConfig
+
+	// quota limits the bytes written through NewWriter; nil if unlimited.
+	quota *QuotaEnforcer
+
 	// mu protects the closed variable.
 	// Read locks are kept to allow holding a read lock for long-running calls,
 	// and thereby prevent closing until a call finishes.
@@ -55,7 +61,7 @@ func (b *Bucket) Delete(ctx context.Context, key string) (err error) {
 		return errClosed
 	}
 
-	return b.b.Delete(ctx, key)
+	return wrapError(b.b, b.b.Delete(ctx, key), key)
 }
 
 // SignedURLOptions sets options for SignedURL.
@@ -144,7 +150,7 @@ func (b *Bucket) SignedURL(ctx context.Context, key string, opts *SignedURLOptio
 
 	url, err := b.b.SignedURL(ctx, key, dopts)
 	if err != nil {
-		return "", err
+		return "", wrapError(b.b, err, key)
 	}
 
 	return url, nil
@@ -190,7 +196,7 @@ func (b *Bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *CopyOpti
 		return errClosed
 	}
 
-	return b.b.Copy(ctx, dstKey, srcKey, dopts)
+	return wrapError(b.b, b.b.Copy(ctx, dstKey, srcKey, dopts), srcKey)
 }
 
 // ReaderOptions sets options for NewReader and NewRangeReader.
@@ -289,6 +295,12 @@ type WriterOptions struct {
 	// be left untouched. An error for which gcerrors.Code will return
 	// gcerrors.PreconditionFailed will be returned by Write or Close.
 	IfNotExist bool
+
+	// ImagePipeline processes the written image before it is stored, e.g.
+	// to strip EXIF metadata and write thumbnails to derived keys. The image
+	// is buffered in memory and written on Close; ContentType is set from
+	// the output format. See ImagePipeline for details.
+	ImagePipeline *ImagePipeline
 }
 
 // Uploads reads from a io.Reader and writes into a blob
@@ -331,6 +343,12 @@ func (b *Bucket) NewWriter(ctx context.Context, key string, opts *WriterOptions)
 		opts = &WriterOptions{}
 	}
 
+	if opts.ImagePipeline != nil {
+		if err := opts.ImagePipeline.validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	dopts := &driver.WriterOptions{
 		CacheControl:                opts.CacheControl,
 		ContentDisposition:          opts.ContentDisposition,
@@ -381,6 +399,18 @@ func (b *Bucket) NewWriter(ctx context.Context, key string, opts *WriterOptions)
 		return nil, errClosed
 	}
 
+	if b.quota != nil {
+		if err := b.quota.check(ctx, b.b); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.ImagePipeline != nil {
+		// The processed image and its variants are written through
+		// Upload on Close, which applies quota and metadata handling.
+		return &Writer{b: b.b, key: key, image: newImageWriter(ctx, b, key, opts)}, nil
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	w := &Writer{
@@ -389,6 +419,7 @@ func (b *Bucket) NewWriter(ctx context.Context, key string, opts *WriterOptions)
 		key:        key,
 		contentMD5: opts.ContentMD5,
 		md5hash:    md5.New(), //nolint:gosec // MD5 is used for Content-MD5 validation as per RFC 1864
+		quota:      b.quota,
 		ctx:        ctx,
 	}
 
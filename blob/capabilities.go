@@ -0,0 +1,65 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import "github.com/kopexa-grc/common/blob/driver"
+
+// Capabilities describes the optional features supported by the driver of a
+// bucket. Calling an unsupported feature returns an error for which kerr.Code
+// returns kerr.NotImplemented.
+type Capabilities struct {
+	// SignedURL reports support for SignedURL.
+	SignedURL bool `json:"signedURL"`
+	// IfNotExist reports support for conditional writes with
+	// WriterOptions.IfNotExist.
+	IfNotExist bool `json:"ifNotExist"`
+	// Snapshots reports support for object versioning with Snapshot,
+	// ListSnapshots, NewSnapshotReader and PromoteSnapshot.
+	Snapshots bool `json:"snapshots"`
+	// Tags reports support for SetTags, GetTags and ListOptions.Tags.
+	Tags bool `json:"tags"`
+	// List reports support for List and Usage.
+	List bool `json:"list"`
+	// UploadForm reports support for SignedUploadForm.
+	UploadForm bool `json:"uploadForm"`
+	// Append reports native support for NewAppender. Other drivers emulate
+	// it by rewriting the object with every block.
+	Append bool `json:"append"`
+	// Encryption reports support for EncryptionInfo.
+	Encryption bool `json:"encryption"`
+}
+
+// Capabilities returns the optional features supported by the driver of the
+// bucket, so that callers can adapt their behavior per backend instead of
+// handling NotImplemented errors.
+//
+// Example:
+//
+//	if !bucket.Capabilities().Snapshots {
+//	    // keep previous versions under a separate key instead
+//	}
+func (b *Bucket) Capabilities() Capabilities {
+	var reported driver.Capability
+	if r, ok := b.b.(driver.CapabilityReporter); ok {
+		reported = r.Capabilities()
+	}
+
+	_, snapshots := b.b.(driver.Snapshotter)
+	_, tags := b.b.(driver.Tagger)
+	_, list := b.b.(driver.Lister)
+	_, uploadForm := b.b.(driver.UploadFormSigner)
+	_, appender := b.b.(driver.Appender)
+	_, encryption := b.b.(driver.EncryptionReporter)
+
+	return Capabilities{
+		SignedURL:  reported.Has(driver.CapSignedURL),
+		IfNotExist: reported.Has(driver.CapIfNotExist),
+		Snapshots:  snapshots,
+		Tags:       tags,
+		List:       list,
+		UploadForm: uploadForm,
+		Append:     appender,
+		Encryption: encryption,
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"testing"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// reportingDriver is a driver.Bucket that also implements
+// driver.CapabilityReporter and driver.Snapshotter.
+type reportingDriver struct {
+	*MockBucket
+	*MockCapabilityReporter
+	*MockSnapshotter
+}
+
+func TestBucket_Capabilities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("base driver", func(t *testing.T) {
+		bucket := blob.NewBucketForTest(NewMockBucket(ctrl))
+		assert.Equal(t, blob.Capabilities{}, bucket.Capabilities())
+	})
+
+	t.Run("optional interfaces", func(t *testing.T) {
+		bucket := blob.NewBucketForTest(&taggerDriver{MockBucket: NewMockBucket(ctrl)})
+		assert.Equal(t, blob.Capabilities{Tags: true, List: true}, bucket.Capabilities())
+
+		bucket = blob.NewBucketForTest(&uploadFormDriver{MockBucket: NewMockBucket(ctrl)})
+		assert.Equal(t, blob.Capabilities{List: true, UploadForm: true}, bucket.Capabilities())
+	})
+
+	t.Run("reported capabilities", func(t *testing.T) {
+		reporter := NewMockCapabilityReporter(ctrl)
+		reporter.EXPECT().Capabilities().Return(driver.CapSignedURL | driver.CapIfNotExist)
+
+		bucket := blob.NewBucketForTest(&reportingDriver{MockBucket: NewMockBucket(ctrl), MockCapabilityReporter: reporter})
+		assert.Equal(t, blob.Capabilities{SignedURL: true, IfNotExist: true, Snapshots: true}, bucket.Capabilities())
+	})
+}
+
+func TestCapability_Has(t *testing.T) {
+	caps := driver.CapSignedURL
+
+	assert.True(t, caps.Has(driver.CapSignedURL))
+	assert.False(t, caps.Has(driver.CapIfNotExist))
+	assert.False(t, caps.Has(driver.CapSignedURL|driver.CapIfNotExist))
+}
@@ -0,0 +1,234 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// DefaultCDNTokenExpiry is the default lifetime of signed CDN URLs.
+const DefaultCDNTokenExpiry = 1 * time.Hour
+
+// Query parameters added to CDN URLs by PublicURL.
+const (
+	// CDNVersionParam carries the cache-busting content version.
+	CDNVersionParam = "v"
+	// CDNExpiresParam carries the Unix expiry time of a signed URL.
+	CDNExpiresParam = "exp"
+	// CDNSignatureParam carries the signature of a signed URL.
+	CDNSignatureParam = "sig"
+)
+
+// CDNConfig configures how public objects are served through a CDN.
+type CDNConfig struct {
+	// BaseURL is the CDN endpoint that fronts the public container,
+	// e.g. "https://cdn.kopexa.com/assets". Keys are appended as path.
+	BaseURL string
+
+	// SigningKey is the shared secret used to sign CDN URLs. The CDN (or an
+	// edge function in front of it) verifies the signature with the same key.
+	SigningKey string
+
+	// RequireSignedURLs signs every URL returned by PublicURL. If false, URLs
+	// are only signed when PublicURLOptions.Signed is set.
+	RequireSignedURLs bool
+
+	// TokenExpiry is the default lifetime of signed URLs.
+	// Defaults to DefaultCDNTokenExpiry.
+	TokenExpiry time.Duration
+}
+
+// validate checks that the configuration can be used to build URLs.
+func (c *CDNConfig) validate() error {
+	u, err := url.Parse(c.BaseURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return kerr.Newf(kerr.InvalidArgument, err, "blob: CDN base URL must be an absolute http(s) URL: %q", c.BaseURL)
+	}
+
+	if c.RequireSignedURLs && c.SigningKey == "" {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: CDN signing key is required when signed URLs are required")
+	}
+
+	return nil
+}
+
+// PublicURLOptions sets options for PublicURL.
+type PublicURLOptions struct {
+	// CacheBust appends a content version as query parameter so that the CDN
+	// caches each version of the object separately. The version is the
+	// object's Content-MD5 (or its modification time if the service does not
+	// report one) and requires a metadata lookup unless ContentHash is set.
+	CacheBust bool
+
+	// ContentHash is used as cache-busting version instead of looking it up.
+	// Setting it implies CacheBust.
+	ContentHash string
+
+	// Signed requests a signed URL even if the CDN does not require one.
+	Signed bool
+
+	// Expiry sets how long a signed URL is valid for.
+	// Defaults to CDNConfig.TokenExpiry.
+	Expiry time.Duration
+
+	// BeforeRead is passed to the metadata lookup; see ReaderOptions.BeforeRead.
+	BeforeRead func(asFunc func(any) bool) error
+}
+
+// PublicURL returns the CDN URL for the public object stored at key.
+//
+// The URL is built from the bucket's CDNConfig.BaseURL and the key. Depending
+// on opts, a cache-busting version parameter is added, and the URL is signed
+// with HMAC-SHA256 over its path and query (see CDNConfig.VerifyURL).
+//
+// A nil PublicURLOptions is treated the same as the zero value.
+//
+// If the bucket has no CDN configured, PublicURL returns an error for which
+// kerr.Code will return kerr.FailedPrecondition.
+func (b *Bucket) PublicURL(ctx context.Context, key string, opts *PublicURLOptions) (string, error) {
+	if !utf8.ValidString(key) {
+		return "", kerr.Newf(kerr.InvalidArgument, nil, "blob: PublicURL key must be a valid UTF-8 string: %q", key)
+	}
+
+	if key == "" {
+		return "", kerr.Newf(kerr.InvalidArgument, nil, "blob: PublicURL key must be a non-empty string")
+	}
+
+	if opts == nil {
+		opts = &PublicURLOptions{}
+	}
+
+	if opts.Expiry < 0 {
+		return "", kerr.Newf(kerr.InvalidArgument, nil, "blob: PublicURL expiry must be non-negative: %q", opts.Expiry)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return "", errClosed
+	}
+
+	if b.cdn == nil {
+		return "", kerr.Newf(kerr.FailedPrecondition, nil, "blob: PublicURL requires a CDN configuration")
+	}
+
+	signed := b.cdn.RequireSignedURLs || opts.Signed
+	if signed && b.cdn.SigningKey == "" {
+		return "", kerr.Newf(kerr.FailedPrecondition, nil, "blob: PublicURL cannot sign URLs without a CDN signing key")
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(b.cdn.BaseURL, "/"))
+	if err != nil {
+		return "", kerr.Newf(kerr.InvalidArgument, err, "blob: invalid CDN base URL: %q", b.cdn.BaseURL)
+	}
+
+	u = u.JoinPath(strings.Split(key, "/")...)
+	q := url.Values{}
+
+	version := opts.ContentHash
+	if version == "" && opts.CacheBust {
+		if version, err = b.contentVersion(ctx, key, opts.BeforeRead); err != nil {
+			return "", err
+		}
+	}
+
+	if version != "" {
+		q.Set(CDNVersionParam, version)
+	}
+
+	if signed {
+		expiry := opts.Expiry
+		if expiry == 0 {
+			expiry = b.cdn.TokenExpiry
+		}
+
+		if expiry == 0 {
+			expiry = DefaultCDNTokenExpiry
+		}
+
+		q.Set(CDNExpiresParam, strconv.FormatInt(time.Now().Add(expiry).Unix(), 10))
+		q.Set(CDNSignatureParam, signCDN(b.cdn.SigningKey, u.EscapedPath(), q))
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// contentVersion returns a version string for the object at key, derived
+// from its Content-MD5 or, if unavailable, its modification time.
+func (b *Bucket) contentVersion(ctx context.Context, key string, beforeRead func(asFunc func(any) bool) error) (string, error) {
+	// A zero-length read fetches the attributes without transferring content.
+	r, err := b.b.NewRangeReader(ctx, key, 0, 0, &driver.ReaderOptions{BeforeRead: beforeRead})
+	if err != nil {
+		return "", wrapError(b.b, err, key)
+	}
+
+	attrs := r.Attributes()
+	_ = r.Close()
+
+	if len(attrs.ContentMD5) > 0 {
+		return hex.EncodeToString(attrs.ContentMD5), nil
+	}
+
+	return strconv.FormatInt(attrs.ModTime.Unix(), 36), nil
+}
+
+// VerifyURL checks the signature and expiry of a signed CDN URL produced by
+// PublicURL. It is intended for edge functions or origin handlers that
+// validate requests forwarded by the CDN.
+func (c *CDNConfig) VerifyURL(rawURL string) error {
+	if c.SigningKey == "" {
+		return kerr.Newf(kerr.FailedPrecondition, nil, "blob: CDN signing key is not configured")
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return kerr.Newf(kerr.InvalidArgument, err, "blob: invalid CDN URL")
+	}
+
+	q := u.Query()
+
+	sig := q.Get(CDNSignatureParam)
+	if sig == "" {
+		return kerr.Newf(kerr.Unauthorized, nil, "blob: CDN URL is not signed")
+	}
+
+	q.Del(CDNSignatureParam)
+
+	if !hmac.Equal([]byte(sig), []byte(signCDN(c.SigningKey, u.EscapedPath(), q))) {
+		return kerr.Newf(kerr.Unauthorized, nil, "blob: CDN URL signature is invalid")
+	}
+
+	exp, err := strconv.ParseInt(q.Get(CDNExpiresParam), 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return kerr.Newf(kerr.Unauthorized, nil, "blob: CDN URL has expired")
+	}
+
+	return nil
+}
+
+// signCDN returns the base64url encoded HMAC-SHA256 of the escaped path
+// (normalized to a leading slash) and the encoded (sorted) query.
+func signCDN(key, path string, q url.Values) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("/" + strings.TrimPrefix(path, "/")))
+	mac.Write([]byte("?"))
+	mac.Write([]byte(q.Encode()))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,164 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newCDNBucket(t *testing.T, cdn *blob.CDNConfig) (*blob.Bucket, *MockBucket) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockDriver := NewMockBucket(ctrl)
+	bucket := blob.NewBucketForTest(mockDriver)
+	blob.SetCDNForTest(bucket, cdn)
+
+	return bucket, mockDriver
+}
+
+func TestBucket_PublicURL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("plain url", func(t *testing.T) {
+		bucket, _ := newCDNBucket(t, &blob.CDNConfig{BaseURL: "https://cdn.kopexa.com/assets/"})
+
+		u, err := bucket.PublicURL(ctx, "images/logo v2.png", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "https://cdn.kopexa.com/assets/images/logo%20v2.png", u)
+	})
+
+	t.Run("cache bust with content md5", func(t *testing.T) {
+		bucket, mockDriver := newCDNBucket(t, &blob.CDNConfig{BaseURL: "https://cdn.kopexa.com"})
+
+		mockDriver.EXPECT().
+			NewRangeReader(gomock.Any(), "logo.png", int64(0), int64(0), gomock.Any()).
+			Return(&memReader{
+				Reader: bytes.NewReader(nil),
+				attrs:  driver.ReaderAttributes{ContentMD5: []byte{0xde, 0xad, 0xbe, 0xef}},
+			}, nil)
+
+		u, err := bucket.PublicURL(ctx, "logo.png", &blob.PublicURLOptions{CacheBust: true})
+		require.NoError(t, err)
+		assert.Equal(t, "https://cdn.kopexa.com/logo.png?v=deadbeef", u)
+	})
+
+	t.Run("cache bust falls back to mod time", func(t *testing.T) {
+		bucket, mockDriver := newCDNBucket(t, &blob.CDNConfig{BaseURL: "https://cdn.kopexa.com"})
+
+		mockDriver.EXPECT().
+			NewRangeReader(gomock.Any(), "logo.png", int64(0), int64(0), gomock.Any()).
+			Return(&memReader{
+				Reader: bytes.NewReader(nil),
+				attrs:  driver.ReaderAttributes{ModTime: time.Unix(36, 0)},
+			}, nil)
+
+		u, err := bucket.PublicURL(ctx, "logo.png", &blob.PublicURLOptions{CacheBust: true})
+		require.NoError(t, err)
+		assert.Equal(t, "https://cdn.kopexa.com/logo.png?v=10", u)
+	})
+
+	t.Run("explicit content hash skips lookup", func(t *testing.T) {
+		bucket, _ := newCDNBucket(t, &blob.CDNConfig{BaseURL: "https://cdn.kopexa.com"})
+
+		u, err := bucket.PublicURL(ctx, "logo.png", &blob.PublicURLOptions{ContentHash: "abc"})
+		require.NoError(t, err)
+		assert.Equal(t, "https://cdn.kopexa.com/logo.png?v=abc", u)
+	})
+
+	t.Run("signed url verifies", func(t *testing.T) {
+		cdn := &blob.CDNConfig{
+			BaseURL:           "https://cdn.kopexa.com",
+			SigningKey:        "secret",
+			RequireSignedURLs: true,
+		}
+		bucket, _ := newCDNBucket(t, cdn)
+
+		u, err := bucket.PublicURL(ctx, "docs/report.pdf", &blob.PublicURLOptions{ContentHash: "abc", Expiry: time.Minute})
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(u)
+		require.NoError(t, err)
+		assert.NotEmpty(t, parsed.Query().Get(blob.CDNSignatureParam))
+		assert.NotEmpty(t, parsed.Query().Get(blob.CDNExpiresParam))
+
+		assert.NoError(t, cdn.VerifyURL(u))
+
+		// tampering with the version invalidates the signature
+		q := parsed.Query()
+		q.Set(blob.CDNVersionParam, "other")
+		parsed.RawQuery = q.Encode()
+		assert.True(t, kerr.Is(cdn.VerifyURL(parsed.String()), kerr.Unauthorized))
+
+		// a different key does not verify
+		other := &blob.CDNConfig{SigningKey: "other"}
+		assert.Error(t, other.VerifyURL(u))
+	})
+
+	t.Run("expired signed url", func(t *testing.T) {
+		cdn := &blob.CDNConfig{BaseURL: "https://cdn.kopexa.com", SigningKey: "secret"}
+		bucket, _ := newCDNBucket(t, cdn)
+
+		u, err := bucket.PublicURL(ctx, "logo.png", &blob.PublicURLOptions{Signed: true, Expiry: time.Nanosecond})
+		require.NoError(t, err)
+
+		time.Sleep(1100 * time.Millisecond)
+
+		assert.True(t, kerr.Is(cdn.VerifyURL(u), kerr.Unauthorized))
+	})
+
+	t.Run("signing without key", func(t *testing.T) {
+		bucket, _ := newCDNBucket(t, &blob.CDNConfig{BaseURL: "https://cdn.kopexa.com"})
+
+		_, err := bucket.PublicURL(ctx, "logo.png", &blob.PublicURLOptions{Signed: true})
+		assert.True(t, kerr.Is(err, kerr.FailedPrecondition))
+	})
+
+	t.Run("no cdn configured", func(t *testing.T) {
+		bucket, _ := newCDNBucket(t, nil)
+
+		_, err := bucket.PublicURL(ctx, "logo.png", nil)
+		assert.True(t, kerr.Is(err, kerr.FailedPrecondition))
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		bucket, _ := newCDNBucket(t, &blob.CDNConfig{BaseURL: "https://cdn.kopexa.com"})
+
+		_, err := bucket.PublicURL(ctx, "", nil)
+		assert.True(t, kerr.Is(err, kerr.InvalidArgument))
+	})
+}
+
+func TestNew_CDNConfig(t *testing.T) {
+	config := func(cdn *blob.CDNConfig) *blob.Config {
+		return &blob.Config{
+			Azure: blob.AzureConfig{
+				AccountName: "test-account",
+				AccountKey:  "dGVzdC1rZXk=",
+				Endpoint:    "https://test.blob.core.windows.net",
+			},
+			CDN: cdn,
+		}
+	}
+
+	_, err := blob.New(config(&blob.CDNConfig{BaseURL: "https://cdn.kopexa.com"}))
+	assert.NoError(t, err)
+
+	_, err = blob.New(config(&blob.CDNConfig{BaseURL: "cdn.kopexa.com"}))
+	assert.Error(t, err)
+
+	_, err = blob.New(config(&blob.CDNConfig{BaseURL: "https://cdn.kopexa.com", RequireSignedURLs: true}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,353 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is used for Content-MD5 validation as per RFC 1864
+	"hash"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+const (
+	// DefaultDownloadPartSize is the default size of a single range read
+	// issued by DownloadParallel.
+	DefaultDownloadPartSize = 8 * 1024 * 1024
+	// DefaultDownloadConcurrency is the default number of concurrent range
+	// reads issued by DownloadParallel.
+	DefaultDownloadConcurrency = 5
+	// DefaultDownloadMaxRetries is the default number of times a single
+	// failed part is retried by DownloadParallel.
+	DefaultDownloadMaxRetries = 3
+	// DefaultDownloadRetryBackoff is the default delay before the first
+	// retry of a failed part issued by DownloadParallel.
+	DefaultDownloadRetryBackoff = 100 * time.Millisecond
+)
+
+// DownloadOptions sets options for DownloadParallel.
+type DownloadOptions struct {
+	// PartSize is the size in bytes of each range read.
+	// Defaults to DefaultDownloadPartSize.
+	PartSize int64
+
+	// Concurrency is the maximum number of parts downloaded at the same time.
+	// Defaults to DefaultDownloadConcurrency.
+	Concurrency int
+
+	// MaxRetries is the number of times a failed part is retried before the
+	// download is aborted. Each part is retried independently.
+	// Defaults to DefaultDownloadMaxRetries; use a negative value to disable
+	// retries.
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry of a failed part. It
+	// doubles with every further retry of the same part.
+	// Defaults to DefaultDownloadRetryBackoff.
+	RetryBackoff time.Duration
+
+	// BeforeRead is passed to every range read; see ReaderOptions.BeforeRead.
+	BeforeRead func(asFunc func(any) bool) error
+}
+
+// downloadPart is a single downloaded range waiting to be hashed.
+type downloadPart struct {
+	index int
+	data  []byte
+}
+
+// DownloadParallel downloads the blob stored at key into w using concurrent
+// range reads of opts.PartSize bytes. Failed parts are retried independently
+// with exponential backoff, unless the error is permanent, e.g. NotFound or
+// Forbidden.
+//
+// If the driver reports a ContentMD5 for the blob, the digest of the
+// downloaded bytes is verified against it and an error is returned on
+// mismatch. In that case the contents of w must be considered corrupt.
+//
+// A nil DownloadOptions is treated the same as the zero value.
+func (b *Bucket) DownloadParallel(ctx context.Context, key string, w io.WriterAt, opts *DownloadOptions) error {
+	if !utf8.ValidString(key) {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: DownloadParallel key must be a valid UTF-8 string: %q", key)
+	}
+
+	if w == nil {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: DownloadParallel requires a non-nil io.WriterAt")
+	}
+
+	if opts == nil {
+		opts = &DownloadOptions{}
+	}
+
+	partSize := opts.PartSize
+	if partSize < 0 {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: DownloadParallel part size must be non-negative (%d)", partSize)
+	}
+
+	if partSize == 0 {
+		partSize = DefaultDownloadPartSize
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultDownloadConcurrency
+	}
+
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultDownloadMaxRetries
+	} else if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	backoff := opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultDownloadRetryBackoff
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return errClosed
+	}
+
+	dopts := &driver.ReaderOptions{BeforeRead: opts.BeforeRead}
+
+	// A zero-length read fetches the attributes without transferring content;
+	// drivers must not download the object for it.
+	probe, err := b.b.NewRangeReader(ctx, key, 0, 0, dopts)
+	if err != nil {
+		return wrapError(b.b, err, key)
+	}
+
+	attrs := *probe.Attributes()
+	_ = probe.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	d := &parallelDownload{
+		b:          b.b,
+		key:        key,
+		w:          w,
+		dopts:      dopts,
+		size:       attrs.Size,
+		partSize:   partSize,
+		maxRetries: maxRetries,
+		backoff:    backoff,
+	}
+
+	if len(attrs.ContentMD5) > 0 {
+		d.hash = md5.New() //nolint:gosec // MD5 is used for Content-MD5 validation as per RFC 1864
+	}
+
+	if err := d.run(ctx, cancel, concurrency); err != nil {
+		return err
+	}
+
+	if d.hash != nil && !bytes.Equal(d.hash.Sum(nil), attrs.ContentMD5) {
+		return kerr.Newf(kerr.UnexpectedFailure, nil, "blob: DownloadParallel checksum mismatch for key %q", key)
+	}
+
+	return nil
+}
+
+// parallelDownload holds the state of a single DownloadParallel call.
+type parallelDownload struct {
+	b          driver.Bucket
+	key        string
+	w          io.WriterAt
+	dopts      *driver.ReaderOptions
+	size       int64
+	partSize   int64
+	maxRetries int
+	backoff    time.Duration
+	hash       hash.Hash
+}
+
+// run downloads all parts using concurrency workers. Parts are hashed in
+// order; the number of parts held in memory is bounded by 2*concurrency.
+func (d *parallelDownload) run(ctx context.Context, cancel context.CancelFunc, concurrency int) error {
+	numParts := int((d.size + d.partSize - 1) / d.partSize)
+	if numParts == 0 {
+		return nil
+	}
+
+	var (
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+
+			cancel()
+		})
+	}
+
+	slots := make(chan struct{}, 2*concurrency)
+	jobs := make(chan int)
+	done := make(chan downloadPart, concurrency)
+
+	var wg sync.WaitGroup
+
+	for range concurrency {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				data, err := d.fetchPart(ctx, i)
+				if err != nil {
+					fail(err)
+					return
+				}
+
+				done <- downloadPart{index: i, data: data}
+			}
+		}()
+	}
+
+	// Dispatch parts in order, waiting for a free slot for each.
+	go func() {
+		defer close(jobs)
+
+		for i := range numParts {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	pending := make(map[int][]byte)
+	next := 0
+
+	for p := range done {
+		pending[p.index] = p.data
+
+		for data, ok := pending[next]; ok; data, ok = pending[next] {
+			if d.hash != nil {
+				_, _ = d.hash.Write(data)
+			}
+
+			delete(pending, next)
+			next++
+
+			<-slots
+		}
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if err := ctx.Err(); err != nil && next < numParts {
+		return err
+	}
+
+	return nil
+}
+
+// fetchPart downloads part i and writes it to d.w, retrying transient
+// failures with exponential backoff.
+func (d *parallelDownload) fetchPart(ctx context.Context, i int) ([]byte, error) {
+	offset := int64(i) * d.partSize
+
+	length := d.partSize
+	if offset+length > d.size {
+		length = d.size - offset
+	}
+
+	var lastErr error
+
+	delay := d.backoff
+
+	for attempt := 0; attempt <= d.maxRetries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(delay)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+
+			delay *= 2
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		data, err := d.readRange(ctx, offset, length)
+		if err != nil {
+			lastErr = err
+
+			if isPermanent(errorCode(d.b, err)) {
+				break
+			}
+
+			continue
+		}
+
+		if _, err := d.w.WriteAt(data, offset); err != nil {
+			return nil, err
+		}
+
+		return data, nil
+	}
+
+	return nil, wrapError(d.b, lastErr, d.key)
+}
+
+// isPermanent reports whether a failed read with code fails again when
+// retried.
+func isPermanent(code kerr.ErrorCode) bool {
+	switch code {
+	case kerr.NotFound, kerr.Unauthorized, kerr.Forbidden, kerr.InvalidArgument,
+		kerr.FailedPrecondition, kerr.OutOfRange, kerr.NotImplemented:
+		return true
+	default:
+		return false
+	}
+}
+
+// readRange reads exactly length bytes starting at offset.
+func (d *parallelDownload) readRange(ctx context.Context, offset, length int64) ([]byte, error) {
+	r, err := d.b.NewRangeReader(ctx, d.key, offset, length, d.dopts)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,223 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is used for Content-MD5 validation as per RFC 1864
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var errTransient = errors.New("transient")
+
+// memReader is a driver.Reader over an in-memory byte slice.
+type memReader struct {
+	io.Reader
+	attrs driver.ReaderAttributes
+}
+
+func (r *memReader) Close() error                         { return nil }
+func (r *memReader) Attributes() *driver.ReaderAttributes { return &r.attrs }
+func (r *memReader) As(any) bool                          { return false }
+
+// memWriterAt is a concurrency-safe io.WriterAt backed by a byte slice.
+type memWriterAt struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *memWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	copy(w.buf[off:], p)
+
+	return len(p), nil
+}
+
+func rangeReaderFor(data, digest []byte) func(context.Context, string, int64, int64, *driver.ReaderOptions) (driver.Reader, error) {
+	return func(_ context.Context, _ string, offset, length int64, _ *driver.ReaderOptions) (driver.Reader, error) {
+		end := int64(len(data))
+		if length >= 0 && offset+length < end {
+			end = offset + length
+		}
+
+		return &memReader{
+			Reader: bytes.NewReader(data[offset:end]),
+			attrs:  driver.ReaderAttributes{Size: int64(len(data)), ContentMD5: digest},
+		}, nil
+	}
+}
+
+func TestBucket_DownloadParallel(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	sum := md5.Sum(data) //nolint:gosec
+
+	t.Run("downloads and verifies digest", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDriver := NewMockBucket(ctrl)
+		bucket := blob.NewBucketForTest(mockDriver)
+
+		mockDriver.EXPECT().
+			NewRangeReader(gomock.Any(), "key", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(rangeReaderFor(data, sum[:])).
+			AnyTimes()
+
+		w := &memWriterAt{buf: make([]byte, len(data))}
+		err := bucket.DownloadParallel(context.Background(), "key", w, &blob.DownloadOptions{PartSize: 333, Concurrency: 4})
+		require.NoError(t, err)
+		assert.Equal(t, data, w.buf)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDriver := NewMockBucket(ctrl)
+		bucket := blob.NewBucketForTest(mockDriver)
+
+		mockDriver.EXPECT().
+			NewRangeReader(gomock.Any(), "key", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(rangeReaderFor(data, []byte("not-the-digest"))).
+			AnyTimes()
+
+		w := &memWriterAt{buf: make([]byte, len(data))}
+		err := bucket.DownloadParallel(context.Background(), "key", w, &blob.DownloadOptions{PartSize: 1024})
+		assert.Error(t, err)
+	})
+
+	t.Run("retries failed parts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDriver := NewMockBucket(ctrl)
+		bucket := blob.NewBucketForTest(mockDriver)
+
+		var calls atomic.Int32
+
+		read := rangeReaderFor(data, nil)
+
+		mockDriver.EXPECT().
+			NewRangeReader(gomock.Any(), "key", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
+				// Fail the first read of the second part once.
+				if offset == 1000 && calls.Add(1) == 1 {
+					return nil, errTransient
+				}
+
+				return read(ctx, key, offset, length, opts)
+			}).
+			AnyTimes()
+
+		w := &memWriterAt{buf: make([]byte, len(data))}
+		err := bucket.DownloadParallel(context.Background(), "key", w, &blob.DownloadOptions{PartSize: 1000, RetryBackoff: time.Millisecond})
+		require.NoError(t, err)
+		assert.Equal(t, data, w.buf)
+	})
+
+	t.Run("backs off between retries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDriver := NewMockBucket(ctrl)
+		bucket := blob.NewBucketForTest(mockDriver)
+
+		var (
+			mu       sync.Mutex
+			attempts []time.Time
+		)
+
+		read := rangeReaderFor(data, nil)
+
+		mockDriver.EXPECT().
+			NewRangeReader(gomock.Any(), "key", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
+				if offset == 1000 {
+					mu.Lock()
+					attempts = append(attempts, time.Now())
+					n := len(attempts)
+					mu.Unlock()
+
+					if n <= 2 {
+						return nil, errTransient
+					}
+				}
+
+				return read(ctx, key, offset, length, opts)
+			}).
+			AnyTimes()
+
+		w := &memWriterAt{buf: make([]byte, len(data))}
+		err := bucket.DownloadParallel(context.Background(), "key", w, &blob.DownloadOptions{PartSize: 1000, RetryBackoff: 20 * time.Millisecond})
+		require.NoError(t, err)
+		require.Len(t, attempts, 3)
+		assert.GreaterOrEqual(t, attempts[1].Sub(attempts[0]), 20*time.Millisecond)
+		assert.GreaterOrEqual(t, attempts[2].Sub(attempts[1]), 40*time.Millisecond)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDriver := NewMockBucket(ctrl)
+		bucket := blob.NewBucketForTest(mockDriver)
+
+		var calls atomic.Int32
+
+		read := rangeReaderFor(data, nil)
+
+		mockDriver.EXPECT().
+			NewRangeReader(gomock.Any(), "key", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
+				if offset == 1000 && length > 0 {
+					calls.Add(1)
+					return nil, kerr.New(kerr.NotFound, "gone")
+				}
+
+				return read(ctx, key, offset, length, opts)
+			}).
+			AnyTimes()
+
+		w := &memWriterAt{buf: make([]byte, len(data))}
+		err := bucket.DownloadParallel(context.Background(), "key", w, &blob.DownloadOptions{PartSize: 1000, RetryBackoff: time.Millisecond})
+		assert.True(t, kerr.IsNotFound(err))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDriver := NewMockBucket(ctrl)
+		bucket := blob.NewBucketForTest(mockDriver)
+
+		read := rangeReaderFor(data, nil)
+
+		mockDriver.EXPECT().
+			NewRangeReader(gomock.Any(), "key", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
+				if offset == 2000 {
+					return nil, errTransient
+				}
+
+				return read(ctx, key, offset, length, opts)
+			}).
+			AnyTimes()
+
+		w := &memWriterAt{buf: make([]byte, len(data))}
+		err := bucket.DownloadParallel(context.Background(), "key", w, &blob.DownloadOptions{PartSize: 1000, MaxRetries: 1, RetryBackoff: time.Millisecond})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		bucket := blob.NewBucketForTest(NewMockBucket(ctrl))
+
+		assert.Error(t, bucket.DownloadParallel(context.Background(), "key", nil, nil))
+		assert.Error(t, bucket.DownloadParallel(context.Background(), "key", &memWriterAt{}, &blob.DownloadOptions{PartSize: -1}))
+	})
+}
@@ -41,9 +41,10 @@ type Bucket interface {
 
 	// NewRangeReader returns a Reader that reads part of an object, reading at
 	// most length bytes starting at the given offset. If length is negative, it
-	// will read until the end of the object. If the specified object does not
-	// exist, NewRangeReader must return an error for which ErrorCode returns
-	// gcerrors.NotFound.
+	// will read until the end of the object. If length is zero, only the
+	// attributes are fetched; the object content must not be transferred. If
+	// the specified object does not exist, NewRangeReader must return an
+	// error for which ErrorCode returns gcerrors.NotFound.
 	// opts is guaranteed to be non-nil.
 	//
 	// The returned Reader *may* also implement Downloader if the underlying
@@ -73,6 +74,234 @@ type Bucket interface {
 	NewTypedWriter(ctx context.Context, key, contentType string, opts *WriterOptions) (Writer, error)
 }
 
+// Snapshotter is an optional interface a Bucket may implement to support
+// point-in-time, read-only snapshots of objects.
+type Snapshotter interface {
+	// Snapshot creates a snapshot of the object associated with key and
+	// returns its opaque, driver-specific ID. If the object does not exist,
+	// Snapshot must return an error for which ErrorCode returns kerr.NotFound.
+	Snapshot(ctx context.Context, key string) (string, error)
+
+	// ListSnapshots returns the snapshots of the object associated with key,
+	// oldest first.
+	ListSnapshots(ctx context.Context, key string) ([]*SnapshotInfo, error)
+
+	// NewSnapshotReader returns a Reader for the full content of the given
+	// snapshot of key. opts is guaranteed to be non-nil.
+	NewSnapshotReader(ctx context.Context, key, snapshotID string, opts *ReaderOptions) (Reader, error)
+
+	// PromoteSnapshot restores the given snapshot of key by overwriting the
+	// current object with the snapshot content. The snapshot itself is kept.
+	PromoteSnapshot(ctx context.Context, key, snapshotID string) error
+}
+
+// Capability is a set of optional features of a driver.
+type Capability uint64
+
+// Capabilities that cannot be detected from the optional interfaces a Bucket
+// implements. Drivers report them through CapabilityReporter.
+const (
+	// CapSignedURL reports that SignedURL returns usable URLs instead of an
+	// Unimplemented error.
+	CapSignedURL Capability = 1 << iota
+	// CapIfNotExist reports that writers honor WriterOptions.IfNotExist.
+	CapIfNotExist
+)
+
+// Has reports whether all capabilities in c are set.
+func (caps Capability) Has(c Capability) bool {
+	return caps&c == c
+}
+
+// CapabilityReporter is an optional interface a Bucket may implement to
+// report capabilities of the base Bucket interface it supports. Optional
+// interfaces such as Snapshotter or Tagger are detected by type assertion
+// and need not be reported.
+type CapabilityReporter interface {
+	Capabilities() Capability
+}
+
+// SnapshotInfo describes a single snapshot of an object.
+type SnapshotInfo struct {
+	// ID is the opaque, driver-specific identifier of the snapshot.
+	ID string
+	// CreatedAt is the time the snapshot was taken.
+	CreatedAt time.Time
+	// Size is the size of the snapshot content in bytes.
+	Size int64
+}
+
+// Lister is an optional interface a Bucket may implement to support
+// listing the objects it contains.
+type Lister interface {
+	// ListPaged lists a single page of objects. opts is guaranteed to be
+	// non-nil. An empty NextPageToken in the returned page marks the last
+	// page.
+	ListPaged(ctx context.Context, opts *ListOptions) (*ListPage, error)
+}
+
+// ListOptions sets options for listing objects.
+type ListOptions struct {
+	// Prefix restricts the listing to keys starting with Prefix.
+	Prefix string
+	// PageSize is the maximum number of objects to return in a single page.
+	// A value <= 0 lets the driver choose.
+	PageSize int
+	// PageToken continues a previous listing. It is empty for the first page.
+	PageToken []byte
+	// Tags restricts the listing to objects carrying all of the given tags.
+	// It is only set for drivers that also implement Tagger. Drivers may
+	// report a zero Size and ModTime for objects of tag-filtered listings
+	// and may ignore Prefix and PageSize for them.
+	Tags map[string]string
+}
+
+// Tagger is an optional interface a Bucket may implement to support object
+// tags. Unlike metadata, tags can be changed without rewriting the object and
+// are indexed by the service, so they can be used to filter listings; see
+// ListOptions.Tags.
+type Tagger interface {
+	// SetTags replaces all tags of the object associated with key. An empty
+	// map removes all tags. If the object does not exist, SetTags must
+	// return an error for which ErrorCode returns kerr.NotFound.
+	SetTags(ctx context.Context, key string, tags map[string]string) error
+
+	// GetTags returns the tags of the object associated with key. If the
+	// object does not exist, GetTags must return an error for which
+	// ErrorCode returns kerr.NotFound.
+	GetTags(ctx context.Context, key string) (map[string]string, error)
+}
+
+// UploadFormSigner is an optional interface a Bucket may implement to let
+// browsers upload objects directly to the service.
+type UploadFormSigner interface {
+	// SignedUploadForm returns the request a user agent must send to upload
+	// the object associated with key. opts is guaranteed to be non-nil.
+	SignedUploadForm(ctx context.Context, key string, opts *UploadFormOptions) (*UploadForm, error)
+}
+
+// UploadFormOptions sets options for SignedUploadForm.
+type UploadFormOptions struct {
+	// Expiry sets how long the upload form is valid for. It is guaranteed to
+	// be > 0.
+	Expiry time.Duration
+	// MaxSize is the maximum size of the upload in bytes; zero means no limit.
+	MaxSize int64
+	// ContentTypes lists the permitted Content-Type values of the upload;
+	// empty means any.
+	ContentTypes []string
+	// BeforeSign is a callback that will be called before the form is signed.
+	// asFunc converts its argument to driver-specific types.
+	BeforeSign func(asFunc func(any) bool) error
+}
+
+// UploadForm describes a signed upload request.
+type UploadForm struct {
+	// URL is the target of the upload request.
+	URL string
+	// Method is the HTTP method of the upload request, "POST" for multipart
+	// form uploads or "PUT" for raw uploads.
+	Method string
+	// Fields holds the form fields that must precede the file part of a
+	// multipart POST upload.
+	Fields map[string]string
+	// Headers holds the headers the upload request must carry.
+	Headers map[string]string
+	// ExpiresAt is the time the form stops being accepted.
+	ExpiresAt time.Time
+	// EnforcesMaxSize reports whether the service rejects uploads larger than
+	// UploadFormOptions.MaxSize.
+	EnforcesMaxSize bool
+	// EnforcesContentType reports whether the service rejects uploads with a
+	// Content-Type not listed in UploadFormOptions.ContentTypes.
+	EnforcesContentType bool
+}
+
+// Appender is an optional interface a Bucket may implement to support append
+// objects, such as Azure append blobs, that are extended in place by
+// committing blocks at their end. Drivers without it are emulated by
+// rewriting the object.
+type Appender interface {
+	// OpenAppend creates an empty append object at key with the given
+	// content type unless it exists, and returns its current state. If an
+	// object that is not an append object exists at key, OpenAppend must
+	// return an error for which ErrorCode returns kerr.FailedPrecondition.
+	// opts is guaranteed to be non-nil.
+	OpenAppend(ctx context.Context, key, contentType string, opts *AppendOptions) (*AppendInfo, error)
+
+	// AppendBlock commits p as a single block at the end of the append
+	// object at key. offset is the size of the object the block is expected
+	// to start at; drivers should reject the block with an error for which
+	// ErrorCode returns kerr.FailedPrecondition if the object has a
+	// different size, e.g. because another writer appended to it.
+	AppendBlock(ctx context.Context, key string, offset int64, p []byte) error
+}
+
+// AppendOptions sets options for OpenAppend.
+type AppendOptions struct {
+	// Metadata holds key/value strings to be associated with a newly
+	// created object. Keys are guaranteed to be non-empty and lowercased.
+	Metadata map[string]string
+}
+
+// AppendInfo describes an append object and the limits of the service.
+type AppendInfo struct {
+	// Size is the current size of the object in bytes.
+	Size int64
+	// Blocks is the number of blocks committed to the object.
+	Blocks int
+	// MaxBlockSize is the maximum size of a single block in bytes.
+	MaxBlockSize int
+	// MaxBlocks is the maximum number of blocks of an object.
+	MaxBlocks int
+	// MaxSize is the maximum size of an object in bytes.
+	MaxSize int64
+}
+
+// EncryptionReporter is an optional interface a Bucket may implement to
+// report how the service encrypts objects at rest.
+type EncryptionReporter interface {
+	// EncryptionInfo returns the encryption settings the service reports for
+	// the container of the bucket.
+	EncryptionInfo(ctx context.Context) (*EncryptionInfo, error)
+}
+
+// EncryptionInfo describes the encryption at rest of a container.
+type EncryptionInfo struct {
+	// Encrypted reports whether the service encrypts every object at rest.
+	Encrypted bool
+	// Algorithm is the encryption algorithm, e.g. "AES256".
+	Algorithm string
+	// Scope is the default encryption scope, or key, of the container. It is
+	// empty if the service has no such notion.
+	Scope string
+	// ScopeEnforced reports whether writes are prevented from selecting a
+	// scope other than Scope.
+	ScopeEnforced bool
+	// KeySource describes where the key comes from, in the terms of the
+	// service.
+	KeySource string
+}
+
+// ListObject describes a single object returned by ListPaged.
+type ListObject struct {
+	// Key is the key of the object.
+	Key string
+	// Size is the size of the object content in bytes.
+	Size int64
+	// ModTime is the time the object was last modified.
+	ModTime time.Time
+}
+
+// ListPage is a single page of objects returned by ListPaged.
+type ListPage struct {
+	// Objects holds the objects of this page.
+	Objects []*ListObject
+	// NextPageToken is passed as ListOptions.PageToken to fetch the next
+	// page. It is empty when there are no more pages.
+	NextPageToken []byte
+}
+
 // SignedURLOptions sets options for SignedURL.
 type SignedURLOptions struct {
 	// Expiry sets how long the returned URL is valid for. It is guaranteed to be > 0.
@@ -135,6 +364,9 @@ type ReaderAttributes struct {
 	ModTime time.Time
 	// Size is the size of the object in bytes.
 	Size int64
+	// ContentMD5 is the MD5 digest of the whole object, if the service
+	// provides one. It may be nil.
+	ContentMD5 []byte
 }
 
 // Downloader has an optional extra method for readers.
@@ -3,9 +3,53 @@
 
 package driver
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+
+	kerr "github.com/kopexa-grc/common/errors"
+)
 
 var (
 	ErrUnsupportedMethod = errors.New("unsupported method")
 	ErrCopyFailed        = errors.New("copy failed")
 )
+
+// ErrorCoder is an optional interface a Bucket may implement to classify the
+// provider errors it returns, e.g. an Azure response with status 404 as
+// kerr.NotFound. The blob package uses it to return *kerr.Error values with
+// consistent codes for all drivers.
+type ErrorCoder interface {
+	// ErrorCode returns the code of err, or "" if err is not a provider
+	// error known to the driver.
+	ErrorCode(err error) kerr.ErrorCode
+}
+
+// CodeFromHTTPStatus returns the error code for the HTTP status of a provider
+// response. Drivers of HTTP-based services use it to implement ErrorCoder.
+func CodeFromHTTPStatus(status int) kerr.ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return kerr.InvalidArgument
+	case http.StatusUnauthorized:
+		return kerr.Unauthorized
+	case http.StatusForbidden:
+		return kerr.Forbidden
+	case http.StatusNotFound:
+		return kerr.NotFound
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return kerr.FailedPrecondition
+	case http.StatusRequestedRangeNotSatisfiable:
+		return kerr.OutOfRange
+	case http.StatusTooManyRequests:
+		return kerr.TooManyRequests
+	case http.StatusNotImplemented:
+		return kerr.NotImplemented
+	case http.StatusServiceUnavailable:
+		return kerr.ServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return kerr.GatewayTimeout
+	default:
+		return kerr.UnexpectedFailure
+	}
+}
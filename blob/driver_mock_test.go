@@ -115,6 +115,350 @@ func (mr *MockBucketMockRecorder) SignedURL(ctx, key, opts any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignedURL", reflect.TypeOf((*MockBucket)(nil).SignedURL), ctx, key, opts)
 }
 
+// MockSnapshotter is a mock of Snapshotter interface.
+type MockSnapshotter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotterMockRecorder
+	isgomock struct{}
+}
+
+// MockSnapshotterMockRecorder is the mock recorder for MockSnapshotter.
+type MockSnapshotterMockRecorder struct {
+	mock *MockSnapshotter
+}
+
+// NewMockSnapshotter creates a new mock instance.
+func NewMockSnapshotter(ctrl *gomock.Controller) *MockSnapshotter {
+	mock := &MockSnapshotter{ctrl: ctrl}
+	mock.recorder = &MockSnapshotterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotter) EXPECT() *MockSnapshotterMockRecorder {
+	return m.recorder
+}
+
+// ListSnapshots mocks base method.
+func (m *MockSnapshotter) ListSnapshots(ctx context.Context, key string) ([]*driver.SnapshotInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSnapshots", ctx, key)
+	ret0, _ := ret[0].([]*driver.SnapshotInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSnapshots indicates an expected call of ListSnapshots.
+func (mr *MockSnapshotterMockRecorder) ListSnapshots(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSnapshots", reflect.TypeOf((*MockSnapshotter)(nil).ListSnapshots), ctx, key)
+}
+
+// NewSnapshotReader mocks base method.
+func (m *MockSnapshotter) NewSnapshotReader(ctx context.Context, key, snapshotID string, opts *driver.ReaderOptions) (driver.Reader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewSnapshotReader", ctx, key, snapshotID, opts)
+	ret0, _ := ret[0].(driver.Reader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewSnapshotReader indicates an expected call of NewSnapshotReader.
+func (mr *MockSnapshotterMockRecorder) NewSnapshotReader(ctx, key, snapshotID, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSnapshotReader", reflect.TypeOf((*MockSnapshotter)(nil).NewSnapshotReader), ctx, key, snapshotID, opts)
+}
+
+// PromoteSnapshot mocks base method.
+func (m *MockSnapshotter) PromoteSnapshot(ctx context.Context, key, snapshotID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoteSnapshot", ctx, key, snapshotID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PromoteSnapshot indicates an expected call of PromoteSnapshot.
+func (mr *MockSnapshotterMockRecorder) PromoteSnapshot(ctx, key, snapshotID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteSnapshot", reflect.TypeOf((*MockSnapshotter)(nil).PromoteSnapshot), ctx, key, snapshotID)
+}
+
+// Snapshot mocks base method.
+func (m *MockSnapshotter) Snapshot(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockSnapshotterMockRecorder) Snapshot(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockSnapshotter)(nil).Snapshot), ctx, key)
+}
+
+// MockCapabilityReporter is a mock of CapabilityReporter interface.
+type MockCapabilityReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCapabilityReporterMockRecorder
+	isgomock struct{}
+}
+
+// MockCapabilityReporterMockRecorder is the mock recorder for MockCapabilityReporter.
+type MockCapabilityReporterMockRecorder struct {
+	mock *MockCapabilityReporter
+}
+
+// NewMockCapabilityReporter creates a new mock instance.
+func NewMockCapabilityReporter(ctrl *gomock.Controller) *MockCapabilityReporter {
+	mock := &MockCapabilityReporter{ctrl: ctrl}
+	mock.recorder = &MockCapabilityReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCapabilityReporter) EXPECT() *MockCapabilityReporterMockRecorder {
+	return m.recorder
+}
+
+// Capabilities mocks base method.
+func (m *MockCapabilityReporter) Capabilities() driver.Capability {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capabilities")
+	ret0, _ := ret[0].(driver.Capability)
+	return ret0
+}
+
+// Capabilities indicates an expected call of Capabilities.
+func (mr *MockCapabilityReporterMockRecorder) Capabilities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capabilities", reflect.TypeOf((*MockCapabilityReporter)(nil).Capabilities))
+}
+
+// MockLister is a mock of Lister interface.
+type MockLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockListerMockRecorder
+	isgomock struct{}
+}
+
+// MockListerMockRecorder is the mock recorder for MockLister.
+type MockListerMockRecorder struct {
+	mock *MockLister
+}
+
+// NewMockLister creates a new mock instance.
+func NewMockLister(ctrl *gomock.Controller) *MockLister {
+	mock := &MockLister{ctrl: ctrl}
+	mock.recorder = &MockListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLister) EXPECT() *MockListerMockRecorder {
+	return m.recorder
+}
+
+// ListPaged mocks base method.
+func (m *MockLister) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaged", ctx, opts)
+	ret0, _ := ret[0].(*driver.ListPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaged indicates an expected call of ListPaged.
+func (mr *MockListerMockRecorder) ListPaged(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaged", reflect.TypeOf((*MockLister)(nil).ListPaged), ctx, opts)
+}
+
+// MockTagger is a mock of Tagger interface.
+type MockTagger struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaggerMockRecorder
+	isgomock struct{}
+}
+
+// MockTaggerMockRecorder is the mock recorder for MockTagger.
+type MockTaggerMockRecorder struct {
+	mock *MockTagger
+}
+
+// NewMockTagger creates a new mock instance.
+func NewMockTagger(ctrl *gomock.Controller) *MockTagger {
+	mock := &MockTagger{ctrl: ctrl}
+	mock.recorder = &MockTaggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagger) EXPECT() *MockTaggerMockRecorder {
+	return m.recorder
+}
+
+// GetTags mocks base method.
+func (m *MockTagger) GetTags(ctx context.Context, key string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, key)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockTaggerMockRecorder) GetTags(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockTagger)(nil).GetTags), ctx, key)
+}
+
+// SetTags mocks base method.
+func (m *MockTagger) SetTags(ctx context.Context, key string, tags map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTags", ctx, key, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTags indicates an expected call of SetTags.
+func (mr *MockTaggerMockRecorder) SetTags(ctx, key, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTags", reflect.TypeOf((*MockTagger)(nil).SetTags), ctx, key, tags)
+}
+
+// MockUploadFormSigner is a mock of UploadFormSigner interface.
+type MockUploadFormSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadFormSignerMockRecorder
+	isgomock struct{}
+}
+
+// MockUploadFormSignerMockRecorder is the mock recorder for MockUploadFormSigner.
+type MockUploadFormSignerMockRecorder struct {
+	mock *MockUploadFormSigner
+}
+
+// NewMockUploadFormSigner creates a new mock instance.
+func NewMockUploadFormSigner(ctrl *gomock.Controller) *MockUploadFormSigner {
+	mock := &MockUploadFormSigner{ctrl: ctrl}
+	mock.recorder = &MockUploadFormSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUploadFormSigner) EXPECT() *MockUploadFormSignerMockRecorder {
+	return m.recorder
+}
+
+// SignedUploadForm mocks base method.
+func (m *MockUploadFormSigner) SignedUploadForm(ctx context.Context, key string, opts *driver.UploadFormOptions) (*driver.UploadForm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignedUploadForm", ctx, key, opts)
+	ret0, _ := ret[0].(*driver.UploadForm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignedUploadForm indicates an expected call of SignedUploadForm.
+func (mr *MockUploadFormSignerMockRecorder) SignedUploadForm(ctx, key, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignedUploadForm", reflect.TypeOf((*MockUploadFormSigner)(nil).SignedUploadForm), ctx, key, opts)
+}
+
+// MockAppender is a mock of Appender interface.
+type MockAppender struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppenderMockRecorder
+	isgomock struct{}
+}
+
+// MockAppenderMockRecorder is the mock recorder for MockAppender.
+type MockAppenderMockRecorder struct {
+	mock *MockAppender
+}
+
+// NewMockAppender creates a new mock instance.
+func NewMockAppender(ctrl *gomock.Controller) *MockAppender {
+	mock := &MockAppender{ctrl: ctrl}
+	mock.recorder = &MockAppenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppender) EXPECT() *MockAppenderMockRecorder {
+	return m.recorder
+}
+
+// AppendBlock mocks base method.
+func (m *MockAppender) AppendBlock(ctx context.Context, key string, offset int64, p []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendBlock", ctx, key, offset, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendBlock indicates an expected call of AppendBlock.
+func (mr *MockAppenderMockRecorder) AppendBlock(ctx, key, offset, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendBlock", reflect.TypeOf((*MockAppender)(nil).AppendBlock), ctx, key, offset, p)
+}
+
+// OpenAppend mocks base method.
+func (m *MockAppender) OpenAppend(ctx context.Context, key, contentType string, opts *driver.AppendOptions) (*driver.AppendInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenAppend", ctx, key, contentType, opts)
+	ret0, _ := ret[0].(*driver.AppendInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenAppend indicates an expected call of OpenAppend.
+func (mr *MockAppenderMockRecorder) OpenAppend(ctx, key, contentType, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenAppend", reflect.TypeOf((*MockAppender)(nil).OpenAppend), ctx, key, contentType, opts)
+}
+
+// MockEncryptionReporter is a mock of EncryptionReporter interface.
+type MockEncryptionReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockEncryptionReporterMockRecorder
+	isgomock struct{}
+}
+
+// MockEncryptionReporterMockRecorder is the mock recorder for MockEncryptionReporter.
+type MockEncryptionReporterMockRecorder struct {
+	mock *MockEncryptionReporter
+}
+
+// NewMockEncryptionReporter creates a new mock instance.
+func NewMockEncryptionReporter(ctrl *gomock.Controller) *MockEncryptionReporter {
+	mock := &MockEncryptionReporter{ctrl: ctrl}
+	mock.recorder = &MockEncryptionReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEncryptionReporter) EXPECT() *MockEncryptionReporterMockRecorder {
+	return m.recorder
+}
+
+// EncryptionInfo mocks base method.
+func (m *MockEncryptionReporter) EncryptionInfo(ctx context.Context) (*driver.EncryptionInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptionInfo", ctx)
+	ret0, _ := ret[0].(*driver.EncryptionInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptionInfo indicates an expected call of EncryptionInfo.
+func (mr *MockEncryptionReporterMockRecorder) EncryptionInfo(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptionInfo", reflect.TypeOf((*MockEncryptionReporter)(nil).EncryptionInfo), ctx)
+}
+
 // MockReader is a mock of Reader interface.
 type MockReader struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,157 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// EncryptionInfo describes how the service encrypts the objects of a bucket
+// at rest, as reported by the service.
+type EncryptionInfo struct {
+	// Encrypted reports whether every object is encrypted at rest.
+	Encrypted bool `json:"encrypted"`
+	// Algorithm is the encryption algorithm, e.g. "AES256".
+	Algorithm string `json:"algorithm,omitempty"`
+	// Scope is the default encryption scope, or key, of the bucket, e.g. the
+	// name of an Azure encryption scope.
+	Scope string `json:"scope,omitempty"`
+	// ScopeEnforced reports whether writes are prevented from selecting
+	// another scope.
+	ScopeEnforced bool `json:"scopeEnforced"`
+	// KeySource describes where the key comes from, in the terms of the
+	// driver; see e.g. azurestore.KeySourceAccount.
+	KeySource string `json:"keySource,omitempty"`
+}
+
+// EncryptionInfo returns the encryption at rest the service reports for the
+// bucket, so that audits can show which key protects which container.
+//
+// If the driver does not report encryption, EncryptionInfo returns an error
+// for which kerr.Code will return kerr.NotImplemented.
+func (b *Bucket) EncryptionInfo(ctx context.Context) (*EncryptionInfo, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, errClosed
+	}
+
+	r, ok := b.b.(driver.EncryptionReporter)
+	if !ok {
+		return nil, kerr.Newf(kerr.NotImplemented, nil, "blob: EncryptionInfo is not supported by this driver")
+	}
+
+	info, err := r.EncryptionInfo(ctx)
+	if err != nil {
+		return nil, wrapError(b.b, err, "")
+	}
+
+	return &EncryptionInfo{
+		Encrypted:     info.Encrypted,
+		Algorithm:     info.Algorithm,
+		Scope:         info.Scope,
+		ScopeEnforced: info.ScopeEnforced,
+		KeySource:     info.KeySource,
+	}, nil
+}
+
+// ComplianceReport summarizes the encryption at rest of a set of buckets.
+type ComplianceReport struct {
+	// GeneratedAt is the time the report was created.
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Compliant reports whether every bucket is encrypted at rest.
+	Compliant bool `json:"compliant"`
+	// Buckets holds one entry per bucket, in the order they were checked.
+	Buckets []BucketCompliance `json:"buckets"`
+}
+
+// BucketCompliance is the entry of a single bucket in a ComplianceReport.
+type BucketCompliance struct {
+	// Container is the name of the container backing the bucket.
+	Container string `json:"container"`
+	// Encryption is the reported encryption; nil if Error is set.
+	Encryption *EncryptionInfo `json:"encryption,omitempty"`
+	// Error describes why the encryption could not be determined.
+	Error string `json:"error,omitempty"`
+}
+
+// ComplianceReport reports the encryption at rest of the public bucket and
+// the buckets of the given spaces. The provider does not keep track of
+// spaces, so callers pass the IDs of all spaces the report must cover.
+//
+// Buckets whose encryption cannot be determined are listed with an error
+// and make the report non-compliant; ComplianceReport itself only fails for
+// invalid arguments.
+//
+// Example:
+//
+//	report, err := provider.ComplianceReport(ctx, spaceIDs...)
+//	if err != nil {
+//		return err
+//	}
+//
+//	if !report.Compliant {
+//		// alert
+//	}
+func (p *BucketProvider) ComplianceReport(ctx context.Context, spaceIDs ...string) (*ComplianceReport, error) {
+	containers := []string{PublicContainer}
+	buckets := make([]*Bucket, 0, len(spaceIDs)+1)
+
+	public, err := p.Public()
+	if err != nil {
+		return nil, err
+	}
+
+	buckets = append(buckets, public)
+
+	for _, spaceID := range spaceIDs {
+		bucket, err := p.Space(spaceID)
+		if err != nil {
+			return nil, err
+		}
+
+		containers = append(containers, spaceContainer(spaceID))
+		buckets = append(buckets, bucket)
+	}
+
+	return newComplianceReport(ctx, containers, buckets), nil
+}
+
+// newComplianceReport checks the encryption of buckets, which are backed by
+// the given containers.
+func newComplianceReport(ctx context.Context, containers []string, buckets []*Bucket) *ComplianceReport {
+	report := &ComplianceReport{
+		GeneratedAt: time.Now().UTC(),
+		Compliant:   true,
+		Buckets:     make([]BucketCompliance, 0, len(buckets)),
+	}
+
+	for i, bucket := range buckets {
+		entry := BucketCompliance{Container: containers[i]}
+
+		info, err := bucket.EncryptionInfo(ctx)
+		if err != nil {
+			entry.Error = err.Error()
+			if cause := errors.Unwrap(err); cause != nil {
+				entry.Error += ": " + cause.Error()
+			}
+		} else {
+			entry.Encryption = info
+		}
+
+		if entry.Encryption == nil || !entry.Encryption.Encrypted {
+			report.Compliant = false
+		}
+
+		report.Buckets = append(report.Buckets, entry)
+	}
+
+	return report
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// encryptionDriver is a driver.Bucket that also implements
+// driver.EncryptionReporter.
+type encryptionDriver struct {
+	*MockBucket
+	*MockEncryptionReporter
+}
+
+func TestBucket_EncryptionInfo(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	reporter := NewMockEncryptionReporter(ctrl)
+	bucket := blob.NewBucketForTest(&encryptionDriver{MockBucket: NewMockBucket(ctrl), MockEncryptionReporter: reporter})
+
+	reporter.EXPECT().EncryptionInfo(ctx).Return(&driver.EncryptionInfo{
+		Encrypted:     true,
+		Algorithm:     "AES256",
+		Scope:         "grc-cmk",
+		ScopeEnforced: true,
+		KeySource:     "encryption-scope",
+	}, nil)
+
+	info, err := bucket.EncryptionInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &blob.EncryptionInfo{
+		Encrypted:     true,
+		Algorithm:     "AES256",
+		Scope:         "grc-cmk",
+		ScopeEnforced: true,
+		KeySource:     "encryption-scope",
+	}, info)
+	assert.True(t, bucket.Capabilities().Encryption)
+}
+
+func TestBucket_EncryptionInfoNotImplemented(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bucket := blob.NewBucketForTest(NewMockBucket(ctrl))
+
+	_, err := bucket.EncryptionInfo(context.Background())
+	assert.True(t, kerr.Is(err, kerr.NotImplemented))
+}
+
+func TestComplianceReport(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	encrypted := NewMockEncryptionReporter(ctrl)
+	encrypted.EXPECT().EncryptionInfo(ctx).Return(&driver.EncryptionInfo{Encrypted: true, Algorithm: "AES256"}, nil).Times(2)
+
+	failing := NewMockEncryptionReporter(ctrl)
+	failing.EXPECT().EncryptionInfo(ctx).Return(nil, errors.New("forbidden"))
+
+	base := NewMockBucket(ctrl)
+
+	public := blob.NewBucketForTest(&encryptionDriver{MockBucket: base, MockEncryptionReporter: encrypted})
+	space := blob.NewBucketForTest(&encryptionDriver{MockBucket: base, MockEncryptionReporter: encrypted})
+
+	report := blob.NewComplianceReportForTest(ctx, []string{"public", "space-1"}, []*blob.Bucket{public, space})
+	assert.True(t, report.Compliant)
+	require.Len(t, report.Buckets, 2)
+	assert.Equal(t, "space-1", report.Buckets[1].Container)
+	assert.Equal(t, "AES256", report.Buckets[1].Encryption.Algorithm)
+	assert.False(t, report.GeneratedAt.IsZero())
+
+	unsupported := blob.NewBucketForTest(NewMockBucket(ctrl))
+	broken := blob.NewBucketForTest(&encryptionDriver{MockBucket: base, MockEncryptionReporter: failing})
+
+	report = blob.NewComplianceReportForTest(ctx, []string{"space-2", "space-3"}, []*blob.Bucket{unsupported, broken})
+	assert.False(t, report.Compliant)
+	assert.Nil(t, report.Buckets[0].Encryption)
+	assert.Contains(t, report.Buckets[0].Error, "not supported")
+	assert.Contains(t, report.Buckets[1].Error, "forbidden")
+}
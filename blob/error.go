@@ -4,15 +4,39 @@
 package blob
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 
 	"github.com/kopexa-grc/common/blob/driver"
 	kerr "github.com/kopexa-grc/common/errors"
 )
 
-func wrapError(_ driver.Bucket, err error, key string) error {
-	if err == nil {
-		return nil
+// codeStatus is the HTTP status of errors returned by the blob package.
+var codeStatus = map[kerr.ErrorCode]int{
+	kerr.InvalidArgument:    http.StatusBadRequest,
+	kerr.Unauthorized:       http.StatusUnauthorized,
+	kerr.Forbidden:          http.StatusForbidden,
+	kerr.NotFound:           http.StatusNotFound,
+	kerr.FailedPrecondition: http.StatusPreconditionFailed,
+	kerr.OutOfRange:         http.StatusRequestedRangeNotSatisfiable,
+	kerr.TooManyRequests:    http.StatusTooManyRequests,
+	kerr.QuotaExceeded:      http.StatusInsufficientStorage,
+	kerr.NotImplemented:     http.StatusNotImplemented,
+	kerr.ServiceUnavailable: http.StatusServiceUnavailable,
+	kerr.GatewayTimeout:     http.StatusGatewayTimeout,
+	kerr.DeadlineExceeded:   http.StatusGatewayTimeout,
+}
+
+// wrapError converts a driver error into a *kerr.Error whose code is
+// determined by errorCode. Code and status of *kerr.Error values returned by
+// the driver are kept. The driver error is kept as the cause. io.EOF is
+// returned unchanged, as readers must report it as is.
+func wrapError(b driver.Bucket, err error, key string) error {
+	if err == nil || err == io.EOF { //nolint:errorlint // io.EOF must not be wrapped
+		return err
 	}
 
 	msg := "blob"
@@ -20,7 +44,48 @@ func wrapError(_ driver.Bucket, err error, key string) error {
 		msg += fmt.Sprintf(" (key %q)", key)
 	}
 
-	code := kerr.Code(err)
+	code := errorCode(b, err)
+
+	status, ok := codeStatus[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	var e *kerr.Error
+	if errors.As(err, &e) && e.Status != 0 {
+		status = e.Status
+	}
+
+	return kerr.New(code, msg).WithStatus(status).With(err)
+}
 
-	return kerr.New(code, msg)
+// errorCode classifies err. Codes of *kerr.Error values are kept, provider
+// errors are classified by the driver if it implements driver.ErrorCoder, or
+// by their HTTP status if they have an HTTPStatusCode method, as the errors
+// of the AWS SDK do.
+func errorCode(b driver.Bucket, err error) kerr.ErrorCode {
+	var e *kerr.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	if c, ok := b.(driver.ErrorCoder); ok {
+		if code := c.ErrorCode(err); code != "" {
+			return code
+		}
+	}
+
+	var sc interface{ HTTPStatusCode() int }
+	if errors.As(err, &sc) {
+		return driver.CodeFromHTTPStatus(sc.HTTPStatusCode())
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return kerr.DeadlineExceeded
+	case errors.Is(err, driver.ErrUnsupportedMethod):
+		return kerr.NotImplemented
+	default:
+		return kerr.UnexpectedFailure
+	}
 }
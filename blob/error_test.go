@@ -0,0 +1,120 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// providerError is a provider error classified by errorCoderDriver.
+type providerError struct{ status int }
+
+func (e *providerError) Error() string { return fmt.Sprintf("provider error %d", e.status) }
+
+// httpStatusError mimics the errors of the AWS SDK.
+type httpStatusError struct{ status int }
+
+func (e *httpStatusError) Error() string       { return fmt.Sprintf("http error %d", e.status) }
+func (e *httpStatusError) HTTPStatusCode() int { return e.status }
+
+// errorCoderDriver is a driver.Bucket that classifies providerError.
+type errorCoderDriver struct {
+	*MockBucket
+}
+
+func (errorCoderDriver) ErrorCode(err error) kerr.ErrorCode {
+	var pe *providerError
+	if errors.As(err, &pe) {
+		return driver.CodeFromHTTPStatus(pe.status)
+	}
+
+	return ""
+}
+
+// TestBucket_ErrorConformance checks that driver errors are returned as
+// *kerr.Error with the same code, whichever way the driver reports them.
+func TestBucket_ErrorConformance(t *testing.T) {
+	statuses := []struct {
+		status int
+		code   kerr.ErrorCode
+	}{
+		{http.StatusNotFound, kerr.NotFound},
+		{http.StatusConflict, kerr.FailedPrecondition},
+		{http.StatusPreconditionFailed, kerr.FailedPrecondition},
+		{http.StatusForbidden, kerr.Forbidden},
+		{http.StatusTooManyRequests, kerr.TooManyRequests},
+		{http.StatusBadGateway, kerr.UnexpectedFailure},
+	}
+
+	drivers := map[string]func(status int) error{
+		"error coder": func(status int) error { return &providerError{status: status} },
+		"http status": func(status int) error { return &httpStatusError{status: status} },
+	}
+
+	for name, newErr := range drivers {
+		for _, tt := range statuses {
+			t.Run(fmt.Sprintf("%s/%d", name, tt.status), func(t *testing.T) {
+				ctx := context.Background()
+				ctrl := gomock.NewController(t)
+				mock := NewMockBucket(ctrl)
+				bucket := blob.NewBucketForTest(errorCoderDriver{MockBucket: mock})
+
+				driverErr := fmt.Errorf("wrapped: %w", newErr(tt.status))
+				mock.EXPECT().Delete(ctx, "key").Return(driverErr)
+				mock.EXPECT().Copy(ctx, "dst", "src", gomock.Any()).Return(driverErr)
+				mock.EXPECT().NewRangeReader(ctx, "key", int64(0), int64(-1), gomock.Any()).Return(nil, driverErr)
+
+				errs := []error{bucket.Delete(ctx, "key"), bucket.Copy(ctx, "dst", "src", nil)}
+
+				_, err := bucket.NewRangeReader(ctx, "key", 0, -1, nil)
+				errs = append(errs, err)
+
+				for _, err := range errs {
+					var e *kerr.Error
+					require.ErrorAs(t, err, &e)
+					assert.Equal(t, tt.code, e.Code)
+					assert.ErrorIs(t, err, driverErr)
+				}
+			})
+		}
+	}
+}
+
+func TestBucket_ErrorKeepsCode(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mock := NewMockBucket(ctrl)
+	bucket := blob.NewBucketForTest(mock)
+
+	mock.EXPECT().Delete(ctx, "key").Return(kerr.NewConflict("locked"))
+
+	err := bucket.Delete(ctx, "key")
+	assert.True(t, kerr.Is(err, kerr.Conflict))
+	assert.Equal(t, http.StatusConflict, kerr.Status(err))
+}
+
+func TestBucket_ErrorContext(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mock := NewMockBucket(ctrl)
+	bucket := blob.NewBucketForTest(mock)
+
+	mock.EXPECT().Delete(ctx, "key").Return(fmt.Errorf("delete: %w", context.DeadlineExceeded))
+
+	err := bucket.Delete(ctx, "key")
+	assert.True(t, kerr.Is(err, kerr.DeadlineExceeded))
+	assert.Equal(t, http.StatusGatewayTimeout, kerr.Status(err))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
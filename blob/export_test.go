@@ -0,0 +1,24 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import "context"
+
+// SetCDNForTest sets the CDN configuration of a bucket created with
+// NewBucketForTest.
+func SetCDNForTest(b *Bucket, cdn *CDNConfig) {
+	b.cdn = cdn
+}
+
+// SetQuotaForTest sets the quota enforcer of a bucket created with
+// NewBucketForTest.
+func SetQuotaForTest(b *Bucket, q *QuotaEnforcer) {
+	b.quota = q
+}
+
+// NewComplianceReportForTest checks the encryption of buckets backed by the
+// given containers.
+func NewComplianceReportForTest(ctx context.Context, containers []string, buckets []*Bucket) *ComplianceReport {
+	return newComplianceReport(ctx, containers, buckets)
+}
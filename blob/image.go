@@ -0,0 +1,433 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is used for Content-MD5 validation as per RFC 1864
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"regexp"
+	"strings"
+
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// ImageFormat is an image encoding supported by ImagePipeline.
+type ImageFormat string
+
+const (
+	// ImageFormatJPEG encodes images as JPEG.
+	ImageFormatJPEG ImageFormat = "jpeg"
+	// ImageFormatPNG encodes images as PNG.
+	ImageFormatPNG ImageFormat = "png"
+	// ImageFormatGIF encodes images as GIF. Animations are reduced to their
+	// first frame when re-encoded.
+	ImageFormatGIF ImageFormat = "gif"
+)
+
+// Defaults applied by ImagePipeline.
+const (
+	// DefaultImageMaxInputBytes is the default limit for the size of an image
+	// written through an ImagePipeline, which has to be buffered in memory.
+	DefaultImageMaxInputBytes = 32 << 20
+	// DefaultImageMaxPixels is the default limit for the number of pixels of
+	// an image, guarding against decompression bombs.
+	DefaultImageMaxPixels = 40_000_000
+	// DefaultJPEGQuality is the default quality for JPEG encoding.
+	DefaultJPEGQuality = 85
+)
+
+// variantNamePattern restricts variant names, which become part of keys.
+var variantNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ContentType returns the MIME type of the format.
+func (f ImageFormat) ContentType() string {
+	return "image/" + string(f)
+}
+
+// Extension returns the file extension of the format including the dot.
+func (f ImageFormat) Extension() string {
+	if f == ImageFormatJPEG {
+		return ".jpg"
+	}
+
+	return "." + string(f)
+}
+
+func (f ImageFormat) valid() bool {
+	switch f {
+	case ImageFormatJPEG, ImageFormatPNG, ImageFormatGIF:
+		return true
+	}
+
+	return false
+}
+
+// ImageVariant describes a derived image, e.g. a thumbnail, written next to
+// the original.
+type ImageVariant struct {
+	// Name identifies the variant and is part of its key (see
+	// ImagePipeline.DerivedKey). It must consist of lowercase letters,
+	// digits, "-" and "_".
+	Name string
+
+	// Width and Height bound the size of the variant. The aspect ratio is
+	// kept and images are never enlarged. Zero leaves a dimension unbounded,
+	// but at least one must be set.
+	Width  int
+	Height int
+
+	// Format of the variant; defaults to the output format of the pipeline.
+	Format ImageFormat
+}
+
+// ImagePipeline processes images written through a Writer before they reach
+// the driver. The whole image is buffered in memory and processed on Close:
+// variants are written to their derived keys first, then the (optionally
+// resized, converted and stripped) image is written to the key of the Writer.
+//
+// Re-encoding an image always drops its metadata. If the image is not
+// re-encoded, StripMetadata removes metadata without touching the pixel data.
+type ImagePipeline struct {
+	// Format converts the image; defaults to the format of the input.
+	Format ImageFormat
+
+	// MaxWidth and MaxHeight downscale the image itself if it is larger.
+	// Zero leaves a dimension unbounded.
+	MaxWidth  int
+	MaxHeight int
+
+	// StripMetadata removes EXIF, XMP, IPTC and comments, e.g. GPS positions
+	// of photos. The EXIF orientation is applied to the pixels first. ICC
+	// color profiles are kept. GIF images are written unchanged.
+	StripMetadata bool
+
+	// Variants are derived images written next to the original.
+	Variants []ImageVariant
+
+	// JPEGQuality is the quality (1-100) for JPEG encoding; defaults to
+	// DefaultJPEGQuality.
+	JPEGQuality int
+
+	// MaxInputBytes limits the size of the written image; defaults to
+	// DefaultImageMaxInputBytes.
+	MaxInputBytes int
+
+	// MaxPixels limits the number of pixels of the image; defaults to
+	// DefaultImageMaxPixels.
+	MaxPixels int
+
+	// VariantKey overrides the key a variant is written to.
+	// See DerivedKey for the default.
+	VariantKey func(key string, variant ImageVariant, format ImageFormat) string
+}
+
+// validate checks the pipeline configuration.
+func (p *ImagePipeline) validate() error {
+	if p.Format != "" && !p.Format.valid() {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: unsupported ImagePipeline.Format %q", p.Format)
+	}
+
+	if p.MaxWidth < 0 || p.MaxHeight < 0 || p.MaxInputBytes < 0 || p.MaxPixels < 0 {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: ImagePipeline limits must not be negative")
+	}
+
+	if p.JPEGQuality < 0 || p.JPEGQuality > 100 {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: ImagePipeline.JPEGQuality must be between 1 and 100")
+	}
+
+	seen := make(map[string]struct{}, len(p.Variants))
+
+	for _, v := range p.Variants {
+		if !variantNamePattern.MatchString(v.Name) {
+			return kerr.Newf(kerr.InvalidArgument, nil, "blob: invalid image variant name %q", v.Name)
+		}
+
+		if _, ok := seen[v.Name]; ok {
+			return kerr.Newf(kerr.InvalidArgument, nil, "blob: duplicate image variant name %q", v.Name)
+		}
+
+		seen[v.Name] = struct{}{}
+
+		if v.Width < 0 || v.Height < 0 || (v.Width == 0 && v.Height == 0) {
+			return kerr.Newf(kerr.InvalidArgument, nil, "blob: image variant %q requires a positive width or height", v.Name)
+		}
+
+		if v.Format != "" && !v.Format.valid() {
+			return kerr.Newf(kerr.InvalidArgument, nil, "blob: unsupported format %q for image variant %q", v.Format, v.Name)
+		}
+	}
+
+	return nil
+}
+
+// DerivedKey returns the key a variant of the image at key is written to.
+// Unless VariantKey is set, the variant name is appended to the base name and
+// the extension matches the format of the variant, e.g. "avatars/u1.png"
+// becomes "avatars/u1_thumb.jpg" for a JPEG variant named "thumb".
+func (p *ImagePipeline) DerivedKey(key string, variant ImageVariant, format ImageFormat) string {
+	if p.VariantKey != nil {
+		return p.VariantKey(key, variant, format)
+	}
+
+	base := strings.TrimSuffix(key, path.Ext(key))
+
+	return base + "_" + variant.Name + format.Extension()
+}
+
+func (p *ImagePipeline) maxInputBytes() int {
+	if p.MaxInputBytes > 0 {
+		return p.MaxInputBytes
+	}
+
+	return DefaultImageMaxInputBytes
+}
+
+func (p *ImagePipeline) maxPixels() int {
+	if p.MaxPixels > 0 {
+		return p.MaxPixels
+	}
+
+	return DefaultImageMaxPixels
+}
+
+// encodedImage is an image produced by the pipeline.
+type encodedImage struct {
+	data   []byte
+	format ImageFormat
+}
+
+// processedImage is the result of running the pipeline on an image.
+type processedImage struct {
+	main     encodedImage
+	variants []encodedImage // in the order of ImagePipeline.Variants
+}
+
+// process runs the pipeline on the encoded image data.
+func (p *ImagePipeline) process(data []byte) (*processedImage, error) {
+	cfg, name, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, kerr.Newf(kerr.InvalidArgument, err, "blob: unsupported or invalid image")
+	}
+
+	src := ImageFormat(name)
+	if !src.valid() {
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: unsupported image format %q", name)
+	}
+
+	if cfg.Width*cfg.Height > p.maxPixels() {
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: image of %dx%d pixels exceeds the limit of %d pixels", cfg.Width, cfg.Height, p.maxPixels())
+	}
+
+	orientation := 1
+	if src == ImageFormatJPEG {
+		orientation = jpegOrientation(data)
+	}
+
+	width, height := cfg.Width, cfg.Height
+	if orientation >= 5 {
+		width, height = height, width
+	}
+
+	out := p.Format
+	if out == "" {
+		out = src
+	}
+
+	var decoded image.Image
+
+	decode := func() (image.Image, error) {
+		if decoded != nil {
+			return decoded, nil
+		}
+
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, kerr.Newf(kerr.InvalidArgument, err, "blob: unsupported or invalid image")
+		}
+
+		decoded = applyOrientation(img, orientation)
+
+		return decoded, nil
+	}
+
+	resize := exceeds(width, height, p.MaxWidth, p.MaxHeight)
+	result := &processedImage{main: encodedImage{data: data, format: src}}
+
+	switch {
+	case out != src || resize || (p.StripMetadata && orientation != 1):
+		img, err := decode()
+		if err != nil {
+			return nil, err
+		}
+
+		if result.main, err = p.encode(fit(img, p.MaxWidth, p.MaxHeight), out); err != nil {
+			return nil, err
+		}
+	case p.StripMetadata:
+		stripped, err := stripMetadata(data, src)
+		if err != nil {
+			return nil, kerr.Newf(kerr.InvalidArgument, err, "blob: unable to strip image metadata")
+		}
+
+		result.main.data = stripped
+	}
+
+	for _, v := range p.Variants {
+		img, err := decode()
+		if err != nil {
+			return nil, err
+		}
+
+		format := v.Format
+		if format == "" {
+			format = out
+		}
+
+		encoded, err := p.encode(fit(img, v.Width, v.Height), format)
+		if err != nil {
+			return nil, err
+		}
+
+		result.variants = append(result.variants, encoded)
+	}
+
+	return result, nil
+}
+
+// encode encodes img in format.
+func (p *ImagePipeline) encode(img image.Image, format ImageFormat) (encodedImage, error) {
+	var (
+		buf bytes.Buffer
+		err error
+	)
+
+	switch format {
+	case ImageFormatJPEG:
+		quality := p.JPEGQuality
+		if quality == 0 {
+			quality = DefaultJPEGQuality
+		}
+
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case ImageFormatPNG:
+		err = png.Encode(&buf, img)
+	case ImageFormatGIF:
+		err = gif.Encode(&buf, img, nil)
+	}
+
+	if err != nil {
+		return encodedImage{}, kerr.Newf(kerr.UnexpectedFailure, err, "blob: unable to encode %s image", format)
+	}
+
+	return encodedImage{data: buf.Bytes(), format: format}, nil
+}
+
+// imageWriter buffers the data written to a Writer with an ImagePipeline
+// and processes it on Close.
+type imageWriter struct {
+	b          *Bucket
+	ctx        context.Context
+	key        string
+	opts       WriterOptions
+	pipeline   *ImagePipeline
+	contentMD5 []byte
+	buf        bytes.Buffer
+	err        error
+	derived    map[string]string
+}
+
+func newImageWriter(ctx context.Context, b *Bucket, key string, opts *WriterOptions) *imageWriter {
+	inner := *opts
+	inner.ImagePipeline = nil
+	inner.ContentMD5 = nil
+	inner.DisableContentTypeDetection = false
+
+	return &imageWriter{
+		b:          b,
+		ctx:        ctx,
+		key:        key,
+		opts:       inner,
+		pipeline:   opts.ImagePipeline,
+		contentMD5: opts.ContentMD5,
+	}
+}
+
+func (w *imageWriter) write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	if w.buf.Len()+len(p) > w.pipeline.maxInputBytes() {
+		w.err = kerr.Newf(kerr.InvalidArgument, nil, "blob: image exceeds the limit of %d bytes", w.pipeline.maxInputBytes())
+		return 0, w.err
+	}
+
+	return w.buf.Write(p)
+}
+
+func (w *imageWriter) close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	data := w.buf.Bytes()
+
+	if len(w.contentMD5) > 0 {
+		sum := md5.Sum(data) //nolint:gosec // MD5 is used for Content-MD5 validation as per RFC 1864
+		if !bytes.Equal(sum[:], w.contentMD5) {
+			return kerr.Newf(kerr.FailedPrecondition, nil, "blob: the WriterOptions.ContentMD5 you specified (%X) did not match what was written (%X)", w.contentMD5, sum)
+		}
+	}
+
+	result, err := w.pipeline.process(data)
+	if err != nil {
+		return err
+	}
+
+	derived := make(map[string]string, len(result.variants))
+
+	// Variants first, so that an existing original implies existing variants.
+	for i, v := range w.pipeline.Variants {
+		variant := result.variants[i]
+		key := w.pipeline.DerivedKey(w.key, v, variant.format)
+
+		if err := w.upload(key, variant); err != nil {
+			return err
+		}
+
+		derived[v.Name] = key
+	}
+
+	if err := w.upload(w.key, result.main); err != nil {
+		return err
+	}
+
+	w.derived = derived
+
+	return nil
+}
+
+func (w *imageWriter) upload(key string, img encodedImage) error {
+	opts := w.opts
+	opts.ContentType = img.format.ContentType()
+
+	return w.b.Upload(w.ctx, key, bytes.NewReader(img.data), &opts)
+}
+
+// DerivedKeys returns the keys of the image variants written by a Writer
+// with an ImagePipeline, by variant name. It is nil before Close succeeded
+// and for writers without a pipeline.
+func (w *Writer) DerivedKeys() map[string]string {
+	if w.image == nil {
+		return nil
+	}
+
+	return w.image.derived
+}
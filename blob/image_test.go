@@ -0,0 +1,267 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// storedBlob is a blob written to a captureBucket.
+type storedBlob struct {
+	contentType string
+	data        bytes.Buffer
+}
+
+type storedWriter struct{ b *storedBlob }
+
+func (w storedWriter) Write(p []byte) (int, error) { return w.b.data.Write(p) }
+func (w storedWriter) Close() error                { return nil }
+
+// newCaptureBucket returns a bucket that records all written blobs by key.
+func newCaptureBucket(t *testing.T) (*blob.Bucket, map[string]*storedBlob) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockDriver := NewMockBucket(ctrl)
+	stored := map[string]*storedBlob{}
+
+	mockDriver.EXPECT().NewTypedWriter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key, contentType string, _ *driver.WriterOptions) (driver.Writer, error) {
+			b := &storedBlob{contentType: contentType}
+			stored[key] = b
+
+			return storedWriter{b: b}, nil
+		}).AnyTimes()
+
+	return blob.NewBucketForTest(mockDriver), stored
+}
+
+func testImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+// jpegWithExif encodes img as JPEG with an EXIF segment carrying the given
+// orientation and a marker string standing in for sensitive metadata.
+func jpegWithExif(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = append(tiff, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+	tiff = append(tiff, []byte("GPS-48.137-11.575")...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+
+	return append(out, data[2:]...)
+}
+
+func decodeConfig(t *testing.T, data []byte) (image.Config, string) {
+	t.Helper()
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	return cfg, format
+}
+
+func TestImagePipeline_StripMetadata(t *testing.T) {
+	bucket, stored := newCaptureBucket(t)
+	input := jpegWithExif(t, testImage(8, 4), 1)
+
+	err := bucket.Upload(context.Background(), "avatars/u1.jpg", bytes.NewReader(input), &blob.WriterOptions{
+		ContentType:   "image/jpeg",
+		ImagePipeline: &blob.ImagePipeline{StripMetadata: true},
+	})
+	require.NoError(t, err)
+
+	out := stored["avatars/u1.jpg"]
+	require.NotNil(t, out)
+	assert.Equal(t, "image/jpeg", out.contentType)
+	assert.NotContains(t, out.data.String(), "GPS-")
+	assert.Equal(t, len(input)-out.data.Len(), 4+6+len("MM\x00\x2a\x00\x00\x00\x08\x00\x01")+12+4+len("GPS-48.137-11.575"),
+		"only the EXIF segment is removed")
+
+	cfg, format := decodeConfig(t, out.data.Bytes())
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, 8, cfg.Width)
+	assert.Equal(t, 4, cfg.Height)
+}
+
+func TestImagePipeline_AppliesOrientation(t *testing.T) {
+	bucket, stored := newCaptureBucket(t)
+	input := jpegWithExif(t, testImage(8, 4), 6)
+
+	err := bucket.Upload(context.Background(), "photo.jpg", bytes.NewReader(input), &blob.WriterOptions{
+		ContentType:   "image/jpeg",
+		ImagePipeline: &blob.ImagePipeline{StripMetadata: true},
+	})
+	require.NoError(t, err)
+
+	out := stored["photo.jpg"].data.Bytes()
+	assert.NotContains(t, string(out), "GPS-")
+
+	cfg, _ := decodeConfig(t, out)
+	assert.Equal(t, 4, cfg.Width)
+	assert.Equal(t, 8, cfg.Height)
+}
+
+func TestImagePipeline_StripPNG(t *testing.T) {
+	bucket, stored := newCaptureBucket(t)
+	input := encodePNG(t, testImage(4, 4))
+
+	// Insert a tEXt chunk after IHDR (8 byte signature + 25 byte chunk).
+	text := []byte("Comment\x00secret")
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(text)))
+	chunk = append(chunk, "tEXt"...)
+	chunk = append(chunk, text...)
+	chunk = append(chunk, 0, 0, 0, 0)
+
+	withText := append(append(append([]byte{}, input[:33]...), chunk...), input[33:]...)
+
+	err := bucket.Upload(context.Background(), "doc.png", bytes.NewReader(withText), &blob.WriterOptions{
+		ContentType:   "image/png",
+		ImagePipeline: &blob.ImagePipeline{StripMetadata: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, input, stored["doc.png"].data.Bytes())
+}
+
+func TestImagePipeline_Variants(t *testing.T) {
+	bucket, stored := newCaptureBucket(t)
+	pipeline := &blob.ImagePipeline{
+		Format:   blob.ImageFormatJPEG,
+		MaxWidth: 300,
+		Variants: []blob.ImageVariant{
+			{Name: "thumb", Width: 100, Height: 100},
+			{Name: "small", Height: 20, Format: blob.ImageFormatPNG},
+		},
+	}
+
+	w, err := bucket.NewWriter(context.Background(), "evidence/scan.png", &blob.WriterOptions{ImagePipeline: pipeline})
+	require.NoError(t, err)
+
+	_, err = w.Write(encodePNG(t, testImage(400, 200)))
+	require.NoError(t, err)
+	assert.Nil(t, w.DerivedKeys())
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, map[string]string{
+		"thumb": "evidence/scan_thumb.jpg",
+		"small": "evidence/scan_small.png",
+	}, w.DerivedKeys())
+
+	main := stored["evidence/scan.png"]
+	assert.Equal(t, "image/jpeg", main.contentType)
+	cfg, _ := decodeConfig(t, main.data.Bytes())
+	assert.Equal(t, image.Config{ColorModel: cfg.ColorModel, Width: 300, Height: 150}, cfg)
+
+	thumb := stored["evidence/scan_thumb.jpg"]
+	assert.Equal(t, "image/jpeg", thumb.contentType)
+	cfg, _ = decodeConfig(t, thumb.data.Bytes())
+	assert.Equal(t, 100, cfg.Width)
+	assert.Equal(t, 50, cfg.Height)
+
+	small := stored["evidence/scan_small.png"]
+	assert.Equal(t, "image/png", small.contentType)
+	cfg, _ = decodeConfig(t, small.data.Bytes())
+	assert.Equal(t, 40, cfg.Width)
+	assert.Equal(t, 20, cfg.Height)
+
+	assert.Equal(t, "thumbs/scan.jpg", (&blob.ImagePipeline{
+		VariantKey: func(key string, v blob.ImageVariant, _ blob.ImageFormat) string { return v.Name + "s/scan.jpg" },
+	}).DerivedKey("scan.png", blob.ImageVariant{Name: "thumb"}, blob.ImageFormatJPEG))
+}
+
+func TestImagePipeline_Errors(t *testing.T) {
+	t.Run("invalid configuration", func(t *testing.T) {
+		bucket, _ := newCaptureBucket(t)
+
+		for _, p := range []*blob.ImagePipeline{
+			{Format: "webp"},
+			{Variants: []blob.ImageVariant{{Name: "Thumb", Width: 10}}},
+			{Variants: []blob.ImageVariant{{Name: "thumb"}}},
+			{Variants: []blob.ImageVariant{{Name: "a", Width: 1}, {Name: "a", Width: 2}}},
+			{JPEGQuality: 101},
+		} {
+			_, err := bucket.NewWriter(context.Background(), "key", &blob.WriterOptions{ImagePipeline: p})
+			assert.True(t, kerr.IsInvalidArgument(err), "%+v", p)
+		}
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		bucket, stored := newCaptureBucket(t)
+
+		err := bucket.Upload(context.Background(), "key", bytes.NewReader([]byte("plain text")), &blob.WriterOptions{
+			ContentType:   "text/plain",
+			ImagePipeline: &blob.ImagePipeline{StripMetadata: true},
+		})
+		assert.True(t, kerr.IsInvalidArgument(err))
+		assert.Empty(t, stored)
+	})
+
+	t.Run("input too large", func(t *testing.T) {
+		bucket, _ := newCaptureBucket(t)
+
+		w, err := bucket.NewWriter(context.Background(), "key", &blob.WriterOptions{
+			ImagePipeline: &blob.ImagePipeline{MaxInputBytes: 10},
+		})
+		require.NoError(t, err)
+
+		_, err = w.Write(make([]byte, 11))
+		assert.True(t, kerr.IsInvalidArgument(err))
+		assert.True(t, kerr.IsInvalidArgument(w.Close()))
+	})
+
+	t.Run("too many pixels", func(t *testing.T) {
+		bucket, _ := newCaptureBucket(t)
+
+		err := bucket.Upload(context.Background(), "key", bytes.NewReader(encodePNG(t, testImage(20, 20))), &blob.WriterOptions{
+			ContentType:   "image/png",
+			ImagePipeline: &blob.ImagePipeline{MaxPixels: 100},
+		})
+		assert.True(t, kerr.IsInvalidArgument(err))
+	})
+}
@@ -0,0 +1,352 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+)
+
+var (
+	errInvalidJPEG = errors.New("invalid JPEG structure")
+	errInvalidPNG  = errors.New("invalid PNG structure")
+)
+
+// exceeds reports whether a w x h image is larger than the bounds; zero
+// bounds are unlimited.
+func exceeds(w, h, maxW, maxH int) bool {
+	return (maxW > 0 && w > maxW) || (maxH > 0 && h > maxH)
+}
+
+// toRGBA converts img to an *image.RGBA with origin (0, 0).
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Rect, img, b.Min, draw.Src)
+
+	return dst
+}
+
+// fit downscales img to fit into maxW x maxH while keeping the aspect ratio.
+// Images that already fit are returned unchanged.
+func fit(img image.Image, maxW, maxH int) image.Image {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if !exceeds(w, h, maxW, maxH) {
+		return img
+	}
+
+	scale := 1.0
+	if maxW > 0 {
+		scale = min(scale, float64(maxW)/float64(w))
+	}
+
+	if maxH > 0 {
+		scale = min(scale, float64(maxH)/float64(h))
+	}
+
+	dw := max(1, int(float64(w)*scale+0.5))
+	dh := max(1, int(float64(h)*scale+0.5))
+
+	return downscale(toRGBA(img), dw, dh)
+}
+
+// downscale resizes src to dw x dh by averaging the source pixels covered by
+// each destination pixel (box filter). It is only used to shrink images.
+func downscale(src *image.RGBA, dw, dh int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for dy := range dh {
+		y0 := dy * sh / dh
+		y1 := max(y0+1, (dy+1)*sh/dh)
+
+		for dx := range dw {
+			x0 := dx * sw / dw
+			x1 := max(x0+1, (dx+1)*sw/dw)
+
+			var r, g, b, a, n uint64
+
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+
+			o := dst.PixOffset(dx, dy)
+			dst.Pix[o+0] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(b / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+// applyOrientation transforms img according to an EXIF orientation (1-8) so
+// that it displays correctly without the tag.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	src := toRGBA(img)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := range h {
+		for x := range w {
+			var tx, ty int
+
+			switch orientation {
+			case 2: // mirror horizontal
+				tx, ty = w-1-x, y
+			case 3: // rotate 180
+				tx, ty = w-1-x, h-1-y
+			case 4: // mirror vertical
+				tx, ty = x, h-1-y
+			case 5: // transpose
+				tx, ty = y, x
+			case 6: // rotate 90 clockwise
+				tx, ty = h-1-y, x
+			case 7: // transverse
+				tx, ty = h-1-y, w-1-x
+			case 8: // rotate 90 counter-clockwise
+				tx, ty = y, w-1-x
+			}
+
+			copy(dst.Pix[dst.PixOffset(tx, ty):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+
+	return dst
+}
+
+// JPEG markers used when scanning segments.
+const (
+	jpegSOI  = 0xD8
+	jpegSOS  = 0xDA
+	jpegAPP0 = 0xE0
+	jpegAPP1 = 0xE1
+	jpegAPP2 = 0xE2
+	jpegAPPE = 0xEE
+	jpegAPPF = 0xEF
+	jpegCOM  = 0xFE
+)
+
+// jpegSegment is a marker segment before the image data.
+type jpegSegment struct {
+	marker byte
+	data   []byte // payload without marker and length
+	raw    []byte // complete segment including marker and length
+}
+
+// jpegSegments splits the header of a JPEG into its segments and returns
+// them together with the remainder starting at the SOS marker.
+func jpegSegments(data []byte) ([]jpegSegment, []byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != jpegSOI {
+		return nil, nil, errInvalidJPEG
+	}
+
+	var segments []jpegSegment
+
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return nil, nil, errInvalidJPEG
+		}
+
+		marker := data[i+1]
+		if marker == 0xFF { // fill byte
+			i++
+			continue
+		}
+
+		if marker == jpegSOS {
+			return segments, data[i:], nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return nil, nil, errInvalidJPEG
+		}
+
+		segments = append(segments, jpegSegment{
+			marker: marker,
+			data:   data[i+4 : i+2+length],
+			raw:    data[i : i+2+length],
+		})
+		i += 2 + length
+	}
+
+	return nil, nil, errInvalidJPEG
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG, or 1 if it has
+// none or it cannot be read.
+func jpegOrientation(data []byte) int {
+	segments, _, err := jpegSegments(data)
+	if err != nil {
+		return 1
+	}
+
+	for _, s := range segments {
+		if s.marker != jpegAPP1 || !bytes.HasPrefix(s.data, []byte("Exif\x00\x00")) {
+			continue
+		}
+
+		if o := exifOrientation(s.data[6:]); o != 0 {
+			return o
+		}
+	}
+
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of a TIFF
+// structure. It returns 0 if there is none.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[offset:]))
+
+	for i := range count {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+
+		const tagOrientation = 0x0112
+
+		if order.Uint16(tiff[entry:]) == tagOrientation {
+			if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+
+			return 0
+		}
+	}
+
+	return 0
+}
+
+// stripMetadata removes metadata from an encoded image without re-encoding
+// it. GIF images are returned unchanged.
+func stripMetadata(data []byte, format ImageFormat) ([]byte, error) {
+	switch format {
+	case ImageFormatJPEG:
+		return stripJPEG(data)
+	case ImageFormatPNG:
+		return stripPNG(data)
+	default:
+		return data, nil
+	}
+}
+
+// stripJPEG drops APPn segments other than JFIF (APP0), ICC profiles (APP2)
+// and Adobe color information (APP14), and all comments.
+func stripJPEG(data []byte) ([]byte, error) {
+	segments, rest, err := jpegSegments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, jpegSOI)
+
+	for _, s := range segments {
+		switch {
+		case s.marker == jpegAPP0, s.marker == jpegAPPE:
+			// keep
+		case s.marker == jpegAPP2 && bytes.HasPrefix(s.data, []byte("ICC_PROFILE\x00")):
+			// keep
+		case s.marker >= jpegAPP1 && s.marker <= jpegAPPF, s.marker == jpegCOM:
+			continue
+		}
+
+		out = append(out, s.raw...)
+	}
+
+	return append(out, rest...), nil
+}
+
+// pngMetadataChunks are the PNG chunks removed by stripPNG.
+var pngMetadataChunks = map[string]struct{}{
+	"tEXt": {}, "zTXt": {}, "iTXt": {}, "eXIf": {}, "tIME": {},
+}
+
+// stripPNG drops text, EXIF and timestamp chunks.
+func stripPNG(data []byte) ([]byte, error) {
+	const signatureLen = 8
+
+	if len(data) < signatureLen || string(data[:signatureLen]) != "\x89PNG\r\n\x1a\n" {
+		return nil, errInvalidPNG
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:signatureLen]...)
+
+	for i := signatureLen; i < len(data); {
+		if i+12 > len(data) {
+			return nil, errInvalidPNG
+		}
+
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+
+		if length < 0 || end > len(data) {
+			return nil, errInvalidPNG
+		}
+
+		chunkType := string(data[i+4 : i+8])
+		if _, drop := pngMetadataChunks[chunkType]; !drop {
+			out = append(out, data[i:end]...)
+		}
+
+		i = end
+
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// ListOptions sets options for listing objects.
+type ListOptions struct {
+	// Prefix restricts the listing to keys starting with Prefix.
+	Prefix string
+	// PageSize is the maximum number of objects to return in a single page.
+	// Zero lets the driver choose.
+	PageSize int
+	// PageToken continues a previous listing; pass ListPage.NextPageToken.
+	// It is empty for the first page.
+	PageToken []byte
+	// Tags restricts the listing to objects carrying all of the given tags.
+	// Filtering by tags requires a driver that supports tags; see
+	// Bucket.SupportsTags. Objects of tag-filtered listings may have a zero
+	// Size and ModTime.
+	Tags map[string]string
+}
+
+// ListObject describes a single object returned by List.
+type ListObject struct {
+	// Key is the key of the object.
+	Key string
+	// Size is the size of the object content in bytes.
+	Size int64
+	// ModTime is the time the object was last modified.
+	ModTime time.Time
+}
+
+// ListPage is a single page of objects returned by List.
+type ListPage struct {
+	// Objects holds the objects of this page.
+	Objects []*ListObject
+	// NextPageToken is passed as ListOptions.PageToken to fetch the next
+	// page. It is empty when there are no more pages.
+	NextPageToken []byte
+}
+
+// List returns a single page of the objects in the bucket. A nil ListOptions
+// is treated the same as the zero value.
+//
+// If the driver does not support listing, or opts.Tags is set and the driver
+// does not support tags, List returns an error for which kerr.Code will
+// return kerr.NotImplemented.
+func (b *Bucket) List(ctx context.Context, opts *ListOptions) (*ListPage, error) {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+
+	if opts.PageSize < 0 {
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: List PageSize must be >= 0, got %d", opts.PageSize)
+	}
+
+	if len(opts.Tags) > 0 {
+		if err := validateTags("List", opts.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, errClosed
+	}
+
+	l, ok := b.b.(driver.Lister)
+	if !ok {
+		return nil, kerr.Newf(kerr.NotImplemented, nil, "blob: List is not supported by this driver")
+	}
+
+	dopts := &driver.ListOptions{
+		Prefix:    opts.Prefix,
+		PageSize:  opts.PageSize,
+		PageToken: opts.PageToken,
+	}
+
+	if len(opts.Tags) > 0 {
+		if _, err := b.tagger("List by tags"); err != nil {
+			return nil, err
+		}
+
+		dopts.Tags = maps.Clone(opts.Tags)
+	}
+
+	dpage, err := l.ListPaged(ctx, dopts)
+	if err != nil {
+		return nil, wrapError(b.b, err, "")
+	}
+
+	page := &ListPage{
+		Objects:       make([]*ListObject, 0, len(dpage.Objects)),
+		NextPageToken: dpage.NextPageToken,
+	}
+
+	for _, obj := range dpage.Objects {
+		page.Objects = append(page.Objects, &ListObject{
+			Key:     obj.Key,
+			Size:    obj.Size,
+			ModTime: obj.ModTime,
+		})
+	}
+
+	return page, nil
+}
@@ -0,0 +1,381 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultSearchMaxObjectSize is the size above which SearchText skips
+	// objects.
+	DefaultSearchMaxObjectSize = 1024 * 1024
+	// DefaultSearchConcurrency is the number of objects SearchText scans at
+	// the same time.
+	DefaultSearchConcurrency = 8
+	// DefaultSearchMaxMatches is the number of matches SearchText reports per
+	// object.
+	DefaultSearchMaxMatches = 100
+	// DefaultSearchPageSize is the number of objects requested per page when
+	// SearchText lists the prefix.
+	DefaultSearchPageSize = 1000
+
+	// searchSniffLength is the number of leading bytes inspected to detect
+	// binary content.
+	searchSniffLength = 512
+	// searchSnippetContext is the number of bytes kept around a match in
+	// SearchMatch.Text for long lines.
+	searchSnippetContext = 80
+)
+
+// Reasons reported in SearchSkip.
+const (
+	// SearchSkipTooLarge marks objects above SearchOptions.MaxObjectSize.
+	SearchSkipTooLarge = "too_large"
+	// SearchSkipBinary marks objects that do not contain UTF-8 text.
+	SearchSkipBinary = "binary"
+	// SearchSkipNotFound marks objects deleted after they were listed.
+	SearchSkipNotFound = "not_found"
+)
+
+// SearchOptions sets options for SearchText.
+type SearchOptions struct {
+	// CaseInsensitive matches the query regardless of case.
+	CaseInsensitive bool
+	// MaxObjectSize is the size in bytes above which objects are skipped.
+	// Defaults to DefaultSearchMaxObjectSize.
+	MaxObjectSize int64
+	// Concurrency is the maximum number of objects scanned at the same time.
+	// Defaults to DefaultSearchConcurrency.
+	Concurrency int
+	// MaxMatchesPerKey limits the matches reported per object. Defaults to
+	// DefaultSearchMaxMatches.
+	MaxMatchesPerKey int
+}
+
+// SearchMatch is a single occurrence of the query.
+type SearchMatch struct {
+	// Line is the 1-based line number.
+	Line int
+	// Column is the 1-based position of the match within the line, in
+	// characters.
+	Column int
+	// Offset is the byte offset of the match within the object.
+	Offset int64
+	// Text is the line containing the match, shortened around the match for
+	// long lines.
+	Text string
+}
+
+// SearchResult lists the matches found in a single object.
+type SearchResult struct {
+	// Key is the key of the object.
+	Key string
+	// Matches holds the matches in order of appearance.
+	Matches []SearchMatch
+	// Truncated is set if the object has more than MaxMatchesPerKey matches.
+	Truncated bool
+}
+
+// SearchSkip describes an object that was not searched.
+type SearchSkip struct {
+	// Key is the key of the object.
+	Key string
+	// Reason is one of SearchSkipTooLarge, SearchSkipBinary or
+	// SearchSkipNotFound.
+	Reason string
+}
+
+// SearchReport is the result of SearchText.
+type SearchReport struct {
+	// Results holds the objects with at least one match, sorted by key.
+	Results []SearchResult
+	// Skipped holds the objects that were not searched, sorted by key.
+	Skipped []SearchSkip
+	// Scanned is the number of objects that were searched.
+	Scanned int
+}
+
+// SearchText searches the text objects below prefix for query and reports
+// the location of every match. It is meant for small text documents such as
+// policies; objects are streamed line by line and scanned concurrently, so
+// memory usage is bounded by opts.Concurrency lines.
+//
+// Objects larger than opts.MaxObjectSize and objects that do not start with
+// UTF-8 text are skipped and listed in SearchReport.Skipped. Matches must
+// not span lines.
+//
+// A nil SearchOptions is treated the same as the zero value.
+//
+// If the driver does not support listing, SearchText returns an error for
+// which kerr.Code will return kerr.NotImplemented.
+func (b *Bucket) SearchText(ctx context.Context, prefix, query string, opts *SearchOptions) (*SearchReport, error) {
+	if query == "" {
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: SearchText query must not be empty")
+	}
+
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+
+	if opts.MaxObjectSize < 0 || opts.Concurrency < 0 || opts.MaxMatchesPerKey < 0 {
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: SearchText options must be non-negative")
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, errClosed
+	}
+
+	l, ok := b.b.(driver.Lister)
+	if !ok {
+		return nil, kerr.Newf(kerr.NotImplemented, nil, "blob: SearchText is not supported by this driver")
+	}
+
+	pattern := regexp.QuoteMeta(query)
+	if opts.CaseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+
+	s := &textSearch{
+		b:          b.b,
+		re:         regexp.MustCompile(pattern),
+		maxSize:    defaultInt64(opts.MaxObjectSize, DefaultSearchMaxObjectSize),
+		maxMatches: defaultInt(opts.MaxMatchesPerKey, DefaultSearchMaxMatches),
+		report:     &SearchReport{},
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	objects := make(chan *driver.ListObject)
+
+	g.Go(func() error {
+		defer close(objects)
+		return s.list(gctx, l, prefix, objects)
+	})
+
+	for range defaultInt(opts.Concurrency, DefaultSearchConcurrency) {
+		g.Go(func() error {
+			for obj := range objects {
+				if err := s.scan(gctx, obj); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(s.report.Results, func(a, b SearchResult) int { return strings.Compare(a.Key, b.Key) })
+	slices.SortFunc(s.report.Skipped, func(a, b SearchSkip) int { return strings.Compare(a.Key, b.Key) })
+
+	return s.report, nil
+}
+
+// textSearch is a running SearchText.
+type textSearch struct {
+	b          driver.Bucket
+	re         *regexp.Regexp
+	maxSize    int64
+	maxMatches int
+
+	mu     sync.Mutex
+	report *SearchReport
+}
+
+// list sends the objects below prefix to out.
+func (s *textSearch) list(ctx context.Context, l driver.Lister, prefix string, out chan<- *driver.ListObject) error {
+	opts := &driver.ListOptions{Prefix: prefix, PageSize: DefaultSearchPageSize}
+
+	for {
+		page, err := l.ListPaged(ctx, opts)
+		if err != nil {
+			return wrapError(s.b, err, "")
+		}
+
+		for _, obj := range page.Objects {
+			select {
+			case out <- obj:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if len(page.NextPageToken) == 0 {
+			return nil
+		}
+
+		opts.PageToken = page.NextPageToken
+	}
+}
+
+// scan searches a single object and records the outcome.
+func (s *textSearch) scan(ctx context.Context, obj *driver.ListObject) error {
+	if obj.Size > s.maxSize {
+		s.skip(obj.Key, SearchSkipTooLarge)
+		return nil
+	}
+
+	r, err := s.b.NewRangeReader(ctx, obj.Key, 0, -1, &driver.ReaderOptions{})
+	if err != nil {
+		if kerr.Code(err) == kerr.NotFound {
+			s.skip(obj.Key, SearchSkipNotFound)
+			return nil
+		}
+
+		return wrapError(s.b, err, obj.Key)
+	}
+	defer r.Close()
+
+	// Listings may not report sizes, so check again.
+	if r.Attributes().Size > s.maxSize {
+		s.skip(obj.Key, SearchSkipTooLarge)
+		return nil
+	}
+
+	br := bufio.NewReader(io.LimitReader(r, s.maxSize+1))
+
+	head, err := br.Peek(searchSniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return wrapError(s.b, err, obj.Key)
+	}
+
+	if isBinary(head, len(head) == searchSniffLength) {
+		s.skip(obj.Key, SearchSkipBinary)
+		return nil
+	}
+
+	res := SearchResult{Key: obj.Key}
+
+	var offset int64
+
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return wrapError(s.b, err, obj.Key)
+		}
+
+		if offset+int64(len(data)) > s.maxSize {
+			s.skip(obj.Key, SearchSkipTooLarge)
+			return nil
+		}
+
+		if !res.Truncated {
+			s.match(&res, line, offset, bytes.TrimRight(data, "\r\n"))
+		}
+
+		offset += int64(len(data))
+
+		if err != nil {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.report.Scanned++
+
+	if len(res.Matches) > 0 {
+		s.report.Results = append(s.report.Results, res)
+	}
+
+	return nil
+}
+
+// match appends the matches of the query in text to res.
+func (s *textSearch) match(res *SearchResult, line int, offset int64, text []byte) {
+	for _, loc := range s.re.FindAllIndex(text, -1) {
+		if len(res.Matches) == s.maxMatches {
+			res.Truncated = true
+			return
+		}
+
+		res.Matches = append(res.Matches, SearchMatch{
+			Line:   line,
+			Column: utf8.RuneCount(text[:loc[0]]) + 1,
+			Offset: offset + int64(loc[0]),
+			Text:   snippet(text, loc[0], loc[1]),
+		})
+	}
+}
+
+// skip records an object that was not searched.
+func (s *textSearch) skip(key, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.report.Skipped = append(s.report.Skipped, SearchSkip{Key: key, Reason: reason})
+}
+
+// isBinary reports whether head does not look like UTF-8 text. If truncated
+// is set, head may end in the middle of a character.
+func isBinary(head []byte, truncated bool) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true
+	}
+
+	for i := 0; i < len(head); {
+		r, size := utf8.DecodeRune(head[i:])
+		if r == utf8.RuneError && size == 1 {
+			return !truncated || utf8.FullRune(head[i:])
+		}
+
+		i += size
+	}
+
+	return false
+}
+
+// snippet returns text shortened to the match at [start, end) and
+// searchSnippetContext bytes on each side, cut at character boundaries.
+func snippet(text []byte, start, end int) string {
+	from := max(0, start-searchSnippetContext)
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from--
+	}
+
+	to := min(len(text), end+searchSnippetContext)
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to++
+	}
+
+	return string(text[from:to])
+}
+
+// defaultInt returns v, or def if v is zero.
+func defaultInt(v, def int) int {
+	if v == 0 {
+		return def
+	}
+
+	return v
+}
+
+// defaultInt64 returns v, or def if v is zero.
+func defaultInt64(v, def int64) int64 {
+	if v == 0 {
+		return def
+	}
+
+	return v
+}
@@ -0,0 +1,163 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newSearchBucket returns a bucket listing and serving objects. Keys in
+// objects without content are listed but not found when read.
+func newSearchBucket(t *testing.T, objects map[string]string, sizes map[string]int64) *blob.Bucket {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockBucket := NewMockBucket(ctrl)
+	lister := NewMockLister(ctrl)
+
+	var listed []*driver.ListObject
+
+	for key, content := range objects {
+		size, ok := sizes[key]
+		if !ok {
+			size = int64(len(content))
+		}
+
+		listed = append(listed, &driver.ListObject{Key: key, Size: size})
+	}
+
+	lister.EXPECT().ListPaged(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+			assert.Equal(t, "policies/", opts.Prefix)
+
+			// two pages to exercise pagination
+			if len(opts.PageToken) == 0 {
+				return &driver.ListPage{Objects: listed[:len(listed)/2], NextPageToken: []byte("next")}, nil
+			}
+
+			return &driver.ListPage{Objects: listed[len(listed)/2:]}, nil
+		}).AnyTimes()
+
+	mockBucket.EXPECT().NewRangeReader(gomock.Any(), gomock.Any(), int64(0), int64(-1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string, _, _ int64, _ *driver.ReaderOptions) (driver.Reader, error) {
+			content := objects[key]
+			if content == "" {
+				return nil, kerr.NewNotFound("")
+			}
+
+			return &memReader{
+				Reader: bytes.NewReader([]byte(content)),
+				attrs:  driver.ReaderAttributes{Size: int64(len(content))},
+			}, nil
+		}).AnyTimes()
+
+	return blob.NewBucketForTest(&listerDriver{MockBucket: mockBucket, MockLister: lister})
+}
+
+func TestBucket_SearchText(t *testing.T) {
+	objects := map[string]string{
+		"policies/access.md":   "# Access Control\r\nAll access is logged.\r\nReview ACCESS quarterly.",
+		"policies/backup.md":   "# Backup\nBackups are encrypted.\n",
+		"policies/unicode.md":  "Zugriff für Äußere: access denied",
+		"policies/binary.bin":  "access\x00\x01\x02",
+		"policies/large.md":    "access",
+		"policies/deleted.md":  "",
+		"policies/streamed.md": strings.Repeat("x", 80) + "\naccess\n",
+	}
+
+	bucket := newSearchBucket(t, objects, map[string]int64{
+		"policies/large.md":    1 << 30,
+		"policies/streamed.md": 0, // listings may not report sizes
+	})
+
+	report, err := bucket.SearchText(context.Background(), "policies/", "access", &blob.SearchOptions{
+		CaseInsensitive: true,
+		MaxObjectSize:   80,
+		Concurrency:     3,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Scanned)
+	assert.Equal(t, []blob.SearchSkip{
+		{Key: "policies/binary.bin", Reason: blob.SearchSkipBinary},
+		{Key: "policies/deleted.md", Reason: blob.SearchSkipNotFound},
+		{Key: "policies/large.md", Reason: blob.SearchSkipTooLarge},
+		{Key: "policies/streamed.md", Reason: blob.SearchSkipTooLarge},
+	}, report.Skipped)
+
+	require.Len(t, report.Results, 2)
+
+	access := report.Results[0]
+	assert.Equal(t, "policies/access.md", access.Key)
+	assert.Equal(t, []blob.SearchMatch{
+		{Line: 1, Column: 3, Offset: 2, Text: "# Access Control"},
+		{Line: 2, Column: 5, Offset: 22, Text: "All access is logged."},
+		{Line: 3, Column: 8, Offset: 48, Text: "Review ACCESS quarterly."},
+	}, access.Matches)
+	assert.Equal(t, "ACCESS", objects[access.Key][48:54])
+
+	unicode := report.Results[1]
+	assert.Equal(t, "policies/unicode.md", unicode.Key)
+	require.Len(t, unicode.Matches, 1)
+	assert.Equal(t, 21, unicode.Matches[0].Column)
+	assert.Equal(t, int64(len("Zugriff für Äußere: ")), unicode.Matches[0].Offset)
+}
+
+func TestBucket_SearchText_CaseSensitive(t *testing.T) {
+	bucket := newSearchBucket(t, map[string]string{
+		"policies/a.md": "Access\naccess",
+		"policies/b.md": "none",
+	}, nil)
+
+	report, err := bucket.SearchText(context.Background(), "policies/", "access", nil)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, []blob.SearchMatch{{Line: 2, Column: 1, Offset: 7, Text: "access"}}, report.Results[0].Matches)
+}
+
+func TestBucket_SearchText_MaxMatches(t *testing.T) {
+	long := strings.Repeat("a", 200) + "key" + strings.Repeat("b", 200)
+
+	bucket := newSearchBucket(t, map[string]string{
+		"policies/a.md": "key key\nkey\n" + long,
+		"policies/b.md": "no match",
+	}, nil)
+
+	report, err := bucket.SearchText(context.Background(), "policies/", "key", &blob.SearchOptions{MaxMatchesPerKey: 2})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Len(t, report.Results[0].Matches, 2)
+	assert.True(t, report.Results[0].Truncated)
+
+	report, err = bucket.SearchText(context.Background(), "policies/", "key", nil)
+	require.NoError(t, err)
+
+	last := report.Results[0].Matches[3]
+	assert.Equal(t, 3, last.Line)
+	assert.Equal(t, strings.Repeat("a", 80)+"key"+strings.Repeat("b", 80), last.Text)
+}
+
+func TestBucket_SearchText_InvalidArguments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bucket := blob.NewBucketForTest(NewMockBucket(ctrl))
+
+	_, err := bucket.SearchText(context.Background(), "", "", nil)
+	assert.True(t, kerr.Is(err, kerr.InvalidArgument))
+
+	_, err = bucket.SearchText(context.Background(), "", "q", &blob.SearchOptions{Concurrency: -1})
+	assert.True(t, kerr.Is(err, kerr.InvalidArgument))
+
+	_, err = bucket.SearchText(context.Background(), "", "q", nil)
+	assert.True(t, kerr.Is(err, kerr.NotImplemented))
+}
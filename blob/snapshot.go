@@ -0,0 +1,213 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"context"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// SnapshotInfo describes a point-in-time snapshot of a blob.
+type SnapshotInfo struct {
+	// ID is the opaque identifier of the snapshot.
+	ID string
+	// CreatedAt is the time the snapshot was taken.
+	CreatedAt time.Time
+	// Size is the size of the snapshot content in bytes.
+	Size int64
+}
+
+// SnapshotReader reads the content of a blob snapshot.
+// It must be closed after reads are finished.
+type SnapshotReader struct {
+	r driver.Reader
+}
+
+// Read implements io.Reader.
+func (r *SnapshotReader) Read(p []byte) (int, error) {
+	return r.r.Read(p)
+}
+
+// Close implements io.Closer.
+func (r *SnapshotReader) Close() error {
+	return r.r.Close()
+}
+
+// ContentType returns the MIME type of the snapshot.
+func (r *SnapshotReader) ContentType() string {
+	return r.r.Attributes().ContentType
+}
+
+// ModTime returns the time the snapshotted blob was last modified.
+func (r *SnapshotReader) ModTime() time.Time {
+	return r.r.Attributes().ModTime
+}
+
+// Size returns the size of the snapshot content in bytes.
+func (r *SnapshotReader) Size() int64 {
+	return r.r.Attributes().Size
+}
+
+// Ensure that SnapshotReader implements io.ReadCloser.
+var _ io.ReadCloser = (*SnapshotReader)(nil)
+
+// Snapshot creates a point-in-time, read-only snapshot of the blob stored at
+// key and returns its ID.
+//
+// If the driver does not support snapshots, Snapshot returns an error for
+// which kerr.Code will return kerr.NotImplemented.
+func (b *Bucket) Snapshot(ctx context.Context, key string) (string, error) {
+	if err := validateSnapshotKey("Snapshot", key); err != nil {
+		return "", err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return "", errClosed
+	}
+
+	s, err := b.snapshotter("Snapshot")
+	if err != nil {
+		return "", err
+	}
+
+	id, err := s.Snapshot(ctx, key)
+	if err != nil {
+		return "", wrapError(b.b, err, key)
+	}
+
+	return id, nil
+}
+
+// ListSnapshots returns the snapshots of the blob stored at key, oldest first.
+//
+// If the driver does not support snapshots, ListSnapshots returns an error for
+// which kerr.Code will return kerr.NotImplemented.
+func (b *Bucket) ListSnapshots(ctx context.Context, key string) ([]*SnapshotInfo, error) {
+	if err := validateSnapshotKey("ListSnapshots", key); err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, errClosed
+	}
+
+	s, err := b.snapshotter("ListSnapshots")
+	if err != nil {
+		return nil, err
+	}
+
+	dsnaps, err := s.ListSnapshots(ctx, key)
+	if err != nil {
+		return nil, wrapError(b.b, err, key)
+	}
+
+	snaps := make([]*SnapshotInfo, 0, len(dsnaps))
+	for _, ds := range dsnaps {
+		snaps = append(snaps, &SnapshotInfo{
+			ID:        ds.ID,
+			CreatedAt: ds.CreatedAt,
+			Size:      ds.Size,
+		})
+	}
+
+	return snaps, nil
+}
+
+// ReadSnapshot returns a reader for the content of the given snapshot of the
+// blob stored at key. A nil ReaderOptions is treated the same as the zero value.
+//
+// The caller must call Close on the returned reader when done reading.
+func (b *Bucket) ReadSnapshot(ctx context.Context, key, snapshotID string, opts *ReaderOptions) (*SnapshotReader, error) {
+	if err := validateSnapshotKey("ReadSnapshot", key); err != nil {
+		return nil, err
+	}
+
+	if snapshotID == "" {
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: ReadSnapshot snapshotID must be a non-empty string")
+	}
+
+	if opts == nil {
+		opts = &ReaderOptions{}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, errClosed
+	}
+
+	s, err := b.snapshotter("ReadSnapshot")
+	if err != nil {
+		return nil, err
+	}
+
+	dr, err := s.NewSnapshotReader(ctx, key, snapshotID, &driver.ReaderOptions{BeforeRead: opts.BeforeRead})
+	if err != nil {
+		return nil, wrapError(b.b, err, key)
+	}
+
+	return &SnapshotReader{r: dr}, nil
+}
+
+// PromoteSnapshot restores the blob stored at key to the content of the given
+// snapshot. The current content is overwritten; take a new Snapshot first if
+// it must be preserved. The promoted snapshot itself is kept.
+func (b *Bucket) PromoteSnapshot(ctx context.Context, key, snapshotID string) error {
+	if err := validateSnapshotKey("PromoteSnapshot", key); err != nil {
+		return err
+	}
+
+	if snapshotID == "" {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: PromoteSnapshot snapshotID must be a non-empty string")
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return errClosed
+	}
+
+	s, err := b.snapshotter("PromoteSnapshot")
+	if err != nil {
+		return err
+	}
+
+	return wrapError(b.b, s.PromoteSnapshot(ctx, key, snapshotID), key)
+}
+
+// snapshotter returns the driver as a driver.Snapshotter, or a NotImplemented
+// error if the driver does not support snapshots.
+func (b *Bucket) snapshotter(op string) (driver.Snapshotter, error) {
+	s, ok := b.b.(driver.Snapshotter)
+	if !ok {
+		return nil, kerr.Newf(kerr.NotImplemented, nil, "blob: %s is not supported by this driver", op)
+	}
+
+	return s, nil
+}
+
+func validateSnapshotKey(op, key string) error {
+	if !utf8.ValidString(key) {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: %s key must be a valid UTF-8 string: %q", op, key)
+	}
+
+	if key == "" {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: %s key must be a non-empty string", op)
+	}
+
+	return nil
+}
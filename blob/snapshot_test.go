@@ -0,0 +1,86 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// snapshotDriver is a driver.Bucket that also implements driver.Snapshotter.
+type snapshotDriver struct {
+	*MockBucket
+	*MockSnapshotter
+}
+
+func TestBucket_SnapshotNotImplemented(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bucket := blob.NewBucketForTest(NewMockBucket(ctrl))
+
+	_, err := bucket.Snapshot(context.Background(), "key")
+	assert.True(t, kerr.Is(err, kerr.NotImplemented))
+
+	_, err = bucket.ListSnapshots(context.Background(), "key")
+	assert.True(t, kerr.Is(err, kerr.NotImplemented))
+}
+
+func TestBucket_Snapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	snapshotter := NewMockSnapshotter(ctrl)
+	bucket := blob.NewBucketForTest(&snapshotDriver{MockBucket: NewMockBucket(ctrl), MockSnapshotter: snapshotter})
+	ctx := context.Background()
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	snapshotter.EXPECT().Snapshot(ctx, "key").Return("snap-1", nil)
+	snapshotter.EXPECT().ListSnapshots(ctx, "key").Return([]*driver.SnapshotInfo{{ID: "snap-1", CreatedAt: created, Size: 5}}, nil)
+	snapshotter.EXPECT().
+		NewSnapshotReader(ctx, "key", "snap-1", gomock.Any()).
+		Return(&memReader{Reader: bytes.NewReader([]byte("hello")), attrs: driver.ReaderAttributes{Size: 5, ContentType: "text/plain"}}, nil)
+	snapshotter.EXPECT().PromoteSnapshot(ctx, "key", "snap-1").Return(nil)
+
+	id, err := bucket.Snapshot(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "snap-1", id)
+
+	snaps, err := bucket.ListSnapshots(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []*blob.SnapshotInfo{{ID: "snap-1", CreatedAt: created, Size: 5}}, snaps)
+
+	r, err := bucket.ReadSnapshot(ctx, "key", "snap-1", nil)
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+	assert.Equal(t, "text/plain", r.ContentType())
+	assert.Equal(t, int64(5), r.Size())
+	require.NoError(t, r.Close())
+
+	require.NoError(t, bucket.PromoteSnapshot(ctx, "key", "snap-1"))
+}
+
+func TestBucket_SnapshotInvalidArguments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bucket := blob.NewBucketForTest(&snapshotDriver{MockBucket: NewMockBucket(ctrl), MockSnapshotter: NewMockSnapshotter(ctrl)})
+	ctx := context.Background()
+
+	_, err := bucket.Snapshot(ctx, "")
+	assert.True(t, kerr.Is(err, kerr.InvalidArgument))
+
+	_, err = bucket.ReadSnapshot(ctx, "key", "", nil)
+	assert.True(t, kerr.Is(err, kerr.InvalidArgument))
+
+	err = bucket.PromoteSnapshot(ctx, "key", "")
+	assert.True(t, kerr.Is(err, kerr.InvalidArgument))
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"context"
+	"maps"
+	"unicode/utf8"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+const (
+	// MaxTags is the maximum number of tags an object may carry.
+	MaxTags = 10
+	// MaxTagKeyLength is the maximum length of a tag key in characters.
+	MaxTagKeyLength = 128
+	// MaxTagValueLength is the maximum length of a tag value in characters.
+	MaxTagValueLength = 256
+)
+
+// SupportsTags reports whether the driver of the bucket supports object tags.
+func (b *Bucket) SupportsTags() bool {
+	_, ok := b.b.(driver.Tagger)
+	return ok
+}
+
+// SetTags replaces all tags of the object stored at key. An empty map removes
+// all tags.
+//
+// Tags differ from WriterOptions.Metadata: they can be changed without
+// rewriting the object and are indexed by the service, so they can be used to
+// filter listings with ListOptions.Tags. Keys must be 1 to MaxTagKeyLength and
+// values at most MaxTagValueLength characters long and may contain letters,
+// digits, spaces and the characters + - . / : = _.
+//
+// If the driver does not support tags, SetTags returns an error for which
+// kerr.Code will return kerr.NotImplemented.
+func (b *Bucket) SetTags(ctx context.Context, key string, tags map[string]string) error {
+	if err := validateSnapshotKey("SetTags", key); err != nil {
+		return err
+	}
+
+	if err := validateTags("SetTags", tags); err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return errClosed
+	}
+
+	t, err := b.tagger("SetTags")
+	if err != nil {
+		return err
+	}
+
+	return wrapError(b.b, t.SetTags(ctx, key, maps.Clone(tags)), key)
+}
+
+// GetTags returns the tags of the object stored at key. Objects without tags
+// yield an empty, non-nil map.
+//
+// If the driver does not support tags, GetTags returns an error for which
+// kerr.Code will return kerr.NotImplemented.
+func (b *Bucket) GetTags(ctx context.Context, key string) (map[string]string, error) {
+	if err := validateSnapshotKey("GetTags", key); err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, errClosed
+	}
+
+	t, err := b.tagger("GetTags")
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := t.GetTags(ctx, key)
+	if err != nil {
+		return nil, wrapError(b.b, err, key)
+	}
+
+	if tags == nil {
+		tags = map[string]string{}
+	}
+
+	return tags, nil
+}
+
+// tagger returns the driver as a driver.Tagger, or a NotImplemented error if
+// the driver does not support tags.
+func (b *Bucket) tagger(op string) (driver.Tagger, error) {
+	t, ok := b.b.(driver.Tagger)
+	if !ok {
+		return nil, kerr.Newf(kerr.NotImplemented, nil, "blob: %s is not supported by this driver", op)
+	}
+
+	return t, nil
+}
+
+func validateTags(op string, tags map[string]string) error {
+	if len(tags) > MaxTags {
+		return kerr.Newf(kerr.InvalidArgument, nil, "blob: %s accepts at most %d tags, got %d", op, MaxTags, len(tags))
+	}
+
+	for k, v := range tags {
+		if n := utf8.RuneCountInString(k); n == 0 || n > MaxTagKeyLength || !validTagString(k) {
+			return kerr.Newf(kerr.InvalidArgument, nil, "blob: %s invalid tag key %q", op, k)
+		}
+
+		if utf8.RuneCountInString(v) > MaxTagValueLength || !validTagString(v) {
+			return kerr.Newf(kerr.InvalidArgument, nil, "blob: %s invalid value for tag %q", op, k)
+		}
+	}
+
+	return nil
+}
+
+// validTagString reports whether s only holds characters allowed in tag keys
+// and values. The set is the intersection of what the supported services
+// accept.
+func validTagString(s string) bool {
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == ' ', c == '+', c == '-', c == '.', c == '/', c == ':', c == '=', c == '_':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
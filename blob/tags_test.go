@@ -0,0 +1,130 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// taggerDriver is a driver.Bucket that also implements driver.Lister and
+// driver.Tagger.
+type taggerDriver struct {
+	*MockBucket
+	*MockLister
+	*MockTagger
+}
+
+func TestBucket_Tags(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	tagger := NewMockTagger(ctrl)
+	bucket := blob.NewBucketForTest(&taggerDriver{MockBucket: NewMockBucket(ctrl), MockTagger: tagger})
+
+	tags := map[string]string{"classification": "confidential", "framework": "iso-27001"}
+
+	tagger.EXPECT().SetTags(ctx, "key", tags).Return(nil)
+	tagger.EXPECT().GetTags(ctx, "key").Return(nil, nil)
+
+	assert.True(t, bucket.SupportsTags())
+	require.NoError(t, bucket.SetTags(ctx, "key", tags))
+
+	got, err := bucket.GetTags(ctx, "key")
+	require.NoError(t, err)
+	assert.NotNil(t, got)
+	assert.Empty(t, got)
+}
+
+func TestBucket_TagsNotImplemented(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bucket := blob.NewBucketForTest(NewMockBucket(ctrl))
+
+	assert.False(t, bucket.SupportsTags())
+
+	err := bucket.SetTags(context.Background(), "key", nil)
+	assert.True(t, kerr.Is(err, kerr.NotImplemented))
+
+	_, err = bucket.GetTags(context.Background(), "key")
+	assert.True(t, kerr.Is(err, kerr.NotImplemented))
+}
+
+func TestBucket_SetTagsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bucket := blob.NewBucketForTest(&taggerDriver{MockBucket: NewMockBucket(ctrl), MockTagger: NewMockTagger(ctrl)})
+
+	tooMany := map[string]string{}
+	for i := range blob.MaxTags + 1 {
+		tooMany[string(rune('a'+i))] = "v"
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		tags map[string]string
+	}{
+		{name: "empty key", tags: map[string]string{"a": "b"}},
+		{name: "too many tags", key: "key", tags: tooMany},
+		{name: "empty tag key", key: "key", tags: map[string]string{"": "v"}},
+		{name: "long tag key", key: "key", tags: map[string]string{strings.Repeat("k", blob.MaxTagKeyLength+1): "v"}},
+		{name: "long tag value", key: "key", tags: map[string]string{"k": strings.Repeat("v", blob.MaxTagValueLength+1)}},
+		{name: "invalid character", key: "key", tags: map[string]string{"k": "a'b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bucket.SetTags(context.Background(), tt.key, tt.tags)
+			assert.True(t, kerr.Is(err, kerr.InvalidArgument), err)
+		})
+	}
+}
+
+func TestBucket_List(t *testing.T) {
+	t.Run("passes tag filter", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		lister := NewMockLister(ctrl)
+		bucket := blob.NewBucketForTest(&taggerDriver{MockBucket: NewMockBucket(ctrl), MockLister: lister, MockTagger: NewMockTagger(ctrl)})
+
+		lister.EXPECT().
+			ListPaged(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+				assert.Equal(t, "evidence/", opts.Prefix)
+				assert.Equal(t, map[string]string{"status": "approved"}, opts.Tags)
+
+				return &driver.ListPage{
+					Objects:       []*driver.ListObject{{Key: "evidence/a"}},
+					NextPageToken: []byte("next"),
+				}, nil
+			})
+
+		page, err := bucket.List(context.Background(), &blob.ListOptions{Prefix: "evidence/", Tags: map[string]string{"status": "approved"}})
+		require.NoError(t, err)
+		require.Len(t, page.Objects, 1)
+		assert.Equal(t, "evidence/a", page.Objects[0].Key)
+		assert.Equal(t, []byte("next"), page.NextPageToken)
+	})
+
+	t.Run("tag filter not implemented", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		bucket := blob.NewBucketForTest(&listerDriver{MockBucket: NewMockBucket(ctrl), MockLister: NewMockLister(ctrl)})
+
+		_, err := bucket.List(context.Background(), &blob.ListOptions{Tags: map[string]string{"status": "approved"}})
+		assert.True(t, kerr.Is(err, kerr.NotImplemented))
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		bucket := blob.NewBucketForTest(NewMockBucket(ctrl))
+
+		_, err := bucket.List(context.Background(), nil)
+		assert.True(t, kerr.Is(err, kerr.NotImplemented))
+	})
+}
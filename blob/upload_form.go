@@ -0,0 +1,140 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"context"
+	"maps"
+	"mime"
+	"slices"
+	"time"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// UploadFormOptions sets options for SignedUploadForm.
+type UploadFormOptions struct {
+	// Expiry sets how long the upload form is valid for.
+	// Defaults to DefaultSignedURLExpiry.
+	Expiry time.Duration
+
+	// MaxSize is the maximum size of the upload in bytes. Zero means no
+	// limit. If the bucket has a storage quota, MaxSize is required.
+	MaxSize int64
+
+	// ContentTypes lists the permitted Content-Type values of the upload.
+	// Empty means any.
+	ContentTypes []string
+
+	// BeforeSign is a callback that will be called before the form is signed.
+	// asFunc converts its argument to driver-specific types.
+	BeforeSign func(asFunc func(any) bool) error
+}
+
+// UploadForm describes the request a browser sends to upload an object
+// directly to the storage service, without proxying the bytes through the
+// application.
+//
+// For Method "POST" the browser sends a multipart/form-data request to URL
+// with Fields as form fields, followed by the file as last part. For Method
+// "PUT" the browser sends the raw file to URL. In both cases Headers must be
+// set on the request.
+type UploadForm struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+
+	// EnforcesMaxSize reports whether the service itself rejects uploads
+	// larger than UploadFormOptions.MaxSize. If false, the size must be
+	// verified once the upload finished.
+	EnforcesMaxSize bool `json:"-"`
+	// EnforcesContentType reports whether the service itself rejects uploads
+	// with a Content-Type not listed in UploadFormOptions.ContentTypes. If
+	// false, the content type must be verified once the upload finished.
+	EnforcesContentType bool `json:"-"`
+}
+
+// SignedUploadForm returns an UploadForm that lets a browser upload the blob
+// stored at key directly to the service for the duration specified in
+// opts.Expiry. A nil UploadFormOptions is treated the same as the zero value.
+//
+// Not every service can enforce all conditions; check the Enforces fields of
+// the returned form and verify the uploaded object where they are false.
+//
+// If the driver does not support upload forms, SignedUploadForm returns an
+// error for which kerr.Code will return kerr.NotImplemented.
+func (b *Bucket) SignedUploadForm(ctx context.Context, key string, opts *UploadFormOptions) (*UploadForm, error) {
+	if err := validateSnapshotKey("SignedUploadForm", key); err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = &UploadFormOptions{}
+	}
+
+	dopts := &driver.UploadFormOptions{
+		Expiry:     opts.Expiry,
+		MaxSize:    opts.MaxSize,
+		BeforeSign: opts.BeforeSign,
+	}
+
+	switch {
+	case opts.Expiry < 0:
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: SignedUploadForm expiry must be non-negative: %v", opts.Expiry)
+	case opts.Expiry == 0:
+		dopts.Expiry = DefaultSignedURLExpiry
+	}
+
+	if opts.MaxSize < 0 {
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: SignedUploadForm MaxSize must be non-negative: %d", opts.MaxSize)
+	}
+
+	for _, ct := range opts.ContentTypes {
+		if _, _, err := mime.ParseMediaType(ct); err != nil {
+			return nil, kerr.Newf(kerr.InvalidArgument, err, "blob: SignedUploadForm invalid content type %q", ct)
+		}
+	}
+
+	dopts.ContentTypes = slices.Clone(opts.ContentTypes)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, errClosed
+	}
+
+	if b.quota != nil && opts.MaxSize == 0 {
+		return nil, kerr.Newf(kerr.InvalidArgument, nil, "blob: SignedUploadForm MaxSize is required for buckets with a storage quota")
+	}
+
+	if b.quota != nil {
+		if err := b.quota.check(ctx, b.b); err != nil {
+			return nil, err
+		}
+	}
+
+	s, ok := b.b.(driver.UploadFormSigner)
+	if !ok {
+		return nil, kerr.Newf(kerr.NotImplemented, nil, "blob: SignedUploadForm is not supported by this driver")
+	}
+
+	df, err := s.SignedUploadForm(ctx, key, dopts)
+	if err != nil {
+		return nil, wrapError(b.b, err, key)
+	}
+
+	return &UploadForm{
+		URL:                 df.URL,
+		Method:              df.Method,
+		Fields:              maps.Clone(df.Fields),
+		Headers:             maps.Clone(df.Headers),
+		ExpiresAt:           df.ExpiresAt,
+		EnforcesMaxSize:     df.EnforcesMaxSize,
+		EnforcesContentType: df.EnforcesContentType,
+	}, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// uploadFormDriver is a driver.Bucket that also implements driver.Lister and
+// driver.UploadFormSigner.
+type uploadFormDriver struct {
+	*MockBucket
+	*MockLister
+	*MockUploadFormSigner
+}
+
+func TestBucket_SignedUploadForm(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("passes conditions to driver", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		signer := NewMockUploadFormSigner(ctrl)
+		bucket := blob.NewBucketForTest(&uploadFormDriver{MockBucket: NewMockBucket(ctrl), MockUploadFormSigner: signer})
+
+		expiresAt := time.Now().Add(blob.DefaultSignedURLExpiry)
+
+		signer.EXPECT().
+			SignedUploadForm(ctx, "evidence/report.pdf", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, opts *driver.UploadFormOptions) (*driver.UploadForm, error) {
+				assert.Equal(t, blob.DefaultSignedURLExpiry, opts.Expiry)
+				assert.Equal(t, int64(1<<20), opts.MaxSize)
+				assert.Equal(t, []string{"application/pdf"}, opts.ContentTypes)
+
+				return &driver.UploadForm{
+					URL:             "https://storage.example/evidence/report.pdf",
+					Method:          http.MethodPost,
+					Fields:          map[string]string{"policy": "p"},
+					ExpiresAt:       expiresAt,
+					EnforcesMaxSize: true,
+				}, nil
+			})
+
+		form, err := bucket.SignedUploadForm(ctx, "evidence/report.pdf", &blob.UploadFormOptions{
+			MaxSize:      1 << 20,
+			ContentTypes: []string{"application/pdf"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.MethodPost, form.Method)
+		assert.Equal(t, map[string]string{"policy": "p"}, form.Fields)
+		assert.Equal(t, expiresAt, form.ExpiresAt)
+		assert.True(t, form.EnforcesMaxSize)
+		assert.False(t, form.EnforcesContentType)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		bucket := blob.NewBucketForTest(&uploadFormDriver{MockBucket: NewMockBucket(ctrl), MockUploadFormSigner: NewMockUploadFormSigner(ctrl)})
+
+		for _, opts := range []*blob.UploadFormOptions{
+			{Expiry: -time.Second},
+			{MaxSize: -1},
+			{ContentTypes: []string{"not a type"}},
+		} {
+			_, err := bucket.SignedUploadForm(ctx, "key", opts)
+			assert.True(t, kerr.Is(err, kerr.InvalidArgument), err)
+		}
+	})
+
+	t.Run("quota requires max size", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		bucket := blob.NewBucketForTest(&uploadFormDriver{MockBucket: NewMockBucket(ctrl), MockUploadFormSigner: NewMockUploadFormSigner(ctrl)})
+
+		q, err := blob.NewQuotaEnforcer(blob.QuotaConfig{Limit: 100})
+		require.NoError(t, err)
+		blob.SetQuotaForTest(bucket, q)
+
+		_, err = bucket.SignedUploadForm(ctx, "key", nil)
+		assert.True(t, kerr.Is(err, kerr.InvalidArgument), err)
+	})
+
+	t.Run("quota exhausted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		lister := NewMockLister(ctrl)
+		bucket := blob.NewBucketForTest(&uploadFormDriver{MockBucket: NewMockBucket(ctrl), MockLister: lister, MockUploadFormSigner: NewMockUploadFormSigner(ctrl)})
+
+		q, err := blob.NewQuotaEnforcer(blob.QuotaConfig{Limit: 100})
+		require.NoError(t, err)
+		blob.SetQuotaForTest(bucket, q)
+
+		expectPages(lister, &driver.ListPage{Objects: []*driver.ListObject{{Key: "existing", Size: 100}}})
+
+		_, err = bucket.SignedUploadForm(ctx, "key", &blob.UploadFormOptions{MaxSize: 10})
+		assert.True(t, kerr.Is(err, kerr.QuotaExceeded), err)
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		bucket := blob.NewBucketForTest(NewMockBucket(ctrl))
+
+		_, err := bucket.SignedUploadForm(ctx, "key", nil)
+		assert.True(t, kerr.Is(err, kerr.NotImplemented))
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+const (
+	// DefaultUsagePageSize is the number of objects requested per page when
+	// Usage walks the bucket.
+	DefaultUsagePageSize = 1000
+	// DefaultQuotaUsageTTL is how long a QuotaEnforcer trusts a measured
+	// usage before listing the bucket again.
+	DefaultQuotaUsageTTL = time.Minute
+)
+
+// ErrInvalidQuota is returned by New when Config.SpaceQuota is invalid.
+var ErrInvalidQuota = errors.New("blob: space quota limit must be positive")
+
+// Usage returns the number of objects stored in the bucket and their total
+// size in bytes. The bucket is walked page by page, so memory usage does not
+// grow with the number of objects.
+//
+// If the driver does not support listing, Usage returns an error for which
+// kerr.Code will return kerr.NotImplemented.
+func (b *Bucket) Usage(ctx context.Context) (objectCount, totalBytes int64, err error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return 0, 0, errClosed
+	}
+
+	return usage(ctx, b.b)
+}
+
+// usage sums up the objects of db. The caller must hold the bucket lock.
+func usage(ctx context.Context, db driver.Bucket) (objectCount, totalBytes int64, err error) {
+	l, ok := db.(driver.Lister)
+	if !ok {
+		return 0, 0, kerr.Newf(kerr.NotImplemented, nil, "blob: Usage is not supported by this driver")
+	}
+
+	opts := &driver.ListOptions{PageSize: DefaultUsagePageSize}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+
+		page, err := l.ListPaged(ctx, opts)
+		if err != nil {
+			return 0, 0, wrapError(db, err, "")
+		}
+
+		for _, obj := range page.Objects {
+			objectCount++
+			totalBytes += obj.Size
+		}
+
+		if len(page.NextPageToken) == 0 {
+			return objectCount, totalBytes, nil
+		}
+
+		opts.PageToken = page.NextPageToken
+	}
+}
+
+// QuotaConfig configures the storage quota applied to space buckets.
+type QuotaConfig struct {
+	// Limit is the maximum number of bytes a single space may store.
+	Limit int64
+
+	// UsageTTL is how long a measured usage is reused before the bucket is
+	// listed again. Defaults to DefaultQuotaUsageTTL.
+	UsageTTL time.Duration
+}
+
+func (c *QuotaConfig) validate() error {
+	if c.Limit <= 0 {
+		return fmt.Errorf("%w (%d)", ErrInvalidQuota, c.Limit)
+	}
+
+	return nil
+}
+
+// QuotaEnforcer rejects writes that would grow a bucket beyond a byte limit.
+//
+// The current usage is measured with Bucket.Usage and cached for the
+// configured TTL. Bytes of writes in progress are reserved so that concurrent
+// writers sharing an enforcer cannot exceed the limit together. Overwriting an
+// existing object is counted as growth until the next measurement.
+//
+// A QuotaEnforcer is safe for concurrent use.
+type QuotaEnforcer struct {
+	limit int64
+	ttl   time.Duration
+
+	mu         sync.Mutex
+	used       int64
+	reserved   int64
+	measuredAt time.Time
+}
+
+// NewQuotaEnforcer returns a QuotaEnforcer for the given configuration.
+func NewQuotaEnforcer(cfg QuotaConfig) (*QuotaEnforcer, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	ttl := cfg.UsageTTL
+	if ttl <= 0 {
+		ttl = DefaultQuotaUsageTTL
+	}
+
+	return &QuotaEnforcer{limit: cfg.Limit, ttl: ttl}, nil
+}
+
+// Limit returns the configured byte limit.
+func (q *QuotaEnforcer) Limit() int64 {
+	return q.limit
+}
+
+// check refreshes the usage of db if it is stale and fails if the quota is
+// already exhausted.
+func (q *QuotaEnforcer) check(ctx context.Context, db driver.Bucket) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.measuredAt.IsZero() || time.Since(q.measuredAt) >= q.ttl {
+		_, total, err := usage(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		q.used = total
+		q.measuredAt = time.Now()
+	}
+
+	if q.used+q.reserved >= q.limit {
+		return q.exceeded()
+	}
+
+	return nil
+}
+
+// reserve reserves n bytes for a write in progress.
+func (q *QuotaEnforcer) reserve(n int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.used+q.reserved+n > q.limit {
+		return q.exceeded()
+	}
+
+	q.reserved += n
+
+	return nil
+}
+
+// commit turns n reserved bytes into used bytes after a successful write.
+func (q *QuotaEnforcer) commit(n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reserved -= n
+	q.used += n
+}
+
+// release drops n reserved bytes after a failed write.
+func (q *QuotaEnforcer) release(n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reserved -= n
+}
+
+func (q *QuotaEnforcer) exceeded() error {
+	return kerr.NewQuotaExceeded(fmt.Sprintf("blob: storage quota of %d bytes exceeded", q.limit))
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/kopexa-grc/common/blob"
+	"github.com/kopexa-grc/common/blob/driver"
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// listerDriver is a driver.Bucket that also implements driver.Lister.
+type listerDriver struct {
+	*MockBucket
+	*MockLister
+}
+
+func expectPages(lister *MockLister, pages ...*driver.ListPage) {
+	calls := make([]any, 0, len(pages))
+
+	for _, page := range pages {
+		calls = append(calls, lister.EXPECT().ListPaged(gomock.Any(), gomock.Any()).Return(page, nil))
+	}
+
+	gomock.InOrder(calls...)
+}
+
+func TestBucket_Usage(t *testing.T) {
+	t.Run("sums all pages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		lister := NewMockLister(ctrl)
+		bucket := blob.NewBucketForTest(&listerDriver{MockBucket: NewMockBucket(ctrl), MockLister: lister})
+
+		first := lister.EXPECT().
+			ListPaged(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+				assert.Empty(t, opts.PageToken)
+				assert.Equal(t, blob.DefaultUsagePageSize, opts.PageSize)
+
+				return &driver.ListPage{
+					Objects:       []*driver.ListObject{{Key: "a", Size: 10}, {Key: "b", Size: 20}},
+					NextPageToken: []byte("next"),
+				}, nil
+			})
+		lister.EXPECT().
+			ListPaged(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+				assert.Equal(t, []byte("next"), opts.PageToken)
+
+				return &driver.ListPage{Objects: []*driver.ListObject{{Key: "c", Size: 5}}}, nil
+			}).
+			After(first)
+
+		count, total, err := bucket.Usage(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+		assert.Equal(t, int64(35), total)
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		bucket := blob.NewBucketForTest(NewMockBucket(ctrl))
+
+		_, _, err := bucket.Usage(context.Background())
+		assert.True(t, kerr.Is(err, kerr.NotImplemented))
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		bucket := blob.NewBucketForTest(&listerDriver{MockBucket: NewMockBucket(ctrl), MockLister: NewMockLister(ctrl)})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := bucket.Usage(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestNewQuotaEnforcer(t *testing.T) {
+	_, err := blob.NewQuotaEnforcer(blob.QuotaConfig{})
+	require.ErrorIs(t, err, blob.ErrInvalidQuota)
+
+	q, err := blob.NewQuotaEnforcer(blob.QuotaConfig{Limit: 100})
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), q.Limit())
+}
+
+func TestBucket_Quota(t *testing.T) {
+	newBucket := func(t *testing.T, limit int64, used int64) (*blob.Bucket, *MockBucket) {
+		t.Helper()
+
+		ctrl := gomock.NewController(t)
+		mockDriver := NewMockBucket(ctrl)
+		lister := NewMockLister(ctrl)
+		bucket := blob.NewBucketForTest(&listerDriver{MockBucket: mockDriver, MockLister: lister})
+
+		q, err := blob.NewQuotaEnforcer(blob.QuotaConfig{Limit: limit})
+		require.NoError(t, err)
+		blob.SetQuotaForTest(bucket, q)
+
+		expectPages(lister, &driver.ListPage{Objects: []*driver.ListObject{{Key: "existing", Size: used}}})
+
+		return bucket, mockDriver
+	}
+
+	t.Run("write within quota", func(t *testing.T) {
+		bucket, mockDriver := newBucket(t, 100, 50)
+		ctrl := gomock.NewController(t)
+		dw := NewMockWriter(ctrl)
+
+		mockDriver.EXPECT().NewTypedWriter(gomock.Any(), "key", "text/plain", gomock.Any()).Return(dw, nil).Times(2)
+		dw.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) { return len(p), nil }).Times(2)
+		dw.EXPECT().Close().Return(nil).Times(2)
+
+		err := bucket.Upload(context.Background(), "key", bytes.NewReader(make([]byte, 30)), &blob.WriterOptions{ContentType: "text/plain"})
+		require.NoError(t, err)
+
+		// The committed 30 bytes leave room for exactly 20 more.
+		err = bucket.Upload(context.Background(), "key", bytes.NewReader(make([]byte, 20)), &blob.WriterOptions{ContentType: "text/plain"})
+		require.NoError(t, err)
+
+		_, err = bucket.NewWriter(context.Background(), "key", &blob.WriterOptions{ContentType: "text/plain"})
+		assert.True(t, kerr.IsQuotaExceeded(err))
+	})
+
+	t.Run("rejects new writer when exhausted", func(t *testing.T) {
+		bucket, _ := newBucket(t, 100, 100)
+
+		_, err := bucket.NewWriter(context.Background(), "key", &blob.WriterOptions{ContentType: "text/plain"})
+		assert.True(t, kerr.IsQuotaExceeded(err))
+	})
+
+	t.Run("aborts write exceeding quota", func(t *testing.T) {
+		bucket, mockDriver := newBucket(t, 100, 90)
+		ctrl := gomock.NewController(t)
+		dw := NewMockWriter(ctrl)
+
+		mockDriver.EXPECT().NewTypedWriter(gomock.Any(), "key", "text/plain", gomock.Any()).Return(dw, nil)
+		dw.EXPECT().Close().Return(nil)
+
+		w, err := bucket.NewWriter(context.Background(), "key", &blob.WriterOptions{ContentType: "text/plain"})
+		require.NoError(t, err)
+
+		_, err = w.Write(make([]byte, 11))
+		assert.True(t, kerr.IsQuotaExceeded(err))
+		assert.True(t, kerr.IsQuotaExceeded(w.Close()))
+	})
+}
@@ -28,6 +28,17 @@ type Writer struct {
 	contentMD5 []byte
 	md5hash    hash.Hash
 
+	// quota is non-nil if the bucket enforces a storage quota. reserved is
+	// the number of bytes reserved with it, quotaErr is set once a Write
+	// exceeded the quota and the write was aborted.
+	quota    *QuotaEnforcer
+	reserved int64
+	quotaErr error
+
+	// image is set if the Writer processes its content with an
+	// ImagePipeline; all other fields except b and key are unused then.
+	image *imageWriter
+
 	// Metric collection fields
 	bytesWrittenCounter metric.Int64Counter
 	bytesWritten        int
@@ -56,6 +67,16 @@ const sniffLen = 512
 // even if the actual write eventually fails. The write is only guaranteed to
 // have succeeded if Close returns no error.
 func (w *Writer) Write(p []byte) (int, error) {
+	if w.image != nil {
+		return w.image.write(p)
+	}
+
+	if w.quota != nil {
+		if err := w.reserve(len(p)); err != nil {
+			return 0, err
+		}
+	}
+
 	if len(w.contentMD5) > 0 {
 		if _, err := w.md5hash.Write(p); err != nil {
 			return 0, err
@@ -99,10 +120,22 @@ func (w *Writer) Write(p []byte) (int, error) {
 func (w *Writer) Close() (err error) {
 	w.closed = true
 
+	if w.image != nil {
+		return w.image.close()
+	}
+
 	// Store context before it might be set to nil in open()
 	ctx := w.ctx
 
 	defer func() {
+		if w.quota != nil {
+			if err == nil {
+				w.quota.commit(w.reserved)
+			} else {
+				w.quota.release(w.reserved)
+			}
+		}
+
 		if w.end != nil {
 			w.end(err)
 		}
@@ -115,6 +148,16 @@ func (w *Writer) Close() (err error) {
 		}
 	}()
 
+	if w.quotaErr != nil {
+		// The context was canceled when the quota was exceeded, so closing
+		// the driver's writer aborts the write.
+		if w.w != nil {
+			_ = w.w.Close()
+		}
+
+		return w.quotaErr
+	}
+
 	if len(w.contentMD5) > 0 {
 		// Verify the MD5 hash of what was written matches the ContentMD5 provided
 		// by the user.
@@ -145,6 +188,25 @@ func (w *Writer) Close() (err error) {
 	return wrapError(w.b, w.w.Close(), w.key)
 }
 
+// reserve reserves n bytes with the quota. If the quota is exceeded, the
+// write is aborted and all further calls fail.
+func (w *Writer) reserve(n int) error {
+	if w.quotaErr != nil {
+		return w.quotaErr
+	}
+
+	if err := w.quota.reserve(int64(n)); err != nil {
+		w.quotaErr = err
+		w.cancel()
+
+		return err
+	}
+
+	w.reserved += int64(n)
+
+	return nil
+}
+
 // open tries to detect the MIME type of p and write it to the blob.
 // The error it returns is wrapped.
 func (w *Writer) open(p []byte) (int, error) {
@@ -200,8 +262,8 @@ func (w *Writer) uploadAndClose(r io.Reader) (err error) {
 		// Shouldn't happen.
 		return kerr.Newf(kerr.UnexpectedFailure, nil, "blob: uploadAndClose must be the first write")
 	}
-	// When ContentMD5 is being checked, we can't use Upload.
-	if len(w.contentMD5) > 0 {
+	// When ContentMD5 or a quota is being checked, we can't use Upload.
+	if len(w.contentMD5) > 0 || w.quota != nil {
 		_, err = w.ReadFrom(r)
 	} else {
 		driverUploader, ok := w.w.(driver.Uploader)
@@ -0,0 +1,76 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// Details keys used by FromContextErrorWithBudget.
+const (
+	// DetailDeadline is the context deadline in RFC 3339 format.
+	DetailDeadline = "deadline"
+	// DetailBudgetMs is the total budget in milliseconds, from StartBudget to the deadline.
+	DetailBudgetMs = "budget_ms"
+	// DetailElapsedMs is the time spent since StartBudget in milliseconds.
+	DetailElapsedMs = "elapsed_ms"
+	// DetailRemainingMs is the budget left until the deadline in milliseconds.
+	// It is negative if the deadline has already passed.
+	DetailRemainingMs = "remaining_ms"
+)
+
+// budgetStartKey is the context key for the start of the time budget.
+type budgetStartKey struct{}
+
+// StartBudget records the current time as the start of the request's time
+// budget. It is typically called by middleware at the beginning of a request,
+// before any deadline is applied. Calling it again on a derived context keeps
+// the original start.
+func StartBudget(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(budgetStartKey{}).(time.Time); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, budgetStartKey{}, time.Now())
+}
+
+// FromContextErrorWithBudget converts err like FromContextError and records
+// the deadline of ctx, the time elapsed since StartBudget and the remaining
+// budget in Details. Values that are unknown, e.g. because ctx has no
+// deadline or StartBudget was not called, are omitted.
+//
+// Example:
+//
+//	if err := downstream.Call(ctx); err != nil {
+//	    return errors.FromContextErrorWithBudget(ctx, err)
+//	}
+func FromContextErrorWithBudget(ctx context.Context, err error) *Error {
+	e := FromContextError(err)
+	if e == nil {
+		return nil
+	}
+
+	if e.Err == nil {
+		e.Err = err
+	}
+
+	now := time.Now()
+	start, hasStart := ctx.Value(budgetStartKey{}).(time.Time)
+
+	if hasStart {
+		e.WithDetails(DetailElapsedMs, now.Sub(start).Milliseconds())
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		e.WithDetails(DetailDeadline, deadline.UTC().Format(time.RFC3339Nano))
+		e.WithDetails(DetailRemainingMs, deadline.Sub(now).Milliseconds())
+
+		if hasStart {
+			e.WithDetails(DetailBudgetMs, deadline.Sub(start).Milliseconds())
+		}
+	}
+
+	return e
+}
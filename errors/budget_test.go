@@ -0,0 +1,81 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContextErrorWithBudget(t *testing.T) {
+	ctx := StartBudget(context.Background())
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+
+	<-ctx.Done()
+
+	e := FromContextErrorWithBudget(ctx, ctx.Err())
+	require.NotNil(t, e)
+
+	assert.Equal(t, DeadlineExceeded, e.Code)
+	assert.True(t, errors.Is(e, context.DeadlineExceeded))
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, deadline.UTC().Format(time.RFC3339Nano), e.Details[DetailDeadline])
+
+	assert.GreaterOrEqual(t, e.Details[DetailElapsedMs], int64(20))
+	assert.LessOrEqual(t, e.Details[DetailRemainingMs], int64(0))
+	assert.InDelta(t, 20, e.Details[DetailBudgetMs], 5)
+}
+
+func TestFromContextErrorWithBudget_RemainingBudget(t *testing.T) {
+	ctx, cancel := context.WithTimeout(StartBudget(context.Background()), time.Hour)
+	defer cancel()
+
+	e := FromContextErrorWithBudget(ctx, errors.New("downstream failed"))
+	require.NotNil(t, e)
+
+	assert.Equal(t, UnexpectedFailure, e.Code)
+	assert.Greater(t, e.Details[DetailRemainingMs], int64(59*60*1000))
+}
+
+func TestFromContextErrorWithBudget_Partial(t *testing.T) {
+	t.Run("no start", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		e := FromContextErrorWithBudget(ctx, context.DeadlineExceeded)
+		assert.Contains(t, e.Details, DetailDeadline)
+		assert.Contains(t, e.Details, DetailRemainingMs)
+		assert.NotContains(t, e.Details, DetailElapsedMs)
+		assert.NotContains(t, e.Details, DetailBudgetMs)
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		e := FromContextErrorWithBudget(StartBudget(context.Background()), context.Canceled)
+		assert.Equal(t, RequestTimeout, e.Code)
+		assert.Contains(t, e.Details, DetailElapsedMs)
+		assert.NotContains(t, e.Details, DetailDeadline)
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, FromContextErrorWithBudget(context.Background(), nil))
+	})
+}
+
+func TestStartBudget_KeepsOriginalStart(t *testing.T) {
+	ctx := StartBudget(context.Background())
+	start := ctx.Value(budgetStartKey{})
+
+	time.Sleep(time.Millisecond)
+
+	assert.Equal(t, start, StartBudget(ctx).Value(budgetStartKey{}))
+}
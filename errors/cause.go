@@ -0,0 +1,131 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+)
+
+// DefaultCauseDepth is the number of causes collected when no other depth is
+// configured with SetCauseDepth.
+const DefaultCauseDepth = 10
+
+// CauseSummary describes a single error in the cause chain of an Error.
+type CauseSummary struct {
+	// Code is the error code if the cause is an Error.
+	Code ErrorCode `json:"code,omitempty"`
+	// Message is the message of the cause.
+	Message string `json:"message"`
+	// Type is the Go type of the cause (e.g., "*fs.PathError").
+	Type string `json:"type"`
+}
+
+var (
+	verboseCauses atomic.Bool
+	causeDepth    atomic.Int64
+)
+
+func init() {
+	causeDepth.Store(DefaultCauseDepth)
+}
+
+// SetVerbose enables or disables the serialization of the cause chain. When
+// enabled, Errors marshaled to JSON include their causes (see Causes).
+// Causes may contain internal details such as file paths or SQL, so this is
+// meant for development and internal APIs.
+func SetVerbose(enabled bool) {
+	verboseCauses.Store(enabled)
+}
+
+// Verbose reports whether the cause chain is serialized.
+func Verbose() bool {
+	return verboseCauses.Load()
+}
+
+// SetCauseDepth sets the maximum number of causes collected for serialization.
+// Values below one restore DefaultCauseDepth.
+func SetCauseDepth(depth int) {
+	if depth < 1 {
+		depth = DefaultCauseDepth
+	}
+
+	causeDepth.Store(int64(depth))
+}
+
+// Causes walks the chain of err via Unwrap and summarizes up to maxDepth
+// errors, starting with err itself. Errors joined with errors.Join or
+// wrapped with multiple %w verbs are visited depth first.
+func Causes(err error, maxDepth int) []CauseSummary {
+	if err == nil || maxDepth < 1 {
+		return nil
+	}
+
+	causes := make([]CauseSummary, 0, min(maxDepth, DefaultCauseDepth))
+	stack := []error{err}
+
+	for len(stack) > 0 && len(causes) < maxDepth {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if cur == nil {
+			continue
+		}
+
+		causes = append(causes, summarize(cur))
+
+		switch u := cur.(type) {
+		case interface{ Unwrap() []error }:
+			children := u.Unwrap()
+			for i := len(children) - 1; i >= 0; i-- {
+				stack = append(stack, children[i])
+			}
+		case interface{ Unwrap() error }:
+			stack = append(stack, u.Unwrap())
+		}
+	}
+
+	return causes
+}
+
+// summarize describes a single error of the chain.
+func summarize(err error) CauseSummary {
+	s := CauseSummary{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+	}
+
+	if e, ok := err.(*Error); ok {
+		s.Code = e.Code
+	}
+
+	return s
+}
+
+// WithCauses records the cause chain of the underlying error, up to maxDepth
+// entries, in Causes. It is serialized only when verbose mode is enabled.
+func (e *Error) WithCauses(maxDepth int) *Error {
+	e.Causes = Causes(e.Err, maxDepth)
+	return e
+}
+
+// MarshalJSON implements json.Marshaler. The cause chain is included only in
+// verbose mode; it is collected from the underlying error if WithCauses has
+// not been called. The severity defaults to EffectiveSeverity.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	type plain Error
+
+	out := plain(*e)
+	out.Severity = e.EffectiveSeverity()
+
+	switch {
+	case !Verbose():
+		out.Causes = nil
+	case out.Causes == nil:
+		out.Causes = Causes(e.Err, int(causeDepth.Load()))
+	}
+
+	return json.Marshal(out)
+}
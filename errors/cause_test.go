@@ -0,0 +1,88 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCauses(t *testing.T) {
+	root := &fs.PathError{Op: "open", Path: "/etc/app.yaml", Err: fs.ErrNotExist}
+	wrapped := fmt.Errorf("load config: %w", root)
+	e := NewNotFound("config missing").With(wrapped)
+
+	causes := Causes(e, DefaultCauseDepth)
+	require.Len(t, causes, 4)
+
+	assert.Equal(t, CauseSummary{Code: NotFound, Message: "config missing", Type: "*errors.Error"}, causes[0])
+	assert.Equal(t, "*fmt.wrapError", causes[1].Type)
+	assert.Equal(t, "load config: open /etc/app.yaml: file does not exist", causes[1].Message)
+	assert.Equal(t, "*fs.PathError", causes[2].Type)
+	assert.Empty(t, causes[2].Code)
+	assert.Equal(t, "file does not exist", causes[3].Message)
+
+	assert.Len(t, Causes(e, 2), 2)
+	assert.Nil(t, Causes(nil, DefaultCauseDepth))
+	assert.Nil(t, Causes(e, 0))
+}
+
+func TestCausesJoined(t *testing.T) {
+	first := NewConflict("duplicate")
+	second := errors.New("second")
+
+	causes := Causes(errors.Join(first, second), DefaultCauseDepth)
+	require.Len(t, causes, 3)
+
+	assert.Equal(t, "*errors.joinError", causes[0].Type)
+	assert.Equal(t, Conflict, causes[1].Code)
+	assert.Equal(t, "second", causes[2].Message)
+}
+
+func TestMarshalJSONCauses(t *testing.T) {
+	t.Cleanup(func() {
+		SetVerbose(false)
+		SetCauseDepth(0)
+	})
+
+	e := NewUnexpectedFailure("failed").With(fmt.Errorf("query: %w", errors.New("connection reset")))
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "causes")
+
+	SetVerbose(true)
+	assert.True(t, Verbose())
+
+	data, err = json.Marshal(e)
+	require.NoError(t, err)
+
+	var decoded Error
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded.Causes, 2)
+	assert.Equal(t, "query: connection reset", decoded.Causes[0].Message)
+	assert.Equal(t, "connection reset", decoded.Causes[1].Message)
+	assert.Nil(t, e.Causes, "marshaling must not modify the error")
+
+	SetCauseDepth(1)
+
+	data, err = json.Marshal(e)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Causes, 1)
+
+	// Explicitly recorded causes take precedence over the configured depth.
+	data, err = json.Marshal(e.WithCauses(5))
+	require.NoError(t, err)
+
+	decoded = Error{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Causes, 2)
+}
@@ -31,4 +31,5 @@ const (
 	msgDeadlineExceeded   = "Deadline Exceeded"
 	msgRequestTimeout     = "Request Timeout"
 	msgOutOfRange         = "Out Of Range"
+	msgValidationFailed   = "Validation Failed"
 )
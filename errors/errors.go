@@ -16,6 +16,8 @@ type Error struct {
 	Code ErrorCode `json:"code" validate:"required"`
 	// Category is the category of the error (e.g., client, server, auth).
 	Category ErrorCategory `json:"category" validate:"required"`
+	// Severity classifies the urgency of the error (info, warn, error, critical).
+	Severity Severity `json:"severity,omitempty"`
 	// Status is the HTTP status code associated with the error.
 	Status int `json:"status" validate:"required"`
 	// Message is a human-readable error message.
@@ -28,15 +30,21 @@ type Error struct {
 	Timestamp time.Time `json:"timestamp"`
 	// Details contains additional error details.
 	Details map[string]interface{} `json:"details,omitempty"`
+	// Causes summarizes the chain of underlying errors. It is only serialized
+	// in verbose mode (see SetVerbose).
+	Causes []CauseSummary `json:"causes,omitempty"`
 	// Err is the underlying error.
 	Err error `json:"-"`
 }
 
 // New creates a new Error.
 func New(code ErrorCode, message string) *Error {
+	category := getCategoryForCode(code)
+
 	return &Error{
 		Code:      code,
-		Category:  getCategoryForCode(code),
+		Category:  category,
+		Severity:  getSeverityForCategory(category),
 		Message:   message,
 		Timestamp: time.Now(),
 		Details:   make(map[string]interface{}),
@@ -126,7 +134,9 @@ func (e *Error) With(err error) *Error {
 	return e
 }
 
-// Wrap wraps an error with a message.
+// Wrap wraps an error with a message. The result is always an
+// UnexpectedFailure, also if err already is an *Error; layers that only pass
+// errors through should use WrapPreserve to keep the code.
 func Wrap(err error, message string) *Error {
 	return &Error{
 		Code:    UnexpectedFailure,
@@ -136,6 +146,96 @@ func Wrap(err error, message string) *Error {
 	}
 }
 
+// WrapPreserve wraps err with a message like Wrap, but keeps the code,
+// category, severity, status, entity and details of the most specific *Error
+// in the chain of err: the first one, depth first, that is not an
+// UnexpectedFailure. The message is prepended to the message of that error,
+// and err stays the underlying error.
+//
+// Without an *Error in the chain, WrapPreserve behaves like Wrap. Returns nil
+// for nil.
+//
+// Example:
+//
+//	if err := s.repo.Save(ctx, doc); err != nil {
+//	    return errors.WrapPreserve(err, "save document") // stays a Conflict
+//	}
+func WrapPreserve(err error, message string) *Error {
+	if err == nil {
+		return nil
+	}
+
+	inner := mostSpecific(err)
+	if inner == nil {
+		return Wrap(err, message)
+	}
+
+	details := make(map[string]interface{}, len(inner.Details))
+	for k, v := range inner.Details {
+		details[k] = v
+	}
+
+	if inner.Message != "" {
+		message += ": " + inner.Message
+	}
+
+	return &Error{
+		Code:      inner.Code,
+		Category:  inner.Category,
+		Severity:  inner.Severity,
+		Status:    inner.Status,
+		Message:   message,
+		Entity:    inner.Entity,
+		RequestID: inner.RequestID,
+		Timestamp: inner.Timestamp,
+		Details:   details,
+		Err:       err,
+	}
+}
+
+// mostSpecific returns the first *Error in the chain of err, depth first,
+// that is not an UnexpectedFailure, or else the first *Error, or nil.
+func mostSpecific(err error) *Error {
+	var fallback *Error
+
+	var walk func(err error) *Error
+
+	walk = func(err error) *Error {
+		if err == nil {
+			return nil
+		}
+
+		if e, ok := err.(*Error); ok {
+			if e.Code != UnexpectedFailure {
+				return e
+			}
+
+			if fallback == nil {
+				fallback = e
+			}
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			return walk(u.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if found := walk(e); found != nil {
+					return found
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if e := walk(err); e != nil {
+		return e
+	}
+
+	return fallback
+}
+
 // IsError checks if the error is an Error.
 func IsError(err error) bool {
 	_, ok := err.(*Error)
@@ -6,6 +6,7 @@ package errors
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -114,6 +115,58 @@ func TestWrap(t *testing.T) {
 	}
 }
 
+func TestWrapPreserve(t *testing.T) {
+	inner := NewNotFound("document not found").WithEntity("document").WithDetails("id", "d1")
+
+	// pass-through layers keep the code; Wrap would downgrade it
+	if got := Wrap(inner, "load document"); got.Code != UnexpectedFailure {
+		t.Errorf("Wrap() code = %v, want %v", got.Code, UnexpectedFailure)
+	}
+
+	err := WrapPreserve(fmt.Errorf("repository: %w", inner), "load document")
+	if err.Code != NotFound || err.Status != http.StatusNotFound || err.Category != CategoryClient {
+		t.Errorf("WrapPreserve() = %v/%v/%v, want NotFound/404/client", err.Code, err.Status, err.Category)
+	}
+
+	if err.Message != "load document: document not found" {
+		t.Errorf("WrapPreserve() message = %q", err.Message)
+	}
+
+	if err.Entity != "document" || err.Details["id"] != "d1" {
+		t.Errorf("WrapPreserve() entity = %q, details = %v", err.Entity, err.Details)
+	}
+
+	err.WithDetails("extra", true)
+
+	if _, ok := inner.Details["extra"]; ok {
+		t.Error("WrapPreserve() must not share details with the inner error")
+	}
+
+	if !errors.Is(err, inner) {
+		t.Error("WrapPreserve() must keep the chain")
+	}
+
+	// the most specific code wins over an UnexpectedFailure wrapper
+	err = WrapPreserve(Wrap(NewConflict("duplicate"), "save"), "create")
+	if err.Code != Conflict || err.Message != "create: duplicate" {
+		t.Errorf("WrapPreserve() = %v %q, want Conflict", err.Code, err.Message)
+	}
+
+	err = WrapPreserve(errors.Join(errTest, NewForbidden("denied")), "check")
+	if err.Code != Forbidden {
+		t.Errorf("WrapPreserve() code = %v, want %v", err.Code, Forbidden)
+	}
+
+	err = WrapPreserve(errUnderlying, "plain")
+	if err.Code != UnexpectedFailure || err.Message != "plain" {
+		t.Errorf("WrapPreserve() = %v %q, want UnexpectedFailure like Wrap", err.Code, err.Message)
+	}
+
+	if WrapPreserve(nil, "nothing") != nil {
+		t.Error("WrapPreserve(nil) must return nil")
+	}
+}
+
 func TestIsError(t *testing.T) {
 	err := New(BadRequest, "test error")
 	if !IsError(err) {
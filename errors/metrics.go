@@ -0,0 +1,178 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kopexa-grc/common/wellknown"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Label values used for errors that would otherwise create unbounded
+// metric cardinality.
+const (
+	// MetricCodeOther replaces error codes that are not allowed as label.
+	MetricCodeOther = "OTHER"
+	// MetricCategoryOther replaces unknown categories.
+	MetricCategoryOther = "other"
+	// MetricStatusOther replaces status codes outside 100-599.
+	MetricStatusOther = "other"
+)
+
+// MetricLabels are the labels an error is counted with.
+type MetricLabels struct {
+	Code     string
+	Category string
+	Status   string
+}
+
+// ErrorRecorder counts errors, e.g. to alert on per-code error rates.
+type ErrorRecorder interface {
+	RecordError(ctx context.Context, labels MetricLabels)
+}
+
+var (
+	recorder atomic.Pointer[ErrorRecorder]
+
+	metricCodesMu sync.RWMutex
+	metricCodes   = map[ErrorCode]struct{}{
+		BadRequest: {}, Unauthorized: {}, Forbidden: {}, NotFound: {}, Conflict: {}, Gone: {},
+		UnprocessableEntity: {}, TooManyRequests: {},
+		UnexpectedFailure: {}, NotImplemented: {}, ServiceUnavailable: {}, GatewayTimeout: {},
+		ResourceExhausted: {}, QuotaExceeded: {}, SpaceNotFound: {},
+		NoAuthorization: {}, InvalidCredentials: {}, TokenExpired: {},
+		ConnectionFailed: {}, ConnectionTimeout: {}, ConnectionRefused: {},
+		DeadlineExceeded: {}, RequestTimeout: {},
+		InvalidArgument: {}, FailedPrecondition: {}, OutOfRange: {},
+	}
+
+	metricCategories = map[ErrorCategory]struct{}{
+		CategoryClient: {}, CategoryServer: {}, CategoryResource: {},
+		CategoryAuth: {}, CategoryNetwork: {}, CategoryTimeout: {},
+	}
+)
+
+// SetRecorder installs the recorder used by RecordError and WriteHTTP.
+// Passing nil disables recording.
+func SetRecorder(r ErrorRecorder) {
+	if r == nil {
+		recorder.Store(nil)
+		return
+	}
+
+	recorder.Store(&r)
+}
+
+// AllowMetricCodes adds custom error codes to the codes reported as metric
+// label. All codes defined by this package are allowed; other codes are
+// reported as MetricCodeOther to keep the number of series bounded.
+func AllowMetricCodes(codes ...ErrorCode) {
+	metricCodesMu.Lock()
+	defer metricCodesMu.Unlock()
+
+	for _, code := range codes {
+		metricCodes[code] = struct{}{}
+	}
+}
+
+// MetricLabelsFor returns the labels err is counted with. Errors that are not
+// an *Error are counted as UnexpectedFailure with status 500, matching
+// WriteHTTP.
+func MetricLabelsFor(err error) MetricLabels {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{Code: UnexpectedFailure, Status: http.StatusInternalServerError}
+	}
+
+	category := e.Category
+	if category == "" {
+		category = getCategoryForCode(e.Code)
+	}
+
+	labels := MetricLabels{
+		Code:     MetricCodeOther,
+		Category: MetricCategoryOther,
+		Status:   MetricStatusOther,
+	}
+
+	metricCodesMu.RLock()
+	_, ok := metricCodes[e.Code]
+	metricCodesMu.RUnlock()
+
+	if ok {
+		labels.Code = string(e.Code)
+	}
+
+	if _, ok := metricCategories[category]; ok {
+		labels.Category = string(category)
+	}
+
+	status := e.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	if status >= 100 && status <= 599 {
+		labels.Status = strconv.Itoa(status)
+	}
+
+	return labels
+}
+
+// RecordError counts err with the recorder installed by SetRecorder. Nil
+// errors are ignored. WriteHTTP records every error it writes, so call
+// RecordError only for errors that are not written as HTTP response, e.g. in
+// background jobs or GraphQL resolvers.
+func RecordError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	r := recorder.Load()
+	if r == nil {
+		return
+	}
+
+	(*r).RecordError(ctx, MetricLabelsFor(err))
+}
+
+// PrometheusRecorder is an ErrorRecorder counting errors in the
+// kopexa_errors_total counter with the labels code, category and status.
+type PrometheusRecorder struct {
+	counter *prometheus.CounterVec
+}
+
+// NewPrometheusRecorder creates the error counter and registers it with reg.
+//
+// Example:
+//
+//	rec, err := errors.NewPrometheusRecorder(registry)
+//	if err != nil {
+//	    return err
+//	}
+//	errors.SetRecorder(rec)
+func NewPrometheusRecorder(reg prometheus.Registerer) (*PrometheusRecorder, error) {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: wellknown.PrometheusNamespaceKopexa,
+		Name:      "errors_total",
+		Help:      "Total number of errors by code, category and HTTP status.",
+	}, []string{"code", "category", "status"})
+
+	if err := reg.Register(counter); err != nil {
+		return nil, err
+	}
+
+	return &PrometheusRecorder{counter: counter}, nil
+}
+
+// RecordError implements ErrorRecorder.
+func (r *PrometheusRecorder) RecordError(_ context.Context, labels MetricLabels) {
+	r.counter.WithLabelValues(labels.Code, labels.Category, labels.Status).Inc()
+}
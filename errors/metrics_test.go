@@ -0,0 +1,130 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedLabels struct {
+	labels []MetricLabels
+}
+
+func (r *recordedLabels) RecordError(_ context.Context, labels MetricLabels) {
+	r.labels = append(r.labels, labels)
+}
+
+func TestMetricLabelsFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want MetricLabels
+	}{
+		{
+			name: "known code",
+			err:  NewNotFound("missing"),
+			want: MetricLabels{Code: "NOT_FOUND", Category: "client", Status: "404"},
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("loading: %w", NewUnauthorized("")),
+			want: MetricLabels{Code: "UNAUTHORIZED", Category: "client", Status: "401"},
+		},
+		{
+			name: "plain error",
+			err:  errTest,
+			want: MetricLabels{Code: "UNEXPECTED_FAILURE", Category: "server", Status: "500"},
+		},
+		{
+			name: "custom code",
+			err:  New("TENANT_SUSPENDED_4711", "suspended").WithStatus(423),
+			want: MetricLabels{Code: MetricCodeOther, Category: "client", Status: "423"},
+		},
+		{
+			name: "missing category and status",
+			err:  &Error{Code: ServiceUnavailable},
+			want: MetricLabels{Code: "SERVICE_UNAVAILABLE", Category: "server", Status: "500"},
+		},
+		{
+			name: "invalid status and category",
+			err:  &Error{Code: Conflict, Category: "made-up", Status: 1000},
+			want: MetricLabels{Code: "CONFLICT", Category: MetricCategoryOther, Status: MetricStatusOther},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MetricLabelsFor(tt.err))
+		})
+	}
+}
+
+func TestAllowMetricCodes(t *testing.T) {
+	const code ErrorCode = "TEST_ALLOWED_CODE"
+
+	t.Cleanup(func() {
+		metricCodesMu.Lock()
+		delete(metricCodes, code)
+		metricCodesMu.Unlock()
+	})
+
+	assert.Equal(t, MetricCodeOther, MetricLabelsFor(New(code, "")).Code)
+
+	AllowMetricCodes(code)
+	assert.Equal(t, string(code), MetricLabelsFor(New(code, "")).Code)
+}
+
+func TestRecordError(t *testing.T) {
+	rec := &recordedLabels{}
+	SetRecorder(rec)
+	t.Cleanup(func() { SetRecorder(nil) })
+
+	RecordError(context.Background(), nil)
+	RecordError(context.Background(), NewForbidden(""))
+	WriteHTTP(httptest.NewRecorder(), errors.New("boom"))
+
+	assert.Equal(t, []MetricLabels{
+		{Code: "FORBIDDEN", Category: "client", Status: "403"},
+		{Code: "UNEXPECTED_FAILURE", Category: "server", Status: "500"},
+	}, rec.labels)
+
+	SetRecorder(nil)
+	RecordError(context.Background(), NewForbidden(""))
+	assert.Len(t, rec.labels, 2)
+}
+
+func TestPrometheusRecorder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	rec, err := NewPrometheusRecorder(reg)
+	require.NoError(t, err)
+
+	SetRecorder(rec)
+	t.Cleanup(func() { SetRecorder(nil) })
+
+	RecordError(context.Background(), NewNotFound(""))
+	RecordError(context.Background(), NewNotFound(""))
+	WriteHTTP(httptest.NewRecorder(), NewTooManyRequestsWithRetryAfter(0))
+
+	err = testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP kopexa_errors_total Total number of errors by code, category and HTTP status.
+# TYPE kopexa_errors_total counter
+kopexa_errors_total{category="client",code="NOT_FOUND",status="404"} 2
+kopexa_errors_total{category="client",code="TOO_MANY_REQUESTS",status="429"} 1
+`))
+	require.NoError(t, err)
+
+	_, err = NewPrometheusRecorder(reg)
+	require.Error(t, err)
+}
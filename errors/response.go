@@ -0,0 +1,190 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ResponseShape names the JSON shape of error responses.
+type ResponseShape string
+
+const (
+	// ShapeCurrent renders the Error itself, as WriteHTTP does.
+	ShapeCurrent ResponseShape = "current"
+	// ShapeLegacy renders {"error": {"code": ..., "message": ...}} for
+	// clients that predate the current shape.
+	ShapeLegacy ResponseShape = "legacy"
+)
+
+// Renderer converts an error into the JSON response body.
+type Renderer func(e *Error) any
+
+// LegacyResponse is the body rendered for ShapeLegacy.
+type LegacyResponse struct {
+	Error LegacyError `json:"error"`
+}
+
+// LegacyError is the error object of a LegacyResponse.
+type LegacyError struct {
+	Code    ErrorCode `json:"code,omitempty"`
+	Message string    `json:"message"`
+}
+
+// RenderCurrent renders e in the current shape.
+func RenderCurrent(e *Error) any {
+	return e
+}
+
+// RenderLegacy renders e in the legacy shape.
+func RenderLegacy(e *Error) any {
+	return LegacyResponse{Error: LegacyError{Code: e.Code, Message: e.Message}}
+}
+
+// ResponseEncoder writes errors in the shape a client expects, so that
+// handlers can serve old and new clients during a migration. The shape is
+// selected, in order, by a media type of the Accept header registered with
+// WithMediaTypeShape, by the route (see WithRouteShape) and by the default
+// shape.
+//
+// Example:
+//
+//	enc := errors.NewResponseEncoder(
+//		errors.WithDefaultShape(errors.ShapeLegacy),
+//		errors.WithMediaTypeShape("application/vnd.kopexa.v2+json", errors.ShapeCurrent),
+//	)
+//	r.With(errors.WithRouteShape(errors.ShapeCurrent)).Get("/v2/controls", handler)
+//	...
+//	enc.Write(w, r, err)
+type ResponseEncoder struct {
+	defaultShape ResponseShape
+	mediaTypes   map[string]ResponseShape
+	renderers    map[ResponseShape]Renderer
+}
+
+// ResponseEncoderOption configures NewResponseEncoder.
+type ResponseEncoderOption func(*ResponseEncoder)
+
+// WithDefaultShape sets the shape used when neither the Accept header nor
+// the route select one. Defaults to ShapeCurrent.
+func WithDefaultShape(shape ResponseShape) ResponseEncoderOption {
+	return func(enc *ResponseEncoder) {
+		enc.defaultShape = shape
+	}
+}
+
+// WithMediaTypeShape selects shape for requests accepting mediaType.
+func WithMediaTypeShape(mediaType string, shape ResponseShape) ResponseEncoderOption {
+	return func(enc *ResponseEncoder) {
+		enc.mediaTypes[strings.ToLower(mediaType)] = shape
+	}
+}
+
+// WithRenderer registers render for shape, e.g. to add a custom shape or
+// to include more fields in the legacy shape.
+func WithRenderer(shape ResponseShape, render Renderer) ResponseEncoderOption {
+	return func(enc *ResponseEncoder) {
+		enc.renderers[shape] = render
+	}
+}
+
+// NewResponseEncoder creates a ResponseEncoder with the ShapeCurrent and
+// ShapeLegacy renderers.
+func NewResponseEncoder(opts ...ResponseEncoderOption) *ResponseEncoder {
+	enc := &ResponseEncoder{
+		defaultShape: ShapeCurrent,
+		mediaTypes:   map[string]ResponseShape{},
+		renderers: map[ResponseShape]Renderer{
+			ShapeCurrent: RenderCurrent,
+			ShapeLegacy:  RenderLegacy,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(enc)
+	}
+
+	return enc
+}
+
+// Shape returns the shape of the error response to r.
+func (enc *ResponseEncoder) Shape(r *http.Request) ResponseShape {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(accepted)
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+
+		if shape, ok := enc.mediaTypes[mediaType]; ok {
+			return shape
+		}
+	}
+
+	if shape, ok := r.Context().Value(routeShapeKey{}).(ResponseShape); ok {
+		return shape
+	}
+
+	return enc.defaultShape
+}
+
+// Write writes err like WriteHTTP, rendered in the shape selected for r.
+// Shapes without renderer fall back to ShapeCurrent.
+func (enc *ResponseEncoder) Write(w http.ResponseWriter, r *http.Request, err error) {
+	render, ok := enc.renderers[enc.Shape(r)]
+	if !ok {
+		render = RenderCurrent
+	}
+
+	writeHTTP(r.Context(), w, err, render)
+}
+
+// routeShapeKey is the context key of the route shape.
+type routeShapeKey struct{}
+
+// ContextWithShape returns a copy of ctx that selects shape for error
+// responses written by a ResponseEncoder.
+func ContextWithShape(ctx context.Context, shape ResponseShape) context.Context {
+	return context.WithValue(ctx, routeShapeKey{}, shape)
+}
+
+// WithRouteShape returns middleware that selects shape for the error
+// responses of the routes it wraps.
+func WithRouteShape(shape ResponseShape) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(ContextWithShape(r.Context(), shape)))
+		})
+	}
+}
+
+// writeHTTP implements WriteHTTP with the body produced by render.
+func writeHTTP(ctx context.Context, w http.ResponseWriter, err error, render Renderer) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = NewUnexpectedFailure("").With(err)
+	}
+
+	RecordError(ctx, e)
+
+	status := e.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	if d, ok := e.RetryAfter(); ok {
+		w.Header().Set(headerRetryAfter, strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(render(e))
+}
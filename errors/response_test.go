@@ -0,0 +1,106 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mediaTypeV2 = "application/vnd.kopexa.v2+json"
+
+func TestResponseEncoder_Shape(t *testing.T) {
+	enc := NewResponseEncoder(
+		WithDefaultShape(ShapeLegacy),
+		WithMediaTypeShape(mediaTypeV2, ShapeCurrent),
+	)
+
+	tests := []struct {
+		name   string
+		accept string
+		route  ResponseShape
+		want   ResponseShape
+	}{
+		{name: "default", want: ShapeLegacy},
+		{name: "unknown media type", accept: "application/json", want: ShapeLegacy},
+		{name: "accept header", accept: "text/html, " + mediaTypeV2 + ";q=0.9", want: ShapeCurrent},
+		{name: "refused media type", accept: mediaTypeV2 + ";q=0", want: ShapeLegacy},
+		{name: "route", route: ShapeCurrent, want: ShapeCurrent},
+		{name: "accept header overrides route", accept: mediaTypeV2, route: ShapeLegacy, want: ShapeCurrent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+
+			if tt.route != "" {
+				r = r.WithContext(ContextWithShape(r.Context(), tt.route))
+			}
+
+			assert.Equal(t, tt.want, enc.Shape(r))
+		})
+	}
+}
+
+func TestResponseEncoder_Write(t *testing.T) {
+	enc := NewResponseEncoder(WithMediaTypeShape("application/vnd.kopexa.legacy+json", ShapeLegacy))
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		enc.Write(w, r, NewNotFound("control not found"))
+	}
+
+	t.Run("current", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		var body Error
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, NotFound, body.Code)
+		assert.Equal(t, "control not found", body.Message)
+	})
+
+	t.Run("legacy route", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WithRouteShape(ShapeLegacy)(http.HandlerFunc(handler)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"error":{"code":"NOT_FOUND","message":"control not found"}}`, rec.Body.String())
+	})
+
+	t.Run("legacy accept header", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", "application/vnd.kopexa.legacy+json")
+
+		rec := httptest.NewRecorder()
+		handler(rec, r)
+
+		var body LegacyResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "control not found", body.Error.Message)
+	})
+
+	t.Run("custom renderer", func(t *testing.T) {
+		enc := NewResponseEncoder(
+			WithDefaultShape("problem"),
+			WithRenderer("problem", func(e *Error) any {
+				return map[string]any{"title": e.Message, "status": e.Status}
+			}),
+		)
+
+		rec := httptest.NewRecorder()
+		enc.Write(rec, httptest.NewRequest(http.MethodGet, "/", nil), NewNotFound("control not found"))
+
+		assert.JSONEq(t, `{"title":"control not found","status":404}`, rec.Body.String())
+	})
+}
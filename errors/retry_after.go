@@ -0,0 +1,168 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DetailRetryAfterSeconds is the Details key holding the number of seconds a
+// client should wait before retrying.
+const DetailRetryAfterSeconds = "retry_after_seconds"
+
+// headerRetryAfter is the HTTP header announcing when to retry a request.
+const headerRetryAfter = "Retry-After"
+
+// NewTooManyRequestsWithRetryAfter creates a TooManyRequests error that asks
+// the client to retry after d. The duration is rounded up to whole seconds,
+// the resolution of the Retry-After header, and recorded in Details under
+// DetailRetryAfterSeconds.
+func NewTooManyRequestsWithRetryAfter(d time.Duration) *Error {
+	return New(TooManyRequests, msgTooManyRequests).
+		WithStatus(http.StatusTooManyRequests).
+		WithRetryAfter(d)
+}
+
+// WithRetryAfter records in Details that the client should retry after d.
+// Negative durations are treated as zero.
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	seconds := int64(math.Ceil(d.Seconds()))
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	return e.WithDetails(DetailRetryAfterSeconds, seconds)
+}
+
+// RetryAfter returns the retry delay recorded with WithRetryAfter. It also
+// understands Details decoded from JSON, where numbers are float64.
+func (e *Error) RetryAfter() (time.Duration, bool) {
+	var seconds float64
+
+	switch v := e.Details[DetailRetryAfterSeconds].(type) {
+	case int64:
+		seconds = float64(v)
+	case int:
+		seconds = float64(v)
+	case float64:
+		seconds = v
+	default:
+		return 0, false
+	}
+
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// WriteHTTP writes err as JSON response with its HTTP status. Errors that
+// are not an *Error are reported as UnexpectedFailure without exposing their
+// message. If the error carries a retry delay, the Retry-After header is set.
+// The error is counted with the recorder installed by SetRecorder.
+//
+// Example:
+//
+//	if !limiter.Allow() {
+//	    errors.WriteHTTP(w, errors.NewTooManyRequestsWithRetryAfter(limiter.Delay()))
+//	    return
+//	}
+func WriteHTTP(w http.ResponseWriter, err error) {
+	writeHTTP(context.Background(), w, err, RenderCurrent)
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, given either as
+// delay in seconds or as HTTP date, into the delay relative to now. Dates in
+// the past yield a zero delay.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	if d := at.Sub(now); d > 0 {
+		return d, true
+	}
+
+	return 0, true
+}
+
+// RetryPolicy tells a client whether and when to retry a failed request.
+type RetryPolicy struct {
+	// Retryable reports whether the request may be retried.
+	Retryable bool
+	// Delay is the minimum wait before the retry; zero if the server did not
+	// announce one and the client should use its own backoff.
+	Delay time.Duration
+}
+
+// RetryPolicyFromResponse derives the retry policy from an HTTP response.
+// Responses with status 429, 502, 503 or 504 are retryable; the delay is
+// taken from the Retry-After header.
+func RetryPolicyFromResponse(resp *http.Response) RetryPolicy {
+	if resp == nil {
+		return RetryPolicy{}
+	}
+
+	var p RetryPolicy
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		p.Retryable = true
+	default:
+		return p
+	}
+
+	if d, ok := ParseRetryAfter(resp.Header.Get(headerRetryAfter), time.Now()); ok {
+		p.Delay = d
+	}
+
+	return p
+}
+
+// RetryPolicyFor derives the retry policy from an error. Errors accepted by
+// IsRetryable and errors carrying a retry delay are retryable.
+func RetryPolicyFor(err error) RetryPolicy {
+	var e *Error
+	if !errors.As(err, &e) {
+		return RetryPolicy{}
+	}
+
+	d, ok := e.RetryAfter()
+
+	return RetryPolicy{Retryable: ok || IsRetryable(e), Delay: d}
+}
+
+// WithinBudget reports whether the request is retryable and waiting for Delay
+// still leaves time before the deadline of ctx. Without a deadline only
+// Retryable is considered.
+func (p RetryPolicy) WithinBudget(ctx context.Context) bool {
+	if !p.Retryable {
+		return false
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+
+	return time.Now().Add(p.Delay).Before(deadline)
+}
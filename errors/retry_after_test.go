@@ -0,0 +1,115 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTooManyRequestsWithRetryAfter(t *testing.T) {
+	e := NewTooManyRequestsWithRetryAfter(1500 * time.Millisecond)
+
+	assert.Equal(t, TooManyRequests, e.Code)
+	assert.Equal(t, http.StatusTooManyRequests, e.Status)
+	assert.Equal(t, int64(2), e.Details[DetailRetryAfterSeconds])
+
+	d, ok := e.RetryAfter()
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, d)
+
+	_, ok = NewBadRequest("").RetryAfter()
+	assert.False(t, ok)
+}
+
+func TestWriteHTTP(t *testing.T) {
+	t.Run("retry after", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WriteHTTP(rec, NewTooManyRequestsWithRetryAfter(30*time.Second))
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+
+		var body Error
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, TooManyRequests, body.Code)
+
+		// details decoded from JSON still yield the delay
+		d, ok := body.RetryAfter()
+		assert.True(t, ok)
+		assert.Equal(t, 30*time.Second, d)
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WriteHTTP(rec, errors.New("db password is hunter2"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Empty(t, rec.Header().Get("Retry-After"))
+		assert.NotContains(t, rec.Body.String(), "hunter2")
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "120", want: 2 * time.Minute, wantOK: true},
+		{value: "Wed, 01 Jan 2025 12:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{value: "Wed, 01 Jan 2025 11:00:00 GMT", want: 0, wantOK: true},
+		{value: "", wantOK: false},
+		{value: "-5", wantOK: false},
+		{value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			d, ok := ParseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, d)
+		})
+	}
+}
+
+func TestRetryPolicyFromResponse(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "5")
+
+	assert.Equal(t, RetryPolicy{Retryable: true, Delay: 5 * time.Second}, RetryPolicyFromResponse(resp))
+	assert.Equal(t, RetryPolicy{Retryable: true}, RetryPolicyFromResponse(&http.Response{StatusCode: http.StatusServiceUnavailable}))
+	assert.Equal(t, RetryPolicy{}, RetryPolicyFromResponse(&http.Response{StatusCode: http.StatusBadRequest}))
+	assert.Equal(t, RetryPolicy{}, RetryPolicyFromResponse(nil))
+}
+
+func TestRetryPolicyFor(t *testing.T) {
+	assert.Equal(t, RetryPolicy{Retryable: true, Delay: time.Second}, RetryPolicyFor(NewTooManyRequestsWithRetryAfter(time.Second)))
+	assert.Equal(t, RetryPolicy{Retryable: true}, RetryPolicyFor(New(ServiceUnavailable, "")))
+	assert.Equal(t, RetryPolicy{}, RetryPolicyFor(NewBadRequest("")))
+	assert.Equal(t, RetryPolicy{}, RetryPolicyFor(errors.New("boom")))
+}
+
+func TestRetryPolicy_WithinBudget(t *testing.T) {
+	p := RetryPolicy{Retryable: true, Delay: time.Minute}
+
+	assert.True(t, p.WithinBudget(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.False(t, p.WithinBudget(ctx))
+	assert.True(t, RetryPolicy{Retryable: true, Delay: time.Millisecond}.WithinBudget(ctx))
+	assert.False(t, RetryPolicy{}.WithinBudget(context.Background()))
+}
@@ -0,0 +1,165 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Severity classifies how urgent an error is, e.g. to decide whether it
+// should page someone.
+type Severity string
+
+const (
+	// SeverityInfo is expected behaviour, such as invalid client input.
+	SeverityInfo Severity = "info"
+	// SeverityWarn needs attention if it happens often, such as failed logins.
+	SeverityWarn Severity = "warn"
+	// SeverityError is a failure of the service that should be investigated.
+	SeverityError Severity = "error"
+	// SeverityCritical requires immediate action and should alert on-call.
+	SeverityCritical Severity = "critical"
+)
+
+// severityRanks orders the severities from least to most urgent.
+var severityRanks = map[Severity]int{
+	SeverityInfo:     1,
+	SeverityWarn:     2,
+	SeverityError:    3,
+	SeverityCritical: 4,
+}
+
+// ParseSeverity parses a severity name, case-insensitively, e.g. to read an
+// alerting threshold from configuration.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := severityRanks[sev]; !ok {
+		return "", fmt.Errorf("unknown severity %q", s)
+	}
+
+	return sev, nil
+}
+
+// AtLeast reports whether s is as urgent as threshold or more. Unknown
+// severities are below every threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	rank, ok := severityRanks[s]
+
+	return ok && rank >= severityRanks[threshold]
+}
+
+// Level returns the log level to log errors of this severity with. Critical
+// errors are logged at error level, as the fatal and panic levels would
+// terminate the process.
+func (s Severity) Level() zerolog.Level {
+	switch s {
+	case SeverityInfo:
+		return zerolog.InfoLevel
+	case SeverityWarn:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
+// getSeverityForCategory returns the default severity of an error category.
+func getSeverityForCategory(category ErrorCategory) Severity {
+	switch category {
+	case CategoryClient:
+		return SeverityInfo
+	case CategoryAuth, CategoryResource, CategoryTimeout:
+		return SeverityWarn
+	default:
+		return SeverityError
+	}
+}
+
+// WithSeverity sets the severity of the Error, overriding the default of its
+// category.
+func (e *Error) WithSeverity(severity Severity) *Error {
+	e.Severity = severity
+	return e
+}
+
+// EffectiveSeverity returns the severity of the Error, falling back to the
+// default of its category, or of its code if the category is not set.
+func (e *Error) EffectiveSeverity() Severity {
+	if e.Severity != "" {
+		return e.Severity
+	}
+
+	category := e.Category
+	if category == "" {
+		category = getCategoryForCode(e.Code)
+	}
+
+	return getSeverityForCategory(category)
+}
+
+// SeverityOf returns the severity of err. Errors that are not an *Error are
+// unexpected and reported as SeverityError; nil yields an empty severity.
+func SeverityOf(err error) Severity {
+	if err == nil {
+		return ""
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		return SeverityError
+	}
+
+	return e.EffectiveSeverity()
+}
+
+// IsCritical reports whether err has SeverityCritical.
+func IsCritical(err error) bool {
+	return SeverityOf(err) == SeverityCritical
+}
+
+// ShouldAlert reports whether err is at least as severe as threshold.
+//
+// Example:
+//
+//	if errors.ShouldAlert(err, errors.SeverityCritical) {
+//	    pager.Trigger(ctx, err)
+//	}
+func ShouldAlert(err error, threshold Severity) bool {
+	return SeverityOf(err).AtLeast(threshold)
+}
+
+// MarshalZerologObject implements zerolog.LogObjectMarshaler, so errors
+// logged with Object include their classification.
+//
+// Example:
+//
+//	log.WithLevel(e.EffectiveSeverity().Level()).Object("error", e).Msg("request failed")
+func (e *Error) MarshalZerologObject(ev *zerolog.Event) {
+	ev.Str("code", string(e.Code)).
+		Str("severity", string(e.EffectiveSeverity())).
+		Str("message", e.Message)
+
+	if e.Category != "" {
+		ev.Str("category", string(e.Category))
+	}
+
+	if e.Status != 0 {
+		ev.Int("status", e.Status)
+	}
+
+	if e.Entity != "" {
+		ev.Str("entity", e.Entity)
+	}
+
+	if e.RequestID != "" {
+		ev.Str("request_id", e.RequestID)
+	}
+
+	if e.Err != nil {
+		ev.Str("cause", e.Err.Error())
+	}
+}
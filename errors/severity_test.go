@@ -0,0 +1,112 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultSeverity(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Severity
+	}{
+		{"client", NewBadRequest(""), SeverityInfo},
+		{"auth", NewInvalidCredentials(""), SeverityWarn},
+		{"resource", NewQuotaExceeded(""), SeverityWarn},
+		{"timeout", NewDeadlineExceeded(""), SeverityWarn},
+		{"server", NewUnexpectedFailure(""), SeverityError},
+		{"network", NewConnectionRefused(""), SeverityError},
+		{"wrapped", Wrap(errors.New("boom"), "failed"), SeverityError},
+		{"plain error", errors.New("boom"), SeverityError},
+		{"nested", fmt.Errorf("handler: %w", NewNotFound("")), SeverityInfo},
+		{"nil", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SeverityOf(tt.err))
+		})
+	}
+}
+
+func TestWithSeverity(t *testing.T) {
+	err := NewServiceUnavailable("database down").(*Error).WithSeverity(SeverityCritical)
+
+	assert.Equal(t, SeverityCritical, err.Severity)
+	assert.True(t, IsCritical(err))
+	assert.True(t, IsCritical(fmt.Errorf("wrapped: %w", err)))
+	assert.False(t, IsCritical(NewUnexpectedFailure("")))
+}
+
+func TestShouldAlert(t *testing.T) {
+	assert.True(t, ShouldAlert(NewUnexpectedFailure(""), SeverityError))
+	assert.True(t, ShouldAlert(NewUnexpectedFailure(""), SeverityWarn))
+	assert.False(t, ShouldAlert(NewUnexpectedFailure(""), SeverityCritical))
+	assert.False(t, ShouldAlert(NewBadRequest(""), SeverityWarn))
+	assert.False(t, ShouldAlert(nil, SeverityInfo))
+	assert.False(t, ShouldAlert(NewBadRequest("").WithSeverity("unknown"), SeverityInfo))
+}
+
+func TestParseSeverity(t *testing.T) {
+	s, err := ParseSeverity(" Critical ")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityCritical, s)
+
+	_, err = ParseSeverity("fatal")
+	require.Error(t, err)
+}
+
+func TestSeverityLevel(t *testing.T) {
+	assert.Equal(t, zerolog.InfoLevel, SeverityInfo.Level())
+	assert.Equal(t, zerolog.WarnLevel, SeverityWarn.Level())
+	assert.Equal(t, zerolog.ErrorLevel, SeverityError.Level())
+	assert.Equal(t, zerolog.ErrorLevel, SeverityCritical.Level())
+}
+
+func TestSeverityJSON(t *testing.T) {
+	data, err := json.Marshal(NewForbidden(""))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"severity":"info"`)
+
+	// errors not created with New get their default severity
+	data, err = json.Marshal(Wrap(errors.New("boom"), "failed"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"severity":"error"`)
+
+	var decoded Error
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, SeverityError, decoded.Severity)
+}
+
+func TestSeverityLog(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := zerolog.New(&buf)
+	e := NewNotFound("control not found").WithEntity("control").WithSeverity(SeverityWarn)
+
+	logger.WithLevel(e.EffectiveSeverity().Level()).Object("error", e).Send()
+
+	var out struct {
+		Level string         `json:"level"`
+		Error map[string]any `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+
+	assert.Equal(t, "warn", out.Level)
+	assert.Equal(t, "NOT_FOUND", out.Error["code"])
+	assert.Equal(t, "warn", out.Error["severity"])
+	assert.Equal(t, "client", out.Error["category"])
+	assert.Equal(t, "control", out.Error["entity"])
+	assert.InDelta(t, 404, out.Error["status"], 0)
+}
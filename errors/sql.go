@@ -0,0 +1,202 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// DetailSQLState is the Details key holding the SQLSTATE of a database error
+// converted by FromSQLError.
+const DetailSQLState = "sql_state"
+
+// SQLSTATE codes recognized by FromSQLError, see the PostgreSQL manual,
+// appendix "PostgreSQL Error Codes".
+const (
+	SQLStateUniqueViolation           = "23505"
+	SQLStateForeignKeyViolation       = "23503"
+	SQLStateNotNullViolation          = "23502"
+	SQLStateCheckViolation            = "23514"
+	SQLStateExclusionViolation        = "23P01"
+	SQLStateStringDataRightTruncation = "22001"
+	SQLStateNumericValueOutOfRange    = "22003"
+	SQLStateInvalidTextRepresentation = "22P02"
+	SQLStateSerializationFailure      = "40001"
+	SQLStateDeadlockDetected          = "40P01"
+	SQLStateLockNotAvailable          = "55P03"
+	SQLStateQueryCanceled             = "57014"
+	SQLStateAdminShutdown             = "57P01"
+	SQLStateCannotConnectNow          = "57P03"
+	SQLStateTooManyConnections        = "53300"
+)
+
+// sqlStateError is implemented by driver errors carrying a SQLSTATE, such as
+// *pgconn.PgError (pgx) and *pq.Error (lib/pq).
+type sqlStateError interface {
+	SQLState() string
+}
+
+// FromSQLError converts an error returned by database/sql, pgx or an ent
+// client into an *Error with a matching code:
+//
+//   - sql.ErrNoRows, pgx.ErrNoRows and ent "not found" errors: NotFound
+//   - unique and exclusion violations: Conflict
+//   - foreign key, not null and check violations: FailedPrecondition
+//   - invalid input values: InvalidArgument
+//   - serialization failures, deadlocks and lock timeouts: ServiceUnavailable,
+//     so that IsRetryable reports true and the transaction can be retried
+//   - connection failures and shutdowns: ConnectionFailed or
+//     ServiceUnavailable
+//   - context errors: see FromContextError
+//
+// The messages never contain the database error, which may include column
+// values; it is kept as the underlying error. Driver errors are detected by
+// their SQLState method, so this package does not depend on a driver. Errors
+// that are already an *Error are returned unchanged. Returns nil for nil.
+//
+// Example:
+//
+//	user, err := client.User.Get(ctx, id)
+//	if err != nil {
+//	    return nil, errors.FromSQLError(err).WithEntity("user")
+//	}
+func FromSQLError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	if isNoRows(err) {
+		return NewNotFound(msgNotFound).With(err)
+	}
+
+	if label, ok := entNotFoundLabel(err); ok {
+		return NewNotFound(msgNotFound).WithEntity(label).With(err)
+	}
+
+	var stateErr sqlStateError
+	if errors.As(err, &stateErr) {
+		if e := fromSQLState(stateErr.SQLState()); e != nil {
+			return e.With(err)
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return FromContextError(err).With(err)
+	}
+
+	if errors.Is(err, sql.ErrConnDone) {
+		return New(ConnectionFailed, msgConnectionFailed).WithStatus(http.StatusServiceUnavailable).With(err)
+	}
+
+	// Drivers without SQLSTATE, e.g. SQLite in tests, are matched by message
+	// like entgo.io/ent/dialect/sql/sqlgraph does.
+	msg := err.Error()
+
+	switch {
+	case containsAny(msg, "UNIQUE constraint failed", "Error 1062", "violates unique constraint"):
+		return NewConflict(msgConflict).With(err)
+	case containsAny(msg, "FOREIGN KEY constraint failed", "Error 1451", "Error 1452", "violates foreign key constraint"):
+		return NewFailedPrecondition(msgFailedPrecondition).With(err)
+	}
+
+	return Wrap(err, msgUnexpectedFailure)
+}
+
+// IsSQLNotFound reports whether err is a "no rows" error of database/sql,
+// pgx or an ent client.
+func IsSQLNotFound(err error) bool {
+	if isNoRows(err) {
+		return true
+	}
+
+	_, ok := entNotFoundLabel(err)
+
+	return ok
+}
+
+// fromSQLState returns the error for a SQLSTATE or nil if it is not mapped.
+func fromSQLState(state string) *Error {
+	var e *Error
+
+	switch state {
+	case SQLStateUniqueViolation, SQLStateExclusionViolation:
+		e = NewConflict(msgConflict)
+	case SQLStateForeignKeyViolation, SQLStateNotNullViolation, SQLStateCheckViolation:
+		e = NewFailedPrecondition(msgFailedPrecondition)
+	case SQLStateStringDataRightTruncation, SQLStateNumericValueOutOfRange, SQLStateInvalidTextRepresentation:
+		e = NewInvalidArgument(msgInvalidArgument)
+	case SQLStateSerializationFailure, SQLStateDeadlockDetected, SQLStateLockNotAvailable,
+		SQLStateAdminShutdown, SQLStateCannotConnectNow, SQLStateTooManyConnections:
+		e = New(ServiceUnavailable, msgServiceUnavailable).WithStatus(http.StatusServiceUnavailable)
+	case SQLStateQueryCanceled:
+		e = New(DeadlineExceeded, msgDeadlineExceeded).WithStatus(http.StatusGatewayTimeout)
+	default:
+		// class 08: connection exception
+		if strings.HasPrefix(state, "08") {
+			e = New(ConnectionFailed, msgConnectionFailed).WithStatus(http.StatusServiceUnavailable)
+		}
+	}
+
+	if e != nil {
+		e.WithDetails(DetailSQLState, state)
+	}
+
+	return e
+}
+
+// isNoRows reports whether err is sql.ErrNoRows or pgx.ErrNoRows. The pgx
+// error is matched by message, as pgx.ErrNoRows only wraps sql.ErrNoRows
+// since pgx v5.6.
+func isNoRows(err error) bool {
+	if errors.Is(err, sql.ErrNoRows) {
+		return true
+	}
+
+	for err != nil {
+		if err.Error() == "no rows in result set" {
+			return true
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return false
+}
+
+// entNotFoundLabel returns the label of an ent *NotFoundError. The error type
+// is generated per schema, so it is matched by its message "ent: <label> not
+// found".
+func entNotFoundLabel(err error) (string, bool) {
+	for err != nil {
+		msg := err.Error()
+		if label, ok := strings.CutPrefix(msg, "ent: "); ok {
+			if label, ok = strings.CutSuffix(label, " not found"); ok && label != "" {
+				return label, true
+			}
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return "", false
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pgError mimics *pgconn.PgError
+type pgError struct {
+	Code    string
+	Message string
+}
+
+func (e *pgError) Error() string    { return "ERROR: " + e.Message + " (SQLSTATE " + e.Code + ")" }
+func (e *pgError) SQLState() string { return e.Code }
+
+// entNotFoundError mimics the NotFoundError generated by ent
+type entNotFoundError struct{ label string }
+
+func (e *entNotFoundError) Error() string { return "ent: " + e.label + " not found" }
+
+func TestFromSQLError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      ErrorCode
+		status    int
+		retryable bool
+	}{
+		{"no rows", sql.ErrNoRows, NotFound, http.StatusNotFound, false},
+		{"pgx no rows", errors.New("no rows in result set"), NotFound, http.StatusNotFound, false},
+		{"unique violation", &pgError{Code: SQLStateUniqueViolation, Message: "duplicate key"}, Conflict, http.StatusConflict, false},
+		{"foreign key violation", &pgError{Code: SQLStateForeignKeyViolation}, FailedPrecondition, http.StatusPreconditionFailed, false},
+		{"invalid input", &pgError{Code: SQLStateInvalidTextRepresentation}, InvalidArgument, http.StatusBadRequest, false},
+		{"serialization failure", &pgError{Code: SQLStateSerializationFailure}, ServiceUnavailable, http.StatusServiceUnavailable, true},
+		{"deadlock", &pgError{Code: SQLStateDeadlockDetected}, ServiceUnavailable, http.StatusServiceUnavailable, true},
+		{"connection exception", &pgError{Code: "08006"}, ConnectionFailed, http.StatusServiceUnavailable, true},
+		{"query canceled", &pgError{Code: SQLStateQueryCanceled}, DeadlineExceeded, http.StatusGatewayTimeout, false},
+		{"context deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), DeadlineExceeded, http.StatusGatewayTimeout, false},
+		{"sqlite unique", errors.New("UNIQUE constraint failed: users.email"), Conflict, http.StatusConflict, false},
+		{"unknown", errors.New("boom"), UnexpectedFailure, http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("create user: %w", tt.err)
+
+			e := FromSQLError(err)
+			require.NotNil(t, e)
+			assert.Equal(t, tt.code, e.Code)
+			assert.Equal(t, tt.status, e.Status)
+			assert.Equal(t, tt.retryable, IsRetryable(e))
+			assert.ErrorIs(t, e, tt.err)
+			assert.NotContains(t, e.Message, "SQLSTATE")
+		})
+	}
+}
+
+func TestFromSQLError_SQLStateDetail(t *testing.T) {
+	e := FromSQLError(&pgError{Code: SQLStateUniqueViolation})
+	assert.Equal(t, SQLStateUniqueViolation, e.Details[DetailSQLState])
+}
+
+func TestFromSQLError_Ent(t *testing.T) {
+	e := FromSQLError(fmt.Errorf("get: %w", &entNotFoundError{label: "control"}))
+	assert.Equal(t, NotFound, e.Code)
+	assert.Equal(t, "control", e.Entity)
+
+	assert.True(t, IsSQLNotFound(&entNotFoundError{label: "control"}))
+	assert.True(t, IsSQLNotFound(sql.ErrNoRows))
+	assert.False(t, IsSQLNotFound(errors.New("ent: constraint failed")))
+}
+
+func TestFromSQLError_PassThrough(t *testing.T) {
+	assert.Nil(t, FromSQLError(nil))
+
+	orig := NewForbidden("no access")
+	assert.Same(t, orig, FromSQLError(fmt.Errorf("wrapped: %w", orig)))
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import "net/http"
+
+// DetailViolations is the Details key under which field violations are stored.
+const DetailViolations = "violations"
+
+// FieldViolation describes a single invalid field in a request, modeled after
+// the google.rpc.BadRequest.FieldViolation detail used by AIP-193.
+type FieldViolation struct {
+	// Field is the path to the offending field (e.g., "address.city").
+	Field string `json:"field"`
+	// Code is a machine-readable reason for the violation (e.g., "required").
+	Code string `json:"code,omitempty"`
+	// Message is a human-readable description of the violation.
+	Message string `json:"message"`
+}
+
+// NewValidation creates a new UnprocessableEntity Error carrying the given field
+// violations in its Details under the "violations" key.
+func NewValidation(violations ...FieldViolation) *Error {
+	e := New(UnprocessableEntity, msgValidationFailed).WithStatus(http.StatusUnprocessableEntity)
+
+	return e.WithViolations(violations...)
+}
+
+// WithViolation appends a single field violation to the Error.
+func (e *Error) WithViolation(field, code, message string) *Error {
+	return e.WithViolations(FieldViolation{Field: field, Code: code, Message: message})
+}
+
+// WithViolations appends the given field violations to the Error.
+func (e *Error) WithViolations(violations ...FieldViolation) *Error {
+	existing := e.Violations()
+	merged := make([]FieldViolation, 0, len(existing)+len(violations))
+	merged = append(merged, existing...)
+	merged = append(merged, violations...)
+
+	return e.WithDetails(DetailViolations, merged)
+}
+
+// Violations returns the field violations attached to the Error, if any.
+func (e *Error) Violations() []FieldViolation {
+	if e.Details == nil {
+		return nil
+	}
+
+	v, ok := e.Details[DetailViolations].([]FieldViolation)
+	if !ok {
+		return nil
+	}
+
+	return v
+}
+
+// HasViolations reports whether the Error carries any field violations.
+func (e *Error) HasViolations() bool {
+	return len(e.Violations()) > 0
+}
+
+// ViolationsOf returns the field violations of err if it is an Error.
+func ViolationsOf(err error) []FieldViolation {
+	if e, ok := err.(*Error); ok {
+		return e.Violations()
+	}
+
+	return nil
+}
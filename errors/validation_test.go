@@ -0,0 +1,67 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidation(t *testing.T) {
+	err := NewValidation(
+		FieldViolation{Field: "name", Code: "required", Message: "name is required"},
+	)
+
+	assert.Equal(t, UnprocessableEntity, err.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.Status)
+	assert.Equal(t, msgValidationFailed, err.Message)
+	assert.True(t, err.HasViolations())
+	assert.Len(t, err.Violations(), 1)
+}
+
+func TestNewValidationEmpty(t *testing.T) {
+	err := NewValidation()
+	assert.False(t, err.HasViolations())
+	assert.Empty(t, err.Violations())
+}
+
+func TestWithViolation(t *testing.T) {
+	err := NewValidation().
+		WithViolation("email", "format", "invalid email").
+		WithViolation("age", "min", "must be at least 18")
+
+	v := err.Violations()
+	require.Len(t, v, 2)
+	assert.Equal(t, FieldViolation{Field: "email", Code: "format", Message: "invalid email"}, v[0])
+	assert.Equal(t, "age", v[1].Field)
+}
+
+func TestViolationsOf(t *testing.T) {
+	assert.Nil(t, ViolationsOf(errTest))
+	assert.Nil(t, ViolationsOf(NewBadRequest("")))
+
+	err := NewValidation(FieldViolation{Field: "a", Message: "b"})
+	assert.Len(t, ViolationsOf(err), 1)
+}
+
+func TestValidationJSON(t *testing.T) {
+	err := NewValidation(FieldViolation{Field: "name", Code: "required", Message: "name is required"})
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+
+	var out struct {
+		Details struct {
+			Violations []FieldViolation `json:"violations"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(b, &out))
+	require.Len(t, out.Details.Violations, 1)
+	assert.Equal(t, "name", out.Details.Violations[0].Field)
+	assert.Equal(t, "required", out.Details.Violations[0].Code)
+}
@@ -0,0 +1,213 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/rs/zerolog/log"
+)
+
+// Page and batch sizes for public access audits. OpenFGA returns at most
+// 100 tuples per read and accepts at most 100 tuple changes per write
+// request by default.
+const (
+	// AuditPageSize is the number of tuples read per page during an audit.
+	AuditPageSize = 100
+	// AuditRemovalBatchSize is the number of tuples deleted per write during
+	// remediation.
+	AuditRemovalBatchSize = 100
+)
+
+// PublicAccessFinding is a tuple that grants a relation to all subjects of a
+// type, such as user:*.
+type PublicAccessFinding struct {
+	// ObjectType is the type of the exposed object.
+	ObjectType string
+	// ObjectID is the ID of the exposed object.
+	ObjectID string
+	// Relation is the relation granted to everyone.
+	Relation string
+	// Subject is the wildcard subject, e.g. "user:*".
+	Subject string
+	// Condition is the name of the tuple condition, if any.
+	Condition string
+	// Removed is true if the tuple was deleted in remediation mode.
+	Removed bool
+}
+
+// PublicAccessAudit is the result of AuditPublicAccess.
+type PublicAccessAudit struct {
+	// Scanned is the number of tuples inspected.
+	Scanned int
+	// Findings lists the wildcard tuples, in the order they were read.
+	Findings []PublicAccessFinding
+	// Removed is the number of deleted findings in remediation mode.
+	Removed int
+}
+
+// AuditOption configures AuditPublicAccess.
+type AuditOption func(*auditConfig)
+
+type auditConfig struct {
+	remediate bool
+	allowed   []Relation
+}
+
+// WithRemediation deletes the findings after the scan. Findings of allowed
+// relations are neither reported nor deleted.
+func WithRemediation() AuditOption {
+	return func(c *auditConfig) {
+		c.remediate = true
+	}
+}
+
+// WithAllowedPublicRelations exempts relations that are public by design,
+// such as CanView on tuples written with CreatePublicViewTuples.
+func WithAllowedPublicRelations(relations ...Relation) AuditOption {
+	return func(c *auditConfig) {
+		c.allowed = append(c.allowed, relations...)
+	}
+}
+
+// AuditPublicAccess scans all tuples of the given object types for wildcard
+// subjects such as user:*, which grant a relation to everyone and are
+// usually written by accident. Tuples are read page by page; with
+// WithRemediation the findings are deleted in batches once the scan is
+// complete, so that deletions do not disturb the pagination.
+//
+// Example:
+//
+//	audit, err := client.AuditPublicAccess(ctx, []string{"document", "space"},
+//		fga.WithAllowedPublicRelations(fga.CanView))
+//	for _, f := range audit.Findings {
+//		log.Warn().Str("object", f.ObjectType+":"+f.ObjectID).Str("relation", f.Relation).Msg("public access")
+//	}
+func (c *Client) AuditPublicAccess(ctx context.Context, objectTypes []string, opts ...AuditOption) (*PublicAccessAudit, error) {
+	if len(objectTypes) == 0 || slices.Contains(objectTypes, "") {
+		return nil, fmt.Errorf("%w: object types are required", ErrInvalidArgument)
+	}
+
+	cfg := &auditConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	audit := &PublicAccessAudit{}
+
+	for _, objectType := range objectTypes {
+		if err := c.auditObjectType(ctx, objectType, cfg, audit); err != nil {
+			return audit, err
+		}
+	}
+
+	if cfg.remediate {
+		if err := c.removeFindings(ctx, audit); err != nil {
+			return audit, err
+		}
+	}
+
+	log.Debug().Int("scanned", audit.Scanned).Int("findings", len(audit.Findings)).Int("removed", audit.Removed).Msg("audited public access")
+
+	return audit, nil
+}
+
+// auditObjectType adds the wildcard tuples of objectType to audit.
+func (c *Client) auditObjectType(ctx context.Context, objectType string, cfg *auditConfig, audit *PublicAccessAudit) error {
+	object := objectType + ":"
+	pageSize := int32(AuditPageSize)
+
+	var token string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		opts := client.ClientReadOptions{PageSize: &pageSize}
+		if token != "" {
+			opts.ContinuationToken = &token
+		}
+
+		resp, err := c.client.Read(ctx).
+			Body(client.ClientReadRequest{Object: &object}).
+			Options(opts).
+			Execute()
+		if err != nil {
+			return fmt.Errorf("failed to read tuples to audit: %w", err)
+		}
+
+		if resp == nil {
+			return ErrEmptyResponse
+		}
+
+		for _, t := range resp.Tuples {
+			audit.Scanned++
+
+			if !strings.HasSuffix(t.Key.User, ":"+Wildcard) || slices.Contains(cfg.allowed, Relation(t.Key.Relation)) {
+				continue
+			}
+
+			finding := PublicAccessFinding{
+				ObjectType: objectType,
+				ObjectID:   strings.TrimPrefix(t.Key.Object, object),
+				Relation:   t.Key.Relation,
+				Subject:    t.Key.User,
+			}
+
+			if t.Key.Condition != nil {
+				finding.Condition = t.Key.Condition.Name
+			}
+
+			audit.Findings = append(audit.Findings, finding)
+		}
+
+		if resp.ContinuationToken == "" || len(resp.Tuples) == 0 {
+			return nil
+		}
+
+		token = resp.ContinuationToken
+	}
+}
+
+// removeFindings deletes the findings of audit in batches.
+func (c *Client) removeFindings(ctx context.Context, audit *PublicAccessAudit) error {
+	for start := 0; start < len(audit.Findings); start += AuditRemovalBatchSize {
+		batch := audit.Findings[start:min(start+AuditRemovalBatchSize, len(audit.Findings))]
+
+		deletes := make([]openfga.TupleKeyWithoutCondition, 0, len(batch))
+		for _, f := range batch {
+			deletes = append(deletes, openfga.TupleKeyWithoutCondition{
+				User:     f.Subject,
+				Relation: f.Relation,
+				Object:   f.ObjectType + ":" + f.ObjectID,
+			})
+		}
+
+		_, err := c.client.Write(ctx).
+			Body(client.ClientWriteRequest{Deletes: deletes}).
+			Options(client.ClientWriteOptions{
+				Conflict: client.ClientWriteConflictOptions{
+					OnMissingDeletes: client.CLIENT_WRITE_REQUEST_ON_MISSING_DELETES_IGNORE,
+				},
+			}).
+			Execute()
+		if err != nil {
+			return fmt.Errorf("failed to remove public access tuples: %w", err)
+		}
+
+		for i := range batch {
+			batch[i].Removed = true
+		}
+
+		audit.Removed += len(batch)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/kopexa-grc/common/fga/internal/fgamock"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func tuple(user, relation, object string) openfga.Tuple {
+	return openfga.Tuple{Key: openfga.TupleKey{User: user, Relation: relation, Object: object}}
+}
+
+func TestClient_AuditPublicAccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockRead := fgamock.NewMockSdkClientReadRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	var objects []string
+
+	mockSdk.EXPECT().Read(gomock.Any()).Return(mockRead).Times(3)
+	mockRead.EXPECT().Body(gomock.Any()).DoAndReturn(func(body client.ClientReadRequest) client.SdkClientReadRequestInterface {
+		objects = append(objects, *body.Object)
+		assert.Nil(t, body.User)
+
+		return mockRead
+	}).Times(3)
+	mockRead.EXPECT().Options(gomock.Any()).Return(mockRead).Times(3)
+
+	conditional := tuple("service:*", "editor", "document:2")
+	conditional.Key.Condition = &openfga.RelationshipCondition{Name: "valid_until"}
+
+	gomock.InOrder(
+		mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{
+			Tuples:            []openfga.Tuple{tuple("user:*", "viewer", "document:1"), tuple("user:alice", "editor", "document:1")},
+			ContinuationToken: "next",
+		}, nil),
+		mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{
+			Tuples: []openfga.Tuple{conditional, tuple("user:*", "can_view", "document:3")},
+		}, nil),
+		mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{
+			Tuples: []openfga.Tuple{tuple("organization:acme#member", "member", "space:1")},
+		}, nil),
+	)
+
+	audit, err := c.AuditPublicAccess(context.Background(), []string{"document", "space"}, fga.WithAllowedPublicRelations(fga.CanView))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"document:", "document:", "space:"}, objects)
+	assert.Equal(t, 5, audit.Scanned)
+	assert.Zero(t, audit.Removed)
+	assert.Equal(t, []fga.PublicAccessFinding{
+		{ObjectType: "document", ObjectID: "1", Relation: "viewer", Subject: "user:*"},
+		{ObjectType: "document", ObjectID: "2", Relation: "editor", Subject: "service:*", Condition: "valid_until"},
+	}, audit.Findings)
+}
+
+func TestClient_AuditPublicAccess_Remediation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockRead := fgamock.NewMockSdkClientReadRequestInterface(ctrl)
+	mockWrite := fgamock.NewMockSdkClientWriteRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	mockSdk.EXPECT().Read(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Body(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Options(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{
+		Tuples: []openfga.Tuple{tuple("user:*", "viewer", "document:1")},
+	}, nil)
+
+	mockSdk.EXPECT().Write(gomock.Any()).Return(mockWrite)
+	mockWrite.EXPECT().Body(gomock.Any()).DoAndReturn(func(body client.ClientWriteRequest) client.SdkClientWriteRequestInterface {
+		assert.Empty(t, body.Writes)
+		assert.Equal(t, []openfga.TupleKeyWithoutCondition{{User: "user:*", Relation: "viewer", Object: "document:1"}}, body.Deletes)
+
+		return mockWrite
+	})
+	mockWrite.EXPECT().Options(gomock.Any()).Return(mockWrite)
+	mockWrite.EXPECT().Execute().Return(&client.ClientWriteResponse{}, nil)
+
+	audit, err := c.AuditPublicAccess(context.Background(), []string{"document"}, fga.WithRemediation())
+	require.NoError(t, err)
+	assert.Equal(t, 1, audit.Removed)
+	require.Len(t, audit.Findings, 1)
+	assert.True(t, audit.Findings[0].Removed)
+}
+
+func TestClient_AuditPublicAccess_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockRead := fgamock.NewMockSdkClientReadRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+	ctx := context.Background()
+
+	_, err := c.AuditPublicAccess(ctx, nil)
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+
+	_, err = c.AuditPublicAccess(ctx, []string{"document", ""})
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+
+	mockSdk.EXPECT().Read(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Body(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Options(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Execute().Return(nil, ErrClientError)
+
+	_, err = c.AuditPublicAccess(ctx, []string{"document"}, fga.WithRemediation())
+	assert.ErrorIs(t, err, ErrClientError)
+}
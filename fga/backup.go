@@ -0,0 +1,261 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/rs/zerolog/log"
+)
+
+// Page and batch sizes for tuple backups. OpenFGA returns at most 100
+// tuples per read and accepts at most 100 tuple changes per write request
+// by default.
+const (
+	// ExportPageSize is the number of tuples read per page during an export.
+	ExportPageSize = 100
+	// DefaultImportBatchSize is the number of tuples written per batch.
+	DefaultImportBatchSize = 100
+	// MaxImportBatchSize is the largest supported batch size.
+	MaxImportBatchSize = 100
+	// MaxImportLineSize is the longest NDJSON line accepted by ImportTuples.
+	MaxImportLineSize = 1 << 20
+)
+
+// ExportTuples writes all tuples of the store to w as NDJSON, one
+// openfga.TupleKey per line, including its condition. Tuples are read page
+// by page, so the export does not hold the store in memory. It returns the
+// number of exported tuples.
+//
+// The export is not a consistent snapshot: tuples written or deleted while
+// it runs may or may not be included.
+//
+// Example:
+//
+//	f, _ := os.Create("tuples.ndjson")
+//	n, err := client.ExportTuples(ctx, f)
+func (c *Client) ExportTuples(ctx context.Context, w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	pageSize := int32(ExportPageSize)
+
+	var (
+		token    string
+		exported int
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return exported, err
+		}
+
+		opts := client.ClientReadOptions{PageSize: &pageSize}
+		if token != "" {
+			opts.ContinuationToken = &token
+		}
+
+		resp, err := c.client.Read(ctx).
+			Body(client.ClientReadRequest{}).
+			Options(opts).
+			Execute()
+		if err != nil {
+			return exported, fmt.Errorf("failed to read tuples to export: %w", err)
+		}
+
+		if resp == nil {
+			return exported, ErrEmptyResponse
+		}
+
+		for _, t := range resp.Tuples {
+			if err := enc.Encode(t.Key); err != nil {
+				return exported, fmt.Errorf("failed to write exported tuple: %w", err)
+			}
+
+			exported++
+		}
+
+		if resp.ContinuationToken == "" || len(resp.Tuples) == 0 {
+			break
+		}
+
+		token = resp.ContinuationToken
+	}
+
+	if err := bw.Flush(); err != nil {
+		return exported, fmt.Errorf("failed to write exported tuples: %w", err)
+	}
+
+	log.Debug().Int("tuples", exported).Msg("exported tuples")
+
+	return exported, nil
+}
+
+// ImportProgress reports the state of a running ImportTuples.
+type ImportProgress struct {
+	// Read is the number of tuples read from the input so far.
+	Read int
+	// Written is the number of tuples sent to OpenFGA so far. Tuples that
+	// already exist in the store are ignored by OpenFGA and still counted.
+	Written int
+	// Duplicates is the number of tuples skipped because they appeared
+	// earlier in the input.
+	Duplicates int
+	// Batches is the number of write requests committed so far.
+	Batches int
+}
+
+// ImportOptions configures ImportTuples.
+type ImportOptions struct {
+	// BatchSize is the number of tuples written per request. A value <= 0
+	// uses DefaultImportBatchSize.
+	BatchSize int
+	// Progress, if set, is called after every committed batch.
+	Progress func(ImportProgress)
+}
+
+// ImportTuples writes the tuples read from r, in the NDJSON format produced
+// by ExportTuples, to the store. Blank lines are skipped.
+//
+// Tuples are written in batches of opts.BatchSize; each batch is a single
+// transactional write. Tuples that appear more than once in the input are
+// written once (the first occurrence wins), and tuples that already exist in
+// the store are ignored, so an interrupted import can be repeated.
+//
+// A malformed line aborts the import with ErrInvalidArgument; batches
+// committed before are not rolled back. The returned progress reflects what
+// was committed.
+//
+// Example:
+//
+//	f, _ := os.Open("tuples.ndjson")
+//	progress, err := client.ImportTuples(ctx, f, fga.ImportOptions{
+//	    Progress: func(p fga.ImportProgress) { log.Printf("%d tuples", p.Written) },
+//	})
+func (c *Client) ImportTuples(ctx context.Context, r io.Reader, opts ImportOptions) (ImportProgress, error) {
+	var progress ImportProgress
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+
+	if batchSize > MaxImportBatchSize {
+		return progress, fmt.Errorf("%w: batch size must not exceed %d", ErrInvalidArgument, MaxImportBatchSize)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxImportLineSize)
+
+	seen := make(map[openfga.TupleKeyWithoutCondition]struct{})
+	batch := make([]client.ClientTupleKey, 0, batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := c.importBatch(ctx, batch); err != nil {
+			return err
+		}
+
+		progress.Written += len(batch)
+		progress.Batches++
+		batch = make([]client.ClientTupleKey, 0, batchSize)
+
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+
+		return nil
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		key, err := parseBackupTuple(data)
+		if err != nil {
+			return progress, fmt.Errorf("%w: line %d: %w", ErrInvalidArgument, line, err)
+		}
+
+		progress.Read++
+
+		id := openfga.TupleKeyWithoutCondition{User: key.User, Relation: key.Relation, Object: key.Object}
+		if _, ok := seen[id]; ok {
+			progress.Duplicates++
+			continue
+		}
+
+		seen[id] = struct{}{}
+		batch = append(batch, key)
+
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return progress, err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return progress, fmt.Errorf("failed to read tuples to import: %w", err)
+	}
+
+	if err := flush(); err != nil {
+		return progress, err
+	}
+
+	log.Debug().
+		Int("read", progress.Read).
+		Int("written", progress.Written).
+		Int("duplicates", progress.Duplicates).
+		Msg("imported tuples")
+
+	return progress, nil
+}
+
+// parseBackupTuple decodes and validates a single NDJSON line.
+func parseBackupTuple(data []byte) (client.ClientTupleKey, error) {
+	var key openfga.TupleKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return client.ClientTupleKey{}, fmt.Errorf("invalid tuple: %w", err)
+	}
+
+	if key.User == "" || key.Relation == "" || key.Object == "" {
+		return client.ClientTupleKey{}, errors.New("user, relation and object are required")
+	}
+
+	return client.ClientTupleKey{
+		User:      key.User,
+		Relation:  key.Relation,
+		Object:    key.Object,
+		Condition: key.Condition,
+	}, nil
+}
+
+// importBatch writes a batch of tuples, ignoring tuples that already exist.
+func (c *Client) importBatch(ctx context.Context, batch []client.ClientTupleKey) error {
+	_, err := c.client.Write(ctx).
+		Body(client.ClientWriteRequest{Writes: batch}).
+		Options(client.ClientWriteOptions{
+			Conflict: client.ClientWriteConflictOptions{
+				OnDuplicateWrites: client.CLIENT_WRITE_REQUEST_ON_DUPLICATE_WRITES_IGNORE,
+			},
+		}).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to write imported tuples: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/kopexa-grc/common/fga/internal/fgamock"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClient_ExportTuples(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockRead := fgamock.NewMockSdkClientReadRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	condition := &openfga.RelationshipCondition{Name: "in_region", Context: &map[string]any{"region": "eu"}}
+
+	mockSdk.EXPECT().Read(gomock.Any()).Return(mockRead).Times(2)
+	mockRead.EXPECT().Body(client.ClientReadRequest{}).Return(mockRead).Times(2)
+
+	var tokens []*string
+
+	mockRead.EXPECT().Options(gomock.Any()).DoAndReturn(func(opts client.ClientReadOptions) client.SdkClientReadRequestInterface {
+		assert.Equal(t, int32(fga.ExportPageSize), *opts.PageSize)
+		tokens = append(tokens, opts.ContinuationToken)
+
+		return mockRead
+	}).Times(2)
+	gomock.InOrder(
+		mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{
+			Tuples:            []openfga.Tuple{{Key: openfga.TupleKey{User: "user:1", Relation: "member", Object: "organization:a"}}},
+			ContinuationToken: "next",
+		}, nil),
+		mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{
+			Tuples: []openfga.Tuple{{Key: openfga.TupleKey{User: "user:2", Relation: "viewer", Object: "document:b", Condition: condition}}},
+		}, nil),
+	)
+
+	var buf bytes.Buffer
+
+	n, err := c.ExportTuples(context.Background(), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Nil(t, tokens[0])
+	assert.Equal(t, "next", *tokens[1])
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"user":"user:1","relation":"member","object":"organization:a"}`, lines[0])
+	assert.JSONEq(t, `{"user":"user:2","relation":"viewer","object":"document:b","condition":{"name":"in_region","context":{"region":"eu"}}}`, lines[1])
+}
+
+func TestClient_ExportTuples_ReadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockRead := fgamock.NewMockSdkClientReadRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	mockSdk.EXPECT().Read(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Body(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Options(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Execute().Return(nil, errors.New("unavailable"))
+
+	_, err := c.ExportTuples(context.Background(), &bytes.Buffer{})
+	require.Error(t, err)
+}
+
+func TestClient_ImportTuples(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockWrite := fgamock.NewMockSdkClientWriteRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	input := strings.Join([]string{
+		`{"user":"user:1","relation":"member","object":"organization:a"}`,
+		``,
+		`{"user":"user:2","relation":"viewer","object":"document:b","condition":{"name":"in_region","context":{"region":"eu"}}}`,
+		`{"user":"user:1","relation":"member","object":"organization:a"}`,
+		`{"user":"user:3","relation":"member","object":"organization:a"}`,
+	}, "\n")
+
+	var batches [][]client.ClientTupleKey
+
+	mockSdk.EXPECT().Write(gomock.Any()).Return(mockWrite).Times(2)
+	mockWrite.EXPECT().Body(gomock.Any()).DoAndReturn(func(body client.ClientWriteRequest) client.SdkClientWriteRequestInterface {
+		assert.Empty(t, body.Deletes)
+		batches = append(batches, body.Writes)
+
+		return mockWrite
+	}).Times(2)
+	mockWrite.EXPECT().Options(gomock.Any()).DoAndReturn(func(opts client.ClientWriteOptions) client.SdkClientWriteRequestInterface {
+		assert.Equal(t, client.CLIENT_WRITE_REQUEST_ON_DUPLICATE_WRITES_IGNORE, opts.Conflict.OnDuplicateWrites)
+
+		return mockWrite
+	}).Times(2)
+	mockWrite.EXPECT().Execute().Return(&client.ClientWriteResponse{}, nil).Times(2)
+
+	var reported []fga.ImportProgress
+
+	progress, err := c.ImportTuples(context.Background(), strings.NewReader(input), fga.ImportOptions{
+		BatchSize: 2,
+		Progress:  func(p fga.ImportProgress) { reported = append(reported, p) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fga.ImportProgress{Read: 4, Written: 3, Duplicates: 1, Batches: 2}, progress)
+	assert.Equal(t, []fga.ImportProgress{
+		{Read: 2, Written: 2, Batches: 1},
+		{Read: 4, Written: 3, Duplicates: 1, Batches: 2},
+	}, reported)
+
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+	assert.Equal(t, "in_region", batches[0][1].Condition.Name)
+	assert.Equal(t, "user:3", batches[1][0].User)
+}
+
+func TestClient_ImportTuples_InvalidInput(t *testing.T) {
+	c := fga.NewMockFGAClient(fgamock.NewMockSdkClient(gomock.NewController(t)))
+
+	tests := map[string]string{
+		"malformed json":   `{"user":`,
+		"missing relation": `{"user":"user:1","object":"organization:a"}`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			progress, err := c.ImportTuples(context.Background(), strings.NewReader(input), fga.ImportOptions{})
+			require.ErrorIs(t, err, fga.ErrInvalidArgument)
+			assert.Contains(t, err.Error(), "line 1")
+			assert.Zero(t, progress)
+		})
+	}
+
+	_, err := c.ImportTuples(context.Background(), strings.NewReader(""), fga.ImportOptions{BatchSize: fga.MaxImportBatchSize + 1})
+	require.ErrorIs(t, err, fga.ErrInvalidArgument)
+}
@@ -6,6 +6,7 @@ package fga
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/openfga/go-sdk/client"
@@ -161,7 +162,7 @@ func (c *Client) checkAccess(ctx context.Context, ac AccessCheck) (bool, error)
 }
 
 // checkTuple sends a check request to the FGA service and returns the result.
-// This is the low-level method that actually communicates with the FGA service.
+// Identical concurrent checks share one request if coalescing is enabled.
 //
 // Parameters:
 //   - ctx: The context for the request
@@ -171,9 +172,35 @@ func (c *Client) checkAccess(ctx context.Context, ac AccessCheck) (bool, error)
 //   - bool: True if the permission is granted, false otherwise
 //   - error: If the check fails
 func (c *Client) checkTuple(ctx context.Context, body client.ClientCheckRequest) (bool, error) {
-	data, err := c.client.Check(ctx).Body(body).Execute()
+	start := time.Now()
+
+	var (
+		allowed bool
+		err     error
+	)
+
+	if c.checks != nil {
+		allowed, err = c.coalescedCheck(ctx, body)
+	} else {
+		allowed, err = c.sendCheck(ctx, body)
+	}
+
 	if err != nil {
 		log.Error().Err(err).Interface("tuple", body).Msg("failed to check tuple")
+		c.logDecision(ctx, Decision{Subject: body.User, Relation: body.Relation, Object: body.Object, Err: err}, start)
+
+		return false, err
+	}
+
+	c.logDecision(ctx, Decision{Subject: body.User, Relation: body.Relation, Object: body.Object, Allowed: allowed}, start)
+
+	return allowed, nil
+}
+
+// sendCheck sends a single check request to the FGA service.
+func (c *Client) sendCheck(ctx context.Context, body client.ClientCheckRequest) (bool, error) {
+	data, err := c.client.Check(ctx).Body(body).Execute()
+	if err != nil {
 		return false, err
 	}
 
@@ -206,15 +233,20 @@ func (c *Client) BatchCheckObjectAccess(ctx context.Context, checks []AccessChec
 		checkRequests = append(checkRequests, *item)
 	}
 
+	start := time.Now()
+
 	results, err := c.client.BatchCheck(ctx).Body(
 		client.ClientBatchCheckRequest{
 			Checks: checkRequests,
 		},
 	).Execute()
 	if err != nil {
+		c.logBatchDecisions(ctx, checkRequests, nil, err, start)
 		return nil, err
 	}
 
+	c.logBatchDecisions(ctx, checkRequests, results, nil, start)
+
 	allowedObjects := make([]string, 0, len(checks))
 
 	for id, result := range *results.Result {
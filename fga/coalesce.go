@@ -0,0 +1,129 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kopexa-grc/common/wellknown"
+	"github.com/openfga/go-sdk/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+)
+
+// Outcome labels reported by PrometheusCoalescingMetrics.
+const (
+	// CheckOutcomeExecuted labels checks that sent a request to FGA.
+	CheckOutcomeExecuted = "executed"
+	// CheckOutcomeCoalesced labels checks answered by a request that was
+	// already in flight.
+	CheckOutcomeCoalesced = "coalesced"
+)
+
+// CoalescingMetrics observes coalesced checks.
+type CoalescingMetrics interface {
+	// ObserveCheck is called once per check; coalesced reports whether the
+	// check shared the request of an identical check already in flight.
+	ObserveCheck(ctx context.Context, coalesced bool)
+}
+
+// WithCheckCoalescing makes concurrent identical checks share one request to
+// FGA. Checks are identical if subject, relation, object, context and
+// contextual tuples are equal. Only checks in flight are shared; results are
+// not cached. The shared request is not cancelled when a caller gives up, so
+// that the remaining callers still get an answer; each caller still returns
+// when its own context is done.
+//
+// Example:
+//
+//	client, err := fga.NewClient("https://api.openfga.example",
+//	    fga.WithCheckCoalescing(),
+//	)
+func WithCheckCoalescing() Option {
+	return func(c *Client) {
+		c.checks = &singleflight.Group{}
+	}
+}
+
+// WithCoalescingMetrics sets the metrics observing coalesced checks. It has
+// no effect without WithCheckCoalescing.
+func WithCoalescingMetrics(m CoalescingMetrics) Option {
+	return func(c *Client) {
+		c.coalescingMetrics = m
+	}
+}
+
+// coalescedCheck sends body to FGA unless an identical check is in flight, in
+// which case it waits for that check's result.
+func (c *Client) coalescedCheck(ctx context.Context, body client.ClientCheckRequest) (bool, error) {
+	key, err := json.Marshal(body)
+	if err != nil {
+		return c.sendCheck(ctx, body)
+	}
+
+	executed := false
+
+	ch := c.checks.DoChan(string(key), func() (any, error) {
+		executed = true
+
+		return c.sendCheck(context.WithoutCancel(ctx), body)
+	})
+
+	select {
+	case res := <-ch:
+		// executed is written before the result is sent on ch.
+		if c.coalescingMetrics != nil {
+			c.coalescingMetrics.ObserveCheck(ctx, !executed)
+		}
+
+		if res.Err != nil {
+			return false, res.Err
+		}
+
+		return res.Val.(bool), nil //nolint:forcetypeassert
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// PrometheusCoalescingMetrics counts checks by whether they were coalesced.
+type PrometheusCoalescingMetrics struct {
+	checks *prometheus.CounterVec
+}
+
+// NewPrometheusCoalescingMetrics creates the check counter and registers it
+// with reg.
+//
+// Example:
+//
+//	metrics, err := fga.NewPrometheusCoalescingMetrics(registry)
+//	if err != nil {
+//	    return err
+//	}
+//	client, err := fga.NewClient(host, fga.WithCheckCoalescing(), fga.WithCoalescingMetrics(metrics))
+func NewPrometheusCoalescingMetrics(reg prometheus.Registerer) (*PrometheusCoalescingMetrics, error) {
+	checks := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: wellknown.PrometheusNamespaceKopexa,
+		Subsystem: "fga",
+		Name:      "checks_total",
+		Help:      "Total number of FGA checks by outcome (executed or coalesced).",
+	}, []string{"outcome"})
+
+	if err := reg.Register(checks); err != nil {
+		return nil, err
+	}
+
+	return &PrometheusCoalescingMetrics{checks: checks}, nil
+}
+
+// ObserveCheck implements CoalescingMetrics.
+func (m *PrometheusCoalescingMetrics) ObserveCheck(_ context.Context, coalesced bool) {
+	outcome := CheckOutcomeExecuted
+	if coalesced {
+		outcome = CheckOutcomeCoalesced
+	}
+
+	m.checks.WithLabelValues(outcome).Inc()
+}
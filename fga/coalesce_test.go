@@ -0,0 +1,172 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/kopexa-grc/common/fga/internal/fgamock"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type countingMetrics struct {
+	executed  atomic.Int32
+	coalesced atomic.Int32
+}
+
+func (m *countingMetrics) ObserveCheck(_ context.Context, coalesced bool) {
+	if coalesced {
+		m.coalesced.Add(1)
+	} else {
+		m.executed.Add(1)
+	}
+}
+
+func TestClient_CheckCoalescing(t *testing.T) {
+	const callers = 5
+
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockCheck := fgamock.NewMockSdkClientCheckRequestInterface(ctrl)
+
+	metrics := &countingMetrics{}
+	c := fga.NewMockFGAClient(mockSdk)
+	fga.WithCheckCoalescing()(c)
+	fga.WithCoalescingMetrics(metrics)(c)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	allowed := true
+
+	mockSdk.EXPECT().Check(gomock.Any()).Return(mockCheck).Times(1)
+	mockCheck.EXPECT().Body(gomock.Any()).Return(mockCheck).Times(1)
+	mockCheck.EXPECT().Execute().DoAndReturn(func() (*client.ClientCheckResponse, error) {
+		close(started)
+		<-release
+
+		return &client.ClientCheckResponse{CheckResponse: openfga.CheckResponse{Allowed: &allowed}}, nil
+	}).Times(1)
+
+	check := func() (bool, error) {
+		return c.Has().User("123").Capability("member").In("organization", "kopexa").Check(context.Background())
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results [callers]bool
+		errs    [callers]error
+	)
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		results[0], errs[0] = check()
+	}()
+
+	<-started
+
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i], errs[i] = check()
+		}()
+	}
+
+	// give the followers time to join the request in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range callers {
+		require.NoError(t, errs[i])
+		assert.True(t, results[i])
+	}
+
+	assert.Equal(t, int32(1), metrics.executed.Load())
+	assert.Equal(t, int32(callers-1), metrics.coalesced.Load())
+}
+
+func TestClient_CheckCoalescingDistinctChecks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockCheck := fgamock.NewMockSdkClientCheckRequestInterface(ctrl)
+
+	c := fga.NewMockFGAClient(mockSdk)
+	fga.WithCheckCoalescing()(c)
+
+	allowed := true
+
+	mockSdk.EXPECT().Check(gomock.Any()).Return(mockCheck).Times(2)
+	mockCheck.EXPECT().Body(gomock.Any()).Return(mockCheck).Times(2)
+	mockCheck.EXPECT().Execute().Return(&client.ClientCheckResponse{CheckResponse: openfga.CheckResponse{Allowed: &allowed}}, nil).Times(2)
+
+	for _, relation := range []string{"member", "owner"} {
+		got, err := c.Has().User("123").Capability(relation).In("organization", "kopexa").Check(context.Background())
+		require.NoError(t, err)
+		assert.True(t, got)
+	}
+}
+
+func TestClient_CheckCoalescingCallerCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockCheck := fgamock.NewMockSdkClientCheckRequestInterface(ctrl)
+
+	c := fga.NewMockFGAClient(mockSdk)
+	fga.WithCheckCoalescing()(c)
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	allowed := true
+
+	mockSdk.EXPECT().Check(gomock.Any()).Return(mockCheck).Times(1)
+	mockCheck.EXPECT().Body(gomock.Any()).Return(mockCheck).Times(1)
+	mockCheck.EXPECT().Execute().DoAndReturn(func() (*client.ClientCheckResponse, error) {
+		defer close(done)
+		<-release
+
+		return &client.ClientCheckResponse{CheckResponse: openfga.CheckResponse{Allowed: &allowed}}, nil
+	}).Times(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.Has().User("123").Capability("member").In("organization", "kopexa").Check(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	<-done
+}
+
+func TestPrometheusCoalescingMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	m, err := fga.NewPrometheusCoalescingMetrics(reg)
+	require.NoError(t, err)
+
+	m.ObserveCheck(context.Background(), false)
+	m.ObserveCheck(context.Background(), true)
+	m.ObserveCheck(context.Background(), true)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(reg, "kopexa_fga_checks_total"))
+
+	_, err = fga.NewPrometheusCoalescingMetrics(reg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,319 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"sort"
+	"time"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/language/pkg/go/transformer"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Names of the conditions built by the helpers in this file. The model must
+// declare them as follows:
+//
+//	condition valid_until(current_time: timestamp, expires_at: timestamp) {
+//	    current_time < expires_at
+//	}
+//
+//	condition in_ip_range(user_ip: ipaddress, cidr: string) {
+//	    user_ip.in_cidr(cidr)
+//	}
+//
+//	condition require_mfa(mfa: bool) {
+//	    mfa
+//	}
+const (
+	ConditionValidUntil = "valid_until"
+	ConditionIPRange    = "in_ip_range"
+	ConditionRequireMFA = "require_mfa"
+)
+
+// Parameters of the built-in conditions. Parameters stored on the tuple are
+// set by the helpers; the others are provided with RequestContext at check time.
+const (
+	ConditionParamCurrentTime = "current_time"
+	ConditionParamExpiresAt   = "expires_at"
+	ConditionParamUserIP      = "user_ip"
+	ConditionParamCIDR        = "cidr"
+	ConditionParamMFA         = "mfa"
+)
+
+// ValidUntil returns a condition that grants access until t.
+//
+// Example:
+//
+//	key := fga.TupleKey{..., Condition: fga.ValidUntil(time.Now().Add(24 * time.Hour))}
+func ValidUntil(t time.Time) Condition {
+	return Condition{
+		Name: ConditionValidUntil,
+		Context: &map[string]any{
+			ConditionParamExpiresAt: t.UTC().Format(time.RFC3339),
+		},
+	}
+}
+
+// IPRange returns a condition that grants access to requests from cidr.
+// Returns an error wrapping ErrInvalidArgument if cidr is not a valid prefix.
+func IPRange(cidr string) (Condition, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return Condition{}, fmt.Errorf("%w: invalid CIDR %q", ErrInvalidArgument, cidr)
+	}
+
+	return Condition{
+		Name: ConditionIPRange,
+		Context: &map[string]any{
+			ConditionParamCIDR: prefix.Masked().String(),
+		},
+	}, nil
+}
+
+// RequireMFA returns a condition that grants access only to requests made
+// with a multi-factor authenticated session.
+func RequireMFA() Condition {
+	return Condition{Name: ConditionRequireMFA}
+}
+
+// RequestContext holds the request attributes evaluated by the built-in
+// conditions. It is passed as the Context of an AccessCheck or ListRequest.
+type RequestContext struct {
+	// Time is the time of the request; defaults to the current time.
+	Time time.Time
+	// IP is the client address of the request. It is omitted if empty.
+	IP string
+	// MFA is set if the session was authenticated with a second factor.
+	MFA bool
+}
+
+// Context returns the request context in the form expected by OpenFGA.
+//
+// Example:
+//
+//	allowed, err := client.CheckAccess(ctx, fga.AccessCheck{
+//	    ...,
+//	    Context: fga.RequestContext{IP: r.RemoteAddr, MFA: session.MFA}.Context(),
+//	})
+func (r RequestContext) Context() *map[string]any {
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	ctx := map[string]any{
+		ConditionParamCurrentTime: now.UTC().Format(time.RFC3339),
+		ConditionParamMFA:         r.MFA,
+	}
+
+	if r.IP != "" {
+		ctx[ConditionParamUserIP] = r.IP
+	}
+
+	return &ctx
+}
+
+// ConditionSchema holds the conditions declared by an authorization model
+// and validates tuple conditions against their parameters before they are
+// written.
+type ConditionSchema struct {
+	conditions map[string]map[string]openfga.ConditionParamTypeRef
+}
+
+// NewConditionSchema creates a ConditionSchema from the conditions of model.
+func NewConditionSchema(model openfga.AuthorizationModel) *ConditionSchema {
+	s := &ConditionSchema{conditions: make(map[string]map[string]openfga.ConditionParamTypeRef)}
+
+	for name, cond := range model.GetConditions() {
+		params := make(map[string]openfga.ConditionParamTypeRef)
+		if cond.Parameters != nil {
+			for p, ref := range *cond.Parameters {
+				params[p] = ref
+			}
+		}
+
+		s.conditions[name] = params
+	}
+
+	return s
+}
+
+// NewConditionSchemaFromDSL creates a ConditionSchema from an FGA DSL model.
+func NewConditionSchemaFromDSL(dsl []byte) (*ConditionSchema, error) {
+	model, err := parseModelDSL(dsl)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConditionSchema(model), nil
+}
+
+// parseModelDSL parses an FGA DSL model into the SDK representation.
+func parseModelDSL(dsl []byte) (openfga.AuthorizationModel, error) {
+	var model openfga.AuthorizationModel
+
+	parsed, err := transformer.TransformDSLToProto(string(dsl))
+	if err != nil {
+		return model, fmt.Errorf("%w: %w", ErrFailedToTransformModel, err)
+	}
+
+	data, err := protojson.Marshal(parsed)
+	if err != nil {
+		return model, err
+	}
+
+	if err := json.Unmarshal(data, &model); err != nil {
+		return model, err
+	}
+
+	return model, nil
+}
+
+// Conditions returns the names of the declared conditions in sorted order.
+func (s *ConditionSchema) Conditions() []string {
+	names := make([]string, 0, len(s.conditions))
+	for name := range s.conditions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Validate checks that c is declared by the model and that every value in
+// its context is a declared parameter of a matching type. Parameters missing
+// from the context are expected at check time and are not reported.
+// An empty condition is valid.
+//
+// Returns an error wrapping ErrUnknownCondition or ErrInvalidConditionContext.
+func (s *ConditionSchema) Validate(c Condition) error {
+	if c.Name == "" {
+		return nil
+	}
+
+	params, ok := s.conditions[c.Name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCondition, c.Name)
+	}
+
+	if c.Context == nil {
+		return nil
+	}
+
+	for key, value := range *c.Context {
+		ref, ok := params[key]
+		if !ok {
+			return fmt.Errorf("%w: %s has no parameter %q", ErrInvalidConditionContext, c.Name, key)
+		}
+
+		if !matchesParamType(ref, value) {
+			return fmt.Errorf("%w: %s parameter %q must be of type %s", ErrInvalidConditionContext, c.Name, key, ref.TypeName)
+		}
+	}
+
+	return nil
+}
+
+// ValidateTuples validates the conditions of keys.
+func (s *ConditionSchema) ValidateTuples(keys ...TupleKey) error {
+	for _, k := range keys {
+		if err := s.Validate(k.Condition); err != nil {
+			return fmt.Errorf("%s %s %s: %w", k.Subject, k.Relation, k.Object, err)
+		}
+	}
+
+	return nil
+}
+
+// matchesParamType reports whether value can be passed as a parameter of
+// type ref. Values are expected in their JSON form, so timestamps,
+// durations and IP addresses are strings.
+func matchesParamType(ref openfga.ConditionParamTypeRef, value any) bool {
+	switch ref.TypeName {
+	case openfga.TYPENAME_ANY, openfga.TYPENAME_UNSPECIFIED:
+		return true
+	case openfga.TYPENAME_BOOL:
+		_, ok := value.(bool)
+		return ok
+	case openfga.TYPENAME_STRING:
+		_, ok := value.(string)
+		return ok
+	case openfga.TYPENAME_INT, openfga.TYPENAME_UINT, openfga.TYPENAME_DOUBLE:
+		return matchesNumber(ref.TypeName, value)
+	case openfga.TYPENAME_TIMESTAMP:
+		switch v := value.(type) {
+		case time.Time:
+			return true
+		case string:
+			_, err := time.Parse(time.RFC3339, v)
+			return err == nil
+		}
+
+		return false
+	case openfga.TYPENAME_DURATION:
+		switch v := value.(type) {
+		case time.Duration:
+			return true
+		case string:
+			_, err := time.ParseDuration(v)
+			return err == nil
+		}
+
+		return false
+	case openfga.TYPENAME_IPADDRESS:
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+
+		_, err := netip.ParseAddr(s)
+
+		return err == nil
+	case openfga.TYPENAME_LIST:
+		rv := reflect.ValueOf(value)
+		return rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array
+	case openfga.TYPENAME_MAP:
+		rv := reflect.ValueOf(value)
+		return rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String
+	}
+
+	return false
+}
+
+// matchesNumber reports whether value is a number of the given type.
+// Whole float64 values are accepted for integers as JSON decodes all
+// numbers as float64.
+func matchesNumber(typ openfga.TypeName, value any) bool {
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return typ != openfga.TYPENAME_UINT || rv.Int() >= 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if typ == openfga.TYPENAME_DOUBLE {
+			return true
+		}
+
+		return f == float64(int64(f)) && (typ != openfga.TYPENAME_UINT || f >= 0)
+	}
+
+	return false
+}
+
+// WithCondition sets the condition of the tuple request.
+func (r *TupleRequest) WithCondition(c Condition) *TupleRequest {
+	r.ConditionName = c.Name
+	r.ConditionContext = c.Context
+
+	return r
+}
@@ -0,0 +1,136 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadConditionSchema(t *testing.T) *fga.ConditionSchema {
+	t.Helper()
+
+	dsl, err := os.ReadFile("testdata/conditions.fga")
+	require.NoError(t, err)
+
+	s, err := fga.NewConditionSchemaFromDSL(dsl)
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestConditionHelpers(t *testing.T) {
+	expires := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+
+	c := fga.ValidUntil(expires)
+	assert.Equal(t, fga.ConditionValidUntil, c.Name)
+	assert.Equal(t, map[string]any{"expires_at": "2026-01-02T02:04:05Z"}, *c.Context)
+
+	c, err := fga.IPRange("10.1.2.3/16")
+	require.NoError(t, err)
+	assert.Equal(t, fga.ConditionIPRange, c.Name)
+	assert.Equal(t, map[string]any{"cidr": "10.1.0.0/16"}, *c.Context)
+
+	_, err = fga.IPRange("10.1.2.3")
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+
+	c = fga.RequireMFA()
+	assert.Equal(t, fga.ConditionRequireMFA, c.Name)
+	assert.Nil(t, c.Context)
+
+	req := (&fga.TupleRequest{SubjectID: "1", ObjectID: "2", ObjectType: "document", Relation: "viewer"}).
+		WithSubjectType("user").
+		WithCondition(fga.ValidUntil(expires))
+	assert.Equal(t, fga.ValidUntil(expires), fga.GetTupleKey(*req).Condition)
+}
+
+func TestRequestContext(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	ctx := fga.RequestContext{Time: now, IP: "10.0.0.1", MFA: true}.Context()
+	assert.Equal(t, map[string]any{
+		"current_time": "2026-01-02T03:04:05Z",
+		"user_ip":      "10.0.0.1",
+		"mfa":          true,
+	}, *ctx)
+
+	ctx = fga.RequestContext{}.Context()
+	assert.NotContains(t, *ctx, "user_ip")
+	assert.Contains(t, *ctx, "current_time")
+	assert.Equal(t, false, (*ctx)["mfa"])
+}
+
+func TestConditionSchema_Validate(t *testing.T) {
+	s := loadConditionSchema(t)
+
+	assert.Equal(t, []string{"in_ip_range", "max_amount", "require_mfa", "valid_until"}, s.Conditions())
+
+	ipRange, err := fga.IPRange("192.168.0.0/24")
+	require.NoError(t, err)
+
+	for _, c := range []fga.Condition{{}, fga.ValidUntil(time.Now()), ipRange, fga.RequireMFA()} {
+		assert.NoError(t, s.Validate(c), c.Name)
+	}
+
+	tests := []struct {
+		name    string
+		context map[string]any
+		err     error
+	}{
+		{name: "valid", context: map[string]any{"amount": 1.5, "limit": float64(10), "tags": []any{"a"}}},
+		{name: "int for double", context: map[string]any{"amount": 2}},
+		{name: "fractional int", context: map[string]any{"limit": 1.5}, err: fga.ErrInvalidConditionContext},
+		{name: "string for int", context: map[string]any{"limit": "10"}, err: fga.ErrInvalidConditionContext},
+		{name: "not a list", context: map[string]any{"tags": "a"}, err: fga.ErrInvalidConditionContext},
+		{name: "undeclared", context: map[string]any{"currency": "EUR"}, err: fga.ErrInvalidConditionContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate(fga.Condition{Name: "max_amount", Context: &tt.context})
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	err = s.Validate(fga.Condition{Name: "valid_until", Context: &map[string]any{"expires_at": "tomorrow"}})
+	assert.ErrorIs(t, err, fga.ErrInvalidConditionContext)
+
+	err = s.Validate(fga.Condition{Name: "in_ip_range", Context: &map[string]any{"user_ip": "10.0.0.300"}})
+	assert.ErrorIs(t, err, fga.ErrInvalidConditionContext)
+
+	err = s.Validate(fga.Condition{Name: "geo_fence"})
+	assert.ErrorIs(t, err, fga.ErrUnknownCondition)
+}
+
+func TestConditionSchema_ValidateTuples(t *testing.T) {
+	s := loadConditionSchema(t)
+
+	ok := fga.TupleKey{
+		Subject:   fga.Entity{Kind: "user", Identifier: "1"},
+		Object:    fga.Entity{Kind: "document", Identifier: "2"},
+		Relation:  "viewer",
+		Condition: fga.RequireMFA(),
+	}
+	bad := ok
+	bad.Condition = fga.Condition{Name: "geo_fence"}
+
+	require.NoError(t, s.ValidateTuples(ok))
+
+	err := s.ValidateTuples(ok, bad)
+	assert.ErrorIs(t, err, fga.ErrUnknownCondition)
+	assert.Contains(t, err.Error(), "user:1 viewer document:2")
+
+	_, err = fga.NewConditionSchemaFromDSL([]byte("model\n  schema"))
+	assert.ErrorIs(t, err, fga.ErrFailedToTransformModel)
+}
@@ -0,0 +1,329 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/rs/zerolog/log"
+)
+
+// Default settings for the AsyncDecisionLogger.
+const (
+	// DefaultDecisionBufferSize is the number of decisions buffered before
+	// new decisions are dropped.
+	DefaultDecisionBufferSize = 1024
+	// DefaultDecisionBatchSize is the maximum number of decisions written to
+	// the sink at once.
+	DefaultDecisionBatchSize = 100
+	// DefaultDecisionFlushInterval is the maximum time a decision stays in
+	// the buffer before it is written.
+	DefaultDecisionFlushInterval = time.Second
+)
+
+// Decision is the outcome of a single authorization check.
+type Decision struct {
+	// Subject is the checked subject, e.g. "user:123".
+	Subject string
+	// Relation is the checked relation, e.g. "can_view".
+	Relation string
+	// Object is the checked object, e.g. "document:456".
+	Object string
+	// Allowed reports whether access was granted.
+	Allowed bool
+	// Err is set if the check failed; Allowed is false in that case.
+	Err error
+	// Latency is the duration of the request to FGA. Checks of the same
+	// batch share the latency of the batch request.
+	Latency time.Duration
+	// ModelID is the authorization model the check was evaluated against.
+	// It is empty if the client uses the latest model.
+	ModelID string
+	// Batch is true if the check was part of a batch check.
+	Batch bool
+	// Timestamp is the time the check was issued.
+	Timestamp time.Time
+}
+
+// DecisionLogger records authorization decisions, e.g. for audit trails.
+// LogDecision is called synchronously on every check and must not block;
+// use AsyncDecisionLogger to write decisions to slow destinations.
+type DecisionLogger interface {
+	LogDecision(ctx context.Context, d Decision)
+}
+
+// DecisionLoggerFunc adapts a function to the DecisionLogger interface.
+type DecisionLoggerFunc func(ctx context.Context, d Decision)
+
+// LogDecision calls f(ctx, d).
+func (f DecisionLoggerFunc) LogDecision(ctx context.Context, d Decision) {
+	f(ctx, d)
+}
+
+// WithDecisionLogger sets the logger that is invoked for every decision made
+// by CheckAccess, Has().Check and the batch check methods.
+//
+// Example:
+//
+//	client, err := fga.NewClient("https://api.openfga.example",
+//	    fga.WithDecisionLogger(fga.SampleDecisions(auditLogger, 0.1, 1)),
+//	)
+func WithDecisionLogger(l DecisionLogger) Option {
+	return func(c *Client) {
+		c.decisionLogger = l
+	}
+}
+
+// logDecision reports a decision to the configured DecisionLogger, if any.
+func (c *Client) logDecision(ctx context.Context, d Decision, start time.Time) {
+	if c.decisionLogger == nil {
+		return
+	}
+
+	d.Timestamp = start
+	d.Latency = time.Since(start)
+
+	if c.config != nil {
+		d.ModelID = c.config.AuthorizationModelId
+	}
+
+	c.decisionLogger.LogDecision(ctx, d)
+}
+
+// logBatchDecisions reports one decision per batch check item. If the batch
+// request failed, err is reported for every item.
+func (c *Client) logBatchDecisions(ctx context.Context, checks []client.ClientBatchCheckItem, res *openfga.BatchCheckResponse, err error, start time.Time) {
+	if c.decisionLogger == nil {
+		return
+	}
+
+	var results map[string]openfga.BatchCheckSingleResult
+	if res != nil && res.Result != nil {
+		results = *res.Result
+	}
+
+	for _, check := range checks {
+		d := Decision{
+			Subject:  check.User,
+			Relation: check.Relation,
+			Object:   check.Object,
+			Batch:    true,
+			Err:      err,
+		}
+
+		if err == nil {
+			result, ok := results[check.CorrelationId]
+
+			switch {
+			case !ok:
+				d.Err = ErrEmptyBatchCheckResponse
+			case result.HasError():
+				batchErr := result.GetError()
+				d.Err = fmt.Errorf("%w: %s", ErrBatchCheckItem, batchErr.GetMessage())
+			default:
+				d.Allowed = result.GetAllowed()
+			}
+		}
+
+		c.logDecision(ctx, d, start)
+	}
+}
+
+// SampleDecisions returns a DecisionLogger that forwards only a fraction of
+// the decisions to next. Granted decisions are forwarded with probability
+// allowRate, denied and failed decisions with probability denyRate. Rates
+// are clamped to [0, 1].
+//
+// Example:
+//
+//	// keep every denial but only 5% of the granted checks
+//	logger := fga.SampleDecisions(next, 0.05, 1)
+func SampleDecisions(next DecisionLogger, allowRate, denyRate float64) DecisionLogger {
+	return DecisionLoggerFunc(func(ctx context.Context, d Decision) {
+		rate := denyRate
+		if d.Allowed {
+			rate = allowRate
+		}
+
+		if sampled(rate) {
+			next.LogDecision(ctx, d)
+		}
+	})
+}
+
+func sampled(rate float64) bool {
+	switch {
+	case rate >= 1:
+		return true
+	case rate <= 0:
+		return false
+	default:
+		return rand.Float64() < rate //nolint:gosec // sampling does not need a secure source
+	}
+}
+
+// DecisionSink is the destination of an AsyncDecisionLogger.
+type DecisionSink interface {
+	// WriteDecisions persists a batch of decisions.
+	WriteDecisions(ctx context.Context, decisions []Decision) error
+}
+
+// AsyncDecisionLogger is a DecisionLogger that buffers decisions and writes
+// them to a DecisionSink in batches from a background goroutine, so that
+// checks are never slowed down by the sink.
+//
+// When the buffer is full, new decisions are dropped and counted; see
+// Dropped. Close must be called to flush the remaining decisions.
+type AsyncDecisionLogger struct {
+	sink          DecisionSink
+	bufferSize    int
+	batchSize     int
+	flushInterval time.Duration
+
+	ch      chan Decision
+	done    chan struct{}
+	dropped atomic.Int64
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// AsyncDecisionOption configures an AsyncDecisionLogger.
+type AsyncDecisionOption func(*AsyncDecisionLogger)
+
+// WithDecisionBufferSize sets the number of buffered decisions.
+func WithDecisionBufferSize(n int) AsyncDecisionOption {
+	return func(l *AsyncDecisionLogger) {
+		if n > 0 {
+			l.bufferSize = n
+		}
+	}
+}
+
+// WithDecisionBatchSize sets the maximum number of decisions per write.
+func WithDecisionBatchSize(n int) AsyncDecisionOption {
+	return func(l *AsyncDecisionLogger) {
+		if n > 0 {
+			l.batchSize = n
+		}
+	}
+}
+
+// WithDecisionFlushInterval sets the maximum time decisions are buffered.
+func WithDecisionFlushInterval(d time.Duration) AsyncDecisionOption {
+	return func(l *AsyncDecisionLogger) {
+		if d > 0 {
+			l.flushInterval = d
+		}
+	}
+}
+
+// NewAsyncDecisionLogger creates an AsyncDecisionLogger writing to sink and
+// starts its background goroutine.
+func NewAsyncDecisionLogger(sink DecisionSink, opts ...AsyncDecisionOption) *AsyncDecisionLogger {
+	l := &AsyncDecisionLogger{
+		sink:          sink,
+		bufferSize:    DefaultDecisionBufferSize,
+		batchSize:     DefaultDecisionBatchSize,
+		flushInterval: DefaultDecisionFlushInterval,
+		done:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	l.ch = make(chan Decision, l.bufferSize)
+
+	go l.run()
+
+	return l
+}
+
+// LogDecision implements DecisionLogger. It never blocks.
+func (l *AsyncDecisionLogger) LogDecision(_ context.Context, d Decision) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		l.dropped.Add(1)
+		return
+	}
+
+	select {
+	case l.ch <- d:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of decisions dropped because the buffer was
+// full or the logger was closed.
+func (l *AsyncDecisionLogger) Dropped() int64 {
+	return l.dropped.Load()
+}
+
+// Close stops accepting decisions and waits until the buffered decisions
+// have been written or ctx is done.
+func (l *AsyncDecisionLogger) Close(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.ch)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run collects decisions into batches and writes them to the sink.
+func (l *AsyncDecisionLogger) run() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Decision, 0, l.batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := l.sink.WriteDecisions(context.Background(), batch); err != nil {
+			log.Error().Err(err).Int("count", len(batch)).Msg("failed to write authorization decisions")
+		}
+
+		batch = make([]Decision, 0, l.batchSize)
+	}
+
+	for {
+		select {
+		case d, ok := <-l.ch:
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, d)
+			if len(batch) >= l.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
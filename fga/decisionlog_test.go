@@ -0,0 +1,155 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/fga/internal/fgamock"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// recordingLogger collects decisions in memory.
+type recordingLogger struct {
+	mu        sync.Mutex
+	decisions []Decision
+}
+
+func (r *recordingLogger) LogDecision(_ context.Context, d Decision) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decisions = append(r.decisions, d)
+}
+
+func (r *recordingLogger) WriteDecisions(ctx context.Context, decisions []Decision) error {
+	for _, d := range decisions {
+		r.LogDecision(ctx, d)
+	}
+
+	return nil
+}
+
+func (r *recordingLogger) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.decisions)
+}
+
+func TestClient_DecisionLogger_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockCheck := fgamock.NewMockSdkClientCheckRequestInterface(ctrl)
+	logger := &recordingLogger{}
+
+	c := &Client{
+		client:         mockSdk,
+		config:         &client.ClientConfiguration{AuthorizationModelId: "model-1"},
+		decisionLogger: logger,
+	}
+
+	allowed := true
+
+	mockSdk.EXPECT().Check(gomock.Any()).Return(mockCheck).Times(2)
+	mockCheck.EXPECT().Body(gomock.Any()).Return(mockCheck).Times(2)
+	gomock.InOrder(
+		mockCheck.EXPECT().Execute().Return(&client.ClientCheckResponse{CheckResponse: openfga.CheckResponse{Allowed: &allowed}}, nil),
+		mockCheck.EXPECT().Execute().Return(nil, assert.AnError),
+	)
+
+	ok, err := c.CheckAccess(t.Context(), AccessCheck{SubjectType: "user", SubjectID: "123", Relation: "viewer", ObjectType: "document", ObjectID: "456"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = c.Has().User("123").Capability("editor").In("document", "456").Check(t.Context())
+	require.Error(t, err)
+
+	require.Len(t, logger.decisions, 2)
+
+	d := logger.decisions[0]
+	assert.Equal(t, "user:123", d.Subject)
+	assert.Equal(t, "viewer", d.Relation)
+	assert.Equal(t, "document:456", d.Object)
+	assert.True(t, d.Allowed)
+	assert.NoError(t, d.Err)
+	assert.Equal(t, "model-1", d.ModelID)
+	assert.False(t, d.Timestamp.IsZero())
+	assert.False(t, d.Batch)
+
+	assert.False(t, logger.decisions[1].Allowed)
+	assert.ErrorIs(t, logger.decisions[1].Err, assert.AnError)
+}
+
+func TestClient_LogBatchDecisions(t *testing.T) {
+	logger := &recordingLogger{}
+	c := &Client{decisionLogger: logger}
+
+	checks := []client.ClientBatchCheckItem{
+		{User: "user:1", Relation: "viewer", Object: "document:1", CorrelationId: "a"},
+		{User: "user:1", Relation: "viewer", Object: "document:2", CorrelationId: "b"},
+		{User: "user:1", Relation: "viewer", Object: "document:3", CorrelationId: "c"},
+	}
+
+	allowed := true
+	msg := "boom"
+	results := map[string]openfga.BatchCheckSingleResult{
+		"a": {Allowed: &allowed},
+		"b": {Error: &openfga.CheckError{Message: &msg}},
+	}
+
+	c.logBatchDecisions(context.Background(), checks, &openfga.BatchCheckResponse{Result: &results}, nil, time.Now())
+
+	require.Len(t, logger.decisions, 3)
+	assert.True(t, logger.decisions[0].Allowed)
+	assert.True(t, logger.decisions[0].Batch)
+	assert.ErrorIs(t, logger.decisions[1].Err, ErrBatchCheckItem)
+	assert.ErrorIs(t, logger.decisions[2].Err, ErrEmptyBatchCheckResponse)
+}
+
+func TestSampleDecisions(t *testing.T) {
+	logger := &recordingLogger{}
+	sampler := SampleDecisions(logger, 0, 1)
+
+	sampler.LogDecision(context.Background(), Decision{Allowed: true})
+	sampler.LogDecision(context.Background(), Decision{Allowed: false})
+
+	require.Len(t, logger.decisions, 1)
+	assert.False(t, logger.decisions[0].Allowed)
+}
+
+func TestAsyncDecisionLogger(t *testing.T) {
+	t.Run("flushes on close", func(t *testing.T) {
+		sink := &recordingLogger{}
+		l := NewAsyncDecisionLogger(sink, WithDecisionBatchSize(2), WithDecisionFlushInterval(time.Hour))
+
+		for range 5 {
+			l.LogDecision(context.Background(), Decision{Allowed: true})
+		}
+
+		require.NoError(t, l.Close(context.Background()))
+		assert.Equal(t, 5, sink.len())
+
+		l.LogDecision(context.Background(), Decision{})
+		assert.Equal(t, int64(1), l.Dropped())
+	})
+
+	t.Run("flushes on interval", func(t *testing.T) {
+		sink := &recordingLogger{}
+		l := NewAsyncDecisionLogger(sink, WithDecisionFlushInterval(10*time.Millisecond))
+
+		defer func() { _ = l.Close(context.Background()) }()
+
+		l.LogDecision(context.Background(), Decision{})
+
+		assert.Eventually(t, func() bool { return sink.len() == 1 }, time.Second, 5*time.Millisecond)
+	})
+}
@@ -21,8 +21,17 @@ var (
 	// ErrEmptyBatchCheckResponse is returned when a batch check operation returns an empty response.
 	// This indicates that the FGA service did not return any results for the batch check request.
 	ErrEmptyBatchCheckResponse = errors.New("empty response from batch check")
+	// ErrBatchCheckItem is reported to the DecisionLogger when a single item
+	// of a batch check failed.
+	ErrBatchCheckItem = errors.New("batch check item failed")
 	// ErrFailedToTransformModel is returned when the model transformation fails
 	ErrFailedToTransformModel = errors.New("failed to transform model")
+	// ErrUnknownCondition is returned when a tuple references a condition the model does not declare.
+	ErrUnknownCondition = errors.New("unknown condition")
+	// ErrInvalidConditionContext is returned when a condition context does not match the declared parameters.
+	ErrInvalidConditionContext = errors.New("invalid condition context")
+	// ErrModelLint is returned by LintResult.Err when a model has lint errors.
+	ErrModelLint = errors.New("model lint failed")
 )
 
 // WriteError represents an error that occurred during a write operation to the FGA service.
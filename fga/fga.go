@@ -37,6 +37,7 @@ import (
 
 	"github.com/kopexa-grc/common/errors"
 	"github.com/openfga/go-sdk/client"
+	"golang.org/x/sync/singleflight"
 )
 
 // Client represents a connection to the OpenFGA service.
@@ -49,6 +50,14 @@ type Client struct {
 	// IgnoreDuplicateKeyError determines whether duplicate key errors should be ignored.
 	// When true, attempts to write duplicate tuples will be silently ignored.
 	IgnoreDuplicateKeyError bool
+
+	// decisionLogger records the outcome of every check; nil if disabled.
+	decisionLogger DecisionLogger
+
+	// checks coalesces identical concurrent checks; nil if disabled.
+	checks *singleflight.Group
+	// coalescingMetrics observes coalesced checks; nil if disabled.
+	coalescingMetrics CoalescingMetrics
 }
 
 // NewClient creates a new FGA client with the given host and options.
@@ -0,0 +1,194 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fgatest provisions disposable OpenFGA stores for integration tests.
+//
+// Every call to NewEphemeralStore creates a fresh store with its own
+// authorization model, so tests running in parallel cannot observe each
+// other's tuples. The store is deleted when the test finishes.
+//
+// The stores live on the OpenFGA server named by the FGA_TEST_URL environment
+// variable, e.g. a local instance started with docker compose. If the variable
+// is not set, an OpenFGA container is started on first use and shared by all
+// tests of the package; it is removed when the test binary exits. Tests are
+// skipped if neither is available.
+//
+// Example:
+//
+//	func TestDocumentAccess(t *testing.T) {
+//	    client := fgatest.NewEphemeralStore(t, model)
+//
+//	    _, err := client.WriteTupleKeys(t.Context(), writes, nil)
+//	    require.NoError(t, err)
+//	}
+package fgatest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	// EnvURL is the environment variable naming the OpenFGA server to use
+	// instead of starting a container.
+	EnvURL = "FGA_TEST_URL"
+
+	// DefaultImage is the OpenFGA image started if EnvURL is not set.
+	DefaultImage = "openfga/openfga:v1.8.9"
+
+	httpPort = "8080/tcp"
+
+	// maxStoreNameLength is the maximum store name length OpenFGA accepts.
+	maxStoreNameLength = 64
+)
+
+var (
+	serverOnce sync.Once
+	serverURL  string
+	serverErr  error
+
+	unsafeNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+)
+
+// Option configures NewEphemeralStore.
+type Option func(*options)
+
+type options struct {
+	clientOpts []fga.Option
+}
+
+// WithClientOptions passes additional options to the returned client, e.g.
+// fga.WithDecisionLogger.
+func WithClientOptions(opts ...fga.Option) Option {
+	return func(o *options) {
+		o.clientOpts = append(o.clientOpts, opts...)
+	}
+}
+
+// NewEphemeralStore creates a disposable store with the given authorization
+// model (FGA DSL) and returns a client configured for it. The store is
+// deleted on test cleanup.
+func NewEphemeralStore(t *testing.T, model []byte, opts ...Option) *fga.Client {
+	t.Helper()
+
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	url := serverURLFor(t)
+	ctx := context.Background()
+
+	admin, err := fga.NewClient(url)
+	if err != nil {
+		t.Fatalf("fgatest: creating client: %v", err)
+	}
+
+	storeID, err := admin.CreateNamedStore(ctx, storeName(t))
+	if err != nil {
+		t.Fatalf("fgatest: creating store: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := admin.DeleteStore(context.Background(), storeID); err != nil {
+			t.Errorf("fgatest: deleting store %s: %v", storeID, err)
+		}
+	})
+
+	modelClient, err := fga.NewClient(url, fga.WithStoreID(storeID))
+	if err != nil {
+		t.Fatalf("fgatest: creating client: %v", err)
+	}
+
+	modelID, err := modelClient.CreateModelFromDSL(ctx, model)
+	if err != nil {
+		t.Fatalf("fgatest: creating model: %v", err)
+	}
+
+	clientOpts := append([]fga.Option{
+		fga.WithStoreID(storeID),
+		fga.WithAuthorizationModelID(modelID),
+	}, o.clientOpts...)
+
+	c, err := fga.NewClient(url, clientOpts...)
+	if err != nil {
+		t.Fatalf("fgatest: creating client: %v", err)
+	}
+
+	return c
+}
+
+// serverURLFor returns the URL of the OpenFGA server, starting the shared
+// container on first use. It skips the test if no server is available.
+func serverURLFor(t *testing.T) string {
+	t.Helper()
+
+	if url := os.Getenv(EnvURL); url != "" {
+		return url
+	}
+
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	serverOnce.Do(func() {
+		serverURL, serverErr = startServer(context.Background())
+	})
+
+	if serverErr != nil {
+		t.Fatalf("fgatest: starting OpenFGA container: %v", serverErr)
+	}
+
+	return serverURL
+}
+
+// startServer starts an in-memory OpenFGA container. It is not terminated
+// explicitly; the testcontainers reaper removes it once the test binary exits.
+func startServer(ctx context.Context) (string, error) {
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        DefaultImage,
+			Cmd:          []string{"run"},
+			ExposedPorts: []string{httpPort},
+			WaitingFor:   wait.ForHTTP("/healthz").WithPort(httpPort),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	port, err := container.MappedPort(ctx, httpPort)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("http://%s:%s", host, port.Port()), nil
+}
+
+// storeName derives a unique store name from the test name, so leftover
+// stores of aborted runs can be attributed.
+func storeName(t *testing.T) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	name := unsafeNameChars.ReplaceAllString(t.Name(), "_")
+	if limit := maxStoreNameLength - len("test--") - 2*len(suffix); len(name) > limit {
+		name = name[:limit]
+	}
+
+	return "test-" + name + "-" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,40 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fgatest_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/kopexa-grc/common/fga/fgatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEphemeralStore(t *testing.T) {
+	model, err := os.ReadFile("../testdata/model.fga")
+	require.NoError(t, err)
+
+	first := fgatest.NewEphemeralStore(t, model)
+	second := fgatest.NewEphemeralStore(t, model)
+
+	_, err = first.WriteTupleKeys(t.Context(), []fga.TupleKey{{
+		Subject:  fga.Entity{Kind: "user", Identifier: "alice"},
+		Relation: "viewer",
+		Object:   fga.Entity{Kind: "document", Identifier: "doc-1"},
+	}}, nil)
+	require.NoError(t, err)
+
+	check := fga.AccessCheck{SubjectID: "alice", SubjectType: "user", ObjectType: "document", ObjectID: "doc-1", Relation: "can_view"}
+
+	allowed, err := first.CheckAccess(t.Context(), check)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// tuples do not leak into other stores
+	allowed, err = second.CheckAccess(t.Context(), check)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
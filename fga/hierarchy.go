@@ -0,0 +1,289 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Default settings for the hierarchy resolver.
+const (
+	// DefaultHierarchyTTL is the time a resolved parent is cached.
+	DefaultHierarchyTTL = 5 * time.Minute
+	// DefaultParentRelation is the relation that links an object to its parent,
+	// e.g. organization:acme#parent@space:compliance.
+	DefaultParentRelation Relation = "parent"
+	// DefaultMaxHierarchyDepth is the maximum number of ancestors resolved for
+	// a single object.
+	DefaultMaxHierarchyDepth = 8
+)
+
+// HierarchyResolver resolves the parents of objects from tuples, e.g. the
+// organization of a space or the parent of an organization, and memoizes the
+// results for a configurable TTL. Objects without a parent are cached as well.
+//
+// A parent is read from the tuple <parent>#<relation>@<object>, where the
+// relation defaults to DefaultParentRelation and can be set per object kind.
+// The resolver is safe for concurrent use by multiple goroutines.
+type HierarchyResolver struct {
+	client    *Client
+	relations map[Kind]Relation
+	ttl       time.Duration
+	maxDepth  int
+	now       func() time.Time
+
+	mu    sync.RWMutex
+	cache map[string]hierarchyEntry
+	group singleflight.Group
+}
+
+// hierarchyEntry is a cached parent lookup.
+type hierarchyEntry struct {
+	parent  Entity
+	found   bool
+	expires time.Time
+}
+
+// HierarchyOption configures a HierarchyResolver.
+type HierarchyOption func(*HierarchyResolver)
+
+// WithHierarchyTTL sets the time a resolved parent is cached.
+func WithHierarchyTTL(d time.Duration) HierarchyOption {
+	return func(r *HierarchyResolver) {
+		if d > 0 {
+			r.ttl = d
+		}
+	}
+}
+
+// WithParentRelation sets the relation that links objects of kind to their
+// parent. An empty relation marks kind as a root that has no parent.
+func WithParentRelation(kind Kind, relation Relation) HierarchyOption {
+	return func(r *HierarchyResolver) {
+		r.relations[Kind(kind.String())] = relation
+	}
+}
+
+// WithMaxHierarchyDepth sets the maximum number of ancestors resolved for a
+// single object.
+func WithMaxHierarchyDepth(n int) HierarchyOption {
+	return func(r *HierarchyResolver) {
+		if n > 0 {
+			r.maxDepth = n
+		}
+	}
+}
+
+// NewHierarchyResolver creates a new HierarchyResolver that reads tuples using client.
+//
+// Example:
+//
+//	resolver := fga.NewHierarchyResolver(client,
+//	    fga.WithHierarchyTTL(time.Minute),
+//	    fga.WithParentRelation("space", "organization"),
+//	)
+func NewHierarchyResolver(client *Client, opts ...HierarchyOption) *HierarchyResolver {
+	r := &HierarchyResolver{
+		client:    client,
+		relations: map[Kind]Relation{},
+		ttl:       DefaultHierarchyTTL,
+		maxDepth:  DefaultMaxHierarchyDepth,
+		now:       time.Now,
+		cache:     map[string]hierarchyEntry{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Parent returns the parent of obj. The boolean is false if obj has no parent.
+func (r *HierarchyResolver) Parent(ctx context.Context, obj Entity) (Entity, bool, error) {
+	obj = Entity{Kind: Kind(obj.Kind.String()), Identifier: obj.Identifier}
+	if obj.Kind == "" || obj.Identifier == "" {
+		return Entity{}, false, fmt.Errorf("%w: object kind and identifier are required", ErrInvalidArgument)
+	}
+
+	relation := r.parentRelation(obj.Kind)
+	if relation == "" {
+		return Entity{}, false, nil
+	}
+
+	key := obj.String()
+
+	if entry, ok := r.cached(key); ok {
+		return entry.parent, entry.found, nil
+	}
+
+	res, err, _ := r.group.Do(key, func() (any, error) {
+		entry, err := r.lookup(context.WithoutCancel(ctx), obj, relation)
+		if err != nil {
+			return hierarchyEntry{}, err
+		}
+
+		r.mu.Lock()
+		r.cache[key] = entry
+		r.mu.Unlock()
+
+		return entry, nil
+	})
+	if err != nil {
+		return Entity{}, false, err
+	}
+
+	entry := res.(hierarchyEntry) //nolint:forcetypeassert
+
+	return entry.parent, entry.found, nil
+}
+
+// OrganizationOf returns the ID of the organization that owns the space.
+// The boolean is false if the space has no organization.
+func (r *HierarchyResolver) OrganizationOf(ctx context.Context, spaceID string) (string, bool, error) {
+	parent, ok, err := r.Parent(ctx, Entity{Kind: "space", Identifier: spaceID})
+	if err != nil || !ok || parent.Kind != "organization" {
+		return "", false, err
+	}
+
+	return parent.Identifier, true, nil
+}
+
+// Ancestors returns the parents of obj ordered from the direct parent to the
+// root. Returns an error if the hierarchy contains a cycle or is deeper than
+// the configured maximum depth.
+func (r *HierarchyResolver) Ancestors(ctx context.Context, obj Entity) ([]Entity, error) {
+	var ancestors []Entity
+
+	seen := map[string]struct{}{obj.String(): {}}
+	current := obj
+
+	for {
+		parent, ok, err := r.Parent(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return ancestors, nil
+		}
+
+		if _, dup := seen[parent.String()]; dup {
+			return nil, fmt.Errorf("%w: hierarchy of %s contains a cycle at %s", ErrInvalidArgument, obj, parent)
+		}
+
+		if len(ancestors) == r.maxDepth {
+			return nil, fmt.Errorf("%w: hierarchy of %s exceeds %d levels", ErrInvalidArgument, obj, r.maxDepth)
+		}
+
+		seen[parent.String()] = struct{}{}
+		ancestors = append(ancestors, parent)
+		current = parent
+	}
+}
+
+// CheckInherited checks if the subject has the relation on the object or on
+// any of its ancestors. The object is checked first, followed by its
+// ancestors from the direct parent to the root; checking stops at the first
+// ancestor that grants access.
+//
+// Example:
+//
+//	allowed, err := resolver.CheckInherited(ctx, fga.AccessCheck{
+//	    SubjectID:  "user123",
+//	    Relation:   "can_view",
+//	    ObjectType: "space",
+//	    ObjectID:   "space456",
+//	})
+func (r *HierarchyResolver) CheckInherited(ctx context.Context, ac AccessCheck) (bool, error) {
+	allowed, err := r.client.CheckAccess(ctx, ac)
+	if err != nil || allowed {
+		return allowed, err
+	}
+
+	ancestors, err := r.Ancestors(ctx, Entity{Kind: Kind(ac.ObjectType), Identifier: ac.ObjectID})
+	if err != nil {
+		return false, err
+	}
+
+	for _, ancestor := range ancestors {
+		ac.ObjectType = ancestor.Kind.String()
+		ac.ObjectID = ancestor.Identifier
+
+		allowed, err := r.client.CheckAccess(ctx, ac)
+		if err != nil || allowed {
+			return allowed, err
+		}
+	}
+
+	return false, nil
+}
+
+// Invalidate removes the cached parent of obj, e.g. after a space was moved
+// to another organization.
+func (r *HierarchyResolver) Invalidate(obj Entity) {
+	obj = Entity{Kind: Kind(obj.Kind.String()), Identifier: obj.Identifier}
+
+	r.mu.Lock()
+	delete(r.cache, obj.String())
+	r.mu.Unlock()
+}
+
+// Reset removes all cached parents.
+func (r *HierarchyResolver) Reset() {
+	r.mu.Lock()
+	r.cache = map[string]hierarchyEntry{}
+	r.mu.Unlock()
+}
+
+// parentRelation returns the relation linking objects of kind to their parent.
+func (r *HierarchyResolver) parentRelation(kind Kind) Relation {
+	if relation, ok := r.relations[kind]; ok {
+		return relation
+	}
+
+	return DefaultParentRelation
+}
+
+// cached returns the cached entry for key unless it has expired.
+func (r *HierarchyResolver) cached(key string) (hierarchyEntry, bool) {
+	r.mu.RLock()
+	entry, ok := r.cache[key]
+	r.mu.RUnlock()
+
+	if !ok || !r.now().Before(entry.expires) {
+		return hierarchyEntry{}, false
+	}
+
+	return entry, true
+}
+
+// lookup reads the parent of obj from FGA. Usersets such as
+// organization:acme#member are not parents and are ignored.
+func (r *HierarchyResolver) lookup(ctx context.Context, obj Entity, relation Relation) (hierarchyEntry, error) {
+	resp, err := r.client.ListTuples(ctx, ListTuplesRequest{Relation: relation, Object: obj})
+	if err != nil {
+		return hierarchyEntry{}, err
+	}
+
+	entry := hierarchyEntry{expires: r.now().Add(r.ttl)}
+
+	for _, t := range resp.Tuples {
+		if t.Subject.Identifier == "" || t.Subject.Identifier == Wildcard || t.Subject.Relation != "" {
+			continue
+		}
+
+		entry.parent = t.Subject
+		entry.found = true
+
+		break
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,187 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/kopexa-grc/common/fga/internal/fgamock"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeHierarchy serves parent tuples and checks from in-memory maps and
+// counts the tuple reads.
+type fakeHierarchy struct {
+	parents map[string][]string
+	allowed map[string]bool
+	reads   map[string]int
+}
+
+func newFakeHierarchy(t *testing.T, parents map[string][]string, allowed map[string]bool) (*fga.Client, *fakeHierarchy) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	f := &fakeHierarchy{parents: parents, allowed: allowed, reads: map[string]int{}}
+
+	mockSdk.EXPECT().Read(gomock.Any()).DoAndReturn(func(context.Context) client.SdkClientReadRequestInterface {
+		mockRead := fgamock.NewMockSdkClientReadRequestInterface(ctrl)
+
+		var body client.ClientReadRequest
+
+		mockRead.EXPECT().Body(gomock.Any()).DoAndReturn(func(b client.ClientReadRequest) client.SdkClientReadRequestInterface {
+			body = b
+			return mockRead
+		})
+		mockRead.EXPECT().Execute().DoAndReturn(func() (*client.ClientReadResponse, error) {
+			f.reads[*body.Object]++
+
+			resp := &client.ClientReadResponse{}
+			for _, user := range f.parents[*body.Object] {
+				resp.Tuples = append(resp.Tuples, openfga.Tuple{Key: openfga.TupleKey{User: user, Relation: *body.Relation, Object: *body.Object}})
+			}
+
+			return resp, nil
+		})
+
+		return mockRead
+	}).AnyTimes()
+
+	mockSdk.EXPECT().Check(gomock.Any()).DoAndReturn(func(context.Context) client.SdkClientCheckRequestInterface {
+		mockCheck := fgamock.NewMockSdkClientCheckRequestInterface(ctrl)
+
+		var body client.ClientCheckRequest
+
+		mockCheck.EXPECT().Body(gomock.Any()).DoAndReturn(func(b client.ClientCheckRequest) client.SdkClientCheckRequestInterface {
+			body = b
+			return mockCheck
+		})
+		mockCheck.EXPECT().Execute().DoAndReturn(func() (*client.ClientCheckResponse, error) {
+			allowed := f.allowed[body.User+"#"+body.Relation+"@"+body.Object]
+
+			return &client.ClientCheckResponse{CheckResponse: openfga.CheckResponse{Allowed: &allowed}}, nil
+		})
+
+		return mockCheck
+	}).AnyTimes()
+
+	return fga.NewMockFGAClient(mockSdk), f
+}
+
+func TestHierarchyResolver_Parent(t *testing.T) {
+	c, f := newFakeHierarchy(t, map[string][]string{
+		"space:s1":          {"organization:acme#member", "organization:acme"},
+		"organization:acme": {"organization:holding"},
+	}, nil)
+	r := fga.NewHierarchyResolver(c)
+	ctx := context.Background()
+
+	orgID, ok, err := r.OrganizationOf(ctx, "s1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", orgID)
+
+	_, _, err = r.Parent(ctx, fga.Entity{Kind: "space", Identifier: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.reads["space:s1"], "parent should be memoized")
+
+	_, ok, err = r.Parent(ctx, fga.Entity{Kind: "organization", Identifier: "holding"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = r.Parent(ctx, fga.Entity{Kind: "organization", Identifier: "holding"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 1, f.reads["organization:holding"], "missing parent should be memoized")
+
+	r.Invalidate(fga.Entity{Kind: "space", Identifier: "s1"})
+	f.parents["space:s1"] = []string{"organization:other"}
+
+	orgID, _, err = r.OrganizationOf(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "other", orgID)
+
+	_, _, err = r.Parent(ctx, fga.Entity{Kind: "space"})
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+}
+
+func TestHierarchyResolver_TTL(t *testing.T) {
+	c, f := newFakeHierarchy(t, map[string][]string{"space:s1": {"organization:acme"}}, nil)
+	r := fga.NewHierarchyResolver(c, fga.WithHierarchyTTL(time.Millisecond))
+	ctx := context.Background()
+
+	_, _, err := r.Parent(ctx, fga.Entity{Kind: "space", Identifier: "s1"})
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, _, err = r.Parent(ctx, fga.Entity{Kind: "space", Identifier: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.reads["space:s1"])
+}
+
+func TestHierarchyResolver_Ancestors(t *testing.T) {
+	c, _ := newFakeHierarchy(t, map[string][]string{
+		"space:s1":          {"organization:acme"},
+		"organization:acme": {"organization:holding"},
+		"space:loop":        {"space:loop2"},
+		"space:loop2":       {"space:loop"},
+	}, nil)
+	ctx := context.Background()
+
+	ancestors, err := fga.NewHierarchyResolver(c).Ancestors(ctx, fga.Entity{Kind: "space", Identifier: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, []fga.Entity{
+		{Kind: "organization", Identifier: "acme"},
+		{Kind: "organization", Identifier: "holding"},
+	}, ancestors)
+
+	_, err = fga.NewHierarchyResolver(c).Ancestors(ctx, fga.Entity{Kind: "space", Identifier: "loop"})
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+
+	_, err = fga.NewHierarchyResolver(c, fga.WithMaxHierarchyDepth(1)).Ancestors(ctx, fga.Entity{Kind: "space", Identifier: "s1"})
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+
+	ancestors, err = fga.NewHierarchyResolver(c, fga.WithParentRelation("organization", "")).Ancestors(ctx, fga.Entity{Kind: "space", Identifier: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, []fga.Entity{{Kind: "organization", Identifier: "acme"}}, ancestors)
+}
+
+func TestHierarchyResolver_CheckInherited(t *testing.T) {
+	c, _ := newFakeHierarchy(t, map[string][]string{
+		"space:s1":          {"organization:acme"},
+		"organization:acme": {"organization:holding"},
+	}, map[string]bool{
+		"user:owner#can_view@organization:holding": true,
+		"user:member#can_view@space:s1":            true,
+	})
+	r := fga.NewHierarchyResolver(c)
+	ctx := context.Background()
+
+	check := func(subject string) fga.AccessCheck {
+		return fga.AccessCheck{SubjectType: "user", SubjectID: subject, Relation: "can_view", ObjectType: "space", ObjectID: "s1"}
+	}
+
+	allowed, err := r.CheckInherited(ctx, check("owner"))
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = r.CheckInherited(ctx, check("member"))
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = r.CheckInherited(ctx, check("stranger"))
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	_, err = r.CheckInherited(ctx, fga.AccessCheck{Relation: "can_view"})
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/openfga/go-sdk/client (interfaces: SdkClient,SdkClientCheckRequestInterface,SdkClientWriteRequestInterface,SdkClientReadRequestInterface,SdkClientListObjectsRequestInterface,SdkClientListStoresRequestInterface,SdkClientCreateStoreRequestInterface,SdkClientReadAuthorizationModelsRequestInterface,SdkClientWriteAuthorizationModelRequestInterface)
+// Source: github.com/openfga/go-sdk/client (interfaces: SdkClient,SdkClientCheckRequestInterface,SdkClientWriteRequestInterface,SdkClientReadRequestInterface,SdkClientListObjectsRequestInterface,SdkClientListStoresRequestInterface,SdkClientCreateStoreRequestInterface,SdkClientDeleteStoreRequestInterface,SdkClientReadAuthorizationModelsRequestInterface,SdkClientWriteAuthorizationModelRequestInterface)
 //
 // Generated by this command:
 //
-//	mockgen -destination=./fga.go -package=fgamock github.com/openfga/go-sdk/client SdkClient,SdkClientCheckRequestInterface,SdkClientWriteRequestInterface,SdkClientReadRequestInterface,SdkClientListObjectsRequestInterface,SdkClientListStoresRequestInterface,SdkClientCreateStoreRequestInterface,SdkClientReadAuthorizationModelsRequestInterface,SdkClientWriteAuthorizationModelRequestInterface
+//	mockgen -destination=./fga.go -package=fgamock github.com/openfga/go-sdk/client SdkClient,SdkClientCheckRequestInterface,SdkClientWriteRequestInterface,SdkClientReadRequestInterface,SdkClientListObjectsRequestInterface,SdkClientListStoresRequestInterface,SdkClientCreateStoreRequestInterface,SdkClientDeleteStoreRequestInterface,SdkClientReadAuthorizationModelsRequestInterface,SdkClientWriteAuthorizationModelRequestInterface
 //
 
 // Package fgamock is a generated GoMock package.
@@ -1491,6 +1491,101 @@ func (mr *MockSdkClientCreateStoreRequestInterfaceMockRecorder) Options(options
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Options", reflect.TypeOf((*MockSdkClientCreateStoreRequestInterface)(nil).Options), options)
 }
 
+// MockSdkClientDeleteStoreRequestInterface is a mock of SdkClientDeleteStoreRequestInterface interface.
+type MockSdkClientDeleteStoreRequestInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSdkClientDeleteStoreRequestInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockSdkClientDeleteStoreRequestInterfaceMockRecorder is the mock recorder for MockSdkClientDeleteStoreRequestInterface.
+type MockSdkClientDeleteStoreRequestInterfaceMockRecorder struct {
+	mock *MockSdkClientDeleteStoreRequestInterface
+}
+
+// NewMockSdkClientDeleteStoreRequestInterface creates a new mock instance.
+func NewMockSdkClientDeleteStoreRequestInterface(ctrl *gomock.Controller) *MockSdkClientDeleteStoreRequestInterface {
+	mock := &MockSdkClientDeleteStoreRequestInterface{ctrl: ctrl}
+	mock.recorder = &MockSdkClientDeleteStoreRequestInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSdkClientDeleteStoreRequestInterface) EXPECT() *MockSdkClientDeleteStoreRequestInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockSdkClientDeleteStoreRequestInterface) Execute() (*client.ClientDeleteStoreResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(*client.ClientDeleteStoreResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockSdkClientDeleteStoreRequestInterfaceMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockSdkClientDeleteStoreRequestInterface)(nil).Execute))
+}
+
+// GetContext mocks base method.
+func (m *MockSdkClientDeleteStoreRequestInterface) GetContext() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContext")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// GetContext indicates an expected call of GetContext.
+func (mr *MockSdkClientDeleteStoreRequestInterfaceMockRecorder) GetContext() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContext", reflect.TypeOf((*MockSdkClientDeleteStoreRequestInterface)(nil).GetContext))
+}
+
+// GetOptions mocks base method.
+func (m *MockSdkClientDeleteStoreRequestInterface) GetOptions() *client.ClientDeleteStoreOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOptions")
+	ret0, _ := ret[0].(*client.ClientDeleteStoreOptions)
+	return ret0
+}
+
+// GetOptions indicates an expected call of GetOptions.
+func (mr *MockSdkClientDeleteStoreRequestInterfaceMockRecorder) GetOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOptions", reflect.TypeOf((*MockSdkClientDeleteStoreRequestInterface)(nil).GetOptions))
+}
+
+// GetStoreIdOverride mocks base method.
+func (m *MockSdkClientDeleteStoreRequestInterface) GetStoreIdOverride() *string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStoreIdOverride")
+	ret0, _ := ret[0].(*string)
+	return ret0
+}
+
+// GetStoreIdOverride indicates an expected call of GetStoreIdOverride.
+func (mr *MockSdkClientDeleteStoreRequestInterfaceMockRecorder) GetStoreIdOverride() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoreIdOverride", reflect.TypeOf((*MockSdkClientDeleteStoreRequestInterface)(nil).GetStoreIdOverride))
+}
+
+// Options mocks base method.
+func (m *MockSdkClientDeleteStoreRequestInterface) Options(options client.ClientDeleteStoreOptions) client.SdkClientDeleteStoreRequestInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Options", options)
+	ret0, _ := ret[0].(client.SdkClientDeleteStoreRequestInterface)
+	return ret0
+}
+
+// Options indicates an expected call of Options.
+func (mr *MockSdkClientDeleteStoreRequestInterfaceMockRecorder) Options(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Options", reflect.TypeOf((*MockSdkClientDeleteStoreRequestInterface)(nil).Options), options)
+}
+
 // MockSdkClientReadAuthorizationModelsRequestInterface is a mock of SdkClientReadAuthorizationModelsRequestInterface interface.
 type MockSdkClientReadAuthorizationModelsRequestInterface struct {
 	ctrl     *gomock.Controller
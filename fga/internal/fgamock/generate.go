@@ -3,4 +3,4 @@
 
 package fgamock
 
-//go:generate go run -mod=mod go.uber.org/mock/mockgen -destination=./fga.go -package=fgamock github.com/openfga/go-sdk/client SdkClient,SdkClientCheckRequestInterface,SdkClientWriteRequestInterface,SdkClientReadRequestInterface,SdkClientListObjectsRequestInterface,SdkClientListStoresRequestInterface,SdkClientCreateStoreRequestInterface,SdkClientReadAuthorizationModelsRequestInterface,SdkClientWriteAuthorizationModelRequestInterface
+//go:generate go run -mod=mod go.uber.org/mock/mockgen -destination=./fga.go -package=fgamock github.com/openfga/go-sdk/client SdkClient,SdkClientCheckRequestInterface,SdkClientWriteRequestInterface,SdkClientReadRequestInterface,SdkClientListObjectsRequestInterface,SdkClientListStoresRequestInterface,SdkClientCreateStoreRequestInterface,SdkClientDeleteStoreRequestInterface,SdkClientReadAuthorizationModelsRequestInterface,SdkClientWriteAuthorizationModelRequestInterface
@@ -0,0 +1,614 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	openfga "github.com/openfga/go-sdk"
+)
+
+// Rules checked by LintModel.
+const (
+	// LintRuleNaming reports type and relation names that do not match the
+	// naming convention.
+	LintRuleNaming = "naming"
+	// LintRuleTypo reports relation names that are close to, but not equal
+	// to, a required relation, e.g. "can_veiw".
+	LintRuleTypo = "typo"
+	// LintRuleUndefined reports references to undefined types, relations
+	// and conditions.
+	LintRuleUndefined = "undefined"
+	// LintRuleUnreachable reports relations that can never be granted,
+	// because no tuple can ever satisfy their definition.
+	LintRuleUnreachable = "unreachable"
+	// LintRuleUnused reports relations that are neither permissions nor
+	// referenced by another relation.
+	LintRuleUnused = "unused"
+	// LintRuleMissingRelation reports types without a required relation.
+	LintRuleMissingRelation = "missing_relation"
+	// LintRuleBreakingChange reports changes against the deployed model that
+	// invalidate existing tuples or checks.
+	LintRuleBreakingChange = "breaking_change"
+)
+
+// LintSeverity classifies a LintIssue.
+type LintSeverity string
+
+const (
+	// LintError marks issues that break authorization and must be fixed
+	// before the model is deployed.
+	LintError LintSeverity = "error"
+	// LintWarning marks issues that should be reviewed.
+	LintWarning LintSeverity = "warning"
+)
+
+// permissionPrefix marks relations that are checked by the application and
+// therefore need not be referenced within the model.
+const permissionPrefix = "can_"
+
+// maxTypoDistance is the largest edit distance at which a relation name is
+// reported as a possible typo of a required relation.
+const maxTypoDistance = 2
+
+// defaultNamePattern is the snake_case convention for type and relation
+// names. A leading underscore marks internal relations such as "_self".
+var defaultNamePattern = regexp.MustCompile(`^_?[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// LintIssue is a single finding of LintModel.
+type LintIssue struct {
+	// Rule is the rule that reported the issue, e.g. LintRuleUnreachable.
+	Rule string `json:"rule"`
+	// Severity is the severity of the issue.
+	Severity LintSeverity `json:"severity"`
+	// Type is the affected type.
+	Type string `json:"type,omitempty"`
+	// Relation is the affected relation, if any.
+	Relation string `json:"relation,omitempty"`
+	// Message describes the issue.
+	Message string `json:"message"`
+}
+
+// String returns the issue as "severity [rule] type#relation: message".
+func (i LintIssue) String() string {
+	target := i.Type
+	if i.Relation != "" {
+		target += "#" + i.Relation
+	}
+
+	return fmt.Sprintf("%s [%s] %s: %s", i.Severity, i.Rule, target, i.Message)
+}
+
+// LintResult holds the issues found by LintModel in a stable order.
+type LintResult struct {
+	Issues []LintIssue `json:"issues"`
+}
+
+// Errors returns the issues with severity LintError.
+func (r *LintResult) Errors() []LintIssue {
+	var errs []LintIssue
+
+	for _, i := range r.Issues {
+		if i.Severity == LintError {
+			errs = append(errs, i)
+		}
+	}
+
+	return errs
+}
+
+// Err returns an error wrapping ErrModelLint that lists all issues with
+// severity LintError, or nil if there are none.
+func (r *LintResult) Err() error {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.String()
+	}
+
+	return fmt.Errorf("%w: %s", ErrModelLint, strings.Join(msgs, "; "))
+}
+
+// LintOption configures LintModel.
+type LintOption func(*lintConfig)
+
+type lintConfig struct {
+	required    []Relation
+	exemptTypes []string
+	namePattern *regexp.Regexp
+	deployed    []byte
+}
+
+// WithRequiredRelations sets the relations every type must define. Defaults
+// to CanView and CanEdit; calling it without relations disables the check.
+func WithRequiredRelations(relations ...Relation) LintOption {
+	return func(c *lintConfig) {
+		c.required = relations
+	}
+}
+
+// WithExemptTypes sets the types that need not define the required
+// relations, typically subject types. Defaults to "user" and "service".
+func WithExemptTypes(types ...string) LintOption {
+	return func(c *lintConfig) {
+		c.exemptTypes = types
+	}
+}
+
+// WithNamePattern sets the naming convention for type and relation names.
+// Defaults to snake_case with an optional leading underscore.
+func WithNamePattern(pattern *regexp.Regexp) LintOption {
+	return func(c *lintConfig) {
+		c.namePattern = pattern
+	}
+}
+
+// WithDeployedModel enables breaking-change detection against the DSL of the
+// currently deployed model.
+func WithDeployedModel(dsl []byte) LintOption {
+	return func(c *lintConfig) {
+		c.deployed = dsl
+	}
+}
+
+// LintModel checks an FGA DSL model for mistakes that break authorization in
+// production:
+//
+//   - references to undefined types, relations and conditions (error)
+//   - relations that can never be granted (error)
+//   - types without the required relations (error)
+//   - breaking changes against the deployed model, see WithDeployedModel:
+//     removed types, relations, conditions and directly related types
+//     (error) and changed relation definitions (warning)
+//   - names violating the naming convention (warning)
+//   - relation names close to a required relation (warning)
+//   - relations that are neither permissions ("can_*") nor referenced (warning)
+//
+// It returns an error only if the model cannot be parsed; the findings are
+// reported in the LintResult.
+//
+// Example:
+//
+//	result, err := fga.LintModel(dsl, fga.WithDeployedModel(deployedDSL))
+//	if err != nil {
+//	    return err
+//	}
+//
+//	if err := result.Err(); err != nil {
+//	    return err // fail the pipeline
+//	}
+func LintModel(dsl []byte, opts ...LintOption) (*LintResult, error) {
+	cfg := &lintConfig{
+		required:    []Relation{CanView, CanEdit},
+		exemptTypes: []string{userSubject, serviceSubject},
+		namePattern: defaultNamePattern,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	model, err := parseModelDSL(dsl)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &linter{cfg: cfg, idx: newModelIndex(model), result: &LintResult{}}
+
+	l.checkNaming()
+	l.checkReferences()
+	l.checkReachability()
+	l.checkRequired()
+
+	if cfg.deployed != nil {
+		deployed, err := parseModelDSL(cfg.deployed)
+		if err != nil {
+			return nil, fmt.Errorf("deployed model: %w", err)
+		}
+
+		l.checkBreakingChanges(newModelIndex(deployed))
+	}
+
+	return l.result, nil
+}
+
+// modelIndex gives access to the definitions of a model by type and relation.
+type modelIndex struct {
+	types      []string
+	relations  map[string]map[string]openfga.Userset
+	direct     map[string]map[string][]openfga.RelationReference
+	conditions map[string]openfga.Condition
+}
+
+func newModelIndex(model openfga.AuthorizationModel) *modelIndex {
+	idx := &modelIndex{
+		relations:  make(map[string]map[string]openfga.Userset),
+		direct:     make(map[string]map[string][]openfga.RelationReference),
+		conditions: model.GetConditions(),
+	}
+
+	for _, td := range model.TypeDefinitions {
+		idx.types = append(idx.types, td.GetType())
+		idx.relations[td.GetType()] = td.GetRelations()
+
+		direct := make(map[string][]openfga.RelationReference)
+
+		metadata := td.GetMetadata()
+		for rel, md := range metadata.GetRelations() {
+			direct[rel] = md.GetDirectlyRelatedUserTypes()
+		}
+
+		idx.direct[td.GetType()] = direct
+	}
+
+	slices.Sort(idx.types)
+
+	return idx
+}
+
+// hasType reports whether typ is defined.
+func (m *modelIndex) hasType(typ string) bool {
+	_, ok := m.relations[typ]
+	return ok
+}
+
+// has reports whether rel is defined on typ.
+func (m *modelIndex) has(typ, rel string) bool {
+	_, ok := m.relations[typ][rel]
+	return ok
+}
+
+// relationNames returns the relations of typ in sorted order.
+func (m *modelIndex) relationNames(typ string) []string {
+	return slices.Sorted(maps.Keys(m.relations[typ]))
+}
+
+// tuplesetTargets returns the types directly related through the tupleset
+// relation rel of typ.
+func (m *modelIndex) tuplesetTargets(typ, rel string) []string {
+	var targets []string
+
+	for _, ref := range m.direct[typ][rel] {
+		if !slices.Contains(targets, ref.Type) {
+			targets = append(targets, ref.Type)
+		}
+	}
+
+	return targets
+}
+
+// walkUserset calls fn for u and all nested usersets.
+func walkUserset(u openfga.Userset, fn func(openfga.Userset)) {
+	fn(u)
+
+	switch {
+	case u.Union != nil:
+		for _, c := range u.Union.Child {
+			walkUserset(c, fn)
+		}
+	case u.Intersection != nil:
+		for _, c := range u.Intersection.Child {
+			walkUserset(c, fn)
+		}
+	case u.Difference != nil:
+		walkUserset(u.Difference.Base, fn)
+		walkUserset(u.Difference.Subtract, fn)
+	}
+}
+
+// referenceKey identifies a directly related type, e.g. "team#member" or
+// "user:*", including its condition.
+func referenceKey(ref openfga.RelationReference) string {
+	key := ref.Type
+
+	switch {
+	case ref.Relation != nil:
+		key += "#" + *ref.Relation
+	case ref.Wildcard != nil:
+		key += ":" + Wildcard
+	}
+
+	if ref.Condition != nil && *ref.Condition != "" {
+		key += " with " + *ref.Condition
+	}
+
+	return key
+}
+
+type linter struct {
+	cfg       *lintConfig
+	idx       *modelIndex
+	result    *LintResult
+	undefined map[string]bool // "type#relation" with undefined references
+}
+
+func (l *linter) report(rule string, severity LintSeverity, typ, rel, format string, args ...any) {
+	l.result.Issues = append(l.result.Issues, LintIssue{
+		Rule:     rule,
+		Severity: severity,
+		Type:     typ,
+		Relation: rel,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkNaming reports names violating the convention and likely typos of
+// required relations.
+func (l *linter) checkNaming() {
+	for _, typ := range l.idx.types {
+		if !l.cfg.namePattern.MatchString(typ) {
+			l.report(LintRuleNaming, LintWarning, typ, "", "type name does not match %s", l.cfg.namePattern)
+		}
+
+		for _, rel := range l.idx.relationNames(typ) {
+			if !l.cfg.namePattern.MatchString(rel) {
+				l.report(LintRuleNaming, LintWarning, typ, rel, "relation name does not match %s", l.cfg.namePattern)
+			}
+
+			for _, req := range l.cfg.required {
+				if d := editDistance(rel, string(req)); d > 0 && d <= maxTypoDistance {
+					l.report(LintRuleTypo, LintWarning, typ, rel, "possible typo of required relation %s", req)
+				}
+			}
+		}
+	}
+}
+
+// checkReferences reports references to undefined types, relations and
+// conditions, and relations that are not referenced at all.
+func (l *linter) checkReferences() {
+	l.undefined = make(map[string]bool)
+	referenced := make(map[string]bool)
+
+	for _, typ := range l.idx.types {
+		for _, rel := range l.idx.relationNames(typ) {
+			undefined := func(format string, args ...any) {
+				l.undefined[typ+"#"+rel] = true
+				l.report(LintRuleUndefined, LintError, typ, rel, format, args...)
+			}
+
+			for _, ref := range l.idx.direct[typ][rel] {
+				switch {
+				case !l.idx.hasType(ref.Type):
+					undefined("directly related type %s is not defined", ref.Type)
+				case ref.Relation != nil && !l.idx.has(ref.Type, *ref.Relation):
+					undefined("directly related relation %s#%s is not defined", ref.Type, *ref.Relation)
+				case ref.Relation != nil:
+					referenced[ref.Type+"#"+*ref.Relation] = true
+				}
+
+				if ref.Condition != nil && *ref.Condition != "" {
+					if _, ok := l.idx.conditions[*ref.Condition]; !ok {
+						undefined("condition %s is not defined", *ref.Condition)
+					}
+				}
+			}
+
+			walkUserset(l.idx.relations[typ][rel], func(u openfga.Userset) {
+				switch {
+				case u.ComputedUserset != nil:
+					target := u.ComputedUserset.GetRelation()
+					if !l.idx.has(typ, target) {
+						undefined("relation %s is not defined", target)
+					}
+
+					referenced[typ+"#"+target] = true
+				case u.TupleToUserset != nil:
+					tupleset := u.TupleToUserset.Tupleset.GetRelation()
+					computed := u.TupleToUserset.ComputedUserset.GetRelation()
+
+					if !l.idx.has(typ, tupleset) {
+						undefined("tupleset relation %s is not defined", tupleset)
+						return
+					}
+
+					referenced[typ+"#"+tupleset] = true
+
+					found := false
+
+					for _, target := range l.idx.tuplesetTargets(typ, tupleset) {
+						if l.idx.has(target, computed) {
+							referenced[target+"#"+computed] = true
+							found = true
+						}
+					}
+
+					if !found {
+						undefined("relation %s is not defined on any type related through %s", computed, tupleset)
+					}
+				}
+			})
+		}
+	}
+
+	for _, typ := range l.idx.types {
+		if slices.Contains(l.cfg.exemptTypes, typ) {
+			continue
+		}
+
+		for _, rel := range l.idx.relationNames(typ) {
+			if referenced[typ+"#"+rel] || strings.HasPrefix(rel, permissionPrefix) || slices.Contains(l.cfg.required, Relation(rel)) {
+				continue
+			}
+
+			l.report(LintRuleUnused, LintWarning, typ, rel, "relation is neither a permission nor referenced by another relation")
+		}
+	}
+}
+
+// checkReachability reports relations that no set of tuples can satisfy,
+// e.g. a computed relation based only on relations without directly related
+// types. Satisfiable relations are determined as a fixpoint.
+func (l *linter) checkReachability() {
+	sat := make(map[string]bool)
+
+	var satisfiable func(typ, rel string, u openfga.Userset) bool
+
+	satisfiable = func(typ, rel string, u openfga.Userset) bool {
+		switch {
+		case u.This != nil:
+			return slices.ContainsFunc(l.idx.direct[typ][rel], func(ref openfga.RelationReference) bool {
+				return ref.Relation == nil || sat[ref.Type+"#"+*ref.Relation]
+			})
+		case u.ComputedUserset != nil:
+			return sat[typ+"#"+u.ComputedUserset.GetRelation()]
+		case u.TupleToUserset != nil:
+			tupleset := u.TupleToUserset.Tupleset.GetRelation()
+			if !sat[typ+"#"+tupleset] {
+				return false
+			}
+
+			computed := u.TupleToUserset.ComputedUserset.GetRelation()
+
+			return slices.ContainsFunc(l.idx.tuplesetTargets(typ, tupleset), func(target string) bool {
+				return sat[target+"#"+computed]
+			})
+		case u.Union != nil:
+			return slices.ContainsFunc(u.Union.Child, func(c openfga.Userset) bool {
+				return satisfiable(typ, rel, c)
+			})
+		case u.Intersection != nil:
+			return !slices.ContainsFunc(u.Intersection.Child, func(c openfga.Userset) bool {
+				return !satisfiable(typ, rel, c)
+			})
+		case u.Difference != nil:
+			return satisfiable(typ, rel, u.Difference.Base)
+		default:
+			return false
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, typ := range l.idx.types {
+			for rel, u := range l.idx.relations[typ] {
+				key := typ + "#" + rel
+				if !sat[key] && satisfiable(typ, rel, u) {
+					sat[key] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	for _, typ := range l.idx.types {
+		for _, rel := range l.idx.relationNames(typ) {
+			key := typ + "#" + rel
+			if !sat[key] && !l.undefined[key] {
+				l.report(LintRuleUnreachable, LintError, typ, rel, "relation can never be granted")
+			}
+		}
+	}
+}
+
+// checkRequired reports types without the required relations.
+func (l *linter) checkRequired() {
+	for _, typ := range l.idx.types {
+		if slices.Contains(l.cfg.exemptTypes, typ) {
+			continue
+		}
+
+		for _, req := range l.cfg.required {
+			if !l.idx.has(typ, string(req)) {
+				l.report(LintRuleMissingRelation, LintError, typ, "", "required relation %s is not defined", req)
+			}
+		}
+	}
+}
+
+// checkBreakingChanges compares the model with the deployed model.
+func (l *linter) checkBreakingChanges(deployed *modelIndex) {
+	for _, typ := range deployed.types {
+		if !l.idx.hasType(typ) {
+			l.report(LintRuleBreakingChange, LintError, typ, "", "type was removed; existing tuples become invalid")
+			continue
+		}
+
+		for _, rel := range deployed.relationNames(typ) {
+			if !l.idx.has(typ, rel) {
+				l.report(LintRuleBreakingChange, LintError, typ, rel, "relation was removed; existing tuples and checks fail")
+				continue
+			}
+
+			for _, ref := range deployed.direct[typ][rel] {
+				key := referenceKey(ref)
+
+				if !slices.ContainsFunc(l.idx.direct[typ][rel], func(r openfga.RelationReference) bool {
+					return referenceKey(r) == key
+				}) {
+					l.report(LintRuleBreakingChange, LintError, typ, rel, "directly related type %s was removed; existing tuples become invalid", key)
+				}
+			}
+
+			if !sameUserset(deployed.relations[typ][rel], l.idx.relations[typ][rel]) {
+				l.report(LintRuleBreakingChange, LintWarning, typ, rel, "relation definition changed; review the effective permissions")
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(deployed.conditions)) {
+		cond, ok := l.idx.conditions[name]
+		if !ok {
+			l.report(LintRuleBreakingChange, LintError, "", "", "condition %s was removed; existing tuples become invalid", name)
+			continue
+		}
+
+		params := cond.GetParameters()
+
+		old := deployed.conditions[name]
+
+		oldParams := old.GetParameters()
+		for _, p := range slices.Sorted(maps.Keys(oldParams)) {
+			ref, ok := params[p]
+			if !ok || ref.TypeName != oldParams[p].TypeName {
+				l.report(LintRuleBreakingChange, LintError, "", "", "parameter %s of condition %s was removed or changed its type", p, name)
+			}
+		}
+	}
+}
+
+// sameUserset reports whether two relation definitions are equal.
+func sameUserset(a, b openfga.Userset) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,164 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadLintModel(t *testing.T) []byte {
+	t.Helper()
+
+	dsl, err := os.ReadFile("testdata/lint.fga")
+	require.NoError(t, err)
+
+	return dsl
+}
+
+// issueKeys returns the issues as "rule type#relation" for comparison.
+func issueKeys(result *fga.LintResult) []string {
+	keys := make([]string, len(result.Issues))
+	for i, issue := range result.Issues {
+		keys[i] = issue.Rule + " " + issue.Type + "#" + issue.Relation
+	}
+
+	return keys
+}
+
+func TestLintModel_Clean(t *testing.T) {
+	dsl := loadLintModel(t)
+
+	result, err := fga.LintModel(dsl, fga.WithDeployedModel(dsl))
+	require.NoError(t, err)
+	assert.Empty(t, result.Issues)
+	assert.NoError(t, result.Err())
+}
+
+func TestLintModel_Issues(t *testing.T) {
+	dsl := `model
+  schema 1.1
+
+type user
+
+type Folder
+    relations
+        define viewer: [user]
+        define can_view: viewer
+        define can_edit: editor
+        define editor: [team#member]
+        define can_veiw: viewer
+
+type document
+    relations
+        define parent: [Folder]
+        define draft: [user]
+        define can_view: can_view from parent
+        define can_delete: owner
+`
+
+	result, err := fga.LintModel([]byte(dsl))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"naming Folder#",
+		"typo Folder#can_veiw",
+		"undefined Folder#editor",    // team is not defined
+		"unreachable Folder#can_edit", // editor can never be granted
+		"undefined document#can_delete",
+		"unused document#draft",
+		"missing_relation document#",
+	}, issueKeys(result))
+
+	err = result.Err()
+	require.ErrorIs(t, err, fga.ErrModelLint)
+	assert.Contains(t, err.Error(), "error [missing_relation] document: required relation can_edit is not defined")
+}
+
+func TestLintModel_Unreachable(t *testing.T) {
+	dsl := `model
+  schema 1.1
+
+type user
+
+type report
+    relations
+        define approver: [user]
+        define reviewer: approver and auditor
+        define auditor: reviewer
+        define can_view: approver
+        define can_edit: reviewer
+`
+
+	result, err := fga.LintModel([]byte(dsl))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"unreachable report#reviewer",
+		"unreachable report#auditor",
+		"unreachable report#can_edit",
+	}, issueKeys(result))
+}
+
+func TestLintModel_BreakingChanges(t *testing.T) {
+	deployed := loadLintModel(t)
+
+	changed := strings.NewReplacer(
+		"define viewer: [user, user:*, organization#member]", "define viewer: [user, organization#member]",
+		"define can_view: viewer or owner or can_view from organization", "define can_view: viewer or owner",
+		"        define owner: [user]\n", "        define owner: [user]\n        define editor: [user]\n",
+		"define can_edit: owner or", "define can_edit: editor or",
+	).Replace(string(deployed))
+
+	result, err := fga.LintModel([]byte(changed), fga.WithDeployedModel(deployed))
+	require.NoError(t, err)
+
+	var breaking []string
+
+	for _, issue := range result.Issues {
+		if issue.Rule == fga.LintRuleBreakingChange {
+			breaking = append(breaking, string(issue.Severity)+" "+issue.Type+"#"+issue.Relation)
+		}
+	}
+
+	assert.ElementsMatch(t, []string{
+		"error document#viewer",
+		"warning document#can_view",
+		"warning document#can_edit",
+	}, breaking)
+
+	removed := strings.Replace(string(deployed), "        define owner: [user]\n", "", 1)
+	removed = strings.ReplaceAll(removed, " or owner", "")
+	removed = strings.Replace(removed, "define can_edit: owner or ", "define can_edit: ", 1)
+
+	result, err = fga.LintModel([]byte(removed), fga.WithDeployedModel(deployed))
+	require.NoError(t, err)
+	assert.Contains(t, issueKeys(result), "breaking_change document#owner")
+	assert.ErrorIs(t, result.Err(), fga.ErrModelLint)
+}
+
+func TestLintModel_Options(t *testing.T) {
+	dsl := `model
+  schema 1.1
+
+type user
+
+type tag
+    relations
+        define tagger: [user]
+        define canRead: tagger
+`
+
+	result, err := fga.LintModel([]byte(dsl), fga.WithRequiredRelations(), fga.WithExemptTypes("user", "tag"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"naming tag#canRead"}, issueKeys(result))
+
+	_, err = fga.LintModel([]byte("model\n  schema 1.1\n\ntype"))
+	assert.ErrorIs(t, err, fga.ErrFailedToTransformModel)
+}
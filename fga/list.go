@@ -6,6 +6,7 @@ package fga
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/kopexa-grc/common/ptr"
 	"github.com/oklog/ulid/v2"
@@ -239,6 +240,8 @@ func (c *Client) batchCheckTuples(ctx context.Context, checks []client.ClientBat
 		return []string{}, nil
 	}
 
+	start := time.Now()
+
 	res, err := c.client.BatchCheck(ctx).Body(
 		client.ClientBatchCheckRequest{
 			Checks: checks,
@@ -250,6 +253,8 @@ func (c *Client) batchCheckTuples(ctx context.Context, checks []client.ClientBat
 			Int("checkCount", len(checks)).
 			Msg("failed to execute batch check")
 
+		c.logBatchDecisions(ctx, checks, nil, err, start)
+
 		return nil, err
 	}
 
@@ -257,6 +262,8 @@ func (c *Client) batchCheckTuples(ctx context.Context, checks []client.ClientBat
 		return nil, ErrEmptyBatchCheckResponse
 	}
 
+	c.logBatchDecisions(ctx, checks, res, nil, start)
+
 	relations := make([]string, 0, len(checks))
 
 	for id, r := range *res.Result {
@@ -0,0 +1,254 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/rs/zerolog/log"
+)
+
+// Batch sizes for relation migrations. Every migrated tuple results in one
+// write and one delete, and OpenFGA accepts at most 100 tuple changes per
+// write request by default.
+const (
+	// DefaultMigrationBatchSize is the number of tuples migrated per batch.
+	DefaultMigrationBatchSize = 50
+	// MaxMigrationBatchSize is the largest supported batch size.
+	MaxMigrationBatchSize = 50
+)
+
+// MigrationProgress tracks the state of a relation migration.
+type MigrationProgress struct {
+	// ObjectType is the type of objects whose tuples are migrated.
+	ObjectType string
+	// OldRelation is the relation being renamed.
+	OldRelation Relation
+	// NewRelation is the relation tuples are moved to.
+	NewRelation Relation
+	// Migrated is the number of tuples migrated so far, across all runs.
+	Migrated int
+	// Batches is the number of batches committed so far, across all runs.
+	Batches int
+	// Completed is true once no tuples with OldRelation remain.
+	Completed bool
+	// UpdatedAt is the time the progress was last saved.
+	UpdatedAt time.Time
+}
+
+// ID returns the key under which the progress is stored.
+func (p *MigrationProgress) ID() string {
+	return migrationID(p.ObjectType, p.OldRelation, p.NewRelation)
+}
+
+// MigrationProgressStore persists migration progress so that an interrupted
+// migration can be resumed and reported on.
+type MigrationProgressStore interface {
+	// LoadMigrationProgress returns the stored progress for id, or nil if
+	// the migration has not been started yet.
+	LoadMigrationProgress(ctx context.Context, id string) (*MigrationProgress, error)
+	// SaveMigrationProgress stores the progress after each committed batch.
+	SaveMigrationProgress(ctx context.Context, progress *MigrationProgress) error
+}
+
+// MigrationOption configures MigrateRelation.
+type MigrationOption func(*migrationConfig)
+
+type migrationConfig struct {
+	store MigrationProgressStore
+}
+
+// WithMigrationProgressStore persists the progress of the migration in store.
+func WithMigrationProgressStore(store MigrationProgressStore) MigrationOption {
+	return func(c *migrationConfig) {
+		c.store = store
+	}
+}
+
+// MigrateRelation moves all tuples of objectType from oldRel to newRel, for
+// example after a relation was renamed in the authorization model.
+//
+// Tuples are migrated in batches of batchSize. Within a batch, the tuples
+// with newRel are written and the tuples with oldRel are deleted in a single
+// transactional write, so every tuple is always reachable through one of the
+// two relations. Conditions are carried over.
+//
+// Because migrated tuples are deleted, every batch reads the first remaining
+// page of tuples with oldRel. An interrupted migration is resumed by simply
+// calling MigrateRelation again; tuples that were already written with newRel
+// are ignored. With WithMigrationProgressStore the counters of previous runs
+// are loaded and the progress is saved after every batch.
+//
+// A batchSize <= 0 uses DefaultMigrationBatchSize.
+//
+// Example:
+//
+//	progress, err := client.MigrateRelation(ctx, "document", "reader", "viewer", 0)
+func (c *Client) MigrateRelation(ctx context.Context, objectType string, oldRel, newRel Relation, batchSize int, opts ...MigrationOption) (*MigrationProgress, error) {
+	if objectType == "" {
+		return nil, fmt.Errorf("%w: object type is required", ErrInvalidArgument)
+	}
+
+	if oldRel == "" || newRel == "" {
+		return nil, fmt.Errorf("%w: old and new relation are required", ErrInvalidArgument)
+	}
+
+	if oldRel == newRel {
+		return nil, fmt.Errorf("%w: old and new relation must differ", ErrInvalidArgument)
+	}
+
+	if batchSize <= 0 {
+		batchSize = DefaultMigrationBatchSize
+	}
+
+	if batchSize > MaxMigrationBatchSize {
+		return nil, fmt.Errorf("%w: batch size must not exceed %d", ErrInvalidArgument, MaxMigrationBatchSize)
+	}
+
+	cfg := &migrationConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	progress, err := c.loadMigrationProgress(ctx, cfg.store, objectType, oldRel, newRel)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		n, err := c.migrateRelationBatch(ctx, objectType, oldRel, newRel, batchSize)
+		if err != nil {
+			return progress, err
+		}
+
+		if n == 0 {
+			progress.Completed = true
+		} else {
+			progress.Migrated += n
+			progress.Batches++
+		}
+
+		if err := saveMigrationProgress(ctx, cfg.store, progress); err != nil {
+			return progress, err
+		}
+
+		log.Debug().
+			Str("object_type", objectType).
+			Str("old_relation", oldRel.String()).
+			Str("new_relation", newRel.String()).
+			Int("migrated", progress.Migrated).
+			Msg("migrated relation batch")
+
+		if progress.Completed {
+			return progress, nil
+		}
+	}
+}
+
+// migrateRelationBatch migrates the first page of remaining tuples and
+// returns the number of tuples migrated.
+func (c *Client) migrateRelationBatch(ctx context.Context, objectType string, oldRel, newRel Relation, batchSize int) (int, error) {
+	object := objectType + ":"
+	relation := oldRel.String()
+	pageSize := int32(batchSize) //nolint:gosec // bounded by MaxMigrationBatchSize
+
+	resp, err := c.client.Read(ctx).
+		Body(client.ClientReadRequest{
+			Relation: &relation,
+			Object:   &object,
+		}).
+		Options(client.ClientReadOptions{PageSize: &pageSize}).
+		Execute()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read tuples to migrate: %w", err)
+	}
+
+	if resp == nil || len(resp.Tuples) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]client.ClientTupleKey, 0, len(resp.Tuples))
+	deletes := make([]openfga.TupleKeyWithoutCondition, 0, len(resp.Tuples))
+
+	for _, t := range resp.Tuples {
+		writes = append(writes, client.ClientTupleKey{
+			User:      t.Key.User,
+			Relation:  newRel.String(),
+			Object:    t.Key.Object,
+			Condition: t.Key.Condition,
+		})
+		deletes = append(deletes, openfga.TupleKeyWithoutCondition{
+			User:     t.Key.User,
+			Relation: t.Key.Relation,
+			Object:   t.Key.Object,
+		})
+	}
+
+	_, err = c.client.Write(ctx).
+		Body(client.ClientWriteRequest{
+			Writes:  writes,
+			Deletes: deletes,
+		}).
+		Options(client.ClientWriteOptions{
+			Conflict: client.ClientWriteConflictOptions{
+				OnDuplicateWrites: client.CLIENT_WRITE_REQUEST_ON_DUPLICATE_WRITES_IGNORE,
+				OnMissingDeletes:  client.CLIENT_WRITE_REQUEST_ON_MISSING_DELETES_IGNORE,
+			},
+		}).
+		Execute()
+	if err != nil {
+		return 0, fmt.Errorf("failed to write migrated tuples: %w", err)
+	}
+
+	return len(resp.Tuples), nil
+}
+
+// loadMigrationProgress returns the stored progress or a fresh one.
+func (c *Client) loadMigrationProgress(ctx context.Context, store MigrationProgressStore, objectType string, oldRel, newRel Relation) (*MigrationProgress, error) {
+	if store != nil {
+		progress, err := store.LoadMigrationProgress(ctx, migrationID(objectType, oldRel, newRel))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load migration progress: %w", err)
+		}
+
+		if progress != nil {
+			progress.Completed = false
+			return progress, nil
+		}
+	}
+
+	return &MigrationProgress{
+		ObjectType:  objectType,
+		OldRelation: oldRel,
+		NewRelation: newRel,
+	}, nil
+}
+
+// saveMigrationProgress stores the progress if a store is configured.
+func saveMigrationProgress(ctx context.Context, store MigrationProgressStore, progress *MigrationProgress) error {
+	progress.UpdatedAt = time.Now()
+
+	if store == nil {
+		return nil
+	}
+
+	if err := store.SaveMigrationProgress(ctx, progress); err != nil {
+		return fmt.Errorf("failed to save migration progress: %w", err)
+	}
+
+	return nil
+}
+
+// migrationID identifies a migration of oldRel to newRel on objectType.
+func migrationID(objectType string, oldRel, newRel Relation) string {
+	return objectType + "#" + oldRel.String() + "->" + newRel.String()
+}
@@ -0,0 +1,136 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/kopexa-grc/common/fga/internal/fgamock"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// memProgressStore is an in-memory fga.MigrationProgressStore.
+type memProgressStore struct {
+	progress map[string]fga.MigrationProgress
+	saves    int
+}
+
+func (s *memProgressStore) LoadMigrationProgress(_ context.Context, id string) (*fga.MigrationProgress, error) {
+	p, ok := s.progress[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &p, nil
+}
+
+func (s *memProgressStore) SaveMigrationProgress(_ context.Context, p *fga.MigrationProgress) error {
+	s.progress[p.ID()] = *p
+	s.saves++
+
+	return nil
+}
+
+func readerTuples(n int) []openfga.Tuple {
+	tuples := make([]openfga.Tuple, n)
+	for i := range tuples {
+		tuples[i] = openfga.Tuple{Key: openfga.TupleKey{User: "user:*", Relation: "reader", Object: "document:1"}}
+	}
+
+	return tuples
+}
+
+func TestClient_MigrateRelation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockRead := fgamock.NewMockSdkClientReadRequestInterface(ctrl)
+	mockWrite := fgamock.NewMockSdkClientWriteRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	store := &memProgressStore{progress: map[string]fga.MigrationProgress{
+		"document#reader->viewer": {ObjectType: "document", OldRelation: "reader", NewRelation: "viewer", Migrated: 7, Batches: 1},
+	}}
+
+	mockSdk.EXPECT().Read(gomock.Any()).Return(mockRead).Times(3)
+	mockRead.EXPECT().Body(gomock.Any()).DoAndReturn(func(body client.ClientReadRequest) client.SdkClientReadRequestInterface {
+		assert.Equal(t, "reader", *body.Relation)
+		assert.Equal(t, "document:", *body.Object)
+		assert.Nil(t, body.User)
+
+		return mockRead
+	}).Times(3)
+	mockRead.EXPECT().Options(gomock.Any()).Return(mockRead).Times(3)
+	gomock.InOrder(
+		mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{Tuples: readerTuples(2)}, nil),
+		mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{Tuples: readerTuples(1)}, nil),
+		mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{}, nil),
+	)
+
+	mockSdk.EXPECT().Write(gomock.Any()).Return(mockWrite).Times(2)
+	mockWrite.EXPECT().Body(gomock.Any()).DoAndReturn(func(body client.ClientWriteRequest) client.SdkClientWriteRequestInterface {
+		require.Len(t, body.Writes, len(body.Deletes))
+		assert.Equal(t, "viewer", body.Writes[0].Relation)
+		assert.Equal(t, "user:*", body.Writes[0].User)
+		assert.Equal(t, "reader", body.Deletes[0].Relation)
+
+		return mockWrite
+	}).Times(2)
+	mockWrite.EXPECT().Options(gomock.Any()).DoAndReturn(func(opts client.ClientWriteOptions) client.SdkClientWriteRequestInterface {
+		assert.Nil(t, opts.Transaction)
+
+		return mockWrite
+	}).Times(2)
+	mockWrite.EXPECT().Execute().Return(&client.ClientWriteResponse{}, nil).Times(2)
+
+	progress, err := c.MigrateRelation(context.Background(), "document", "reader", "viewer", 2, fga.WithMigrationProgressStore(store))
+	require.NoError(t, err)
+	assert.True(t, progress.Completed)
+	assert.Equal(t, 10, progress.Migrated)
+	assert.Equal(t, 3, progress.Batches)
+	assert.Equal(t, 3, store.saves)
+	assert.True(t, store.progress[progress.ID()].Completed)
+}
+
+func TestClient_MigrateRelation_WriteError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockRead := fgamock.NewMockSdkClientReadRequestInterface(ctrl)
+	mockWrite := fgamock.NewMockSdkClientWriteRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	mockSdk.EXPECT().Read(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Body(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Options(gomock.Any()).Return(mockRead)
+	mockRead.EXPECT().Execute().Return(&client.ClientReadResponse{Tuples: readerTuples(1)}, nil)
+
+	mockSdk.EXPECT().Write(gomock.Any()).Return(mockWrite)
+	mockWrite.EXPECT().Body(gomock.Any()).Return(mockWrite)
+	mockWrite.EXPECT().Options(gomock.Any()).Return(mockWrite)
+	mockWrite.EXPECT().Execute().Return(nil, ErrClientError)
+
+	progress, err := c.MigrateRelation(context.Background(), "document", "reader", "viewer", 0)
+	require.ErrorIs(t, err, ErrClientError)
+	assert.False(t, progress.Completed)
+	assert.Zero(t, progress.Migrated)
+}
+
+func TestClient_MigrateRelation_InvalidArguments(t *testing.T) {
+	c := fga.NewMockFGAClient(fgamock.NewMockSdkClient(gomock.NewController(t)))
+	ctx := context.Background()
+
+	_, err := c.MigrateRelation(ctx, "", "reader", "viewer", 0)
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+
+	_, err = c.MigrateRelation(ctx, "document", "reader", "reader", 0)
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+
+	_, err = c.MigrateRelation(ctx, "document", "reader", "viewer", fga.MaxMigrationBatchSize+1)
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+}
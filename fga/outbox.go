@@ -0,0 +1,385 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openfga/go-sdk/client"
+	"github.com/rs/zerolog/log"
+)
+
+// Default settings for the outbox relay.
+const (
+	// DefaultRelayBatchSize is the number of outbox records processed per batch.
+	DefaultRelayBatchSize = 100
+	// DefaultRelayMaxAttempts is the number of attempts after which a record is
+	// given up on and reported as final failure to the store.
+	DefaultRelayMaxAttempts = 10
+	// DefaultRelayInterval is the polling interval used by Relay.Run.
+	DefaultRelayInterval = 5 * time.Second
+)
+
+// OutboxRecord is a single pending tuple change stored in the outbox.
+// The Payload is opaque to the store and is decoded by the Relay.
+type OutboxRecord struct {
+	// ID uniquely identifies the record and serves as idempotency key.
+	ID string
+	// Payload is the serialized tuple change set.
+	Payload []byte
+	// CreatedAt is the time the record was written to the outbox.
+	CreatedAt time.Time
+	// Attempts is the number of failed relay attempts so far.
+	Attempts int
+}
+
+// OutboxTx is the transactional handle the OutboxWriter writes records into.
+// It is typically implemented by a thin adapter around the caller's database
+// transaction so the record commits or rolls back together with the
+// business data.
+type OutboxTx interface {
+	// InsertOutboxRecord persists the record as part of the transaction.
+	InsertOutboxRecord(ctx context.Context, record OutboxRecord) error
+}
+
+// OutboxStore gives the Relay access to committed outbox records.
+type OutboxStore interface {
+	// PendingOutboxRecords returns up to limit unprocessed records, oldest first.
+	PendingOutboxRecords(ctx context.Context, limit int) ([]OutboxRecord, error)
+	// MarkOutboxRecordDone marks the record as successfully relayed.
+	MarkOutboxRecordDone(ctx context.Context, id string) error
+	// MarkOutboxRecordFailed records a failed attempt. When final is true the
+	// relay has given up on the record and it should no longer be returned by
+	// PendingOutboxRecords.
+	MarkOutboxRecordFailed(ctx context.Context, id string, cause error, final bool) error
+}
+
+// outboxTuple is the serialized form of a TupleKey.
+type outboxTuple struct {
+	User      string           `json:"user"`
+	Relation  string           `json:"relation"`
+	Object    string           `json:"object"`
+	Condition *outboxCondition `json:"condition,omitempty"`
+}
+
+// outboxCondition is the serialized form of a Condition.
+type outboxCondition struct {
+	Name    string          `json:"name"`
+	Context *map[string]any `json:"context,omitempty"`
+}
+
+// outboxPayload is the serialized form of a tuple change set.
+type outboxPayload struct {
+	Writes  []outboxTuple `json:"writes,omitempty"`
+	Deletes []outboxTuple `json:"deletes,omitempty"`
+}
+
+// OutboxWriter records intended tuple writes and deletes into a caller-provided
+// transaction. The changes are applied to FGA later by a Relay, which keeps
+// FGA consistent with the database even if the process dies after commit.
+type OutboxWriter struct {
+	now   func() time.Time
+	newID func() string
+}
+
+// NewOutboxWriter creates a new OutboxWriter.
+func NewOutboxWriter() *OutboxWriter {
+	return &OutboxWriter{
+		now:   time.Now,
+		newID: uuid.NewString,
+	}
+}
+
+// Write serializes the given tuple changes and inserts them into tx.
+// Returns the ID of the created outbox record.
+//
+// Example:
+//
+//	id, err := outbox.Write(ctx, txAdapter, []fga.TupleKey{
+//	    {Subject: user, Relation: "member", Object: org},
+//	}, nil)
+func (w *OutboxWriter) Write(ctx context.Context, tx OutboxTx, writes []TupleKey, deletes []TupleKey) (string, error) {
+	if tx == nil {
+		return "", fmt.Errorf("%w: transaction is required", ErrInvalidArgument)
+	}
+
+	if len(writes) == 0 && len(deletes) == 0 {
+		return "", fmt.Errorf("%w: at least one write or delete is required", ErrInvalidArgument)
+	}
+
+	payload, err := encodeOutboxPayload(writes, deletes)
+	if err != nil {
+		return "", err
+	}
+
+	record := OutboxRecord{
+		ID:        w.newID(),
+		Payload:   payload,
+		CreatedAt: w.now(),
+	}
+
+	if err := tx.InsertOutboxRecord(ctx, record); err != nil {
+		return "", fmt.Errorf("failed to insert outbox record: %w", err)
+	}
+
+	return record.ID, nil
+}
+
+// Relay applies committed outbox records to FGA.
+//
+// Records are written with conflict options that ignore tuples that already
+// exist and deletes of tuples that are already gone, so a record that is
+// relayed more than once (e.g. because marking it as done failed) has no
+// additional effect. Any other write error fails the record, which is then
+// retried by a later batch.
+type Relay struct {
+	client      *Client
+	store       OutboxStore
+	batchSize   int
+	maxAttempts int
+	interval    time.Duration
+}
+
+// RelayOption configures a Relay.
+type RelayOption func(*Relay)
+
+// WithRelayBatchSize sets the number of records processed per batch.
+func WithRelayBatchSize(n int) RelayOption {
+	return func(r *Relay) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithRelayMaxAttempts sets the number of attempts after which a record is
+// reported as final failure.
+func WithRelayMaxAttempts(n int) RelayOption {
+	return func(r *Relay) {
+		if n > 0 {
+			r.maxAttempts = n
+		}
+	}
+}
+
+// WithRelayInterval sets the polling interval used by Run.
+func WithRelayInterval(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		if d > 0 {
+			r.interval = d
+		}
+	}
+}
+
+// NewRelay creates a new Relay that applies records from store using client.
+func NewRelay(client *Client, store OutboxStore, opts ...RelayOption) *Relay {
+	r := &Relay{
+		client:      client,
+		store:       store,
+		batchSize:   DefaultRelayBatchSize,
+		maxAttempts: DefaultRelayMaxAttempts,
+		interval:    DefaultRelayInterval,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// ProcessBatch relays a single batch of pending records.
+// Returns the number of records that were applied successfully.
+// Failures of individual records are reported to the store and do not
+// abort the batch; only errors from the store itself are returned.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	records, err := r.store.PendingOutboxRecords(ctx, r.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load outbox records: %w", err)
+	}
+
+	applied := 0
+
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return applied, err
+		}
+
+		if err := r.apply(ctx, record); err != nil {
+			final := record.Attempts+1 >= r.maxAttempts
+
+			log.Warn().
+				Err(err).
+				Str("record", record.ID).
+				Int("attempt", record.Attempts+1).
+				Bool("final", final).
+				Msg("failed to relay outbox record")
+
+			if merr := r.store.MarkOutboxRecordFailed(ctx, record.ID, err, final); merr != nil {
+				return applied, fmt.Errorf("failed to mark outbox record %s as failed: %w", record.ID, merr)
+			}
+
+			continue
+		}
+
+		if err := r.store.MarkOutboxRecordDone(ctx, record.ID); err != nil {
+			return applied, fmt.Errorf("failed to mark outbox record %s as done: %w", record.ID, err)
+		}
+
+		applied++
+	}
+
+	return applied, nil
+}
+
+// Run processes the outbox until ctx is canceled. Full batches are followed
+// immediately by the next batch; otherwise Run waits for the configured
+// interval before polling again.
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		n, err := r.ProcessBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("outbox relay batch failed")
+		}
+
+		if err == nil && n == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// apply decodes a record and writes its tuple changes to FGA.
+func (r *Relay) apply(ctx context.Context, record OutboxRecord) error {
+	writes, deletes, err := decodeOutboxPayload(record.Payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.client.Write(ctx).
+		Body(client.ClientWriteRequest{
+			Writes:  tupleKeyToWriteRequest(writes),
+			Deletes: tupleKeyToDeleteRequest(deletes),
+		}).
+		Options(client.ClientWriteOptions{
+			Conflict: client.ClientWriteConflictOptions{
+				OnDuplicateWrites: client.CLIENT_WRITE_REQUEST_ON_DUPLICATE_WRITES_IGNORE,
+				OnMissingDeletes:  client.CLIENT_WRITE_REQUEST_ON_MISSING_DELETES_IGNORE,
+			},
+		}).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to write outbox tuples: %w", err)
+	}
+
+	return nil
+}
+
+// encodeOutboxPayload serializes a tuple change set.
+func encodeOutboxPayload(writes, deletes []TupleKey) ([]byte, error) {
+	p := outboxPayload{
+		Writes:  make([]outboxTuple, 0, len(writes)),
+		Deletes: make([]outboxTuple, 0, len(deletes)),
+	}
+
+	for _, t := range writes {
+		p.Writes = append(p.Writes, toOutboxTuple(t))
+	}
+
+	for _, t := range deletes {
+		p.Deletes = append(p.Deletes, toOutboxTuple(t))
+	}
+
+	return json.Marshal(p)
+}
+
+// decodeOutboxPayload deserializes a tuple change set.
+func decodeOutboxPayload(data []byte) ([]TupleKey, []TupleKey, error) {
+	var p outboxPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid outbox payload: %w", ErrInvalidArgument, err)
+	}
+
+	writes, err := fromOutboxTuples(p.Writes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deletes, err := fromOutboxTuples(p.Deletes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return writes, deletes, nil
+}
+
+func toOutboxTuple(t TupleKey) outboxTuple {
+	ot := outboxTuple{
+		User:     t.Subject.String(),
+		Relation: t.Relation.String(),
+		Object:   t.Object.String(),
+	}
+
+	if t.Condition.Name != "" {
+		ot.Condition = &outboxCondition{
+			Name:    t.Condition.Name,
+			Context: t.Condition.Context,
+		}
+	}
+
+	return ot
+}
+
+func fromOutboxTuples(tuples []outboxTuple) ([]TupleKey, error) {
+	out := make([]TupleKey, 0, len(tuples))
+
+	for _, ot := range tuples {
+		subject, err := parseOutboxEntity(ot.User)
+		if err != nil {
+			return nil, err
+		}
+
+		object, err := ParseEntity(ot.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		t := TupleKey{
+			Subject:  subject,
+			Object:   object,
+			Relation: Relation(ot.Relation),
+		}
+
+		if ot.Condition != nil {
+			t.Condition = Condition{
+				Name:    ot.Condition.Name,
+				Context: ot.Condition.Context,
+			}
+		}
+
+		out = append(out, t)
+	}
+
+	return out, nil
+}
+
+// parseOutboxEntity parses a subject string. Unlike ParseEntity it accepts
+// typed wildcards such as "user:*".
+func parseOutboxEntity(s string) (Entity, error) {
+	if kind, id, ok := strings.Cut(s, ":"); ok && id == Wildcard {
+		return Entity{Kind: Kind(kind), Identifier: Wildcard}, nil
+	}
+
+	return ParseEntity(s)
+}
@@ -0,0 +1,232 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package fga_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/kopexa-grc/common/fga/internal/fgamock"
+	openfga "github.com/openfga/go-sdk"
+	"github.com/openfga/go-sdk/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// memOutbox is an in-memory OutboxTx and OutboxStore.
+type memOutbox struct {
+	records []fga.OutboxRecord
+	done    map[string]bool
+	failed  map[string]bool
+}
+
+func newMemOutbox() *memOutbox {
+	return &memOutbox{done: map[string]bool{}, failed: map[string]bool{}}
+}
+
+func (m *memOutbox) InsertOutboxRecord(_ context.Context, record fga.OutboxRecord) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+func (m *memOutbox) PendingOutboxRecords(_ context.Context, limit int) ([]fga.OutboxRecord, error) {
+	var out []fga.OutboxRecord
+
+	for _, r := range m.records {
+		if m.done[r.ID] || m.failed[r.ID] {
+			continue
+		}
+
+		out = append(out, r)
+		if len(out) == limit {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+func (m *memOutbox) MarkOutboxRecordDone(_ context.Context, id string) error {
+	m.done[id] = true
+	return nil
+}
+
+func (m *memOutbox) MarkOutboxRecordFailed(_ context.Context, id string, _ error, final bool) error {
+	for i := range m.records {
+		if m.records[i].ID == id {
+			m.records[i].Attempts++
+		}
+	}
+
+	if final {
+		m.failed[id] = true
+	}
+
+	return nil
+}
+
+func TestOutboxWriter_Write(t *testing.T) {
+	outbox := newMemOutbox()
+	w := fga.NewOutboxWriter()
+
+	_, err := w.Write(context.Background(), outbox, nil, nil)
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+
+	_, err = w.Write(context.Background(), nil, []fga.TupleKey{{}}, nil)
+	assert.ErrorIs(t, err, fga.ErrInvalidArgument)
+
+	id, err := w.Write(context.Background(), outbox, []fga.TupleKey{
+		{
+			Subject:  fga.Entity{Kind: "user", Identifier: "123"},
+			Relation: "member",
+			Object:   fga.Entity{Kind: "organization", Identifier: "456"},
+		},
+	}, []fga.TupleKey{
+		{
+			Subject:  fga.Entity{Kind: "user", Identifier: "*"},
+			Relation: "viewer",
+			Object:   fga.Entity{Kind: "document", Identifier: "789"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, outbox.records, 1)
+	assert.Equal(t, id, outbox.records[0].ID)
+	assert.NotEmpty(t, outbox.records[0].Payload)
+}
+
+func TestRelay_ProcessBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockWrite := fgamock.NewMockSdkClientWriteRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	outbox := newMemOutbox()
+	w := fga.NewOutboxWriter()
+
+	_, err := w.Write(context.Background(), outbox, []fga.TupleKey{
+		{
+			Subject:  fga.Entity{Kind: "user", Identifier: "123"},
+			Relation: "member",
+			Object:   fga.Entity{Kind: "organization", Identifier: "456"},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	mockSdk.EXPECT().Write(gomock.Any()).Return(mockWrite).Times(1)
+	mockWrite.EXPECT().Body(client.ClientWriteRequest{
+		Writes: []client.ClientTupleKey{
+			{User: "user:123", Relation: "member", Object: "organization:456"},
+		},
+		Deletes: []openfga.TupleKeyWithoutCondition{},
+	}).Return(mockWrite).Times(1)
+	mockWrite.EXPECT().Options(client.ClientWriteOptions{
+		Conflict: client.ClientWriteConflictOptions{
+			OnDuplicateWrites: client.CLIENT_WRITE_REQUEST_ON_DUPLICATE_WRITES_IGNORE,
+			OnMissingDeletes:  client.CLIENT_WRITE_REQUEST_ON_MISSING_DELETES_IGNORE,
+		},
+	}).Return(mockWrite).Times(1)
+	mockWrite.EXPECT().Execute().Return(&client.ClientWriteResponse{}, nil).Times(1)
+
+	relay := fga.NewRelay(c, outbox)
+
+	n, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.True(t, outbox.done[outbox.records[0].ID])
+
+	// Nothing left to relay.
+	n, err = relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestRelay_ProcessBatchRetries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockWrite := fgamock.NewMockSdkClientWriteRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	outbox := newMemOutbox()
+
+	_, err := fga.NewOutboxWriter().Write(context.Background(), outbox, []fga.TupleKey{
+		{
+			Subject:  fga.Entity{Kind: "user", Identifier: "123"},
+			Relation: "member",
+			Object:   fga.Entity{Kind: "organization", Identifier: "456"},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	mockSdk.EXPECT().Write(gomock.Any()).Return(mockWrite).Times(2)
+	mockWrite.EXPECT().Body(gomock.Any()).Return(mockWrite).Times(2)
+	mockWrite.EXPECT().Options(gomock.Any()).Return(mockWrite).Times(2)
+	mockWrite.EXPECT().Execute().Return(nil, ErrClientError).Times(2)
+
+	relay := fga.NewRelay(c, outbox, fga.WithRelayMaxAttempts(2))
+
+	n, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, 1, outbox.records[0].Attempts)
+	assert.False(t, outbox.failed[outbox.records[0].ID])
+
+	n, err = relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.True(t, outbox.failed[outbox.records[0].ID])
+
+	// The record has been given up on.
+	n, err = relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestRelay_ProcessBatchPartialResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockWrite := fgamock.NewMockSdkClientWriteRequestInterface(ctrl)
+	c := fga.NewMockFGAClient(mockSdk)
+
+	outbox := newMemOutbox()
+
+	_, err := fga.NewOutboxWriter().Write(context.Background(), outbox, []fga.TupleKey{
+		{
+			Subject:  fga.Entity{Kind: "user", Identifier: "123"},
+			Relation: "member",
+			Object:   fga.Entity{Kind: "organization", Identifier: "456"},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	// The SDK returns the per-tuple results together with the error.
+	mockSdk.EXPECT().Write(gomock.Any()).Return(mockWrite).Times(1)
+	mockWrite.EXPECT().Body(gomock.Any()).Return(mockWrite).Times(1)
+	mockWrite.EXPECT().Options(gomock.Any()).Return(mockWrite).Times(1)
+	mockWrite.EXPECT().Execute().Return(&client.ClientWriteResponse{
+		Writes: []client.ClientWriteRequestWriteResponse{
+			{
+				TupleKey:     client.ClientTupleKey{User: "user:123", Relation: "member", Object: "organization:456"},
+				Status:       client.FAILURE,
+				HttpResponse: nil,
+				Error:        ErrClientError,
+			},
+		},
+	}, ErrClientError).Times(1)
+
+	relay := fga.NewRelay(c, outbox)
+
+	n, err := relay.ProcessBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.False(t, outbox.done[outbox.records[0].ID], "failed records must not be marked as done")
+	assert.Equal(t, 1, outbox.records[0].Attempts)
+}
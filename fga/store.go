@@ -80,3 +80,27 @@ func (c *Client) CreateStore(storeName string) (string, error) {
 
 	return storeID, nil
 }
+
+// CreateNamedStore always creates a new store with the given name and returns
+// its ID. Unlike CreateStore, it never reuses an existing store, which makes
+// it suitable for provisioning isolated stores, e.g. in tests.
+func (c *Client) CreateNamedStore(ctx context.Context, storeName string) (string, error) {
+	resp, err := c.client.CreateStore(ctx).Body(client.ClientCreateStoreRequest{
+		Name: storeName,
+	}).Execute()
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetId(), nil
+}
+
+// DeleteStore deletes the store with the given ID, including all of its
+// authorization models and tuples.
+func (c *Client) DeleteStore(ctx context.Context, storeID string) error {
+	_, err := c.client.DeleteStore(ctx).Options(client.ClientDeleteStoreOptions{
+		StoreId: openfga.PtrString(storeID),
+	}).Execute()
+
+	return err
+}
@@ -104,3 +104,38 @@ func TestClient_CreateStore(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_CreateNamedStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockCreate := fgamock.NewMockSdkClientCreateStoreRequestInterface(ctrl)
+
+	c := fga.NewMockFGAClient(mockSdk)
+
+	// existing stores are not consulted
+	mockSdk.EXPECT().CreateStore(gomock.Any()).Return(mockCreate).Times(1)
+	mockCreate.EXPECT().Body(client.ClientCreateStoreRequest{Name: "test-store"}).Return(mockCreate).Times(1)
+	mockCreate.EXPECT().Execute().Return(&client.ClientCreateStoreResponse{Id: "store-789"}, nil).Times(1)
+
+	storeID, err := c.CreateNamedStore(t.Context(), "test-store")
+	assert.NoError(t, err)
+	assert.Equal(t, "store-789", storeID)
+}
+
+func TestClient_DeleteStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSdk := fgamock.NewMockSdkClient(ctrl)
+	mockDelete := fgamock.NewMockSdkClientDeleteStoreRequestInterface(ctrl)
+
+	c := fga.NewMockFGAClient(mockSdk)
+
+	mockSdk.EXPECT().DeleteStore(gomock.Any()).Return(mockDelete).Times(1)
+	mockDelete.EXPECT().Options(client.ClientDeleteStoreOptions{StoreId: openfga.PtrString("store-789")}).Return(mockDelete).Times(1)
+	mockDelete.EXPECT().Execute().Return(&client.ClientDeleteStoreResponse{}, nil).Times(1)
+
+	assert.NoError(t, c.DeleteStore(t.Context(), "store-789"))
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package jws encodes and decodes the compact JWS serialization (RFC 7515)
+// shared by the token packages of iam. It only handles the framing; signing
+// and the choice of algorithm stay with the callers, which must take the
+// algorithm from the verification key and not from the header.
+package jws
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrMalformed is returned when a token is not a compact JWS of the
+// expected type.
+var ErrMalformed = errors.New("jws: malformed token")
+
+// Header is the JOSE header.
+type Header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ"`
+}
+
+// Token is a parsed, not yet verified compact JWS.
+type Token struct {
+	// Header is the decoded JOSE header.
+	Header Header
+	// SigningInput is the encoded header and payload the signature is
+	// computed over.
+	SigningInput []byte
+	// Signature is the decoded signature.
+	Signature []byte
+
+	payload string
+}
+
+// Sign returns the compact serialization of header and the JSON encoding of
+// claims, signed with sign.
+func Sign(header Header, claims any, sign func(signingInput []byte) ([]byte, error)) (string, error) {
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encode(h) + "." + encode(c)
+
+	sig, err := sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + encode(sig), nil
+}
+
+// Parse splits token and decodes its header and signature. It returns
+// ErrMalformed if token is not a compact JWS or its "typ" is not typ.
+func Parse(token, typ string) (*Token, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:mnd // header, payload, signature
+		return nil, ErrMalformed
+	}
+
+	var h Header
+	if err := decode(parts[0], &h); err != nil || h.Type != typ {
+		return nil, ErrMalformed
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	return &Token{
+		Header:       h,
+		SigningInput: []byte(parts[0] + "." + parts[1]),
+		Signature:    sig,
+		payload:      parts[1],
+	}, nil
+}
+
+// Claims decodes the payload into v. Call it only after the signature has
+// been verified.
+func (t *Token) Claims(v any) error {
+	if err := decode(t.payload, v); err != nil {
+		return ErrMalformed
+	}
+
+	return nil
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
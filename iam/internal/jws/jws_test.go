@@ -0,0 +1,52 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package jws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignParse(t *testing.T) {
+	type claims struct {
+		Subject string `json:"sub"`
+	}
+
+	var signed []byte
+
+	token, err := Sign(Header{Algorithm: "EdDSA", KeyID: "k1", Type: "JWT"}, claims{Subject: "user-1"}, func(input []byte) ([]byte, error) {
+		signed = input
+		return []byte("sig"), nil
+	})
+	require.NoError(t, err)
+
+	parsed, err := Parse(token, "JWT")
+	require.NoError(t, err)
+	assert.Equal(t, Header{Algorithm: "EdDSA", KeyID: "k1", Type: "JWT"}, parsed.Header)
+	assert.Equal(t, signed, parsed.SigningInput)
+	assert.Equal(t, []byte("sig"), parsed.Signature)
+
+	var c claims
+	require.NoError(t, parsed.Claims(&c))
+	assert.Equal(t, "user-1", c.Subject)
+
+	_, err = Parse(token, "svc+jwt")
+	assert.ErrorIs(t, err, ErrMalformed)
+
+	for _, malformed := range []string{"", "a.b", "a.b.c.d", "!.b.c", token + "!"} {
+		_, err := Parse(malformed, "JWT")
+		assert.ErrorIs(t, err, ErrMalformed, malformed)
+	}
+
+	parsed.payload = strings.Repeat("!", 4)
+	assert.ErrorIs(t, parsed.Claims(&c), ErrMalformed)
+
+	signErr := errors.New("kms unavailable")
+	_, err = Sign(Header{}, claims{}, func([]byte) ([]byte, error) { return nil, signErr })
+	assert.ErrorIs(t, err, signErr)
+}
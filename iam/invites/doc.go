@@ -0,0 +1,25 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+//
+// Package invites orchestrates the organization invitation flow on top of
+// the tokens, fga and errors packages.
+//
+// Issuing
+// IssueInvite creates an OrganizationInviteToken for the invitee, signs it and
+// persists the resulting Invite (including the secret) through a Store. The
+// signature is what ends up in the invitation link; the secret never leaves
+// the server unless the caller decides to embed it.
+//
+// Accepting
+// AcceptInvite looks up the invite by signature, verifies the signature
+// against the secret and grants the accepting user the invited role on the
+// organization. The invite is claimed in the store before the membership
+// tuple is written; if writing the tuple fails the claim is released again,
+// so an invite is never marked as accepted without the membership existing
+// and can be retried after a transient FGA outage.
+//
+// Store implementations must make Claim a compare-and-set (pending ->
+// accepted) so that concurrent acceptances of the same invite cannot both
+// succeed, and must round-trip ExpiresAt with at least second precision, as
+// it is part of the signed token data.
+package invites
@@ -0,0 +1,27 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package invites
+
+import "github.com/kopexa-grc/common/errors"
+
+var (
+	// ErrNilStore is returned by New when no Store is configured.
+	ErrNilStore = errors.NewUnexpectedFailure("invites: store is required")
+	// ErrNilTupleWriter is returned by New when no TupleWriter is configured.
+	ErrNilTupleWriter = errors.NewUnexpectedFailure("invites: tuple writer is required")
+	// ErrMissingOrganizationID is returned when an invite is issued without an organization.
+	ErrMissingOrganizationID = errors.NewBadRequest("organization id is required")
+	// ErrInvalidRole is returned when an invite is issued for a role that is not allowed.
+	ErrInvalidRole = errors.NewBadRequest("invalid invite role")
+	// ErrInviteNotFound is returned when no invite exists for a signature.
+	// Store implementations should return it from GetBySignature.
+	ErrInviteNotFound = errors.NewNotFound("invite not found")
+	// ErrInviteNotPending is returned when an invite has already been accepted or revoked.
+	// Store implementations should return it from Claim if the invite is not pending.
+	ErrInviteNotPending = errors.NewConflict("invite is no longer pending")
+	// ErrInviteSecretMismatch is returned when the secret does not belong to the invite.
+	ErrInviteSecretMismatch = errors.NewBadRequest("invite secret does not match")
+	// ErrUserRequired is returned when an invite is accepted without an authenticated user.
+	ErrUserRequired = errors.NewUnauthorized("an authenticated user is required to accept an invite")
+)
@@ -0,0 +1,255 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package invites
+
+import (
+	"context"
+	"crypto/hmac"
+	stderrors "errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openfga/go-sdk/client"
+
+	"github.com/kopexa-grc/common/errors"
+	"github.com/kopexa-grc/common/fga"
+	"github.com/kopexa-grc/common/iam/auth"
+	"github.com/kopexa-grc/common/iam/tokens"
+)
+
+// Status is the lifecycle state of an invite.
+type Status string
+
+const (
+	// StatusPending marks an invite that can still be accepted.
+	StatusPending Status = "pending"
+	// StatusAccepted marks an invite that has been accepted.
+	StatusAccepted Status = "accepted"
+	// StatusRevoked marks an invite that has been withdrawn.
+	StatusRevoked Status = "revoked"
+)
+
+const (
+	// DefaultObjectKind is the FGA type the membership tuple is written to.
+	DefaultObjectKind fga.Kind = "organization"
+	// RoleMember is the default organization member role.
+	RoleMember fga.Relation = "member"
+	// RoleAdmin is the organization admin role.
+	RoleAdmin fga.Relation = "admin"
+)
+
+// DefaultRoles are the roles an invite can be issued for unless configured otherwise.
+var DefaultRoles = []fga.Relation{RoleMember, RoleAdmin}
+
+// Invite is a persisted organization invitation.
+type Invite struct {
+	// ID uniquely identifies the invite.
+	ID string
+	// OrganizationID is the organization the invitee joins.
+	OrganizationID string
+	// Email is the (normalized) email address of the invitee.
+	Email string
+	// Role is the relation granted on the organization.
+	Role fga.Relation
+	// ExpiresAt is the expiry of the signed token.
+	ExpiresAt time.Time
+	// Signature is the token signature embedded in the invitation link.
+	Signature string
+	// Secret is required to verify the signature.
+	Secret []byte
+	// Status is the lifecycle state of the invite.
+	Status Status
+	// InvitedBy is the actor that issued the invite.
+	InvitedBy string
+	// AcceptedBy is the user that accepted the invite.
+	AcceptedBy string
+	// AcceptedAt is when the invite was accepted.
+	AcceptedAt time.Time
+}
+
+// Store persists invites.
+type Store interface {
+	// Save persists a newly issued invite.
+	Save(ctx context.Context, invite *Invite) error
+	// GetBySignature returns the invite for a signature or ErrInviteNotFound.
+	GetBySignature(ctx context.Context, signature string) (*Invite, error)
+	// Claim atomically moves a pending invite to accepted and records the
+	// accepting user. It returns ErrInviteNotPending if the invite is not pending.
+	Claim(ctx context.Context, id, userID string, at time.Time) error
+	// Release reverts a claimed invite back to pending. It is called when the
+	// membership could not be written after a successful Claim.
+	Release(ctx context.Context, id string) error
+}
+
+// TupleWriter writes FGA tuples. *fga.Client satisfies it.
+type TupleWriter interface {
+	WriteTupleKeys(ctx context.Context, writes []fga.TupleKey, deletes []fga.TupleKey) (*client.ClientWriteResponse, error)
+}
+
+// Service issues and accepts organization invites.
+type Service struct {
+	store      Store
+	tuples     TupleWriter
+	roles      []fga.Relation
+	objectKind fga.Kind
+	now        func() time.Time
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithRoles restricts the roles invites can be issued for.
+func WithRoles(roles ...fga.Relation) Option {
+	return func(s *Service) {
+		s.roles = roles
+	}
+}
+
+// WithObjectKind sets the FGA type of the membership object (default "organization").
+func WithObjectKind(kind fga.Kind) Option {
+	return func(s *Service) {
+		s.objectKind = kind
+	}
+}
+
+// WithClock sets the clock used for acceptance timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+// New creates a Service backed by the given store and tuple writer.
+func New(store Store, tuples TupleWriter, opts ...Option) (*Service, error) {
+	if store == nil {
+		return nil, ErrNilStore
+	}
+
+	if tuples == nil {
+		return nil, ErrNilTupleWriter
+	}
+
+	s := &Service{
+		store:      store,
+		tuples:     tuples,
+		roles:      DefaultRoles,
+		objectKind: DefaultObjectKind,
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// IssueInvite creates, signs and persists an invite for email to join the
+// organization with the given role. The returned invite carries the signature
+// and secret.
+func (s *Service) IssueInvite(ctx context.Context, organizationID, email string, role fga.Relation) (*Invite, error) {
+	if organizationID == "" {
+		return nil, ErrMissingOrganizationID
+	}
+
+	if !slices.Contains(s.roles, role) {
+		return nil, ErrInvalidRole
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	token, err := tokens.NewOrganizationInviteToken(email, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stores frequently truncate timestamps; the expiry is part of the signed
+	// data, so keep it at a precision every store can round-trip.
+	token.ExpiresAt = token.ExpiresAt.UTC().Truncate(time.Second)
+
+	signature, secret, err := token.Sign()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign invite token")
+	}
+
+	invite := &Invite{
+		ID:             uuid.NewString(),
+		OrganizationID: organizationID,
+		Email:          email,
+		Role:           role,
+		ExpiresAt:      token.ExpiresAt,
+		Signature:      signature,
+		Secret:         secret,
+		Status:         StatusPending,
+		InvitedBy:      auth.ActorFromContext(ctx).ID,
+	}
+
+	if err := s.store.Save(ctx, invite); err != nil {
+		return nil, errors.WrapPreserve(err, "failed to save invite")
+	}
+
+	return invite, nil
+}
+
+// AcceptInvite verifies the invite identified by signature and grants the
+// authenticated user from ctx the invited role on the organization. The claim
+// on the invite is released if the membership cannot be written.
+func (s *Service) AcceptInvite(ctx context.Context, signature string, secret []byte) (*Invite, error) {
+	actor := auth.ActorFromContext(ctx)
+	if actor.Type != auth.ActorTypeUser || actor.ID == "" {
+		return nil, ErrUserRequired
+	}
+
+	invite, err := s.store.GetBySignature(ctx, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if invite.Status != StatusPending {
+		return nil, ErrInviteNotPending
+	}
+
+	if !hmac.Equal(invite.Secret, secret) {
+		return nil, ErrInviteSecretMismatch
+	}
+
+	token := &tokens.OrganizationInviteToken{
+		Email:          invite.Email,
+		OrganizationID: invite.OrganizationID,
+		SigningInfo:    tokens.SigningInfo{ExpiresAt: invite.ExpiresAt},
+	}
+
+	if err := token.Verify(signature, secret); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+
+	if err := s.store.Claim(ctx, invite.ID, actor.ID, now); err != nil {
+		return nil, err
+	}
+
+	membership := fga.TupleKey{
+		Subject:  fga.Entity{Kind: "user", Identifier: actor.ID},
+		Relation: invite.Role,
+		Object:   fga.Entity{Kind: s.objectKind, Identifier: invite.OrganizationID},
+	}
+
+	if _, err := s.tuples.WriteTupleKeys(ctx, []fga.TupleKey{membership}, nil); err != nil {
+		// The caller may already be gone; the rollback must still happen.
+		if rerr := s.store.Release(context.WithoutCancel(ctx), invite.ID); rerr != nil {
+			return nil, errors.WrapPreserve(stderrors.Join(err, rerr), "failed to write membership and release invite")
+		}
+
+		return nil, errors.WrapPreserve(err, "failed to write membership")
+	}
+
+	invite.Status = StatusAccepted
+	invite.AcceptedBy = actor.ID
+	invite.AcceptedAt = now
+
+	return invite, nil
+}
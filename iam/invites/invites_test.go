@@ -0,0 +1,248 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package invites_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openfga/go-sdk/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kopexa-grc/common/fga"
+	"github.com/kopexa-grc/common/iam/auth"
+	"github.com/kopexa-grc/common/iam/invites"
+	"github.com/kopexa-grc/common/iam/tokens"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	invites map[string]*invites.Invite
+	release int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{invites: map[string]*invites.Invite{}}
+}
+
+func (m *memoryStore) Save(_ context.Context, invite *invites.Invite) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cp := *invite
+	m.invites[invite.Signature] = &cp
+
+	return nil
+}
+
+func (m *memoryStore) GetBySignature(_ context.Context, signature string) (*invites.Invite, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invite, ok := m.invites[signature]
+	if !ok {
+		return nil, invites.ErrInviteNotFound
+	}
+
+	cp := *invite
+
+	return &cp, nil
+}
+
+func (m *memoryStore) find(id string) *invites.Invite {
+	for _, invite := range m.invites {
+		if invite.ID == id {
+			return invite
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryStore) Claim(_ context.Context, id, userID string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invite := m.find(id)
+	if invite == nil {
+		return invites.ErrInviteNotFound
+	}
+
+	if invite.Status != invites.StatusPending {
+		return invites.ErrInviteNotPending
+	}
+
+	invite.Status = invites.StatusAccepted
+	invite.AcceptedBy = userID
+	invite.AcceptedAt = at
+
+	return nil
+}
+
+func (m *memoryStore) Release(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.release++
+
+	invite := m.find(id)
+	if invite == nil {
+		return invites.ErrInviteNotFound
+	}
+
+	invite.Status = invites.StatusPending
+	invite.AcceptedBy = ""
+	invite.AcceptedAt = time.Time{}
+
+	return nil
+}
+
+type fakeTuples struct {
+	err    error
+	writes []fga.TupleKey
+}
+
+func (f *fakeTuples) WriteTupleKeys(_ context.Context, writes []fga.TupleKey, _ []fga.TupleKey) (*client.ClientWriteResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	f.writes = append(f.writes, writes...)
+
+	return &client.ClientWriteResponse{}, nil
+}
+
+func userContext(id string) context.Context {
+	return auth.WithActor(context.Background(), &auth.Actor{ID: id, Type: auth.ActorTypeUser})
+}
+
+func TestNew(t *testing.T) {
+	_, err := invites.New(nil, &fakeTuples{})
+	require.ErrorIs(t, err, invites.ErrNilStore)
+
+	_, err = invites.New(newMemoryStore(), nil)
+	require.ErrorIs(t, err, invites.ErrNilTupleWriter)
+}
+
+func TestIssueInvite(t *testing.T) {
+	store := newMemoryStore()
+	svc, err := invites.New(store, &fakeTuples{})
+	require.NoError(t, err)
+
+	ctx := userContext("admin-1")
+
+	invite, err := svc.IssueInvite(ctx, "org-1", " Jane@Example.com ", invites.RoleMember)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, invite.ID)
+	assert.NotEmpty(t, invite.Signature)
+	assert.NotEmpty(t, invite.Secret)
+	assert.Equal(t, "jane@example.com", invite.Email)
+	assert.Equal(t, invites.StatusPending, invite.Status)
+	assert.Equal(t, "admin-1", invite.InvitedBy)
+	assert.Equal(t, invite.ExpiresAt.Truncate(time.Second), invite.ExpiresAt)
+
+	stored, err := store.GetBySignature(ctx, invite.Signature)
+	require.NoError(t, err)
+	assert.Equal(t, invite.Secret, stored.Secret)
+
+	_, err = svc.IssueInvite(ctx, "", "jane@example.com", invites.RoleMember)
+	require.ErrorIs(t, err, invites.ErrMissingOrganizationID)
+
+	_, err = svc.IssueInvite(ctx, "org-1", "jane@example.com", "owner")
+	require.ErrorIs(t, err, invites.ErrInvalidRole)
+
+	_, err = svc.IssueInvite(ctx, "org-1", "", invites.RoleMember)
+	require.ErrorIs(t, err, tokens.ErrInviteTokenMissingEmail)
+}
+
+func TestAcceptInvite(t *testing.T) {
+	store := newMemoryStore()
+	tuples := &fakeTuples{}
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	svc, err := invites.New(store, tuples, invites.WithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	invite, err := svc.IssueInvite(userContext("admin-1"), "org-1", "jane@example.com", invites.RoleAdmin)
+	require.NoError(t, err)
+
+	accepted, err := svc.AcceptInvite(userContext("user-1"), invite.Signature, invite.Secret)
+	require.NoError(t, err)
+
+	assert.Equal(t, invites.StatusAccepted, accepted.Status)
+	assert.Equal(t, "user-1", accepted.AcceptedBy)
+	assert.Equal(t, now, accepted.AcceptedAt)
+
+	require.Len(t, tuples.writes, 1)
+	assert.Equal(t, "user:user-1", tuples.writes[0].Subject.String())
+	assert.Equal(t, invites.RoleAdmin, tuples.writes[0].Relation)
+	assert.Equal(t, "organization:org-1", tuples.writes[0].Object.String())
+
+	_, err = svc.AcceptInvite(userContext("user-2"), invite.Signature, invite.Secret)
+	require.ErrorIs(t, err, invites.ErrInviteNotPending)
+	assert.Len(t, tuples.writes, 1)
+}
+
+func TestAcceptInviteRejects(t *testing.T) {
+	store := newMemoryStore()
+	svc, err := invites.New(store, &fakeTuples{})
+	require.NoError(t, err)
+
+	invite, err := svc.IssueInvite(context.Background(), "org-1", "jane@example.com", invites.RoleMember)
+	require.NoError(t, err)
+
+	_, err = svc.AcceptInvite(context.Background(), invite.Signature, invite.Secret)
+	require.ErrorIs(t, err, invites.ErrUserRequired)
+
+	_, err = svc.AcceptInvite(userContext("user-1"), "unknown", invite.Secret)
+	require.ErrorIs(t, err, invites.ErrInviteNotFound)
+
+	other, err := svc.IssueInvite(context.Background(), "org-1", "john@example.com", invites.RoleMember)
+	require.NoError(t, err)
+
+	_, err = svc.AcceptInvite(userContext("user-1"), invite.Signature, other.Secret)
+	require.ErrorIs(t, err, invites.ErrInviteSecretMismatch)
+
+	// A tampered invite no longer matches the signed token.
+	store.invites[invite.Signature].OrganizationID = "org-2"
+	_, err = svc.AcceptInvite(userContext("user-1"), invite.Signature, invite.Secret)
+	require.ErrorIs(t, err, tokens.ErrTokenInvalid)
+
+	store.invites[invite.Signature].OrganizationID = "org-1"
+	store.invites[invite.Signature].ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = svc.AcceptInvite(userContext("user-1"), invite.Signature, invite.Secret)
+	require.ErrorIs(t, err, tokens.ErrTokenExpired)
+}
+
+func TestAcceptInviteRollback(t *testing.T) {
+	store := newMemoryStore()
+	tuples := &fakeTuples{err: errors.New("fga unavailable")}
+
+	svc, err := invites.New(store, tuples)
+	require.NoError(t, err)
+
+	invite, err := svc.IssueInvite(context.Background(), "org-1", "jane@example.com", invites.RoleMember)
+	require.NoError(t, err)
+
+	_, err = svc.AcceptInvite(userContext("user-1"), invite.Signature, invite.Secret)
+	require.Error(t, err)
+	assert.Equal(t, 1, store.release)
+
+	stored, err := store.GetBySignature(context.Background(), invite.Signature)
+	require.NoError(t, err)
+	assert.Equal(t, invites.StatusPending, stored.Status)
+	assert.Empty(t, stored.AcceptedBy)
+
+	// Once FGA recovers the invite can be accepted.
+	tuples.err = nil
+
+	accepted, err := svc.AcceptInvite(userContext("user-1"), invite.Signature, invite.Secret)
+	require.NoError(t, err)
+	assert.Equal(t, invites.StatusAccepted, accepted.Status)
+}
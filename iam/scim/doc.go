@@ -0,0 +1,38 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+//
+// Package scim implements the resources and the PATCH operation of SCIM 2.0,
+// the protocol enterprise identity providers use to provision users and
+// groups.
+//
+// Resources
+// User and Group follow the core schema of RFC 7643, including the enterprise
+// user extension. They marshal to the JSON representation of the RFC: the
+// "schemas" attribute is filled in when empty, and the write-only password is
+// never returned. Attribute names are matched case-insensitively when
+// decoding, as the RFC requires. ListResponse wraps query results.
+//
+// Patching
+// ParsePatch decodes and validates a PatchOp request of RFC 7644, section
+// 3.5.2. PatchRequest.Apply applies its operations to a resource:
+//
+//	req, err := scim.ParsePatch(body)
+//	if err != nil {
+//	    return err // *scim.Error, see WriteError
+//	}
+//
+//	user := loadUser(ctx, id)
+//	if err := req.Apply(user); err != nil {
+//	    return err
+//	}
+//
+// Paths may select values of multi-valued attributes with a filter, e.g.
+// `emails[type eq "work"].value` or `members[value eq "2819c223"]`, and may be
+// qualified with a schema URN. Filters support the operators eq, ne, co, sw,
+// ew, gt, ge, lt, le and pr, combined with and, or, not and parentheses.
+// String comparisons ignore case.
+//
+// Errors
+// Errors are returned as *Error carrying the HTTP status and the scimType of
+// RFC 7644, section 3.12. Its JSON representation is the SCIM error response.
+package scim
@@ -0,0 +1,114 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// scimType values of RFC 7644, section 3.12.
+const (
+	ScimTypeInvalidFilter = "invalidFilter"
+	ScimTypeInvalidSyntax = "invalidSyntax"
+	ScimTypeInvalidPath   = "invalidPath"
+	ScimTypeNoTarget      = "noTarget"
+	ScimTypeInvalidValue  = "invalidValue"
+	ScimTypeMutability    = "mutability"
+	ScimTypeUniqueness    = "uniqueness"
+)
+
+var (
+	// ErrInvalidFilter is returned when a filter in a path cannot be parsed.
+	ErrInvalidFilter = &Error{Status: http.StatusBadRequest, ScimType: ScimTypeInvalidFilter}
+	// ErrInvalidSyntax is returned when a request body is not a valid PatchOp request.
+	ErrInvalidSyntax = &Error{Status: http.StatusBadRequest, ScimType: ScimTypeInvalidSyntax}
+	// ErrInvalidPath is returned when a path cannot be parsed.
+	ErrInvalidPath = &Error{Status: http.StatusBadRequest, ScimType: ScimTypeInvalidPath}
+	// ErrNoTarget is returned when a path selects no value.
+	ErrNoTarget = &Error{Status: http.StatusBadRequest, ScimType: ScimTypeNoTarget}
+	// ErrInvalidValue is returned when a value is missing or does not fit the attribute.
+	ErrInvalidValue = &Error{Status: http.StatusBadRequest, ScimType: ScimTypeInvalidValue}
+	// ErrMutability is returned when an operation modifies a read-only attribute.
+	ErrMutability = &Error{Status: http.StatusBadRequest, ScimType: ScimTypeMutability}
+	// ErrUniqueness is returned by services when a userName or displayName is taken.
+	ErrUniqueness = &Error{Status: http.StatusConflict, ScimType: ScimTypeUniqueness}
+)
+
+// Error is a SCIM error. Its JSON representation is the error response of
+// RFC 7644, section 3.12. Errors match the sentinels above with errors.Is
+// regardless of their Detail.
+type Error struct {
+	// Status is the HTTP status code.
+	Status int
+	// ScimType is the SCIM error type, e.g. ScimTypeInvalidPath.
+	ScimType string
+	// Detail is a human-readable description of the error.
+	Detail string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	msg := "scim: " + http.StatusText(e.Status)
+	if e.ScimType != "" {
+		msg = "scim: " + e.ScimType
+	}
+
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+
+	return msg
+}
+
+// Is reports whether target is an *Error with the same status and type.
+func (e *Error) Is(target error) bool {
+	var t *Error
+	if !errors.As(target, &t) {
+		return false
+	}
+
+	return t.Status == e.Status && t.ScimType == e.ScimType
+}
+
+// HTTPStatusCode returns the HTTP status code of the error.
+func (e *Error) HTTPStatusCode() int {
+	return e.Status
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Schemas  []string `json:"schemas"`
+		Status   string   `json:"status"`
+		ScimType string   `json:"scimType,omitempty"`
+		Detail   string   `json:"detail,omitempty"`
+	}{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(e.Status),
+		ScimType: e.ScimType,
+		Detail:   e.Detail,
+	})
+}
+
+// WriteError writes err as SCIM error response. Errors other than *Error are
+// written as 500 Internal Server Error without details.
+func WriteError(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{Status: http.StatusInternalServerError}
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(e.Status)
+	_ = json.NewEncoder(w).Encode(e)
+}
+
+// errorf returns a copy of base with a formatted Detail.
+func errorf(base *Error, format string, args ...any) *Error {
+	return &Error{Status: base.Status, ScimType: base.ScimType, Detail: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,436 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package scim
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// PATCH operations of RFC 7644, section 3.5.2.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// readOnlyAttrs are the attributes a PATCH request must not modify.
+var readOnlyAttrs = []string{"id", "meta", "schemas", "groups"}
+
+// PatchRequest is a PatchOp request of RFC 7644, section 3.5.2.
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is a single operation of a PatchRequest.
+type PatchOperation struct {
+	// Op is "add", "remove" or "replace". It is matched case-insensitively,
+	// as some identity providers send "Add" or "Replace".
+	Op string `json:"op"`
+	// Path selects the attribute to modify. It is optional for add and
+	// replace, in which case Value holds the attributes to modify.
+	Path string `json:"path,omitempty"`
+	// Value is the value to add or replace.
+	Value json.RawMessage `json:"value,omitempty"`
+
+	path *path
+}
+
+// ParsePatch decodes and validates a PatchOp request body.
+func ParsePatch(data []byte) (*PatchRequest, error) {
+	var req PatchRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, errorf(ErrInvalidSyntax, "invalid PatchOp request: %v", err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// Validate checks the schema and the operations of the request and parses
+// their paths.
+func (r *PatchRequest) Validate() error {
+	if !slices.ContainsFunc(r.Schemas, func(s string) bool { return strings.EqualFold(s, SchemaPatchOp) }) {
+		return errorf(ErrInvalidSyntax, "schemas must contain %s", SchemaPatchOp)
+	}
+
+	if len(r.Operations) == 0 {
+		return errorf(ErrInvalidSyntax, "no operations")
+	}
+
+	for i := range r.Operations {
+		if err := r.Operations[i].validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *PatchOperation) validate() error {
+	o.Op = strings.ToLower(o.Op)
+
+	switch o.Op {
+	case OpAdd, OpReplace:
+		if len(bytes.TrimSpace(o.Value)) == 0 {
+			return errorf(ErrInvalidValue, "%s operation requires a value", o.Op)
+		}
+	case OpRemove:
+		if o.Path == "" {
+			return errorf(ErrNoTarget, "remove operation requires a path")
+		}
+	default:
+		return errorf(ErrInvalidSyntax, "unknown operation %q", o.Op)
+	}
+
+	o.path = nil
+
+	if o.Path != "" {
+		p, err := parsePath(o.Path)
+		if err != nil {
+			return err
+		}
+
+		if p.schema == "" && slices.ContainsFunc(readOnlyAttrs, func(a string) bool { return strings.EqualFold(a, p.attr) }) {
+			return errorf(ErrMutability, "attribute %q is read-only", p.attr)
+		}
+
+		o.path = p
+	}
+
+	return nil
+}
+
+// Apply applies the operations in order to resource, a pointer to a User,
+// Group or any other struct with SCIM JSON tags. The resource is only
+// modified if all operations succeed.
+//
+// Read-only attributes (id, meta, schemas and groups) in the value of an
+// operation without path are ignored, as some clients send the complete
+// resource.
+func (r *PatchRequest) Apply(resource any) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(resource)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errorf(ErrInvalidValue, "resource must be a non-nil pointer")
+	}
+
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return errorf(ErrInvalidValue, "resource is not a JSON object")
+	}
+
+	for _, op := range r.Operations {
+		if err := op.apply(doc); err != nil {
+			return err
+		}
+	}
+
+	if data, err = json.Marshal(doc); err != nil {
+		return err
+	}
+
+	patched := reflect.New(rv.Elem().Type())
+	if err := json.Unmarshal(data, patched.Interface()); err != nil {
+		return errorf(ErrInvalidValue, "%v", err)
+	}
+
+	rv.Elem().Set(patched.Elem())
+
+	return nil
+}
+
+// apply applies the operation to the JSON object doc.
+func (o *PatchOperation) apply(doc map[string]any) error {
+	var value any
+	if len(o.Value) > 0 {
+		if err := json.Unmarshal(o.Value, &value); err != nil {
+			return errorf(ErrInvalidValue, "invalid value: %v", err)
+		}
+	}
+
+	if o.path == nil {
+		attrs, ok := value.(map[string]any)
+		if !ok {
+			return errorf(ErrInvalidValue, "%s operation without path requires an object value", o.Op)
+		}
+
+		for name, v := range attrs {
+			if slices.ContainsFunc(readOnlyAttrs, func(a string) bool { return strings.EqualFold(a, name) }) {
+				continue
+			}
+
+			setAttr(doc, name, v, o.Op)
+		}
+
+		return nil
+	}
+
+	p := o.path
+
+	container := doc
+	if p.schema != "" {
+		ext, key, ok := lookup(doc, p.schema)
+		m, isMap := ext.(map[string]any)
+
+		switch {
+		case p.attr == "" && o.Op == OpRemove:
+			delete(doc, key)
+			return nil
+		case p.attr == "":
+			setAttr(doc, p.schema, value, o.Op)
+			return nil
+		case !ok || !isMap:
+			if o.Op == OpRemove {
+				return nil
+			}
+
+			m = map[string]any{}
+			doc[p.schema] = m
+		}
+
+		container = m
+	}
+
+	if p.filter != nil {
+		return o.applyFiltered(container, p, value)
+	}
+
+	if p.subAttr == "" {
+		if o.Op == OpRemove {
+			removeAttr(container, p.attr, value)
+		} else {
+			setAttr(container, p.attr, value, o.Op)
+		}
+
+		return nil
+	}
+
+	current, key, ok := lookup(container, p.attr)
+
+	var targets []map[string]any
+
+	switch current := current.(type) {
+	case map[string]any:
+		targets = []map[string]any{current}
+	case []any:
+		// a sub-attribute of a multi-valued attribute applies to every value
+		for _, v := range current {
+			if m, ok := v.(map[string]any); ok {
+				targets = append(targets, m)
+			}
+		}
+	default:
+		if o.Op == OpRemove {
+			return nil
+		}
+
+		if ok {
+			return errorf(ErrInvalidPath, "attribute %q has no sub-attributes", p.attr)
+		}
+
+		m := map[string]any{}
+		container[key] = m
+		targets = []map[string]any{m}
+	}
+
+	for _, t := range targets {
+		if o.Op == OpRemove {
+			deleteAttr(t, p.subAttr)
+		} else {
+			setAttr(t, p.subAttr, value, o.Op)
+		}
+	}
+
+	return nil
+}
+
+// applyFiltered applies the operation to the values of a multi-valued
+// attribute matching the filter of p.
+func (o *PatchOperation) applyFiltered(container map[string]any, p *path, value any) error {
+	current, key, _ := lookup(container, p.attr)
+	values, _ := current.([]any)
+
+	matched := 0
+	kept := values[:0:0]
+
+	for _, v := range values {
+		m, ok := v.(map[string]any)
+		if !ok || !p.filter.match(m) {
+			kept = append(kept, v)
+			continue
+		}
+
+		matched++
+
+		switch {
+		case o.Op == OpRemove && p.subAttr == "":
+			continue
+		case o.Op == OpRemove:
+			deleteAttr(m, p.subAttr)
+		case p.subAttr != "":
+			setAttr(m, p.subAttr, value, o.Op)
+		default:
+			attrs, ok := value.(map[string]any)
+			if !ok {
+				return errorf(ErrInvalidValue, "value of %q must be an object", o.Path)
+			}
+
+			for name, v := range attrs {
+				setAttr(m, name, v, OpReplace)
+			}
+		}
+
+		kept = append(kept, m)
+	}
+
+	if matched == 0 {
+		if o.Op == OpRemove {
+			return nil
+		}
+
+		return errorf(ErrNoTarget, "no value matches %q", o.Path)
+	}
+
+	if len(kept) == 0 {
+		delete(container, key)
+	} else {
+		container[key] = kept
+	}
+
+	return nil
+}
+
+// lookup returns the value of the attribute name in m, matching the name
+// case-insensitively, and the key it is stored under. If the attribute does
+// not exist, the key is name.
+func lookup(m map[string]any, name string) (any, string, bool) {
+	if v, ok := m[name]; ok {
+		return v, name, true
+	}
+
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, k, true
+		}
+	}
+
+	return nil, name, false
+}
+
+// setAttr adds or replaces the attribute name of m with value. Adding to a
+// multi-valued attribute appends the values, and complex values are merged
+// for both operations; otherwise the value is replaced.
+func setAttr(m map[string]any, name string, value any, op string) {
+	current, key, ok := lookup(m, name)
+	if !ok || value == nil {
+		m[key] = value
+		return
+	}
+
+	switch current := current.(type) {
+	case []any:
+		if op != OpAdd {
+			break
+		}
+
+		if values, ok := value.([]any); ok {
+			m[key] = appendUnique(current, values...)
+		} else {
+			m[key] = appendUnique(current, value)
+		}
+
+		return
+	case map[string]any:
+		if sub, ok := value.(map[string]any); ok {
+			for k, v := range sub {
+				setAttr(current, k, v, op)
+			}
+
+			return
+		}
+	}
+
+	m[key] = value
+}
+
+// appendUnique appends the values that are not in s yet, as adding an
+// existing value to a multi-valued attribute must not duplicate it.
+func appendUnique(s []any, values ...any) []any {
+	for _, v := range values {
+		if !slices.ContainsFunc(s, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+			s = append(s, v)
+		}
+	}
+
+	return s
+}
+
+// removeAttr removes the attribute name of m. If value holds values of a
+// multi-valued attribute, only values with the same "value" sub-attribute are
+// removed, as sent by some identity providers instead of a filter.
+func removeAttr(m map[string]any, name string, value any) {
+	current, key, ok := lookup(m, name)
+	if !ok {
+		return
+	}
+
+	values, isSlice := current.([]any)
+	if !isSlice || value == nil {
+		delete(m, key)
+		return
+	}
+
+	remove, ok := value.([]any)
+	if !ok {
+		remove = []any{value}
+	}
+
+	kept := slices.DeleteFunc(slices.Clone(values), func(v any) bool {
+		return slices.ContainsFunc(remove, func(r any) bool { return sameValue(v, r) })
+	})
+
+	if len(kept) == 0 {
+		delete(m, key)
+	} else {
+		m[key] = kept
+	}
+}
+
+// sameValue reports whether a and b have the same "value" sub-attribute.
+func sameValue(a, b any) bool {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+
+	if !aok || !bok {
+		return reflect.DeepEqual(a, b)
+	}
+
+	av, _, aok := lookup(am, "value")
+	bv, _, bok := lookup(bm, "value")
+
+	return aok && bok && equal(av, bv)
+}
+
+// deleteAttr deletes the attribute name of m.
+func deleteAttr(m map[string]any, name string) {
+	if _, key, ok := lookup(m, name); ok {
+		delete(m, key)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package scim_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kopexa-grc/common/iam/scim"
+)
+
+func patchBody(ops ...string) []byte {
+	return fmt.Appendf(nil, `{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": [%s]}`, strings.Join(ops, ","))
+}
+
+func testUser() *scim.User {
+	active := true
+
+	return &scim.User{
+		ID:       "2819c223",
+		UserName: "bjensen",
+		Name:     &scim.Name{GivenName: "Barbara", FamilyName: "Jensen"},
+		Active:   &active,
+		Emails: []scim.MultiValue{
+			{Value: "bjensen@example.com", Type: "work", Primary: true},
+			{Value: "babs@jensen.org", Type: "home"},
+		},
+	}
+}
+
+func TestPatchRequest_Apply(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    string
+		check func(t *testing.T, u *scim.User)
+	}{
+		{
+			name: "replace simple attribute",
+			op:   `{"op": "replace", "path": "displayName", "value": "Babs"}`,
+			check: func(t *testing.T, u *scim.User) {
+				assert.Equal(t, "Babs", u.DisplayName)
+			},
+		},
+		{
+			name: "replace without path and capitalized op",
+			op:   `{"op": "Replace", "value": {"active": false, "id": "ignored", "name": {"givenName": "Babs"}}}`,
+			check: func(t *testing.T, u *scim.User) {
+				assert.False(t, u.IsActive())
+				assert.Equal(t, "2819c223", u.ID, "read-only attributes must be ignored")
+				assert.Equal(t, "Babs", u.Name.GivenName)
+				assert.Equal(t, "Jensen", u.Name.FamilyName, "unspecified sub-attributes must be kept")
+			},
+		},
+		{
+			name: "replace sub-attribute",
+			op:   `{"op": "replace", "path": "name.familyName", "value": "Smith"}`,
+			check: func(t *testing.T, u *scim.User) {
+				assert.Equal(t, "Smith", u.Name.FamilyName)
+				assert.Equal(t, "Barbara", u.Name.GivenName)
+			},
+		},
+		{
+			name: "replace with schema URN",
+			op:   `{"op": "replace", "path": "urn:ietf:params:scim:schemas:core:2.0:User:userName", "value": "babs"}`,
+			check: func(t *testing.T, u *scim.User) {
+				assert.Equal(t, "babs", u.UserName)
+			},
+		},
+		{
+			name: "add to multi-valued attribute",
+			op:   `{"op": "add", "path": "emails", "value": [{"value": "babs@example.org", "type": "other"}]}`,
+			check: func(t *testing.T, u *scim.User) {
+				require.Len(t, u.Emails, 3)
+				assert.Equal(t, "babs@example.org", u.Emails[2].Value)
+			},
+		},
+		{
+			name: "add existing value is not duplicated",
+			op:   `{"op": "add", "path": "emails", "value": {"value": "babs@jensen.org", "type": "home"}}`,
+			check: func(t *testing.T, u *scim.User) {
+				assert.Len(t, u.Emails, 2)
+			},
+		},
+		{
+			name: "replace value selected by filter",
+			op:   `{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "barbara@example.com"}`,
+			check: func(t *testing.T, u *scim.User) {
+				assert.Equal(t, "barbara@example.com", u.Emails[0].Value)
+				assert.True(t, u.Emails[0].Primary)
+				assert.Equal(t, "babs@jensen.org", u.Emails[1].Value)
+			},
+		},
+		{
+			name: "remove values selected by filter",
+			op:   `{"op": "remove", "path": "emails[type eq \"HOME\" or value co \"nothing\"]"}`,
+			check: func(t *testing.T, u *scim.User) {
+				require.Len(t, u.Emails, 1)
+				assert.Equal(t, "work", u.Emails[0].Type)
+			},
+		},
+		{
+			name: "remove attribute",
+			op:   `{"op": "remove", "path": "name"}`,
+			check: func(t *testing.T, u *scim.User) {
+				assert.Nil(t, u.Name)
+			},
+		},
+		{
+			name: "add enterprise extension attribute",
+			op:   `{"op": "add", "path": "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", "value": "Security"}`,
+			check: func(t *testing.T, u *scim.User) {
+				require.NotNil(t, u.Enterprise)
+				assert.Equal(t, "Security", u.Enterprise.Department)
+			},
+		},
+		{
+			name: "set password",
+			op:   `{"op": "replace", "path": "password", "value": "n3wSecret!"}`,
+			check: func(t *testing.T, u *scim.User) {
+				assert.Equal(t, "n3wSecret!", u.Password)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := scim.ParsePatch(patchBody(tt.op))
+			require.NoError(t, err)
+
+			u := testUser()
+			require.NoError(t, req.Apply(u))
+			tt.check(t, u)
+		})
+	}
+}
+
+func TestPatchRequest_ApplyGroupMembers(t *testing.T) {
+	g := &scim.Group{DisplayName: "Auditors", Members: []scim.Reference{{Value: "u1"}, {Value: "u2"}, {Value: "u3"}}}
+
+	req, err := scim.ParsePatch(patchBody(
+		`{"op": "add", "path": "members", "value": [{"value": "u4"}]}`,
+		`{"op": "remove", "path": "members[value eq \"u1\"]"}`,
+		`{"op": "remove", "path": "members", "value": [{"value": "u3"}]}`,
+	))
+	require.NoError(t, err)
+	require.NoError(t, req.Apply(g))
+
+	assert.Equal(t, []scim.Reference{{Value: "u2"}, {Value: "u4"}}, g.Members)
+	assert.Equal(t, "Auditors", g.DisplayName)
+
+	req, err = scim.ParsePatch(patchBody(`{"op": "remove", "path": "members"}`))
+	require.NoError(t, err)
+	require.NoError(t, req.Apply(g))
+	assert.Empty(t, g.Members)
+}
+
+func TestPatchRequest_Filters(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{`type eq "work"`, []string{"a@example.com"}},
+		{`type ne "work"`, []string{"b@example.org", "c@example.net"}},
+		{`value sw "B@"`, []string{"b@example.org"}},
+		{`value ew ".net"`, []string{"c@example.net"}},
+		{`primary eq true`, []string{"a@example.com"}},
+		{`display pr`, []string{"c@example.net"}},
+		{`not (type eq "work") and value co "example.org"`, []string{"b@example.org"}},
+		{`(type eq "home" or type eq "work") and not (primary eq true)`, []string{"b@example.org"}},
+		{`value gt "b"`, []string{"b@example.org", "c@example.net"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			u := &scim.User{UserName: "x", Emails: []scim.MultiValue{
+				{Value: "a@example.com", Type: "work", Primary: true},
+				{Value: "b@example.org", Type: "home"},
+				{Value: "c@example.net", Type: "other", Display: "C"},
+			}}
+
+			path := fmt.Sprintf("emails[%s].type", tt.filter)
+			op := fmt.Sprintf(`{"op": "replace", "path": %q, "value": "matched"}`, path)
+
+			req, err := scim.ParsePatch(patchBody(op))
+			require.NoError(t, err)
+			require.NoError(t, req.Apply(u))
+
+			var got []string
+			for _, e := range u.Emails {
+				if e.Type == "matched" {
+					got = append(got, e.Value)
+				}
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePatch_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+		want error
+	}{
+		{"invalid JSON", []byte(`{`), scim.ErrInvalidSyntax},
+		{"missing schema", []byte(`{"Operations": [{"op": "remove", "path": "name"}]}`), scim.ErrInvalidSyntax},
+		{"no operations", patchBody(), scim.ErrInvalidSyntax},
+		{"unknown op", patchBody(`{"op": "move", "path": "name"}`), scim.ErrInvalidSyntax},
+		{"remove without path", patchBody(`{"op": "remove"}`), scim.ErrNoTarget},
+		{"add without value", patchBody(`{"op": "add", "path": "name"}`), scim.ErrInvalidValue},
+		{"invalid attribute", patchBody(`{"op": "remove", "path": "1name"}`), scim.ErrInvalidPath},
+		{"unterminated filter", patchBody(`{"op": "remove", "path": "emails[type eq \"work\""}`), scim.ErrInvalidPath},
+		{"invalid sub-attribute", patchBody(`{"op": "remove", "path": "emails[type eq \"work\"]value"}`), scim.ErrInvalidPath},
+		{"unknown operator", patchBody(`{"op": "remove", "path": "emails[type is \"work\"]"}`), scim.ErrInvalidFilter},
+		{"missing value", patchBody(`{"op": "remove", "path": "emails[type eq]"}`), scim.ErrInvalidFilter},
+		{"unbalanced parentheses", patchBody(`{"op": "remove", "path": "emails[(type eq \"work\"]"}`), scim.ErrInvalidFilter},
+		{"read-only attribute", patchBody(`{"op": "replace", "path": "id", "value": "x"}`), scim.ErrMutability},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := scim.ParsePatch(tt.body)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestPatchRequest_ApplyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		op   string
+		want error
+	}{
+		{"filter without match", `{"op": "replace", "path": "emails[type eq \"fax\"].value", "value": "x"}`, scim.ErrNoTarget},
+		{"value of wrong type", `{"op": "replace", "path": "active", "value": "yes"}`, scim.ErrInvalidValue},
+		{"no path and no object", `{"op": "add", "value": "x"}`, scim.ErrInvalidValue},
+		{"sub-attribute of simple attribute", `{"op": "replace", "path": "userName.first", "value": "x"}`, scim.ErrInvalidPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := scim.ParsePatch(patchBody(tt.op))
+			require.NoError(t, err)
+
+			u := testUser()
+			err = req.Apply(u)
+			assert.ErrorIs(t, err, tt.want)
+			assert.Equal(t, testUser(), u, "resource must not be modified on error")
+		})
+	}
+
+	req, err := scim.ParsePatch(patchBody(`{"op": "remove", "path": "name"}`))
+	require.NoError(t, err)
+	assert.ErrorIs(t, req.Apply(scim.User{}), scim.ErrInvalidValue)
+}
@@ -0,0 +1,536 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// path is a parsed PATCH path of RFC 7644, section 3.5.2:
+//
+//	PATH = attrPath / valuePath [subAttr]
+type path struct {
+	// schema is the URN of an extension schema, empty for core attributes.
+	schema string
+	// attr is the attribute name, empty if the path is the extension itself.
+	attr string
+	// filter selects values of a multi-valued attribute, or nil.
+	filter filter
+	// subAttr is the sub-attribute of attr, or empty.
+	subAttr string
+}
+
+// coreSchemas are the schemas whose attributes are top-level attributes.
+var coreSchemas = []string{SchemaUser, SchemaGroup}
+
+// parsePath parses a PATCH path.
+func parsePath(s string) (*path, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errorf(ErrInvalidPath, "path is empty")
+	}
+
+	p := &path{}
+
+	if strings.HasPrefix(strings.ToLower(s), "urn:") {
+		head := s
+		if i := strings.IndexByte(s, '['); i >= 0 {
+			head = s[:i]
+		}
+
+		if strings.EqualFold(head, SchemaEnterpriseUser) {
+			p.schema = SchemaEnterpriseUser
+			return p, nil
+		}
+
+		i := strings.LastIndexByte(head, ':')
+		p.schema, s = s[:i], s[i+1:]
+
+		for _, core := range coreSchemas {
+			if strings.EqualFold(p.schema, core) {
+				p.schema = ""
+			}
+		}
+	}
+
+	end := strings.IndexAny(s, "[.")
+	if end < 0 {
+		end = len(s)
+	}
+
+	p.attr, s = s[:end], s[end:]
+	if !validAttrName(p.attr) {
+		return nil, errorf(ErrInvalidPath, "invalid attribute name %q", p.attr)
+	}
+
+	if strings.HasPrefix(s, "[") {
+		closing := closingBracket(s)
+		if closing < 0 {
+			return nil, errorf(ErrInvalidPath, "unterminated filter in path")
+		}
+
+		f, err := parseFilter(s[1:closing])
+		if err != nil {
+			return nil, err
+		}
+
+		p.filter, s = f, s[closing+1:]
+	}
+
+	if s != "" {
+		sub, ok := strings.CutPrefix(s, ".")
+		if !ok || !validAttrName(sub) {
+			return nil, errorf(ErrInvalidPath, "invalid sub-attribute %q", s)
+		}
+
+		p.subAttr = sub
+	}
+
+	return p, nil
+}
+
+// closingBracket returns the index of the "]" closing the "[" at s[0],
+// ignoring brackets in string literals, or -1.
+func closingBracket(s string) int {
+	inString := false
+
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case !inString && c == ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// validAttrName reports whether s is an ATTRNAME of RFC 7644, or "$ref".
+func validAttrName(s string) bool {
+	if s == "$ref" {
+		return true
+	}
+
+	if s == "" || !isAlpha(s[0]) {
+		return false
+	}
+
+	for i := 1; i < len(s); i++ {
+		if c := s[i]; !isAlpha(c) && !isDigit(c) && c != '-' && c != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAlpha(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// filter matches values of a multi-valued attribute.
+type filter interface {
+	match(value map[string]any) bool
+}
+
+type logicalFilter struct {
+	and         bool
+	left, right filter
+}
+
+func (f *logicalFilter) match(v map[string]any) bool {
+	if f.and {
+		return f.left.match(v) && f.right.match(v)
+	}
+
+	return f.left.match(v) || f.right.match(v)
+}
+
+type notFilter struct {
+	f filter
+}
+
+func (f *notFilter) match(v map[string]any) bool {
+	return !f.f.match(v)
+}
+
+type compareFilter struct {
+	attr  []string
+	op    string
+	value any
+}
+
+func (f *compareFilter) match(v map[string]any) bool {
+	actual, ok := lookupPath(v, f.attr)
+
+	if f.op == "pr" {
+		return ok && present(actual)
+	}
+
+	if values, isSlice := actual.([]any); isSlice {
+		for _, a := range values {
+			if compare(a, f.op, f.value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if !ok {
+		return f.op == "ne" && f.value != nil || f.op == "eq" && f.value == nil
+	}
+
+	return compare(actual, f.op, f.value)
+}
+
+// lookupPath returns the value of a dotted attribute path in v.
+func lookupPath(v map[string]any, attrs []string) (any, bool) {
+	var cur any = v
+
+	for _, attr := range attrs {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if cur, _, ok = lookup(m, attr); !ok {
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+// present reports whether v has a value, RFC 7644 operator "pr".
+func present(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+// compare applies op to actual and expected. Strings are compared
+// case-insensitively.
+func compare(actual any, op string, expected any) bool {
+	switch op {
+	case "eq":
+		return equal(actual, expected)
+	case "ne":
+		return !equal(actual, expected)
+	}
+
+	if a, ok := actual.(string); ok {
+		e, ok := expected.(string)
+		if !ok {
+			return false
+		}
+
+		a, e = strings.ToLower(a), strings.ToLower(e)
+
+		switch op {
+		case "co":
+			return strings.Contains(a, e)
+		case "sw":
+			return strings.HasPrefix(a, e)
+		case "ew":
+			return strings.HasSuffix(a, e)
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+
+		return false
+	}
+
+	a, aok := actual.(float64)
+	e, eok := expected.(float64)
+
+	if !aok || !eok {
+		return false
+	}
+
+	switch op {
+	case "gt":
+		return a > e
+	case "ge":
+		return a >= e
+	case "lt":
+		return a < e
+	case "le":
+		return a <= e
+	}
+
+	return false
+}
+
+func equal(actual, expected any) bool {
+	if a, ok := actual.(string); ok {
+		e, ok := expected.(string)
+		return ok && strings.EqualFold(a, e)
+	}
+
+	return actual == expected
+}
+
+// compareOps are the comparison operators of RFC 7644, section 3.4.2.2.
+var compareOps = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "lt": true, "ge": true, "le": true,
+}
+
+// parseFilter parses a filter:
+//
+//	FILTER    = attrExp / logExp / valuePath / *1"not" "(" FILTER ")"
+//
+// Nested value paths are not supported, as filters of PATCH paths apply to
+// the values of one multi-valued attribute.
+func parseFilter(s string) (filter, error) {
+	tokens, err := tokenizeFilter(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, errorf(ErrInvalidFilter, "unexpected %q in filter", p.tokens[p.pos].text)
+	}
+
+	return f, nil
+}
+
+type filterToken struct {
+	text string
+	// literal is the decoded value of string, number and keyword literals.
+	literal any
+	// isLiteral is true for string, number, true, false and null.
+	isLiteral bool
+}
+
+// tokenizeFilter splits a filter into words, parentheses and literals.
+func tokenizeFilter(s string) ([]filterToken, error) {
+	var tokens []filterToken
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, filterToken{text: string(c)})
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(s) && s[end] != '"'; end++ {
+				if s[end] == '\\' {
+					end++
+				}
+			}
+
+			if end >= len(s) {
+				return nil, errorf(ErrInvalidFilter, "unterminated string in filter")
+			}
+
+			var str string
+			if err := json.Unmarshal([]byte(s[i:end+1]), &str); err != nil {
+				return nil, errorf(ErrInvalidFilter, "invalid string %s in filter", s[i:end+1])
+			}
+
+			tokens = append(tokens, filterToken{text: s[i : end+1], literal: str, isLiteral: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(s) && !strings.ContainsRune(" \t()\"", rune(s[end])) {
+				end++
+			}
+
+			word := s[i:end]
+			tokens = append(tokens, wordToken(word))
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
+
+// wordToken classifies a word as keyword literal, number or name.
+func wordToken(word string) filterToken {
+	switch strings.ToLower(word) {
+	case "true":
+		return filterToken{text: word, literal: true, isLiteral: true}
+	case "false":
+		return filterToken{text: word, literal: false, isLiteral: true}
+	case "null":
+		return filterToken{text: word, isLiteral: true}
+	}
+
+	if n, err := strconv.ParseFloat(word, 64); err == nil && (isDigit(word[0]) || word[0] == '-') {
+		return filterToken{text: word, literal: n, isLiteral: true}
+	}
+
+	return filterToken{text: word}
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+// keyword consumes the next token if it is the keyword kw.
+func (p *filterParser) keyword(kw string) bool {
+	t, ok := p.peek()
+	if !ok || t.isLiteral || !strings.EqualFold(t.text, kw) {
+		return false
+	}
+
+	p.pos++
+
+	return true
+}
+
+func (p *filterParser) parseOr() (filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalFilter{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalFilter{and: true, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filter, error) {
+	if p.keyword("not") {
+		if !p.keyword("(") {
+			return nil, errorf(ErrInvalidFilter, `expected "(" after not`)
+		}
+
+		f, err := p.parseGroup()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notFilter{f: f}, nil
+	}
+
+	if p.keyword("(") {
+		return p.parseGroup()
+	}
+
+	return p.parseAttrExp()
+}
+
+// parseGroup parses a filter followed by ")".
+func (p *filterParser) parseGroup() (filter, error) {
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.keyword(")") {
+		return nil, errorf(ErrInvalidFilter, `expected ")" in filter`)
+	}
+
+	return f, nil
+}
+
+// parseAttrExp parses attrPath "pr" / attrPath compareOp compValue.
+func (p *filterParser) parseAttrExp() (filter, error) {
+	t, ok := p.peek()
+	if !ok || t.isLiteral {
+		return nil, errorf(ErrInvalidFilter, "expected attribute in filter")
+	}
+
+	p.pos++
+
+	attrs := strings.Split(t.text, ".")
+	for _, a := range attrs {
+		if !validAttrName(a) {
+			return nil, errorf(ErrInvalidFilter, "invalid attribute %q in filter", t.text)
+		}
+	}
+
+	opToken, ok := p.peek()
+	if !ok || opToken.isLiteral {
+		return nil, errorf(ErrInvalidFilter, "expected operator after %q", t.text)
+	}
+
+	p.pos++
+
+	op := strings.ToLower(opToken.text)
+	if op == "pr" {
+		return &compareFilter{attr: attrs, op: op}, nil
+	}
+
+	if !compareOps[op] {
+		return nil, errorf(ErrInvalidFilter, "unknown operator %q", opToken.text)
+	}
+
+	value, ok := p.peek()
+	if !ok || !value.isLiteral {
+		return nil, errorf(ErrInvalidFilter, "expected value after %q", opToken.text)
+	}
+
+	p.pos++
+
+	return &compareFilter{attr: attrs, op: op, value: value.literal}, nil
+}
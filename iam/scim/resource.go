@@ -0,0 +1,224 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package scim
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// ContentType is the media type of SCIM requests and responses.
+const ContentType = "application/scim+json"
+
+// Schema URIs of RFC 7643 and RFC 7644.
+const (
+	SchemaUser           = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup          = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaEnterpriseUser = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	SchemaPatchOp        = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaListResponse   = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaError          = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// Resource types reported in Meta.ResourceType.
+const (
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+)
+
+// Meta holds the resource metadata of RFC 7643, section 3.1.
+type Meta struct {
+	ResourceType string     `json:"resourceType,omitempty"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+	Version      string     `json:"version,omitempty"`
+}
+
+// Name is the name of a user.
+type Name struct {
+	Formatted       string `json:"formatted,omitempty"`
+	FamilyName      string `json:"familyName,omitempty"`
+	GivenName       string `json:"givenName,omitempty"`
+	MiddleName      string `json:"middleName,omitempty"`
+	HonorificPrefix string `json:"honorificPrefix,omitempty"`
+	HonorificSuffix string `json:"honorificSuffix,omitempty"`
+}
+
+// MultiValue is a value of a multi-valued attribute such as emails, phone
+// numbers or roles.
+type MultiValue struct {
+	Value   string `json:"value,omitempty"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Address is a physical mailing address of a user.
+type Address struct {
+	Formatted     string `json:"formatted,omitempty"`
+	StreetAddress string `json:"streetAddress,omitempty"`
+	Locality      string `json:"locality,omitempty"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postalCode,omitempty"`
+	Country       string `json:"country,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Primary       bool   `json:"primary,omitempty"`
+}
+
+// Reference points to another resource, e.g. a group of a user or a member
+// of a group.
+type Reference struct {
+	Value   string `json:"value,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+// Manager is the manager of a user in the enterprise extension.
+type Manager struct {
+	Value       string `json:"value,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// EnterpriseUser holds the attributes of the enterprise user extension of
+// RFC 7643, section 4.3.
+type EnterpriseUser struct {
+	EmployeeNumber string   `json:"employeeNumber,omitempty"`
+	CostCenter     string   `json:"costCenter,omitempty"`
+	Organization   string   `json:"organization,omitempty"`
+	Division       string   `json:"division,omitempty"`
+	Department     string   `json:"department,omitempty"`
+	Manager        *Manager `json:"manager,omitempty"`
+}
+
+// User is a user resource of RFC 7643, section 4.1.
+type User struct {
+	Schemas           []string        `json:"schemas,omitempty"`
+	ID                string          `json:"id,omitempty"`
+	ExternalID        string          `json:"externalId,omitempty"`
+	UserName          string          `json:"userName"`
+	Name              *Name           `json:"name,omitempty"`
+	DisplayName       string          `json:"displayName,omitempty"`
+	NickName          string          `json:"nickName,omitempty"`
+	ProfileURL        string          `json:"profileUrl,omitempty"`
+	Title             string          `json:"title,omitempty"`
+	UserType          string          `json:"userType,omitempty"`
+	PreferredLanguage string          `json:"preferredLanguage,omitempty"`
+	Locale            string          `json:"locale,omitempty"`
+	Timezone          string          `json:"timezone,omitempty"`
+	Active            *bool           `json:"active,omitempty"`
+	Password          string          `json:"password,omitempty"`
+	Emails            []MultiValue    `json:"emails,omitempty"`
+	PhoneNumbers      []MultiValue    `json:"phoneNumbers,omitempty"`
+	Addresses         []Address       `json:"addresses,omitempty"`
+	Groups            []Reference     `json:"groups,omitempty"`
+	Roles             []MultiValue    `json:"roles,omitempty"`
+	Entitlements      []MultiValue    `json:"entitlements,omitempty"`
+	Enterprise        *EnterpriseUser `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+	Meta              *Meta           `json:"meta,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Empty schemas are filled in, and
+// the password is omitted, as it is never returned.
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+
+	if len(u.Schemas) == 0 {
+		u.Schemas = []string{SchemaUser}
+		if u.Enterprise != nil {
+			u.Schemas = append(u.Schemas, SchemaEnterpriseUser)
+		}
+	}
+
+	u.Password = ""
+
+	return json.Marshal(user(u))
+}
+
+// IsActive reports whether the user is active. Users without the active
+// attribute are active.
+func (u *User) IsActive() bool {
+	return u.Active == nil || *u.Active
+}
+
+// PrimaryEmail returns the primary email address, or the first one if none is
+// marked primary.
+func (u *User) PrimaryEmail() string {
+	if i := slices.IndexFunc(u.Emails, func(e MultiValue) bool { return e.Primary }); i >= 0 {
+		return u.Emails[i].Value
+	}
+
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+
+	return ""
+}
+
+// Validate checks the required attributes of the user.
+func (u *User) Validate() error {
+	if u.UserName == "" {
+		return errorf(ErrInvalidValue, "userName is required")
+	}
+
+	return nil
+}
+
+// Group is a group resource of RFC 7643, section 4.2.
+type Group struct {
+	Schemas     []string    `json:"schemas,omitempty"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []Reference `json:"members,omitempty"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Empty schemas are filled in.
+func (g Group) MarshalJSON() ([]byte, error) {
+	type group Group
+
+	if len(g.Schemas) == 0 {
+		g.Schemas = []string{SchemaGroup}
+	}
+
+	return json.Marshal(group(g))
+}
+
+// Validate checks the required attributes of the group.
+func (g *Group) Validate() error {
+	if g.DisplayName == "" {
+		return errorf(ErrInvalidValue, "displayName is required")
+	}
+
+	return nil
+}
+
+// ListResponse is the response of a query, RFC 7644, section 3.4.2.
+type ListResponse[T any] struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex,omitempty"`
+	ItemsPerPage int      `json:"itemsPerPage,omitempty"`
+	Resources    []T      `json:"Resources"`
+}
+
+// NewListResponse returns the page of resources starting at the 1-based
+// startIndex out of totalResults matching resources.
+func NewListResponse[T any](resources []T, totalResults, startIndex int) *ListResponse[T] {
+	if resources == nil {
+		resources = []T{}
+	}
+
+	return &ListResponse[T]{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: totalResults,
+		StartIndex:   max(startIndex, 1),
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
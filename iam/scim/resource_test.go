@@ -0,0 +1,112 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package scim_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kopexa-grc/common/iam/scim"
+)
+
+func TestUser_JSON(t *testing.T) {
+	// RFC 7643, section 8.2, with attribute names in mixed case
+	input := `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User", "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"],
+		"id": "2819c223-7f76-453a-919d-413861904646",
+		"externalId": "701984",
+		"UserName": "bjensen@example.com",
+		"name": {"familyName": "Jensen", "givenName": "Barbara"},
+		"active": false,
+		"password": "t1meMa$heen",
+		"emails": [
+			{"value": "bjensen@example.com", "type": "work"},
+			{"value": "babs@jensen.org", "type": "home", "primary": true}
+		],
+		"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User": {
+			"employeeNumber": "701984",
+			"manager": {"value": "26118915-6090-4610-87e4-49d8ca9f808d", "displayName": "John Smith"}
+		},
+		"meta": {"resourceType": "User", "created": "2010-01-23T04:56:22Z", "version": "W/\"3694e05e9dff591\""}
+	}`
+
+	var u scim.User
+	require.NoError(t, json.Unmarshal([]byte(input), &u))
+
+	assert.Equal(t, "bjensen@example.com", u.UserName)
+	assert.Equal(t, "Barbara", u.Name.GivenName)
+	assert.False(t, u.IsActive())
+	assert.Equal(t, "t1meMa$heen", u.Password)
+	assert.Equal(t, "babs@jensen.org", u.PrimaryEmail())
+	assert.Equal(t, "John Smith", u.Enterprise.Manager.DisplayName)
+	assert.Equal(t, scim.ResourceTypeUser, u.Meta.ResourceType)
+	require.NoError(t, u.Validate())
+
+	data, err := json.Marshal(u)
+	require.NoError(t, err)
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.NotContains(t, out, "password", "password must never be returned")
+	assert.Equal(t, "bjensen@example.com", out["userName"])
+	assert.Contains(t, out, scim.SchemaEnterpriseUser)
+
+	data, err = json.Marshal(scim.User{UserName: "alice", Enterprise: &scim.EnterpriseUser{Department: "IT"}})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, []any{scim.SchemaUser, scim.SchemaEnterpriseUser}, out["schemas"])
+
+	assert.ErrorIs(t, (&scim.User{}).Validate(), scim.ErrInvalidValue)
+}
+
+func TestGroup_JSON(t *testing.T) {
+	data, err := json.Marshal(scim.Group{
+		DisplayName: "Tour Guides",
+		Members:     []scim.Reference{{Value: "2819c223", Ref: "https://example.com/v2/Users/2819c223", Display: "Babs Jensen"}},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group"],
+		"displayName": "Tour Guides",
+		"members": [{"value": "2819c223", "$ref": "https://example.com/v2/Users/2819c223", "display": "Babs Jensen"}]
+	}`, string(data))
+
+	assert.ErrorIs(t, (&scim.Group{}).Validate(), scim.ErrInvalidValue)
+}
+
+func TestNewListResponse(t *testing.T) {
+	data, err := json.Marshal(scim.NewListResponse[scim.Group](nil, 0, 0))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:ListResponse"],
+		"totalResults": 0,
+		"startIndex": 1,
+		"Resources": []
+	}`, string(data))
+}
+
+func TestWriteError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	scim.WriteError(rec, &scim.Error{Status: http.StatusBadRequest, ScimType: scim.ScimTypeInvalidPath, Detail: "bad path"})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, scim.ContentType, rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:Error"],
+		"status": "400",
+		"scimType": "invalidPath",
+		"detail": "bad path"
+	}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	scim.WriteError(rec, errors.New("database down"))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "database")
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"hash/fnv"
+	"math"
+	"sync/atomic"
+)
+
+// bloomFilter is a fixed-size bloom filter that is safe for concurrent use.
+// Adding never blocks lookups; removing is not supported.
+type bloomFilter struct {
+	bits   []atomic.Uint64
+	size   uint64
+	hashes uint64
+}
+
+// newBloomFilter sizes a filter for n entries at false positive rate p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(n)*math.Ln2))
+	size := uint64(m)
+
+	return &bloomFilter{
+		bits:   make([]atomic.Uint64, (size+63)/64),
+		size:   size,
+		hashes: uint64(k),
+	}
+}
+
+// locations derives the bit positions of key by double hashing.
+func (b *bloomFilter) locations(key string, fn func(idx uint64) bool) bool {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	h1 := h.Sum64()
+	h2 := h1>>33 | h1<<31 | 1
+
+	for i := range b.hashes {
+		if !fn((h1 + i*h2) % b.size) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (b *bloomFilter) add(key string) {
+	b.locations(key, func(idx uint64) bool {
+		b.bits[idx/64].Or(1 << (idx % 64))
+		return true
+	})
+}
+
+func (b *bloomFilter) mayContain(key string) bool {
+	return b.locations(key, func(idx uint64) bool {
+		return b.bits[idx/64].Load()&(1<<(idx%64)) != 0
+	})
+}
@@ -13,6 +13,19 @@ type Config[T any] struct {
 	Store Store[T]
 	// CookieConfig contains the cookie settings for sessions
 	CookieConfig *CookieConfig
+	// OnUpgrade is called after Upgrade turned an anonymous session into an authenticated one
+	OnUpgrade UpgradeHook[T]
+	// Metrics receives measurements of session operations
+	Metrics Metrics
+	// Revocation is consulted on Load to reject revoked sessions
+	Revocation RevocationChecker
+	// UserID extracts the user ID from a session for revocation checks
+	UserID UserIDFunc[T]
+	// SizeGuard configures the payload size guard; nil disables it
+	SizeGuard []SizeGuardOption
+	// Privilege fingerprints the privileges of a session; Middleware rotates
+	// the session ID when it changes
+	Privilege PrivilegeFunc[T]
 }
 
 // CookieConfig contains the cookie settings for sessions
@@ -29,6 +42,11 @@ type CookieConfig struct {
 	HTTPOnly bool
 	// SameSite determines the SameSite attribute of the cookie
 	SameSite http.SameSite
+	// Partitioned stores the cookie per top-level site (CHIPS), for
+	// applications embedded in iframes
+	Partitioned bool
+	// Development relaxes the validation for local development over HTTP
+	Development bool
 }
 
 // Option allows users to optionally supply configuration to the session middleware
@@ -44,9 +62,37 @@ func NewConfig[T any](store Store[T], opts ...Option[T]) Config[T] {
 		opt(&c)
 	}
 
+	if c.SizeGuard != nil {
+		opts := c.SizeGuard
+		if c.Metrics != nil {
+			opts = append([]SizeGuardOption{WithSizeMetrics(c.Metrics)}, opts...)
+		}
+
+		c.Store = SizeGuard(c.Store, opts...)
+	}
+
+	if c.Revocation != nil {
+		c.Store = RevocationStore(c.Store, c.Revocation, c.UserID)
+	}
+
+	if c.Metrics != nil {
+		c.Store = InstrumentStore(c.Store, c.Metrics)
+		setCodecMetrics(c.Metrics)
+	}
+
 	return c
 }
 
+// NewSession creates a new session in the configured store and reports it to
+// the configured metrics.
+func (c Config[T]) NewSession(name string) *Session[T] {
+	if c.Metrics != nil {
+		c.Metrics.SessionCreated()
+	}
+
+	return NewSession(c.Store, name)
+}
+
 // WithCookieConfig allows the user to specify cookie settings
 func WithCookieConfig[T any](config *CookieConfig) Option[T] {
 	return func(c *Config[T]) {
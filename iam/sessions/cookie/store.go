@@ -43,6 +43,10 @@ type Config struct {
 	// SameSite determines the SameSite attribute of the cookie
 	SameSite string
 
+	// Partitioned stores the cookie per top-level site, for applications
+	// embedded in iframes
+	Partitioned bool
+
 	// DevMode enables development mode with relaxed security settings
 	// WARNING: Never use in production!
 	DevMode bool
@@ -104,6 +108,21 @@ func WithSameSite(sameSite string) Option {
 	}
 }
 
+// WithPolicy applies the SameSite, Secure, HttpOnly, Partitioned and Domain
+// settings of a cookie policy preset such as sessions.StrictFirstParty,
+// sessions.EmbeddedIframe or sessions.LocalDev. Development presets enable
+// the development mode.
+func WithPolicy(policy *sessions.CookieConfig) Option {
+	return func(c *Config) {
+		WithDomain(policy.Domain)(c)
+		c.Secure = policy.Secure
+		c.HTTPOnly = policy.HTTPOnly
+		c.SameSite = sameSiteName(policy.SameSite)
+		c.Partitioned = policy.Partitioned
+		c.DevMode = policy.Development
+	}
+}
+
 // WithDevMode enables development mode with relaxed security settings
 // WARNING: Never use in production!
 func WithDevMode(devMode bool) Option {
@@ -130,21 +149,16 @@ func (c *Config) Validate() error {
 		return sessions.ErrInvalidSameSite
 	}
 
-	if !c.DevMode {
-		if !c.Secure {
-			return sessions.ErrSecureRequired
-		}
-
-		if !c.HTTPOnly {
-			return sessions.ErrHTTPOnlyRequired
-		}
-
-		if c.SameSite == sessions.CookieSameSiteNone && !c.Secure {
-			return sessions.ErrSameSiteNoneRequiresSecure
-		}
+	policy := sessions.CookieConfig{
+		Domain:      c.Domain,
+		Secure:      c.Secure,
+		HTTPOnly:    c.HTTPOnly,
+		SameSite:    getSameSite(c.SameSite),
+		Partitioned: c.Partitioned,
+		Development: c.DevMode,
 	}
 
-	return nil
+	return policy.Validate()
 }
 
 // NewStore creates a new cookie store with the given options and validates the config
@@ -180,14 +194,15 @@ func (s *Store[T]) Save(w http.ResponseWriter, session *sessions.Session[T]) err
 	}
 
 	cookie := &http.Cookie{
-		Name:     session.Name,
-		Value:    encoded,
-		Path:     sessions.CookiePath,
-		Domain:   s.config.Domain,
-		MaxAge:   s.config.MaxAge,
-		Secure:   s.config.Secure,
-		HttpOnly: s.config.HTTPOnly,
-		SameSite: getSameSite(s.config.SameSite),
+		Name:        session.Name,
+		Value:       encoded,
+		Path:        sessions.CookiePath,
+		Domain:      s.config.Domain,
+		MaxAge:      s.config.MaxAge,
+		Secure:      s.config.Secure,
+		HttpOnly:    s.config.HTTPOnly,
+		SameSite:    getSameSite(s.config.SameSite),
+		Partitioned: s.config.Partitioned,
 	}
 
 	http.SetCookie(w, cookie)
@@ -221,15 +236,16 @@ func (s *Store[T]) Load(r *http.Request, name string) (*sessions.Session[T], err
 // Destroy removes the session by setting an expired cookie
 func (s *Store[T]) Destroy(w http.ResponseWriter, _ *http.Request, name string) {
 	cookie := &http.Cookie{
-		Name:     name,
-		Value:    "",
-		Path:     sessions.CookiePath,
-		Domain:   s.config.Domain,
-		MaxAge:   -1,
-		Expires:  time.Unix(0, 0),
-		Secure:   s.config.Secure,
-		HttpOnly: s.config.HTTPOnly,
-		SameSite: getSameSite(s.config.SameSite),
+		Name:        name,
+		Value:       "",
+		Path:        sessions.CookiePath,
+		Domain:      s.config.Domain,
+		MaxAge:      -1,
+		Expires:     time.Unix(0, 0),
+		Secure:      s.config.Secure,
+		HttpOnly:    s.config.HTTPOnly,
+		SameSite:    getSameSite(s.config.SameSite),
+		Partitioned: s.config.Partitioned,
 	}
 
 	http.SetCookie(w, cookie)
@@ -246,3 +262,15 @@ func getSameSite(sameSite string) http.SameSite {
 		return http.SameSiteLaxMode
 	}
 }
+
+// sameSiteName converts http.SameSite to the SameSite string of Config
+func sameSiteName(sameSite http.SameSite) string {
+	switch sameSite {
+	case http.SameSiteStrictMode:
+		return sessions.CookieSameSiteStrict
+	case http.SameSiteNoneMode:
+		return sessions.CookieSameSiteNone
+	default:
+		return sessions.CookieSameSiteLax
+	}
+}
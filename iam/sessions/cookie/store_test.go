@@ -297,3 +297,63 @@ func TestStore_DevModeValidation(t *testing.T) {
 	)
 	assert.Error(t, err, "Produktionsmodus sollte unsichere Configuration ablehnen")
 }
+
+const testKey = "0123456789abcdef0123456789abcdef"
+
+func TestWithPolicy(t *testing.T) {
+	store, err := NewStore[string](
+		WithSigningKey(testKey),
+		WithEncryptionKey(testKey),
+		WithPolicy(sessions.EmbeddedIframe("session", "kopexa.com")),
+	)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, store.Save(rec, sessions.NewSession[string](store, "session")))
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, http.SameSiteNoneMode, cookies[0].SameSite)
+	assert.True(t, cookies[0].Secure)
+	assert.True(t, cookies[0].Partitioned)
+	assert.Equal(t, "kopexa.com", cookies[0].Domain)
+	assert.Contains(t, rec.Header().Get("Set-Cookie"), "Partitioned")
+
+	_, err = NewStore[string](
+		WithSigningKey(testKey),
+		WithEncryptionKey(testKey),
+		WithPolicy(sessions.LocalDev("session")),
+	)
+	require.NoError(t, err)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	_, err := NewStore[string](
+		WithSigningKey(testKey),
+		WithEncryptionKey(testKey),
+		WithSecure(false),
+	)
+	assert.ErrorIs(t, err, sessions.ErrSecureRequired)
+}
+
+// Browsers reject SameSite=None cookies without the Secure attribute, so
+// the development mode does not relax this rule.
+func TestStore_DevModeSameSiteNoneRequiresSecure(t *testing.T) {
+	_, err := NewStore[string](
+		WithSigningKey(testKey),
+		WithEncryptionKey(testKey),
+		WithDevMode(true),
+		WithSecure(false),
+		WithSameSite(sessions.CookieSameSiteNone),
+	)
+	assert.ErrorIs(t, err, sessions.ErrSameSiteNoneRequiresSecure)
+
+	_, err = NewStore[string](
+		WithSigningKey(testKey),
+		WithEncryptionKey(testKey),
+		WithDevMode(true),
+		WithSecure(true),
+		WithSameSite(sessions.CookieSameSiteNone),
+	)
+	assert.NoError(t, err)
+}
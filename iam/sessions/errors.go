@@ -14,8 +14,19 @@ var (
 	ErrSecureRequired             = errors.New("secure must be true for production")
 	ErrHTTPOnlyRequired           = errors.New("httpOnly must be true for security")
 	ErrSameSiteNoneRequiresSecure = errors.New("SameSite=None requires Secure=true")
+	ErrPartitionedRequiresSecure  = errors.New("partitioned cookies require Secure=true")
+	ErrCookiePrefix               = errors.New("cookie name prefix requirements not met")
+	ErrLocalhostDomain            = errors.New("localhost cookie domain outside development")
 	ErrBucketNameRequired         = errors.New("bucket name is required")
 	ErrServerURLRequired          = errors.New("server URL is required")
+	ErrPoolSizeMustBePositive     = errors.New("pool size must be positive")
+	ErrTimeoutMustBePositive      = errors.New("timeout must be positive")
 	ErrSaveFailed                 = errors.New("save error")
 	ErrLoadFailed                 = errors.New("load error")
+	ErrSessionRevoked             = errors.New("session has been revoked")
+	ErrRevocationNotSupported     = errors.New("no revocation list configured")
+	ErrMissingUserID              = errors.New("user id is required")
+	ErrSessionConflict            = errors.New("session was modified by a concurrent request")
+	ErrPayloadTooLarge            = errors.New("session payload too large")
+	ErrSessionNameRequired        = errors.New("session name is required")
 )
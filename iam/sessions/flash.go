@@ -0,0 +1,53 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+// MaxFlashesPerKey limits the flash messages kept per key. Older messages are
+// dropped, so that a redirect loop cannot grow the session without bound.
+const MaxFlashesPerKey = 10
+
+// Flash adds a one-time message under key, e.g. "success" or "error". Flash
+// messages are stored with the session like regular values, so they are only
+// visible to the session owner, and are removed once they were read with
+// ConsumeFlashes and the session was saved again.
+func (s *Session[T]) Flash(key string, value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Flashes == nil {
+		s.Flashes = make(map[string][]T)
+	}
+
+	flashes := append(s.Flashes[key], value)
+	if len(flashes) > MaxFlashesPerKey {
+		flashes = flashes[len(flashes)-MaxFlashesPerKey:]
+	}
+
+	s.Flashes[key] = flashes
+}
+
+// ConsumeFlashes returns all flash messages by key and removes them from the
+// session. The session must be saved for the removal to be persisted. Returns
+// nil if there are no flash messages.
+func (s *Session[T]) ConsumeFlashes() map[string][]T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.Flashes) == 0 {
+		return nil
+	}
+
+	flashes := s.Flashes
+	s.Flashes = nil
+
+	return flashes
+}
+
+// HasFlashes reports whether the session has unread flash messages.
+func (s *Session[T]) HasFlashes() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.Flashes) > 0
+}
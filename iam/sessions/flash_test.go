@@ -0,0 +1,94 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_Flash(t *testing.T) {
+	s := NewSession(newMockStore[string](), "foo")
+	assert.False(t, s.HasFlashes())
+	assert.Nil(t, s.ConsumeFlashes())
+
+	s.Flash("success", "saved")
+	s.Flash("success", "published")
+	s.Flash("error", "quota exceeded")
+	assert.True(t, s.HasFlashes())
+
+	flashes := s.ConsumeFlashes()
+	assert.Equal(t, map[string][]string{
+		"success": {"saved", "published"},
+		"error":   {"quota exceeded"},
+	}, flashes)
+
+	// flashes are only returned once
+	assert.False(t, s.HasFlashes())
+	assert.Nil(t, s.ConsumeFlashes())
+}
+
+func TestSession_FlashLimit(t *testing.T) {
+	s := NewSession(newMockStore[int](), "foo")
+	for i := range MaxFlashesPerKey + 5 {
+		s.Flash("info", i)
+	}
+
+	flashes := s.ConsumeFlashes()["info"]
+	require.Len(t, flashes, MaxFlashesPerKey)
+	assert.Equal(t, 5, flashes[0])
+	assert.Equal(t, MaxFlashesPerKey+4, flashes[len(flashes)-1])
+}
+
+func TestSession_FlashEncodeDecode(t *testing.T) {
+	key := "12345678901234567890123456789012"
+	s := NewSession(newMockStore[string](), "foo")
+	s.Flash("success", "saved")
+
+	enc, err := EncodeSession(s, key)
+	require.NoError(t, err)
+
+	dec, err := DecodeSession[string](enc, key)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"success": {"saved"}}, dec.ConsumeFlashes())
+
+	// once consumed, the flashes are not encoded again
+	enc, err = EncodeSession(dec, key)
+	require.NoError(t, err)
+
+	dec, err = DecodeSession[string](enc, key)
+	require.NoError(t, err)
+	assert.False(t, dec.HasFlashes())
+}
+
+func TestLazySession_Flash(t *testing.T) {
+	store := newVersionedTestStore()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	// POST: add a flash message and redirect
+	lazy := NewLazySession[string](store, req, "test")
+	require.NoError(t, lazy.Flash("success", "saved"))
+	require.NoError(t, lazy.Save(httptest.NewRecorder()))
+	assert.Equal(t, 1, store.saves)
+
+	// GET: consume the flash message
+	lazy = NewLazySession[string](store, req, "test")
+	flashes, err := lazy.ConsumeFlashes()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"saved"}, flashes["success"])
+	assert.True(t, lazy.Dirty())
+	require.NoError(t, lazy.Save(httptest.NewRecorder()))
+	assert.Equal(t, 2, store.saves)
+
+	// next request: nothing left, and nothing to save
+	lazy = NewLazySession[string](store, req, "test")
+	flashes, err = lazy.ConsumeFlashes()
+	require.NoError(t, err)
+	assert.Nil(t, flashes)
+	assert.False(t, lazy.Dirty())
+}
@@ -0,0 +1,298 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/kopexa-grc/common/ctxutil"
+	"github.com/rs/zerolog"
+)
+
+// VersionedStore is a Store with optimistic concurrency control. Every save
+// increments Session.Version; a save based on an outdated version is
+// rejected, so concurrent requests cannot silently overwrite each other.
+type VersionedStore[T any] interface {
+	Store[T]
+
+	// SaveVersion persists the session if the stored version still equals
+	// expected, which is 0 for sessions that were not stored yet. On success
+	// session.Version is expected+1; otherwise ErrSessionConflict is returned.
+	SaveVersion(w http.ResponseWriter, session *Session[T], expected uint64) error
+}
+
+// saveVersion saves session with a version check if store is a
+// VersionedStore. Other stores only get the version incremented.
+func saveVersion[T any](store Store[T], w http.ResponseWriter, session *Session[T], expected uint64) error {
+	if vs, ok := store.(VersionedStore[T]); ok {
+		return vs.SaveVersion(w, session, expected)
+	}
+
+	session.Version = expected + 1
+	if err := store.Save(w, session); err != nil {
+		session.Version = expected
+		return err
+	}
+
+	return nil
+}
+
+// LazySession defers loading a session until it is first accessed and
+// saving it until the end of the request. Requests that never touch the
+// session cost no store round trip, and requests that only read it are not
+// written back.
+//
+// If the store is a VersionedStore, the save fails with ErrSessionConflict
+// when another request saved the session after it was loaded.
+type LazySession[T any] struct {
+	store Store[T]
+	req   *http.Request
+	name  string
+
+	mu      sync.Mutex
+	session *Session[T]
+	version uint64 // version at load time
+	loaded  bool
+	dirty   bool
+}
+
+// NewLazySession creates a LazySession for the session name of r. Nothing is
+// loaded until the session is accessed.
+func NewLazySession[T any](store Store[T], r *http.Request, name string) *LazySession[T] {
+	return &LazySession[T]{store: store, req: r, name: name}
+}
+
+// Session loads the session on first access and returns it. If the request
+// has no valid session, a new one is started. Changes made directly on the
+// returned session must be reported with MarkDirty.
+func (l *LazySession[T]) Session() (*Session[T], error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.load()
+}
+
+// Get returns the value stored under key.
+func (l *LazySession[T]) Get(key string) (T, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, err := l.load()
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+
+	v, ok := s.GetOk(key)
+
+	return v, ok, nil
+}
+
+// Set stores value under key and marks the session dirty.
+func (l *LazySession[T]) Set(key string, value T) error {
+	return l.update(func(s *Session[T]) { s.Set(key, value) })
+}
+
+// Delete removes key and marks the session dirty.
+func (l *LazySession[T]) Delete(key string) error {
+	return l.update(func(s *Session[T]) { s.Delete(key) })
+}
+
+// Clear removes all values and marks the session dirty.
+func (l *LazySession[T]) Clear() error {
+	return l.update(func(s *Session[T]) { s.Clear() })
+}
+
+// Flash adds a one-time message under key and marks the session dirty.
+func (l *LazySession[T]) Flash(key string, value T) error {
+	return l.update(func(s *Session[T]) { s.Flash(key, value) })
+}
+
+// ConsumeFlashes returns and removes all flash messages. The session is only
+// marked dirty if there were flash messages, so reading an empty flash does
+// not cause a save.
+func (l *LazySession[T]) ConsumeFlashes() (map[string][]T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+
+	flashes := s.ConsumeFlashes()
+	if flashes != nil {
+		l.dirty = true
+	}
+
+	return flashes, nil
+}
+
+// MarkDirty flags the session to be saved at the end of the request.
+func (l *LazySession[T]) MarkDirty() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.dirty = l.loaded
+}
+
+// Loaded reports whether the session has been loaded.
+func (l *LazySession[T]) Loaded() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.loaded
+}
+
+// Dirty reports whether the session has unsaved changes.
+func (l *LazySession[T]) Dirty() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.dirty
+}
+
+// Save persists the session if it is dirty. A session that was only read is
+// not saved. Returns ErrSessionConflict if the session was saved by another
+// request in the meantime.
+func (l *LazySession[T]) Save(w http.ResponseWriter) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.dirty {
+		return nil
+	}
+
+	if err := saveVersion(l.store, w, l.session, l.version); err != nil {
+		return err
+	}
+
+	l.version = l.session.Version
+	l.dirty = false
+
+	return nil
+}
+
+// load loads the session once. The caller must hold l.mu.
+func (l *LazySession[T]) load() (*Session[T], error) {
+	if l.loaded {
+		return l.session, nil
+	}
+
+	s, err := l.store.Load(l.req, l.name)
+
+	switch {
+	case err == nil && s != nil:
+	case err == nil, errors.Is(err, ErrInvalidSession), errors.Is(err, ErrSessionExpired),
+		errors.Is(err, ErrSessionRevoked), errors.Is(err, http.ErrNoCookie):
+		s = NewSession(l.store, l.name)
+	default:
+		return nil, err
+	}
+
+	if s.store == nil {
+		s.store = l.store
+	}
+
+	l.session = s
+	l.version = s.Version
+	l.loaded = true
+
+	return s, nil
+}
+
+// update loads the session, applies fn and marks it dirty.
+func (l *LazySession[T]) update(fn func(*Session[T])) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, err := l.load()
+	if err != nil {
+		return err
+	}
+
+	fn(s)
+	l.dirty = true
+
+	return nil
+}
+
+// WithLazySession stores a lazy session in the context
+func WithLazySession[T any](ctx context.Context, session *LazySession[T]) context.Context {
+	return ctxutil.With(ctx, session)
+}
+
+// FromLazySession retrieves a lazy session from the context
+func FromLazySession[T any](ctx context.Context) (*LazySession[T], bool) {
+	return ctxutil.From[*LazySession[T]](ctx)
+}
+
+// lazyResponseWriter saves the lazy session before the response header is
+// written, so cookies set by the store are still sent.
+type lazyResponseWriter[T any] struct {
+	http.ResponseWriter
+	session *LazySession[T]
+	req     *http.Request
+	once    sync.Once
+}
+
+// flush saves the session once.
+func (rw *lazyResponseWriter[T]) flush() {
+	rw.once.Do(func() {
+		if err := rw.session.Save(rw.ResponseWriter); err != nil {
+			event := zerolog.Ctx(rw.req.Context()).Error()
+			if errors.Is(err, ErrSessionConflict) {
+				event = zerolog.Ctx(rw.req.Context()).Warn()
+			}
+
+			event.Err(err).Str("session_name", rw.session.name).Msg("failed to save session")
+		}
+	})
+}
+
+// WriteHeader saves the session and writes the status code
+func (rw *lazyResponseWriter[T]) WriteHeader(code int) {
+	rw.flush()
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Write saves the session and writes the body
+func (rw *lazyResponseWriter[T]) Write(b []byte) (int, error) {
+	rw.flush()
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (rw *lazyResponseWriter[T]) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// LazySessionMiddleware returns a middleware that puts a LazySession into
+// the request context. The session is loaded on first access and saved only
+// if it was modified: before the response is written or, for handlers that
+// write nothing, after the handler returned. Save errors are logged; a
+// conflict with a concurrent request is logged as a warning and the changes
+// of this request are dropped.
+//
+// Handlers access the session with FromLazySession:
+//
+//	lazy, _ := sessions.FromLazySession[string](r.Context())
+//	if err := lazy.Set("theme", "dark"); err != nil { ... }
+func LazySessionMiddleware[T any](store Store[T], sessionName string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lazy := NewLazySession(store, r, sessionName)
+			r = r.WithContext(WithLazySession(r.Context(), lazy))
+
+			rw := &lazyResponseWriter[T]{ResponseWriter: w, session: lazy, req: r}
+
+			next.ServeHTTP(rw, r)
+
+			rw.flush()
+		})
+	}
+}
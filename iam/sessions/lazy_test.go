@@ -0,0 +1,284 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedTestStore keeps one copy of each session per name and checks
+// versions on save
+type versionedTestStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session[string]
+	loads    int
+	saves    int
+	loadErr  error
+}
+
+func newVersionedTestStore() *versionedTestStore {
+	return &versionedTestStore{sessions: make(map[string]*Session[string])}
+}
+
+func (s *versionedTestStore) put(session *Session[string]) {
+	values := make(map[string]string, len(session.Values))
+	for k, v := range session.Values {
+		values[k] = v
+	}
+
+	s.sessions[session.Name] = &Session[string]{
+		ID:      session.ID,
+		Name:    session.Name,
+		Values:  values,
+		Version: session.Version,
+		Flashes: session.Flashes,
+	}
+}
+
+func (s *versionedTestStore) Save(_ http.ResponseWriter, session *Session[string]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.saves++
+	s.put(session)
+
+	return nil
+}
+
+func (s *versionedTestStore) SaveVersion(w http.ResponseWriter, session *Session[string], expected uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var current uint64
+	if stored, ok := s.sessions[session.Name]; ok {
+		current = stored.Version
+	}
+
+	if current != expected {
+		return ErrSessionConflict
+	}
+
+	session.Version = expected + 1
+	s.saves++
+	s.put(session)
+	http.SetCookie(w, &http.Cookie{Name: session.Name, Value: session.ID})
+
+	return nil
+}
+
+func (s *versionedTestStore) Load(_ *http.Request, name string) (*Session[string], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loads++
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+
+	stored, ok := s.sessions[name]
+	if !ok {
+		return nil, ErrInvalidSession
+	}
+
+	session := &Session[string]{
+		ID:      stored.ID,
+		Name:    stored.Name,
+		Values:  make(map[string]string, len(stored.Values)),
+		Version: stored.Version,
+		Flashes: stored.Flashes,
+	}
+	for k, v := range stored.Values {
+		session.Values[k] = v
+	}
+
+	return session, nil
+}
+
+func (s *versionedTestStore) Destroy(_ http.ResponseWriter, _ *http.Request, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, name)
+}
+
+func TestLazySession_LoadsOnFirstAccess(t *testing.T) {
+	store := newVersionedTestStore()
+	store.put(&Session[string]{ID: "abc", Name: "test", Values: map[string]string{"key": "value"}, Version: 3})
+
+	lazy := NewLazySession[string](store, httptest.NewRequest(http.MethodGet, "/", nil), "test")
+	assert.False(t, lazy.Loaded())
+	assert.Equal(t, 0, store.loads)
+
+	v, ok, err := lazy.Get("key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "value", v)
+
+	_, _, err = lazy.Get("other")
+	require.NoError(t, err)
+	assert.True(t, lazy.Loaded())
+	assert.Equal(t, 1, store.loads)
+
+	// Reading does not save
+	require.NoError(t, lazy.Save(httptest.NewRecorder()))
+	assert.Equal(t, 0, store.saves)
+}
+
+func TestLazySession_NewSessionWhenMissing(t *testing.T) {
+	store := newVersionedTestStore()
+
+	lazy := NewLazySession[string](store, httptest.NewRequest(http.MethodGet, "/", nil), "test")
+
+	s, err := lazy.Session()
+	require.NoError(t, err)
+	assert.NotEmpty(t, s.ID)
+	assert.Equal(t, uint64(0), s.Version)
+
+	require.NoError(t, lazy.Set("key", "value"))
+	assert.True(t, lazy.Dirty())
+
+	require.NoError(t, lazy.Save(httptest.NewRecorder()))
+	assert.False(t, lazy.Dirty())
+	assert.Equal(t, uint64(1), store.sessions["test"].Version)
+	assert.Equal(t, "value", store.sessions["test"].Values["key"])
+}
+
+func TestLazySession_LoadError(t *testing.T) {
+	store := newVersionedTestStore()
+	store.loadErr = errors.New("unavailable")
+
+	lazy := NewLazySession[string](store, httptest.NewRequest(http.MethodGet, "/", nil), "test")
+
+	require.ErrorIs(t, lazy.Set("key", "value"), store.loadErr)
+	assert.False(t, lazy.Loaded())
+	assert.False(t, lazy.Dirty())
+
+	// Revoked sessions start over
+	store.loadErr = ErrSessionRevoked
+	s, err := lazy.Session()
+	require.NoError(t, err)
+	assert.Empty(t, s.Values)
+}
+
+func TestLazySession_Conflict(t *testing.T) {
+	store := newVersionedTestStore()
+	store.put(&Session[string]{ID: "abc", Name: "test", Values: map[string]string{}, Version: 1})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	first := NewLazySession[string](store, r, "test")
+	second := NewLazySession[string](store, r, "test")
+
+	require.NoError(t, first.Set("key", "first"))
+	require.NoError(t, second.Set("key", "second"))
+
+	require.NoError(t, first.Save(httptest.NewRecorder()))
+	require.ErrorIs(t, second.Save(httptest.NewRecorder()), ErrSessionConflict)
+
+	assert.Equal(t, "first", store.sessions["test"].Values["key"])
+	assert.Equal(t, uint64(2), store.sessions["test"].Version)
+
+	// Further saves of the first request continue from its version
+	require.NoError(t, first.Delete("key"))
+	require.NoError(t, first.Save(httptest.NewRecorder()))
+	assert.Equal(t, uint64(3), store.sessions["test"].Version)
+}
+
+func TestLazySession_UnversionedStore(t *testing.T) {
+	store := newTestStore[string]()
+
+	lazy := NewLazySession[string](store, httptest.NewRequest(http.MethodGet, "/", nil), "test")
+	require.NoError(t, lazy.Set("key", "value"))
+	require.NoError(t, lazy.Save(httptest.NewRecorder()))
+
+	s, err := lazy.Session()
+	require.NoError(t, err)
+	assert.True(t, store.saved)
+	assert.Equal(t, uint64(1), s.Version)
+}
+
+func TestLazySessionMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		wantLoads int
+		wantSaves int
+		cookie    bool
+	}{
+		{
+			name:    "untouched session",
+			handler: func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) },
+		},
+		{
+			name: "read only",
+			handler: func(_ http.ResponseWriter, r *http.Request) {
+				lazy, ok := FromLazySession[string](r.Context())
+				require.True(t, ok)
+
+				_, _, err := lazy.Get("key")
+				require.NoError(t, err)
+			},
+			wantLoads: 1,
+		},
+		{
+			name: "saved before body is written",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				lazy, _ := FromLazySession[string](r.Context())
+				require.NoError(t, lazy.Set("key", "value"))
+
+				_, _ = w.Write([]byte("ok"))
+			},
+			wantLoads: 1,
+			wantSaves: 1,
+			cookie:    true,
+		},
+		{
+			name: "saved after handler without response",
+			handler: func(_ http.ResponseWriter, r *http.Request) {
+				lazy, _ := FromLazySession[string](r.Context())
+				require.NoError(t, lazy.Set("key", "value"))
+			},
+			wantLoads: 1,
+			wantSaves: 1,
+			cookie:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newVersionedTestStore()
+			handler := LazySessionMiddleware[string](store, "test")(tt.handler)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantLoads, store.loads)
+			assert.Equal(t, tt.wantSaves, store.saves)
+			assert.Equal(t, tt.cookie, len(w.Result().Cookies()) == 1)
+		})
+	}
+}
+
+func TestInstrumentedStore_SaveVersion(t *testing.T) {
+	store := newVersionedTestStore()
+	m, _ := newTestPrometheusMetrics(t)
+
+	instrumented, ok := InstrumentStore[string](store, m).(VersionedStore[string])
+	require.True(t, ok)
+
+	s := NewSession[string](store, "test")
+	require.NoError(t, instrumented.SaveVersion(httptest.NewRecorder(), s, 0))
+	require.ErrorIs(t, instrumented.SaveVersion(httptest.NewRecorder(), s, 0), ErrSessionConflict)
+	assert.Equal(t, uint64(1), s.Version)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationSave, ResultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationSave, ResultError)))
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics receives measurements of session operations. Implementations must
+// be safe for concurrent use; see PrometheusMetrics for a ready-made adapter.
+type Metrics interface {
+	// SessionCreated is called for every session created through Config.NewSession.
+	SessionCreated()
+	// SessionLoaded is called after the store loaded a session.
+	SessionLoaded(d time.Duration, err error)
+	// SessionSaved is called after the store saved a session.
+	SessionSaved(d time.Duration, err error)
+	// SessionDestroyed is called after the store destroyed a session.
+	SessionDestroyed(d time.Duration)
+	// SessionEncoded is called by EncodeSession with the size of the encoded
+	// payload in bytes.
+	SessionEncoded(d time.Duration, size int, err error)
+	// SessionDecoded is called by DecodeSession with the size of the encoded
+	// payload in bytes.
+	SessionDecoded(d time.Duration, size int, err error)
+	// SessionPayload is called by SizeGuard with the size of the JSON
+	// payload of a session before it is saved, whether it was compressed and
+	// a *PayloadTooLargeError if it was rejected.
+	SessionPayload(size int, compressed bool, err error)
+}
+
+// NopMetrics is a Metrics implementation that discards all measurements.
+type NopMetrics struct{}
+
+// SessionCreated implements Metrics.
+func (NopMetrics) SessionCreated() {}
+
+// SessionLoaded implements Metrics.
+func (NopMetrics) SessionLoaded(time.Duration, error) {}
+
+// SessionSaved implements Metrics.
+func (NopMetrics) SessionSaved(time.Duration, error) {}
+
+// SessionDestroyed implements Metrics.
+func (NopMetrics) SessionDestroyed(time.Duration) {}
+
+// SessionEncoded implements Metrics.
+func (NopMetrics) SessionEncoded(time.Duration, int, error) {}
+
+// SessionDecoded implements Metrics.
+func (NopMetrics) SessionDecoded(time.Duration, int, error) {}
+
+// SessionPayload implements Metrics.
+func (NopMetrics) SessionPayload(int, bool, error) {}
+
+// WithMetrics instruments the store of the config with m and reports
+// EncodeSession and DecodeSession measurements to m. Since encoding is
+// implemented by package-level functions, codec measurements are process-wide
+// and go to the metrics of the most recently created config.
+func WithMetrics[T any](m Metrics) Option[T] {
+	return func(c *Config[T]) {
+		c.Metrics = m
+	}
+}
+
+// codecMetrics receives the measurements of EncodeSession and DecodeSession.
+var codecMetrics atomic.Pointer[Metrics]
+
+func setCodecMetrics(m Metrics) {
+	codecMetrics.Store(&m)
+}
+
+// observeCodec returns the metrics for codec measurements, or nil.
+func observeCodec() Metrics {
+	m := codecMetrics.Load()
+	if m == nil {
+		return nil
+	}
+
+	return *m
+}
+
+// InstrumentStore wraps store so that every Load, Save and Destroy is
+// reported to m. NewConfig applies it automatically when WithMetrics is used.
+func InstrumentStore[T any](store Store[T], m Metrics) Store[T] {
+	if m == nil {
+		return store
+	}
+
+	return &instrumentedStore[T]{store: store, metrics: m}
+}
+
+type instrumentedStore[T any] struct {
+	store   Store[T]
+	metrics Metrics
+}
+
+// Save implements Store.
+func (s *instrumentedStore[T]) Save(w http.ResponseWriter, session *Session[T]) error {
+	start := time.Now()
+	err := s.store.Save(w, session)
+	s.metrics.SessionSaved(time.Since(start), err)
+
+	return err
+}
+
+// SaveVersion implements VersionedStore.
+func (s *instrumentedStore[T]) SaveVersion(w http.ResponseWriter, session *Session[T], expected uint64) error {
+	start := time.Now()
+	err := saveVersion(s.store, w, session, expected)
+	s.metrics.SessionSaved(time.Since(start), err)
+
+	return err
+}
+
+// Load implements Store.
+func (s *instrumentedStore[T]) Load(r *http.Request, name string) (*Session[T], error) {
+	start := time.Now()
+	session, err := s.store.Load(r, name)
+	s.metrics.SessionLoaded(time.Since(start), err)
+
+	return session, err
+}
+
+// Destroy implements Store.
+func (s *instrumentedStore[T]) Destroy(w http.ResponseWriter, r *http.Request, name string) {
+	start := time.Now()
+	s.store.Destroy(w, r, name)
+	s.metrics.SessionDestroyed(time.Since(start))
+}
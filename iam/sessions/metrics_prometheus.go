@@ -0,0 +1,198 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kopexa-grc/common/wellknown"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Operation and result label values of PrometheusMetrics.
+const (
+	OperationLoad    = "load"
+	OperationSave    = "save"
+	OperationDestroy = "destroy"
+	OperationEncode  = "encode"
+	OperationDecode  = "decode"
+	OperationGuard   = "guard"
+
+	ResultSuccess  = "success"
+	ResultNotFound = "not_found"
+	ResultExpired  = "expired"
+	ResultError    = "error"
+
+	ResultCompressed = "compressed"
+	ResultTooLarge   = "too_large"
+)
+
+var (
+	// DurationBuckets are the histogram buckets of session operation latencies in seconds.
+	DurationBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+	// PayloadSizeBuckets are the histogram buckets of encoded session sizes in bytes.
+	// Browsers reject cookies larger than 4096 bytes.
+	PayloadSizeBuckets = []float64{256, 512, 1024, 2048, 3072, 4096, 8192, 16384}
+)
+
+// PrometheusMetrics implements Metrics with Prometheus collectors in the
+// "kopexa_sessions" namespace:
+//
+//   - created_total: sessions created through Config.NewSession
+//   - operations_total{operation,result}: loads, saves, destroys, encodes,
+//     decodes and size guard checks
+//   - operation_duration_seconds{operation}: latency of these operations
+//   - payload_size_bytes{operation}: size of encoded and decoded sessions
+//     and of the payloads checked by SizeGuard
+//   - active: number of active sessions, if WithActiveSessionsFunc is used
+type PrometheusMetrics struct {
+	created     prometheus.Counter
+	operations  *prometheus.CounterVec
+	durations   *prometheus.HistogramVec
+	payloadSize *prometheus.HistogramVec
+}
+
+// PrometheusOption configures NewPrometheusMetrics.
+type PrometheusOption func(*prometheusOptions)
+
+type prometheusOptions struct {
+	activeSessions func() float64
+}
+
+// WithActiveSessionsFunc exports the number of active sessions as gauge,
+// computed by fn on every scrape. Only server-side stores can count their
+// sessions, e.g. with nats.Store.GetActiveSessions.
+func WithActiveSessionsFunc(fn func() float64) PrometheusOption {
+	return func(o *prometheusOptions) {
+		o.activeSessions = fn
+	}
+}
+
+// NewPrometheusMetrics creates the session collectors and registers them
+// with reg.
+func NewPrometheusMetrics(reg prometheus.Registerer, opts ...PrometheusOption) (*PrometheusMetrics, error) {
+	o := &prometheusOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	namespace := wellknown.PrometheusNamespaceKopexa
+	subsystem := "sessions"
+
+	m := &PrometheusMetrics{
+		created: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "created_total",
+			Help:      "Total number of created sessions.",
+		}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "operations_total",
+			Help:      "Total number of session operations by operation and result.",
+		}, []string{"operation", "result"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of session operations in seconds.",
+			Buckets:   DurationBuckets,
+		}, []string{"operation"}),
+		payloadSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "payload_size_bytes",
+			Help:      "Size of encoded sessions in bytes.",
+			Buckets:   PayloadSizeBuckets,
+		}, []string{"operation"}),
+	}
+
+	collectors := []prometheus.Collector{m.created, m.operations, m.durations, m.payloadSize}
+
+	if o.activeSessions != nil {
+		collectors = append(collectors, prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "active",
+			Help:      "Number of active sessions.",
+		}, o.activeSessions))
+	}
+
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// SessionCreated implements Metrics.
+func (m *PrometheusMetrics) SessionCreated() {
+	m.created.Inc()
+}
+
+// SessionLoaded implements Metrics.
+func (m *PrometheusMetrics) SessionLoaded(d time.Duration, err error) {
+	m.observe(OperationLoad, d, err)
+}
+
+// SessionSaved implements Metrics.
+func (m *PrometheusMetrics) SessionSaved(d time.Duration, err error) {
+	m.observe(OperationSave, d, err)
+}
+
+// SessionDestroyed implements Metrics.
+func (m *PrometheusMetrics) SessionDestroyed(d time.Duration) {
+	m.observe(OperationDestroy, d, nil)
+}
+
+// SessionEncoded implements Metrics.
+func (m *PrometheusMetrics) SessionEncoded(d time.Duration, size int, err error) {
+	m.observe(OperationEncode, d, err)
+
+	if err == nil {
+		m.payloadSize.WithLabelValues(OperationEncode).Observe(float64(size))
+	}
+}
+
+// SessionDecoded implements Metrics.
+func (m *PrometheusMetrics) SessionDecoded(d time.Duration, size int, err error) {
+	m.observe(OperationDecode, d, err)
+	m.payloadSize.WithLabelValues(OperationDecode).Observe(float64(size))
+}
+
+// SessionPayload implements Metrics.
+func (m *PrometheusMetrics) SessionPayload(size int, compressed bool, err error) {
+	result := resultLabel(err)
+	if err == nil && compressed {
+		result = ResultCompressed
+	}
+
+	m.operations.WithLabelValues(OperationGuard, result).Inc()
+	m.payloadSize.WithLabelValues(OperationGuard).Observe(float64(size))
+}
+
+func (m *PrometheusMetrics) observe(operation string, d time.Duration, err error) {
+	m.operations.WithLabelValues(operation, resultLabel(err)).Inc()
+	m.durations.WithLabelValues(operation).Observe(d.Seconds())
+}
+
+// resultLabel maps an operation error to a low-cardinality result label.
+func resultLabel(err error) string {
+	switch {
+	case err == nil:
+		return ResultSuccess
+	case errors.Is(err, ErrInvalidSession):
+		return ResultNotFound
+	case errors.Is(err, ErrSessionExpired):
+		return ResultExpired
+	case errors.Is(err, ErrPayloadTooLarge):
+		return ResultTooLarge
+	default:
+		return ResultError
+	}
+}
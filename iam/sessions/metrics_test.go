@@ -0,0 +1,117 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPrometheusMetrics(t *testing.T, opts ...PrometheusOption) (*PrometheusMetrics, *prometheus.Registry) {
+	t.Helper()
+
+	reg := prometheus.NewRegistry()
+
+	m, err := NewPrometheusMetrics(reg, opts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { codecMetrics.Store(nil) })
+
+	return m, reg
+}
+
+func TestConfigWithMetrics(t *testing.T) {
+	m, _ := newTestPrometheusMetrics(t)
+	store := newMockStore[string]()
+
+	config := NewConfig(store, WithMetrics[string](m))
+
+	session := config.NewSession("test")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.created))
+
+	require.NoError(t, session.Save(httptest.NewRecorder()))
+	assert.Same(t, session, store.sessions["test"])
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationSave, ResultSuccess)))
+
+	store.saveErr = errors.New("boom")
+	require.Error(t, session.Save(httptest.NewRecorder()))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationSave, ResultError)))
+
+	store.loadErr = ErrInvalidSession
+	_, err := config.Store.Load(httptest.NewRequest("GET", "/", nil), "test")
+	require.ErrorIs(t, err, ErrInvalidSession)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationLoad, ResultNotFound)))
+
+	session.Destroy(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.True(t, store.destroyed)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationDestroy, ResultSuccess)))
+
+	assert.Equal(t, 3, testutil.CollectAndCount(m.durations))
+}
+
+func TestConfigWithoutMetrics(t *testing.T) {
+	store := newMockStore[string]()
+	config := NewConfig[string](store)
+
+	assert.Same(t, store, config.Store)
+	assert.NotNil(t, config.NewSession("test"))
+}
+
+func TestCodecMetrics(t *testing.T) {
+	m, _ := newTestPrometheusMetrics(t)
+	_ = NewConfig(newMockStore[string](), WithMetrics[string](m))
+
+	key := "12345678901234567890123456789012"
+	session := NewSession[string](nil, "test")
+	session.Set("key", "value")
+
+	encoded, err := EncodeSession(session, key)
+	require.NoError(t, err)
+
+	_, err = DecodeSession[string](encoded, key)
+	require.NoError(t, err)
+
+	_, err = DecodeSession[string](encoded, strings.Repeat("x", 32))
+	require.Error(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationEncode, ResultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationDecode, ResultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationDecode, ResultError)))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.payloadSize))
+}
+
+func TestPrometheusMetricsActiveSessions(t *testing.T) {
+	_, reg := newTestPrometheusMetrics(t, WithActiveSessionsFunc(func() float64 { return 7 }))
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP kopexa_sessions_active Number of active sessions.
+# TYPE kopexa_sessions_active gauge
+kopexa_sessions_active 7
+`), "kopexa_sessions_active")
+	require.NoError(t, err)
+}
+
+func TestPrometheusMetricsDuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	_, err := NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	_, err = NewPrometheusMetrics(reg)
+	require.Error(t, err)
+}
+
+func TestResultLabel(t *testing.T) {
+	assert.Equal(t, ResultSuccess, resultLabel(nil))
+	assert.Equal(t, ResultNotFound, resultLabel(ErrInvalidSession))
+	assert.Equal(t, ResultExpired, resultLabel(ErrSessionExpired))
+	assert.Equal(t, ResultError, resultLabel(errors.New("boom")))
+}
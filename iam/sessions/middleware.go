@@ -4,7 +4,9 @@
 package sessions
 
 import (
+	"errors"
 	"net/http"
+	"sync"
 
 	"github.com/rs/zerolog"
 )
@@ -29,7 +31,9 @@ func (rw *responseWriter) WriteHeader(code int) {
 }
 
 // SessionMiddleware returns a middleware that loads the session from the store and
-// puts it into the request context for downstream handlers.
+// puts it into the request context for downstream handlers. The session is
+// saved after the handler returned; see Config.Middleware for a middleware
+// that saves it before the response is written.
 func SessionMiddleware[T any](store Store[T], sessionName string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -71,3 +75,221 @@ func GetSessionFromContext[T any](r *http.Request) *Session[T] {
 	sess, _ := FromSession[T](r.Context())
 	return sess
 }
+
+// PrivilegeFunc returns a fingerprint of the privileges a session grants,
+// e.g. the user ID and roles. Config.Middleware rotates the session ID when
+// the fingerprint changes during a request.
+type PrivilegeFunc[T any] func(session *Session[T]) string
+
+// WithPrivilege sets the privilege fingerprint used by Config.Middleware
+func WithPrivilege[T any](fn PrivilegeFunc[T]) Option[T] {
+	return func(c *Config[T]) {
+		c.Privilege = fn
+	}
+}
+
+// Middleware returns a middleware that handles the session named name, or
+// CookieConfig.Name if name is empty, for every request:
+//
+//   - the session is loaded from the store, or a new one is started if the
+//     request has no valid session, and put into the request context with
+//     WithSession
+//   - it is saved before the response header is written, so that the
+//     session cookie is still sent, or after the handler returned if it
+//     wrote nothing; sessions destroyed by the handler are not saved
+//   - if the Privilege fingerprint of a loaded session changed, e.g. on
+//     login or a role change, the session ID is rotated and OnUpgrade is
+//     called with the old ID
+//   - the attributes of CookieConfig are applied to the session cookie set
+//     by the store
+//
+// An error is returned if no name is given or the cookie settings are
+// invalid, see Config.Validate. For echo, wrap the middleware with
+// echo.WrapMiddleware.
+//
+// Example:
+//
+//	mw, err := sessions.NewConfig(store,
+//		sessions.WithCookieConfig[User](sessions.StrictFirstParty("__Host-session", "")),
+//		sessions.WithPrivilege(func(s *sessions.Session[User]) string {
+//			u := s.Get("user")
+//			return u.ID + "|" + strings.Join(u.Roles, ",")
+//		}),
+//	).Middleware("")
+//	r.Use(mw)
+func (c Config[T]) Middleware(name string) (func(http.Handler) http.Handler, error) {
+	if name == "" && c.CookieConfig != nil {
+		name = c.CookieConfig.Name
+	}
+
+	if name == "" {
+		return nil, ErrSessionNameRequired
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, loaded := c.loadOrCreate(r, name)
+
+			r = r.WithContext(WithSession(r.Context(), session))
+
+			rw := &sessionResponseWriter[T]{
+				ResponseWriter: w,
+				config:         c,
+				req:            r,
+				name:           name,
+				session:        session,
+				loaded:         loaded,
+				id:             session.ID,
+				version:        session.Version,
+			}
+
+			if loaded && c.Privilege != nil {
+				rw.privilege = c.Privilege(session)
+			}
+
+			next.ServeHTTP(rw, r)
+
+			rw.flush()
+		})
+	}, nil
+}
+
+// loadOrCreate loads the session of r or starts a new one. It reports
+// whether the session was loaded.
+func (c Config[T]) loadOrCreate(r *http.Request, name string) (*Session[T], bool) {
+	s, err := c.Store.Load(r, name)
+
+	switch {
+	case err == nil && s != nil:
+		if s.store == nil {
+			s.store = c.Store
+		}
+
+		return s, true
+	case err == nil, errors.Is(err, ErrInvalidSession), errors.Is(err, ErrSessionExpired),
+		errors.Is(err, ErrSessionRevoked), errors.Is(err, http.ErrNoCookie):
+	default:
+		zerolog.Ctx(r.Context()).Error().Err(err).Str("session_name", name).Msg("failed to load session")
+	}
+
+	return c.NewSession(name), false
+}
+
+// sessionResponseWriter saves the session of Config.Middleware before the
+// response header is written.
+type sessionResponseWriter[T any] struct {
+	http.ResponseWriter
+	config  Config[T]
+	req     *http.Request
+	name    string
+	session *Session[T]
+	once    sync.Once
+
+	// state at load time
+	loaded    bool
+	id        string
+	version   uint64
+	privilege string
+}
+
+// flush rotates and saves the session once.
+func (rw *sessionResponseWriter[T]) flush() {
+	rw.once.Do(func() {
+		if destroyed(rw.Header(), rw.name) {
+			return
+		}
+
+		s := rw.session
+
+		if rw.loaded && rw.config.Privilege != nil && s.ID == rw.id && rw.config.Privilege(s) != rw.privilege {
+			s.Rotate()
+
+			if rw.config.OnUpgrade != nil {
+				rw.config.OnUpgrade(rw.id, s)
+			}
+		}
+
+		// A rotated session is stored under its new ID for the first time.
+		expected := rw.version
+		if s.ID != rw.id {
+			expected = 0
+		}
+
+		if err := saveVersion(rw.config.Store, rw.ResponseWriter, s, expected); err != nil {
+			event := zerolog.Ctx(rw.req.Context()).Error()
+			if errors.Is(err, ErrSessionConflict) {
+				event = zerolog.Ctx(rw.req.Context()).Warn()
+			}
+
+			event.Err(err).Str("session_name", rw.name).Msg("failed to save session")
+
+			return
+		}
+
+		applyCookieConfig(rw.Header(), rw.name, rw.config.CookieConfig)
+	})
+}
+
+// WriteHeader saves the session and writes the status code
+func (rw *sessionResponseWriter[T]) WriteHeader(code int) {
+	rw.flush()
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Write saves the session and writes the body
+func (rw *sessionResponseWriter[T]) Write(b []byte) (int, error) {
+	rw.flush()
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (rw *sessionResponseWriter[T]) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// destroyed reports whether h deletes the cookie name, e.g. because the
+// handler destroyed the session.
+func destroyed(h http.Header, name string) bool {
+	for _, line := range h.Values("Set-Cookie") {
+		if c, err := http.ParseSetCookie(line); err == nil && c.Name == name && c.MaxAge < 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyCookieConfig sets the attributes of cfg on the cookie name in h.
+func applyCookieConfig(h http.Header, name string, cfg *CookieConfig) {
+	if cfg == nil {
+		return
+	}
+
+	lines := h["Set-Cookie"]
+
+	for i, line := range lines {
+		c, err := http.ParseSetCookie(line)
+		if err != nil || c.Name != name {
+			continue
+		}
+
+		if c.Path == "" {
+			c.Path = CookiePath
+		}
+
+		if cfg.SameSite != 0 {
+			c.SameSite = cfg.SameSite
+		}
+
+		c.Domain = cfg.Domain
+		c.Secure = cfg.Secure
+		c.HttpOnly = cfg.HTTPOnly
+		c.Partitioned = cfg.Partitioned
+
+		lines[i] = c.String()
+	}
+}
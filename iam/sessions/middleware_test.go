@@ -238,3 +238,126 @@ func TestSessionMiddleware(t *testing.T) {
 		})
 	}
 }
+
+// cookieTestStore keeps sessions by ID and sets a bare session cookie like
+// server-side stores do
+type cookieTestStore struct {
+	sessions  map[string]*Session[string]
+	destroyed []string
+}
+
+func (s *cookieTestStore) Save(w http.ResponseWriter, session *Session[string]) error {
+	s.sessions[session.ID] = session
+	http.SetCookie(w, &http.Cookie{Name: session.Name, Value: session.ID, Domain: "store.example", MaxAge: 60})
+
+	return nil
+}
+
+func (s *cookieTestStore) Load(r *http.Request, name string) (*Session[string], error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return nil, err
+	}
+
+	session, ok := s.sessions[c.Value]
+	if !ok {
+		return nil, ErrInvalidSession
+	}
+
+	return session, nil
+}
+
+func (s *cookieTestStore) Destroy(w http.ResponseWriter, r *http.Request, name string) {
+	http.SetCookie(w, &http.Cookie{Name: name, MaxAge: -1})
+
+	if c, err := r.Cookie(name); err == nil {
+		delete(s.sessions, c.Value)
+		s.destroyed = append(s.destroyed, c.Value)
+	}
+}
+
+func TestConfig_Middleware(t *testing.T) {
+	store := &cookieTestStore{sessions: map[string]*Session[string]{}}
+
+	var upgradedFrom string
+
+	mw, err := NewConfig[string](store,
+		WithCookieConfig[string](StrictFirstParty("__Host-session", "")),
+		WithPrivilege(func(s *Session[string]) string { return s.Get("user") }),
+		WithOnUpgrade(func(oldID string, _ *Session[string]) { upgradedFrom = oldID }),
+	).Middleware("")
+	require.NoError(t, err)
+
+	serve := func(cookie *http.Cookie, handler http.HandlerFunc) *http.Response {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if cookie != nil {
+			r.AddCookie(cookie)
+		}
+
+		w := httptest.NewRecorder()
+		mw(handler).ServeHTTP(w, r)
+
+		return w.Result()
+	}
+
+	// A new session is started and its cookie is sent before the body.
+	resp := serve(nil, func(w http.ResponseWriter, r *http.Request) {
+		session := GetSessionFromContext[string](r)
+		require.NotNil(t, session)
+		session.Set("theme", "dark")
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 1)
+
+	cookie := cookies[0]
+	assert.Equal(t, "__Host-session", cookie.Name)
+	assert.True(t, cookie.Secure)
+	assert.True(t, cookie.HttpOnly)
+	assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+	assert.Empty(t, cookie.Domain)
+	assert.Equal(t, "/", cookie.Path)
+
+	anonymousID := cookie.Value
+
+	// Logging in changes the privileges and rotates the session ID.
+	resp = serve(cookie, func(w http.ResponseWriter, r *http.Request) {
+		session := GetSessionFromContext[string](r)
+		assert.Equal(t, anonymousID, session.ID)
+		session.Set("user", "alice")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cookie = resp.Cookies()[0]
+	assert.NotEqual(t, anonymousID, cookie.Value)
+	assert.Equal(t, anonymousID, upgradedFrom)
+	assert.Equal(t, "alice", store.sessions[cookie.Value].Get("user"))
+
+	// Unchanged privileges keep the session ID.
+	upgradedFrom = ""
+	resp = serve(cookie, func(http.ResponseWriter, *http.Request) {})
+	assert.Equal(t, cookie.Value, resp.Cookies()[0].Value)
+	assert.Empty(t, upgradedFrom)
+
+	// Destroyed sessions are not saved again.
+	resp = serve(cookie, func(w http.ResponseWriter, r *http.Request) {
+		GetSessionFromContext[string](r).Destroy(w, r)
+	})
+	require.Len(t, resp.Cookies(), 1)
+	assert.Equal(t, -1, resp.Cookies()[0].MaxAge)
+	assert.NotContains(t, store.sessions, cookie.Value)
+}
+
+func TestConfig_MiddlewareInvalid(t *testing.T) {
+	store := newTestStore[string]()
+
+	_, err := NewConfig[string](store).Middleware("")
+	require.ErrorIs(t, err, ErrSessionNameRequired)
+
+	_, err = NewConfig[string](store, WithCookieConfig[string](&CookieConfig{Name: "session"})).Middleware("")
+	require.ErrorIs(t, err, ErrSecureRequired)
+
+	_, err = NewConfig[string](store).Middleware("session")
+	require.NoError(t, err)
+}
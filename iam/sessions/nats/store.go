@@ -137,6 +137,79 @@ func NewStore[T any](opts ...Option) (*Store[T], error) {
 
 // Save persists the session data in NATS KV store
 func (s *Store[T]) Save(w http.ResponseWriter, session *sessions.Session[T]) error {
+	bytes, err := s.marshal(w, session)
+	if err != nil {
+		return err
+	}
+
+	// Store in NATS KV
+	_, err = s.kv.Put(context.Background(), session.ID, bytes)
+	if err != nil {
+		return err
+	}
+
+	s.setCookie(w, session)
+
+	return nil
+}
+
+// SaveVersion persists the session data if the stored session still has the
+// expected version (implements sessions.VersionedStore). The KV revision
+// guards the write, so concurrent saves of the same version cannot both
+// succeed.
+func (s *Store[T]) SaveVersion(w http.ResponseWriter, session *sessions.Session[T], expected uint64) error {
+	ctx := context.Background()
+
+	entry, err := s.kv.Get(ctx, session.ID)
+
+	switch {
+	case errors.Is(err, jetstream.ErrKeyNotFound):
+		if expected != 0 {
+			return sessions.ErrSessionConflict
+		}
+
+		entry = nil
+	case err != nil:
+		return err
+	default:
+		var stored SessionData[T]
+		if err := json.Unmarshal(entry.Value(), &stored); err != nil {
+			return err
+		}
+
+		if stored.Session == nil || stored.Session.Version != expected {
+			return sessions.ErrSessionConflict
+		}
+	}
+
+	session.Version = expected + 1
+
+	bytes, err := s.marshal(w, session)
+	if err == nil {
+		if entry == nil {
+			_, err = s.kv.Create(ctx, session.ID, bytes)
+		} else {
+			_, err = s.kv.Update(ctx, session.ID, bytes, entry.Revision())
+		}
+	}
+
+	if err != nil {
+		session.Version = expected
+
+		if errors.Is(err, jetstream.ErrKeyExists) {
+			return sessions.ErrSessionConflict
+		}
+
+		return err
+	}
+
+	s.setCookie(w, session)
+
+	return nil
+}
+
+// marshal encodes the session with the request metadata
+func (s *Store[T]) marshal(w http.ResponseWriter, session *sessions.Session[T]) ([]byte, error) {
 	// Get client IP and User-Agent
 	ip := w.Header().Get("X-Real-IP")
 	if ip == "" {
@@ -153,19 +226,11 @@ func (s *Store[T]) Save(w http.ResponseWriter, session *sessions.Session[T]) err
 		LastSeen:  time.Now(),
 	}
 
-	// Marshal session data
-	bytes, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-
-	// Store in NATS KV
-	_, err = s.kv.Put(context.Background(), session.ID, bytes)
-	if err != nil {
-		return err
-	}
+	return json.Marshal(data)
+}
 
-	// Set cookie
+// setCookie sets the session cookie
+func (s *Store[T]) setCookie(w http.ResponseWriter, session *sessions.Session[T]) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     session.Name,
 		Value:    session.ID,
@@ -175,8 +240,6 @@ func (s *Store[T]) Save(w http.ResponseWriter, session *sessions.Session[T]) err
 		Secure:   true,
 		SameSite: http.SameSiteLaxMode,
 	})
-
-	return nil
 }
 
 // Load retrieves the session data from NATS KV store
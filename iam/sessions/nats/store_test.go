@@ -164,3 +164,50 @@ func TestStore_InvalidSession(t *testing.T) {
 	_, err = store.Load(r, "test")
 	assert.ErrorIs(t, err, sessions.ErrInvalidSession)
 }
+
+func TestStore_SaveVersion(t *testing.T) {
+	s := startTestServer(t)
+
+	bucket := "test_sessions_version_" + time.Now().Format("150405_000000")
+	store, err := NewStore[string](
+		WithServerURL(getTestServerURL(s)),
+		WithBucketName(bucket),
+		WithMaxAge(3600),
+	)
+	require.NoError(t, err)
+
+	session := sessions.NewSession(store, "test")
+	session.Set("key", "value")
+
+	// First save creates the session
+	require.NoError(t, store.SaveVersion(httptest.NewRecorder(), session, 0))
+	assert.Equal(t, uint64(1), session.Version)
+
+	// Creating it again is a conflict
+	other := sessions.NewSession(store, "test")
+	other.ID = session.ID
+	require.ErrorIs(t, store.SaveVersion(httptest.NewRecorder(), other, 0), sessions.ErrSessionConflict)
+	assert.Equal(t, uint64(0), other.Version)
+
+	// Saving the loaded version succeeds
+	w := httptest.NewRecorder()
+	require.NoError(t, store.SaveVersion(w, session, 1))
+	assert.Equal(t, uint64(2), session.Version)
+	require.Len(t, w.Result().Cookies(), 1)
+
+	// A save based on an outdated version is rejected
+	session.Set("key", "stale")
+	require.ErrorIs(t, store.SaveVersion(httptest.NewRecorder(), session, 1), sessions.ErrSessionConflict)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+
+	loaded, err := store.Load(r, "test")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), loaded.Version)
+	assert.Equal(t, "value", loaded.Get("key"))
+
+	// A destroyed session cannot be saved with a version
+	store.Destroy(httptest.NewRecorder(), r, "test")
+	require.ErrorIs(t, store.SaveVersion(httptest.NewRecorder(), loaded, 2), sessions.ErrSessionConflict)
+}
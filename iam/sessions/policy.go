@@ -0,0 +1,123 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Cookie name prefixes that browsers enforce, see RFC 6265bis, section 4.1.3.
+const (
+	// CookiePrefixSecure requires the Secure attribute.
+	CookiePrefixSecure = "__Secure-"
+	// CookiePrefixHost requires the Secure attribute, path "/" and no Domain.
+	CookiePrefixHost = "__Host-"
+)
+
+// StrictFirstParty returns the cookie settings for an application that is
+// only used on its own site: SameSite=Strict, Secure and HttpOnly. The cookie
+// is host-only unless domain is set, e.g. ".kopexa.com" to share it across
+// subdomains. Strict cookies are not sent on the navigation that follows a
+// redirect from another site, such as an external identity provider; use
+// SameSite=Lax for the login callback in that case.
+func StrictFirstParty(name, domain string) *CookieConfig {
+	return &CookieConfig{
+		Name:     name,
+		Domain:   domain,
+		MaxAge:   DefaultMaxAge,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	}
+}
+
+// EmbeddedIframe returns the cookie settings for an application embedded in
+// an iframe on another site: SameSite=None, which browsers only accept with
+// Secure, and Partitioned, so that browsers blocking third-party cookies
+// still keep a cookie per embedding site.
+func EmbeddedIframe(name, domain string) *CookieConfig {
+	return &CookieConfig{
+		Name:        name,
+		Domain:      domain,
+		MaxAge:      DefaultMaxAge,
+		Secure:      true,
+		HTTPOnly:    true,
+		SameSite:    http.SameSiteNoneMode,
+		Partitioned: true,
+	}
+}
+
+// LocalDev returns the cookie settings for local development over plain
+// HTTP: SameSite=Lax, HttpOnly, no Secure attribute and a host-only cookie,
+// as browsers do not store cookies with Domain=localhost reliably.
+func LocalDev(name string) *CookieConfig {
+	return &CookieConfig{
+		Name:        name,
+		MaxAge:      DefaultMaxAge,
+		HTTPOnly:    true,
+		SameSite:    http.SameSiteLaxMode,
+		Development: true,
+	}
+}
+
+// Validate detects cookie settings that browsers reject or that are insecure
+// outside development:
+//
+//   - SameSite=None and Partitioned require Secure, also in development, as
+//     browsers drop such cookies
+//   - the __Secure- and __Host- name prefixes require Secure, and __Host-
+//     also no Domain
+//   - outside development, Secure and HttpOnly are required and the domain
+//     must not be localhost
+func (c *CookieConfig) Validate() error {
+	if !c.Development {
+		if !c.Secure {
+			return ErrSecureRequired
+		}
+
+		if !c.HTTPOnly {
+			return ErrHTTPOnlyRequired
+		}
+
+		if isLocalhost(c.Domain) {
+			return ErrLocalhostDomain
+		}
+	}
+
+	if c.SameSite == http.SameSiteNoneMode && !c.Secure {
+		return ErrSameSiteNoneRequiresSecure
+	}
+
+	if c.Partitioned && !c.Secure {
+		return ErrPartitionedRequiresSecure
+	}
+
+	if strings.HasPrefix(c.Name, CookiePrefixSecure) && !c.Secure {
+		return ErrCookiePrefix
+	}
+
+	if strings.HasPrefix(c.Name, CookiePrefixHost) && (!c.Secure || c.Domain != "") {
+		return ErrCookiePrefix
+	}
+
+	return nil
+}
+
+// Validate checks the cookie settings of the configuration, see
+// CookieConfig.Validate. A configuration without cookie settings is valid.
+func (c Config[T]) Validate() error {
+	if c.CookieConfig == nil {
+		return nil
+	}
+
+	return c.CookieConfig.Validate()
+}
+
+// isLocalhost reports whether domain is localhost or a subdomain of it.
+func isLocalhost(domain string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+
+	return domain == "localhost" || strings.HasSuffix(domain, ".localhost")
+}
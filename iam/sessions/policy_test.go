@@ -0,0 +1,113 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookiePresets(t *testing.T) {
+	strict := StrictFirstParty("session", ".kopexa.com")
+	assert.Equal(t, http.SameSiteStrictMode, strict.SameSite)
+	assert.True(t, strict.Secure)
+	assert.True(t, strict.HTTPOnly)
+	assert.Equal(t, ".kopexa.com", strict.Domain)
+	assert.NoError(t, strict.Validate())
+
+	embedded := EmbeddedIframe("session", "")
+	assert.Equal(t, http.SameSiteNoneMode, embedded.SameSite)
+	assert.True(t, embedded.Secure)
+	assert.True(t, embedded.Partitioned)
+	assert.NoError(t, embedded.Validate())
+
+	dev := LocalDev("session")
+	assert.False(t, dev.Secure)
+	assert.True(t, dev.Development)
+	assert.Empty(t, dev.Domain)
+	assert.NoError(t, dev.Validate())
+
+	cfg := NewConfig[string](nil, WithCookieConfig[string](LocalDev("session")))
+	assert.NoError(t, cfg.Validate())
+	assert.NoError(t, NewConfig[string](nil).Validate())
+}
+
+func TestCookieConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *CookieConfig)
+		want   error
+	}{
+		{
+			name:   "insecure outside development",
+			modify: func(c *CookieConfig) { c.Secure = false },
+			want:   ErrSecureRequired,
+		},
+		{
+			name:   "script access outside development",
+			modify: func(c *CookieConfig) { c.HTTPOnly = false },
+			want:   ErrHTTPOnlyRequired,
+		},
+		{
+			name:   "localhost outside development",
+			modify: func(c *CookieConfig) { c.Domain = ".localhost" },
+			want:   ErrLocalhostDomain,
+		},
+		{
+			name: "SameSite=None without Secure in development",
+			modify: func(c *CookieConfig) {
+				c.Development = true
+				c.Secure = false
+				c.SameSite = http.SameSiteNoneMode
+			},
+			want: ErrSameSiteNoneRequiresSecure,
+		},
+		{
+			name: "partitioned without Secure in development",
+			modify: func(c *CookieConfig) {
+				c.Development = true
+				c.Secure = false
+				c.Partitioned = true
+			},
+			want: ErrPartitionedRequiresSecure,
+		},
+		{
+			name: "__Secure- prefix without Secure",
+			modify: func(c *CookieConfig) {
+				c.Development = true
+				c.Secure = false
+				c.Name = "__Secure-session"
+			},
+			want: ErrCookiePrefix,
+		},
+		{
+			name:   "__Host- prefix with domain",
+			modify: func(c *CookieConfig) { c.Name = "__Host-session" },
+			want:   ErrCookiePrefix,
+		},
+		{
+			name: "__Host- prefix without domain",
+			modify: func(c *CookieConfig) {
+				c.Name = "__Host-session"
+				c.Domain = ""
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := StrictFirstParty("session", ".kopexa.com")
+			tt.modify(c)
+
+			err := c.Validate()
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package redis implements a sessions.Store that keeps sessions in Redis
+// using go-redis. Only the session ID is sent to the client; the session
+// expires in Redis at its ExpiresAt time.
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/sessions"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Default configuration values
+const (
+	// DefaultAddr is the default address of the Redis server
+	DefaultAddr = "localhost:6379"
+
+	// DefaultKeyPrefix is the default prefix of the session keys
+	DefaultKeyPrefix = "session:"
+
+	// DefaultPoolSize is the default maximum number of open connections
+	DefaultPoolSize = 10
+
+	// DefaultTimeout is the default timeout of dialing and of each command
+	DefaultTimeout = 5 * time.Second
+)
+
+// Store implements the sessions.Store interface using Redis
+type Store[T any] struct {
+	client goredis.UniversalClient
+	// owned reports whether the store created the client and closes it
+	owned  bool
+	config Config
+}
+
+// Ensure that Store implements sessions.Store.
+var _ sessions.Store[any] = (*Store[any])(nil)
+
+// Config contains the configuration for the Redis store
+type Config struct {
+	// Addr is the host:port address of the Redis server
+	Addr string
+
+	// Username is the ACL user; empty authenticates with Password only
+	Username string
+
+	// Password authenticates the connections; empty disables AUTH
+	Password string
+
+	// DB is the database selected after connecting
+	DB int
+
+	// PoolSize is the maximum number of open connections
+	PoolSize int
+
+	// Timeout limits dialing and each command
+	Timeout time.Duration
+
+	// TLSConfig enables TLS, e.g. for Azure Cache for Redis
+	TLSConfig *tls.Config
+
+	// KeyPrefix is prepended to the session ID to form the key
+	KeyPrefix string
+
+	// EncryptionKey encrypts the stored sessions like the cookie store if
+	// set. It must be a 32 or 64 character string.
+	EncryptionKey string
+
+	// Cookie sets the Domain, Secure, HttpOnly, SameSite and Partitioned
+	// attributes of the session cookie. The cookie expires with the session;
+	// Name and MaxAge are ignored.
+	Cookie *sessions.CookieConfig
+
+	// Client is used instead of a client created from Addr, Username,
+	// Password, DB, PoolSize, Timeout and TLSConfig. The store does not
+	// close it.
+	Client goredis.UniversalClient
+}
+
+// Option is a function that configures a Store
+type Option func(*Config)
+
+// WithAddr sets the address of the Redis server
+func WithAddr(addr string) Option {
+	return func(c *Config) {
+		c.Addr = addr
+	}
+}
+
+// WithCredentials sets the ACL user and password. Pass an empty username to
+// authenticate with the password only.
+func WithCredentials(username, password string) Option {
+	return func(c *Config) {
+		c.Username = username
+		c.Password = password
+	}
+}
+
+// WithDB sets the database
+func WithDB(db int) Option {
+	return func(c *Config) {
+		c.DB = db
+	}
+}
+
+// WithPoolSize sets the maximum number of open connections
+func WithPoolSize(size int) Option {
+	return func(c *Config) {
+		c.PoolSize = size
+	}
+}
+
+// WithTimeout sets the timeout of dialing and of each command
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeout = timeout
+	}
+}
+
+// WithTLSConfig enables TLS with the given configuration
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Config) {
+		c.TLSConfig = config
+	}
+}
+
+// WithKeyPrefix sets the prefix of the session keys
+func WithKeyPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.KeyPrefix = prefix
+	}
+}
+
+// WithEncryptionKey enables encryption at rest with the given key
+func WithEncryptionKey(key string) Option {
+	return func(c *Config) {
+		c.EncryptionKey = key
+	}
+}
+
+// WithPolicy sets the attributes of the session cookie from a cookie policy
+// preset such as sessions.StrictFirstParty, sessions.EmbeddedIframe or
+// sessions.LocalDev. Defaults to a Secure, HttpOnly cookie with SameSite=Lax;
+// nil keeps the default.
+func WithPolicy(policy *sessions.CookieConfig) Option {
+	return func(c *Config) {
+		if policy != nil {
+			c.Cookie = policy
+		}
+	}
+}
+
+// WithClient uses an existing client, e.g. a cluster or sentinel client
+// shared with other components. The connection options are ignored and the
+// store does not close the client.
+func WithClient(client goredis.UniversalClient) Option {
+	return func(c *Config) {
+		c.Client = client
+	}
+}
+
+// Validate checks the configuration
+func (c *Config) Validate() error {
+	if c.Cookie != nil {
+		if err := c.Cookie.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.EncryptionKey != "" && len(c.EncryptionKey) < sessions.DefaultKeyLength {
+		return sessions.ErrEncryptionKeyTooShort
+	}
+
+	if c.Client != nil {
+		return nil
+	}
+
+	if c.Addr == "" {
+		return sessions.ErrServerURLRequired
+	}
+
+	if c.PoolSize <= 0 {
+		return sessions.ErrPoolSizeMustBePositive
+	}
+
+	if c.Timeout <= 0 {
+		return sessions.ErrTimeoutMustBePositive
+	}
+
+	return nil
+}
+
+// NewStore creates a new Redis store with the given options. Connections are
+// opened on first use.
+func NewStore[T any](opts ...Option) (*Store[T], error) {
+	config := Config{
+		Addr:      DefaultAddr,
+		PoolSize:  DefaultPoolSize,
+		Timeout:   DefaultTimeout,
+		KeyPrefix: DefaultKeyPrefix,
+		Cookie: &sessions.CookieConfig{
+			Secure:   true,
+			HTTPOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	store := &Store[T]{
+		client: config.Client,
+		config: config,
+	}
+
+	if store.client == nil {
+		store.client = goredis.NewClient(&goredis.Options{
+			Addr:         config.Addr,
+			Username:     config.Username,
+			Password:     config.Password,
+			DB:           config.DB,
+			PoolSize:     config.PoolSize,
+			DialTimeout:  config.Timeout,
+			ReadTimeout:  config.Timeout,
+			WriteTimeout: config.Timeout,
+			TLSConfig:    config.TLSConfig,
+		})
+		store.owned = true
+	}
+
+	return store, nil
+}
+
+// Save persists the session data in Redis. The key expires at the
+// ExpiresAt time of the session; already expired sessions are rejected
+// with sessions.ErrSessionExpired.
+func (s *Store[T]) Save(w http.ResponseWriter, session *sessions.Session[T]) error {
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return sessions.ErrSessionExpired
+	}
+
+	value, err := s.marshal(session)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.Set(context.Background(), s.key(session.ID), value, ttl).Err(); err != nil {
+		return err
+	}
+
+	http.SetCookie(w, s.cookie(session.Name, session.ID, int(math.Ceil(ttl.Seconds()))))
+
+	return nil
+}
+
+// Load retrieves the session data from Redis
+func (s *Store[T]) Load(r *http.Request, name string) (*sessions.Session[T], error) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		if errors.Is(err, http.ErrNoCookie) {
+			return nil, sessions.ErrInvalidSession
+		}
+
+		return nil, err
+	}
+
+	value, err := s.client.Get(r.Context(), s.key(cookie.Value)).Result()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, sessions.ErrInvalidSession
+		}
+
+		return nil, err
+	}
+
+	session, err := s.unmarshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.ID != cookie.Value {
+		return nil, sessions.ErrInvalidSession
+	}
+
+	if session.IsExpired() {
+		return nil, sessions.ErrSessionExpired
+	}
+
+	return session, nil
+}
+
+// Destroy removes the session data from Redis and clears the cookie
+func (s *Store[T]) Destroy(w http.ResponseWriter, r *http.Request, name string) {
+	http.SetCookie(w, s.cookie(name, "", -1))
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return
+	}
+
+	_ = s.client.Del(r.Context(), s.key(cookie.Value)).Err()
+}
+
+// Close closes the client unless it was passed with WithClient
+func (s *Store[T]) Close() error {
+	if !s.owned {
+		return nil
+	}
+
+	return s.client.Close()
+}
+
+// cookie returns the session cookie with the attributes of the cookie policy
+func (s *Store[T]) cookie(name, value string, maxAge int) *http.Cookie {
+	policy := s.config.Cookie
+
+	return &http.Cookie{
+		Name:        name,
+		Value:       value,
+		Path:        sessions.CookiePath,
+		Domain:      policy.Domain,
+		MaxAge:      maxAge,
+		Secure:      policy.Secure,
+		HttpOnly:    policy.HTTPOnly,
+		SameSite:    policy.SameSite,
+		Partitioned: policy.Partitioned,
+	}
+}
+
+// key returns the Redis key of the session with the given ID
+func (s *Store[T]) key(id string) string {
+	return s.config.KeyPrefix + id
+}
+
+// marshal encodes the session, encrypted if an encryption key is configured
+func (s *Store[T]) marshal(session *sessions.Session[T]) (string, error) {
+	if s.config.EncryptionKey != "" {
+		return sessions.EncodeSession(session, s.config.EncryptionKey)
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// unmarshal decodes a session encoded by marshal
+func (s *Store[T]) unmarshal(value string) (*sessions.Session[T], error) {
+	if s.config.EncryptionKey != "" {
+		return sessions.DecodeSession[T](value, s.config.EncryptionKey)
+	}
+
+	var session sessions.Session[T]
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
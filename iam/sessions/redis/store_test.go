@@ -0,0 +1,165 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package redis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/kopexa-grc/common/iam/sessions"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_SaveLoadDestroy(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+
+	store, err := NewStore[string](WithAddr(server.Addr()), WithCredentials("", "secret"), WithDB(2))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	session := sessions.NewSession(store, "test")
+	session.ExpiresAt = time.Now().Add(10 * time.Minute)
+	session.Set("key", "value")
+
+	w := httptest.NewRecorder()
+	require.NoError(t, store.Save(w, session))
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, session.ID, cookies[0].Value)
+	assert.Equal(t, 600, cookies[0].MaxAge)
+	assert.True(t, cookies[0].Secure)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+
+	server.Select(2)
+	assert.InDelta(t, 10*time.Minute, server.TTL(DefaultKeyPrefix+session.ID), float64(time.Second))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(cookies[0])
+
+	loaded, err := store.Load(r, "test")
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, loaded.ID)
+	assert.Equal(t, "value", loaded.Get("key"))
+
+	store.Destroy(httptest.NewRecorder(), r, "test")
+
+	_, err = store.Load(r, "test")
+	assert.ErrorIs(t, err, sessions.ErrInvalidSession)
+}
+
+func TestStore_Policy(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	store, err := NewStore[string](WithAddr(server.Addr()), WithPolicy(sessions.EmbeddedIframe("test", "app.example.com")))
+	require.NoError(t, err)
+
+	session := sessions.NewSession(store, "test")
+
+	w := httptest.NewRecorder()
+	require.NoError(t, store.Save(w, session))
+
+	cookie := w.Result().Cookies()[0]
+	assert.Equal(t, "app.example.com", cookie.Domain)
+	assert.Equal(t, http.SameSiteNoneMode, cookie.SameSite)
+	assert.True(t, cookie.Secure)
+	assert.True(t, cookie.Partitioned)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(cookie)
+
+	w = httptest.NewRecorder()
+	store.Destroy(w, r, "test")
+
+	cleared := w.Result().Cookies()[0]
+	assert.Equal(t, -1, cleared.MaxAge)
+	assert.Equal(t, "app.example.com", cleared.Domain)
+	assert.True(t, cleared.Partitioned)
+
+	_, err = NewStore[string](WithPolicy(&sessions.CookieConfig{SameSite: http.SameSiteNoneMode, Development: true}))
+	assert.ErrorIs(t, err, sessions.ErrSameSiteNoneRequiresSecure)
+}
+
+func TestStore_Encryption(t *testing.T) {
+	server := miniredis.RunT(t)
+	key := strings.Repeat("k", sessions.DefaultKeyLength)
+
+	store, err := NewStore[string](WithAddr(server.Addr()), WithEncryptionKey(key), WithKeyPrefix("app:"))
+	require.NoError(t, err)
+
+	session := sessions.NewSession(store, "test")
+	session.Set("secret", "top-secret")
+
+	w := httptest.NewRecorder()
+	require.NoError(t, store.Save(w, session))
+
+	stored, err := server.Get("app:" + session.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, stored, "top-secret")
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+
+	loaded, err := store.Load(r, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "top-secret", loaded.Get("secret"))
+}
+
+func TestStore_Errors(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+
+	store, err := NewStore[string](WithAddr(server.Addr()), WithCredentials("", "wrong"))
+	require.NoError(t, err)
+
+	session := sessions.NewSession(store, "test")
+
+	err = store.Save(httptest.NewRecorder(), session)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WRONGPASS")
+
+	session.ExpiresAt = time.Now().Add(-time.Second)
+	assert.ErrorIs(t, store.Save(httptest.NewRecorder(), session), sessions.ErrSessionExpired)
+
+	_, err = store.Load(httptest.NewRequest("GET", "/", nil), "test")
+	assert.ErrorIs(t, err, sessions.ErrInvalidSession)
+}
+
+func TestStore_WithClient(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	store, err := NewStore[string](WithClient(client), WithAddr(""))
+	require.NoError(t, err)
+
+	session := sessions.NewSession(store, "test")
+	require.NoError(t, store.Save(httptest.NewRecorder(), session))
+	assert.True(t, server.Exists(DefaultKeyPrefix+session.ID))
+
+	require.NoError(t, store.Close())
+	assert.NoError(t, client.Ping(t.Context()).Err(), "the store must not close a shared client")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	_, err := NewStore[string](WithAddr(""))
+	assert.ErrorIs(t, err, sessions.ErrServerURLRequired)
+
+	_, err = NewStore[string](WithPoolSize(0))
+	assert.ErrorIs(t, err, sessions.ErrPoolSizeMustBePositive)
+
+	_, err = NewStore[string](WithTimeout(0))
+	assert.ErrorIs(t, err, sessions.ErrTimeoutMustBePositive)
+
+	_, err = NewStore[string](WithEncryptionKey("short"))
+	assert.ErrorIs(t, err, sessions.ErrEncryptionKeyTooShort)
+}
@@ -0,0 +1,257 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RevocationChecker decides whether a session of a user is revoked. It is
+// consulted on every Load of a store wrapped by WithRevocation, so
+// implementations should be cheap for users without revocations.
+type RevocationChecker interface {
+	// IsRevoked reports whether a session of userID issued at issuedAt has
+	// been revoked.
+	IsRevoked(ctx context.Context, userID string, issuedAt time.Time) (bool, error)
+}
+
+// Revoker revokes sessions of a user.
+type Revoker interface {
+	// RevokeUserSessions revokes all sessions of userID issued before before.
+	RevokeUserSessions(ctx context.Context, userID string, before time.Time) error
+}
+
+// RevocationList is a RevocationChecker that can also revoke sessions.
+type RevocationList interface {
+	RevocationChecker
+	Revoker
+}
+
+// UserIDFunc extracts the user ID from a session. It returns an empty string
+// for anonymous sessions, which are never revoked.
+type UserIDFunc[T any] func(session *Session[T]) string
+
+// WithRevocation wraps the store of the config so that Load rejects sessions
+// revoked according to checker with ErrSessionRevoked. The issued-at time of a
+// session is its CreatedAt, which Upgrade and Rotate reset.
+//
+// Example:
+//
+//	revocations := sessions.NewMemoryRevocationList()
+//	config := sessions.NewConfig(store, sessions.WithRevocation(revocations,
+//		func(s *sessions.Session[string]) string { return s.Get("user_id") },
+//	))
+func WithRevocation[T any](checker RevocationChecker, userID UserIDFunc[T]) Option[T] {
+	return func(c *Config[T]) {
+		c.Revocation = checker
+		c.UserID = userID
+	}
+}
+
+// RevokeUserSessions revokes all sessions of userID issued before before
+// through the configured revocation list. It returns ErrRevocationNotSupported
+// if no revocation list is configured.
+func (c Config[T]) RevokeUserSessions(ctx context.Context, userID string, before time.Time) error {
+	revoker, ok := c.Revocation.(Revoker)
+	if !ok {
+		return ErrRevocationNotSupported
+	}
+
+	return revoker.RevokeUserSessions(ctx, userID, before)
+}
+
+// RevocationStore wraps store so that Load rejects revoked sessions.
+// NewConfig applies it automatically when WithRevocation is used.
+func RevocationStore[T any](store Store[T], checker RevocationChecker, userID UserIDFunc[T]) Store[T] {
+	if checker == nil || userID == nil {
+		return store
+	}
+
+	return &revocationStore[T]{store: store, checker: checker, userID: userID}
+}
+
+type revocationStore[T any] struct {
+	store   Store[T]
+	checker RevocationChecker
+	userID  UserIDFunc[T]
+}
+
+// Save implements Store.
+func (s *revocationStore[T]) Save(w http.ResponseWriter, session *Session[T]) error {
+	return s.store.Save(w, session)
+}
+
+// SaveVersion implements VersionedStore.
+func (s *revocationStore[T]) SaveVersion(w http.ResponseWriter, session *Session[T], expected uint64) error {
+	return saveVersion(s.store, w, session, expected)
+}
+
+// Load implements Store. Errors of the checker are returned, so sessions
+// fail closed if the revocation list is unavailable.
+func (s *revocationStore[T]) Load(r *http.Request, name string) (*Session[T], error) {
+	session, err := s.store.Load(r, name)
+	if err != nil || session == nil {
+		return session, err
+	}
+
+	uid := s.userID(session)
+	if uid == "" {
+		return session, nil
+	}
+
+	session.mu.RLock()
+	issuedAt := session.CreatedAt
+	session.mu.RUnlock()
+
+	revoked, err := s.checker.IsRevoked(r.Context(), uid, issuedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return nil, ErrSessionRevoked
+	}
+
+	return session, nil
+}
+
+// Destroy implements Store.
+func (s *revocationStore[T]) Destroy(w http.ResponseWriter, r *http.Request, name string) {
+	s.store.Destroy(w, r, name)
+}
+
+// Defaults of the in-memory revocation list.
+const (
+	// DefaultRevocationCapacity is the number of users the bloom filter is sized for.
+	DefaultRevocationCapacity = 10000
+	// DefaultRevocationFalsePositiveRate is the target false positive rate of the bloom filter.
+	DefaultRevocationFalsePositiveRate = 0.01
+)
+
+// MemoryRevocationList is an in-memory RevocationList. A bloom filter in
+// front of the revocation map answers the common case of a user without
+// revocations without taking a lock. It is suitable for single instance
+// deployments and tests; multi instance deployments need a shared list.
+type MemoryRevocationList struct {
+	mu       sync.RWMutex
+	cutoffs  map[string]time.Time
+	filter   atomic.Pointer[bloomFilter]
+	capacity int
+	fpRate   float64
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// MemoryRevocationOption configures a MemoryRevocationList.
+type MemoryRevocationOption func(*MemoryRevocationList)
+
+// WithRevocationCapacity sizes the bloom filter for n revoked users.
+func WithRevocationCapacity(n int, falsePositiveRate float64) MemoryRevocationOption {
+	return func(l *MemoryRevocationList) {
+		l.capacity = n
+		l.fpRate = falsePositiveRate
+	}
+}
+
+// WithRevocationTTL drops revocations older than ttl. Set it to the maximum
+// session lifetime; sessions older than that are expired anyway.
+func WithRevocationTTL(ttl time.Duration) MemoryRevocationOption {
+	return func(l *MemoryRevocationList) {
+		l.ttl = ttl
+	}
+}
+
+// NewMemoryRevocationList creates an empty in-memory revocation list.
+func NewMemoryRevocationList(opts ...MemoryRevocationOption) *MemoryRevocationList {
+	l := &MemoryRevocationList{
+		cutoffs:  make(map[string]time.Time),
+		capacity: DefaultRevocationCapacity,
+		fpRate:   DefaultRevocationFalsePositiveRate,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	l.filter.Store(newBloomFilter(l.capacity, l.fpRate))
+
+	return l
+}
+
+// RevokeUserSessions implements Revoker. Revoking again with an earlier
+// cutoff keeps the later one.
+func (l *MemoryRevocationList) RevokeUserSessions(_ context.Context, userID string, before time.Time) error {
+	if userID == "" {
+		return ErrMissingUserID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pruneLocked()
+
+	if before.After(l.cutoffs[userID]) {
+		l.cutoffs[userID] = before
+	}
+
+	l.filter.Load().add(userID)
+
+	return nil
+}
+
+// IsRevoked implements RevocationChecker.
+func (l *MemoryRevocationList) IsRevoked(_ context.Context, userID string, issuedAt time.Time) (bool, error) {
+	if !l.filter.Load().mayContain(userID) {
+		return false, nil
+	}
+
+	l.mu.RLock()
+	cutoff, ok := l.cutoffs[userID]
+	l.mu.RUnlock()
+
+	return ok && issuedAt.Before(cutoff), nil
+}
+
+// Len returns the number of users with revoked sessions.
+func (l *MemoryRevocationList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.cutoffs)
+}
+
+// pruneLocked removes revocations older than the TTL and rebuilds the bloom
+// filter, which cannot forget entries on its own.
+func (l *MemoryRevocationList) pruneLocked() {
+	if l.ttl <= 0 {
+		return
+	}
+
+	threshold := l.now().Add(-l.ttl)
+	pruned := false
+
+	for userID, cutoff := range l.cutoffs {
+		if cutoff.Before(threshold) {
+			delete(l.cutoffs, userID)
+
+			pruned = true
+		}
+	}
+
+	if !pruned {
+		return
+	}
+
+	filter := newBloomFilter(l.capacity, l.fpRate)
+	for userID := range l.cutoffs {
+		filter.add(userID)
+	}
+
+	l.filter.Store(filter)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingChecker struct{}
+
+func (failingChecker) IsRevoked(context.Context, string, time.Time) (bool, error) {
+	return false, errors.New("revocation list unavailable")
+}
+
+func sessionUserID(s *Session[string]) string {
+	return s.Get("user_id")
+}
+
+func TestMemoryRevocationList(t *testing.T) {
+	ctx := context.Background()
+	list := NewMemoryRevocationList()
+	cutoff := time.Now()
+
+	revoked, err := list.IsRevoked(ctx, "user-1", cutoff.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, list.RevokeUserSessions(ctx, "user-1", cutoff))
+	require.ErrorIs(t, list.RevokeUserSessions(ctx, "", cutoff), ErrMissingUserID)
+
+	revoked, err = list.IsRevoked(ctx, "user-1", cutoff.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = list.IsRevoked(ctx, "user-1", cutoff.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, revoked, "sessions issued after the cutoff stay valid")
+
+	revoked, err = list.IsRevoked(ctx, "user-2", cutoff.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// An earlier cutoff does not shorten an existing revocation.
+	require.NoError(t, list.RevokeUserSessions(ctx, "user-1", cutoff.Add(-time.Hour)))
+
+	revoked, err = list.IsRevoked(ctx, "user-1", cutoff.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestMemoryRevocationListTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	list := NewMemoryRevocationList(WithRevocationTTL(time.Hour))
+	list.now = func() time.Time { return now }
+
+	require.NoError(t, list.RevokeUserSessions(ctx, "old", now.Add(-2*time.Hour)))
+	require.NoError(t, list.RevokeUserSessions(ctx, "new", now))
+	assert.Equal(t, 1, list.Len(), "expired revocations are pruned on write")
+
+	revoked, err := list.IsRevoked(ctx, "new", now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = list.IsRevoked(ctx, "old", now.Add(-3*time.Hour))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestBloomFilter(t *testing.T) {
+	filter := newBloomFilter(1000, 0.01)
+
+	for i := range 1000 {
+		filter.add(fmt.Sprintf("user-%d", i))
+	}
+
+	for i := range 1000 {
+		assert.True(t, filter.mayContain(fmt.Sprintf("user-%d", i)))
+	}
+
+	falsePositives := 0
+
+	for i := range 10000 {
+		if filter.mayContain(fmt.Sprintf("other-%d", i)) {
+			falsePositives++
+		}
+	}
+
+	assert.Less(t, falsePositives, 300)
+}
+
+func TestConfigWithRevocation(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore[string]()
+	list := NewMemoryRevocationList()
+
+	config := NewConfig[string](store, WithRevocation(list, sessionUserID))
+
+	session := NewSession[string](store, "session")
+	session.Set("user_id", "user-1")
+	store.session = session
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	loaded, err := config.Store.Load(req, "session")
+	require.NoError(t, err)
+	assert.Same(t, session, loaded)
+
+	require.NoError(t, config.RevokeUserSessions(ctx, "user-1", time.Now()))
+
+	_, err = config.Store.Load(req, "session")
+	require.ErrorIs(t, err, ErrSessionRevoked)
+
+	// A new login after the revocation is not affected.
+	session.Rotate()
+
+	_, err = config.Store.Load(req, "session")
+	require.NoError(t, err)
+
+	// Anonymous sessions are never checked.
+	store.session = NewSession[string](store, "session")
+	store.session.CreatedAt = time.Now().Add(-time.Hour)
+
+	_, err = config.Store.Load(req, "session")
+	require.NoError(t, err)
+}
+
+func TestConfigWithRevocationFailsClosed(t *testing.T) {
+	store := newMockStore[string]()
+	config := NewConfig[string](store, WithRevocation(failingChecker{}, sessionUserID))
+
+	store.session = NewSession[string](store, "session")
+	store.session.Set("user_id", "user-1")
+
+	_, err := config.Store.Load(httptest.NewRequest("GET", "/", nil), "session")
+	require.Error(t, err)
+
+	require.ErrorIs(t, config.RevokeUserSessions(context.Background(), "user-1", time.Now()), ErrRevocationNotSupported)
+}
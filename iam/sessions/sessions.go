@@ -59,8 +59,17 @@ type Session[T any] struct {
 	// ExpiresAt is the timestamp when the session will expire
 	ExpiresAt time.Time `json:"expiresAt"`
 
+	// Version is incremented on every versioned save, see VersionedStore
+	Version uint64 `json:"version,omitempty"`
+
+	// Flashes contains one-time messages, see Flash
+	Flashes map[string][]T `json:"flashes,omitempty"`
+
 	mu    sync.RWMutex
 	store Store[T]
+
+	// compressAbove is the compression threshold set by SizeGuard
+	compressAbove int
 }
 
 // NewSession creates a new session with the given store and name
@@ -230,7 +239,15 @@ func decrypt(data []byte, key string) ([]byte, error) {
 }
 
 // EncodeSession encodes the session data to a base64 string
-func EncodeSession[T any](session *Session[T], key string) (string, error) {
+func EncodeSession[T any](session *Session[T], key string) (encoded string, err error) {
+	if m := observeCodec(); m != nil {
+		start := time.Now()
+
+		defer func() {
+			m.SessionEncoded(time.Since(start), len(encoded), err)
+		}()
+	}
+
 	data, err := json.Marshal(session)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal session: %w", err)
@@ -245,7 +262,15 @@ func EncodeSession[T any](session *Session[T], key string) (string, error) {
 }
 
 // DecodeSession decodes the session data from a base64 string
-func DecodeSession[T any](data string, key string) (*Session[T], error) {
+func DecodeSession[T any](data string, key string) (session *Session[T], err error) {
+	if m := observeCodec(); m != nil {
+		start := time.Now()
+
+		defer func() {
+			m.SessionDecoded(time.Since(start), len(data), err)
+		}()
+	}
+
 	decoded, err := base64.URLEncoding.DecodeString(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode session: %w", err)
@@ -256,10 +281,10 @@ func DecodeSession[T any](data string, key string) (*Session[T], error) {
 		return nil, fmt.Errorf("failed to decrypt session: %w", err)
 	}
 
-	var session Session[T]
-	if err := json.Unmarshal(decrypted, &session); err != nil {
+	session = &Session[T]{}
+	if err := json.Unmarshal(decrypted, session); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
 	}
 
-	return &session, nil
+	return session, nil
 }
@@ -0,0 +1,246 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Default limits of SizeGuard.
+const (
+	// DefaultCompressThreshold is the payload size in bytes above which
+	// SizeGuard compresses sessions.
+	DefaultCompressThreshold = 1024
+
+	// DefaultMaxPayloadSize is the payload size in bytes above which SizeGuard
+	// rejects sessions. Encryption and base64 encoding grow the payload by
+	// about a third, so this keeps cookie sessions below the 4096 bytes
+	// browsers accept.
+	DefaultMaxPayloadSize = 3000
+
+	// maxInflatedSize limits the size of a decompressed session, so that a
+	// forged payload cannot exhaust memory.
+	maxInflatedSize = 1 << 20
+)
+
+// PayloadTooLargeError is returned by SizeGuard for sessions whose payload
+// exceeds the maximum size. It matches ErrPayloadTooLarge with errors.Is.
+type PayloadTooLargeError struct {
+	// Size is the payload size in bytes, after compression.
+	Size int
+	// Max is the configured maximum payload size in bytes.
+	Max int
+}
+
+// Error implements error.
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %d bytes, max %d bytes", ErrPayloadTooLarge, e.Size, e.Max)
+}
+
+// Unwrap returns ErrPayloadTooLarge.
+func (e *PayloadTooLargeError) Unwrap() error {
+	return ErrPayloadTooLarge
+}
+
+// SizeGuardOption configures SizeGuard.
+type SizeGuardOption func(*sizeGuardOptions)
+
+type sizeGuardOptions struct {
+	compressAbove int
+	maxSize       int
+	metrics       Metrics
+}
+
+// WithCompressThreshold sets the payload size in bytes above which sessions
+// are compressed. Zero disables compression.
+func WithCompressThreshold(n int) SizeGuardOption {
+	return func(o *sizeGuardOptions) {
+		o.compressAbove = n
+	}
+}
+
+// WithMaxPayloadSize sets the payload size in bytes above which sessions are
+// rejected. Zero disables the limit.
+func WithMaxPayloadSize(n int) SizeGuardOption {
+	return func(o *sizeGuardOptions) {
+		o.maxSize = n
+	}
+}
+
+// WithSizeMetrics reports the payload size of every saved session to m.
+func WithSizeMetrics(m Metrics) SizeGuardOption {
+	return func(o *sizeGuardOptions) {
+		o.metrics = m
+	}
+}
+
+// WithSizeGuard guards the store of the config with SizeGuard. The metrics
+// of WithMetrics are used unless WithSizeMetrics is given.
+func WithSizeGuard[T any](opts ...SizeGuardOption) Option[T] {
+	return func(c *Config[T]) {
+		c.SizeGuard = append([]SizeGuardOption{}, opts...)
+	}
+}
+
+// SizeGuard wraps store so that session payloads are compressed above
+// DefaultCompressThreshold and sessions with payloads above
+// DefaultMaxPayloadSize are not saved; Save returns a *PayloadTooLargeError
+// instead. NewConfig applies it automatically when WithSizeGuard is used.
+//
+// The payload is the JSON encoding of the session, which all stores of this
+// module persist. Compression is part of that encoding, so it applies to any
+// store that encodes sessions with encoding/json, and compressed sessions
+// decode transparently, with or without the guard.
+//
+// Example:
+//
+//	store = sessions.SizeGuard(store,
+//		sessions.WithCompressThreshold(512),
+//		sessions.WithMaxPayloadSize(8192),
+//		sessions.WithSizeMetrics(metrics),
+//	)
+func SizeGuard[T any](store Store[T], opts ...SizeGuardOption) Store[T] {
+	o := sizeGuardOptions{
+		compressAbove: DefaultCompressThreshold,
+		maxSize:       DefaultMaxPayloadSize,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &sizeGuardStore[T]{store: store, opts: o}
+}
+
+type sizeGuardStore[T any] struct {
+	store Store[T]
+	opts  sizeGuardOptions
+}
+
+// Save implements Store.
+func (s *sizeGuardStore[T]) Save(w http.ResponseWriter, session *Session[T]) error {
+	if err := s.check(session); err != nil {
+		return err
+	}
+
+	return s.store.Save(w, session)
+}
+
+// SaveVersion implements VersionedStore.
+func (s *sizeGuardStore[T]) SaveVersion(w http.ResponseWriter, session *Session[T], expected uint64) error {
+	if err := s.check(session); err != nil {
+		return err
+	}
+
+	return saveVersion(s.store, w, session, expected)
+}
+
+// Load implements Store.
+func (s *sizeGuardStore[T]) Load(r *http.Request, name string) (*Session[T], error) {
+	session, err := s.store.Load(r, name)
+	if session != nil {
+		session.compressAbove = s.opts.compressAbove
+	}
+
+	return session, err
+}
+
+// Destroy implements Store.
+func (s *sizeGuardStore[T]) Destroy(w http.ResponseWriter, r *http.Request, name string) {
+	s.store.Destroy(w, r, name)
+}
+
+// check enables compression for session and rejects it if its payload is
+// too large.
+func (s *sizeGuardStore[T]) check(session *Session[T]) error {
+	session.compressAbove = s.opts.compressAbove
+
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	if s.opts.maxSize > 0 && len(payload) > s.opts.maxSize {
+		err = &PayloadTooLargeError{Size: len(payload), Max: s.opts.maxSize}
+	}
+
+	if s.opts.metrics != nil {
+		s.opts.metrics.SessionPayload(len(payload), bytes.HasPrefix(payload, compressedPrefix), err)
+	}
+
+	return err
+}
+
+// compressedPrefix starts the JSON encoding of a compressed session.
+var compressedPrefix = []byte(`{"gzip":`)
+
+// compressedSession is the JSON encoding of a compressed session.
+type compressedSession struct {
+	Gzip []byte `json:"gzip"`
+}
+
+// plainSession has the fields of Session without its JSON methods.
+type plainSession[T any] Session[T]
+
+// MarshalJSON encodes the session. Sessions guarded by SizeGuard whose
+// encoding exceeds the compression threshold are encoded as gzip
+// compressed JSON, if that is smaller.
+func (s *Session[T]) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal((*plainSession[T])(s))
+	if err != nil || s.compressAbove <= 0 || len(data) <= s.compressAbove {
+		return data, err
+	}
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	compressed, err := json.Marshal(compressedSession{Gzip: buf.Bytes()})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(compressed) >= len(data) {
+		return data, nil
+	}
+
+	return compressed, nil
+}
+
+// UnmarshalJSON decodes plain and compressed sessions.
+func (s *Session[T]) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, compressedPrefix) {
+		var compressed compressedSession
+		if err := json.Unmarshal(data, &compressed); err != nil {
+			return err
+		}
+
+		zr, err := gzip.NewReader(bytes.NewReader(compressed.Gzip))
+		if err != nil {
+			return fmt.Errorf("failed to decompress session: %w", err)
+		}
+
+		if data, err = io.ReadAll(io.LimitReader(zr, maxInflatedSize+1)); err != nil {
+			return fmt.Errorf("failed to decompress session: %w", err)
+		}
+
+		if len(data) > maxInflatedSize {
+			return &PayloadTooLargeError{Size: len(data), Max: maxInflatedSize}
+		}
+	}
+
+	return json.Unmarshal(data, (*plainSession[T])(s))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeGuard_Compress(t *testing.T) {
+	store := newMockStore[string]()
+	guarded := SizeGuard[string](store)
+
+	session := NewSession(guarded, "test")
+	session.Set("small", "value")
+	require.NoError(t, session.Save(httptest.NewRecorder()))
+
+	payload, err := json.Marshal(session)
+	require.NoError(t, err)
+	assert.False(t, bytes.HasPrefix(payload, compressedPrefix))
+
+	session.Set("large", strings.Repeat("permission:read ", 200))
+	require.NoError(t, session.Save(httptest.NewRecorder()))
+
+	payload, err = json.Marshal(session)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(payload, compressedPrefix))
+	assert.Less(t, len(payload), DefaultCompressThreshold)
+
+	var decoded Session[string]
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+	assert.Equal(t, session.ID, decoded.ID)
+	assert.Equal(t, session.Get("large"), decoded.Get("large"))
+
+	key := strings.Repeat("k", DefaultKeyLength)
+	encoded, err := EncodeSession(session, key)
+	require.NoError(t, err)
+
+	roundTrip, err := DecodeSession[string](encoded, key)
+	require.NoError(t, err)
+	assert.Equal(t, session.Values, roundTrip.Values)
+}
+
+func TestSizeGuard_Reject(t *testing.T) {
+	store := newMockStore[string]()
+	guarded := SizeGuard[string](store, WithMaxPayloadSize(512))
+
+	noise := make([]byte, 512)
+	_, err := rand.Read(noise)
+	require.NoError(t, err)
+
+	session := NewSession(guarded, "test")
+	session.Set("noise", hex.EncodeToString(noise))
+
+	err = session.Save(httptest.NewRecorder())
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	var tooLarge *PayloadTooLargeError
+	require.ErrorAs(t, err, &tooLarge)
+	assert.Greater(t, tooLarge.Size, 512)
+	assert.Equal(t, 512, tooLarge.Max)
+	assert.Empty(t, store.sessions)
+
+	err = guarded.(VersionedStore[string]).SaveVersion(httptest.NewRecorder(), session, 0)
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+}
+
+func TestSizeGuard_Load(t *testing.T) {
+	store := newMockStore[string]()
+	store.session = NewSession[string](store, "test")
+
+	guarded := SizeGuard[string](store, WithCompressThreshold(64))
+
+	session, err := guarded.Load(httptest.NewRequest("GET", "/", nil), "test")
+	require.NoError(t, err)
+	assert.Equal(t, 64, session.compressAbove)
+}
+
+func TestSizeGuard_Uncompressed(t *testing.T) {
+	session := NewSession[string](newMockStore[string](), "test")
+	session.Set("large", strings.Repeat("a", 2*DefaultCompressThreshold))
+
+	payload, err := json.Marshal(session)
+	require.NoError(t, err)
+	assert.False(t, bytes.HasPrefix(payload, compressedPrefix), "sessions are only compressed when guarded")
+}
+
+func TestConfigWithSizeGuard(t *testing.T) {
+	m, _ := newTestPrometheusMetrics(t)
+	store := newMockStore[string]()
+
+	config := NewConfig(store,
+		WithMetrics[string](m),
+		WithSizeGuard[string](WithMaxPayloadSize(2048)),
+	)
+
+	session := config.NewSession("test")
+	require.NoError(t, session.Save(httptest.NewRecorder()))
+
+	session.Set("large", strings.Repeat("permission:read ", 200))
+	require.NoError(t, session.Save(httptest.NewRecorder()))
+
+	noise := make([]byte, 2048)
+	_, err := rand.Read(noise)
+	require.NoError(t, err)
+
+	session.Set("noise", hex.EncodeToString(noise))
+	require.ErrorIs(t, session.Save(httptest.NewRecorder()), ErrPayloadTooLarge)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationGuard, ResultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationGuard, ResultCompressed)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationGuard, ResultTooLarge)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.operations.WithLabelValues(OperationSave, ResultTooLarge)))
+}
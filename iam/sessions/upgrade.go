@@ -0,0 +1,91 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"time"
+)
+
+// UpgradeHook is called after a session was upgraded. oldID is the session ID
+// before the upgrade; server-side stores typically use it to delete the
+// pre-authentication record.
+type UpgradeHook[T any] func(oldID string, session *Session[T])
+
+// WithOnUpgrade registers a hook that is called by Config.Upgrade
+func WithOnUpgrade[T any](hook UpgradeHook[T]) Option[T] {
+	return func(c *Config[T]) {
+		c.OnUpgrade = hook
+	}
+}
+
+// Upgrade turns an anonymous session into an authenticated one. It rotates the
+// session ID to prevent session fixation, resets CreatedAt and ExpiresAt while
+// keeping the session's lifetime, and drops all values except carryKeys.
+// It returns the session ID before the upgrade.
+//
+// Example:
+//
+//	// keep the cart and locale the visitor selected before logging in
+//	oldID, err := sessions.Upgrade(session, []string{"cart", "locale"})
+func Upgrade[T any](session *Session[T], carryKeys []string) (string, error) {
+	return upgrade(session, carryKeys, 0)
+}
+
+// Upgrade upgrades the session like the package-level Upgrade and calls the
+// OnUpgrade hook. If a cookie MaxAge is configured it is used as the lifetime
+// of the upgraded session.
+func (c Config[T]) Upgrade(session *Session[T], carryKeys []string) error {
+	var lifetime time.Duration
+	if c.CookieConfig != nil && c.CookieConfig.MaxAge > 0 {
+		lifetime = time.Duration(c.CookieConfig.MaxAge) * time.Second
+	}
+
+	oldID, err := upgrade(session, carryKeys, lifetime)
+	if err != nil {
+		return err
+	}
+
+	if c.OnUpgrade != nil {
+		c.OnUpgrade(oldID, session)
+	}
+
+	return nil
+}
+
+// upgrade implements Upgrade. A zero lifetime keeps the current lifetime of the
+// session, falling back to DefaultMaxAge.
+func upgrade[T any](session *Session[T], carryKeys []string, lifetime time.Duration) (string, error) {
+	if session == nil {
+		return "", ErrInvalidSession
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if lifetime <= 0 {
+		lifetime = session.ExpiresAt.Sub(session.CreatedAt)
+	}
+
+	if lifetime <= 0 {
+		lifetime = DefaultMaxAge * time.Second
+	}
+
+	values := make(map[string]T, len(carryKeys))
+
+	for _, key := range carryKeys {
+		if v, ok := session.Values[key]; ok {
+			values[key] = v
+		}
+	}
+
+	oldID := session.ID
+	now := time.Now()
+
+	session.ID = GenerateSessionID()
+	session.Values = values
+	session.CreatedAt = now
+	session.ExpiresAt = now.Add(lifetime)
+
+	return oldID, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgrade(t *testing.T) {
+	store := newMockStore[string]()
+	session := NewSession(store, "test")
+	session.Set("cart", "42")
+	session.Set("locale", "de")
+	session.Set("csrf", "token")
+
+	session.CreatedAt = time.Now().Add(-30 * time.Minute)
+	session.ExpiresAt = session.CreatedAt.Add(2 * time.Hour)
+
+	before := session.ID
+
+	oldID, err := Upgrade(session, []string{"cart", "locale", "missing"})
+	require.NoError(t, err)
+
+	assert.Equal(t, before, oldID)
+	assert.NotEqual(t, before, session.ID)
+	assert.Equal(t, map[string]string{"cart": "42", "locale": "de"}, session.Values)
+	assert.WithinDuration(t, time.Now(), session.CreatedAt, time.Second)
+	assert.Equal(t, 2*time.Hour, session.ExpiresAt.Sub(session.CreatedAt))
+}
+
+func TestUpgrade_NoCarryKeys(t *testing.T) {
+	session := NewSession(newMockStore[string](), "test")
+	session.Set("cart", "42")
+
+	_, err := Upgrade(session, nil)
+	require.NoError(t, err)
+	assert.Empty(t, session.Values)
+}
+
+func TestUpgrade_NilSession(t *testing.T) {
+	_, err := Upgrade[string](nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+}
+
+func TestConfig_Upgrade(t *testing.T) {
+	store := newMockStore[string]()
+
+	var (
+		hookOldID   string
+		hookSession *Session[string]
+	)
+
+	config := NewConfig(store,
+		WithMaxAge[string](600),
+		WithOnUpgrade(func(oldID string, s *Session[string]) {
+			hookOldID = oldID
+			hookSession = s
+		}),
+	)
+
+	session := NewSession(store, "test")
+	session.Set("cart", "42")
+
+	before := session.ID
+
+	require.NoError(t, config.Upgrade(session, []string{"cart"}))
+
+	assert.Equal(t, before, hookOldID)
+	assert.Same(t, session, hookSession)
+	assert.Equal(t, "42", session.Get("cart"))
+	assert.Equal(t, 10*time.Minute, session.ExpiresAt.Sub(session.CreatedAt))
+}
+
+func TestConfig_Upgrade_NilSession(t *testing.T) {
+	called := false
+
+	config := NewConfig(newMockStore[string](), WithOnUpgrade(func(string, *Session[string]) {
+		called = true
+	}))
+
+	assert.ErrorIs(t, config.Upgrade(nil, nil), ErrInvalidSession)
+	assert.False(t, called)
+}
@@ -0,0 +1,34 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+//
+// Package svcauth implements service-to-service authentication with short‑lived
+// signed assertions.
+//
+// Design Overview
+// A calling service mints an assertion with an Issuer. The assertion states
+// which service issued it (iss), which service it is intended for (aud), the
+// scopes granted (scp) and a short expiry (exp). The receiving service checks
+// it with a Verifier, typically through Middleware.
+//
+// Token Format
+// Assertions use the compact JWS serialization
+// base64url(header) "." base64url(claims) "." base64url(signature) with the
+// header fields "alg", "kid" and "typ" (always "svc+jwt"). Supported
+// algorithms are EdDSA (Ed25519, recommended) and HS256 (HMAC-SHA256 with a
+// shared secret, for environments without a key distribution mechanism).
+//
+// Key Rotation
+// Keys are looked up through a KeyProvider. The signing key is resolved on
+// every Mint and verification keys are looked up by "kid", so keys can be
+// rotated without restarts. KeySet is an in-memory KeyProvider: Rotate
+// installs a new signing key and keeps the previous one for verification
+// until it is removed, so assertions minted before the rotation stay valid
+// for their (short) lifetime.
+//
+// Security Notes
+//   - The algorithm is taken from the key, never from the token header, so a
+//     token cannot downgrade an Ed25519 key to HMAC.
+//   - HMAC secrets must be at least MinHMACSecretLength bytes long.
+//   - Lifetimes are capped at MaxTTL; a small clock skew is tolerated on
+//     verification (see WithLeeway).
+package svcauth
@@ -0,0 +1,29 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package svcauth
+
+import "github.com/kopexa-grc/common/errors"
+
+var (
+	// ErrMissingToken is returned when a request carries no bearer token.
+	ErrMissingToken = errors.NewUnauthorized("service token is missing")
+	// ErrInvalidToken is returned when a token is malformed or its signature cannot be verified.
+	ErrInvalidToken = errors.NewUnauthorized("service token is invalid")
+	// ErrTokenExpired is returned when a token has passed its expiration time.
+	ErrTokenExpired = errors.NewTokenExpired("service token is expired")
+	// ErrAudienceMismatch is returned when a token was issued for a different service.
+	ErrAudienceMismatch = errors.NewUnauthorized("service token was issued for a different audience")
+	// ErrIssuerNotAllowed is returned when a token was issued by a service that is not allowed.
+	ErrIssuerNotAllowed = errors.NewForbidden("service token issuer is not allowed")
+	// ErrMissingScope is returned when a token lacks a required scope.
+	ErrMissingScope = errors.NewForbidden("service token is missing a required scope")
+	// ErrUnknownKey is returned when no key exists for a key id.
+	ErrUnknownKey = errors.NewUnauthorized("unknown signing key")
+	// ErrInvalidKey is returned when a key is incomplete or uses an unsupported algorithm.
+	ErrInvalidKey = errors.NewInvalidArgument("invalid key")
+	// ErrInvalidTTL is returned when a token lifetime is not positive or exceeds MaxTTL.
+	ErrInvalidTTL = errors.NewInvalidArgument("invalid token lifetime")
+	// ErrMissingService is returned when an issuer, verifier or token lacks a service name.
+	ErrMissingService = errors.NewInvalidArgument("service name is required")
+)
@@ -0,0 +1,225 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package svcauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"sync"
+)
+
+// Algorithm is the signature algorithm of a key.
+type Algorithm string
+
+const (
+	// AlgEdDSA signs with Ed25519. Only the public key is needed to verify.
+	AlgEdDSA Algorithm = "EdDSA"
+	// AlgHS256 signs with HMAC-SHA256 and a secret shared by both services.
+	AlgHS256 Algorithm = "HS256"
+)
+
+// MinHMACSecretLength is the minimum length of HMAC secrets in bytes.
+const MinHMACSecretLength = 32
+
+// Key is a signing or verification key identified by ID ("kid").
+//
+// For AlgEdDSA, PrivateKey is required to sign and PublicKey to verify; a key
+// with only a PublicKey is verification-only. For AlgHS256, Secret is used
+// for both.
+type Key struct {
+	// ID identifies the key in token headers.
+	ID string
+	// Algorithm is the signature algorithm.
+	Algorithm Algorithm
+	// PrivateKey is the Ed25519 signing key.
+	PrivateKey ed25519.PrivateKey
+	// PublicKey is the Ed25519 verification key.
+	PublicKey ed25519.PublicKey
+	// Secret is the HMAC secret.
+	Secret []byte
+}
+
+// GenerateEd25519Key generates a new Ed25519 key with the given ID.
+func GenerateEd25519Key(id string) (Key, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return Key{}, err
+	}
+
+	return Key{ID: id, Algorithm: AlgEdDSA, PrivateKey: priv, PublicKey: pub}, nil
+}
+
+// NewHMACKey returns an HS256 key with the given ID and shared secret.
+// Returns ErrInvalidKey if the secret is shorter than MinHMACSecretLength.
+func NewHMACKey(id string, secret []byte) (Key, error) {
+	k := Key{ID: id, Algorithm: AlgHS256, Secret: secret}
+
+	if err := k.validate(); err != nil {
+		return Key{}, err
+	}
+
+	return k, nil
+}
+
+// Public returns the key without private material, suitable for sharing
+// with verifying services. HMAC keys are returned unchanged since the secret
+// is needed for verification.
+func (k Key) Public() Key {
+	if k.Algorithm != AlgEdDSA {
+		return k
+	}
+
+	pub := k.PublicKey
+	if pub == nil && k.PrivateKey != nil {
+		pub, _ = k.PrivateKey.Public().(ed25519.PublicKey)
+	}
+
+	return Key{ID: k.ID, Algorithm: AlgEdDSA, PublicKey: pub}
+}
+
+// validate checks that the key is complete for its algorithm.
+func (k Key) validate() error {
+	if k.ID == "" {
+		return ErrInvalidKey
+	}
+
+	switch k.Algorithm {
+	case AlgEdDSA:
+		if len(k.PrivateKey) != ed25519.PrivateKeySize && len(k.PublicKey) != ed25519.PublicKeySize {
+			return ErrInvalidKey
+		}
+	case AlgHS256:
+		if len(k.Secret) < MinHMACSecretLength {
+			return ErrInvalidKey
+		}
+	default:
+		return ErrInvalidKey
+	}
+
+	return nil
+}
+
+// canSign reports whether the key holds the material needed for signing.
+func (k Key) canSign() bool {
+	switch k.Algorithm {
+	case AlgEdDSA:
+		return len(k.PrivateKey) == ed25519.PrivateKeySize
+	case AlgHS256:
+		return len(k.Secret) >= MinHMACSecretLength
+	default:
+		return false
+	}
+}
+
+// KeyProvider supplies the keys used to mint and verify assertions.
+// Implementations must be safe for concurrent use. Fetching keys from a
+// secret store or a JWKS endpoint allows rotation without restarts.
+type KeyProvider interface {
+	// SigningKey returns the key new assertions are signed with.
+	SigningKey(ctx context.Context) (Key, error)
+	// VerificationKey returns the key with the given ID, or ErrUnknownKey.
+	VerificationKey(ctx context.Context, keyID string) (Key, error)
+}
+
+// KeySet is an in-memory KeyProvider with support for key rotation.
+type KeySet struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string]Key
+}
+
+// NewKeySet creates a KeySet that signs with signing and additionally accepts
+// the verification-only keys for verification. A verifying service without
+// a signing key passes a zero Key as signing.
+func NewKeySet(signing Key, verification ...Key) (*KeySet, error) {
+	s := &KeySet{keys: make(map[string]Key, len(verification)+1)}
+
+	for _, k := range verification {
+		if err := k.validate(); err != nil {
+			return nil, err
+		}
+
+		s.keys[k.ID] = k
+	}
+
+	if signing.ID != "" {
+		if err := s.Rotate(signing); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Rotate makes k the signing key. The previous signing key remains available
+// for verification until it is removed with Remove.
+func (s *KeySet) Rotate(k Key) error {
+	if err := k.validate(); err != nil {
+		return err
+	}
+
+	if !k.canSign() {
+		return ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[k.ID] = k
+	s.current = k.ID
+
+	return nil
+}
+
+// Add adds a verification-only key, e.g. the public key of a peer service.
+func (s *KeySet) Add(k Key) error {
+	if err := k.validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[k.ID] = k
+
+	return nil
+}
+
+// Remove removes a key. Assertions signed with it no longer verify. The
+// current signing key cannot be removed; rotate first.
+func (s *KeySet) Remove(keyID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if keyID != s.current {
+		delete(s.keys, keyID)
+	}
+}
+
+// SigningKey implements KeyProvider.
+func (s *KeySet) SigningKey(_ context.Context) (Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	k, ok := s.keys[s.current]
+	if !ok {
+		return Key{}, ErrUnknownKey
+	}
+
+	return k, nil
+}
+
+// VerificationKey implements KeyProvider.
+func (s *KeySet) VerificationKey(_ context.Context, keyID string) (Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	k, ok := s.keys[keyID]
+	if !ok {
+		return Key{}, ErrUnknownKey
+	}
+
+	return k, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package svcauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/rs/zerolog"
+)
+
+// bearerPrefix is the Authorization header scheme for service assertions.
+const bearerPrefix = "Bearer "
+
+// claimsContextKey is the context key for verified claims.
+type claimsContextKey struct{}
+
+// WithClaims returns a context carrying the verified claims of the caller.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// ClaimsFromContext returns the verified claims of the calling service.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// Middleware returns a middleware that verifies the bearer assertion of every
+// request and puts the claims into the request context. Requests without a
+// valid assertion are rejected with 401; requests lacking one of the
+// required scopes with 403.
+//
+// Example:
+//
+//	r.Use(svcauth.Middleware(verifier, "evidence:read"))
+func Middleware(v *Verifier, requiredScopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := authenticate(r, v, requiredScopes)
+			if err != nil {
+				zerolog.Ctx(r.Context()).Debug().Err(err).Msg("service authentication failed")
+
+				writeError(w, err)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// authenticate verifies the bearer assertion of r and checks the scopes.
+func authenticate(r *http.Request, v *Verifier, requiredScopes []string) (*Claims, error) {
+	authz := r.Header.Get("Authorization")
+	if len(authz) <= len(bearerPrefix) || !strings.EqualFold(authz[:len(bearerPrefix)], bearerPrefix) {
+		return nil, ErrMissingToken
+	}
+
+	claims, err := v.Verify(r.Context(), strings.TrimSpace(authz[len(bearerPrefix):]))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range requiredScopes {
+		if !claims.HasScope(scope) {
+			return nil, ErrMissingScope
+		}
+	}
+
+	return claims, nil
+}
+
+// writeError writes the status of err without leaking verification details.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusUnauthorized
+
+	var e *kerr.Error
+	if errors.As(err, &e) && e.Status == http.StatusForbidden {
+		status = http.StatusForbidden
+	}
+
+	http.Error(w, http.StatusText(status), status)
+}
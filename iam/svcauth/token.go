@@ -0,0 +1,251 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package svcauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kopexa-grc/common/iam/internal/jws"
+)
+
+const (
+	// DefaultTTL is the default lifetime of minted assertions.
+	DefaultTTL = 5 * time.Minute
+	// MaxTTL is the maximum lifetime of an assertion.
+	MaxTTL = time.Hour
+	// DefaultLeeway is the default clock skew tolerated on verification.
+	DefaultLeeway = 30 * time.Second
+
+	// tokenType is the "typ" header value of service assertions.
+	tokenType = "svc+jwt"
+)
+
+// Claims are the statements of a service assertion.
+type Claims struct {
+	// ID uniquely identifies the assertion ("jti").
+	ID string `json:"jti"`
+	// Issuer is the calling service ("iss").
+	Issuer string `json:"iss"`
+	// Audience is the service the assertion is intended for ("aud").
+	Audience string `json:"aud"`
+	// Scopes are the permissions granted to the caller ("scp").
+	Scopes []string `json:"scp,omitempty"`
+	// IssuedAt is the Unix time the assertion was minted ("iat").
+	IssuedAt int64 `json:"iat"`
+	// ExpiresAt is the Unix time the assertion expires ("exp").
+	ExpiresAt int64 `json:"exp"`
+}
+
+// HasScope reports whether the claims grant scope.
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+// Issuer mints assertions on behalf of a service.
+type Issuer struct {
+	service string
+	keys    KeyProvider
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// IssuerOption configures an Issuer.
+type IssuerOption func(*Issuer)
+
+// WithTTL sets the lifetime of minted assertions. Defaults to DefaultTTL.
+func WithTTL(ttl time.Duration) IssuerOption {
+	return func(i *Issuer) {
+		i.ttl = ttl
+	}
+}
+
+// NewIssuer creates an Issuer that mints assertions for service, signed with
+// the signing key of keys.
+//
+// Example:
+//
+//	key, _ := svcauth.GenerateEd25519Key("catalog-2025-01")
+//	keys, _ := svcauth.NewKeySet(key)
+//	issuer, _ := svcauth.NewIssuer("catalog", keys)
+//	token, err := issuer.Mint(ctx, "evidence", "evidence:read")
+func NewIssuer(service string, keys KeyProvider, opts ...IssuerOption) (*Issuer, error) {
+	if service == "" {
+		return nil, ErrMissingService
+	}
+
+	i := &Issuer{service: service, keys: keys, ttl: DefaultTTL, now: time.Now}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	if i.ttl <= 0 || i.ttl > MaxTTL {
+		return nil, ErrInvalidTTL
+	}
+
+	return i, nil
+}
+
+// Mint returns a signed assertion for audience granting scopes.
+func (i *Issuer) Mint(ctx context.Context, audience string, scopes ...string) (string, error) {
+	if audience == "" {
+		return "", ErrMissingService
+	}
+
+	key, err := i.keys.SigningKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if !key.canSign() {
+		return "", ErrInvalidKey
+	}
+
+	now := i.now()
+
+	claims := Claims{
+		ID:        uuid.NewString(),
+		Issuer:    i.service,
+		Audience:  audience,
+		Scopes:    scopes,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(i.ttl).Unix(),
+	}
+
+	h := jws.Header{Algorithm: string(key.Algorithm), KeyID: key.ID, Type: tokenType}
+
+	return jws.Sign(h, claims, func(signingInput []byte) ([]byte, error) {
+		if key.Algorithm == AlgEdDSA {
+			return ed25519.Sign(key.PrivateKey, signingInput), nil
+		}
+
+		return hmacSHA256(key.Secret, signingInput), nil
+	})
+}
+
+// Verifier checks assertions addressed to a service.
+type Verifier struct {
+	audience string
+	keys     KeyProvider
+	issuers  []string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// VerifierOption configures a Verifier.
+type VerifierOption func(*Verifier)
+
+// WithAllowedIssuers restricts accepted assertions to the given issuing
+// services. By default every issuer with a known key is accepted.
+func WithAllowedIssuers(services ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.issuers = services
+	}
+}
+
+// WithLeeway sets the tolerated clock skew. Defaults to DefaultLeeway.
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.leeway = leeway
+	}
+}
+
+// NewVerifier creates a Verifier accepting assertions for audience whose
+// keys are provided by keys.
+func NewVerifier(audience string, keys KeyProvider, opts ...VerifierOption) (*Verifier, error) {
+	if audience == "" {
+		return nil, ErrMissingService
+	}
+
+	v := &Verifier{audience: audience, keys: keys, leeway: DefaultLeeway, now: time.Now}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v, nil
+}
+
+// Verify checks the signature, audience, issuer and lifetime of token and
+// returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parsed, err := jws.Parse(token, tokenType)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, err := v.keys.VerificationKey(ctx, parsed.Header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The algorithm is defined by the key; a mismatching header is rejected
+	// to prevent algorithm confusion.
+	if Algorithm(parsed.Header.Algorithm) != key.Algorithm {
+		return nil, ErrInvalidToken
+	}
+
+	if !verifySignature(key, parsed.SigningInput, parsed.Signature) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := parsed.Claims(&claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	now := v.now()
+
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(v.leeway)) {
+		return nil, ErrTokenExpired
+	}
+
+	if now.Add(v.leeway).Before(time.Unix(claims.IssuedAt, 0)) {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Audience != v.audience {
+		return nil, ErrAudienceMismatch
+	}
+
+	if claims.Issuer == "" {
+		return nil, ErrMissingService
+	}
+
+	if len(v.issuers) > 0 && !slices.Contains(v.issuers, claims.Issuer) {
+		return nil, ErrIssuerNotAllowed
+	}
+
+	return &claims, nil
+}
+
+// verifySignature checks sig over signingInput with key.
+func verifySignature(key Key, signingInput, sig []byte) bool {
+	switch key.Algorithm {
+	case AlgEdDSA:
+		pub := key.PublicKey
+		if pub == nil && key.PrivateKey != nil {
+			pub, _ = key.PrivateKey.Public().(ed25519.PublicKey)
+		}
+
+		return len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, signingInput, sig)
+	case AlgHS256:
+		return hmac.Equal(hmacSHA256(key.Secret, signingInput), sig)
+	default:
+		return false
+	}
+}
+
+func hmacSHA256(secret, data []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
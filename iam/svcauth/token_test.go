@@ -0,0 +1,247 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package svcauth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/internal/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeySet(t *testing.T) (*KeySet, Key) {
+	t.Helper()
+
+	key, err := GenerateEd25519Key("key-1")
+	require.NoError(t, err)
+
+	keys, err := NewKeySet(key)
+	require.NoError(t, err)
+
+	return keys, key
+}
+
+func TestMintAndVerify(t *testing.T) {
+	hmacKey, err := NewHMACKey("shared", bytes.Repeat([]byte("s"), MinHMACSecretLength))
+	require.NoError(t, err)
+
+	edKey, err := GenerateEd25519Key("ed")
+	require.NoError(t, err)
+
+	for _, key := range []Key{edKey, hmacKey} {
+		t.Run(string(key.Algorithm), func(t *testing.T) {
+			signing, err := NewKeySet(key)
+			require.NoError(t, err)
+
+			// the verifying service only knows the public part
+			verifying, err := NewKeySet(Key{}, key.Public())
+			require.NoError(t, err)
+
+			issuer, err := NewIssuer("catalog", signing)
+			require.NoError(t, err)
+
+			verifier, err := NewVerifier("evidence", verifying, WithAllowedIssuers("catalog"))
+			require.NoError(t, err)
+
+			token, err := issuer.Mint(t.Context(), "evidence", "evidence:read")
+			require.NoError(t, err)
+
+			claims, err := verifier.Verify(t.Context(), token)
+			require.NoError(t, err)
+			assert.Equal(t, "catalog", claims.Issuer)
+			assert.Equal(t, "evidence", claims.Audience)
+			assert.True(t, claims.HasScope("evidence:read"))
+			assert.False(t, claims.HasScope("evidence:write"))
+			assert.NotEmpty(t, claims.ID)
+		})
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	keys, _ := newTestKeySet(t)
+
+	issuer, err := NewIssuer("catalog", keys)
+	require.NoError(t, err)
+
+	token, err := issuer.Mint(t.Context(), "evidence")
+	require.NoError(t, err)
+
+	t.Run("wrong audience", func(t *testing.T) {
+		v, err := NewVerifier("billing", keys)
+		require.NoError(t, err)
+
+		_, err = v.Verify(t.Context(), token)
+		assert.ErrorIs(t, err, ErrAudienceMismatch)
+	})
+
+	t.Run("issuer not allowed", func(t *testing.T) {
+		v, err := NewVerifier("evidence", keys, WithAllowedIssuers("billing"))
+		require.NoError(t, err)
+
+		_, err = v.Verify(t.Context(), token)
+		assert.ErrorIs(t, err, ErrIssuerNotAllowed)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		v, err := NewVerifier("evidence", keys)
+		require.NoError(t, err)
+
+		v.now = func() time.Time { return time.Now().Add(DefaultTTL + DefaultLeeway + time.Minute) }
+
+		_, err = v.Verify(t.Context(), token)
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+
+	t.Run("tampered claims", func(t *testing.T) {
+		v, err := NewVerifier("evidence", keys)
+		require.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		claims, _ := json.Marshal(Claims{Issuer: "catalog", Audience: "evidence", Scopes: []string{"admin"}, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+		parts[1] = base64.RawURLEncoding.EncodeToString(claims)
+
+		_, err = v.Verify(t.Context(), strings.Join(parts, "."))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("algorithm confusion", func(t *testing.T) {
+		v, err := NewVerifier("evidence", keys)
+		require.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		h, _ := json.Marshal(jws.Header{Algorithm: string(AlgHS256), KeyID: "key-1", Type: tokenType})
+		parts[0] = base64.RawURLEncoding.EncodeToString(h)
+
+		_, err = v.Verify(t.Context(), strings.Join(parts, "."))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		v, err := NewVerifier("evidence", keys)
+		require.NoError(t, err)
+
+		_, err = v.Verify(t.Context(), "not-a-token")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestKeySet_Rotate(t *testing.T) {
+	keys, _ := newTestKeySet(t)
+
+	issuer, err := NewIssuer("catalog", keys)
+	require.NoError(t, err)
+
+	verifier, err := NewVerifier("evidence", keys)
+	require.NoError(t, err)
+
+	before, err := issuer.Mint(t.Context(), "evidence")
+	require.NoError(t, err)
+
+	next, err := GenerateEd25519Key("key-2")
+	require.NoError(t, err)
+	require.NoError(t, keys.Rotate(next))
+
+	after, err := issuer.Mint(t.Context(), "evidence")
+	require.NoError(t, err)
+
+	// assertions signed with the previous key remain valid after rotation
+	_, err = verifier.Verify(t.Context(), before)
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(t.Context(), after)
+	require.NoError(t, err)
+
+	keys.Remove("key-1")
+
+	_, err = verifier.Verify(t.Context(), before)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	// the current signing key cannot be removed
+	keys.Remove("key-2")
+
+	_, err = verifier.Verify(t.Context(), after)
+	assert.NoError(t, err)
+
+	// verification-only keys cannot become the signing key
+	assert.ErrorIs(t, keys.Rotate(next.Public()), ErrInvalidKey)
+}
+
+func TestNew_Invalid(t *testing.T) {
+	keys, _ := newTestKeySet(t)
+
+	_, err := NewHMACKey("short", []byte("secret"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = NewIssuer("", keys)
+	assert.ErrorIs(t, err, ErrMissingService)
+
+	_, err = NewIssuer("catalog", keys, WithTTL(2*MaxTTL))
+	assert.ErrorIs(t, err, ErrInvalidTTL)
+
+	_, err = NewVerifier("", keys)
+	assert.ErrorIs(t, err, ErrMissingService)
+}
+
+func TestMiddleware(t *testing.T) {
+	keys, _ := newTestKeySet(t)
+
+	issuer, err := NewIssuer("catalog", keys)
+	require.NoError(t, err)
+
+	verifier, err := NewVerifier("evidence", keys)
+	require.NoError(t, err)
+
+	handler := Middleware(verifier, "evidence:read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := ClaimsFromContext(r.Context())
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = w.Write([]byte(claims.Issuer))
+	}))
+
+	withScope, err := issuer.Mint(t.Context(), "evidence", "evidence:read")
+	require.NoError(t, err)
+
+	withoutScope, err := issuer.Mint(t.Context(), "evidence")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		authz      string
+		wantStatus int
+	}{
+		{name: "valid", authz: "Bearer " + withScope, wantStatus: http.StatusOK},
+		{name: "missing scope", authz: "Bearer " + withoutScope, wantStatus: http.StatusForbidden},
+		{name: "missing token", wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", authz: "Bearer invalid", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authz != "" {
+				req.Header.Set("Authorization", tt.authz)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "catalog", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// SignBatch signs many tokens at once, e.g. for bulk invites. It is
+// equivalent to calling Sign on every token, but fills the nonces and HMAC
+// keys of all tokens from a single crypto/rand read and reuses one msgpack
+// encoder. Each token gets a fresh nonce through SetNonce; the other
+// fields, including ExpiresAt and TokenID, are signed as they are.
+//
+// The signatures and secrets are returned in the order of batch. On error
+// no result is returned.
+//
+// Example:
+//
+//	batch := make([]tokens.URLToken, 0, len(emails))
+//	for _, email := range emails {
+//		token, err := tokens.NewOrganizationInviteToken(email, orgID)
+//		...
+//		batch = append(batch, token)
+//	}
+//	signatures, secrets, err := tokens.SignBatch(batch)
+func SignBatch(batch []URLToken) ([]string, [][]byte, error) {
+	const secretLength = nonceLength + keyLength
+
+	// One contiguous read; every token owns a chunk of nonce followed by key,
+	// which is exactly the layout of its secret.
+	material := make([]byte, len(batch)*secretLength)
+	if _, err := rand.Read(material); err != nil {
+		return nil, nil, ErrFailedSigning.With(err)
+	}
+
+	signatures := make([]string, len(batch))
+	secrets := make([][]byte, len(batch))
+
+	var buf bytes.Buffer
+
+	enc := msgpack.NewEncoder(&buf)
+
+	for i, token := range batch {
+		secret := material[i*secretLength : (i+1)*secretLength : (i+1)*secretLength]
+
+		token.SetNonce(bytes.Clone(secret[:nonceLength]))
+
+		buf.Reset()
+
+		if err := enc.Encode(token); err != nil {
+			return nil, nil, err
+		}
+
+		mac := hmac.New(sha256.New, secret[nonceLength:])
+		if _, err := mac.Write(buf.Bytes()); err != nil {
+			return nil, nil, ErrFailedSigning.With(err)
+		}
+
+		signatures[i] = base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+		secrets[i] = secret
+	}
+
+	return signatures, secrets, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignBatch(t *testing.T) {
+	invites := make([]*tokens.OrganizationInviteToken, 0, 3)
+	batch := make([]tokens.URLToken, 0, 3)
+
+	for i := range 3 {
+		invite, err := tokens.NewOrganizationInviteToken(fmt.Sprintf("user%d@example.com", i), "org-1")
+		require.NoError(t, err)
+
+		invites = append(invites, invite)
+		batch = append(batch, invite)
+	}
+
+	reset, err := tokens.NewResetToken("user-1")
+	require.NoError(t, err)
+
+	batch = append(batch, reset)
+
+	signatures, secrets, err := tokens.SignBatch(batch)
+	require.NoError(t, err)
+	require.Len(t, signatures, 4)
+	require.Len(t, secrets, 4)
+
+	for i, invite := range invites {
+		assert.Len(t, secrets[i], 128)
+		assert.Equal(t, secrets[i][:64], invite.Nonce)
+		assert.NoError(t, invite.Verify(signatures[i], secrets[i]))
+	}
+
+	assert.NoError(t, reset.Verify(signatures[3], secrets[3]))
+	assert.ErrorIs(t, invites[0].Verify(signatures[1], secrets[1]), tokens.ErrTokenInvalid)
+
+	// Appending to a secret must not affect the next one.
+	_ = append(secrets[0], 0xff)
+	assert.NoError(t, invites[1].Verify(signatures[1], secrets[1]))
+
+	signatures, secrets, err = tokens.SignBatch(nil)
+	require.NoError(t, err)
+	assert.Empty(t, signatures)
+	assert.Empty(t, secrets)
+}
+
+func newInviteBatch(b *testing.B, n int) []*tokens.OrganizationInviteToken {
+	b.Helper()
+
+	invites := make([]*tokens.OrganizationInviteToken, n)
+
+	for i := range invites {
+		invite, err := tokens.NewOrganizationInviteToken(fmt.Sprintf("user%d@example.com", i), "org-1")
+		require.NoError(b, err)
+
+		invites[i] = invite
+	}
+
+	return invites
+}
+
+// BenchmarkSign_Invites500 signs 500 invites one by one.
+func BenchmarkSign_Invites500(b *testing.B) {
+	invites := newInviteBatch(b, 500)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		for _, invite := range invites {
+			if _, _, err := invite.Sign(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkSignBatch_Invites500 signs the same 500 invites with SignBatch.
+func BenchmarkSignBatch_Invites500(b *testing.B) {
+	invites := newInviteBatch(b, 500)
+
+	batch := make([]tokens.URLToken, len(invites))
+	for i, invite := range invites {
+		batch[i] = invite
+	}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if _, _, err := tokens.SignBatch(batch); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
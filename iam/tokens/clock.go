@@ -0,0 +1,123 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the current time. It is used for expiration times and checks,
+// so that tests can control time and deployments can compensate clock skew.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now implements Clock.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// systemClock is the Clock of time.Now.
+type systemClock struct{}
+
+// Now implements Clock.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+var (
+	// clock is the clock installed with SetClock.
+	clock atomic.Pointer[Clock]
+	// leeway is the leeway installed with SetLeeway.
+	leeway atomic.Int64
+)
+
+// SetClock installs the clock used to issue and verify all tokens of the
+// package. Passing nil restores the system clock.
+//
+// Example:
+//
+//	tokens.SetClock(tokens.ClockFunc(func() time.Time { return fixed }))
+func SetClock(c Clock) {
+	if c == nil {
+		clock.Store(nil)
+		return
+	}
+
+	clock.Store(&c)
+}
+
+// SetLeeway sets the default leeway of expiration checks: tokens are
+// accepted until d after their expiration time, to tolerate clock skew
+// between the issuing and the verifying service. Negative durations are
+// treated as zero.
+func SetLeeway(d time.Duration) {
+	leeway.Store(int64(max(d, 0)))
+}
+
+// now returns the current time of the installed clock.
+func now() time.Time {
+	if c := clock.Load(); c != nil {
+		return (*c).Now()
+	}
+
+	return time.Now()
+}
+
+// VerifyOption configures a single verification, overriding SetClock and
+// SetLeeway.
+type VerifyOption func(*verifyConfig)
+
+type verifyConfig struct {
+	clock  Clock
+	leeway time.Duration
+}
+
+// WithClock checks the expiration against c.
+func WithClock(c Clock) VerifyOption {
+	return func(cfg *verifyConfig) {
+		if c != nil {
+			cfg.clock = c
+		}
+	}
+}
+
+// WithLeeway accepts the token until d after its expiration time.
+func WithLeeway(d time.Duration) VerifyOption {
+	return func(cfg *verifyConfig) {
+		cfg.leeway = max(d, 0)
+	}
+}
+
+// newVerifyConfig returns the package defaults with opts applied.
+func newVerifyConfig(opts []VerifyOption) verifyConfig {
+	cfg := verifyConfig{
+		clock:  systemClock{},
+		leeway: time.Duration(leeway.Load()),
+	}
+
+	if c := clock.Load(); c != nil {
+		cfg.clock = *c
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// expired reports whether expiresAt has passed, allowing for the leeway.
+func (cfg verifyConfig) expired(expiresAt time.Time) bool {
+	return expiresAt.Add(cfg.leeway).Before(cfg.clock.Now())
+}
+
+// isExpired checks expiresAt with the package clock and leeway.
+func isExpired(expiresAt time.Time) bool {
+	return newVerifyConfig(nil).expired(expiresAt)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedClock returns a clock that always reports at.
+func fixedClock(at time.Time) tokens.Clock {
+	return tokens.ClockFunc(func() time.Time { return at })
+}
+
+func TestVerify_WithClock(t *testing.T) {
+	token, err := tokens.NewResetToken("user-1")
+	require.NoError(t, err)
+
+	signature, secret, err := token.Sign()
+	require.NoError(t, err)
+
+	later := fixedClock(token.ExpiresAt.Add(time.Minute))
+
+	assert.ErrorIs(t, token.Verify(signature, secret, tokens.WithClock(later)), tokens.ErrTokenExpired)
+	assert.ErrorIs(t, token.Verify(signature, secret, tokens.WithClock(later), tokens.WithLeeway(30*time.Second)), tokens.ErrTokenExpired)
+	require.NoError(t, token.Verify(signature, secret, tokens.WithClock(later), tokens.WithLeeway(2*time.Minute)))
+	require.NoError(t, token.Verify(signature, secret, tokens.WithClock(nil)))
+
+	registry := tokens.NewDefaultTokenRegistry()
+
+	signed, secret, err := registry.SignToken(token)
+	require.NoError(t, err)
+
+	_, _, err = registry.VerifyToken(signed, secretStore(secret), tokens.WithClock(later))
+	assert.ErrorIs(t, err, tokens.ErrTokenExpired)
+}
+
+func TestSetClock(t *testing.T) {
+	issued := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tokens.SetClock(fixedClock(issued))
+	t.Cleanup(func() { tokens.SetClock(nil) })
+
+	token, err := tokens.NewVerificationToken("user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, issued.Add(7*24*time.Hour), token.ExpiresAt)
+
+	signature, secret, err := token.Sign()
+	require.NoError(t, err)
+	require.NoError(t, token.Verify(signature, secret))
+
+	tokens.SetClock(fixedClock(token.ExpiresAt.Add(time.Second)))
+	assert.True(t, token.IsExpired())
+	assert.ErrorIs(t, token.Verify(signature, secret), tokens.ErrTokenExpired)
+
+	tokens.SetLeeway(time.Minute)
+	t.Cleanup(func() { tokens.SetLeeway(0) })
+
+	assert.False(t, token.IsExpired())
+	require.NoError(t, token.Verify(signature, secret))
+	assert.ErrorIs(t, token.Verify(signature, secret, tokens.WithLeeway(0)), tokens.ErrTokenExpired)
+
+	pkce, err := tokens.GeneratePKCE()
+	require.NoError(t, err)
+	assert.Equal(t, token.ExpiresAt.Add(time.Second+10*time.Minute), pkce.ExpiresAt)
+}
@@ -42,6 +42,40 @@ const (
 	// keyLength defines the length of the HMAC key used in token signing.
 	keyLength = 64
 
+	// tokenIDLength defines the number of random bytes of a token ID.
+	tokenIDLength = 16
+
 	expirationDays              = 7
 	resetTokenExpirationMinutes = 15
+
+	// emailChangeExpirationHours defines how long an email change can be confirmed.
+	emailChangeExpirationHours = 24
+
+	// integrationStateExpirationMinutes defines how long an integration OAuth handshake may take.
+	integrationStateExpirationMinutes = 15
+
+	// oauthStateExpirationMinutes defines how long an OAuth/OIDC login may take.
+	oauthStateExpirationMinutes = 10
+	// pkceExpirationMinutes defines how long a PKCE pair remains valid.
+	pkceExpirationMinutes = 10
+	// oidcNonceLength is the number of random bytes of an OIDC nonce.
+	oidcNonceLength = 32
+	// pkceVerifierLength is the number of random bytes of a PKCE verifier,
+	// which encode to 43 characters.
+	pkceVerifierLength = 32
+
+	// totpEnrollmentExpirationMinutes defines how long an MFA enrollment may take.
+	totpEnrollmentExpirationMinutes = 10
+	// totpSecretLength is the number of random bytes of a TOTP secret (RFC 4226).
+	totpSecretLength = 20
+	// totpDigits is the number of digits of a TOTP code.
+	totpDigits = 6
+	// totpPeriodSeconds is the duration of a TOTP time step.
+	totpPeriodSeconds = 30
+	// downloadURLExpiryMinutes defines how long a signed URL returned by
+	// DownloadToken.Exchange is valid.
+	downloadURLExpiryMinutes = 5
+
+	// recoveryCodeLength is the number of characters of a recovery code.
+	recoveryCodeLength = 10
 )
@@ -0,0 +1,49 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import "context"
+
+// Consumer records the use of one-time tokens, so that a token, e.g. a
+// password reset link, can only be redeemed once.
+//
+// MarkUsed must record tokenID atomically and return ErrTokenAlreadyUsed if
+// it was recorded before, e.g. by inserting it into a table with a unique
+// key. Recorded IDs can be deleted once the token has expired.
+type Consumer interface {
+	MarkUsed(ctx context.Context, tokenID string) error
+}
+
+// ConsumerFunc adapts a function to the Consumer interface.
+type ConsumerFunc func(ctx context.Context, tokenID string) error
+
+// MarkUsed implements Consumer.
+func (f ConsumerFunc) MarkUsed(ctx context.Context, tokenID string) error {
+	return f(ctx, tokenID)
+}
+
+// ConsumeToken verifies token like VerifyToken and then marks it as used
+// with consumer. The token is only accepted if consumer records its TokenID
+// for the first time; tokens without TokenID, see AssignTokenID, are
+// rejected with ErrTokenMissingID. The outcome is reported to the observer installed with
+// SetVerifyObserver.
+func (d SigningInfo) ConsumeToken(ctx context.Context, token URLToken, signature string, secret []byte, consumer Consumer) error {
+	err := d.verifyToken(token, signature, secret, newVerifyConfig(nil))
+	if err == nil {
+		err = markUsed(ctx, consumer, d.TokenID)
+	}
+
+	observeVerify(tokenTypeOf(token), err)
+
+	return err
+}
+
+// markUsed marks the token with tokenID as used.
+func markUsed(ctx context.Context, consumer Consumer, tokenID string) error {
+	if tokenID == "" {
+		return ErrTokenMissingID
+	}
+
+	return consumer.MarkUsed(ctx, tokenID)
+}
@@ -2,11 +2,12 @@
 // SPDX-License-Identifier: BUSL-1.1
 //
 // Package tokens implements creation, signing and verification of short‑lived
-// URL tokens used in the IAM subsystem (invite, email verification, password reset).
+// URL tokens used in the IAM subsystem (invite, email verification, email change,
+// password reset, integration OAuth state, login OAuth state).
 //
 // Design Overview
-// A token type (e.g. OrganizationInviteToken, VerificationToken, ResetToken) embeds
-// SigningInfo. SigningInfo supplies:
+// A token type (e.g. OrganizationInviteToken, VerificationToken, ResetToken,
+// IntegrationStateToken) embeds SigningInfo. SigningInfo supplies:
 //   - ExpiresAt (UTC timestamp) for short‑lived validity windows
 //   - Nonce (random bytes) to ensure each issued token instance has unique
 //     input material even if logical data is identical.
@@ -26,9 +27,12 @@
 //   - Reject if: expired, malformed secret length, missing required logical fields, or signature mismatch.
 //
 // Expiration Semantics
-// Tokens are considered expired strictly when ExpiresAt.Before(time.Now()). A token expiring
+// Tokens are considered expired strictly when ExpiresAt.Before(now). A token expiring
 // at the exact call time (== now) is treated as expired (consistent with tests). Negative
 // durations to NewSigningInfo intentionally yield immediately expired tokens (used in tests).
+// The current time is taken from the Clock installed with SetClock (time.Now by default),
+// and SetLeeway accepts tokens for a while after ExpiresAt to tolerate clock skew between
+// services. WithClock and WithLeeway override both for a single verification.
 //
 // Security Notes
 //   - Each token uses an independent random HMAC key; compromise does not cascade.
@@ -37,10 +41,94 @@
 //     long‑term memory disclosure is a concern.
 //   - msgpack is chosen for compact, deterministic binary representation.
 //
+// Client Binding
+// ResetToken can optionally be bound to the requesting client with
+// NewResetTokenWithContext. Only a SHA-256 hash of the IssueContext (user agent,
+// IP network, device ID) is stored. VerifyFingerprint compares it with the client
+// redeeming the link and either rejects mismatches (FingerprintStrict) or reports
+// them to the caller (FingerprintAdvisory).
+//
+// OAuth Login Flows
+// GenerateState creates a signed OAuthStateToken carrying the redirect target and
+// an OIDC nonce; its signature is sent as state parameter and checked in the
+// callback with VerifyState. GeneratePKCE creates an S256 verifier/challenge pair
+// (RFC 7636); VerifyPKCE checks a verifier against a stored challenge. Both expire
+// after 10 minutes.
+//
+// Verification Telemetry
+// Every verification is reported to the VerifyObserver installed with
+// SetVerifyObserver, with the token type and a failure class (expired, tampered,
+// malformed secret, malformed signature, malformed token). NewPrometheusVerifyObserver
+// exports the counts as kopexa_tokens_verifications_total{type,result} so that
+// spikes of tampered tokens can be alerted on.
+//
+// JWT Tokens
+// JWTToken is a stateless alternative for invite, verification and reset tokens.
+// It is signed with an asymmetric key through a Signer (ES256 or EdDSA, see
+// NewES256Signer and NewEdDSASigner) and serialized as compact JWS, so services
+// that cannot reach the secret store verify it with a Verifier holding public keys
+// only (PublicKeys). ParseJWTToken checks signature, token type and expiration;
+// the algorithm is taken from the verification key, never from the token header.
+// The SigningInfo based token types are unaffected.
+//
+// Token Registry
+// TokenRegistry maps kind strings to token types embedding SigningInfo
+// (NewDefaultTokenRegistry registers invite, verification, email change, reset and
+// integration state tokens). SignToken signs the msgpack envelope {kind, token} and returns a
+// self-contained token string, so a single endpoint can decode any registered token,
+// fetch its secret through a SecretLookup and dispatch on the kind. As the kind is
+// signed, a reset token cannot be redeemed as invite; VerifyTokenAs additionally
+// rejects tokens of another kind with ErrTokenKindMismatch.
+//
+// One-Time Tokens
+// AssignTokenID gives a token a random TokenID before it is signed; other tokens
+// have none, so their serialized form is unchanged. The TokenID is signed and must
+// be stored by callers that rebuild tokens for verification. Consume, SigningInfo.ConsumeToken,
+// TokenRegistry.ConsumeToken and ConsumeJWTToken verify a token and then pass its ID
+// to a Consumer, whose MarkUsed records it atomically and returns ErrTokenAlreadyUsed
+// on reuse. Only tokens that pass verification are marked as used; replays are
+// reported to the VerifyObserver as VerifyFailureReplayed.
+//
+// Batch Signing
+// SignBatch signs many tokens at once, e.g. bulk invites. It draws the nonces and
+// HMAC keys of all tokens from a single crypto/rand read and reuses one msgpack
+// encoder; the results are identical in format to the individual Sign methods.
+//
+// Email Changes
+// EmailChangeToken is sent to the new address of a user and carries the user ID
+// together with the old and new address, all of which are signed. Redeeming it
+// proves ownership of the new address; use it instead of a VerificationToken,
+// which cannot tell which address is replaced.
+//
+// Download Links
+// DownloadToken grants time-boxed access to a blob in a space, optionally limited
+// to MaxUses downloads counted by a UsageCounter. Exchange verifies it and returns
+// a bucket signed URL that expires after five minutes, so that the API can
+// redirect to the storage provider without handing out long-lived provider URLs.
+//
+// MFA Enrollment
+// TOTPToken holds the TOTP secret of a pending authenticator enrollment
+// server-side; URI renders it as otpauth:// URI for a QR code, and
+// ConfirmEnrollment checks the first code. VerifyTOTPCode implements RFC 6238
+// (SHA1, 6 digits, 30 seconds) with a configurable skew window and returns the
+// matched time step for replay protection. GenerateRecoveryCodes, HashRecoveryCode
+// and MatchRecoveryCode handle single-use recovery codes, of which only hashes
+// are stored. The iam/totp manager builds on the same functions.
+//
+// Key Rotation
+// Instead of a per-token key, tokens can be signed with a server-side KeyRing
+// (SignWithKeyRing, SigningInfo.SignTokenWithKeyRing). The secret then holds the
+// nonce only, and the signed KeyVersion selects the ring key on verification
+// (VerifyWithKeyRing). To rotate, create a ring with a new current version that
+// keeps the previous keys until the tokens signed with them have expired;
+// outstanding links stay valid. Tokens signed with a retired version fail with
+// ErrUnknownKeyVersion.
+//
 // Migration / Extension
 // For new token types: define struct embedding SigningInfo, provide constructor that calls
 // NewSigningInfo with domain‑appropriate TTL, a Sign method that marshals & calls signData,
-// and a Verify method mirroring existing examples OR implement URLToken and reuse VerifyToken.
+// and implement URLToken to reuse VerifyToken. A TokenType method names the type in
+// verification telemetry.
 //
 // Testing Guidance
 // Tests should cover: successful Sign/Verify, tampering (field modification), invalid secret
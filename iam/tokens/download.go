@@ -0,0 +1,173 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"context"
+	"time"
+)
+
+// DownloadToken grants time-boxed access to a blob, e.g. an evidence file,
+// through a link that resolves via the API instead of exposing a storage
+// provider URL. On each use it is exchanged for a short-lived signed URL of
+// the bucket; see Exchange.
+type DownloadToken struct {
+	// Key is the blob key in the bucket.
+	Key string `msgpack:"key"`
+	// SpaceID is the space the blob belongs to, e.g. for authorization and
+	// audit logging of downloads.
+	SpaceID string `msgpack:"space_id"`
+	// MaxUses limits how often the token can be exchanged. Zero allows an
+	// unlimited number of downloads until the token expires.
+	MaxUses int `msgpack:"max_uses"`
+	// SigningInfo contains the cryptographic information for the token.
+	SigningInfo
+}
+
+// NewDownloadToken creates a download token for key in spaceID that can be
+// used maxUses times (0 for unlimited) within ttl.
+func NewDownloadToken(spaceID, key string, maxUses int, ttl time.Duration) (*DownloadToken, error) {
+	token := &DownloadToken{
+		Key:     key,
+		SpaceID: spaceID,
+		MaxUses: maxUses,
+	}
+
+	if err := token.Validate(); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	if token.SigningInfo, err = NewSigningInfo(ttl); err != nil {
+		return nil, err
+	}
+
+	// Limited tokens are counted by their ID.
+	if maxUses > 0 {
+		if err := token.AssignTokenID(); err != nil {
+			return nil, err
+		}
+	}
+
+	return token, nil
+}
+
+// Sign creates a base64 URL encoded signature for the download token. See VerificationToken.Sign.
+func (t *DownloadToken) Sign() (string, []byte, error) {
+	return t.SignToken(t)
+}
+
+// Validate checks that the token has a blob key, a space ID and a
+// non-negative MaxUses.
+func (t *DownloadToken) Validate() error {
+	switch {
+	case t.Key == "":
+		return ErrTokenMissingBlobKey
+	case t.SpaceID == "":
+		return ErrTokenMissingSpaceID
+	case t.MaxUses < 0:
+		return ErrInvalidMaxUses
+	}
+
+	return nil
+}
+
+// SetNonce sets the nonce for verification (implements URLToken contract).
+func (t *DownloadToken) SetNonce(nonce []byte) {
+	t.Nonce = nonce
+}
+
+// TokenType returns TokenTypeDownload.
+func (t *DownloadToken) TokenType() string {
+	return TokenTypeDownload
+}
+
+// Verify performs full validation (required fields, expiration, signature) for a DownloadToken.
+func (t *DownloadToken) Verify(signature string, secret []byte, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeDownload, VerifyFailureMalformed)
+		return err
+	}
+
+	return t.VerifyToken(t, signature, secret, opts...)
+}
+
+// UsageCounter counts the uses of tokens with a use limit.
+//
+// Increment must atomically increment the uses of tokenID and return the
+// new count, e.g. with an upsert on a table keyed by the token ID. Counts
+// can be deleted once the token has expired.
+type UsageCounter interface {
+	Increment(ctx context.Context, tokenID string) (int, error)
+}
+
+// SignURLFunc returns a signed URL to download the blob at key that is
+// valid for expiry. It is usually a closure around blob.Bucket.SignedURL:
+//
+//	sign := func(ctx context.Context, key string, expiry time.Duration) (string, error) {
+//		return bucket.SignedURL(ctx, key, &blob.SignedURLOptions{
+//			Expiry:             expiry,
+//			ContentDisposition: "attachment",
+//		})
+//	}
+type SignURLFunc func(ctx context.Context, key string, expiry time.Duration) (string, error)
+
+// Exchange verifies the token like Verify, counts the use with counter and
+// returns a signed URL of the blob that expires after
+// downloadURLExpiryMinutes (5) minutes, or earlier if the token does. The
+// API redirects the client to the URL, so that provider URLs are never
+// shared and expire quickly.
+//
+// Tokens with MaxUses require a counter and fail with ErrDownloadLimitReached
+// once they were used MaxUses times; counter may be nil for tokens without
+// limit. The outcome is reported to the observer installed with
+// SetVerifyObserver.
+func (t *DownloadToken) Exchange(ctx context.Context, signature string, secret []byte, counter UsageCounter, sign SignURLFunc) (string, error) {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeDownload, VerifyFailureMalformed)
+		return "", err
+	}
+
+	err := t.verifyToken(t, signature, secret, newVerifyConfig(nil))
+	if err == nil && t.MaxUses > 0 {
+		err = t.countUse(ctx, counter)
+	}
+
+	observeVerify(TokenTypeDownload, err)
+
+	if err != nil {
+		return "", err
+	}
+
+	expiry := time.Minute * downloadURLExpiryMinutes
+	if remaining := time.Until(t.ExpiresAt); remaining < expiry {
+		expiry = remaining
+	}
+
+	return sign(ctx, t.Key, expiry)
+}
+
+// countUse increments the uses of the token and checks them against
+// MaxUses.
+func (t *DownloadToken) countUse(ctx context.Context, counter UsageCounter) error {
+	if t.TokenID == "" {
+		return ErrTokenMissingID
+	}
+
+	if counter == nil {
+		return ErrUsageCounterRequired
+	}
+
+	uses, err := counter.Increment(ctx, t.TokenID)
+	if err != nil {
+		return err
+	}
+
+	if uses > t.MaxUses {
+		return ErrDownloadLimitReached
+	}
+
+	return nil
+}
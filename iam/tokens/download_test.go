@@ -0,0 +1,115 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memCounter is an in-memory UsageCounter.
+type memCounter struct {
+	mu   sync.Mutex
+	uses map[string]int
+}
+
+func (c *memCounter) Increment(_ context.Context, tokenID string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.uses == nil {
+		c.uses = map[string]int{}
+	}
+
+	c.uses[tokenID]++
+
+	return c.uses[tokenID], nil
+}
+
+// fakeSigner records the requested expiry and returns a fixed URL.
+type fakeSigner struct {
+	expiry time.Duration
+}
+
+func (s *fakeSigner) sign(_ context.Context, key string, expiry time.Duration) (string, error) {
+	s.expiry = expiry
+	return "https://storage.example.com/" + key + "?sig=abc", nil
+}
+
+func TestDownloadToken_Exchange(t *testing.T) {
+	observed := recordVerifications(t)
+	ctx := context.Background()
+
+	token, err := tokens.NewDownloadToken("space-1", "evidence/report.pdf", 2, time.Hour)
+	require.NoError(t, err)
+
+	signature, secret, err := token.Sign()
+	require.NoError(t, err)
+
+	counter := &memCounter{}
+	signer := &fakeSigner{}
+
+	for range 2 {
+		url, err := token.Exchange(ctx, signature, secret, counter, signer.sign)
+		require.NoError(t, err)
+		assert.Equal(t, "https://storage.example.com/evidence/report.pdf?sig=abc", url)
+		assert.Equal(t, 5*time.Minute, signer.expiry)
+	}
+
+	_, err = token.Exchange(ctx, signature, secret, counter, signer.sign)
+	assert.ErrorIs(t, err, tokens.ErrDownloadLimitReached)
+
+	_, err = token.Exchange(ctx, signature, secret, nil, signer.sign)
+	assert.ErrorIs(t, err, tokens.ErrUsageCounterRequired)
+
+	token.Key = "evidence/other.pdf"
+	_, err = token.Exchange(ctx, signature, secret, counter, signer.sign)
+	assert.ErrorIs(t, err, tokens.ErrTokenInvalid)
+
+	assert.Equal(t, []observation{
+		{tokens.TokenTypeDownload, tokens.VerifyFailureNone},
+		{tokens.TokenTypeDownload, tokens.VerifyFailureNone},
+		{tokens.TokenTypeDownload, tokens.VerifyFailureReplayed},
+		{tokens.TokenTypeDownload, tokens.VerifyFailureError},
+		{tokens.TokenTypeDownload, tokens.VerifyFailureTampered},
+	}, observed())
+}
+
+func TestDownloadToken_Unlimited(t *testing.T) {
+	token, err := tokens.NewDownloadToken("space-1", "evidence/report.pdf", 0, 2*time.Minute)
+	require.NoError(t, err)
+
+	signature, secret, err := token.Sign()
+	require.NoError(t, err)
+
+	signer := &fakeSigner{}
+
+	for range 3 {
+		_, err := token.Exchange(context.Background(), signature, secret, nil, signer.sign)
+		require.NoError(t, err)
+	}
+
+	assert.LessOrEqual(t, signer.expiry, 2*time.Minute, "signed URL must not outlive the token")
+	require.NoError(t, token.Verify(signature, secret))
+}
+
+func TestNewDownloadToken_Validation(t *testing.T) {
+	_, err := tokens.NewDownloadToken("space-1", "", 1, time.Hour)
+	assert.ErrorIs(t, err, tokens.ErrTokenMissingBlobKey)
+
+	_, err = tokens.NewDownloadToken("", "evidence/report.pdf", 1, time.Hour)
+	assert.ErrorIs(t, err, tokens.ErrTokenMissingSpaceID)
+
+	_, err = tokens.NewDownloadToken("space-1", "evidence/report.pdf", -1, time.Hour)
+	assert.ErrorIs(t, err, tokens.ErrInvalidMaxUses)
+
+	_, err = tokens.NewDownloadToken("space-1", "evidence/report.pdf", 1, 0)
+	assert.ErrorIs(t, err, tokens.ErrExpirationIsRequired)
+}
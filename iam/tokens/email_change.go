@@ -0,0 +1,113 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"context"
+	"time"
+)
+
+// EmailChangeToken confirms a change of the email address of a user. It is
+// sent to the new address; redeeming it proves ownership of that address
+// and replaces OldEmail with NewEmail. OldEmail is signed as well, so a link
+// becomes useless once the address has changed otherwise in the meantime.
+type EmailChangeToken struct {
+	UserID   string `msgpack:"user_id"`
+	OldEmail string `msgpack:"old_email"`
+	NewEmail string `msgpack:"new_email"`
+	SigningInfo
+}
+
+// NewEmailChangeToken creates a token for changing the email address of
+// userID from oldEmail to newEmail that expires in
+// emailChangeExpirationHours (24) hours.
+func NewEmailChangeToken(userID, oldEmail, newEmail string) (token *EmailChangeToken, err error) {
+	if userID == "" {
+		return nil, ErrMissingUserID
+	}
+
+	if oldEmail == "" || newEmail == "" {
+		return nil, ErrMissingEmail
+	}
+
+	token = &EmailChangeToken{
+		UserID:   userID,
+		OldEmail: oldEmail,
+		NewEmail: newEmail,
+	}
+
+	if token.SigningInfo, err = NewSigningInfo(time.Hour * emailChangeExpirationHours); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Sign creates a base64 URL encoded signature for the email change token. See VerificationToken.Sign.
+func (t *EmailChangeToken) Sign() (string, []byte, error) {
+	return t.SignToken(t)
+}
+
+// Verify checks that a token was signed with the secret, required fields are present,
+// and it has not expired.
+func (t *EmailChangeToken) Verify(signature string, secret []byte, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeEmailChange, VerifyFailureMalformed)
+		return err
+	}
+
+	return t.VerifyToken(t, signature, secret, opts...)
+}
+
+// SignWithKeyRing signs the token with the current key of ring. See
+// OrganizationInviteToken.SignWithKeyRing.
+func (t *EmailChangeToken) SignWithKeyRing(ring *KeyRing) (string, []byte, error) {
+	return t.SignTokenWithKeyRing(t, ring)
+}
+
+// VerifyWithKeyRing verifies a token signed with SignWithKeyRing. See
+// OrganizationInviteToken.VerifyWithKeyRing.
+func (t *EmailChangeToken) VerifyWithKeyRing(signature string, secret []byte, ring *KeyRing, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeEmailChange, VerifyFailureMalformed)
+		return err
+	}
+
+	return t.VerifyTokenWithKeyRing(t, signature, secret, ring, opts...)
+}
+
+// Consume verifies the token like Verify and marks it as used with
+// consumer, so that it cannot be redeemed again; see Consumer.
+func (t *EmailChangeToken) Consume(ctx context.Context, signature string, secret []byte, consumer Consumer) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeEmailChange, VerifyFailureMalformed)
+		return err
+	}
+
+	return t.ConsumeToken(ctx, t, signature, secret, consumer)
+}
+
+// Validate checks that the token has a user ID and both email addresses.
+func (t *EmailChangeToken) Validate() error {
+	switch {
+	case t.UserID == "":
+		return ErrTokenMissingUserID
+	case t.OldEmail == "":
+		return ErrTokenMissingOldEmail
+	case t.NewEmail == "":
+		return ErrTokenMissingNewEmail
+	}
+
+	return nil
+}
+
+// SetNonce sets the nonce for verification (implements URLToken contract).
+func (t *EmailChangeToken) SetNonce(nonce []byte) {
+	t.Nonce = nonce
+}
+
+// TokenType returns TokenTypeEmailChange.
+func (t *EmailChangeToken) TokenType() string {
+	return TokenTypeEmailChange
+}
@@ -12,4 +12,103 @@ var (
 	// ErrMissingUserID is returned at construction time (NewResetToken) when the
 	// caller supplies an empty user id.
 	ErrMissingUserID = errors.New("unable to create reset token, user id is required")
+	// ErrMissingOrganizationID is returned at construction time (NewIntegrationStateToken)
+	// when the caller supplies an empty organization id.
+	ErrMissingOrganizationID = errors.New("unable to create integration state token, organization id is required")
+	// ErrMissingIntegrationType is returned at construction time (NewIntegrationStateToken)
+	// when the caller supplies an empty integration type.
+	ErrMissingIntegrationType = errors.New("unable to create integration state token, integration type is required")
+	// ErrTokenMissingOrganizationID is returned during verification when the
+	// IntegrationStateToken lacks an OrganizationID.
+	ErrTokenMissingOrganizationID = errors.New("integration state token is missing organization id")
+	// ErrTokenMissingIntegrationType is returned during verification when the
+	// IntegrationStateToken lacks an IntegrationType.
+	ErrTokenMissingIntegrationType = errors.New("integration state token is missing integration type")
+	// ErrInvalidRedirectURL is returned when the redirect target is neither a
+	// relative path nor an absolute http(s) URL.
+	ErrInvalidRedirectURL = errors.New("invalid redirect url")
+	// ErrOrganizationMismatch is returned when an integration callback is received
+	// for a different organization than the one that initiated the handshake.
+	ErrOrganizationMismatch = errors.New("integration state token was issued for a different organization")
+	// ErrIntegrationTypeMismatch is returned when an integration callback is received
+	// for a different integration than the one that was initiated.
+	ErrIntegrationTypeMismatch = errors.New("integration state token was issued for a different integration")
+	// ErrFingerprintMismatch is returned by strict fingerprint verification when
+	// a token is redeemed by a different client than the one it was issued to.
+	ErrFingerprintMismatch = errors.New("token was issued to a different client")
+	// ErrTokenMissingOIDCNonce is returned during verification when the
+	// OAuthStateToken lacks an OIDC nonce.
+	ErrTokenMissingOIDCNonce = errors.New("oauth state token is missing oidc nonce")
+	// ErrUnsupportedPKCEMethod is returned when a PKCE challenge does not use S256.
+	ErrUnsupportedPKCEMethod = errors.New("unsupported pkce code challenge method")
+	// ErrInvalidPKCEVerifier is returned when a PKCE code verifier violates the
+	// length or character set required by RFC 7636.
+	ErrInvalidPKCEVerifier = errors.New("invalid pkce code verifier")
+	// ErrPKCEMismatch is returned when a PKCE code verifier does not match the challenge.
+	ErrPKCEMismatch = errors.New("pkce code verifier does not match challenge")
+	// ErrInvalidSigningKey is returned when a JWT signer is created with a
+	// missing key ID or a key that does not match the algorithm.
+	ErrInvalidSigningKey = errors.New("invalid jwt signing key")
+	// ErrUnknownKeyID is returned when a JWT token is signed with a key the
+	// verifier does not know.
+	ErrUnknownKeyID = errors.New("jwt token is signed with an unknown key")
+	// ErrMalformedJWT is returned when a JWT token is not a compact JWS with
+	// a JSON header and claims.
+	ErrMalformedJWT = errors.New("malformed jwt token")
+	// ErrTokenTypeMismatch is returned when a JWT token of a different type
+	// is presented, e.g. a reset token as invite.
+	ErrTokenTypeMismatch = errors.New("jwt token has an unexpected type")
+	// ErrTokenKindRegistered is returned when a kind or token type is
+	// registered twice with a TokenRegistry.
+	ErrTokenKindRegistered = errors.New("token kind is already registered")
+	// ErrUnknownTokenKind is returned when a token kind or type is not
+	// registered with the TokenRegistry.
+	ErrUnknownTokenKind = errors.New("unknown token kind")
+	// ErrTokenKindMismatch is returned when a registry token of a different
+	// kind is presented, e.g. a reset token as invite.
+	ErrTokenKindMismatch = errors.New("token has an unexpected kind")
+	// ErrMalformedToken is returned when a registry token cannot be decoded
+	// or lacks required fields.
+	ErrMalformedToken = errors.New("malformed token")
+	// ErrTokenAlreadyUsed is returned by a Consumer when a one-time token
+	// is presented a second time.
+	ErrTokenAlreadyUsed = errors.New("token has already been used")
+	// ErrTokenMissingID is returned when a token without ID, e.g. one issued
+	// before token IDs were introduced, is consumed.
+	ErrTokenMissingID = errors.New("token is missing token id")
+	// ErrInvalidKeyRing is returned when a KeyRing is created without the
+	// current key, with version 0 or with a key shorter than 32 bytes.
+	ErrInvalidKeyRing = errors.New("invalid token key ring")
+	// ErrUnknownKeyVersion is returned when a token is signed with a key
+	// version that is not part of the KeyRing, e.g. a retired key.
+	ErrUnknownKeyVersion = errors.New("token is signed with an unknown key version")
+	// ErrTokenMissingTOTPSecret is returned during verification when the
+	// TOTPToken lacks a secret.
+	ErrTokenMissingTOTPSecret = errors.New("totp token is missing secret")
+	// ErrInvalidTOTPSecret is returned when a TOTP secret is not base32 encoded.
+	ErrInvalidTOTPSecret = errors.New("invalid totp secret")
+	// ErrInvalidTOTPCode is returned when a TOTP code does not match the
+	// secret within the skew window.
+	ErrInvalidTOTPCode = errors.New("invalid totp code")
+	// ErrInvalidRecoveryCodeCount is returned when a negative number of
+	// recovery codes is requested.
+	ErrInvalidRecoveryCodeCount = errors.New("invalid recovery code count")
+	// ErrTokenMissingBlobKey is returned when a DownloadToken lacks a blob key.
+	ErrTokenMissingBlobKey = errors.New("download token is missing blob key")
+	// ErrTokenMissingSpaceID is returned when a DownloadToken lacks a space id.
+	ErrTokenMissingSpaceID = errors.New("download token is missing space id")
+	// ErrInvalidMaxUses is returned when a DownloadToken has a negative use limit.
+	ErrInvalidMaxUses = errors.New("download token max uses must not be negative")
+	// ErrUsageCounterRequired is returned when a DownloadToken with a use
+	// limit is exchanged without UsageCounter.
+	ErrUsageCounterRequired = errors.New("usage counter is required for tokens with a use limit")
+	// ErrDownloadLimitReached is returned when a DownloadToken is exchanged
+	// more often than its MaxUses allow.
+	ErrDownloadLimitReached = errors.New("download token has reached its use limit")
+	// ErrTokenMissingOldEmail is returned during verification when the
+	// EmailChangeToken lacks the current email address.
+	ErrTokenMissingOldEmail = errors.New("email change token is missing old email address")
+	// ErrTokenMissingNewEmail is returned during verification when the
+	// EmailChangeToken lacks the new email address.
+	ErrTokenMissingNewEmail = errors.New("email change token is missing new email address")
 )
@@ -0,0 +1,127 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/netip"
+	"strings"
+)
+
+// Network prefix lengths used to bind client IP addresses. Binding the
+// network instead of the exact address tolerates address changes within the
+// same provider network, e.g. DHCP renewals or IPv6 privacy extensions.
+const (
+	fingerprintIPv4Bits = 24
+	fingerprintIPv6Bits = 48
+)
+
+// IssueContext describes the client a token is issued to, e.g. the browser
+// that requested a password reset. All fields are optional; only non-empty
+// fields contribute to the fingerprint.
+type IssueContext struct {
+	// UserAgent is the User-Agent header of the request.
+	UserAgent string
+	// IPAddress is the client IP address. Only its network (/24 for IPv4,
+	// /48 for IPv6) is bound.
+	IPAddress string
+	// DeviceID is an application-defined device identifier, e.g. from a
+	// long-lived device cookie.
+	DeviceID string
+}
+
+// Fingerprint returns the hex encoded SHA-256 hash of the context, or the
+// empty string if all fields are empty. Only the hash is stored in tokens.
+func (c IssueContext) Fingerprint() string {
+	ip := normalizeFingerprintIP(c.IPAddress)
+	ua := strings.TrimSpace(c.UserAgent)
+	device := strings.TrimSpace(c.DeviceID)
+
+	if ua == "" && ip == "" && device == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte("v1\x00" + ua + "\x00" + ip + "\x00" + device))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeFingerprintIP returns the network of addr, or addr itself if it is
+// not an IP address.
+func normalizeFingerprintIP(addr string) string {
+	addr = strings.TrimSpace(addr)
+
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return addr
+	}
+
+	ip = ip.Unmap()
+
+	bits := fingerprintIPv6Bits
+	if ip.Is4() {
+		bits = fingerprintIPv4Bits
+	}
+
+	prefix, err := ip.Prefix(bits)
+	if err != nil {
+		return addr
+	}
+
+	return prefix.String()
+}
+
+// FingerprintPolicy controls how a fingerprint mismatch is handled during
+// verification.
+type FingerprintPolicy int
+
+const (
+	// FingerprintAdvisory accepts tokens used from a different client and
+	// reports the mismatch, e.g. to log it or to require a second factor.
+	FingerprintAdvisory FingerprintPolicy = iota
+	// FingerprintStrict rejects tokens used from a different client with
+	// ErrFingerprintMismatch.
+	FingerprintStrict
+)
+
+// FingerprintResult is the outcome of a fingerprint comparison.
+type FingerprintResult int
+
+const (
+	// FingerprintNotBound means the token carries no fingerprint.
+	FingerprintNotBound FingerprintResult = iota
+	// FingerprintMatched means the token is used from the client it was
+	// issued to.
+	FingerprintMatched
+	// FingerprintMismatched means the token is used from a different client.
+	FingerprintMismatched
+)
+
+// String returns a human readable representation of the result.
+func (r FingerprintResult) String() string {
+	switch r {
+	case FingerprintMatched:
+		return "matched"
+	case FingerprintMismatched:
+		return "mismatched"
+	default:
+		return "not bound"
+	}
+}
+
+// compareFingerprint compares the stored fingerprint hash with the fingerprint
+// of the candidate context in constant time.
+func compareFingerprint(stored string, candidate IssueContext) FingerprintResult {
+	if stored == "" {
+		return FingerprintNotBound
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(candidate.Fingerprint())) == 1 {
+		return FingerprintMatched
+	}
+
+	return FingerprintMismatched
+}
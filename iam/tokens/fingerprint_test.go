@@ -0,0 +1,137 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var issuedFrom = tokens.IssueContext{
+	UserAgent: "Mozilla/5.0 (Macintosh)",
+	IPAddress: "203.0.113.17",
+}
+
+func TestIssueContextFingerprint(t *testing.T) {
+	t.Run("empty context", func(t *testing.T) {
+		assert.Empty(t, tokens.IssueContext{}.Fingerprint())
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		fp := issuedFrom.Fingerprint()
+		assert.Len(t, fp, 64)
+		assert.Equal(t, fp, issuedFrom.Fingerprint())
+	})
+
+	t.Run("same IPv4 network", func(t *testing.T) {
+		other := issuedFrom
+		other.IPAddress = "203.0.113.200"
+		assert.Equal(t, issuedFrom.Fingerprint(), other.Fingerprint())
+	})
+
+	t.Run("different IPv4 network", func(t *testing.T) {
+		other := issuedFrom
+		other.IPAddress = "198.51.100.17"
+		assert.NotEqual(t, issuedFrom.Fingerprint(), other.Fingerprint())
+	})
+
+	t.Run("same IPv6 network", func(t *testing.T) {
+		a := tokens.IssueContext{IPAddress: "2001:db8:1:1::1"}
+		b := tokens.IssueContext{IPAddress: "2001:db8:1:ffff::2"}
+		assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+	})
+
+	t.Run("IPv4-mapped IPv6", func(t *testing.T) {
+		mapped := issuedFrom
+		mapped.IPAddress = "::ffff:203.0.113.17"
+		assert.Equal(t, issuedFrom.Fingerprint(), mapped.Fingerprint())
+	})
+
+	t.Run("different user agent", func(t *testing.T) {
+		other := issuedFrom
+		other.UserAgent = "curl/8.0"
+		assert.NotEqual(t, issuedFrom.Fingerprint(), other.Fingerprint())
+	})
+
+	t.Run("fields are separated", func(t *testing.T) {
+		a := tokens.IssueContext{UserAgent: "ab", DeviceID: "c"}
+		b := tokens.IssueContext{UserAgent: "a", DeviceID: "bc"}
+		assert.NotEqual(t, a.Fingerprint(), b.Fingerprint())
+	})
+}
+
+func TestResetTokenFingerprint(t *testing.T) {
+	t.Run("construction requires user id", func(t *testing.T) {
+		rt, err := tokens.NewResetTokenWithContext("", issuedFrom)
+		assert.Nil(t, rt)
+		assert.ErrorIs(t, err, tokens.ErrMissingUserID)
+	})
+
+	t.Run("matching client", func(t *testing.T) {
+		rt, err := tokens.NewResetTokenWithContext("user-1", issuedFrom)
+		require.NoError(t, err)
+		assert.Equal(t, issuedFrom.Fingerprint(), rt.FingerprintHash)
+
+		sig, secret, err := rt.Sign()
+		require.NoError(t, err)
+
+		result, err := rt.VerifyFingerprint(sig, secret, issuedFrom, tokens.FingerprintStrict)
+		require.NoError(t, err)
+		assert.Equal(t, tokens.FingerprintMatched, result)
+	})
+
+	t.Run("mismatch strict", func(t *testing.T) {
+		rt, err := tokens.NewResetTokenWithContext("user-1", issuedFrom)
+		require.NoError(t, err)
+
+		sig, secret, err := rt.Sign()
+		require.NoError(t, err)
+
+		other := tokens.IssueContext{UserAgent: "curl/8.0", IPAddress: "198.51.100.17"}
+		result, err := rt.VerifyFingerprint(sig, secret, other, tokens.FingerprintStrict)
+		require.ErrorIs(t, err, tokens.ErrFingerprintMismatch)
+		assert.Equal(t, tokens.FingerprintMismatched, result)
+	})
+
+	t.Run("mismatch advisory", func(t *testing.T) {
+		rt, err := tokens.NewResetTokenWithContext("user-1", issuedFrom)
+		require.NoError(t, err)
+
+		sig, secret, err := rt.Sign()
+		require.NoError(t, err)
+
+		result, err := rt.VerifyFingerprint(sig, secret, tokens.IssueContext{}, tokens.FingerprintAdvisory)
+		require.NoError(t, err)
+		assert.Equal(t, tokens.FingerprintMismatched, result)
+		assert.Equal(t, "mismatched", result.String())
+	})
+
+	t.Run("unbound token", func(t *testing.T) {
+		rt, err := tokens.NewResetToken("user-1")
+		require.NoError(t, err)
+
+		sig, secret, err := rt.Sign()
+		require.NoError(t, err)
+
+		result, err := rt.VerifyFingerprint(sig, secret, issuedFrom, tokens.FingerprintStrict)
+		require.NoError(t, err)
+		assert.Equal(t, tokens.FingerprintNotBound, result)
+	})
+
+	t.Run("tampered fingerprint", func(t *testing.T) {
+		rt, err := tokens.NewResetTokenWithContext("user-1", issuedFrom)
+		require.NoError(t, err)
+
+		sig, secret, err := rt.Sign()
+		require.NoError(t, err)
+
+		clone := *rt
+		clone.FingerprintHash = ""
+		_, err = clone.VerifyFingerprint(sig, secret, issuedFrom, tokens.FingerprintAdvisory)
+		assert.ErrorIs(t, err, tokens.ErrTokenInvalid)
+	})
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// IntegrationStateToken is the signed OAuth "state" used while connecting a
+// third-party integration (e.g. Jira, Slack). It binds the provider callback to
+// the organization and integration that initiated the handshake and carries the
+// target to redirect to once the handshake completes.
+type IntegrationStateToken struct {
+	// OrganizationID is the ID of the organization that initiated the handshake.
+	OrganizationID string `msgpack:"organization_id"`
+	// IntegrationType identifies the integration being connected (e.g. "jira").
+	IntegrationType string `msgpack:"integration_type"`
+	// RedirectURL is where the user is sent after the callback was handled.
+	// It is either a relative path or an absolute http(s) URL.
+	RedirectURL string `msgpack:"redirect_url"`
+	// SigningInfo contains the cryptographic information for the token.
+	SigningInfo
+}
+
+// NewIntegrationStateToken creates a state token that expires in
+// integrationStateExpirationMinutes (15) minutes.
+//
+// Parameters:
+//   - organizationID: The ID of the organization connecting the integration
+//   - integrationType: The integration being connected
+//   - redirectURL: Optional target after the handshake (relative path or http(s) URL)
+//
+// Returns:
+//   - *IntegrationStateToken: The created token
+//   - error: If a required field is missing, the redirect target is invalid or signing info cannot be created
+func NewIntegrationStateToken(organizationID, integrationType, redirectURL string) (token *IntegrationStateToken, err error) {
+	if organizationID == "" {
+		return nil, ErrMissingOrganizationID
+	}
+
+	if integrationType == "" {
+		return nil, ErrMissingIntegrationType
+	}
+
+	if err := validateRedirectURL(redirectURL); err != nil {
+		return nil, err
+	}
+
+	token = &IntegrationStateToken{
+		OrganizationID:  organizationID,
+		IntegrationType: integrationType,
+		RedirectURL:     redirectURL,
+	}
+
+	if token.SigningInfo, err = NewSigningInfo(time.Minute * integrationStateExpirationMinutes); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Sign creates a base64 URL encoded signature for the state token. See VerificationToken.Sign.
+// The signature is passed as OAuth state; the token and secret stay server-side.
+func (t *IntegrationStateToken) Sign() (string, []byte, error) {
+	return t.SignToken(t)
+}
+
+// Validate checks that the token has required fields and a safe redirect target.
+func (t *IntegrationStateToken) Validate() error {
+	if t.OrganizationID == "" {
+		return ErrTokenMissingOrganizationID
+	}
+
+	if t.IntegrationType == "" {
+		return ErrTokenMissingIntegrationType
+	}
+
+	return validateRedirectURL(t.RedirectURL)
+}
+
+// SetNonce sets the nonce for verification (implements URLToken contract).
+func (t *IntegrationStateToken) SetNonce(nonce []byte) {
+	t.Nonce = nonce
+}
+
+// TokenType returns TokenTypeIntegrationState.
+func (t *IntegrationStateToken) TokenType() string {
+	return TokenTypeIntegrationState
+}
+
+// Verify performs full validation (required fields, expiration, signature) for an IntegrationStateToken.
+func (t *IntegrationStateToken) Verify(signature string, secret []byte, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeIntegrationState, VerifyFailureMalformed)
+		return err
+	}
+
+	return t.VerifyToken(t, signature, secret, opts...)
+}
+
+// VerifyCallback verifies the token like Verify and additionally checks that
+// the callback belongs to the given organization and integration. Use it in
+// the OAuth callback handler with the organization of the current session so
+// a state issued for one organization cannot complete a handshake for another.
+func (t *IntegrationStateToken) VerifyCallback(signature string, secret []byte, organizationID, integrationType string) error {
+	if err := t.Verify(signature, secret); err != nil {
+		return err
+	}
+
+	if t.OrganizationID != organizationID {
+		return ErrOrganizationMismatch
+	}
+
+	if t.IntegrationType != integrationType {
+		return ErrIntegrationTypeMismatch
+	}
+
+	return nil
+}
+
+// validateRedirectURL accepts empty values, relative paths and absolute http(s)
+// URLs. Protocol-relative URLs ("//host") are rejected to prevent open redirects
+// through scheme-less targets.
+func validateRedirectURL(target string) error {
+	if target == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(target, "//") || strings.HasPrefix(target, `/\`) {
+		return ErrInvalidRedirectURL
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return ErrInvalidRedirectURL
+	}
+
+	if u.IsAbs() {
+		if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return ErrInvalidRedirectURL
+		}
+
+		return nil
+	}
+
+	if u.Host != "" || !strings.HasPrefix(target, "/") {
+		return ErrInvalidRedirectURL
+	}
+
+	return nil
+}
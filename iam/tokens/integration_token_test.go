@@ -0,0 +1,119 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationStateToken(t *testing.T) {
+	t.Run("construction requires organization id", func(t *testing.T) {
+		it, err := tokens.NewIntegrationStateToken("", "jira", "/settings/integrations")
+		assert.Nil(t, it)
+		assert.ErrorIs(t, err, tokens.ErrMissingOrganizationID)
+	})
+
+	t.Run("construction requires integration type", func(t *testing.T) {
+		it, err := tokens.NewIntegrationStateToken("org-1", "", "/settings/integrations")
+		assert.Nil(t, it)
+		assert.ErrorIs(t, err, tokens.ErrMissingIntegrationType)
+	})
+
+	t.Run("expires in 15 minutes", func(t *testing.T) {
+		it, err := tokens.NewIntegrationStateToken("org-1", "jira", "")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), it.ExpiresAt, 5*time.Second)
+	})
+
+	t.Run("sign/verify callback success", func(t *testing.T) {
+		it, err := tokens.NewIntegrationStateToken("org-1", "jira", "https://app.kopexa.com/settings")
+		require.NoError(t, err)
+		sig, secret, err := it.Sign()
+		require.NoError(t, err)
+		assert.NoError(t, it.VerifyCallback(sig, secret, "org-1", "jira"))
+	})
+
+	t.Run("callback for other organization", func(t *testing.T) {
+		it, err := tokens.NewIntegrationStateToken("org-1", "jira", "")
+		require.NoError(t, err)
+		sig, secret, err := it.Sign()
+		require.NoError(t, err)
+		assert.ErrorIs(t, it.VerifyCallback(sig, secret, "org-2", "jira"), tokens.ErrOrganizationMismatch)
+	})
+
+	t.Run("callback for other integration", func(t *testing.T) {
+		it, err := tokens.NewIntegrationStateToken("org-1", "jira", "")
+		require.NoError(t, err)
+		sig, secret, err := it.Sign()
+		require.NoError(t, err)
+		assert.ErrorIs(t, it.VerifyCallback(sig, secret, "org-1", "slack"), tokens.ErrIntegrationTypeMismatch)
+	})
+
+	t.Run("tampered organization id", func(t *testing.T) {
+		it, err := tokens.NewIntegrationStateToken("org-1", "jira", "")
+		require.NoError(t, err)
+		sig, secret, err := it.Sign()
+		require.NoError(t, err)
+
+		clone := *it
+		clone.OrganizationID = "org-2"
+		assert.ErrorIs(t, clone.Verify(sig, secret), tokens.ErrTokenInvalid)
+	})
+
+	t.Run("tampered redirect", func(t *testing.T) {
+		it, err := tokens.NewIntegrationStateToken("org-1", "jira", "/settings")
+		require.NoError(t, err)
+		sig, secret, err := it.Sign()
+		require.NoError(t, err)
+
+		clone := *it
+		clone.RedirectURL = "/elsewhere"
+		assert.ErrorIs(t, clone.Verify(sig, secret), tokens.ErrTokenInvalid)
+	})
+
+	t.Run("expired state token", func(t *testing.T) {
+		it, err := tokens.NewIntegrationStateToken("org-1", "jira", "")
+		require.NoError(t, err)
+		sig, secret, err := it.Sign()
+		require.NoError(t, err)
+
+		expired := *it
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		assert.ErrorIs(t, expired.Verify(sig, secret), tokens.ErrTokenExpired)
+	})
+}
+
+func TestIntegrationStateToken_RedirectURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		wantErr bool
+	}{
+		{name: "empty", target: ""},
+		{name: "relative path", target: "/settings/integrations?tab=jira"},
+		{name: "https url", target: "https://app.kopexa.com/settings"},
+		{name: "http url", target: "http://localhost:3000/settings"},
+		{name: "protocol relative", target: "//evil.example.com", wantErr: true},
+		{name: "backslash trick", target: `/\evil.example.com`, wantErr: true},
+		{name: "javascript scheme", target: "javascript:alert(1)", wantErr: true},
+		{name: "relative without slash", target: "settings", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tokens.NewIntegrationStateToken("org-1", "jira", tt.target)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tokens.ErrInvalidRedirectURL)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/internal/jws"
+)
+
+// JWTAlgorithm is the signature algorithm of a JWTToken.
+type JWTAlgorithm string
+
+const (
+	// JWTAlgorithmES256 signs with ECDSA on P-256 and SHA-256.
+	JWTAlgorithmES256 JWTAlgorithm = "ES256"
+	// JWTAlgorithmEdDSA signs with Ed25519.
+	JWTAlgorithmEdDSA JWTAlgorithm = "EdDSA"
+)
+
+const (
+	// jwtType is the "typ" header value of JWT tokens.
+	jwtType = "JWT"
+	// jwtIDLength is the number of random bytes of a JWT ID.
+	jwtIDLength = 16
+	// es256CoordinateSize is the size of r and s of an ES256 signature.
+	es256CoordinateSize = 32
+)
+
+// Signer signs JWT tokens. Implementations may keep the private key in a KMS
+// or HSM; NewES256Signer and NewEdDSASigner sign with keys in memory.
+type Signer interface {
+	// Algorithm returns the signature algorithm.
+	Algorithm() JWTAlgorithm
+	// KeyID returns the "kid" header verifiers look the public key up by.
+	KeyID() string
+	// Sign returns the signature of signingInput. ES256 signatures are the
+	// 64-byte concatenation of r and s (RFC 7518, section 3.4).
+	Sign(signingInput []byte) ([]byte, error)
+}
+
+// Verifier verifies the signatures of JWT tokens. It only needs public keys,
+// so services without access to the secret store can verify tokens.
+type Verifier interface {
+	// Verify checks signature over signingInput with the key identified by
+	// keyID. It must return ErrUnknownKeyID for unknown keys and
+	// ErrTokenInvalid if alg is not the algorithm of the key or the
+	// signature does not match.
+	Verify(alg JWTAlgorithm, keyID string, signingInput, signature []byte) error
+}
+
+// keySigner is a Signer with an in-memory private key.
+type keySigner struct {
+	alg   JWTAlgorithm
+	keyID string
+	key   crypto.Signer
+}
+
+// NewES256Signer returns a Signer for a P-256 private key.
+func NewES256Signer(keyID string, key *ecdsa.PrivateKey) (Signer, error) {
+	if keyID == "" || key == nil || key.Curve != elliptic.P256() {
+		return nil, ErrInvalidSigningKey
+	}
+
+	return &keySigner{alg: JWTAlgorithmES256, keyID: keyID, key: key}, nil
+}
+
+// NewEdDSASigner returns a Signer for an Ed25519 private key.
+func NewEdDSASigner(keyID string, key ed25519.PrivateKey) (Signer, error) {
+	if keyID == "" || len(key) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidSigningKey
+	}
+
+	return &keySigner{alg: JWTAlgorithmEdDSA, keyID: keyID, key: key}, nil
+}
+
+// Algorithm implements Signer.
+func (s *keySigner) Algorithm() JWTAlgorithm {
+	return s.alg
+}
+
+// KeyID implements Signer.
+func (s *keySigner) KeyID() string {
+	return s.keyID
+}
+
+// Sign implements Signer.
+func (s *keySigner) Sign(signingInput []byte) ([]byte, error) {
+	switch key := s.key.(type) {
+	case ed25519.PrivateKey:
+		return ed25519.Sign(key, signingInput), nil
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(signingInput)
+
+		r, sig, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			return nil, ErrFailedSigning.With(err)
+		}
+
+		out := make([]byte, 2*es256CoordinateSize)
+		r.FillBytes(out[:es256CoordinateSize])
+		sig.FillBytes(out[es256CoordinateSize:])
+
+		return out, nil
+	default:
+		return nil, ErrInvalidSigningKey
+	}
+}
+
+// PublicKeys is a Verifier over public keys by key ID. Values must be
+// *ecdsa.PublicKey on P-256 for ES256 or ed25519.PublicKey for EdDSA. The
+// algorithm is taken from the key, never from the token header, so a token
+// cannot select a different algorithm for a key.
+type PublicKeys map[string]crypto.PublicKey
+
+// Verify implements Verifier.
+func (k PublicKeys) Verify(alg JWTAlgorithm, keyID string, signingInput, signature []byte) error {
+	key, ok := k[keyID]
+	if !ok {
+		return ErrUnknownKeyID
+	}
+
+	if !verifyJWTSignature(key, alg, signingInput, signature) {
+		return ErrTokenInvalid
+	}
+
+	return nil
+}
+
+// verifyJWTSignature checks signature over signingInput with key if alg is
+// the algorithm of key.
+func verifyJWTSignature(key crypto.PublicKey, alg JWTAlgorithm, signingInput, signature []byte) bool {
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		return alg == JWTAlgorithmEdDSA && len(key) == ed25519.PublicKeySize &&
+			ed25519.Verify(key, signingInput, signature)
+	case *ecdsa.PublicKey:
+		if alg != JWTAlgorithmES256 || key.Curve != elliptic.P256() || len(signature) != 2*es256CoordinateSize {
+			return false
+		}
+
+		digest := sha256.Sum256(signingInput)
+		r := new(big.Int).SetBytes(signature[:es256CoordinateSize])
+		s := new(big.Int).SetBytes(signature[es256CoordinateSize:])
+
+		return ecdsa.Verify(key, digest[:], r, s)
+	default:
+		return false
+	}
+}
+
+// JWTToken is a self-contained alternative to the HMAC-signed token types.
+// It is signed with an asymmetric key (ES256 or EdDSA), so it can be verified
+// with the public key alone, without a per-token secret stored server-side.
+//
+// The token types share one claim set: Subject holds the email address for
+// invite and verification tokens and the user ID for reset tokens.
+//
+// Unlike HMAC-signed tokens, a JWTToken cannot be revoked by deleting its
+// secret; it stays valid until it expires. ConsumeJWTToken limits it to a
+// single use.
+type JWTToken struct {
+	// ID uniquely identifies the token ("jti").
+	ID string `json:"jti"`
+	// Type is the token type, e.g. TokenTypeInvite. Verification rejects
+	// tokens of other types, so a reset token cannot be used as an invite.
+	Type string `json:"token_type"`
+	// Subject is the email address or user ID the token was issued for ("sub").
+	Subject string `json:"sub"`
+	// OrganizationID is the organization of an invite token.
+	OrganizationID string `json:"org_id,omitempty"`
+	// FingerprintHash optionally binds a reset token to the client that
+	// requested it; see NewJWTResetTokenWithContext.
+	FingerprintHash string `json:"fph,omitempty"`
+	// IssuedAt is the Unix time the token was created ("iat").
+	IssuedAt int64 `json:"iat"`
+	// ExpiresAt is the Unix time the token expires ("exp").
+	ExpiresAt int64 `json:"exp"`
+}
+
+// NewJWTToken creates a token of tokenType for subject that expires after
+// expires.
+func NewJWTToken(tokenType, subject string, expires time.Duration) (*JWTToken, error) {
+	if expires == 0 {
+		return nil, ErrExpirationIsRequired
+	}
+
+	id := make([]byte, jwtIDLength)
+	if _, err := rand.Read(id); err != nil {
+		return nil, ErrFailedSigning.With(err)
+	}
+
+	issuedAt := now()
+
+	return &JWTToken{
+		ID:        base64.RawURLEncoding.EncodeToString(id),
+		Type:      tokenType,
+		Subject:   subject,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(expires).Unix(),
+	}, nil
+}
+
+// NewJWTInviteToken creates an invite token for email that expires in
+// inviteExpirationDays (14) days, like NewOrganizationInviteToken.
+func NewJWTInviteToken(email, organizationID string) (*JWTToken, error) {
+	if email == "" {
+		return nil, ErrInviteTokenMissingEmail
+	}
+
+	token, err := NewJWTToken(TokenTypeInvite, email, time.Hour*24*inviteExpirationDays)
+	if err != nil {
+		return nil, err
+	}
+
+	token.OrganizationID = organizationID
+
+	return token, nil
+}
+
+// NewJWTVerificationToken creates an email verification token that expires
+// in expirationDays (7) days, like NewVerificationToken.
+func NewJWTVerificationToken(email string) (*JWTToken, error) {
+	if email == "" {
+		return nil, ErrMissingEmail
+	}
+
+	return NewJWTToken(TokenTypeVerification, email, time.Hour*24*expirationDays)
+}
+
+// NewJWTResetToken creates a password reset token for a user ID that expires
+// in resetTokenExpirationMinutes (15) minutes, like NewResetToken.
+func NewJWTResetToken(userID string) (*JWTToken, error) {
+	if userID == "" {
+		return nil, ErrMissingUserID
+	}
+
+	return NewJWTToken(TokenTypeReset, userID, time.Minute*resetTokenExpirationMinutes)
+}
+
+// NewJWTResetTokenWithContext creates a reset token like NewJWTResetToken and
+// binds it to the fingerprint of the requesting client.
+func NewJWTResetTokenWithContext(userID string, issue IssueContext) (*JWTToken, error) {
+	token, err := NewJWTResetToken(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token.FingerprintHash = issue.Fingerprint()
+
+	return token, nil
+}
+
+// Sign returns the token in compact JWS serialization, signed with signer.
+// The result is URL-safe and can be embedded in links as is.
+func (t *JWTToken) Sign(signer Signer) (string, error) {
+	if err := t.Validate(); err != nil {
+		return "", err
+	}
+
+	header := jws.Header{Algorithm: string(signer.Algorithm()), KeyID: signer.KeyID(), Type: jwtType}
+
+	return jws.Sign(header, t, signer.Sign)
+}
+
+// ParseJWTToken verifies the signature, type and expiration of token and
+// returns its claims. tokenType is the expected type, e.g. TokenTypeReset.
+// The outcome is reported to the observer installed with SetVerifyObserver.
+//
+// Example:
+//
+//	token, err := tokens.ParseJWTToken(r.URL.Query().Get("token"), tokens.TokenTypeReset, keys)
+//	if err != nil {
+//	    return err
+//	}
+//	userID := token.Subject
+func ParseJWTToken(token, tokenType string, verifier Verifier) (*JWTToken, error) {
+	return verifyJWTToken(context.Background(), token, tokenType, verifier, nil)
+}
+
+// ConsumeJWTToken parses token like ParseJWTToken and then marks its ID as
+// used with consumer, so that it cannot be redeemed again; see Consumer.
+func ConsumeJWTToken(ctx context.Context, token, tokenType string, verifier Verifier, consumer Consumer) (*JWTToken, error) {
+	return verifyJWTToken(ctx, token, tokenType, verifier, consumer)
+}
+
+// verifyJWTToken implements ParseJWTToken and, if consumer is not nil,
+// ConsumeJWTToken.
+func verifyJWTToken(ctx context.Context, token, tokenType string, verifier Verifier, consumer Consumer) (*JWTToken, error) {
+	t, err := parseJWTToken(token, tokenType, verifier)
+	if err != nil {
+		observeVerify(tokenType, err)
+		return nil, err
+	}
+
+	if err := t.Validate(); err != nil {
+		notifyVerify(tokenType, VerifyFailureMalformed)
+		return nil, err
+	}
+
+	if consumer != nil {
+		if err := markUsed(ctx, consumer, t.ID); err != nil {
+			observeVerify(tokenType, err)
+			return nil, err
+		}
+	}
+
+	notifyVerify(tokenType, VerifyFailureNone)
+
+	return t, nil
+}
+
+// parseJWTToken implements ParseJWTToken.
+func parseJWTToken(token, tokenType string, verifier Verifier) (*JWTToken, error) {
+	parsed, err := jws.Parse(token, jwtType)
+	if err != nil {
+		return nil, ErrMalformedJWT
+	}
+
+	h := parsed.Header
+	if err := verifier.Verify(JWTAlgorithm(h.Algorithm), h.KeyID, parsed.SigningInput, parsed.Signature); err != nil {
+		return nil, err
+	}
+
+	var t JWTToken
+	if err := parsed.Claims(&t); err != nil {
+		return nil, ErrMalformedJWT
+	}
+
+	if t.Type != tokenType {
+		return nil, ErrTokenTypeMismatch
+	}
+
+	if t.IsExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	return &t, nil
+}
+
+// IsExpired reports whether the token has passed its expiration time.
+func (t *JWTToken) IsExpired() bool {
+	return isExpired(time.Unix(t.ExpiresAt, 0))
+}
+
+// Validate checks the required claims of the token type.
+func (t *JWTToken) Validate() error {
+	switch {
+	case t.Subject != "":
+		return nil
+	case t.Type == TokenTypeInvite:
+		return ErrInviteTokenMissingEmail
+	case t.Type == TokenTypeReset:
+		return ErrTokenMissingUserID
+	default:
+		return ErrTokenMissingEmail
+	}
+}
+
+// VerifyFingerprint compares the fingerprint of a token returned by
+// ParseJWTToken with the client redeeming it, with the semantics of
+// ResetToken.VerifyFingerprint.
+func (t *JWTToken) VerifyFingerprint(candidate IssueContext, policy FingerprintPolicy) (FingerprintResult, error) {
+	result := compareFingerprint(t.FingerprintHash, candidate)
+	if result == FingerprintMismatched && policy == FingerprintStrict {
+		return result, ErrFingerprintMismatch
+	}
+
+	return result, nil
+}
+
+// TokenType returns the type of the token.
+func (t *JWTToken) TokenType() string {
+	return t.Type
+}
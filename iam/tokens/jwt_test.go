@@ -0,0 +1,212 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJWTKeys(t *testing.T) (es256, eddsa tokens.Signer, keys tokens.PublicKeys) {
+	t.Helper()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	es256, err = tokens.NewES256Signer("es-1", ecKey)
+	require.NoError(t, err)
+
+	eddsa, err = tokens.NewEdDSASigner("ed-1", edKey)
+	require.NoError(t, err)
+
+	return es256, eddsa, tokens.PublicKeys{"es-1": &ecKey.PublicKey, "ed-1": edPub}
+}
+
+func TestJWTToken_SignAndParse(t *testing.T) {
+	es256, eddsa, keys := newJWTKeys(t)
+
+	for _, signer := range []tokens.Signer{es256, eddsa} {
+		t.Run(string(signer.Algorithm()), func(t *testing.T) {
+			token, err := tokens.NewJWTInviteToken("rusty@example.com", "org-1")
+			require.NoError(t, err)
+
+			signed, err := token.Sign(signer)
+			require.NoError(t, err)
+			assert.Len(t, strings.Split(signed, "."), 3)
+
+			parsed, err := tokens.ParseJWTToken(signed, tokens.TokenTypeInvite, keys)
+			require.NoError(t, err)
+			assert.Equal(t, token, parsed)
+			assert.Equal(t, "rusty@example.com", parsed.Subject)
+			assert.Equal(t, "org-1", parsed.OrganizationID)
+		})
+	}
+}
+
+func TestJWTToken_Constructors(t *testing.T) {
+	_, err := tokens.NewJWTInviteToken("", "org-1")
+	assert.ErrorIs(t, err, tokens.ErrInviteTokenMissingEmail)
+
+	_, err = tokens.NewJWTVerificationToken("")
+	assert.ErrorIs(t, err, tokens.ErrMissingEmail)
+
+	_, err = tokens.NewJWTResetToken("")
+	assert.ErrorIs(t, err, tokens.ErrMissingUserID)
+
+	_, err = tokens.NewJWTToken(tokens.TokenTypeReset, "user-1", 0)
+	assert.ErrorIs(t, err, tokens.ErrExpirationIsRequired)
+
+	reset, err := tokens.NewJWTResetToken("user-1")
+	require.NoError(t, err)
+	assert.Equal(t, tokens.TokenTypeReset, reset.Type)
+	assert.NotEmpty(t, reset.ID)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), time.Unix(reset.ExpiresAt, 0), time.Minute)
+
+	other, err := tokens.NewJWTResetToken("user-1")
+	require.NoError(t, err)
+	assert.NotEqual(t, reset.ID, other.ID)
+}
+
+func TestParseJWTToken_Rejects(t *testing.T) {
+	es256, eddsa, keys := newJWTKeys(t)
+
+	reset, err := tokens.NewJWTResetToken("user-1")
+	require.NoError(t, err)
+
+	signed, err := reset.Sign(eddsa)
+	require.NoError(t, err)
+
+	parts := strings.Split(signed, ".")
+
+	t.Run("wrong type", func(t *testing.T) {
+		_, err := tokens.ParseJWTToken(signed, tokens.TokenTypeInvite, keys)
+		assert.ErrorIs(t, err, tokens.ErrTokenTypeMismatch)
+	})
+
+	t.Run("tampered claims", func(t *testing.T) {
+		claims := base64.RawURLEncoding.EncodeToString([]byte(`{"jti":"x","token_type":"reset","sub":"admin","iat":1,"exp":99999999999}`))
+
+		_, err := tokens.ParseJWTToken(parts[0]+"."+claims+"."+parts[2], tokens.TokenTypeReset, keys)
+		assert.ErrorIs(t, err, tokens.ErrTokenInvalid)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := tokens.ParseJWTToken(signed, tokens.TokenTypeReset, tokens.PublicKeys{})
+		assert.ErrorIs(t, err, tokens.ErrUnknownKeyID)
+	})
+
+	t.Run("algorithm confusion", func(t *testing.T) {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","kid":"ed-1","typ":"JWT"}`))
+
+		_, err := tokens.ParseJWTToken(header+"."+parts[1]+"."+parts[2], tokens.TokenTypeReset, keys)
+		assert.ErrorIs(t, err, tokens.ErrTokenInvalid)
+	})
+
+	t.Run("key of other signer", func(t *testing.T) {
+		_, err := tokens.ParseJWTToken(signed, tokens.TokenTypeReset, tokens.PublicKeys{"ed-1": keys["es-1"]})
+		assert.ErrorIs(t, err, tokens.ErrTokenInvalid)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := tokens.ParseJWTToken("not-a-jwt", tokens.TokenTypeReset, keys)
+		assert.ErrorIs(t, err, tokens.ErrMalformedJWT)
+
+		_, err = tokens.ParseJWTToken(parts[0]+"."+parts[1]+".***", tokens.TokenTypeReset, keys)
+		assert.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired, err := tokens.NewJWTToken(tokens.TokenTypeReset, "user-1", -time.Minute)
+		require.NoError(t, err)
+
+		signed, err := expired.Sign(es256)
+		require.NoError(t, err)
+
+		_, err = tokens.ParseJWTToken(signed, tokens.TokenTypeReset, keys)
+		assert.ErrorIs(t, err, tokens.ErrTokenExpired)
+	})
+
+	t.Run("missing subject", func(t *testing.T) {
+		token := &tokens.JWTToken{Type: tokens.TokenTypeReset, ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+		_, err := token.Sign(es256)
+		assert.ErrorIs(t, err, tokens.ErrTokenMissingUserID)
+	})
+}
+
+func TestJWTToken_Telemetry(t *testing.T) {
+	_, eddsa, keys := newJWTKeys(t)
+	recorded := recordVerifications(t)
+
+	token, err := tokens.NewJWTVerificationToken("rusty@example.com")
+	require.NoError(t, err)
+
+	signed, err := token.Sign(eddsa)
+	require.NoError(t, err)
+
+	_, err = tokens.ParseJWTToken(signed, tokens.TokenTypeVerification, keys)
+	require.NoError(t, err)
+
+	_, err = tokens.ParseJWTToken(signed, tokens.TokenTypeVerification, tokens.PublicKeys{})
+	require.Error(t, err)
+
+	_, err = tokens.ParseJWTToken("garbage", tokens.TokenTypeVerification, keys)
+	require.Error(t, err)
+
+	assert.Equal(t, []observation{
+		{tokens.TokenTypeVerification, tokens.VerifyFailureNone},
+		{tokens.TokenTypeVerification, tokens.VerifyFailureTampered},
+		{tokens.TokenTypeVerification, tokens.VerifyFailureMalformed},
+	}, recorded())
+}
+
+func TestJWTToken_VerifyFingerprint(t *testing.T) {
+	_, eddsa, keys := newJWTKeys(t)
+
+	token, err := tokens.NewJWTResetTokenWithContext("user-1", issuedFrom)
+	require.NoError(t, err)
+
+	signed, err := token.Sign(eddsa)
+	require.NoError(t, err)
+
+	parsed, err := tokens.ParseJWTToken(signed, tokens.TokenTypeReset, keys)
+	require.NoError(t, err)
+
+	result, err := parsed.VerifyFingerprint(issuedFrom, tokens.FingerprintStrict)
+	require.NoError(t, err)
+	assert.Equal(t, tokens.FingerprintMatched, result)
+
+	_, err = parsed.VerifyFingerprint(tokens.IssueContext{UserAgent: "curl/8.0"}, tokens.FingerprintStrict)
+	assert.ErrorIs(t, err, tokens.ErrFingerprintMismatch)
+}
+
+func TestNewSigner_InvalidKeys(t *testing.T) {
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = tokens.NewES256Signer("es-1", p384)
+	assert.ErrorIs(t, err, tokens.ErrInvalidSigningKey)
+
+	_, err = tokens.NewEdDSASigner("ed-1", ed25519.PrivateKey{1, 2, 3})
+	assert.ErrorIs(t, err, tokens.ErrInvalidSigningKey)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, err = tokens.NewEdDSASigner("", edKey)
+	assert.ErrorIs(t, err, tokens.ErrInvalidSigningKey)
+}
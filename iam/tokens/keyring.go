@@ -0,0 +1,136 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// minKeyRingKeyLength is the minimum length of a KeyRing key in bytes.
+const minKeyRingKeyLength = 32
+
+// KeyRing holds versioned server-side HMAC keys. Tokens are signed with the
+// current key and record its version in SigningInfo.KeyVersion, so that they
+// can still be verified with a historical key after the current key has been
+// rotated. Removing a version from the ring invalidates all tokens signed
+// with it.
+//
+// A KeyRing is immutable and safe for concurrent use. To rotate, create a
+// new ring with the new key as current version and keep the previous keys
+// until the tokens signed with them have expired.
+type KeyRing struct {
+	current uint32
+	keys    map[uint32][]byte
+}
+
+// NewKeyRing creates a KeyRing that signs with the key of version current
+// and verifies with all keys. Versions start at 1, and keys must be at least
+// 32 bytes long.
+//
+// Example:
+//
+//	ring, err := tokens.NewKeyRing(2, map[uint32][]byte{
+//		1: previousKey,
+//		2: currentKey,
+//	})
+func NewKeyRing(current uint32, keys map[uint32][]byte) (*KeyRing, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, ErrInvalidKeyRing
+	}
+
+	ring := &KeyRing{current: current, keys: make(map[uint32][]byte, len(keys))}
+
+	for version, key := range keys {
+		if version == 0 || len(key) < minKeyRingKeyLength {
+			return nil, ErrInvalidKeyRing
+		}
+
+		ring.keys[version] = append([]byte(nil), key...)
+	}
+
+	return ring, nil
+}
+
+// Current returns the version of the key new tokens are signed with.
+func (r *KeyRing) Current() uint32 {
+	return r.current
+}
+
+// key returns the key of version.
+func (r *KeyRing) key(version uint32) ([]byte, error) {
+	key, ok := r.keys[version]
+	if !ok {
+		return nil, ErrUnknownKeyVersion
+	}
+
+	return key, nil
+}
+
+// SignTokenWithKeyRing sets d.KeyVersion to ring.Current(), then marshals
+// token, which must embed d, and signs it with the current ring key. Unlike
+// SignToken, the returned secret only holds the nonce; the key material
+// stays in the ring. Store the secret and the KeyVersion with the token.
+func (d *SigningInfo) SignTokenWithKeyRing(token any, ring *KeyRing) (string, []byte, error) {
+	d.KeyVersion = ring.Current()
+
+	key, err := ring.key(d.KeyVersion)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := msgpack.Marshal(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	if _, err := mac.Write(data); err != nil {
+		return "", nil, ErrFailedSigning.With(err)
+	}
+
+	secret := append([]byte(nil), d.Nonce...)
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), secret, nil
+}
+
+// VerifyTokenWithKeyRing verifies a token signed with SignTokenWithKeyRing
+// using the ring key of d.KeyVersion. Returns ErrUnknownKeyVersion if the
+// version is not (or no longer) part of the ring. The expiration is checked
+// like VerifyToken with the clock and leeway of opts. The outcome is
+// reported to the observer installed with SetVerifyObserver.
+func (d SigningInfo) VerifyTokenWithKeyRing(token URLToken, signature string, secret []byte, ring *KeyRing, opts ...VerifyOption) error {
+	err := d.verifyTokenWithKeyRing(token, signature, secret, ring, newVerifyConfig(opts))
+	observeVerify(tokenTypeOf(token), err)
+
+	return err
+}
+
+// verifyTokenWithKeyRing implements VerifyTokenWithKeyRing.
+func (d SigningInfo) verifyTokenWithKeyRing(token URLToken, signature string, secret []byte, ring *KeyRing, cfg verifyConfig) error {
+	if cfg.expired(d.ExpiresAt) {
+		return ErrTokenExpired
+	}
+
+	if len(secret) != nonceLength {
+		return ErrInvalidSecret
+	}
+
+	key, err := ring.key(d.KeyVersion)
+	if err != nil {
+		return err
+	}
+
+	token.SetNonce(secret)
+
+	data, err := msgpack.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	return verifyMAC(data, signature, key)
+}
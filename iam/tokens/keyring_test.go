@@ -0,0 +1,143 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ringKeyV1 = bytes.Repeat([]byte{1}, 32)
+	ringKeyV2 = bytes.Repeat([]byte{2}, 32)
+)
+
+func TestNewKeyRing(t *testing.T) {
+	_, err := tokens.NewKeyRing(2, map[uint32][]byte{1: ringKeyV1})
+	assert.ErrorIs(t, err, tokens.ErrInvalidKeyRing)
+
+	_, err = tokens.NewKeyRing(1, map[uint32][]byte{1: []byte("short")})
+	assert.ErrorIs(t, err, tokens.ErrInvalidKeyRing)
+
+	_, err = tokens.NewKeyRing(0, map[uint32][]byte{0: ringKeyV1})
+	assert.ErrorIs(t, err, tokens.ErrInvalidKeyRing)
+
+	ring, err := tokens.NewKeyRing(2, map[uint32][]byte{1: ringKeyV1, 2: ringKeyV2})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), ring.Current())
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	observed := recordVerifications(t)
+
+	v1, err := tokens.NewKeyRing(1, map[uint32][]byte{1: ringKeyV1})
+	require.NoError(t, err)
+
+	invite, err := tokens.NewOrganizationInviteToken("invitee@example.com", "org-1")
+	require.NoError(t, err)
+
+	signature, secret, err := invite.SignWithKeyRing(v1)
+	require.NoError(t, err)
+	assert.Len(t, secret, 64)
+	assert.Equal(t, uint32(1), invite.KeyVersion)
+
+	// restore reconstructs the token from the stored fields.
+	restore := func(version uint32) *tokens.OrganizationInviteToken {
+		return &tokens.OrganizationInviteToken{
+			Email:          invite.Email,
+			OrganizationID: invite.OrganizationID,
+			SigningInfo: tokens.SigningInfo{
+				ExpiresAt:  invite.ExpiresAt,
+				KeyVersion: version,
+			},
+		}
+	}
+
+	require.NoError(t, restore(1).VerifyWithKeyRing(signature, secret, v1))
+
+	rotated, err := tokens.NewKeyRing(2, map[uint32][]byte{1: ringKeyV1, 2: ringKeyV2})
+	require.NoError(t, err)
+
+	assert.NoError(t, restore(1).VerifyWithKeyRing(signature, secret, rotated), "outstanding tokens survive rotation")
+	assert.ErrorIs(t, restore(2).VerifyWithKeyRing(signature, secret, rotated), tokens.ErrTokenInvalid)
+
+	retired, err := tokens.NewKeyRing(2, map[uint32][]byte{2: ringKeyV2})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, restore(1).VerifyWithKeyRing(signature, secret, retired), tokens.ErrUnknownKeyVersion)
+
+	reset, err := tokens.NewResetToken("user-1")
+	require.NoError(t, err)
+
+	signature, secret, err = reset.SignWithKeyRing(rotated)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), reset.KeyVersion)
+
+	restored := &tokens.ResetToken{UserID: "user-1", SigningInfo: tokens.SigningInfo{
+		ExpiresAt:  reset.ExpiresAt,
+		KeyVersion: reset.KeyVersion,
+	}}
+	require.NoError(t, restored.VerifyWithKeyRing(signature, secret, retired))
+	assert.ErrorIs(t, restored.VerifyWithKeyRing(signature, append(secret, 0), retired), tokens.ErrInvalidSecret)
+
+	assert.Equal(t, []observation{
+		{tokens.TokenTypeInvite, tokens.VerifyFailureNone},
+		{tokens.TokenTypeInvite, tokens.VerifyFailureNone},
+		{tokens.TokenTypeInvite, tokens.VerifyFailureTampered},
+		{tokens.TokenTypeInvite, tokens.VerifyFailureTampered},
+		{tokens.TokenTypeReset, tokens.VerifyFailureNone},
+		{tokens.TokenTypeReset, tokens.VerifyFailureMalformedSecret},
+	}, observed())
+}
+
+func TestKeyRing_PerTokenKeysUnchanged(t *testing.T) {
+	token, err := tokens.NewVerificationToken("user@example.com")
+	require.NoError(t, err)
+
+	signature, secret, err := token.Sign()
+	require.NoError(t, err)
+	assert.Zero(t, token.KeyVersion)
+
+	ring, err := tokens.NewKeyRing(1, map[uint32][]byte{1: ringKeyV1})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, token.VerifyWithKeyRing(signature, secret[:64], ring), tokens.ErrUnknownKeyVersion)
+	assert.NoError(t, token.Verify(signature, secret))
+}
+
+func TestKeyRing_SignTokenSetsKeyVersion(t *testing.T) {
+	ring, err := tokens.NewKeyRing(2, map[uint32][]byte{1: ringKeyV1, 2: ringKeyV2})
+	require.NoError(t, err)
+
+	token, err := tokens.NewVerificationToken("user@example.com")
+	require.NoError(t, err)
+
+	signature, secret, err := token.SignTokenWithKeyRing(token, ring)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), token.KeyVersion)
+
+	assert.NoError(t, token.VerifyWithKeyRing(signature, secret, ring))
+}
+
+func TestKeyRing_VerifyOptions(t *testing.T) {
+	ring, err := tokens.NewKeyRing(1, map[uint32][]byte{1: ringKeyV1})
+	require.NoError(t, err)
+
+	token, err := tokens.NewResetToken("user-1")
+	require.NoError(t, err)
+
+	signature, secret, err := token.SignWithKeyRing(ring)
+	require.NoError(t, err)
+
+	later := token.ExpiresAt.Add(time.Minute)
+	clock := tokens.WithClock(tokens.ClockFunc(func() time.Time { return later }))
+
+	assert.ErrorIs(t, token.VerifyWithKeyRing(signature, secret, ring, clock), tokens.ErrTokenExpired)
+	assert.NoError(t, token.VerifyWithKeyRing(signature, secret, ring, clock, tokens.WithLeeway(2*time.Minute)))
+}
@@ -0,0 +1,232 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"time"
+)
+
+// PKCEMethodS256 is the only supported PKCE code challenge method. The
+// "plain" method is rejected as it offers no protection if the challenge
+// leaks.
+const PKCEMethodS256 = "S256"
+
+// OAuthStateToken is the signed OAuth/OIDC "state" of a login flow. It binds
+// the provider callback to the login that initiated it, carries the target to
+// redirect to afterwards and the OIDC nonce expected in the ID token.
+//
+// The token and secret stay server-side (e.g. in the session); only the
+// signature is sent to the provider as state parameter.
+type OAuthStateToken struct {
+	// RedirectURL is where the user is sent after the login completed.
+	// It is either a relative path or an absolute http(s) URL.
+	RedirectURL string `msgpack:"redirect_url"`
+	// OIDCNonce is the value to send as "nonce" in the authorization request
+	// and to compare with the nonce claim of the returned ID token.
+	OIDCNonce string `msgpack:"oidc_nonce"`
+	// SigningInfo contains the cryptographic information for the token.
+	SigningInfo
+}
+
+// OAuthState is a generated login state.
+type OAuthState struct {
+	// Token is the state token to store server-side.
+	Token *OAuthStateToken
+	// State is the value of the state parameter of the authorization request.
+	State string
+	// Secret is required to verify the state and must stay server-side.
+	Secret []byte
+}
+
+// GenerateState creates and signs a state for an OAuth/OIDC login that
+// expires in oauthStateExpirationMinutes (10) minutes.
+//
+// Parameters:
+//   - redirectURL: Optional target after the login (relative path or http(s) URL)
+//
+// Returns:
+//   - *OAuthState: The state parameter with the token and secret to store
+//   - error: If the redirect target is invalid or signing fails
+func GenerateState(redirectURL string) (*OAuthState, error) {
+	if err := validateRedirectURL(redirectURL); err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomString(oidcNonceLength)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &OAuthStateToken{
+		RedirectURL: redirectURL,
+		OIDCNonce:   nonce,
+	}
+
+	if token.SigningInfo, err = NewSigningInfo(time.Minute * oauthStateExpirationMinutes); err != nil {
+		return nil, err
+	}
+
+	state, secret, err := token.Sign()
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthState{Token: token, State: state, Secret: secret}, nil
+}
+
+// VerifyState verifies the state parameter received in the callback against
+// the stored token and secret. It rejects expired states and states that were
+// not issued for this token.
+func VerifyState(token *OAuthStateToken, state string, secret []byte) error {
+	if token == nil || state == "" {
+		notifyVerify(TokenTypeOAuthState, VerifyFailureMalformed)
+		return ErrTokenInvalid
+	}
+
+	return token.Verify(state, secret)
+}
+
+// Sign creates a base64 URL encoded signature for the state token. See VerificationToken.Sign.
+func (t *OAuthStateToken) Sign() (string, []byte, error) {
+	return t.SignToken(t)
+}
+
+// Validate checks that the token has an OIDC nonce and a safe redirect target.
+func (t *OAuthStateToken) Validate() error {
+	if t.OIDCNonce == "" {
+		return ErrTokenMissingOIDCNonce
+	}
+
+	return validateRedirectURL(t.RedirectURL)
+}
+
+// SetNonce sets the nonce for verification (implements URLToken contract).
+func (t *OAuthStateToken) SetNonce(nonce []byte) {
+	t.Nonce = nonce
+}
+
+// TokenType returns TokenTypeOAuthState.
+func (t *OAuthStateToken) TokenType() string {
+	return TokenTypeOAuthState
+}
+
+// Verify performs full validation (required fields, expiration, signature) for an OAuthStateToken.
+func (t *OAuthStateToken) Verify(signature string, secret []byte, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeOAuthState, VerifyFailureMalformed)
+		return err
+	}
+
+	return t.VerifyToken(t, signature, secret, opts...)
+}
+
+// PKCE is a Proof Key for Code Exchange (RFC 7636) pair. The client keeps
+// the Verifier server-side and sends the Challenge with the authorization
+// request; the Verifier is sent with the token request.
+type PKCE struct {
+	// Verifier is the high-entropy code verifier.
+	Verifier string `msgpack:"verifier"`
+	// Challenge is the S256 code challenge derived from Verifier.
+	Challenge string `msgpack:"challenge"`
+	// Method is the code challenge method, always PKCEMethodS256.
+	Method string `msgpack:"method"`
+	// ExpiresAt is the UTC timestamp after which the pair must not be used.
+	ExpiresAt time.Time `msgpack:"expires_at"`
+}
+
+// GeneratePKCE creates a PKCE verifier and its S256 challenge that expire in
+// pkceExpirationMinutes (10) minutes.
+//
+// Returns:
+//   - *PKCE: The generated verifier and challenge
+//   - error: If random generation fails
+func GeneratePKCE() (*PKCE, error) {
+	verifier, err := randomString(pkceVerifierLength)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PKCE{
+		Verifier:  verifier,
+		Challenge: PKCEChallenge(verifier),
+		Method:    PKCEMethodS256,
+		ExpiresAt: now().UTC().Add(time.Minute * pkceExpirationMinutes).Truncate(time.Microsecond),
+	}, nil
+}
+
+// IsExpired checks if the PKCE pair has passed its expiration time.
+func (p *PKCE) IsExpired() bool {
+	return isExpired(p.ExpiresAt)
+}
+
+// PKCEChallenge returns the S256 code challenge of verifier:
+// BASE64URL(SHA256(verifier)) without padding.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyPKCE checks a code verifier received in a token request against the
+// stored challenge, as done by an authorization server. The Verifier field
+// of stored is ignored.
+//
+// Returns:
+//   - error: ErrTokenExpired, ErrUnsupportedPKCEMethod, ErrInvalidPKCEVerifier
+//     if verifier is malformed, or ErrPKCEMismatch if it does not match
+func VerifyPKCE(stored *PKCE, verifier string) error {
+	if stored == nil {
+		return ErrPKCEMismatch
+	}
+
+	if stored.IsExpired() {
+		return ErrTokenExpired
+	}
+
+	if stored.Method != PKCEMethodS256 {
+		return ErrUnsupportedPKCEMethod
+	}
+
+	if !validPKCEVerifier(verifier) {
+		return ErrInvalidPKCEVerifier
+	}
+
+	if subtle.ConstantTimeCompare([]byte(PKCEChallenge(verifier)), []byte(stored.Challenge)) != 1 {
+		return ErrPKCEMismatch
+	}
+
+	return nil
+}
+
+// validPKCEVerifier checks the length (43-128) and the character set
+// (unreserved URI characters) required by RFC 7636.
+func validPKCEVerifier(v string) bool {
+	if len(v) < 43 || len(v) > 128 {
+		return false
+	}
+
+	for _, c := range v {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '-', c == '.', c == '_', c == '~':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// randomString returns n random bytes encoded as unpadded base64url.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", ErrFailedSigning.With(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthState(t *testing.T) {
+	t.Run("generate and verify", func(t *testing.T) {
+		s, err := tokens.GenerateState("/dashboard")
+		require.NoError(t, err)
+		assert.NotEmpty(t, s.State)
+		assert.NotEmpty(t, s.Token.OIDCNonce)
+		assert.Equal(t, "/dashboard", s.Token.RedirectURL)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), s.Token.ExpiresAt, 5*time.Second)
+		assert.NoError(t, tokens.VerifyState(s.Token, s.State, s.Secret))
+	})
+
+	t.Run("unique per login", func(t *testing.T) {
+		a, err := tokens.GenerateState("")
+		require.NoError(t, err)
+		b, err := tokens.GenerateState("")
+		require.NoError(t, err)
+		assert.NotEqual(t, a.State, b.State)
+		assert.NotEqual(t, a.Token.OIDCNonce, b.Token.OIDCNonce)
+		assert.ErrorIs(t, tokens.VerifyState(a.Token, b.State, a.Secret), tokens.ErrTokenInvalid)
+	})
+
+	t.Run("invalid redirect", func(t *testing.T) {
+		_, err := tokens.GenerateState("//evil.example")
+		assert.ErrorIs(t, err, tokens.ErrInvalidRedirectURL)
+	})
+
+	t.Run("tampered redirect", func(t *testing.T) {
+		s, err := tokens.GenerateState("/dashboard")
+		require.NoError(t, err)
+		s.Token.RedirectURL = "/admin"
+		assert.ErrorIs(t, tokens.VerifyState(s.Token, s.State, s.Secret), tokens.ErrTokenInvalid)
+	})
+
+	t.Run("missing state", func(t *testing.T) {
+		s, err := tokens.GenerateState("")
+		require.NoError(t, err)
+		assert.ErrorIs(t, tokens.VerifyState(s.Token, "", s.Secret), tokens.ErrTokenInvalid)
+		assert.ErrorIs(t, tokens.VerifyState(nil, s.State, s.Secret), tokens.ErrTokenInvalid)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		s, err := tokens.GenerateState("")
+		require.NoError(t, err)
+		s.Token.ExpiresAt = time.Now().Add(-time.Minute)
+		state, secret, err := s.Token.Sign()
+		require.NoError(t, err)
+		assert.ErrorIs(t, tokens.VerifyState(s.Token, state, secret), tokens.ErrTokenExpired)
+	})
+
+	t.Run("missing nonce", func(t *testing.T) {
+		s, err := tokens.GenerateState("")
+		require.NoError(t, err)
+		s.Token.OIDCNonce = ""
+		assert.ErrorIs(t, tokens.VerifyState(s.Token, s.State, s.Secret), tokens.ErrTokenMissingOIDCNonce)
+	})
+}
+
+func TestPKCE(t *testing.T) {
+	t.Run("rfc 7636 test vector", func(t *testing.T) {
+		assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			tokens.PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+	})
+
+	t.Run("generate and verify", func(t *testing.T) {
+		p, err := tokens.GeneratePKCE()
+		require.NoError(t, err)
+		assert.Len(t, p.Verifier, 43)
+		assert.Equal(t, tokens.PKCEMethodS256, p.Method)
+		assert.Equal(t, tokens.PKCEChallenge(p.Verifier), p.Challenge)
+		assert.NoError(t, tokens.VerifyPKCE(p, p.Verifier))
+	})
+
+	t.Run("stored challenge without verifier", func(t *testing.T) {
+		p, err := tokens.GeneratePKCE()
+		require.NoError(t, err)
+		stored := &tokens.PKCE{Challenge: p.Challenge, Method: p.Method, ExpiresAt: p.ExpiresAt}
+		assert.NoError(t, tokens.VerifyPKCE(stored, p.Verifier))
+	})
+
+	t.Run("wrong verifier", func(t *testing.T) {
+		p, err := tokens.GeneratePKCE()
+		require.NoError(t, err)
+		other, err := tokens.GeneratePKCE()
+		require.NoError(t, err)
+		assert.ErrorIs(t, tokens.VerifyPKCE(p, other.Verifier), tokens.ErrPKCEMismatch)
+	})
+
+	t.Run("malformed verifier", func(t *testing.T) {
+		p, err := tokens.GeneratePKCE()
+		require.NoError(t, err)
+		assert.ErrorIs(t, tokens.VerifyPKCE(p, "short"), tokens.ErrInvalidPKCEVerifier)
+		assert.ErrorIs(t, tokens.VerifyPKCE(p, strings.Repeat("a", 129)), tokens.ErrInvalidPKCEVerifier)
+		assert.ErrorIs(t, tokens.VerifyPKCE(p, strings.Repeat("a", 42)+"+"), tokens.ErrInvalidPKCEVerifier)
+	})
+
+	t.Run("plain method rejected", func(t *testing.T) {
+		p, err := tokens.GeneratePKCE()
+		require.NoError(t, err)
+		p.Method = "plain"
+		assert.ErrorIs(t, tokens.VerifyPKCE(p, p.Verifier), tokens.ErrUnsupportedPKCEMethod)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		p, err := tokens.GeneratePKCE()
+		require.NoError(t, err)
+		p.ExpiresAt = time.Now().Add(-time.Second)
+		assert.ErrorIs(t, tokens.VerifyPKCE(p, p.Verifier), tokens.ErrTokenExpired)
+	})
+}
@@ -0,0 +1,299 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// RegisteredToken is a URLToken that can be signed and verified through a
+// TokenRegistry. Every token type embedding SigningInfo implements it.
+type RegisteredToken interface {
+	URLToken
+	signing() SigningInfo
+}
+
+// signing returns d; it makes every type embedding SigningInfo a RegisteredToken.
+func (d SigningInfo) signing() SigningInfo {
+	return d
+}
+
+// SecretLookup returns the secret stored for a decoded token, e.g. by looking
+// up the user or invite it refers to. The token has not been verified yet.
+type SecretLookup func(kind string, token RegisteredToken) ([]byte, error)
+
+// tokenEnvelope is the signed payload of a registry token. The kind is part
+// of the signed data, so a token cannot be redeemed as another type.
+type tokenEnvelope struct {
+	Kind  string `msgpack:"kind"`
+	Token any    `msgpack:"token"`
+}
+
+// rawTokenEnvelope is a tokenEnvelope whose token is not decoded yet.
+type rawTokenEnvelope struct {
+	Kind  string             `msgpack:"kind"`
+	Token msgpack.RawMessage `msgpack:"token"`
+}
+
+// TokenRegistry maps kind strings to token types, so that a single endpoint
+// can verify any registered token and dispatch on its type.
+//
+// Tokens signed by SignToken are self-contained: the returned string carries
+// the kind and the msgpack encoded token followed by the HMAC signature. As
+// with the type specific Sign methods, the returned secret must be stored
+// server-side; VerifyToken retrieves it through a SecretLookup.
+//
+// A TokenRegistry is safe for concurrent use by multiple goroutines.
+type TokenRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]func() RegisteredToken
+	kinds     map[reflect.Type]string
+}
+
+// NewTokenRegistry creates an empty TokenRegistry.
+func NewTokenRegistry() *TokenRegistry {
+	return &TokenRegistry{
+		factories: map[string]func() RegisteredToken{},
+		kinds:     map[reflect.Type]string{},
+	}
+}
+
+// NewDefaultTokenRegistry creates a TokenRegistry with the invite,
+// verification, email change, reset and integration state tokens registered
+// under their TokenType.
+func NewDefaultTokenRegistry() *TokenRegistry {
+	r := NewTokenRegistry()
+
+	for kind, factory := range map[string]func() RegisteredToken{
+		TokenTypeInvite:           func() RegisteredToken { return &OrganizationInviteToken{} },
+		TokenTypeVerification:     func() RegisteredToken { return &VerificationToken{} },
+		TokenTypeEmailChange:      func() RegisteredToken { return &EmailChangeToken{} },
+		TokenTypeReset:            func() RegisteredToken { return &ResetToken{} },
+		TokenTypeIntegrationState: func() RegisteredToken { return &IntegrationStateToken{} },
+	} {
+		if err := r.Register(kind, factory); err != nil {
+			panic(err)
+		}
+	}
+
+	return r
+}
+
+// Register registers the token type created by factory under kind. Each kind
+// and each token type can only be registered once.
+//
+// Example:
+//
+//	err := registry.Register("export", func() tokens.RegisteredToken { return &ExportToken{} })
+func (r *TokenRegistry) Register(kind string, factory func() RegisteredToken) error {
+	if kind == "" || factory == nil {
+		return fmt.Errorf("%w: kind and factory are required", ErrTokenKindRegistered)
+	}
+
+	typ := reflect.TypeOf(factory())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.factories[kind]; ok {
+		return fmt.Errorf("%w: kind %q", ErrTokenKindRegistered, kind)
+	}
+
+	if other, ok := r.kinds[typ]; ok {
+		return fmt.Errorf("%w: %s is registered as %q", ErrTokenKindRegistered, typ, other)
+	}
+
+	r.factories[kind] = factory
+	r.kinds[typ] = kind
+
+	return nil
+}
+
+// SignToken signs token together with its registered kind. Returns the token
+// string to embed in a link and the secret to store.
+func (r *TokenRegistry) SignToken(token RegisteredToken) (string, []byte, error) {
+	r.mu.RLock()
+	kind, ok := r.kinds[reflect.TypeOf(token)]
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %T", ErrUnknownTokenKind, token)
+	}
+
+	if err := token.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	data, err := msgpack.Marshal(tokenEnvelope{Kind: kind, Token: token})
+	if err != nil {
+		return "", nil, err
+	}
+
+	signature, secret, err := token.signing().signData(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data) + "." + signature, secret, nil
+}
+
+// DecodeToken decodes token without verifying it and returns its kind.
+// The result must not be trusted; use VerifyToken to redeem a token.
+func (r *TokenRegistry) DecodeToken(token string) (string, RegisteredToken, error) {
+	payload, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", nil, ErrMalformedToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrMalformedToken, err) //nolint:errorlint
+	}
+
+	var envelope rawTokenEnvelope
+	if err := msgpack.Unmarshal(data, &envelope); err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	r.mu.RLock()
+	factory, ok := r.factories[envelope.Kind]
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %q", ErrUnknownTokenKind, envelope.Kind)
+	}
+
+	decoded := factory()
+	if err := msgpack.Unmarshal(envelope.Token, decoded); err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	return envelope.Kind, decoded, nil
+}
+
+// VerifyToken decodes token, retrieves its secret with lookup and checks the
+// required fields, expiration and signature; see SigningInfo.VerifyToken for
+// opts. Returns the kind and the verified token; callers dispatch on the kind
+// or a type switch on the token. The outcome is reported to the observer
+// installed with SetVerifyObserver.
+//
+// Example:
+//
+//	kind, token, err := registry.VerifyToken(link, lookupSecret)
+//	switch t := token.(type) {
+//	case *tokens.ResetToken:
+//	    // reset the password of t.UserID
+//	}
+func (r *TokenRegistry) VerifyToken(token string, lookup SecretLookup, opts ...VerifyOption) (string, RegisteredToken, error) {
+	kind, decoded, err := r.verifyToken(token, lookup, newVerifyConfig(opts))
+	if decoded == nil {
+		observeVerify(TokenTypeOther, err)
+	} else {
+		observeVerify(tokenTypeOf(decoded), err)
+	}
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return kind, decoded, nil
+}
+
+// ConsumeToken verifies token like VerifyToken and then marks it as used
+// with consumer, so that it cannot be redeemed again; see Consumer.
+func (r *TokenRegistry) ConsumeToken(ctx context.Context, token string, lookup SecretLookup, consumer Consumer) (string, RegisteredToken, error) {
+	kind, decoded, err := r.verifyToken(token, lookup, newVerifyConfig(nil))
+	if err == nil {
+		err = markUsed(ctx, consumer, decoded.signing().TokenID)
+	}
+
+	if decoded == nil {
+		observeVerify(TokenTypeOther, err)
+	} else {
+		observeVerify(tokenTypeOf(decoded), err)
+	}
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return kind, decoded, nil
+}
+
+// verifyToken implements VerifyToken. The decoded token is returned on
+// failure as well, to report its type.
+func (r *TokenRegistry) verifyToken(token string, lookup SecretLookup, cfg verifyConfig) (string, RegisteredToken, error) {
+	kind, decoded, err := r.DecodeToken(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := decoded.Validate(); err != nil {
+		return kind, decoded, fmt.Errorf("%w: %w", ErrMalformedToken, err)
+	}
+
+	secret, err := lookup(kind, decoded)
+	if err != nil {
+		return kind, decoded, err
+	}
+
+	info := decoded.signing()
+	if cfg.expired(info.ExpiresAt) {
+		return kind, decoded, ErrTokenExpired
+	}
+
+	if len(secret) != nonceLength+keyLength {
+		return kind, decoded, ErrInvalidSecret
+	}
+
+	decoded.SetNonce(secret[:nonceLength])
+
+	data, err := msgpack.Marshal(tokenEnvelope{Kind: kind, Token: decoded})
+	if err != nil {
+		return kind, decoded, err
+	}
+
+	_, signature, _ := strings.Cut(token, ".")
+
+	return kind, decoded, info.verifyData(data, signature, secret)
+}
+
+// VerifyTokenAs verifies token like TokenRegistry.VerifyToken and returns it
+// as T. Returns ErrTokenKindMismatch if the token is of another type, e.g. a
+// reset token presented to the invite endpoint.
+//
+// Example:
+//
+//	invite, err := tokens.VerifyTokenAs[*tokens.OrganizationInviteToken](registry, link, lookupSecret)
+func VerifyTokenAs[T RegisteredToken](r *TokenRegistry, token string, lookup SecretLookup, opts ...VerifyOption) (T, error) {
+	var zero T
+
+	r.mu.RLock()
+	want, ok := r.kinds[reflect.TypeFor[T]()]
+	r.mu.RUnlock()
+
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrUnknownTokenKind, reflect.TypeFor[T]())
+	}
+
+	kind, decoded, err := r.DecodeToken(token)
+	if err == nil && kind != want {
+		notifyVerify(tokenTypeOf(decoded), VerifyFailureMalformed)
+		return zero, fmt.Errorf("%w: got %q, want %q", ErrTokenKindMismatch, kind, want)
+	}
+
+	_, verified, err := r.VerifyToken(token, lookup, opts...)
+	if err != nil {
+		return zero, err
+	}
+
+	return verified.(T), nil //nolint:forcetypeassert
+}
@@ -0,0 +1,174 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// exportToken is a custom token type registered by a consumer of the package.
+type exportToken struct {
+	ExportID string `msgpack:"export_id"`
+	tokens.SigningInfo
+}
+
+func (t *exportToken) Validate() error {
+	if t.ExportID == "" {
+		return errors.New("missing export id")
+	}
+
+	return nil
+}
+
+func (t *exportToken) SetNonce(nonce []byte) {
+	t.Nonce = nonce
+}
+
+// secretStore returns a SecretLookup serving the given secret.
+func secretStore(secret []byte) tokens.SecretLookup {
+	return func(string, tokens.RegisteredToken) ([]byte, error) {
+		return secret, nil
+	}
+}
+
+func TestTokenRegistry_Dispatch(t *testing.T) {
+	registry := tokens.NewDefaultTokenRegistry()
+
+	reset, err := tokens.NewResetToken("user-1")
+	require.NoError(t, err)
+
+	signed, secret, err := registry.SignToken(reset)
+	require.NoError(t, err)
+
+	kind, token, err := registry.VerifyToken(signed, secretStore(secret))
+	require.NoError(t, err)
+	assert.Equal(t, tokens.TokenTypeReset, kind)
+
+	verified, ok := token.(*tokens.ResetToken)
+	require.True(t, ok)
+	assert.Equal(t, "user-1", verified.UserID)
+	assert.True(t, reset.ExpiresAt.Equal(verified.ExpiresAt))
+
+	typed, err := tokens.VerifyTokenAs[*tokens.ResetToken](registry, signed, secretStore(secret))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", typed.UserID)
+
+	_, err = tokens.VerifyTokenAs[*tokens.OrganizationInviteToken](registry, signed, secretStore(secret))
+	assert.ErrorIs(t, err, tokens.ErrTokenKindMismatch)
+}
+
+func TestTokenRegistry_CustomKind(t *testing.T) {
+	registry := tokens.NewTokenRegistry()
+	require.NoError(t, registry.Register("export", func() tokens.RegisteredToken { return &exportToken{} }))
+
+	err := registry.Register("export", func() tokens.RegisteredToken { return &tokens.ResetToken{} })
+	assert.ErrorIs(t, err, tokens.ErrTokenKindRegistered)
+
+	err = registry.Register("export2", func() tokens.RegisteredToken { return &exportToken{} })
+	assert.ErrorIs(t, err, tokens.ErrTokenKindRegistered)
+
+	info, err := tokens.NewSigningInfo(time.Hour)
+	require.NoError(t, err)
+
+	signed, secret, err := registry.SignToken(&exportToken{ExportID: "exp-1", SigningInfo: info})
+	require.NoError(t, err)
+
+	kind, token, err := registry.VerifyToken(signed, func(kind string, token tokens.RegisteredToken) ([]byte, error) {
+		assert.Equal(t, "exp-1", token.(*exportToken).ExportID) //nolint:forcetypeassert
+		return secret, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "export", kind)
+	assert.Equal(t, "exp-1", token.(*exportToken).ExportID) //nolint:forcetypeassert
+
+	reset, err := tokens.NewResetToken("user-1")
+	require.NoError(t, err)
+
+	_, _, err = registry.SignToken(reset)
+	assert.ErrorIs(t, err, tokens.ErrUnknownTokenKind)
+
+	_, _, err = tokens.NewDefaultTokenRegistry().VerifyToken(signed, secretStore(secret))
+	assert.ErrorIs(t, err, tokens.ErrUnknownTokenKind)
+}
+
+func TestTokenRegistry_Rejects(t *testing.T) {
+	registry := tokens.NewDefaultTokenRegistry()
+	observed := recordVerifications(t)
+
+	reset, err := tokens.NewResetToken("user-1")
+	require.NoError(t, err)
+
+	signed, secret, err := registry.SignToken(reset)
+	require.NoError(t, err)
+
+	payload, signature, _ := strings.Cut(signed, ".")
+
+	t.Run("kind swapped", func(t *testing.T) {
+		data, err := base64.RawURLEncoding.DecodeString(payload)
+		require.NoError(t, err)
+
+		var envelope map[string]any
+		require.NoError(t, msgpack.Unmarshal(data, &envelope))
+		envelope["kind"] = tokens.TokenTypeVerification
+		envelope["token"].(map[string]any)["email"] = "user-1" //nolint:forcetypeassert
+
+		data, err = msgpack.Marshal(envelope)
+		require.NoError(t, err)
+
+		_, _, err = registry.VerifyToken(base64.RawURLEncoding.EncodeToString(data)+"."+signature, secretStore(secret))
+		assert.ErrorIs(t, err, tokens.ErrTokenInvalid)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		other, err := tokens.NewResetToken("user-1")
+		require.NoError(t, err)
+
+		_, otherSecret, err := registry.SignToken(other)
+		require.NoError(t, err)
+
+		_, _, err = registry.VerifyToken(signed, secretStore(otherSecret))
+		assert.ErrorIs(t, err, tokens.ErrTokenInvalid)
+
+		_, _, err = registry.VerifyToken(signed, secretStore([]byte("short")))
+		assert.ErrorIs(t, err, tokens.ErrInvalidSecret)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, _, err := registry.VerifyToken("garbage", secretStore(secret))
+		assert.ErrorIs(t, err, tokens.ErrMalformedToken)
+
+		_, _, err = registry.VerifyToken("***."+signature, secretStore(secret))
+		assert.ErrorIs(t, err, tokens.ErrMalformedToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired := &tokens.ResetToken{UserID: "user-1"}
+		expired.SigningInfo, err = tokens.NewSigningInfo(-time.Minute)
+		require.NoError(t, err)
+
+		signed, secret, err := registry.SignToken(expired)
+		require.NoError(t, err)
+
+		_, _, err = registry.VerifyToken(signed, secretStore(secret))
+		assert.ErrorIs(t, err, tokens.ErrTokenExpired)
+	})
+
+	assert.Equal(t, []observation{
+		{tokens.TokenTypeVerification, tokens.VerifyFailureTampered},
+		{tokens.TokenTypeReset, tokens.VerifyFailureTampered},
+		{tokens.TokenTypeReset, tokens.VerifyFailureMalformedSecret},
+		{tokens.TokenTypeOther, tokens.VerifyFailureMalformed},
+		{tokens.TokenTypeOther, tokens.VerifyFailureMalformed},
+		{tokens.TokenTypeReset, tokens.VerifyFailureExpired},
+	}, observed())
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"encoding/base64"
+	"errors"
+	"sync/atomic"
+)
+
+// Token types reported to the VerifyObserver.
+const (
+	TokenTypeInvite           = "invite"
+	TokenTypeVerification     = "verification"
+	TokenTypeReset            = "reset"
+	TokenTypeIntegrationState = "integration_state"
+	TokenTypeOAuthState       = "oauth_state"
+	TokenTypeTOTP             = "totp"
+	TokenTypeDownload         = "download"
+	TokenTypeEmailChange      = "email_change"
+	// TokenTypeOther is reported for URLToken implementations without a
+	// TokenType method.
+	TokenTypeOther = "other"
+)
+
+// VerifyFailure classifies why a token was rejected.
+type VerifyFailure string
+
+const (
+	// VerifyFailureNone is reported for accepted tokens.
+	VerifyFailureNone VerifyFailure = ""
+	// VerifyFailureExpired is reported for tokens past their expiration.
+	VerifyFailureExpired VerifyFailure = "expired"
+	// VerifyFailureTampered is reported if the signature does not match the
+	// token, i.e. the token or the signature was modified.
+	VerifyFailureTampered VerifyFailure = "tampered"
+	// VerifyFailureMalformedSecret is reported for secrets of the wrong length.
+	VerifyFailureMalformedSecret VerifyFailure = "malformed_secret"
+	// VerifyFailureMalformedSignature is reported for signatures that are
+	// not base64url encoded.
+	VerifyFailureMalformedSignature VerifyFailure = "malformed_signature"
+	// VerifyFailureMalformed is reported for tokens that lack required fields.
+	VerifyFailureMalformed VerifyFailure = "malformed_token"
+	// VerifyFailureReplayed is reported for one-time tokens that were
+	// already used.
+	VerifyFailureReplayed VerifyFailure = "replayed"
+	// VerifyFailureError is reported for other errors, e.g. failed encoding.
+	VerifyFailureError VerifyFailure = "error"
+)
+
+// VerifyObserver receives the outcome of every token verification, e.g. to
+// alert on spikes of tampered tokens. Implementations must be safe for
+// concurrent use and must not block; see PrometheusVerifyObserver for a
+// ready-made adapter.
+type VerifyObserver interface {
+	// ObserveVerify is called once per verification with the token type and
+	// VerifyFailureNone if the token was accepted.
+	ObserveVerify(tokenType string, failure VerifyFailure)
+}
+
+// VerifyObserverFunc adapts a function to the VerifyObserver interface.
+type VerifyObserverFunc func(tokenType string, failure VerifyFailure)
+
+// ObserveVerify implements VerifyObserver.
+func (f VerifyObserverFunc) ObserveVerify(tokenType string, failure VerifyFailure) {
+	f(tokenType, failure)
+}
+
+// verifyObserver is the observer installed with SetVerifyObserver.
+var verifyObserver atomic.Pointer[VerifyObserver]
+
+// SetVerifyObserver installs the observer notified by VerifyToken and the
+// Verify methods of all token types. Passing nil disables observation.
+func SetVerifyObserver(o VerifyObserver) {
+	if o == nil {
+		verifyObserver.Store(nil)
+		return
+	}
+
+	verifyObserver.Store(&o)
+}
+
+// ClassifyVerifyError returns the failure class of an error returned by a
+// token verification. Errors that are not caused by the token itself are
+// classified as VerifyFailureError.
+func ClassifyVerifyError(err error) VerifyFailure {
+	var corrupt base64.CorruptInputError
+
+	switch {
+	case err == nil:
+		return VerifyFailureNone
+	case errors.Is(err, ErrTokenExpired):
+		return VerifyFailureExpired
+	case errors.Is(err, ErrTokenInvalid), errors.Is(err, ErrUnknownKeyID), errors.Is(err, ErrUnknownKeyVersion):
+		return VerifyFailureTampered
+	case errors.Is(err, ErrTokenAlreadyUsed), errors.Is(err, ErrDownloadLimitReached):
+		return VerifyFailureReplayed
+	case errors.Is(err, ErrInvalidSecret):
+		return VerifyFailureMalformedSecret
+	case errors.As(err, &corrupt):
+		return VerifyFailureMalformedSignature
+	case errors.Is(err, ErrMalformedJWT), errors.Is(err, ErrTokenTypeMismatch),
+		errors.Is(err, ErrMalformedToken), errors.Is(err, ErrUnknownTokenKind), errors.Is(err, ErrTokenKindMismatch),
+		errors.Is(err, ErrTokenMissingID):
+		return VerifyFailureMalformed
+	default:
+		return VerifyFailureError
+	}
+}
+
+// observeVerify reports the outcome of a verification that returned err.
+func observeVerify(tokenType string, err error) {
+	notifyVerify(tokenType, ClassifyVerifyError(err))
+}
+
+// notifyVerify reports a verification outcome to the installed observer.
+func notifyVerify(tokenType string, failure VerifyFailure) {
+	if o := verifyObserver.Load(); o != nil {
+		(*o).ObserveVerify(tokenType, failure)
+	}
+}
+
+// tokenTypeOf returns the type reported for token.
+func tokenTypeOf(token URLToken) string {
+	if t, ok := token.(interface{ TokenType() string }); ok {
+		return t.TokenType()
+	}
+
+	return TokenTypeOther
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"github.com/kopexa-grc/common/wellknown"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ResultSuccess is the result label of accepted tokens in
+// PrometheusVerifyObserver; rejected tokens are labeled with their
+// VerifyFailure.
+const ResultSuccess = "success"
+
+// PrometheusVerifyObserver implements VerifyObserver with the counter
+// kopexa_tokens_verifications_total{type,result}.
+//
+// Example:
+//
+//	obs, err := tokens.NewPrometheusVerifyObserver(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	tokens.SetVerifyObserver(obs)
+type PrometheusVerifyObserver struct {
+	verifications *prometheus.CounterVec
+}
+
+// NewPrometheusVerifyObserver creates the verification counter and
+// registers it with reg.
+func NewPrometheusVerifyObserver(reg prometheus.Registerer) (*PrometheusVerifyObserver, error) {
+	o := &PrometheusVerifyObserver{
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: wellknown.PrometheusNamespaceKopexa,
+			Subsystem: "tokens",
+			Name:      "verifications_total",
+			Help:      "Total number of token verifications by token type and result.",
+		}, []string{"type", "result"}),
+	}
+
+	if err := reg.Register(o.verifications); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// ObserveVerify implements VerifyObserver.
+func (o *PrometheusVerifyObserver) ObserveVerify(tokenType string, failure VerifyFailure) {
+	result := string(failure)
+	if failure == VerifyFailureNone {
+		result = ResultSuccess
+	}
+
+	o.verifications.WithLabelValues(tokenType, result).Inc()
+}
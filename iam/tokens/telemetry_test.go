@@ -0,0 +1,128 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type observation struct {
+	tokenType string
+	failure   tokens.VerifyFailure
+}
+
+// recordVerifications installs an observer for the duration of the test.
+func recordVerifications(t *testing.T) func() []observation {
+	t.Helper()
+
+	var (
+		mu  sync.Mutex
+		got []observation
+	)
+
+	tokens.SetVerifyObserver(tokens.VerifyObserverFunc(func(tokenType string, failure tokens.VerifyFailure) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		got = append(got, observation{tokenType, failure})
+	}))
+	t.Cleanup(func() { tokens.SetVerifyObserver(nil) })
+
+	return func() []observation {
+		mu.Lock()
+		defer mu.Unlock()
+
+		out := got
+		got = nil
+
+		return out
+	}
+}
+
+func TestVerifyObserver(t *testing.T) {
+	observed := recordVerifications(t)
+
+	vt, err := tokens.NewVerificationToken("user@example.com")
+	require.NoError(t, err)
+	sig, secret, err := vt.Sign()
+	require.NoError(t, err)
+
+	require.NoError(t, vt.Verify(sig, secret))
+	assert.Equal(t, []observation{{tokens.TokenTypeVerification, tokens.VerifyFailureNone}}, observed())
+
+	tampered := *vt
+	tampered.Email = "other@example.com"
+	require.ErrorIs(t, tampered.Verify(sig, secret), tokens.ErrTokenInvalid)
+	assert.Equal(t, []observation{{tokens.TokenTypeVerification, tokens.VerifyFailureTampered}}, observed())
+
+	require.ErrorIs(t, vt.Verify(sig, secret[:10]), tokens.ErrInvalidSecret)
+	assert.Equal(t, []observation{{tokens.TokenTypeVerification, tokens.VerifyFailureMalformedSecret}}, observed())
+
+	require.Error(t, vt.Verify("%%%", secret))
+	assert.Equal(t, []observation{{tokens.TokenTypeVerification, tokens.VerifyFailureMalformedSignature}}, observed())
+
+	missing := *vt
+	missing.Email = ""
+	require.ErrorIs(t, missing.Verify(sig, secret), tokens.ErrTokenMissingEmail)
+	assert.Equal(t, []observation{{tokens.TokenTypeVerification, tokens.VerifyFailureMalformed}}, observed())
+
+	rt, err := tokens.NewResetToken("user-1")
+	require.NoError(t, err)
+	rt.ExpiresAt = time.Now().Add(-time.Minute)
+	sig, secret, err = rt.Sign()
+	require.NoError(t, err)
+
+	require.ErrorIs(t, rt.Verify(sig, secret), tokens.ErrTokenExpired)
+	assert.Equal(t, []observation{{tokens.TokenTypeReset, tokens.VerifyFailureExpired}}, observed())
+
+	invite, err := tokens.NewOrganizationInviteToken("user@example.com", "org-1")
+	require.NoError(t, err)
+	sig, secret, err = invite.Sign()
+	require.NoError(t, err)
+
+	require.NoError(t, invite.Verify(sig, secret))
+	assert.Equal(t, []observation{{tokens.TokenTypeInvite, tokens.VerifyFailureNone}}, observed())
+
+	require.ErrorIs(t, tokens.VerifyState(nil, "state", secret), tokens.ErrTokenInvalid)
+	assert.Equal(t, []observation{{tokens.TokenTypeOAuthState, tokens.VerifyFailureMalformed}}, observed())
+}
+
+func TestPrometheusVerifyObserver(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	obs, err := tokens.NewPrometheusVerifyObserver(reg)
+	require.NoError(t, err)
+
+	tokens.SetVerifyObserver(obs)
+	t.Cleanup(func() { tokens.SetVerifyObserver(nil) })
+
+	vt, err := tokens.NewVerificationToken("user@example.com")
+	require.NoError(t, err)
+	sig, secret, err := vt.Sign()
+	require.NoError(t, err)
+
+	require.NoError(t, vt.Verify(sig, secret))
+	require.Error(t, vt.Verify(sig, secret[:1]))
+	require.Error(t, vt.Verify(sig, secret[:1]))
+
+	expected := `
+# HELP kopexa_tokens_verifications_total Total number of token verifications by token type and result.
+# TYPE kopexa_tokens_verifications_total counter
+kopexa_tokens_verifications_total{result="malformed_secret",type="verification"} 2
+kopexa_tokens_verifications_total{result="success",type="verification"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "kopexa_tokens_verifications_total"))
+
+	_, err = tokens.NewPrometheusVerifyObserver(reg)
+	assert.Error(t, err, "duplicate registration")
+}
@@ -4,6 +4,7 @@
 package tokens
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
@@ -26,6 +27,14 @@ type SigningInfo struct {
 	ExpiresAt time.Time `msgpack:"expires_at"`
 	// Nonce is a random value used to prevent token reuse.
 	Nonce []byte `msgpack:"nonce"`
+	// TokenID uniquely identifies the token, e.g. to enforce single use with
+	// a Consumer. It is only set by AssignTokenID and omitted when empty, so
+	// that other tokens keep their serialized form.
+	TokenID string `msgpack:"token_id,omitempty"`
+	// KeyVersion is the version of the KeyRing key the token is signed
+	// with. It is zero for tokens signed with a per-token key and then
+	// omitted, so that their serialized form is unchanged.
+	KeyVersion uint32 `msgpack:"key_version,omitempty"`
 }
 
 // NewSigningInfo creates a new SigningInfo instance with the specified expiration duration.
@@ -43,7 +52,7 @@ func NewSigningInfo(expires time.Duration) (SigningInfo, error) {
 	}
 
 	info := SigningInfo{
-		ExpiresAt: time.Now().UTC().Add(expires).Truncate(time.Microsecond),
+		ExpiresAt: now().UTC().Add(expires).Truncate(time.Microsecond),
 		Nonce:     make([]byte, nonceLength),
 	}
 
@@ -54,12 +63,37 @@ func NewSigningInfo(expires time.Duration) (SigningInfo, error) {
 	return info, nil
 }
 
-// IsExpired checks if the token has passed its expiration time.
+// AssignTokenID sets a random TokenID, which tokens need to be redeemed with
+// Consume. It must be called before signing. As the TokenID is signed,
+// callers that rebuild a token from stored fields to verify it must store
+// the TokenID along with the other fields.
+//
+// Example:
+//
+//	token, err := tokens.NewResetToken(userID)
+//	...
+//	if err := token.AssignTokenID(); err != nil {
+//		return err
+//	}
+//	signature, secret, err := token.Sign()
+func (d *SigningInfo) AssignTokenID() error {
+	id := make([]byte, tokenIDLength)
+	if _, err := rand.Read(id); err != nil {
+		return ErrFailedSigning.With(err)
+	}
+
+	d.TokenID = base64.RawURLEncoding.EncodeToString(id)
+
+	return nil
+}
+
+// IsExpired checks if the token has passed its expiration time, according to
+// the clock and leeway installed with SetClock and SetLeeway.
 //
 // Returns:
 //   - bool: true if the token is expired, false otherwise
 func (d SigningInfo) IsExpired() bool {
-	return d.ExpiresAt.Before(time.Now())
+	return isExpired(d.ExpiresAt)
 }
 
 // SignToken marshals and signs any token that embeds SigningInfo
@@ -99,9 +133,20 @@ func (d SigningInfo) signData(data []byte) (string, []byte, error) {
 	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), secret, nil
 }
 
-// VerifyToken provides common verification logic for all token types
-func (d SigningInfo) VerifyToken(token URLToken, signature string, secret []byte) error {
-	if d.IsExpired() {
+// VerifyToken provides common verification logic for all token types. The
+// expiration is checked with the clock and leeway of opts, which default to
+// SetClock and SetLeeway. The outcome is reported to the observer installed
+// with SetVerifyObserver.
+func (d SigningInfo) VerifyToken(token URLToken, signature string, secret []byte, opts ...VerifyOption) error {
+	err := d.verifyToken(token, signature, secret, newVerifyConfig(opts))
+	observeVerify(tokenTypeOf(token), err)
+
+	return err
+}
+
+// verifyToken implements VerifyToken.
+func (d SigningInfo) verifyToken(token URLToken, signature string, secret []byte, cfg verifyConfig) error {
+	if cfg.expired(d.ExpiresAt) {
 		return ErrTokenExpired
 	}
 
@@ -130,9 +175,15 @@ func (d SigningInfo) VerifyToken(token URLToken, signature string, secret []byte
 // Returns:
 //   - error: If verification fails
 func (d SigningInfo) verifyData(data []byte, signature string, secret []byte) error {
+	return verifyMAC(data, signature, secret[nonceLength:])
+}
+
+// verifyMAC compares signature in constant time with the HMAC-SHA256 of
+// data under key.
+func verifyMAC(data []byte, signature string, key []byte) error {
 	var err error
 
-	mac := hmac.New(sha256.New, secret[nonceLength:])
+	mac := hmac.New(sha256.New, key)
 	if _, err = mac.Write(data); err != nil {
 		return err
 	}
@@ -212,28 +263,59 @@ func (t *OrganizationInviteToken) Sign() (string, []byte, error) {
 //
 // Returns:
 //   - error: If verification fails
-func (t *OrganizationInviteToken) Verify(signature string, secret []byte) error {
-	if t.Email == "" {
-		return ErrInviteTokenMissingEmail
+func (t *OrganizationInviteToken) Verify(signature string, secret []byte, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeInvite, VerifyFailureMalformed)
+		return err
 	}
 
-	if t.IsExpired() {
-		return ErrTokenExpired
-	}
+	return t.VerifyToken(t, signature, secret, opts...)
+}
 
-	if len(secret) != nonceLength+keyLength {
-		return ErrInvalidSecret
+// SignWithKeyRing signs the token with the current key of ring; see
+// KeyRing. The returned secret holds the nonce only.
+func (t *OrganizationInviteToken) SignWithKeyRing(ring *KeyRing) (string, []byte, error) {
+	return t.SignTokenWithKeyRing(t, ring)
+}
+
+// VerifyWithKeyRing verifies a token signed with SignWithKeyRing like
+// Verify; opts set the clock and leeway. The token's KeyVersion must be
+// restored before.
+func (t *OrganizationInviteToken) VerifyWithKeyRing(signature string, secret []byte, ring *KeyRing, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeInvite, VerifyFailureMalformed)
+		return err
 	}
 
-	t.Nonce = secret[0:nonceLength]
+	return t.VerifyTokenWithKeyRing(t, signature, secret, ring, opts...)
+}
 
-	var data []byte
+// Consume verifies the token like Verify and marks it as used with
+// consumer, so that it cannot be redeemed again; see Consumer.
+func (t *OrganizationInviteToken) Consume(ctx context.Context, signature string, secret []byte, consumer Consumer) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeInvite, VerifyFailureMalformed)
+		return err
+	}
 
-	var err error
+	return t.ConsumeToken(ctx, t, signature, secret, consumer)
+}
 
-	if data, err = msgpack.Marshal(t); err != nil {
-		return err
+// Validate checks that the invite token has an email address.
+func (t *OrganizationInviteToken) Validate() error {
+	if t.Email == "" {
+		return ErrInviteTokenMissingEmail
 	}
 
-	return t.verifyData(data, signature, secret)
+	return nil
+}
+
+// SetNonce sets the nonce for verification (implements URLToken contract).
+func (t *OrganizationInviteToken) SetNonce(nonce []byte) {
+	t.Nonce = nonce
+}
+
+// TokenType returns TokenTypeInvite.
+func (t *OrganizationInviteToken) TokenType() string {
+	return TokenTypeInvite
 }
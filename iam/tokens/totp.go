@@ -0,0 +1,355 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // HMAC-SHA1 is the TOTP default supported by all authenticator apps.
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTOTPSkew is the number of time steps before and after the current
+// one in which codes are accepted, to tolerate clock drift.
+const DefaultTOTPSkew = 1
+
+// totpEncoding encodes TOTP secrets as unpadded base32 (RFC 4648), as
+// expected by authenticator apps.
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPToken is the server-side state of a pending MFA enrollment. It carries
+// the TOTP secret shown to the user (as otpauth:// URI, usually rendered as
+// QR code) until the user confirms the enrollment with a first valid code;
+// the secret is then persisted with the user and the token discarded.
+type TOTPToken struct {
+	// UserID is the user enrolling the authenticator.
+	UserID string `msgpack:"user_id"`
+	// Secret is the base32 encoded TOTP secret.
+	Secret string `msgpack:"secret"`
+	// SigningInfo contains the cryptographic information for the token.
+	SigningInfo
+}
+
+// NewTOTPToken creates an enrollment token with a random secret (see
+// NewTOTPSecret) that expires in totpEnrollmentExpirationMinutes (10)
+// minutes.
+func NewTOTPToken(userID string) (*TOTPToken, error) {
+	if userID == "" {
+		return nil, ErrMissingUserID
+	}
+
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	token := &TOTPToken{
+		UserID: userID,
+		Secret: secret,
+	}
+
+	if token.SigningInfo, err = NewSigningInfo(time.Minute * totpEnrollmentExpirationMinutes); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Sign creates a base64 URL encoded signature for the enrollment token. See
+// VerificationToken.Sign. The token and secret stay server-side.
+func (t *TOTPToken) Sign() (string, []byte, error) {
+	return t.SignToken(t)
+}
+
+// Validate checks that the token has a user ID and a secret.
+func (t *TOTPToken) Validate() error {
+	if t.UserID == "" {
+		return ErrTokenMissingUserID
+	}
+
+	if t.Secret == "" {
+		return ErrTokenMissingTOTPSecret
+	}
+
+	return nil
+}
+
+// SetNonce sets the nonce for verification (implements URLToken contract).
+func (t *TOTPToken) SetNonce(nonce []byte) {
+	t.Nonce = nonce
+}
+
+// TokenType returns TokenTypeTOTP.
+func (t *TOTPToken) TokenType() string {
+	return TokenTypeTOTP
+}
+
+// Verify performs full validation (required fields, expiration, signature) for a TOTPToken.
+func (t *TOTPToken) Verify(signature string, secret []byte, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeTOTP, VerifyFailureMalformed)
+		return err
+	}
+
+	return t.VerifyToken(t, signature, secret, opts...)
+}
+
+// URI returns the otpauth:// URI of the secret; see TOTPURI.
+func (t *TOTPToken) URI(issuer, accountName string) string {
+	return TOTPURI(issuer, accountName, t.Secret)
+}
+
+// ConfirmEnrollment verifies the token like Verify and checks that code is
+// valid for its secret, proving that the user's authenticator is set up.
+// The code is checked at the time of the verification clock, within
+// DefaultTOTPSkew steps.
+//
+// Returns ErrInvalidTOTPCode if the code does not match.
+func (t *TOTPToken) ConfirmEnrollment(signature string, secret []byte, code string, opts ...VerifyOption) error {
+	if err := t.Verify(signature, secret, opts...); err != nil {
+		return err
+	}
+
+	_, err := VerifyTOTPCode(t.Secret, code, WithTOTPTime(newVerifyConfig(opts).clock.Now()))
+
+	return err
+}
+
+// NewTOTPSecret returns a random base32 encoded secret of totpSecretLength
+// (20) bytes, the key size recommended by RFC 4226.
+func NewTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", ErrFailedSigning.With(err)
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPURI returns the otpauth:// key URI for secret, e.g. for a QR code.
+// The URI uses the defaults of RFC 6238 (SHA1, 6 digits, 30 seconds), which
+// every authenticator app supports.
+//
+// Example:
+//
+//	uri := tokens.TOTPURI("Kopexa", "jane@example.com", token.Secret)
+//	// otpauth://totp/Kopexa:jane@example.com?algorithm=SHA1&digits=6&issuer=Kopexa&period=30&secret=...
+func TOTPURI(issuer, accountName, secret string) string {
+	label := accountName
+	if issuer != "" {
+		label = issuer + ":" + accountName
+	}
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriodSeconds))
+
+	if issuer != "" {
+		query.Set("issuer", issuer)
+	}
+
+	u := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + label, RawQuery: query.Encode()}
+
+	return u.String()
+}
+
+// TOTPOption configures the generation and verification of TOTP codes.
+type TOTPOption func(*totpConfig)
+
+type totpConfig struct {
+	skew int
+	now  time.Time
+}
+
+// WithTOTPSkew sets the number of time steps before and after the current
+// one in which codes are accepted. Defaults to DefaultTOTPSkew; 0 accepts
+// the current step only.
+func WithTOTPSkew(steps int) TOTPOption {
+	return func(c *totpConfig) {
+		if steps >= 0 {
+			c.skew = steps
+		}
+	}
+}
+
+// WithTOTPTime sets the time codes are generated or verified for. Defaults
+// to the current time.
+func WithTOTPTime(now time.Time) TOTPOption {
+	return func(c *totpConfig) {
+		c.now = now
+	}
+}
+
+func newTOTPConfig(opts []TOTPOption) *totpConfig {
+	c := &totpConfig{skew: DefaultTOTPSkew, now: time.Now()}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// GenerateTOTPCode returns the 6-digit code of secret for the current time
+// step (RFC 6238).
+func GenerateTOTPCode(secret string, opts ...TOTPOption) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return totpCode(key, totpStep(newTOTPConfig(opts).now)), nil
+}
+
+// VerifyTOTPCode checks a 6-digit code against secret within the skew
+// window and returns the time step it was generated for. Store the step
+// with the user and reject codes for steps that are not newer, or pass
+// "<user>:<step>" to a Consumer, so that an observed code cannot be
+// replayed within its validity window.
+//
+// Returns ErrInvalidTOTPCode if the code does not match and
+// ErrInvalidTOTPSecret if secret is not base32 encoded.
+func VerifyTOTPCode(secret, code string, opts ...TOTPOption) (int64, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(code) != totpDigits {
+		return 0, ErrInvalidTOTPCode
+	}
+
+	c := newTOTPConfig(opts)
+	current := totpStep(c.now)
+
+	for offset := -c.skew; offset <= c.skew; offset++ {
+		step := current + int64(offset)
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, nil
+		}
+	}
+
+	return 0, ErrInvalidTOTPCode
+}
+
+// decodeTOTPSecret decodes a base32 secret, ignoring case, spaces and
+// padding as entered by users.
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.TrimRight(strings.ToUpper(strings.ReplaceAll(secret, " ", "")), "=")
+
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidTOTPSecret
+	}
+
+	return key, nil
+}
+
+// totpStep returns the RFC 6238 time step of t.
+func totpStep(t time.Time) int64 {
+	return t.Unix() / totpPeriodSeconds
+}
+
+// totpCode computes the HOTP value (RFC 4226) of key for counter.
+func totpCode(key []byte, counter int64) string {
+	var msg [8]byte
+
+	binary.BigEndian.PutUint64(msg[:], uint64(counter)) //nolint:gosec // time steps are positive
+
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1_000_000)
+}
+
+// recoveryCodeAlphabet excludes characters that are easily confused, such
+// as 0/O and 1/I/L.
+const recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+// GenerateRecoveryCodes returns n random single-use recovery codes of the
+// form "xxxxx-xxxxx". Show them to the user once and store only their
+// HashRecoveryCode values.
+//
+// Returns ErrInvalidRecoveryCodeCount if n is negative.
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	if n < 0 {
+		return nil, ErrInvalidRecoveryCodeCount
+	}
+
+	codes := make([]string, n)
+
+	for i := range codes {
+		s, err := randomCharacters(recoveryCodeLength, recoveryCodeAlphabet)
+		if err != nil {
+			return nil, err
+		}
+
+		codes[i] = s[:recoveryCodeLength/2] + "-" + s[recoveryCodeLength/2:]
+	}
+
+	return codes, nil
+}
+
+// HashRecoveryCode returns the hex encoded SHA-256 hash of a recovery code.
+// Case, spaces and dashes are ignored, so that codes can be typed freely.
+func HashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchRecoveryCode returns the index of the hash in hashes that code
+// matches, or -1. Remove the matched hash afterwards, as every recovery code
+// can only be used once.
+func MatchRecoveryCode(code string, hashes []string) int {
+	hash := []byte(HashRecoveryCode(code))
+	match := -1
+
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare(hash, []byte(h)) == 1 && match < 0 {
+			match = i
+		}
+	}
+
+	return match
+}
+
+// randomCharacters returns a uniformly distributed random string of length
+// characters of charset, which must have between 1 and 256 characters.
+// Random bytes beyond the largest multiple of len(charset) are rejected, so
+// that no character is more likely than another.
+func randomCharacters(length int, charset string) (string, error) {
+	limit := 256 - 256%len(charset)
+	b := make([]byte, 0, length)
+	buf := make([]byte, length)
+
+	for len(b) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", ErrFailedSigning.With(err)
+		}
+
+		for _, r := range buf {
+			if int(r) < limit && len(b) < length {
+				b = append(b, charset[int(r)%len(charset)])
+			}
+		}
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,182 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package tokens_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/iam/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA1 test key of RFC 6238, appendix B.
+var rfc6238Secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode_RFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		at := tokens.WithTOTPTime(time.Unix(tt.unix, 0))
+
+		code, err := tokens.GenerateTOTPCode(rfc6238Secret, at)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, code, tt.unix)
+
+		step, err := tokens.VerifyTOTPCode(rfc6238Secret, tt.want, at)
+		require.NoError(t, err)
+		assert.Equal(t, tt.unix/30, step)
+	}
+}
+
+func TestVerifyTOTPCode_Skew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	previous, err := tokens.GenerateTOTPCode(rfc6238Secret, tokens.WithTOTPTime(now.Add(-30*time.Second)))
+	require.NoError(t, err)
+
+	_, err = tokens.VerifyTOTPCode(rfc6238Secret, previous, tokens.WithTOTPTime(now))
+	require.NoError(t, err)
+
+	_, err = tokens.VerifyTOTPCode(rfc6238Secret, previous, tokens.WithTOTPTime(now), tokens.WithTOTPSkew(0))
+	assert.ErrorIs(t, err, tokens.ErrInvalidTOTPCode)
+
+	old, err := tokens.GenerateTOTPCode(rfc6238Secret, tokens.WithTOTPTime(now.Add(-90*time.Second)))
+	require.NoError(t, err)
+
+	_, err = tokens.VerifyTOTPCode(rfc6238Secret, old, tokens.WithTOTPTime(now))
+	assert.ErrorIs(t, err, tokens.ErrInvalidTOTPCode)
+
+	_, err = tokens.VerifyTOTPCode(rfc6238Secret, old, tokens.WithTOTPTime(now), tokens.WithTOTPSkew(3))
+	require.NoError(t, err)
+
+	_, err = tokens.VerifyTOTPCode(rfc6238Secret, "12345", tokens.WithTOTPTime(now))
+	assert.ErrorIs(t, err, tokens.ErrInvalidTOTPCode)
+
+	_, err = tokens.VerifyTOTPCode("not base32!", "123456")
+	assert.ErrorIs(t, err, tokens.ErrInvalidTOTPSecret)
+}
+
+func TestTOTPURI(t *testing.T) {
+	secret, err := tokens.NewTOTPSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri, err := url.Parse(tokens.TOTPURI("Kopexa", "jane@example.com", secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Kopexa:jane@example.com", uri.Path)
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "Kopexa", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+}
+
+func TestTOTPToken_Enrollment(t *testing.T) {
+	token, err := tokens.NewTOTPToken("user-1")
+	require.NoError(t, err)
+	assert.Len(t, token.Secret, 32)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), token.ExpiresAt, 5*time.Second)
+
+	uri, err := url.Parse(token.URI("Kopexa", "jane@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "/Kopexa:jane@example.com", uri.Path)
+	assert.Equal(t, token.Secret, uri.Query().Get("secret"))
+
+	signature, secret, err := token.Sign()
+	require.NoError(t, err)
+
+	code, err := tokens.GenerateTOTPCode(token.Secret)
+	require.NoError(t, err)
+
+	require.NoError(t, token.ConfirmEnrollment(signature, secret, code))
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	assert.ErrorIs(t, token.ConfirmEnrollment(signature, secret, wrong), tokens.ErrInvalidTOTPCode)
+
+	token.Secret = strings.ToLower(token.Secret)
+	assert.ErrorIs(t, token.ConfirmEnrollment(signature, secret, code), tokens.ErrTokenInvalid)
+
+	_, err = tokens.NewTOTPToken("")
+	assert.ErrorIs(t, err, tokens.ErrMissingUserID)
+}
+
+func TestTOTPToken_ConfirmEnrollmentVerifyOptions(t *testing.T) {
+	token, err := tokens.NewTOTPToken("user-1")
+	require.NoError(t, err)
+
+	signature, secret, err := token.Sign()
+	require.NoError(t, err)
+
+	later := token.ExpiresAt.Add(time.Minute)
+	clock := tokens.WithClock(tokens.ClockFunc(func() time.Time { return later }))
+
+	code, err := tokens.GenerateTOTPCode(token.Secret, tokens.WithTOTPTime(later))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, token.ConfirmEnrollment(signature, secret, code, clock), tokens.ErrTokenExpired)
+	require.NoError(t, token.ConfirmEnrollment(signature, secret, code, clock, tokens.WithLeeway(2*time.Minute)))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := tokens.GenerateRecoveryCodes(8)
+	require.NoError(t, err)
+	require.Len(t, codes, 8)
+
+	hashes := make([]string, 0, len(codes))
+	for _, c := range codes {
+		assert.Regexp(t, `^[2-9a-z]{5}-[2-9a-z]{5}$`, c)
+		hashes = append(hashes, tokens.HashRecoveryCode(c))
+	}
+
+	assert.Equal(t, 3, tokens.MatchRecoveryCode(codes[3], hashes))
+	assert.Equal(t, 3, tokens.MatchRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[3], "-", " ")), hashes))
+	assert.Equal(t, -1, tokens.MatchRecoveryCode("aaaaa-aaaaa", hashes))
+
+	codes, err = tokens.GenerateRecoveryCodes(0)
+	require.NoError(t, err)
+	assert.Empty(t, codes)
+
+	_, err = tokens.GenerateRecoveryCodes(-1)
+	assert.ErrorIs(t, err, tokens.ErrInvalidRecoveryCodeCount)
+}
+
+func TestRecoveryCodes_Uniform(t *testing.T) {
+	codes, err := tokens.GenerateRecoveryCodes(30000)
+	require.NoError(t, err)
+
+	counts := make(map[rune]int)
+	for _, c := range codes {
+		for _, r := range strings.ReplaceAll(c, "-", "") {
+			counts[r]++
+		}
+	}
+
+	// The 300000 characters are drawn from 31 characters, i.e. about 9677
+	// times each. Without rejection sampling the first 8 characters are
+	// drawn with probability 9/256 instead of 1/31, i.e. about 10547 times.
+	require.Len(t, counts, 31)
+
+	for r, n := range counts {
+		assert.InDelta(t, 9677, n, 500, string(r))
+	}
+}
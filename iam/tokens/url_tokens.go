@@ -4,6 +4,7 @@
 package tokens
 
 import (
+	"context"
 	"time"
 
 	"github.com/vmihailenco/msgpack/v5"
@@ -49,35 +50,70 @@ func (t *VerificationToken) Sign() (string, []byte, error) {
 
 // Verify checks that a token was signed with the secret, required fields are present,
 // and it has not expired.
-func (t *VerificationToken) Verify(signature string, secret []byte) (err error) {
-	if t.Email == "" {
-		return ErrTokenMissingEmail
+func (t *VerificationToken) Verify(signature string, secret []byte, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeVerification, VerifyFailureMalformed)
+		return err
 	}
 
-	if t.IsExpired() {
-		return ErrTokenExpired
-	}
+	return t.VerifyToken(t, signature, secret, opts...)
+}
 
-	if len(secret) != nonceLength+keyLength {
-		return ErrInvalidSecret
-	}
+// SignWithKeyRing signs the token with the current key of ring. See
+// OrganizationInviteToken.SignWithKeyRing.
+func (t *VerificationToken) SignWithKeyRing(ring *KeyRing) (string, []byte, error) {
+	return t.SignTokenWithKeyRing(t, ring)
+}
 
-	// Serialize the struct with the nonce from the secret
-	t.Nonce = secret[0:nonceLength]
+// VerifyWithKeyRing verifies a token signed with SignWithKeyRing. See
+// OrganizationInviteToken.VerifyWithKeyRing.
+func (t *VerificationToken) VerifyWithKeyRing(signature string, secret []byte, ring *KeyRing, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeVerification, VerifyFailureMalformed)
+		return err
+	}
 
-	var data []byte
+	return t.VerifyTokenWithKeyRing(t, signature, secret, ring, opts...)
+}
 
-	if data, err = msgpack.Marshal(t); err != nil {
+// Consume verifies the token like Verify and marks it as used with
+// consumer, so that it cannot be redeemed again; see Consumer.
+func (t *VerificationToken) Consume(ctx context.Context, signature string, secret []byte, consumer Consumer) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeVerification, VerifyFailureMalformed)
 		return err
 	}
 
-	return t.verifyData(data, signature, secret)
+	return t.ConsumeToken(ctx, t, signature, secret, consumer)
+}
+
+// Validate checks that the verification token has an email address.
+func (t *VerificationToken) Validate() error {
+	if t.Email == "" {
+		return ErrTokenMissingEmail
+	}
+
+	return nil
+}
+
+// SetNonce sets the nonce for verification (implements URLToken contract).
+func (t *VerificationToken) SetNonce(nonce []byte) {
+	t.Nonce = nonce
+}
+
+// TokenType returns TokenTypeVerification.
+func (t *VerificationToken) TokenType() string {
+	return TokenTypeVerification
 }
 
 // ResetToken packages a user ID with random data and an expiration time so that it can
 // be serialized and hashed into a token which can be sent to users for password resets.
 type ResetToken struct {
 	UserID string `msgpack:"user_id"`
+	// FingerprintHash optionally binds the token to the client that requested
+	// the reset; see NewResetTokenWithContext. It is omitted when empty so
+	// that unbound tokens keep their serialized form.
+	FingerprintHash string `msgpack:"fingerprint_hash,omitempty"`
 	SigningInfo
 }
 
@@ -98,6 +134,20 @@ func NewResetToken(id string) (token *ResetToken, err error) {
 	return token, nil
 }
 
+// NewResetTokenWithContext creates a reset token like NewResetToken and binds
+// it to the fingerprint of the requesting client, so that a link forwarded to
+// someone else can be detected by VerifyFingerprint.
+func NewResetTokenWithContext(id string, issue IssueContext) (*ResetToken, error) {
+	token, err := NewResetToken(id)
+	if err != nil {
+		return nil, err
+	}
+
+	token.FingerprintHash = issue.Fingerprint()
+
+	return token, nil
+}
+
 // Sign creates a base64 URL encoded signature for the reset token. See VerificationToken.Sign.
 func (t *ResetToken) Sign() (string, []byte, error) {
 	return t.SignToken(t)
@@ -117,11 +167,63 @@ func (t *ResetToken) SetNonce(nonce []byte) {
 	t.Nonce = nonce
 }
 
+// TokenType returns TokenTypeReset.
+func (t *ResetToken) TokenType() string {
+	return TokenTypeReset
+}
+
 // Verify performs full validation (required fields, expiration, signature) for a ResetToken.
-func (t *ResetToken) Verify(signature string, secret []byte) error {
+func (t *ResetToken) Verify(signature string, secret []byte, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeReset, VerifyFailureMalformed)
+		return err
+	}
+
+	return t.VerifyToken(t, signature, secret, opts...)
+}
+
+// SignWithKeyRing signs the token with the current key of ring. See
+// OrganizationInviteToken.SignWithKeyRing.
+func (t *ResetToken) SignWithKeyRing(ring *KeyRing) (string, []byte, error) {
+	return t.SignTokenWithKeyRing(t, ring)
+}
+
+// VerifyWithKeyRing verifies a token signed with SignWithKeyRing. See
+// OrganizationInviteToken.VerifyWithKeyRing.
+func (t *ResetToken) VerifyWithKeyRing(signature string, secret []byte, ring *KeyRing, opts ...VerifyOption) error {
+	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeReset, VerifyFailureMalformed)
+		return err
+	}
+
+	return t.VerifyTokenWithKeyRing(t, signature, secret, ring, opts...)
+}
+
+// Consume verifies the token like Verify and marks it as used with
+// consumer, so that it cannot be redeemed again; see Consumer.
+func (t *ResetToken) Consume(ctx context.Context, signature string, secret []byte, consumer Consumer) error {
 	if err := t.Validate(); err != nil {
+		notifyVerify(TokenTypeReset, VerifyFailureMalformed)
 		return err
 	}
 
-	return t.VerifyToken(t, signature, secret)
+	return t.ConsumeToken(ctx, t, signature, secret, consumer)
+}
+
+// VerifyFingerprint verifies the token like Verify and compares its
+// fingerprint with the client redeeming it. With FingerprintStrict a mismatch
+// returns ErrFingerprintMismatch; with FingerprintAdvisory the token is
+// accepted and FingerprintMismatched is returned. Tokens without fingerprint
+// are accepted under both policies.
+func (t *ResetToken) VerifyFingerprint(signature string, secret []byte, candidate IssueContext, policy FingerprintPolicy) (FingerprintResult, error) {
+	if err := t.Verify(signature, secret); err != nil {
+		return FingerprintNotBound, err
+	}
+
+	result := compareFingerprint(t.FingerprintHash, candidate)
+	if result == FingerprintMismatched && policy == FingerprintStrict {
+		return result, ErrFingerprintMismatch
+	}
+
+	return result, nil
 }
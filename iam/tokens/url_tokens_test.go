@@ -55,6 +55,87 @@ func TestVerificationToken(t *testing.T) {
 	})
 }
 
+func TestEmailChangeToken(t *testing.T) {
+	t.Run("success sign/verify", func(t *testing.T) {
+		ct, err := tokens.NewEmailChangeToken("user-1", "old@example.com", "new@example.com")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), ct.ExpiresAt, 5*time.Second)
+
+		sig, secret, err := ct.Sign()
+		require.NoError(t, err)
+		require.NoError(t, ct.Verify(sig, secret))
+	})
+
+	t.Run("construction requires user id and both addresses", func(t *testing.T) {
+		_, err := tokens.NewEmailChangeToken("", "old@example.com", "new@example.com")
+		require.ErrorIs(t, err, tokens.ErrMissingUserID)
+
+		_, err = tokens.NewEmailChangeToken("user-1", "", "new@example.com")
+		require.ErrorIs(t, err, tokens.ErrMissingEmail)
+
+		_, err = tokens.NewEmailChangeToken("user-1", "old@example.com", "")
+		require.ErrorIs(t, err, tokens.ErrMissingEmail)
+	})
+
+	t.Run("tampered addresses", func(t *testing.T) {
+		ct, err := tokens.NewEmailChangeToken("user-1", "old@example.com", "new@example.com")
+		require.NoError(t, err)
+		sig, secret, err := ct.Sign()
+		require.NoError(t, err)
+
+		clone := *ct
+		clone.NewEmail = "attacker@example.com"
+		require.ErrorIs(t, clone.Verify(sig, secret), tokens.ErrTokenInvalid)
+
+		clone = *ct
+		clone.OldEmail = "other@example.com"
+		require.ErrorIs(t, clone.Verify(sig, secret), tokens.ErrTokenInvalid)
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		observed := recordVerifications(t)
+
+		ct, err := tokens.NewEmailChangeToken("user-1", "old@example.com", "new@example.com")
+		require.NoError(t, err)
+		sig, secret, err := ct.Sign()
+		require.NoError(t, err)
+
+		clone := *ct
+		clone.OldEmail = ""
+		require.ErrorIs(t, clone.Verify(sig, secret), tokens.ErrTokenMissingOldEmail)
+
+		clone = *ct
+		clone.NewEmail = ""
+		require.ErrorIs(t, clone.Verify(sig, secret), tokens.ErrTokenMissingNewEmail)
+
+		clone = *ct
+		clone.UserID = ""
+		require.ErrorIs(t, clone.Verify(sig, secret), tokens.ErrTokenMissingUserID)
+
+		assert.Equal(t, []observation{
+			{tokens.TokenTypeEmailChange, tokens.VerifyFailureMalformed},
+			{tokens.TokenTypeEmailChange, tokens.VerifyFailureMalformed},
+			{tokens.TokenTypeEmailChange, tokens.VerifyFailureMalformed},
+		}, observed())
+	})
+
+	t.Run("default registry", func(t *testing.T) {
+		ct, err := tokens.NewEmailChangeToken("user-1", "old@example.com", "new@example.com")
+		require.NoError(t, err)
+
+		registry := tokens.NewDefaultTokenRegistry()
+
+		signed, secret, err := registry.SignToken(ct)
+		require.NoError(t, err)
+
+		verified, err := tokens.VerifyTokenAs[*tokens.EmailChangeToken](registry, signed, func(string, tokens.RegisteredToken) ([]byte, error) {
+			return secret, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", verified.NewEmail)
+	})
+}
+
 func TestResetToken(t *testing.T) {
 	t.Run("construction requires user id", func(t *testing.T) {
 		rt, err := tokens.NewResetToken("")
@@ -15,7 +15,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/pquerna/otp/totp"
+	"github.com/kopexa-grc/common/iam/tokens"
 )
 
 // otpNATS defines the interface for NATS operations
@@ -66,17 +66,12 @@ func (o *OTP) TOTPQRString(u *User) (string, error) {
 		return "", fmt.Errorf("failed to decrypt secret: %w", err)
 	}
 
-	// Generate QR code URL
-	key, err := totp.Generate(totp.GenerateOpts{
-		Issuer:      o.issuer,
-		AccountName: u.DefaultName(),
-		Secret:      []byte(decrypted),
-	})
-	if err != nil {
+	// Reject secrets that authenticator apps could not use
+	if _, err := tokens.GenerateTOTPCode(decrypted); err != nil {
 		return "", fmt.Errorf("failed to generate TOTP key: %w", err)
 	}
 
-	return key.URL(), nil
+	return tokens.TOTPURI(o.issuer, u.DefaultName(), decrypted), nil
 }
 
 // TOTPDecryptedSecret decrypts a TOTP secret
@@ -120,13 +115,11 @@ func (o *OTP) TOTPSecret(_ *User) (string, error) {
 	latestSecret := o.secrets[len(o.secrets)-1]
 
 	// Generate a valid base32 secret for TOTP
-	base32Secret := make([]byte, Base32SecretLength)
-	if _, err := crand.Read(base32Secret); err != nil {
+	secretString, err := tokens.NewTOTPSecret()
+	if err != nil {
 		return "", ErrFailedToGenerateSecret
 	}
 
-	secretString := base32.StdEncoding.EncodeToString(base32Secret)
-
 	// Encrypt the base32 secret
 	block, err := aes.NewCipher([]byte(latestSecret.Key))
 	if err != nil {
@@ -194,19 +187,32 @@ func (o *OTP) ValidateOTP(code, hash string) error {
 	return nil
 }
 
-// ValidateTOTP checks if a User's TOTP code is valid
+// ValidateTOTP checks if a User's TOTP code is valid.
+//
+// Authenticators enrolled with a QR code of an earlier version received the
+// secret base32 encoded a second time, i.e. they use the bytes of the base32
+// string as key. Their codes are still accepted; see legacyTOTPSecret.
 func (o *OTP) ValidateTOTP(_ context.Context, user *User, code string) error {
 	secret, err := o.TOTPDecryptedSecret(user.TOTPSecret)
 	if err != nil {
 		return ErrFailedToValidateCode
 	}
 
-	valid := totp.Validate(code, secret)
-	if !valid {
-		return ErrInvalidCode
+	if _, err := tokens.VerifyTOTPCode(secret, code); err == nil {
+		return nil
 	}
 
-	return nil
+	if _, err := tokens.VerifyTOTPCode(legacyTOTPSecret(secret), code); err == nil {
+		return nil
+	}
+
+	return ErrInvalidCode
+}
+
+// legacyTOTPSecret returns the secret as encoded in the QR codes of earlier
+// versions, which base32 encoded the already base32 encoded secret again.
+func legacyTOTPSecret(secret string) string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(secret))
 }
 
 // GenerateRecoveryCodes creates a set of recovery codes for a user
@@ -219,15 +225,24 @@ func (o *OTP) GenerateRecoveryCodes() []string {
 	return codes
 }
 
-// generateRandomString generates a random string of the specified length using the provided charset
+// generateRandomString generates a random string of the specified length using the provided charset.
+// Random bytes beyond the largest multiple of len(charset) are rejected, so that no character is more
+// likely than another.
 func generateRandomString(length int, charset string) string {
-	b := make([]byte, length)
-	if _, err := crand.Read(b); err != nil {
-		panic("crypto/rand failed")
-	}
-
-	for i := range b {
-		b[i] = charset[int(b[i])%len(charset)]
+	limit := 256 - 256%len(charset)
+	b := make([]byte, 0, length)
+	buf := make([]byte, length)
+
+	for len(b) < length {
+		if _, err := crand.Read(buf); err != nil {
+			panic("crypto/rand failed")
+		}
+
+		for _, r := range buf {
+			if int(r) < limit && len(b) < length {
+				b = append(b, charset[int(r)%len(charset)])
+			}
+		}
 	}
 
 	return string(b)
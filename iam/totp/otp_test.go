@@ -9,9 +9,8 @@ import (
 	"encoding/base32"
 	"encoding/base64"
 	"testing"
-	"time"
 
-	"github.com/pquerna/otp/totp"
+	"github.com/kopexa-grc/common/iam/tokens"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -109,13 +108,22 @@ func TestOTP(t *testing.T) {
 		assert.Len(t, decodedSecret, Base32SecretLength)
 
 		// Generate a valid code
-		code, err := totp.GenerateCode(decrypted, time.Now())
+		code, err := tokens.GenerateTOTPCode(decrypted)
 		require.NoError(t, err)
 
 		// Test validation
 		err = otp.ValidateTOTP(context.Background(), user, code)
 		require.NoError(t, err)
 
+		// Authenticators enrolled with the double encoded secret of earlier
+		// QR codes are still accepted
+		legacy := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(decrypted))
+		code, err = tokens.GenerateTOTPCode(legacy)
+		require.NoError(t, err)
+
+		err = otp.ValidateTOTP(context.Background(), user, code)
+		require.NoError(t, err)
+
 		// Test invalid code
 		err = otp.ValidateTOTP(context.Background(), user, "invalid")
 		assert.Error(t, err)
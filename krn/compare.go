@@ -0,0 +1,139 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"slices"
+	"strings"
+)
+
+// idSeparator separates the components of hierarchical resource IDs such as
+// "a.1.10".
+const idSeparator = "."
+
+// Compare returns an integer comparing two KRNs for a stable, deterministic
+// ordering. The result is 0 if a == b, -1 if a < b, and +1 if a > b.
+//
+// KRNs are ordered by service name first and then by resource path, component
+// by component, so that a parent sorts directly before its children. Resource
+// IDs are compared with CompareResourceIDs, e.g. "controls/2" sorts before
+// "controls/10".
+func Compare(a, b KRN) int {
+	if c := strings.Compare(a.ServiceName, b.ServiceName); c != 0 {
+		return c
+	}
+
+	as := strings.Split(a.RelativeResourceName, PathSeparator)
+	bs := strings.Split(b.RelativeResourceName, PathSeparator)
+
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := CompareResourceIDs(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CompareResourceIDs compares two sibling resource IDs. IDs are split at dots
+// and compared part by part: parts consisting only of digits are compared
+// numerically, all other parts lexicographically. This orders "a.1.2" before
+// "a.1.10" and "9" before "10".
+func CompareResourceIDs(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	ap := strings.Split(a, idSeparator)
+	bp := strings.Split(b, idSeparator)
+
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		var c int
+		if isNumeric(ap[i]) && isNumeric(bp[i]) {
+			c = compareNumeric(ap[i], bp[i])
+		} else {
+			c = strings.Compare(ap[i], bp[i])
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(ap) < len(bp):
+		return -1
+	case len(ap) > len(bp):
+		return 1
+	default:
+		// numerically equal IDs such as "01" and "1" still need a stable order
+		return strings.Compare(a, b)
+	}
+}
+
+// isNumeric reports whether s is a non-empty string of ASCII digits.
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareNumeric compares two digit strings numerically without converting
+// them, so arbitrarily long numbers cannot overflow.
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// Less reports whether krn sorts before other; see Compare.
+func (krn KRN) Less(other KRN) bool {
+	return Compare(krn, other) < 0
+}
+
+// List is a slice of KRNs that implements sort.Interface using Compare.
+type List []KRN
+
+// Len implements sort.Interface.
+func (l List) Len() int { return len(l) }
+
+// Less implements sort.Interface.
+func (l List) Less(i, j int) bool { return Compare(l[i], l[j]) < 0 }
+
+// Swap implements sort.Interface.
+func (l List) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+
+// Sort sorts the KRNs in place in the order defined by Compare.
+func Sort(krns []KRN) {
+	slices.SortStableFunc(krns, Compare)
+}
+
+// SortResourceIDs sorts sibling resource IDs in place in the order defined by
+// CompareResourceIDs.
+func SortResourceIDs(ids []string) {
+	slices.SortStableFunc(ids, CompareResourceIDs)
+}
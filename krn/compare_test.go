@@ -0,0 +1,69 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareResourceIDs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "9", b: "10", want: -1},
+		{a: "10", b: "9", want: 1},
+		{a: "a.1.2", b: "a.1.10", want: -1},
+		{a: "a.1", b: "a.1.1", want: -1},
+		{a: "alpha", b: "beta", want: -1},
+		{a: "10", b: "abc", want: -1},
+		{a: "007", b: "7", want: -1},
+		{a: "99999999999999999999999", b: "100000000000000000000000", want: -1},
+		{a: "same", b: "same", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareResourceIDs(tt.a, tt.b))
+			assert.Equal(t, -tt.want, CompareResourceIDs(tt.b, tt.a))
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	parent := MustParse("//kopexa.com/frameworks/iso-27001")
+	child := MustParse("//kopexa.com/frameworks/iso-27001/controls/a.1")
+
+	assert.Equal(t, 0, Compare(parent, parent))
+	assert.Equal(t, -1, Compare(parent, child))
+	assert.Equal(t, 1, Compare(child, parent))
+	assert.Equal(t, -1, Compare(MustParse("//a.com/x/zzzz"), MustParse("//b.com/x/aaaa")))
+	assert.True(t, parent.Less(child))
+}
+
+func TestSort(t *testing.T) {
+	want := []KRN{
+		MustParse("//kopexa.com/controls/a.1.2"),
+		MustParse("//kopexa.com/controls/a.1.10"),
+		MustParse("//kopexa.com/controls/a.1.10/evidence/1"),
+		MustParse("//kopexa.com/controls/a.2"),
+		MustParse("//kopexa.com/risks/2"),
+		MustParse("//kopexa.com/risks/10"),
+	}
+
+	got := []KRN{want[4], want[2], want[5], want[0], want[3], want[1]}
+	Sort(got)
+	assert.Equal(t, want, got)
+
+	got = []KRN{want[5], want[1], want[3], want[0], want[2], want[4]}
+	sort.Sort(List(got))
+	assert.Equal(t, want, got)
+
+	ids := []string{"10", "b", "2", "a", "1"}
+	SortResourceIDs(ids)
+	assert.Equal(t, []string{"1", "2", "10", "a", "b"}, ids)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidURN is returned by FromURN for values that are not Kopexa URNs.
+	ErrInvalidURN = errors.New("invalid KRN URN")
+	// ErrInvalidResourceName is returned by FromResourceName for malformed
+	// resource names.
+	ErrInvalidResourceName = errors.New("invalid resource name")
+	// ErrServiceMismatch is returned by FromResourceName if a full resource
+	// name belongs to another service than requested.
+	ErrServiceMismatch = errors.New("resource name belongs to another service")
+)
+
+const (
+	// URNScheme is the scheme of URNs as defined in RFC 8141.
+	URNScheme = "urn"
+	// URNNamespace is the namespace identifier (NID) of Kopexa URNs.
+	URNNamespace = "kopexa"
+	// URNSeparator separates the components of a URN.
+	URNSeparator = ":"
+
+	// urnPrefix is the prefix of every Kopexa URN.
+	urnPrefix = URNScheme + URNSeparator + URNNamespace + URNSeparator
+)
+
+// urnEscaper escapes the characters with a meaning in URN segments. "%" is
+// escaped first, so that escaped segments can be unescaped unambiguously.
+var urnEscaper = strings.NewReplacer("%", "%25", ":", "%3A")
+
+// urnUnescaper reverses urnEscaper. It accepts lower case escapes as well.
+var urnUnescaper = strings.NewReplacer("%3A", ":", "%3a", ":", "%25", "%")
+
+// ToURN converts the KRN into a Kopexa URN. The mapping is:
+//
+//	//<service>/<seg1>/<seg2>/...  ->  urn:kopexa:<service>:<seg1>:<seg2>:...
+//
+// Colons and percent signs within segments are percent-encoded as "%3A" and
+// "%25"; all other characters are kept. For example
+// "//kopexa.com/frameworks/iso-27001" becomes
+// "urn:kopexa:kopexa.com:frameworks:iso-27001".
+func (krn KRN) ToURN() string {
+	segments := strings.Split(krn.RelativeResourceName, PathSeparator)
+
+	var b strings.Builder
+
+	b.WriteString(urnPrefix)
+	b.WriteString(urnEscaper.Replace(krn.ServiceName))
+
+	for _, s := range segments {
+		b.WriteString(URNSeparator)
+		b.WriteString(urnEscaper.Replace(s))
+	}
+
+	return b.String()
+}
+
+// FromURN parses a Kopexa URN created by ToURN. Scheme and namespace are
+// matched case-insensitively as required by RFC 8141; the remaining
+// components are case-sensitive. The URN must contain the service name and
+// at least one path segment, and no segment may be empty or contain a "/".
+func FromURN(urn string) (KRN, error) {
+	if len(urn) < len(urnPrefix) || !strings.EqualFold(urn[:len(urnPrefix)], urnPrefix) {
+		return KRN{}, fmt.Errorf("%w: must start with %q: %q", ErrInvalidURN, urnPrefix, urn)
+	}
+
+	parts := strings.Split(urn[len(urnPrefix):], URNSeparator)
+	if len(parts) < MinPathComponents {
+		return KRN{}, fmt.Errorf("%w: missing service name or resource path: %q", ErrInvalidURN, urn)
+	}
+
+	for i, p := range parts {
+		p = urnUnescaper.Replace(p)
+		if p == "" || strings.Contains(p, PathSeparator) {
+			return KRN{}, fmt.Errorf("%w: invalid component %d: %q", ErrInvalidURN, i, urn)
+		}
+
+		parts[i] = p
+	}
+
+	return KRN{
+		ServiceName:          parts[0],
+		RelativeResourceName: strings.Join(parts[1:], PathSeparator),
+	}, nil
+}
+
+// ToResourceName returns the KRN as relative resource name in the format of
+// Google APIs, e.g. "frameworks/iso-27001" for
+// "//kopexa.com/frameworks/iso-27001". The service name is dropped; it is
+// implied by the API the name is used with. String returns the full resource
+// name, which is the same as the Google format.
+func (krn KRN) ToResourceName() string {
+	return krn.RelativeResourceName
+}
+
+// FromResourceName converts a Google style resource name of the given
+// service into a KRN. Both relative names ("frameworks/iso-27001") and full
+// names ("//kopexa.com/frameworks/iso-27001") are accepted. For full names
+// the service must match serviceName unless serviceName is empty;
+// otherwise ErrServiceMismatch is returned. Leading or trailing slashes and
+// empty segments are rejected.
+func FromResourceName(serviceName, name string) (KRN, error) {
+	if strings.HasPrefix(name, "//") {
+		parsed, err := Parse(name)
+		if err != nil {
+			return KRN{}, fmt.Errorf("%w: %w", ErrInvalidResourceName, err)
+		}
+
+		if serviceName != "" && parsed.ServiceName != serviceName {
+			return KRN{}, fmt.Errorf("%w: %q is not a resource of %q", ErrServiceMismatch, name, serviceName)
+		}
+
+		serviceName = parsed.ServiceName
+		name = parsed.RelativeResourceName
+	}
+
+	if serviceName == "" {
+		return KRN{}, fmt.Errorf("%w: missing service name for %q", ErrInvalidResourceName, name)
+	}
+
+	for _, s := range strings.Split(name, PathSeparator) {
+		if s == "" {
+			return KRN{}, fmt.Errorf("%w: empty segment in %q", ErrInvalidResourceName, name)
+		}
+	}
+
+	return KRN{ServiceName: serviceName, RelativeResourceName: name}, nil
+}
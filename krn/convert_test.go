@@ -0,0 +1,93 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURN_RoundTrip(t *testing.T) {
+	tests := []struct {
+		krn string
+		urn string
+	}{
+		{krn: "//kopexa.com/frameworks/iso-27001", urn: "urn:kopexa:kopexa.com:frameworks:iso-27001"},
+		{krn: "//kopexa.com/spaces/space-1/controls/a.5.1", urn: "urn:kopexa:kopexa.com:spaces:space-1:controls:a.5.1"},
+		{krn: "//kopexa.com/frameworks", urn: "urn:kopexa:kopexa.com:frameworks"},
+		{krn: "//kopexa.com/notes/a:b%c", urn: "urn:kopexa:kopexa.com:notes:a%3Ab%25c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.krn, func(t *testing.T) {
+			k := MustParse(tt.krn)
+			assert.Equal(t, tt.urn, k.ToURN())
+
+			back, err := FromURN(tt.urn)
+			require.NoError(t, err)
+			assert.Equal(t, k, back)
+			assert.Equal(t, tt.krn, back.String())
+		})
+	}
+}
+
+func TestFromURN(t *testing.T) {
+	k, err := FromURN("URN:Kopexa:kopexa.com:frameworks:ISO-27001")
+	require.NoError(t, err)
+	assert.Equal(t, "//kopexa.com/frameworks/ISO-27001", k.String())
+
+	k, err = FromURN("urn:kopexa:kopexa.com:notes:a%3ab")
+	require.NoError(t, err)
+	assert.Equal(t, "notes/a:b", k.RelativeResourceName)
+
+	for _, invalid := range []string{
+		"",
+		"urn:other:kopexa.com:frameworks",
+		"kopexa.com:frameworks",
+		"urn:kopexa:kopexa.com",
+		"urn:kopexa:kopexa.com::iso",
+		"urn:kopexa:kopexa.com:frameworks/iso",
+	} {
+		_, err := FromURN(invalid)
+		assert.ErrorIs(t, err, ErrInvalidURN, invalid)
+	}
+}
+
+func TestResourceName_RoundTrip(t *testing.T) {
+	k := MustParse("//kopexa.com/spaces/space-1/controls/a.5.1")
+
+	name := k.ToResourceName()
+	assert.Equal(t, "spaces/space-1/controls/a.5.1", name)
+
+	back, err := FromResourceName("kopexa.com", name)
+	require.NoError(t, err)
+	assert.Equal(t, k, back)
+
+	back, err = FromResourceName("", k.String())
+	require.NoError(t, err)
+	assert.Equal(t, k, back)
+}
+
+func TestFromResourceName(t *testing.T) {
+	k, err := FromResourceName("kopexa.com", "//kopexa.com/frameworks/iso-27001")
+	require.NoError(t, err)
+	assert.Equal(t, "frameworks/iso-27001", k.RelativeResourceName)
+
+	_, err = FromResourceName("kopexa.com", "//other.com/frameworks/iso-27001")
+	assert.ErrorIs(t, err, ErrServiceMismatch)
+
+	for _, tt := range []struct{ service, name string }{
+		{service: "", name: "frameworks/iso-27001"},
+		{service: "kopexa.com", name: ""},
+		{service: "kopexa.com", name: "/frameworks/iso-27001"},
+		{service: "kopexa.com", name: "frameworks/iso-27001/"},
+		{service: "kopexa.com", name: "frameworks//iso-27001"},
+		{service: "kopexa.com", name: "//kopexa.com"},
+	} {
+		_, err := FromResourceName(tt.service, tt.name)
+		assert.ErrorIs(t, err, ErrInvalidResourceName, tt.name)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Hash is a 64-bit xxHash of the canonical KRN string, for indexing KRN
+// columns in large tables. It is stored as a signed BIGINT.
+//
+// A hash index column is smaller and faster to compare than the full KRN,
+// but different KRNs can collide. Queries must therefore filter on both
+// columns, so the index narrows the rows and the KRN picks the exact match:
+//
+//	CREATE INDEX controls_krn_hash_idx ON controls (krn_hash);
+//
+//	db.QueryContext(ctx,
+//		"SELECT ... FROM controls WHERE krn_hash = $1 AND krn = $2",
+//		k.HashKey(), k)
+//
+// The hash is computed from the canonical string; the same KRN yields the
+// same hash across processes and releases.
+type Hash int64
+
+// Hash128 is a 128-bit hash of the canonical KRN string (the first 16 bytes
+// of its SHA-256). Collisions are negligible, so it can back a unique index.
+// It is stored as BYTEA, or as UUID via its String form.
+type Hash128 [16]byte
+
+// HashKey returns the 64-bit index hash of the KRN.
+func (krn KRN) HashKey() Hash {
+	return Hash(xxhash.Sum64String(krn.String())) //nolint:gosec // reinterpreted as BIGINT
+}
+
+// HashKey128 returns the 128-bit index hash of the KRN.
+func (krn KRN) HashKey128() Hash128 {
+	sum := sha256.Sum256([]byte(krn.String()))
+
+	var h Hash128
+
+	copy(h[:], sum[:len(h)])
+
+	return h
+}
+
+// HashString parses s and returns its 64-bit index hash. Use it to compute
+// query parameters from KRN strings; legacy KRNs without the leading "//"
+// are accepted like in Scan.
+func HashString(s string) (Hash, error) {
+	var k KRN
+	if err := k.Scan(s); err != nil {
+		return 0, err
+	}
+
+	return k.HashKey(), nil
+}
+
+// Value implements the driver.Valuer interface for database integration.
+func (h Hash) Value() (driver.Value, error) {
+	return int64(h), nil
+}
+
+// Scan implements the sql.Scanner interface for database integration.
+func (h *Hash) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*h = 0
+	case int64:
+		*h = Hash(v)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedType, value)
+	}
+
+	return nil
+}
+
+// String returns the hex representation of the hash.
+func (h Hash128) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// Value implements the driver.Valuer interface for database integration.
+func (h Hash128) Value() (driver.Value, error) {
+	return h[:], nil
+}
+
+// Scan implements the sql.Scanner interface for database integration. It
+// accepts the raw bytes as well as the hex form returned by String.
+func (h *Hash128) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*h = Hash128{}
+	case []byte:
+		if len(v) != len(h) {
+			return fmt.Errorf("%w: hash must be %d bytes, got %d", ErrUnsupportedType, len(h), len(v))
+		}
+
+		copy(h[:], v)
+	case string:
+		b, err := hex.DecodeString(v)
+		if err != nil || len(b) != len(h) {
+			return fmt.Errorf("%w: invalid hash %q", ErrUnsupportedType, v)
+		}
+
+		copy(h[:], b)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedType, value)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashKey(t *testing.T) {
+	k := MustParse("//kopexa.com/frameworks/iso-27001-2022")
+
+	// The hashes are persisted in index columns and must never change.
+	assert.Equal(t, Hash(2061772309056269687), k.HashKey())
+	assert.Equal(t, "56e3508c39128870f62b269fa25a471a", k.HashKey128().String())
+
+	other := MustParse("//kopexa.com/frameworks/iso-27001-2013")
+	assert.NotEqual(t, k.HashKey(), other.HashKey())
+	assert.NotEqual(t, k.HashKey128(), other.HashKey128())
+
+	h, err := HashString("kopexa.com/frameworks/iso-27001-2022")
+	require.NoError(t, err)
+	assert.Equal(t, k.HashKey(), h)
+
+	_, err = HashString("//kopexa.com")
+	require.Error(t, err)
+}
+
+func TestHash_ScanValue(t *testing.T) {
+	h := MustParse("//kopexa.com/spaces/acme").HashKey()
+
+	v, err := h.Value()
+	require.NoError(t, err)
+	assert.IsType(t, int64(0), v)
+
+	var scanned Hash
+	require.NoError(t, scanned.Scan(v))
+	assert.Equal(t, h, scanned)
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Zero(t, scanned)
+
+	require.ErrorIs(t, scanned.Scan("123"), ErrUnsupportedType)
+}
+
+func TestHash128_ScanValue(t *testing.T) {
+	h := MustParse("//kopexa.com/spaces/acme").HashKey128()
+
+	v, err := h.Value()
+	require.NoError(t, err)
+
+	var scanned Hash128
+	require.NoError(t, scanned.Scan(v))
+	assert.Equal(t, h, scanned)
+
+	scanned = Hash128{}
+	require.NoError(t, scanned.Scan(h.String()))
+	assert.Equal(t, h, scanned)
+
+	require.ErrorIs(t, scanned.Scan([]byte{1, 2}), ErrUnsupportedType)
+	require.ErrorIs(t, scanned.Scan("zz"), ErrUnsupportedType)
+	require.ErrorIs(t, scanned.Scan(42), ErrUnsupportedType)
+}
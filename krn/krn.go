@@ -249,7 +249,8 @@ func (krn KRN) MarshalJSON() ([]byte, error) {
 	return json.Marshal(krn.String())
 }
 
-// UnmarshalJSON implements the json.Unmarshaler interface.
+// UnmarshalJSON implements the json.Unmarshaler interface. In strict mode
+// the parsed KRN is checked with Validate; see SetStrict.
 func (krn *KRN) UnmarshalJSON(data []byte) error {
 	var krnStr string
 	if err := json.Unmarshal(data, &krnStr); err != nil {
@@ -261,12 +262,17 @@ func (krn *KRN) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("invalid KRN format: %w", err)
 	}
 
+	if err := validateIfStrict(parsed); err != nil {
+		return err
+	}
+
 	*krn = parsed
 
 	return nil
 }
 
-// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// UnmarshalYAML implements the yaml.Unmarshaler interface. In strict mode
+// the parsed KRN is checked with Validate; see SetStrict.
 func (krn *KRN) UnmarshalYAML(data []byte) error {
 	var krnStr string
 	if err := yaml.Unmarshal(data, &krnStr); err != nil {
@@ -278,6 +284,10 @@ func (krn *KRN) UnmarshalYAML(data []byte) error {
 		return fmt.Errorf("invalid KRN format in YAML: %w", err)
 	}
 
+	if err := validateIfStrict(parsed); err != nil {
+		return err
+	}
+
 	*krn = parsed
 
 	return nil
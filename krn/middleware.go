@@ -0,0 +1,186 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	kerr "github.com/kopexa-grc/common/errors"
+	"github.com/rs/zerolog"
+)
+
+// ErrMissingPathParam is returned by ResolveRequest if the request has no
+// value for the path parameter.
+var ErrMissingPathParam = errors.New("missing KRN path parameter")
+
+// resolvedKey is the context key of a KRN resolved from a path parameter.
+type resolvedKey string
+
+// WithResolved returns a context carrying k as the KRN of the path parameter
+// param.
+func WithResolved(ctx context.Context, param string, k KRN) context.Context {
+	return context.WithValue(ctx, resolvedKey(param), k)
+}
+
+// FromContext returns the KRN resolved from the path parameter param.
+func FromContext(ctx context.Context, param string) (KRN, bool) {
+	k, ok := ctx.Value(resolvedKey(param)).(KRN)
+	return k, ok
+}
+
+// MustFromContext is like FromContext but panics if no KRN was resolved. It
+// is meant for handlers mounted behind ResolveKRN.
+func MustFromContext(ctx context.Context, param string) KRN {
+	k, ok := FromContext(ctx, param)
+	if !ok {
+		panic(fmt.Sprintf("krn: no KRN resolved for path parameter %q", param))
+	}
+
+	return k
+}
+
+// ResourceIDFromContext returns the resource ID of collectionID within the
+// KRN resolved from the path parameter param.
+//
+// Example:
+//
+//	controlID, ok := krn.ResourceIDFromContext(r.Context(), "control", "controls")
+func ResourceIDFromContext(ctx context.Context, param, collectionID string) (string, bool) {
+	k, ok := FromContext(ctx, param)
+	if !ok {
+		return "", false
+	}
+
+	id, err := k.ResourceID(collectionID)
+
+	return id, err == nil
+}
+
+// ResolveOption configures ResolveKRN and ResolveRequest.
+type ResolveOption func(*resolveConfig)
+
+type resolveConfig struct {
+	collections  []string
+	errorHandler func(http.ResponseWriter, *http.Request, error)
+}
+
+// WithCollections restricts the accepted KRNs to the given collection
+// paths, e.g. "frameworks/controls" for a control endpoint.
+func WithCollections(collectionPaths ...string) ResolveOption {
+	return func(c *resolveConfig) {
+		c.collections = collectionPaths
+	}
+}
+
+// WithResolveErrorHandler sets the handler for requests whose KRN cannot be
+// resolved. The default writes a 400 response with errors.WriteHTTP.
+func WithResolveErrorHandler(h func(http.ResponseWriter, *http.Request, error)) ResolveOption {
+	return func(c *resolveConfig) {
+		c.errorHandler = h
+	}
+}
+
+// ResolveRequest extracts the path parameter param of r and decodes it
+// into a KRN. The value may be encoded with EncodeURLSafe, be a URN as
+// returned by ToURN, or a canonical KRN with escaped slashes. The KRN is
+// validated against registry.
+//
+// Path parameters are read with r.PathValue, which is set by
+// net/http.ServeMux and by chi.
+func ResolveRequest(r *http.Request, param string, registry *Registry, opts ...ResolveOption) (KRN, error) {
+	cfg := newResolveConfig(opts)
+	return resolve(r, param, registry, cfg)
+}
+
+// ResolveKRN returns a middleware that resolves the path parameter param
+// with ResolveRequest and stores the KRN in the request context, where
+// handlers read it with FromContext. Requests with an invalid KRN are
+// rejected with 400.
+//
+// Example:
+//
+//	reg := krn.NewRegistry().MustRegister("kopexa.com", "frameworks")
+//
+//	mux.Handle("GET /frameworks/{framework}",
+//	    krn.ResolveKRN("framework", reg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        framework := krn.MustFromContext(r.Context(), "framework")
+//	        ...
+//	    })))
+func ResolveKRN(param string, registry *Registry, opts ...ResolveOption) func(http.Handler) http.Handler {
+	cfg := newResolveConfig(opts)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k, err := resolve(r, param, registry, cfg)
+			if err != nil {
+				zerolog.Ctx(r.Context()).Debug().Err(err).Str("param", param).Msg("failed to resolve KRN")
+
+				cfg.errorHandler(w, r, err)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithResolved(r.Context(), param, k)))
+		})
+	}
+}
+
+func newResolveConfig(opts []ResolveOption) *resolveConfig {
+	cfg := &resolveConfig{errorHandler: writeResolveError}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// resolve decodes and validates the KRN of the path parameter param.
+func resolve(r *http.Request, param string, registry *Registry, cfg *resolveConfig) (KRN, error) {
+	value := r.PathValue(param)
+	if value == "" {
+		return KRN{}, fmt.Errorf("%w: %s", ErrMissingPathParam, param)
+	}
+
+	k, err := decodePathValue(value)
+	if err != nil {
+		return KRN{}, err
+	}
+
+	if err := registry.Validate(k); err != nil {
+		return KRN{}, err
+	}
+
+	if len(cfg.collections) > 0 && !slices.Contains(cfg.collections, k.CollectionPath()) {
+		return KRN{}, fmt.Errorf("%w: %s is not accepted here", ErrUnknownCollection, k.CollectionPath())
+	}
+
+	return k, nil
+}
+
+// decodePathValue parses a KRN in any of the forms accepted by ResolveRequest.
+func decodePathValue(value string) (KRN, error) {
+	switch {
+	case strings.HasPrefix(value, "//"):
+		return Parse(value)
+	case len(value) >= len(urnPrefix) && strings.EqualFold(value[:len(urnPrefix)], urnPrefix):
+		return FromURN(value)
+	default:
+		return DecodeURLSafe(value)
+	}
+}
+
+// writeResolveError rejects the request without echoing the input.
+func writeResolveError(w http.ResponseWriter, _ *http.Request, err error) {
+	msg := "invalid resource name"
+	if errors.Is(err, ErrMissingPathParam) {
+		msg = "missing resource name"
+	}
+
+	kerr.WriteHTTP(w, kerr.NewInvalidArgument(msg))
+}
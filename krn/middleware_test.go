@@ -0,0 +1,98 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveKRN(t *testing.T) {
+	reg := NewRegistry().MustRegister("kopexa.com", "frameworks", "frameworks/controls")
+	control := MustParse("//kopexa.com/frameworks/iso-27001/controls/a.5.1")
+
+	var resolved KRN
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /controls/{control}", ResolveKRN("control", reg, WithCollections("frameworks/controls"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resolved = MustFromContext(r.Context(), "control")
+
+			id, ok := ResourceIDFromContext(r.Context(), "control", "controls")
+			assert.True(t, ok)
+			assert.Equal(t, "a.5.1", id)
+
+			w.WriteHeader(http.StatusNoContent)
+		})))
+
+	tests := []struct {
+		name   string
+		value  string
+		status int
+	}{
+		{name: "url safe", value: control.EncodeURLSafe(), status: http.StatusNoContent},
+		{name: "urn", value: control.ToURN(), status: http.StatusNoContent},
+		{name: "escaped canonical", value: url.PathEscape(control.String()), status: http.StatusNoContent},
+		{name: "not encoded", value: "garbage!", status: http.StatusBadRequest},
+		{name: "unregistered", value: MustParse("//kopexa.com/spaces/acme-corp").EncodeURLSafe(), status: http.StatusBadRequest},
+		{name: "wrong collection", value: MustParse("//kopexa.com/frameworks/iso-27001").EncodeURLSafe(), status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved = KRN{}
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/controls/"+tt.value, nil))
+
+			require.Equal(t, tt.status, rec.Code, rec.Body.String())
+
+			if tt.status == http.StatusNoContent {
+				assert.Equal(t, control, resolved)
+			} else {
+				assert.NotContains(t, rec.Body.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestResolveRequest(t *testing.T) {
+	reg := NewRegistry().MustRegister("kopexa.com", "frameworks")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_, err := ResolveRequest(r, "framework", reg)
+	require.ErrorIs(t, err, ErrMissingPathParam)
+
+	r.SetPathValue("framework", MustParse("//kopexa.com/frameworks/iso-27001").EncodeURLSafe())
+
+	k, err := ResolveRequest(r, "framework", reg)
+	require.NoError(t, err)
+	assert.Equal(t, "iso-27001", k.Basename())
+
+	_, ok := FromContext(r.Context(), "framework")
+	assert.False(t, ok)
+	assert.Panics(t, func() { MustFromContext(r.Context(), "framework") })
+}
+
+func TestResolveKRN_ErrorHandler(t *testing.T) {
+	var got error
+
+	h := ResolveKRN("id", NewRegistry(), WithResolveErrorHandler(func(w http.ResponseWriter, _ *http.Request, err error) {
+		got = err
+
+		w.WriteHeader(http.StatusNotFound)
+	}))(http.NotFoundHandler())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.ErrorIs(t, got, ErrMissingPathParam)
+}
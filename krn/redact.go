@@ -0,0 +1,80 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// RedactedPrefix marks a redacted resource ID segment.
+	RedactedPrefix = "#"
+	// RedactedIDLength is the number of hex characters kept from the hash of a
+	// redacted resource ID.
+	RedactedIDLength = 8
+)
+
+// Redact returns the canonical string representation of the KRN with all
+// resource ID segments replaced by a short hash. Collection names and the
+// service name are preserved, so the shape of the resource stays readable
+// while tenant identifiers do not end up in logs verbatim.
+//
+// The same ID always yields the same hash, which keeps log lines correlatable.
+//
+// Example:
+//
+//	krn.MustParse("//kopexa.com/spaces/acme/documents/q3-report").Redact()
+//	// -> "//kopexa.com/spaces/#822b33ad/documents/#9105b6ce"
+func (krn KRN) Redact() string {
+	return krn.redact(nil)
+}
+
+// RedactWithKey is like Redact but hashes the IDs with HMAC-SHA256 using key.
+// Use it when IDs are guessable and the hashes must not be reversible by
+// brute force.
+func (krn KRN) RedactWithKey(key []byte) string {
+	return krn.redact(key)
+}
+
+// MarshalZerologObject implements the zerolog.LogObjectMarshaler interface.
+// The KRN is logged in its redacted form.
+func (krn KRN) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("krn", krn.Redact())
+}
+
+// redact replaces every resource ID segment (every second path component)
+// with its hash.
+func (krn KRN) redact(key []byte) string {
+	if krn.RelativeResourceName == "" {
+		return "//" + krn.ServiceName + "/"
+	}
+
+	parts := strings.Split(krn.RelativeResourceName, PathSeparator)
+	for i := 1; i < len(parts); i += 2 {
+		parts[i] = RedactedPrefix + hashID(parts[i], key)
+	}
+
+	return "//" + krn.ServiceName + "/" + strings.Join(parts, PathSeparator)
+}
+
+// hashID returns the truncated hex hash of id, keyed if key is non-empty.
+func hashID(id string, key []byte) string {
+	var sum []byte
+
+	if len(key) > 0 {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(id))
+		sum = mac.Sum(nil)
+	} else {
+		h := sha256.Sum256([]byte(id))
+		sum = h[:]
+	}
+
+	return hex.EncodeToString(sum)[:RedactedIDLength]
+}
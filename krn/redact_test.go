@@ -0,0 +1,61 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	k := MustParse("//kopexa.com/spaces/acme-corp/documents/q3-report")
+
+	redacted := k.Redact()
+	assert.Regexp(t, regexp.MustCompile(`^//kopexa\.com/spaces/#[0-9a-f]{8}/documents/#[0-9a-f]{8}$`), redacted)
+	assert.NotContains(t, redacted, "acme-corp")
+	assert.NotContains(t, redacted, "q3-report")
+
+	// deterministic
+	assert.Equal(t, redacted, k.Redact())
+
+	// same ID yields the same hash across KRNs
+	other := MustParse("//kopexa.com/spaces/acme-corp")
+	assert.True(t, strings.HasPrefix(redacted, other.Redact()))
+}
+
+func TestRedact_TrailingCollection(t *testing.T) {
+	k := MustParse("//kopexa.com/spaces/acme-corp/documents")
+
+	redacted := k.Redact()
+	assert.True(t, strings.HasSuffix(redacted, "/documents"))
+	assert.NotContains(t, redacted, "acme-corp")
+}
+
+func TestRedactWithKey(t *testing.T) {
+	k := MustParse("//kopexa.com/spaces/acme-corp")
+
+	a := k.RedactWithKey([]byte("secret-a"))
+	b := k.RedactWithKey([]byte("secret-b"))
+
+	assert.NotEqual(t, a, b)
+	assert.NotEqual(t, k.Redact(), a)
+	assert.NotContains(t, a, "acme-corp")
+}
+
+func TestMarshalZerologObject(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := zerolog.New(&buf)
+	k := MustParse("//kopexa.com/spaces/acme-corp")
+
+	logger.Info().Object("resource", k).Msg("test")
+
+	assert.Contains(t, buf.String(), `"resource":{"krn":"//kopexa.com/spaces/#`)
+	assert.NotContains(t, buf.String(), "acme-corp")
+}
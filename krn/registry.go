@@ -0,0 +1,209 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownCollection is returned by Registry.Validate for KRNs whose
+	// collection path is not registered for their service.
+	ErrUnknownCollection = errors.New("unknown collection")
+	// ErrMissingResourceID is returned by Registry.Validate for KRNs that
+	// name a collection instead of a resource.
+	ErrMissingResourceID = errors.New("missing resource ID")
+	// ErrInvalidURLEncoding is returned by DecodeURLSafe for values that are
+	// not base64url encoded.
+	ErrInvalidURLEncoding = errors.New("invalid URL-safe KRN encoding")
+	// ErrChildNotAllowed is returned by NewChildKRNStrict if the collection
+	// of the parent does not permit the requested child collection.
+	ErrChildNotAllowed = errors.New("child collection not allowed")
+)
+
+// CollectionPath returns the collection segments of the resource path
+// joined by "/". Example: for "frameworks/iso-27001/controls/a-5-1",
+// returns "frameworks/controls".
+func (krn KRN) CollectionPath() string {
+	segments := strings.Split(krn.RelativeResourceName, PathSeparator)
+
+	collections := make([]string, 0, (len(segments)+1)/2)
+	for i := 0; i < len(segments); i += 2 {
+		collections = append(collections, segments[i])
+	}
+
+	return strings.Join(collections, PathSeparator)
+}
+
+// EncodeURLSafe returns the KRN as unpadded base64url, so that it can be
+// used as a single path segment.
+func (krn KRN) EncodeURLSafe() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(krn.String()))
+}
+
+// DecodeURLSafe parses a KRN encoded with EncodeURLSafe. Padded input is
+// accepted as well.
+func DecodeURLSafe(s string) (KRN, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return KRN{}, fmt.Errorf("%w: %v", ErrInvalidURLEncoding, err)
+	}
+
+	return Parse(string(data))
+}
+
+// Registry holds the collection paths known per service, e.g.
+// "frameworks" and "frameworks/controls" for "kopexa.com". It is safe for
+// concurrent use.
+type Registry struct {
+	mu          sync.RWMutex
+	collections map[string]map[string]struct{}
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{collections: make(map[string]map[string]struct{})}
+}
+
+// Register adds collection paths for serviceName. Every collection in a
+// path must be a lowercase identifier as required by Validate.
+//
+// Example:
+//
+//	reg := krn.NewRegistry()
+//	err := reg.Register("kopexa.com", "frameworks", "frameworks/controls")
+func (r *Registry) Register(serviceName string, collectionPaths ...string) error {
+	if !reServiceName.MatchString(serviceName) {
+		return fmt.Errorf("%w: %s", ErrInvalidServiceName, serviceName)
+	}
+
+	for _, p := range collectionPaths {
+		for _, c := range strings.Split(p, PathSeparator) {
+			if !reCollectionName.MatchString(c) {
+				return fmt.Errorf("%w: %s", ErrInvalidCollectionName, p)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	paths, ok := r.collections[serviceName]
+	if !ok {
+		paths = make(map[string]struct{})
+		r.collections[serviceName] = paths
+	}
+
+	for _, p := range collectionPaths {
+		paths[p] = struct{}{}
+	}
+
+	return nil
+}
+
+// MustRegister is like Register but panics on invalid input. It is meant
+// for registrations at program start.
+func (r *Registry) MustRegister(serviceName string, collectionPaths ...string) *Registry {
+	if err := r.Register(serviceName, collectionPaths...); err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Hierarchy maps a parent collection path to the child collections it
+// permits. The empty path "" lists the top-level collections.
+//
+// Example:
+//
+//	krn.Hierarchy{
+//		"":                    {"frameworks", "spaces"},
+//		"frameworks":          {"controls"},
+//		"spaces":              {"risks", "assets"},
+//		"frameworks/controls": {"requirements"},
+//	}
+type Hierarchy map[string][]string
+
+// RegisterHierarchy registers the collection paths defined by hierarchy for
+// serviceName, e.g. "frameworks/controls" for "frameworks": {"controls"}.
+// The parent paths are registered as well.
+func (r *Registry) RegisterHierarchy(serviceName string, hierarchy Hierarchy) error {
+	var paths []string
+
+	for parent, children := range hierarchy {
+		if parent != "" {
+			paths = append(paths, parent)
+		}
+
+		for _, child := range children {
+			paths = append(paths, path.Join(parent, child))
+		}
+	}
+
+	return r.Register(serviceName, paths...)
+}
+
+// Has reports whether collectionPath is registered for serviceName.
+func (r *Registry) Has(serviceName, collectionPath string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.collections[serviceName][collectionPath]
+
+	return ok
+}
+
+// Validate checks k with Validate and verifies that it names a resource of
+// a registered collection. Returns an error wrapping ErrMissingResourceID or
+// ErrUnknownCollection, or the *ValidationError of Validate.
+func (r *Registry) Validate(k KRN) error {
+	if err := Validate(k); err != nil {
+		return err
+	}
+
+	if strings.Count(k.RelativeResourceName, PathSeparator)%2 == 0 {
+		return fmt.Errorf("%w: %s", ErrMissingResourceID, k.String())
+	}
+
+	if !r.Has(k.ServiceName, k.CollectionPath()) {
+		return fmt.Errorf("%w: %s in %s", ErrUnknownCollection, k.CollectionPath(), k.ServiceName)
+	}
+
+	return nil
+}
+
+// NewChildKRNStrict is like NewChildKRN but only attaches resource to the
+// owner if the registry permits it: the owner must name a resource of a
+// registered collection, and the collection path of the owner extended by
+// resource must be registered as well. Register the permitted paths with
+// Register or RegisterHierarchy.
+//
+// Returns an error wrapping ErrChildNotAllowed for collections the owner
+// does not permit, or the error of NewChildKRN or Registry.Validate.
+//
+// Example:
+//
+//	reg := krn.NewRegistry().MustRegister("kopexa.com", "frameworks", "frameworks/controls")
+//	control, err := krn.NewChildKRNStrict(reg, "//kopexa.com/frameworks/iso-27001", "controls", "a.5.1")
+func NewChildKRNStrict(registry *Registry, ownerKRN string, resource string, resourceID string) (*KRN, error) {
+	owner, err := Parse(ownerKRN)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKRNFormat, err)
+	}
+
+	if err := registry.Validate(owner); err != nil {
+		return nil, err
+	}
+
+	if !registry.Has(owner.ServiceName, path.Join(owner.CollectionPath(), resource)) {
+		return nil, fmt.Errorf("%w: %s under %s", ErrChildNotAllowed, resource, owner.CollectionPath())
+	}
+
+	return NewChildKRN(ownerKRN, resource, resourceID)
+}
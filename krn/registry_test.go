@@ -0,0 +1,100 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKRN_CollectionPath(t *testing.T) {
+	assert.Equal(t, "frameworks", MustParse("//kopexa.com/frameworks/iso-27001").CollectionPath())
+	assert.Equal(t, "frameworks/controls", MustParse("//kopexa.com/frameworks/iso-27001/controls/a.5.1").CollectionPath())
+	assert.Equal(t, "frameworks/controls", MustParse("//kopexa.com/frameworks/iso-27001/controls").CollectionPath())
+}
+
+func TestURLSafeEncoding(t *testing.T) {
+	k := MustParse("//kopexa.com/frameworks/iso-27001/controls/a.5.1")
+
+	encoded := k.EncodeURLSafe()
+	assert.NotContains(t, encoded, "/")
+	assert.NotContains(t, encoded, "=")
+
+	decoded, err := DecodeURLSafe(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, k, decoded)
+
+	padded := base64.URLEncoding.EncodeToString([]byte(k.String()))
+	decoded, err = DecodeURLSafe(padded)
+	require.NoError(t, err)
+	assert.Equal(t, k, decoded)
+
+	_, err = DecodeURLSafe("not base64!")
+	assert.ErrorIs(t, err, ErrInvalidURLEncoding)
+
+	_, err = DecodeURLSafe(base64.RawURLEncoding.EncodeToString([]byte("kopexa.com/x")))
+	assert.ErrorIs(t, err, ErrMustStartWithDoubleSlash)
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry().MustRegister("kopexa.com", "frameworks", "frameworks/controls")
+
+	assert.True(t, reg.Has("kopexa.com", "frameworks/controls"))
+	assert.False(t, reg.Has("other.com", "frameworks"))
+
+	require.NoError(t, reg.Validate(MustParse("//kopexa.com/frameworks/iso-27001")))
+	require.NoError(t, reg.Validate(MustParse("//kopexa.com/frameworks/iso-27001/controls/a.5.1")))
+
+	err := reg.Validate(MustParse("//kopexa.com/frameworks/iso-27001/controls"))
+	assert.ErrorIs(t, err, ErrMissingResourceID)
+
+	err = reg.Validate(MustParse("//kopexa.com/spaces/acme-corp"))
+	assert.ErrorIs(t, err, ErrUnknownCollection)
+
+	err = reg.Validate(MustParse("//kopexa.com/frameworks/x"))
+	assert.ErrorIs(t, err, ErrInvalidResourceID)
+
+	assert.ErrorIs(t, reg.Register("Kopexa.com", "frameworks"), ErrInvalidServiceName)
+	assert.ErrorIs(t, reg.Register("kopexa.com", "frameworks/Controls"), ErrInvalidCollectionName)
+	assert.Panics(t, func() { reg.MustRegister("kopexa.com", "") })
+}
+
+func TestNewChildKRNStrict(t *testing.T) {
+	reg := NewRegistry()
+	require.NoError(t, reg.RegisterHierarchy("kopexa.com", Hierarchy{
+		"":           {"frameworks", "spaces"},
+		"frameworks": {"controls"},
+		"spaces":     {"risks"},
+	}))
+
+	assert.True(t, reg.Has("kopexa.com", "spaces"))
+	assert.True(t, reg.Has("kopexa.com", "frameworks/controls"))
+
+	child, err := NewChildKRNStrict(reg, "//kopexa.com/frameworks/iso-27001", "controls", "a.5.1")
+	require.NoError(t, err)
+	assert.Equal(t, "//kopexa.com/frameworks/iso-27001/controls/a.5.1", child.String())
+
+	_, err = NewChildKRNStrict(reg, "//kopexa.com/frameworks/iso-27001", "risks", "risk-1")
+	assert.ErrorIs(t, err, ErrChildNotAllowed)
+
+	_, err = NewChildKRNStrict(reg, "//kopexa.com/frameworks/iso-27001/controls/a.5.1", "controls", "a.5.2")
+	assert.ErrorIs(t, err, ErrChildNotAllowed)
+
+	_, err = NewChildKRNStrict(reg, "//kopexa.com/assets/server-1", "risks", "risk-1")
+	assert.ErrorIs(t, err, ErrUnknownCollection)
+
+	_, err = NewChildKRNStrict(reg, "//kopexa.com/spaces", "risks", "risk-1")
+	assert.ErrorIs(t, err, ErrMissingResourceID)
+
+	_, err = NewChildKRNStrict(reg, "//kopexa.com/spaces/acme-corp", "risks", "x")
+	assert.ErrorIs(t, err, ErrInvalidResourceID)
+
+	_, err = NewChildKRNStrict(reg, "kopexa.com/spaces/acme-corp", "risks", "risk-1")
+	assert.ErrorIs(t, err, ErrInvalidKRNFormat)
+
+	assert.ErrorIs(t, reg.RegisterHierarchy("kopexa.com", Hierarchy{"spaces": {"Assets"}}), ErrInvalidCollectionName)
+}
@@ -0,0 +1,196 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/goccy/go-yaml"
+)
+
+var (
+	// ErrInvalidServiceName is returned by Validate for service names that
+	// are not valid host names.
+	ErrInvalidServiceName = errors.New("invalid service name")
+	// ErrInvalidCollectionName is returned by Validate for collection
+	// segments that are not lowercase identifiers.
+	ErrInvalidCollectionName = errors.New("invalid collection name")
+)
+
+// SegmentKind names the role of a segment within a KRN.
+type SegmentKind string
+
+const (
+	// SegmentService is the service name, e.g. "kopexa.com".
+	SegmentService SegmentKind = "service"
+	// SegmentCollection is a collection in the resource path, e.g. "frameworks".
+	SegmentCollection SegmentKind = "collection"
+	// SegmentResourceID is a resource ID in the resource path, e.g. "iso-27001".
+	SegmentResourceID SegmentKind = "resource ID"
+)
+
+// reServiceName matches host names made of dot separated DNS labels.
+var reServiceName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+// reCollectionName matches collection segments such as "frameworks" or
+// "control-objectives".
+var reCollectionName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// SegmentError describes a single invalid segment of a KRN.
+type SegmentError struct {
+	// Index is the position of the segment in the resource path, or -1 for
+	// the service name.
+	Index int
+	// Kind is the role of the segment.
+	Kind SegmentKind
+	// Segment is the offending value.
+	Segment string
+	// Err is ErrInvalidServiceName, ErrInvalidCollectionName or
+	// ErrInvalidResourceID.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *SegmentError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("%s %q: %v", e.Kind, e.Segment, e.Err)
+	}
+
+	return fmt.Sprintf("segment %d (%s) %q: %v", e.Index, e.Kind, e.Segment, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *SegmentError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists all invalid segments of a KRN.
+type ValidationError struct {
+	// KRN is the validated KRN in canonical form.
+	KRN string
+	// Segments holds one error per invalid segment, in path order.
+	Segments []*SegmentError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Segments))
+	for _, s := range e.Segments {
+		msgs = append(msgs, s.Error())
+	}
+
+	return fmt.Sprintf("invalid KRN %s: %s", e.KRN, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the segment errors, so that errors.Is matches their causes.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Segments))
+	for _, s := range e.Segments {
+		errs = append(errs, s)
+	}
+
+	return errs
+}
+
+// Validate checks every segment of the KRN: the service name must be a host
+// name, collections must be lowercase identifiers and resource IDs must
+// satisfy the same rules as in NewChildKRN. A trailing collection without
+// resource ID is allowed. The returned error is a *ValidationError.
+func Validate(k KRN) error {
+	var segs []*SegmentError
+
+	if !reServiceName.MatchString(k.ServiceName) {
+		segs = append(segs, &SegmentError{Index: -1, Kind: SegmentService, Segment: k.ServiceName, Err: ErrInvalidServiceName})
+	}
+
+	for i, seg := range strings.Split(k.RelativeResourceName, PathSeparator) {
+		if i%2 == 0 {
+			if !reCollectionName.MatchString(seg) {
+				segs = append(segs, &SegmentError{Index: i, Kind: SegmentCollection, Segment: seg, Err: ErrInvalidCollectionName})
+			}
+
+			continue
+		}
+
+		if !isValidResourceID(seg) {
+			segs = append(segs, &SegmentError{Index: i, Kind: SegmentResourceID, Segment: seg, Err: ErrInvalidResourceID})
+		}
+	}
+
+	if len(segs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{KRN: k.String(), Segments: segs}
+}
+
+var strict atomic.Bool
+
+// SetStrict enables or disables strict mode. In strict mode KRN values are
+// checked with Validate when they are unmarshaled from JSON or YAML, in
+// addition to being parsed. Strict mode is off by default; use StrictKRN to
+// validate individual fields regardless of the mode.
+func SetStrict(enabled bool) {
+	strict.Store(enabled)
+}
+
+// Strict reports whether strict mode is enabled.
+func Strict() bool {
+	return strict.Load()
+}
+
+// validateIfStrict validates k if strict mode is enabled.
+func validateIfStrict(k KRN) error {
+	if !Strict() {
+		return nil
+	}
+
+	return Validate(k)
+}
+
+// StrictKRN is a KRN that is always checked with Validate when it is
+// unmarshaled from JSON or YAML, e.g. for fields of API requests.
+type StrictKRN struct {
+	KRN
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *StrictKRN) UnmarshalJSON(data []byte) error {
+	var krnStr string
+	if err := json.Unmarshal(data, &krnStr); err != nil {
+		return fmt.Errorf("KRN must be a string: %w", err)
+	}
+
+	return s.set(krnStr)
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (s *StrictKRN) UnmarshalYAML(data []byte) error {
+	var krnStr string
+	if err := yaml.Unmarshal(data, &krnStr); err != nil {
+		return fmt.Errorf("KRN must be a string in YAML: %w", err)
+	}
+
+	return s.set(krnStr)
+}
+
+func (s *StrictKRN) set(krnStr string) error {
+	parsed, err := Parse(krnStr)
+	if err != nil {
+		return fmt.Errorf("invalid KRN format: %w", err)
+	}
+
+	if err := Validate(parsed); err != nil {
+		return err
+	}
+
+	s.KRN = parsed
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package krn
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		krn      string
+		segments []*SegmentError
+	}{
+		{
+			name: "valid",
+			krn:  "//kopexa.com/frameworks/iso-27001-2022/controls/a.1.10",
+		},
+		{
+			name: "trailing collection",
+			krn:  "//kopexa.com/spaces/acme-corp/documents",
+		},
+		{
+			name: "short resource ID",
+			krn:  "//kopexa.com/risks/10",
+			segments: []*SegmentError{
+				{Index: 1, Kind: SegmentResourceID, Segment: "10", Err: ErrInvalidResourceID},
+			},
+		},
+		{
+			name: "multiple invalid segments",
+			krn:  "//Kopexa.com/Frameworks/iso~27001/controls/a",
+			segments: []*SegmentError{
+				{Index: -1, Kind: SegmentService, Segment: "Kopexa.com", Err: ErrInvalidServiceName},
+				{Index: 0, Kind: SegmentCollection, Segment: "Frameworks", Err: ErrInvalidCollectionName},
+				{Index: 1, Kind: SegmentResourceID, Segment: "iso~27001", Err: ErrInvalidResourceID},
+				{Index: 3, Kind: SegmentResourceID, Segment: "a", Err: ErrInvalidResourceID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(MustParse(tt.krn))
+			if tt.segments == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr *ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.segments, verr.Segments)
+
+			for _, seg := range tt.segments {
+				require.ErrorIs(t, err, seg.Err)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	err := Validate(MustParse("//kopexa.com/Frameworks/iso-27001"))
+	assert.EqualError(t, err, `invalid KRN //kopexa.com/Frameworks/iso-27001: segment 0 (collection) "Frameworks": invalid collection name`)
+}
+
+func TestStrictMode(t *testing.T) {
+	t.Cleanup(func() { SetStrict(false) })
+
+	var k KRN
+
+	require.NoError(t, json.Unmarshal([]byte(`"//kopexa.com/Frameworks/x"`), &k))
+	require.NoError(t, yaml.Unmarshal([]byte(`//kopexa.com/Frameworks/x`), &k))
+
+	SetStrict(true)
+	assert.True(t, Strict())
+
+	k = KRN{}
+	err := json.Unmarshal([]byte(`"//kopexa.com/Frameworks/x"`), &k)
+	require.ErrorIs(t, err, ErrInvalidCollectionName)
+	assert.Equal(t, KRN{}, k)
+
+	err = yaml.Unmarshal([]byte(`//kopexa.com/Frameworks/x`), &k)
+	require.ErrorIs(t, err, ErrInvalidCollectionName)
+
+	require.NoError(t, json.Unmarshal([]byte(`"//kopexa.com/frameworks/iso-27001"`), &k))
+	assert.Equal(t, "frameworks/iso-27001", k.RelativeResourceName)
+}
+
+func TestStrictKRN(t *testing.T) {
+	var req struct {
+		Parent StrictKRN `json:"parent" yaml:"parent"`
+	}
+
+	err := json.Unmarshal([]byte(`{"parent":"//kopexa.com/spaces/x"}`), &req)
+	require.ErrorIs(t, err, ErrInvalidResourceID)
+
+	err = yaml.Unmarshal([]byte("parent: //kopexa.com/spaces/x\n"), &req)
+	require.ErrorIs(t, err, ErrInvalidResourceID)
+
+	err = json.Unmarshal([]byte(`{"parent":"not a krn"}`), &req)
+	require.Error(t, err)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"parent":"//kopexa.com/spaces/acme-corp"}`), &req))
+	assert.Equal(t, "//kopexa.com/spaces/acme-corp", req.Parent.String())
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"parent":"//kopexa.com/spaces/acme-corp"}`, string(data))
+}
+
+func TestSegmentErrorUnwrap(t *testing.T) {
+	err := &SegmentError{Index: 1, Kind: SegmentResourceID, Segment: "x", Err: ErrInvalidResourceID}
+	assert.True(t, errors.Is(err, ErrInvalidResourceID))
+	assert.Equal(t, `segment 1 (resource ID) "x": invalid resource ID`, err.Error())
+}
@@ -0,0 +1,320 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Variant identifies one side of an A/B experiment.
+type Variant string
+
+const (
+	// VariantA is the control variant.
+	VariantA Variant = "A"
+	// VariantB is the candidate variant.
+	VariantB Variant = "B"
+)
+
+// ABSplit selects how an ABRouter assigns requests to variants.
+type ABSplit string
+
+const (
+	// ABSplitPercentage assigns every request at random.
+	ABSplitPercentage ABSplit = "percentage"
+	// ABSplitTenant assigns all requests of a tenant to the same variant by
+	// hashing the tenant ID. Requests without a tenant fall back to
+	// ABSplitPercentage.
+	ABSplitTenant ABSplit = "tenant"
+)
+
+// tenantBuckets is the resolution of the tenant hash split.
+const tenantBuckets = 10000
+
+// ABResponse is a generated response tagged with the variant that produced it.
+type ABResponse struct {
+	// ID identifies the response; pass it back in Feedback.
+	ID string
+	// Experiment is the name of the experiment.
+	Experiment string
+	// Variant is the variant that generated the response.
+	Variant Variant
+	// Text is the generated text.
+	Text string
+	// Latency is the time the provider took to respond.
+	Latency time.Duration
+}
+
+// Feedback is a quality rating for a response of an experiment.
+type Feedback struct {
+	// ResponseID is the ABResponse.ID the feedback refers to. Optional.
+	ResponseID string
+	// Variant is the variant that generated the rated response.
+	Variant Variant
+	// Score is the quality rating, e.g. 1 for thumbs up and 0 for thumbs down.
+	Score float64
+	// Comment is an optional free-text comment.
+	Comment string
+}
+
+// ABStats are the aggregated counters of a single variant.
+type ABStats struct {
+	// Requests is the number of requests routed to the variant.
+	Requests int64
+	// Errors is the number of failed requests.
+	Errors int64
+	// TotalLatency is the summed latency of all requests.
+	TotalLatency time.Duration
+	// FeedbackCount is the number of feedback entries received.
+	FeedbackCount int64
+	// FeedbackScore is the sum of all feedback scores.
+	FeedbackScore float64
+}
+
+// AverageScore returns the mean feedback score, or 0 without feedback.
+func (s ABStats) AverageScore() float64 {
+	if s.FeedbackCount == 0 {
+		return 0
+	}
+
+	return s.FeedbackScore / float64(s.FeedbackCount)
+}
+
+// generator is the part of Client used by ABRouter.
+type generator interface {
+	GenerateWithOptions(ctx context.Context, prompt string, options ...llms.CallOption) (string, error)
+}
+
+// ABRouter splits generation requests between two clients to compare models
+// in production. It implements the same Generate method as Client, so it can
+// be used wherever a client is expected, e.g. by the summarizer.
+//
+// ABRouter is safe for concurrent use.
+type ABRouter struct {
+	variants   map[Variant]generator
+	experiment string
+	percentB   float64
+	split      ABSplit
+
+	onResponse func(ctx context.Context, resp *ABResponse, err error)
+	onFeedback func(ctx context.Context, experiment string, fb Feedback)
+
+	mu    sync.Mutex
+	stats map[Variant]*ABStats
+}
+
+// ABOption configures an ABRouter.
+type ABOption func(*ABRouter)
+
+// WithABExperiment sets the experiment name. It is reported with every
+// response and salts the tenant hash, so different experiments assign
+// tenants independently.
+func WithABExperiment(name string) ABOption {
+	return func(r *ABRouter) {
+		r.experiment = name
+	}
+}
+
+// WithABPercentage sets the percentage (0-100) of traffic routed to variant B.
+// Defaults to 50.
+func WithABPercentage(percentB float64) ABOption {
+	return func(r *ABRouter) {
+		r.percentB = percentB
+	}
+}
+
+// WithABSplit sets how requests are assigned to variants.
+// Defaults to ABSplitPercentage.
+func WithABSplit(split ABSplit) ABOption {
+	return func(r *ABRouter) {
+		r.split = split
+	}
+}
+
+// WithABResponseHook sets a hook that is called after every request with the
+// tagged response. err is the generation error, if any.
+func WithABResponseHook(hook func(ctx context.Context, resp *ABResponse, err error)) ABOption {
+	return func(r *ABRouter) {
+		r.onResponse = hook
+	}
+}
+
+// WithABFeedbackHook sets a hook that is called for every feedback recorded
+// with RecordFeedback, e.g. to persist it for offline evaluation.
+func WithABFeedbackHook(hook func(ctx context.Context, experiment string, fb Feedback)) ABOption {
+	return func(r *ABRouter) {
+		r.onFeedback = hook
+	}
+}
+
+// NewABRouter creates an ABRouter that splits traffic between the clients a
+// and b.
+//
+// Example:
+//
+//	router, err := llm.NewABRouter(gpt4, claude,
+//		llm.WithABExperiment("summaries-2025-q3"),
+//		llm.WithABPercentage(10),
+//		llm.WithABSplit(llm.ABSplitTenant),
+//	)
+func NewABRouter(a, b *Client, opts ...ABOption) (*ABRouter, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("%w: both variants are required", ErrInvalidABConfig)
+	}
+
+	r := &ABRouter{
+		variants: map[Variant]generator{VariantA: a, VariantB: b},
+		percentB: 50, //nolint:mnd // even split by default
+		split:    ABSplitPercentage,
+		stats:    map[Variant]*ABStats{VariantA: {}, VariantB: {}},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.percentB < 0 || r.percentB > 100 {
+		return nil, fmt.Errorf("%w: percentage must be between 0 and 100, got %v", ErrInvalidABConfig, r.percentB)
+	}
+
+	if r.split != ABSplitPercentage && r.split != ABSplitTenant {
+		return nil, fmt.Errorf("%w: unsupported split %q", ErrInvalidABConfig, r.split)
+	}
+
+	return r, nil
+}
+
+// NewABRouterFromConfigs creates the clients for both configurations and
+// returns an ABRouter splitting traffic between them.
+func NewABRouterFromConfigs(a, b *Config, opts ...ABOption) (*ABRouter, error) {
+	clientA, err := New(a)
+	if err != nil {
+		return nil, fmt.Errorf("variant A: %w", err)
+	}
+
+	clientB, err := New(b)
+	if err != nil {
+		return nil, fmt.Errorf("variant B: %w", err)
+	}
+
+	return NewABRouter(clientA, clientB, opts...)
+}
+
+// abTenantKey is the context key for the tenant used by ABSplitTenant.
+type abTenantKey struct{}
+
+// WithABTenant returns a context that carries the tenant ID used to assign
+// requests with ABSplitTenant.
+func WithABTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, abTenantKey{}, tenantID)
+}
+
+// Assign returns the variant a request with ctx is routed to. With
+// ABSplitTenant the result is stable for a tenant; otherwise it is random.
+func (r *ABRouter) Assign(ctx context.Context) Variant {
+	if r.split == ABSplitTenant {
+		if tenant, ok := ctx.Value(abTenantKey{}).(string); ok && tenant != "" {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(r.experiment + ":" + tenant))
+
+			if float64(h.Sum32()%tenantBuckets) < r.percentB*tenantBuckets/100 {
+				return VariantB
+			}
+
+			return VariantA
+		}
+	}
+
+	if rand.Float64()*100 < r.percentB { //nolint:gosec // traffic splitting does not need a secure source
+		return VariantB
+	}
+
+	return VariantA
+}
+
+// Generate routes the prompt to a variant and returns the generated text.
+func (r *ABRouter) Generate(ctx context.Context, prompt string) (string, error) {
+	resp, err := r.GenerateWithVariant(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Text, nil
+}
+
+// GenerateWithVariant routes the prompt to a variant and returns the
+// response tagged with the variant and experiment.
+func (r *ABRouter) GenerateWithVariant(ctx context.Context, prompt string, options ...llms.CallOption) (*ABResponse, error) {
+	variant := r.Assign(ctx)
+	start := time.Now()
+
+	text, err := r.variants[variant].GenerateWithOptions(ctx, prompt, options...)
+
+	resp := &ABResponse{
+		ID:         uuid.NewString(),
+		Experiment: r.experiment,
+		Variant:    variant,
+		Text:       text,
+		Latency:    time.Since(start),
+	}
+
+	r.mu.Lock()
+	s := r.stats[variant]
+	s.Requests++
+	s.TotalLatency += resp.Latency
+
+	if err != nil {
+		s.Errors++
+	}
+	r.mu.Unlock()
+
+	if r.onResponse != nil {
+		r.onResponse(ctx, resp, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// RecordFeedback records a quality rating for a variant and forwards it to
+// the feedback hook.
+func (r *ABRouter) RecordFeedback(ctx context.Context, fb Feedback) error {
+	if fb.Variant != VariantA && fb.Variant != VariantB {
+		return fmt.Errorf("%w: unknown variant %q", ErrInvalidABConfig, fb.Variant)
+	}
+
+	r.mu.Lock()
+	s := r.stats[fb.Variant]
+	s.FeedbackCount++
+	s.FeedbackScore += fb.Score
+	r.mu.Unlock()
+
+	if r.onFeedback != nil {
+		r.onFeedback(ctx, r.experiment, fb)
+	}
+
+	return nil
+}
+
+// Stats returns a snapshot of the counters of both variants.
+func (r *ABRouter) Stats() map[Variant]ABStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return map[Variant]ABStats{
+		VariantA: *r.stats[VariantA],
+		VariantB: *r.stats[VariantB],
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// fakeModel is an llms.Model that answers every prompt with a fixed text.
+type fakeModel struct {
+	answer string
+	err    error
+}
+
+func (f *fakeModel) GenerateContent(_ context.Context, _ []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: f.answer}}}, nil
+}
+
+func (f *fakeModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+func newTestABRouter(t *testing.T, opts ...ABOption) *ABRouter {
+	t.Helper()
+
+	r, err := NewABRouter(
+		&Client{llmClient: &fakeModel{answer: "from A"}},
+		&Client{llmClient: &fakeModel{answer: "from B"}},
+		opts...,
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return r
+}
+
+func TestNewABRouter_Invalid(t *testing.T) {
+	client := &Client{llmClient: &fakeModel{}}
+
+	tests := []struct {
+		name string
+		a, b *Client
+		opts []ABOption
+	}{
+		{name: "missing variant", a: client},
+		{name: "negative percentage", a: client, b: client, opts: []ABOption{WithABPercentage(-1)}},
+		{name: "percentage above 100", a: client, b: client, opts: []ABOption{WithABPercentage(101)}},
+		{name: "unknown split", a: client, b: client, opts: []ABOption{WithABSplit("region")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewABRouter(tt.a, tt.b, tt.opts...)
+			if !errors.Is(err, ErrInvalidABConfig) {
+				t.Errorf("Expected ErrInvalidABConfig, got %v", err)
+			}
+		})
+	}
+}
+
+func TestABRouter_PercentageSplit(t *testing.T) {
+	for _, pct := range []float64{0, 100} {
+		r := newTestABRouter(t, WithABPercentage(pct), WithABExperiment("exp"))
+
+		want, text := VariantA, "from A"
+		if pct == 100 {
+			want, text = VariantB, "from B"
+		}
+
+		resp, err := r.GenerateWithVariant(context.Background(), "hello")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if resp.Variant != want || resp.Text != text || resp.Experiment != "exp" || resp.ID == "" {
+			t.Errorf("Unexpected response for %v%%: %+v", pct, resp)
+		}
+	}
+}
+
+func TestABRouter_TenantSplit(t *testing.T) {
+	r := newTestABRouter(t, WithABSplit(ABSplitTenant), WithABExperiment("exp"))
+
+	counts := map[Variant]int{}
+
+	for i := range 200 {
+		ctx := WithABTenant(context.Background(), fmt.Sprintf("tenant-%d", i))
+
+		v := r.Assign(ctx)
+		for range 5 {
+			if got := r.Assign(ctx); got != v {
+				t.Fatalf("Expected stable variant %s for tenant %d, got %s", v, i, got)
+			}
+		}
+
+		counts[v]++
+	}
+
+	if counts[VariantA] == 0 || counts[VariantB] == 0 {
+		t.Errorf("Expected tenants in both variants, got %v", counts)
+	}
+}
+
+func TestABRouter_HooksAndStats(t *testing.T) {
+	var (
+		responses []*ABResponse
+		feedback  []Feedback
+	)
+
+	r := newTestABRouter(t,
+		WithABPercentage(100),
+		WithABExperiment("exp"),
+		WithABResponseHook(func(_ context.Context, resp *ABResponse, _ error) {
+			responses = append(responses, resp)
+		}),
+		WithABFeedbackHook(func(_ context.Context, experiment string, fb Feedback) {
+			if experiment != "exp" {
+				t.Errorf("Unexpected experiment %q", experiment)
+			}
+
+			feedback = append(feedback, fb)
+		}),
+	)
+
+	text, err := r.Generate(context.Background(), "hello")
+	if err != nil || text != "from B" {
+		t.Fatalf("Generate() = %q, %v", text, err)
+	}
+
+	if len(responses) != 1 {
+		t.Fatalf("Expected 1 response hook call, got %d", len(responses))
+	}
+
+	err = r.RecordFeedback(context.Background(), Feedback{ResponseID: responses[0].ID, Variant: VariantB, Score: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := r.RecordFeedback(context.Background(), Feedback{Variant: "C"}); !errors.Is(err, ErrInvalidABConfig) {
+		t.Errorf("Expected ErrInvalidABConfig, got %v", err)
+	}
+
+	if len(feedback) != 1 || feedback[0].ResponseID != responses[0].ID {
+		t.Errorf("Unexpected feedback: %v", feedback)
+	}
+
+	stats := r.Stats()
+	if stats[VariantB].Requests != 1 || stats[VariantB].AverageScore() != 1 || stats[VariantA].Requests != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestABRouter_Error(t *testing.T) {
+	r, err := NewABRouter(
+		&Client{llmClient: &fakeModel{err: errors.New("boom")}},
+		&Client{llmClient: &fakeModel{}},
+		WithABPercentage(0),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := r.Generate(context.Background(), "hello"); err == nil {
+		t.Error("Expected error")
+	}
+
+	if stats := r.Stats(); stats[VariantA].Errors != 1 {
+		t.Errorf("Expected 1 error for variant A, got %+v", stats[VariantA])
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// BudgetManager enforces per-tenant spending limits. It is consulted before
+// every request of a Client whose context carries a tenant (see
+// WithBudgetTenant) and informed about the actual cost afterwards.
+//
+// Check makes no reservation: the cost only counts once Record is called
+// after the response. Concurrent requests of a tenant may therefore all
+// pass Check and exceed the hard limit by the cost of the requests in
+// flight. Limit the concurrent requests per tenant if the hard limit must
+// not be overrun.
+type BudgetManager interface {
+	// Check is called before a request with its estimated cost. It returns
+	// an error created with errors.NewQuotaExceeded if the request would
+	// exceed the hard limit of the tenant.
+	Check(ctx context.Context, tenantID string, estimatedCost float64) (*BudgetStatus, error)
+	// Record adds the actual cost of a completed request to the tenant. If
+	// it fails, the request still returns its response together with an
+	// error wrapping ErrBudgetNotRecorded.
+	Record(ctx context.Context, tenantID string, cost float64) error
+}
+
+// BudgetStatus describes the budget of a tenant at the time of a request.
+type BudgetStatus struct {
+	// TenantID is the tenant the request is billed to.
+	TenantID string
+	// Spent is the cost accumulated in the current period before the
+	// request.
+	Spent float64
+	// SoftLimit is the cost above which requests are annotated. Zero
+	// disables the soft limit.
+	SoftLimit float64
+	// HardLimit is the cost above which requests are rejected. Zero
+	// disables the hard limit.
+	HardLimit float64
+	// SoftLimitExceeded is set if the request crosses the soft limit.
+	SoftLimitExceeded bool
+}
+
+// CostFunc converts the token usage of a request into a cost, e.g. in
+// cents using the price list of the provider.
+type CostFunc func(usage TokenUsage) float64
+
+// TokenCost is the default CostFunc. It counts the total tokens, so budgets
+// are expressed in tokens.
+func TokenCost(usage TokenUsage) float64 {
+	return float64(usage.TotalTokens)
+}
+
+// budgetTenantKey is the context key for the tenant billed by the budget.
+type budgetTenantKey struct{}
+
+// WithBudgetTenant returns a context that carries the tenant ID whose budget
+// is charged for requests made with it. Requests without tenant are not
+// subject to the budget.
+func WithBudgetTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, budgetTenantKey{}, tenantID)
+}
+
+// budgetTenant returns the tenant ID stored in ctx.
+func budgetTenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(budgetTenantKey{}).(string)
+	return tenant
+}
+
+// WithBudget sets the BudgetManager consulted before every request and the
+// CostFunc used to price requests. A nil cost uses TokenCost. Passing a nil
+// manager disables budget enforcement.
+//
+// Example:
+//
+//	budget := llm.NewMemoryBudget(llm.BudgetLimits{Soft: 800_000, Hard: 1_000_000})
+//	client.WithBudget(budget, nil)
+//
+//	resp, err := client.GenerateResponse(llm.WithBudgetTenant(ctx, orgID), prompt, nil)
+//	if kerr.IsQuotaExceeded(err) { ... }
+//	if resp.Budget != nil && resp.Budget.SoftLimitExceeded { ... }
+func (c *Client) WithBudget(manager BudgetManager, cost CostFunc) *Client {
+	if cost == nil {
+		cost = TokenCost
+	}
+
+	c.budget = manager
+	c.cost = cost
+
+	return c
+}
+
+// checkBudget consults the budget manager for the tenant in ctx with the
+// estimated cost of prompt. It returns a nil status if no budget applies.
+func (c *Client) checkBudget(ctx context.Context, prompt string) (*BudgetStatus, error) {
+	tenant := budgetTenant(ctx)
+	if c.budget == nil || tenant == "" {
+		return nil, nil
+	}
+
+	tokens := EstimateTokens(prompt)
+
+	return c.budget.Check(ctx, tenant, c.cost(TokenUsage{PromptTokens: tokens, TotalTokens: tokens}))
+}
+
+// recordBudget records the cost of a completed request for the tenant in
+// ctx. If the provider did not report usage, it is estimated from prompt
+// and content. Failures are wrapped in ErrBudgetNotRecorded.
+func (c *Client) recordBudget(ctx context.Context, prompt, content string, usage TokenUsage) error {
+	tenant := budgetTenant(ctx)
+	if c.budget == nil || tenant == "" {
+		return nil
+	}
+
+	if usage.TotalTokens == 0 {
+		usage = TokenUsage{PromptTokens: EstimateTokens(prompt), CompletionTokens: EstimateTokens(content)}
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+
+	if err := c.budget.Record(ctx, tenant, c.cost(usage)); err != nil {
+		return fmt.Errorf("%w: %w", ErrBudgetNotRecorded, err)
+	}
+
+	return nil
+}
+
+// BudgetLimits are the spending limits of a tenant per period. A zero limit
+// is disabled.
+type BudgetLimits struct {
+	// Soft is the cost above which responses are annotated.
+	Soft float64
+	// Hard is the cost above which requests are rejected.
+	Hard float64
+}
+
+// MemoryBudget is an in-memory BudgetManager that accumulates costs per
+// tenant and calendar month (UTC). All tenants share the default limits
+// unless limits are set with SetLimits. The accumulated costs are lost on
+// restart and not shared between instances.
+type MemoryBudget struct {
+	mu       sync.Mutex
+	defaults BudgetLimits
+	limits   map[string]BudgetLimits
+	spent    map[string]float64
+	period   string
+	now      func() time.Time
+}
+
+// MemoryBudgetOption configures a MemoryBudget.
+type MemoryBudgetOption func(*MemoryBudget)
+
+// WithBudgetClock sets the clock used to determine the current month.
+func WithBudgetClock(now func() time.Time) MemoryBudgetOption {
+	return func(b *MemoryBudget) {
+		if now != nil {
+			b.now = now
+		}
+	}
+}
+
+// NewMemoryBudget creates a MemoryBudget with the default limits applied to
+// all tenants.
+func NewMemoryBudget(defaults BudgetLimits, opts ...MemoryBudgetOption) *MemoryBudget {
+	b := &MemoryBudget{
+		defaults: defaults,
+		limits:   make(map[string]BudgetLimits),
+		spent:    make(map[string]float64),
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// SetLimits sets the limits of tenantID, overriding the defaults.
+func (b *MemoryBudget) SetLimits(tenantID string, limits BudgetLimits) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.limits[tenantID] = limits
+}
+
+// Spent returns the cost accumulated by tenantID in the current month.
+func (b *MemoryBudget) Spent(tenantID string) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+
+	return b.spent[tenantID]
+}
+
+// Check implements BudgetManager.
+func (b *MemoryBudget) Check(_ context.Context, tenantID string, estimatedCost float64) (*BudgetStatus, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+
+	limits, ok := b.limits[tenantID]
+	if !ok {
+		limits = b.defaults
+	}
+
+	status := &BudgetStatus{
+		TenantID:  tenantID,
+		Spent:     b.spent[tenantID],
+		SoftLimit: limits.Soft,
+		HardLimit: limits.Hard,
+	}
+
+	projected := status.Spent + estimatedCost
+
+	if limits.Hard > 0 && projected > limits.Hard {
+		return status, kerr.NewQuotaExceeded(fmt.Sprintf("llm budget of tenant %s exhausted for %s", tenantID, b.period))
+	}
+
+	status.SoftLimitExceeded = limits.Soft > 0 && projected > limits.Soft
+
+	return status, nil
+}
+
+// Record implements BudgetManager.
+func (b *MemoryBudget) Record(_ context.Context, tenantID string, cost float64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	b.spent[tenantID] += cost
+
+	return nil
+}
+
+// rollover resets the accumulated costs when a new month begins. The caller
+// must hold b.mu.
+func (b *MemoryBudget) rollover() {
+	period := b.now().UTC().Format("2006-01")
+	if period == b.period {
+		return
+	}
+
+	b.period = period
+	clear(b.spent)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+func TestMemoryBudget_Limits(t *testing.T) {
+	now := time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+	budget := NewMemoryBudget(BudgetLimits{Soft: 10, Hard: 20}, WithBudgetClock(func() time.Time { return now }))
+	budget.SetLimits("premium", BudgetLimits{Hard: 100})
+
+	ctx := context.Background()
+
+	status, err := budget.Check(ctx, "org-1", 5)
+	if err != nil || status.SoftLimitExceeded {
+		t.Fatalf("Expected request within budget, got %+v, %v", status, err)
+	}
+
+	if err := budget.Record(ctx, "org-1", 8); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	status, err = budget.Check(ctx, "org-1", 5)
+	if err != nil || !status.SoftLimitExceeded || status.Spent != 8 {
+		t.Fatalf("Expected soft limit to be crossed, got %+v, %v", status, err)
+	}
+
+	_, err = budget.Check(ctx, "org-1", 13)
+	if !kerr.Is(err, kerr.QuotaExceeded) {
+		t.Fatalf("Expected QuotaExceeded, got %v", err)
+	}
+
+	var e *kerr.Error
+	if !errors.As(err, &e) || e.Status != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %v", err)
+	}
+
+	if _, err := budget.Check(ctx, "premium", 50); err != nil {
+		t.Errorf("Expected tenant limits to override defaults, got %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+
+	if spent := budget.Spent("org-1"); spent != 0 {
+		t.Errorf("Expected budget to reset in a new month, got %v", spent)
+	}
+}
+
+func TestClient_Budget(t *testing.T) {
+	budget := NewMemoryBudget(BudgetLimits{Soft: 5, Hard: 10})
+	client := (&Client{llmClient: &promptModel{}}).WithBudget(budget, nil)
+
+	if _, err := client.GenerateResponse(context.Background(), "prompt", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if spent := budget.Spent(""); spent != 0 {
+		t.Errorf("Expected requests without tenant to be free, got %v", spent)
+	}
+
+	ctx := WithBudgetTenant(context.Background(), "org-1")
+
+	resp, err := client.GenerateResponse(ctx, "prompt", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.Budget == nil || resp.Budget.SoftLimitExceeded {
+		t.Errorf("Expected budget within soft limit, got %+v", resp.Budget)
+	}
+
+	if spent := budget.Spent("org-1"); spent != 4 {
+		t.Errorf("Expected the reported usage to be recorded, got %v", spent)
+	}
+
+	resp, err = client.GenerateResponse(ctx, "prompt", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !resp.Budget.SoftLimitExceeded {
+		t.Errorf("Expected soft limit to be crossed, got %+v", resp.Budget)
+	}
+
+	if _, err := client.Generate(ctx, "prompt"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, _, err := client.GenerateWithUsage(ctx, "prompt"); !kerr.Is(err, kerr.QuotaExceeded) {
+		t.Errorf("Expected QuotaExceeded, got %v", err)
+	}
+}
+
+// failingBudget passes every check but fails to record costs.
+type failingBudget struct{}
+
+func (failingBudget) Check(_ context.Context, tenantID string, _ float64) (*BudgetStatus, error) {
+	return &BudgetStatus{TenantID: tenantID}, nil
+}
+
+func (failingBudget) Record(context.Context, string, float64) error {
+	return errors.New("store unavailable")
+}
+
+func TestClient_BudgetRecordFailure(t *testing.T) {
+	client := (&Client{llmClient: &promptModel{}}).WithBudget(failingBudget{}, nil)
+	ctx := WithBudgetTenant(context.Background(), "org-1")
+
+	resp, err := client.GenerateResponse(ctx, "prompt", nil)
+	if !errors.Is(err, ErrBudgetNotRecorded) {
+		t.Errorf("Expected ErrBudgetNotRecorded, got %v", err)
+	}
+
+	if resp == nil || resp.Content != "answer" {
+		t.Errorf("Expected the response to be returned, got %+v", resp)
+	}
+
+	content, usage, err := client.GenerateWithUsage(ctx, "prompt")
+	if !errors.Is(err, ErrBudgetNotRecorded) || content != "answer" || usage.TotalTokens != 4 {
+		t.Errorf("Expected answer with ErrBudgetNotRecorded, got %q, %+v, %v", content, usage, err)
+	}
+
+	if content, err := client.Generate(ctx, "prompt"); !errors.Is(err, ErrBudgetNotRecorded) || content != "answer" {
+		t.Errorf("Expected answer with ErrBudgetNotRecorded, got %q, %v", content, err)
+	}
+
+	conv := NewConversation("c1", client)
+
+	answer, err := conv.Send(ctx, "hi")
+	if !errors.Is(err, ErrBudgetNotRecorded) || answer != "answer" {
+		t.Errorf("Expected answer with ErrBudgetNotRecorded, got %q, %v", answer, err)
+	}
+
+	history, err := conv.Messages(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(history) != 2 || history[1].Content != "answer" {
+		t.Errorf("Expected the answer to be stored, got %+v", history)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// OptionCapabilities is the Options key overriding the capabilities of the
+// provider, see WithCapabilities. The value must be a Capabilities.
+const OptionCapabilities = "capabilities"
+
+// Feature is an optional feature of an LLM provider.
+type Feature string
+
+const (
+	// FeatureStreaming streams the response while it is generated.
+	FeatureStreaming Feature = "streaming"
+	// FeatureTools lets the model call functions (tool calling).
+	FeatureTools Feature = "tools"
+	// FeatureJSONMode constrains the response to valid JSON.
+	FeatureJSONMode Feature = "json_mode"
+	// FeatureEmbeddings creates vector embeddings of texts.
+	FeatureEmbeddings Feature = "embeddings"
+)
+
+// Capabilities describes the optional features a provider supports.
+type Capabilities struct {
+	Streaming  bool `json:"streaming"`
+	Tools      bool `json:"tools"`
+	JSONMode   bool `json:"jsonMode"`
+	Embeddings bool `json:"embeddings"`
+}
+
+// Supports reports whether feature is supported.
+func (c Capabilities) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureStreaming:
+		return c.Streaming
+	case FeatureTools:
+		return c.Tools
+	case FeatureJSONMode:
+		return c.JSONMode
+	case FeatureEmbeddings:
+		return c.Embeddings
+	default:
+		return false
+	}
+}
+
+// providerCapabilities is the feature matrix of the provider clients.
+var providerCapabilities = map[Provider]Capabilities{
+	ProviderOpenAI:      {Streaming: true, Tools: true, JSONMode: true, Embeddings: true},
+	ProviderAnthropic:   {Streaming: true, Tools: true},
+	ProviderGemini:      {Streaming: true, Tools: true, JSONMode: true, Embeddings: true},
+	ProviderMistral:     {Streaming: true, Tools: true, Embeddings: true},
+	ProviderOllama:      {Streaming: true, JSONMode: true, Embeddings: true},
+	ProviderCloudflare:  {Streaming: true, Embeddings: true},
+	ProviderHuggingFace: {Embeddings: true},
+}
+
+// ProviderCapabilities returns the features supported by provider. Unknown
+// providers support none.
+func ProviderCapabilities(provider Provider) Capabilities {
+	return providerCapabilities[provider]
+}
+
+// WithCapabilities overrides the capabilities of the provider, e.g. for an
+// OpenAI-compatible endpoint that does not support JSON mode.
+func WithCapabilities(capabilities Capabilities) Option {
+	return func(c *Config) {
+		c.Options[OptionCapabilities] = capabilities
+	}
+}
+
+// Capabilities returns the features supported by the configured provider.
+//
+// Example:
+//
+//	if client.Capabilities().Streaming {
+//	    // render tokens as they arrive
+//	}
+func (c *Client) Capabilities() Capabilities {
+	return c.capabilities
+}
+
+// Require returns an error wrapping ErrUnsupportedFeature for the first
+// feature the provider does not support.
+func (c *Client) Require(features ...Feature) error {
+	for _, f := range features {
+		if !c.capabilities.Supports(f) {
+			return fmt.Errorf("%w: %s is not supported by provider %q", ErrUnsupportedFeature, f, c.provider)
+		}
+	}
+
+	return nil
+}
+
+// jsonInstruction is appended to the prompt when the provider has no JSON mode.
+const jsonInstruction = "\n\nRespond with a single valid JSON value only, without code fences or explanations."
+
+// GenerateJSON generates a JSON response and decodes it into v. Providers
+// with JSON mode are asked for JSON directly. For other providers, the
+// prompt instructs the model to answer with JSON, and the JSON is extracted
+// from the response, which may be wrapped in a code fence.
+//
+// Example:
+//
+//	var risk struct {
+//	    Title    string `json:"title"`
+//	    Severity string `json:"severity"`
+//	}
+//	err := client.GenerateJSON(ctx, "Extract the risk as JSON with title and severity: ...", &risk)
+func (c *Client) GenerateJSON(ctx context.Context, prompt string, v any, options ...llms.CallOption) error {
+	if c.capabilities.JSONMode {
+		options = append(options, llms.WithJSONMode())
+	} else {
+		prompt += jsonInstruction
+	}
+
+	result, err := c.GenerateWithOptions(ctx, prompt, options...)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(extractJSON(result)), v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidJSONResponse, err)
+	}
+
+	return nil
+}
+
+// GenerateStream generates text and passes it to fn in chunks as it is
+// generated. Providers without streaming call fn once with the complete
+// response. It returns the complete response. An error returned by fn
+// aborts the generation.
+func (c *Client) GenerateStream(ctx context.Context, prompt string, fn func(ctx context.Context, chunk []byte) error, options ...llms.CallOption) (string, error) {
+	if c.capabilities.Streaming {
+		return c.GenerateWithOptions(ctx, prompt, append(options, llms.WithStreamingFunc(fn))...)
+	}
+
+	result, err := c.GenerateWithOptions(ctx, prompt, options...)
+	if err != nil {
+		return "", err
+	}
+
+	if err := fn(ctx, []byte(result)); err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// embedder is implemented by provider clients that create embeddings.
+type embedder interface {
+	CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Embed creates a vector embedding for each text. Returns an error wrapping
+// ErrUnsupportedFeature if the provider does not support embeddings.
+func (c *Client) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if err := c.Require(FeatureEmbeddings); err != nil {
+		return nil, err
+	}
+
+	e, ok := c.llmClient.(embedder)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not supported by provider %q", ErrUnsupportedFeature, FeatureEmbeddings, c.provider)
+	}
+
+	return e.CreateEmbedding(ctx, texts)
+}
+
+// extractJSON returns the JSON value in s, stripping a surrounding code
+// fence and any text before the first or after the last bracket.
+func extractJSON(s string) string {
+	s = strings.TrimSpace(s)
+
+	if rest, ok := strings.CutPrefix(s, "```"); ok {
+		// drop the language tag, e.g. ```json
+		if i := strings.IndexByte(rest, '\n'); i >= 0 {
+			rest = rest[i+1:]
+		}
+
+		s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), "```"))
+	}
+
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return s
+	}
+
+	closing := "}"
+	if s[start] == '[' {
+		closing = "]"
+	}
+
+	end := strings.LastIndex(s, closing)
+	if end < start {
+		return s
+	}
+
+	return s[start : end+1]
+}
@@ -0,0 +1,188 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// capabilityModel records the prompt and options of the last call, streams
+// the answer in two chunks if asked to and creates fake embeddings.
+type capabilityModel struct {
+	answer string
+	prompt string
+	last   llms.CallOptions
+}
+
+func (m *capabilityModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.last = llms.CallOptions{}
+	for _, o := range options {
+		o(&m.last)
+	}
+
+	m.prompt = messages[0].Parts[0].(llms.TextContent).Text
+
+	if m.last.StreamingFunc != nil {
+		half := len(m.answer) / 2
+		for _, chunk := range []string{m.answer[:half], m.answer[half:]} {
+			if err := m.last.StreamingFunc(ctx, []byte(chunk)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: m.answer}}}, nil
+}
+
+func (m *capabilityModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *capabilityModel) CreateEmbedding(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text))}
+	}
+
+	return vectors, nil
+}
+
+func TestNew_Capabilities(t *testing.T) {
+	client, err := New(NewConfig(WithAnthropic("claude-3-sonnet", "test-api-key")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := client.Capabilities(); got != ProviderCapabilities(ProviderAnthropic) {
+		t.Errorf("Expected Anthropic capabilities, got %+v", got)
+	}
+
+	if err := client.Require(FeatureStreaming, FeatureTools); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	err = client.Require(FeatureEmbeddings)
+	if !errors.Is(err, ErrUnsupportedFeature) || !strings.Contains(err.Error(), "anthropic") {
+		t.Errorf("Expected ErrUnsupportedFeature naming the provider, got %v", err)
+	}
+
+	client, err = New(NewConfig(
+		WithOpenAI("gpt-4", "test-api-key", WithURL("http://localhost:8000/v1")),
+		WithCapabilities(Capabilities{Streaming: true}),
+	))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if client.Capabilities().JSONMode {
+		t.Error("Expected capabilities override to disable JSON mode")
+	}
+}
+
+func TestClient_GenerateJSON(t *testing.T) {
+	type risk struct {
+		Title string `json:"title"`
+	}
+
+	tests := []struct {
+		name         string
+		capabilities Capabilities
+		answer       string
+		wantJSONMode bool
+	}{
+		{
+			name:         "json mode",
+			capabilities: Capabilities{JSONMode: true},
+			answer:       `{"title":"Data loss"}`,
+			wantJSONMode: true,
+		},
+		{
+			name:   "fallback with code fence",
+			answer: "Here it is:\n```json\n{\"title\":\"Data loss\"}\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &capabilityModel{answer: tt.answer}
+			client := &Client{llmClient: model, capabilities: tt.capabilities}
+
+			var got risk
+			if err := client.GenerateJSON(context.Background(), "Extract the risk.", &got); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got.Title != "Data loss" {
+				t.Errorf("Expected decoded title, got %+v", got)
+			}
+
+			if model.last.JSONMode != tt.wantJSONMode {
+				t.Errorf("Expected JSON mode %v, got %v", tt.wantJSONMode, model.last.JSONMode)
+			}
+
+			if hasInstruction := strings.HasSuffix(model.prompt, jsonInstruction); hasInstruction == tt.wantJSONMode {
+				t.Errorf("Expected JSON instruction only without JSON mode, prompt %q", model.prompt)
+			}
+		})
+	}
+
+	client := &Client{llmClient: &capabilityModel{answer: "no json here"}}
+
+	var got risk
+	if err := client.GenerateJSON(context.Background(), "Extract the risk.", &got); !errors.Is(err, ErrInvalidJSONResponse) {
+		t.Errorf("Expected ErrInvalidJSONResponse, got %v", err)
+	}
+}
+
+func TestClient_GenerateStream(t *testing.T) {
+	for _, streaming := range []bool{true, false} {
+		model := &capabilityModel{answer: "streamed answer"}
+		client := &Client{llmClient: model, capabilities: Capabilities{Streaming: streaming}}
+
+		var chunks []string
+		result, err := client.GenerateStream(context.Background(), "prompt", func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result != "streamed answer" || strings.Join(chunks, "") != result {
+			t.Errorf("streaming=%v: expected chunks to add up to the result, got %q and %q", streaming, chunks, result)
+		}
+
+		wantChunks := 1
+		if streaming {
+			wantChunks = 2
+		}
+
+		if len(chunks) != wantChunks {
+			t.Errorf("streaming=%v: expected %d chunks, got %d", streaming, wantChunks, len(chunks))
+		}
+	}
+}
+
+func TestClient_Embed(t *testing.T) {
+	client := &Client{llmClient: &capabilityModel{}, provider: ProviderOpenAI, capabilities: ProviderCapabilities(ProviderOpenAI)}
+
+	vectors, err := client.Embed(context.Background(), []string{"a", "bb"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(vectors) != 2 || vectors[1][0] != 2 {
+		t.Errorf("Unexpected vectors: %v", vectors)
+	}
+
+	client = &Client{llmClient: &capabilityModel{}, provider: ProviderAnthropic, capabilities: ProviderCapabilities(ProviderAnthropic)}
+	if _, err := client.Embed(context.Background(), []string{"a"}); !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("Expected ErrUnsupportedFeature, got %v", err)
+	}
+}
@@ -6,13 +6,21 @@ package llm
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/tmc/langchaingo/llms"
 )
 
 // Client represents an LLM client that can be used for various text generation tasks.
 type Client struct {
-	llmClient llms.Model
+	llmClient    llms.Model
+	provider     Provider
+	capabilities Capabilities
+	guard        *PromptGuard
+	limit        *InputLimit
+	overrides    []llms.CallOption
+	budget       BudgetManager
+	cost         CostFunc
 }
 
 // New creates a new LLM client with the given configuration.
@@ -36,9 +44,21 @@ func New(cfg *Config) (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{
-		llmClient: llmClient,
-	}, nil
+	c := &Client{
+		llmClient:    llmClient,
+		provider:     cfg.Provider,
+		capabilities: ProviderCapabilities(cfg.Provider),
+	}
+
+	if capabilities, ok := cfg.Options[OptionCapabilities].(Capabilities); ok {
+		c.capabilities = capabilities
+	}
+
+	if temperature, ok := cfg.Options[OptionTemperature].(float64); ok {
+		c.overrides = []llms.CallOption{llms.WithTemperature(temperature)}
+	}
+
+	return c, nil
 }
 
 // Generate generates text based on the provided prompt.
@@ -50,15 +70,65 @@ func New(cfg *Config) (*Client, error) {
 //
 //	result, err := client.Generate(ctx, "Summarize this text: ...")
 func (c *Client) Generate(ctx context.Context, prompt string) (string, error) {
-	return llms.GenerateFromSinglePrompt(ctx, c.llmClient, prompt)
+	return c.GenerateWithOptions(ctx, prompt)
 }
 
 // GenerateWithOptions generates text with additional options.
 //
 // This method allows for more control over the generation process by accepting
-// additional options that are passed to the underlying LLM.
+// additional options that are passed to the underlying LLM. Requests that
+// would exceed the hard limit of the tenant's budget are rejected; see
+// WithBudget. If the cost cannot be recorded, the result is returned
+// together with an error wrapping ErrBudgetNotRecorded.
 func (c *Client) GenerateWithOptions(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
-	return llms.GenerateFromSinglePrompt(ctx, c.llmClient, prompt, options...)
+	prompt, _, err := c.prepare(prompt, c.limit)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := c.checkBudget(ctx, prompt); err != nil {
+		return "", err
+	}
+
+	result, err := llms.GenerateFromSinglePrompt(ctx, c.llmClient, prompt, c.callOptions(options)...)
+	if err != nil {
+		return "", err
+	}
+
+	return result, c.recordBudget(ctx, prompt, result, TokenUsage{})
+}
+
+// WithOverrides returns a copy of the client that applies options to every
+// call, e.g. to use a different model or temperature for one request. The
+// copy shares the provider client, guard and input limit with c, so it is
+// cheap to create per request. Options passed to a call take precedence
+// over the overrides.
+//
+// Example:
+//
+//	precise := client.WithOverrides(llms.WithModel("gpt-4o"), llms.WithTemperature(0))
+//	result, err := precise.Generate(ctx, prompt)
+func (c *Client) WithOverrides(options ...llms.CallOption) *Client {
+	cp := *c
+	cp.overrides = append(slices.Clip(c.overrides), options...)
+
+	return &cp
+}
+
+// callOptions returns the client overrides followed by options.
+func (c *Client) callOptions(options []llms.CallOption) []llms.CallOption {
+	if len(c.overrides) == 0 {
+		return options
+	}
+
+	return append(slices.Clip(c.overrides), options...)
+}
+
+// WithPromptGuard sets a PromptGuard that inspects every prompt before it is
+// sent to the provider. Passing nil disables the guard.
+func (c *Client) WithPromptGuard(guard *PromptGuard) *Client {
+	c.guard = guard
+	return c
 }
 
 // GetModel returns the underlying LLM model for advanced usage.
@@ -6,6 +6,8 @@ package llm
 import (
 	"context"
 	"testing"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 func TestNewClient(t *testing.T) {
@@ -140,3 +142,58 @@ func TestClient_GetModel(t *testing.T) {
 		t.Error("Expected non-nil model")
 	}
 }
+
+// usageModel is an llms.Model that reports fixed generation info.
+type usageModel struct {
+	info map[string]any
+}
+
+func (m *usageModel) GenerateContent(_ context.Context, _ []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "answer", GenerationInfo: m.info}}}, nil
+}
+
+func (m *usageModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestClient_GenerateWithUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		info map[string]any
+		want TokenUsage
+	}{
+		{
+			name: "openai style",
+			info: map[string]any{"PromptTokens": 10, "CompletionTokens": 5, "TotalTokens": 15},
+			want: TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		},
+		{
+			name: "anthropic style",
+			info: map[string]any{"InputTokens": 7, "OutputTokens": 3},
+			want: TokenUsage{PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10},
+		},
+		{
+			name: "not reported",
+			want: TokenUsage{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{llmClient: &usageModel{info: tt.info}}
+
+			text, usage, err := c.GenerateWithUsage(context.Background(), "prompt")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if text != "answer" {
+				t.Errorf("Expected answer, got %q", text)
+			}
+
+			if usage != tt.want {
+				t.Errorf("Expected usage %+v, got %+v", tt.want, usage)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@
 
 package llm
 
+import "net/http"
+
 // Provider represents the supported LLM service providers.
 //
 // Each provider has different API endpoints, authentication methods, and
@@ -89,6 +91,12 @@ type Config struct {
 	// This map allows for extensible configuration without struct changes.
 	// Common keys include "temperature", "organization_id", "beta_header".
 	Options map[string]interface{}
+
+	// HTTPClient is used for requests to the provider instead of the
+	// default client, e.g. to replay recorded responses in tests. Supported
+	// by OpenAI, Anthropic, Gemini, Cloudflare and Ollama; ignored by
+	// Mistral and HuggingFace, whose SDKs do not accept a custom client.
+	HTTPClient *http.Client
 }
 
 // Credentials represents authentication credentials for LLM services.
@@ -127,6 +135,16 @@ func NewConfig(options ...Option) *Config {
 	return config
 }
 
+// WithHTTPClient sets the HTTP client used for requests to the provider.
+//
+// See NewReplayHTTPClient for recording and replaying LLM interactions in
+// tests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
 // WithProvider sets the LLM service provider.
 //
 // This option must be called when configuring LLM-based services.
@@ -0,0 +1,419 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Role is the author of a conversation message.
+type Role string
+
+const (
+	// RoleSystem marks instructions for the model. System messages are never
+	// trimmed.
+	RoleSystem Role = "system"
+	// RoleUser marks messages written by the user.
+	RoleUser Role = "user"
+	// RoleAssistant marks messages generated by the model.
+	RoleAssistant Role = "assistant"
+)
+
+// charsPerToken is the average number of characters per token assumed by
+// EstimateTokens.
+const charsPerToken = 4
+
+// Message is a single message of a conversation.
+type Message struct {
+	Role      Role      `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// chatMessageType maps the role to the langchaingo message type.
+func (m Message) chatMessageType() llms.ChatMessageType {
+	switch m.Role {
+	case RoleSystem:
+		return llms.ChatMessageTypeSystem
+	case RoleAssistant:
+		return llms.ChatMessageTypeAI
+	default:
+		return llms.ChatMessageTypeHuman
+	}
+}
+
+// TokenCounter returns the number of tokens of a text.
+type TokenCounter func(text string) int
+
+// EstimateTokens is a TokenCounter that approximates the token count of
+// English text as one token per four characters. Use a tokenizer of the
+// configured model when exact budgets matter.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// TrimStrategy bounds the history of a conversation, e.g. to fit the context
+// window of the model. Trim must keep system messages and the order of the
+// remaining messages.
+type TrimStrategy interface {
+	Trim(ctx context.Context, messages []Message) ([]Message, error)
+}
+
+// TrimFunc adapts a function to a TrimStrategy.
+type TrimFunc func(ctx context.Context, messages []Message) ([]Message, error)
+
+// Trim implements TrimStrategy.
+func (f TrimFunc) Trim(ctx context.Context, messages []Message) ([]Message, error) {
+	return f(ctx, messages)
+}
+
+// SlidingWindow keeps the last size non-system messages.
+func SlidingWindow(size int) TrimStrategy {
+	return TrimFunc(func(_ context.Context, messages []Message) ([]Message, error) {
+		return dropOldest(messages, func(kept []Message) bool {
+			return countNonSystem(kept) <= size
+		}), nil
+	})
+}
+
+// TokenBudget drops the oldest non-system messages until the conversation
+// fits into maxTokens as counted by counter. A nil counter defaults to
+// EstimateTokens. The latest message is always kept, even if it exceeds the
+// budget on its own.
+func TokenBudget(maxTokens int, counter TokenCounter) TrimStrategy {
+	if counter == nil {
+		counter = EstimateTokens
+	}
+
+	return TrimFunc(func(_ context.Context, messages []Message) ([]Message, error) {
+		return dropOldest(messages, func(kept []Message) bool {
+			if countNonSystem(kept) <= 1 {
+				return true
+			}
+
+			total := 0
+			for _, m := range kept {
+				total += counter(m.Content)
+			}
+
+			return total <= maxTokens
+		}), nil
+	})
+}
+
+// Summarizer condenses a text. *summarizer.Client implements it.
+type Summarizer interface {
+	Summarize(ctx context.Context, text string) (string, error)
+}
+
+// SummaryCompaction replaces older messages by a summary once the
+// conversation holds more than threshold non-system messages. The last keep
+// messages are retained verbatim; all earlier ones, including previous
+// summaries, are condensed by s into a single system message.
+func SummaryCompaction(s Summarizer, threshold, keep int) TrimStrategy {
+	return TrimFunc(func(ctx context.Context, messages []Message) ([]Message, error) {
+		if countNonSystem(messages) <= threshold {
+			return messages, nil
+		}
+
+		var (
+			system   []Message
+			history  []Message
+			previous []string
+		)
+
+		for _, m := range messages {
+			switch {
+			case m.Role != RoleSystem:
+				history = append(history, m)
+			case strings.HasPrefix(m.Content, summaryPrefix):
+				previous = append(previous, strings.TrimPrefix(m.Content, summaryPrefix))
+			default:
+				system = append(system, m)
+			}
+		}
+
+		cut := max(len(history)-keep, 0)
+
+		var b strings.Builder
+
+		for _, p := range previous {
+			b.WriteString(p)
+			b.WriteString("\n")
+		}
+
+		for _, m := range history[:cut] {
+			fmt.Fprintf(&b, "%s: %s\n", m.Role, m.Content)
+		}
+
+		summary, err := s.Summarize(ctx, b.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compact conversation: %w", err)
+		}
+
+		out := make([]Message, 0, len(system)+1+len(history)-cut)
+		out = append(out, system...)
+		out = append(out, Message{Role: RoleSystem, Content: summaryPrefix + summary, CreatedAt: time.Now()})
+		out = append(out, history[cut:]...)
+
+		return out, nil
+	})
+}
+
+// summaryPrefix marks system messages created by SummaryCompaction.
+const summaryPrefix = "Summary of the earlier conversation: "
+
+// dropOldest removes the oldest non-system messages until fits reports true.
+func dropOldest(messages []Message, fits func([]Message) bool) []Message {
+	kept := slices.Clone(messages)
+
+	for !fits(kept) {
+		i := slices.IndexFunc(kept, func(m Message) bool { return m.Role != RoleSystem })
+		if i < 0 {
+			break
+		}
+
+		kept = slices.Delete(kept, i, i+1)
+	}
+
+	return kept
+}
+
+func countNonSystem(messages []Message) int {
+	n := 0
+
+	for _, m := range messages {
+		if m.Role != RoleSystem {
+			n++
+		}
+	}
+
+	return n
+}
+
+// ConversationStore persists conversation histories.
+type ConversationStore interface {
+	// Load returns the messages of the conversation, or nil if it does not
+	// exist.
+	Load(ctx context.Context, id string) ([]Message, error)
+	// Save replaces the messages of the conversation.
+	Save(ctx context.Context, id string, messages []Message) error
+	// Delete removes the conversation. Deleting a missing conversation is
+	// not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryConversationStore is a ConversationStore that keeps histories in
+// memory. It is safe for concurrent use.
+type MemoryConversationStore struct {
+	mu            sync.RWMutex
+	conversations map[string][]Message
+}
+
+// NewMemoryConversationStore returns an empty MemoryConversationStore.
+func NewMemoryConversationStore() *MemoryConversationStore {
+	return &MemoryConversationStore{conversations: make(map[string][]Message)}
+}
+
+// Load implements ConversationStore.
+func (s *MemoryConversationStore) Load(_ context.Context, id string) ([]Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.conversations[id]), nil
+}
+
+// Save implements ConversationStore.
+func (s *MemoryConversationStore) Save(_ context.Context, id string, messages []Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conversations[id] = slices.Clone(messages)
+
+	return nil
+}
+
+// Delete implements ConversationStore.
+func (s *MemoryConversationStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.conversations, id)
+
+	return nil
+}
+
+// ConversationOption configures a Conversation.
+type ConversationOption func(*Conversation)
+
+// WithConversationStore persists the history in store. Defaults to a
+// MemoryConversationStore private to the conversation.
+func WithConversationStore(store ConversationStore) ConversationOption {
+	return func(c *Conversation) {
+		c.store = store
+	}
+}
+
+// WithTrimStrategy bounds the history with strategy before every request.
+// Without a strategy the history grows unbounded.
+func WithTrimStrategy(strategy TrimStrategy) ConversationOption {
+	return func(c *Conversation) {
+		c.trim = strategy
+	}
+}
+
+// WithSystemPrompt starts new conversations with the given system message.
+func WithSystemPrompt(prompt string) ConversationOption {
+	return func(c *Conversation) {
+		c.systemPrompt = prompt
+	}
+}
+
+// Conversation is a chat session with a bounded message history.
+//
+// The history is loaded from the store on every Send, so several instances
+// can serve the same conversation one request at a time. A Conversation is
+// safe for concurrent use; concurrent Sends are serialized.
+//
+// Example:
+//
+//	conv := llm.NewConversation("session-123", client,
+//	    llm.WithSystemPrompt("You are a helpful GRC assistant."),
+//	    llm.WithTrimStrategy(llm.TokenBudget(8000, nil)),
+//	)
+//	answer, err := conv.Send(ctx, "Which controls cover access reviews?")
+type Conversation struct {
+	id           string
+	client       *Client
+	store        ConversationStore
+	trim         TrimStrategy
+	systemPrompt string
+
+	mu sync.Mutex
+}
+
+// NewConversation returns the conversation with the given ID.
+func NewConversation(id string, client *Client, opts ...ConversationOption) *Conversation {
+	c := &Conversation{id: id, client: client}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.store == nil {
+		c.store = NewMemoryConversationStore()
+	}
+
+	return c
+}
+
+// ID returns the ID of the conversation.
+func (c *Conversation) ID() string {
+	return c.id
+}
+
+// Messages returns the stored history, including system messages.
+func (c *Conversation) Messages(ctx context.Context) ([]Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.load(ctx)
+}
+
+// Send appends the user message to the history, trims it, asks the model and
+// stores the answer. If the model fails, the history is left unchanged. If
+// the cost cannot be recorded in the budget, the answer is stored and
+// returned together with an error wrapping ErrBudgetNotRecorded.
+func (c *Conversation) Send(ctx context.Context, content string, options ...llms.CallOption) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client.guard != nil {
+		guarded, _, err := c.client.guard.Apply(content)
+		if err != nil {
+			return "", err
+		}
+
+		content = guarded
+	}
+
+	messages, err := c.load(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	messages = append(messages, Message{Role: RoleUser, Content: content, CreatedAt: time.Now()})
+
+	if c.trim != nil {
+		if messages, err = c.trim.Trim(ctx, messages); err != nil {
+			return "", err
+		}
+	}
+
+	parts := make([]llms.MessageContent, 0, len(messages))
+	contents := make([]string, 0, len(messages))
+
+	for _, m := range messages {
+		parts = append(parts, llms.TextParts(m.chatMessageType(), m.Content))
+		contents = append(contents, m.Content)
+	}
+
+	prompt := strings.Join(contents, "\n")
+
+	if _, err := c.client.checkBudget(ctx, prompt); err != nil {
+		return "", err
+	}
+
+	resp, err := c.client.llmClient.GenerateContent(ctx, parts, c.client.callOptions(options)...)
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", ErrEmptyResponse
+	}
+
+	answer := resp.Choices[0].Content
+
+	recordErr := c.client.recordBudget(ctx, prompt, answer, usageFromGenerationInfo(resp.Choices[0].GenerationInfo))
+
+	messages = append(messages, Message{Role: RoleAssistant, Content: answer, CreatedAt: time.Now()})
+
+	if err := c.store.Save(ctx, c.id, messages); err != nil {
+		return "", err
+	}
+
+	return answer, recordErr
+}
+
+// Reset deletes the history of the conversation.
+func (c *Conversation) Reset(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.store.Delete(ctx, c.id)
+}
+
+// load returns the stored history, starting a new one with the system prompt
+// if the conversation does not exist yet.
+func (c *Conversation) load(ctx context.Context) ([]Message, error) {
+	messages, err := c.store.Load(ctx, c.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 && c.systemPrompt != "" {
+		messages = []Message{{Role: RoleSystem, Content: c.systemPrompt, CreatedAt: time.Now()}}
+	}
+
+	return messages, nil
+}
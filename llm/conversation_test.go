@@ -0,0 +1,204 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// recordingModel answers with a fixed text and records the messages sent.
+type recordingModel struct {
+	answer string
+	err    error
+	calls  [][]llms.MessageContent
+}
+
+func (m *recordingModel) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.calls = append(m.calls, messages)
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: m.answer}}}, nil
+}
+
+func (m *recordingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// fixedSummarizer returns a fixed summary and records its input.
+type fixedSummarizer struct {
+	inputs []string
+}
+
+func (s *fixedSummarizer) Summarize(_ context.Context, text string) (string, error) {
+	s.inputs = append(s.inputs, text)
+	return "summary", nil
+}
+
+func msgs(roles ...Role) []Message {
+	out := make([]Message, 0, len(roles))
+	for i, r := range roles {
+		out = append(out, Message{Role: r, Content: strings.Repeat("x", 4) + string(rune('a'+i))})
+	}
+
+	return out
+}
+
+func roles(messages []Message) []Role {
+	out := make([]Role, 0, len(messages))
+	for _, m := range messages {
+		out = append(out, m.Role)
+	}
+
+	return out
+}
+
+func TestConversation_Send(t *testing.T) {
+	model := &recordingModel{answer: "hello"}
+	store := NewMemoryConversationStore()
+	conv := NewConversation("c1", &Client{llmClient: model},
+		WithSystemPrompt("be brief"),
+		WithConversationStore(store),
+	)
+
+	ctx := context.Background()
+
+	if _, err := conv.Send(ctx, "hi"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	answer, err := conv.Send(ctx, "again")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if answer != "hello" {
+		t.Errorf("Expected hello, got %q", answer)
+	}
+
+	history, err := conv.Messages(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []Role{RoleSystem, RoleUser, RoleAssistant, RoleUser, RoleAssistant}
+	if got := roles(history); !slices.Equal(got, want) {
+		t.Errorf("Expected roles %v, got %v", want, got)
+	}
+
+	// the second request carries the full history
+	if len(model.calls[1]) != 4 || model.calls[1][0].Role != llms.ChatMessageTypeSystem {
+		t.Errorf("Unexpected request messages: %v", model.calls[1])
+	}
+
+	// another instance continues from the store
+	other := NewConversation("c1", &Client{llmClient: model}, WithConversationStore(store))
+	if loaded, _ := other.Messages(ctx); len(loaded) != len(history) {
+		t.Errorf("Expected %d stored messages, got %d", len(history), len(loaded))
+	}
+
+	if err := conv.Reset(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if loaded, _ := other.Messages(ctx); len(loaded) != 0 {
+		t.Errorf("Expected empty history after reset, got %d messages", len(loaded))
+	}
+}
+
+func TestConversation_SendError(t *testing.T) {
+	model := &recordingModel{err: errors.New("boom")}
+	conv := NewConversation("c1", &Client{llmClient: model})
+
+	if _, err := conv.Send(context.Background(), "hi"); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	if history, _ := conv.Messages(context.Background()); len(history) != 0 {
+		t.Errorf("Expected unchanged history, got %v", history)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	in := msgs(RoleSystem, RoleUser, RoleAssistant, RoleUser, RoleAssistant, RoleUser)
+
+	out, err := SlidingWindow(3).Trim(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []Role{RoleSystem, RoleUser, RoleAssistant, RoleUser}
+	if got := roles(out); !slices.Equal(got, want) {
+		t.Errorf("Expected roles %v, got %v", want, got)
+	}
+
+	if out[len(out)-1].Content != in[len(in)-1].Content {
+		t.Error("Expected the latest message to be kept")
+	}
+}
+
+func TestTokenBudget(t *testing.T) {
+	// every message is 5 characters, i.e. 2 estimated tokens
+	in := msgs(RoleSystem, RoleUser, RoleAssistant, RoleUser)
+
+	out, err := TokenBudget(4, nil).Trim(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []Role{RoleSystem, RoleUser}
+	if got := roles(out); !slices.Equal(got, want) {
+		t.Errorf("Expected roles %v, got %v", want, got)
+	}
+
+	// the latest message is kept even if it exceeds the budget
+	out, _ = TokenBudget(0, nil).Trim(context.Background(), in)
+	if len(out) != 2 {
+		t.Errorf("Expected system and latest message, got %v", out)
+	}
+}
+
+func TestSummaryCompaction(t *testing.T) {
+	s := &fixedSummarizer{}
+	strategy := SummaryCompaction(s, 3, 2)
+
+	in := msgs(RoleSystem, RoleUser, RoleAssistant, RoleUser, RoleAssistant)
+
+	out, err := strategy.Trim(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []Role{RoleSystem, RoleSystem, RoleUser, RoleAssistant}
+	if got := roles(out); !slices.Equal(got, want) {
+		t.Fatalf("Expected roles %v, got %v", want, got)
+	}
+
+	if out[1].Content != summaryPrefix+"summary" {
+		t.Errorf("Unexpected summary message: %q", out[1].Content)
+	}
+
+	// below the threshold nothing happens
+	if out, _ := strategy.Trim(context.Background(), out); len(out) != 4 {
+		t.Errorf("Expected no compaction, got %v", out)
+	}
+
+	// later compactions fold in the previous summary
+	out = append(out, msgs(RoleUser, RoleAssistant)...)
+	if _, err := strategy.Trim(context.Background(), out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(s.inputs) != 2 || !strings.HasPrefix(s.inputs[1], "summary\n") {
+		t.Errorf("Expected previous summary in input, got %q", s.inputs)
+	}
+}
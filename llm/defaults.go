@@ -0,0 +1,146 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// OptionTemperature is the Options key for the default sampling
+// temperature. The value must be a float64.
+const OptionTemperature = "temperature"
+
+// Defaults holds the organization-wide LLM settings that services share.
+// It is usually loaded from the service configuration with koanf or from
+// the environment with NewConfigProviderFromEnv.
+type Defaults struct {
+	// Provider is the default LLM provider.
+	Provider Provider `json:"provider" koanf:"provider"`
+	// Model is the default model of the provider.
+	Model string `json:"model" koanf:"model"`
+	// APIKey authenticates with the provider.
+	APIKey string `json:"-" koanf:"apiKey"`
+	// URL is the API endpoint for providers that require one.
+	URL string `json:"url,omitempty" koanf:"url"`
+	// BaseURL is the base URL for providers like Anthropic.
+	BaseURL string `json:"baseUrl,omitempty" koanf:"baseUrl"`
+	// AccountID is the account identifier for providers like Cloudflare.
+	AccountID string `json:"accountId,omitempty" koanf:"accountId"`
+	// MaxTokens is the default response token limit.
+	MaxTokens int `json:"maxTokens,omitempty" koanf:"maxTokens"`
+	// Temperature is the default sampling temperature. Nil leaves the
+	// provider default.
+	Temperature *float64 `json:"temperature,omitempty" koanf:"temperature"`
+	// Options holds provider-specific settings, see WithOption.
+	Options map[string]any `json:"options,omitempty" koanf:"options"`
+}
+
+// ConfigProvider builds client configurations from shared Defaults so that
+// services do not copy provider keys around. It is safe for concurrent use.
+type ConfigProvider struct {
+	defaults Defaults
+}
+
+// NewConfigProvider creates a ConfigProvider with the given defaults.
+func NewConfigProvider(defaults Defaults) *ConfigProvider {
+	defaults.Options = maps.Clone(defaults.Options)
+
+	return &ConfigProvider{defaults: defaults}
+}
+
+// NewConfigProviderFromEnv creates a ConfigProvider from environment
+// variables named <prefix>_PROVIDER, <prefix>_MODEL, <prefix>_API_KEY,
+// <prefix>_URL, <prefix>_BASE_URL, <prefix>_ACCOUNT_ID, <prefix>_MAX_TOKENS
+// and <prefix>_TEMPERATURE. Unset variables are left empty.
+//
+// Example:
+//
+//	// LLM_PROVIDER=openai LLM_MODEL=gpt-4o LLM_API_KEY=sk-...
+//	provider, err := llm.NewConfigProviderFromEnv("LLM")
+func NewConfigProviderFromEnv(prefix string) (*ConfigProvider, error) {
+	env := func(name string) string {
+		return strings.TrimSpace(os.Getenv(prefix + "_" + name))
+	}
+
+	d := Defaults{
+		Provider:  Provider(strings.ToLower(env("PROVIDER"))),
+		Model:     env("MODEL"),
+		APIKey:    env("API_KEY"),
+		URL:       env("URL"),
+		BaseURL:   env("BASE_URL"),
+		AccountID: env("ACCOUNT_ID"),
+	}
+
+	if v := env("MAX_TOKENS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %s_MAX_TOKENS must be a non-negative integer", ErrInvalidDefaults, prefix)
+		}
+
+		d.MaxTokens = n
+	}
+
+	if v := env("TEMPERATURE"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("%w: %s_TEMPERATURE must be a non-negative number", ErrInvalidDefaults, prefix)
+		}
+
+		d.Temperature = &f
+	}
+
+	return NewConfigProvider(d), nil
+}
+
+// Defaults returns a copy of the defaults.
+func (p *ConfigProvider) Defaults() Defaults {
+	d := p.defaults
+	d.Options = maps.Clone(d.Options)
+
+	return d
+}
+
+// Config returns a new Config with the defaults, modified by options. The
+// returned Config is owned by the caller.
+//
+// Example:
+//
+//	cfg := provider.Config(llm.WithModel("gpt-4o-mini"))
+func (p *ConfigProvider) Config(options ...Option) *Config {
+	d := p.defaults
+
+	cfg := &Config{
+		Provider:  d.Provider,
+		Model:     d.Model,
+		APIKey:    d.APIKey,
+		URL:       d.URL,
+		BaseURL:   d.BaseURL,
+		AccountID: d.AccountID,
+		MaxTokens: d.MaxTokens,
+		Options:   make(map[string]any, len(d.Options)+1),
+	}
+
+	maps.Copy(cfg.Options, d.Options)
+
+	if d.Temperature != nil {
+		cfg.Options[OptionTemperature] = *d.Temperature
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	return cfg
+}
+
+// New creates a Client from the defaults modified by options. Use
+// Client.WithOverrides to vary the model or temperature per request
+// instead of creating a client for each request.
+func (p *ConfigProvider) New(options ...Option) (*Client, error) {
+	return New(p.Config(options...))
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// optionsModel is an llms.Model that records the call options of the last call.
+type optionsModel struct {
+	last llms.CallOptions
+}
+
+func (m *optionsModel) GenerateContent(_ context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.last = llms.CallOptions{}
+	for _, o := range options {
+		o(&m.last)
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "answer"}}}, nil
+}
+
+func (m *optionsModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestConfigProvider_Config(t *testing.T) {
+	temperature := 0.2
+	p := NewConfigProvider(Defaults{
+		Provider:    ProviderOpenAI,
+		Model:       "gpt-4o",
+		APIKey:      "sk-test",
+		MaxTokens:   500,
+		Temperature: &temperature,
+		Options:     map[string]any{"organization_id": "org"},
+	})
+
+	cfg := p.Config(WithModel("gpt-4o-mini"), WithOption("organization_id", "other"))
+
+	if cfg.Provider != ProviderOpenAI || cfg.APIKey != "sk-test" || cfg.MaxTokens != 500 {
+		t.Errorf("Expected defaults to be applied, got %+v", cfg)
+	}
+
+	if cfg.Model != "gpt-4o-mini" {
+		t.Errorf("Expected overridden model, got %q", cfg.Model)
+	}
+
+	if cfg.Options[OptionTemperature] != 0.2 {
+		t.Errorf("Expected temperature 0.2, got %v", cfg.Options[OptionTemperature])
+	}
+
+	// options must not leak into the defaults
+	if got := p.Defaults().Options["organization_id"]; got != "org" {
+		t.Errorf("Expected defaults to be unchanged, got %v", got)
+	}
+
+	client, err := p.New()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(client.overrides) != 1 {
+		t.Errorf("Expected the default temperature as override, got %d overrides", len(client.overrides))
+	}
+}
+
+func TestNewConfigProviderFromEnv(t *testing.T) {
+	t.Setenv("TEST_LLM_PROVIDER", "Anthropic")
+	t.Setenv("TEST_LLM_MODEL", "claude-3-haiku")
+	t.Setenv("TEST_LLM_API_KEY", " sk-ant ")
+	t.Setenv("TEST_LLM_MAX_TOKENS", "1000")
+	t.Setenv("TEST_LLM_TEMPERATURE", "0.7")
+
+	p, err := NewConfigProviderFromEnv("TEST_LLM")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	d := p.Defaults()
+	if d.Provider != ProviderAnthropic || d.Model != "claude-3-haiku" || d.APIKey != "sk-ant" || d.MaxTokens != 1000 {
+		t.Errorf("Unexpected defaults %+v", d)
+	}
+
+	if d.Temperature == nil || *d.Temperature != 0.7 {
+		t.Errorf("Expected temperature 0.7, got %v", d.Temperature)
+	}
+
+	t.Setenv("TEST_LLM_TEMPERATURE", "warm")
+
+	if _, err := NewConfigProviderFromEnv("TEST_LLM"); !errors.Is(err, ErrInvalidDefaults) {
+		t.Errorf("Expected ErrInvalidDefaults, got %v", err)
+	}
+}
+
+func TestClient_WithOverrides(t *testing.T) {
+	model := &optionsModel{}
+	base := &Client{llmClient: model, overrides: []llms.CallOption{llms.WithTemperature(0.2)}}
+
+	precise := base.WithOverrides(llms.WithModel("gpt-4o"), llms.WithTemperature(0))
+
+	if _, err := precise.Generate(context.Background(), "prompt"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if model.last.Model != "gpt-4o" || model.last.Temperature != 0 {
+		t.Errorf("Expected overrides to apply, got model %q temperature %v", model.last.Model, model.last.Temperature)
+	}
+
+	// call options take precedence over overrides
+	if _, _, err := precise.GenerateWithUsage(context.Background(), "prompt", llms.WithModel("gpt-4o-mini")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if model.last.Model != "gpt-4o-mini" {
+		t.Errorf("Expected call option to win, got model %q", model.last.Model)
+	}
+
+	// the base client is unchanged
+	if _, err := base.Generate(context.Background(), "prompt"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if model.last.Model != "" || model.last.Temperature != 0.2 {
+		t.Errorf("Expected base client defaults, got model %q temperature %v", model.last.Model, model.last.Temperature)
+	}
+}
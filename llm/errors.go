@@ -10,4 +10,16 @@ var (
 	ErrConfigRequired      = errors.New("config must not be nil")
 	ErrUnsupportedProvider = errors.New("unsupported llm provider")
 	ErrInvalidCredentials  = errors.New("invalid credentials provided")
+	ErrPromptInjection     = errors.New("prompt rejected: possible prompt injection")
+	ErrInvalidABConfig     = errors.New("invalid A/B router configuration")
+	ErrEmptyResponse       = errors.New("empty response from model")
+	ErrInputTooLarge       = errors.New("prompt exceeds the input limit")
+	ErrInvalidInputLimit   = errors.New("invalid input limit")
+	ErrInvalidDefaults     = errors.New("invalid llm defaults")
+	ErrUnsupportedFeature  = errors.New("feature not supported by llm provider")
+	ErrInvalidJSONResponse = errors.New("model response is not valid JSON")
+	ErrBudgetNotRecorded   = errors.New("cost of the request could not be recorded in the budget")
+
+	ErrRecordingNotFound      = errors.New("no recorded LLM response for request")
+	ErrRecordingSchemaVersion = errors.New("unsupported LLM recording schema version")
 )
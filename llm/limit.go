@@ -0,0 +1,203 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// TruncationStrategy determines what happens to a prompt that exceeds the
+// InputLimit.
+type TruncationStrategy string
+
+const (
+	// TruncateError rejects oversized prompts with ErrInputTooLarge.
+	TruncateError TruncationStrategy = "error"
+	// TruncateHead drops the beginning of the prompt and keeps its end, e.g.
+	// for logs where the latest entries matter most.
+	TruncateHead TruncationStrategy = "head"
+	// TruncateTail drops the end of the prompt and keeps its beginning.
+	TruncateTail TruncationStrategy = "tail"
+	// TruncateMiddle drops the middle of the prompt and keeps equal parts of
+	// its beginning and end, which usually hold the instructions and the
+	// question.
+	TruncateMiddle TruncationStrategy = "middle"
+)
+
+// DefaultTruncationMarker is inserted where text was removed.
+const DefaultTruncationMarker = "\n[...]\n"
+
+// InputLimit bounds the size of prompts sent to the provider.
+type InputLimit struct {
+	// MaxTokens is the maximum number of prompt tokens. Zero disables the
+	// limit.
+	MaxTokens int
+	// Strategy is applied to prompts above MaxTokens. Defaults to
+	// TruncateError.
+	Strategy TruncationStrategy
+	// Counter counts the tokens of a prompt. Defaults to EstimateTokens.
+	Counter TokenCounter
+	// Marker is inserted where text was removed and counts towards
+	// MaxTokens. Defaults to DefaultTruncationMarker.
+	Marker string
+}
+
+// Truncation reports how a prompt was shortened to fit the InputLimit.
+type Truncation struct {
+	// Strategy is the applied truncation strategy.
+	Strategy TruncationStrategy
+	// OriginalTokens is the token count of the prompt before truncation.
+	OriginalTokens int
+	// Tokens is the token count of the prompt that was sent.
+	Tokens int
+	// RemovedChars is the number of characters removed from the prompt.
+	RemovedChars int
+}
+
+// Response is the result of GenerateResponse.
+type Response struct {
+	// Content is the generated text.
+	Content string
+	// Usage is the token usage reported by the provider.
+	Usage TokenUsage
+	// Truncation is set if the prompt was truncated to fit the input limit.
+	Truncation *Truncation
+	// Budget is the budget status of the tenant if a BudgetManager was
+	// consulted. Budget.SoftLimitExceeded reports a crossed soft limit.
+	Budget *BudgetStatus
+}
+
+// WithInputLimit sets the default InputLimit applied to every prompt.
+// Passing nil removes the limit.
+func (c *Client) WithInputLimit(limit *InputLimit) *Client {
+	c.limit = limit
+	return c
+}
+
+// GenerateResponse generates text like GenerateWithUsage, enforcing limit
+// instead of the client's default input limit. A nil limit uses the
+// default. The response reports whether and how the prompt was truncated.
+//
+// Example:
+//
+//	resp, err := client.GenerateResponse(ctx, prompt, &llm.InputLimit{
+//		MaxTokens: 8000,
+//		Strategy:  llm.TruncateMiddle,
+//	})
+//	if resp.Truncation != nil {
+//		log.Warn().Int("original_tokens", resp.Truncation.OriginalTokens).Msg("prompt truncated")
+//	}
+func (c *Client) GenerateResponse(ctx context.Context, prompt string, limit *InputLimit, options ...llms.CallOption) (*Response, error) {
+	if limit == nil {
+		limit = c.limit
+	}
+
+	prompt, truncation, err := c.prepare(prompt, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := c.checkBudget(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	content, usage, err := c.generateContent(ctx, prompt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &Response{Content: content, Usage: usage, Truncation: truncation, Budget: budget}
+
+	return resp, c.recordBudget(ctx, prompt, content, usage)
+}
+
+// prepare applies the prompt guard and the input limit to prompt.
+func (c *Client) prepare(prompt string, limit *InputLimit) (string, *Truncation, error) {
+	if c.guard != nil {
+		guarded, _, err := c.guard.Apply(prompt)
+		if err != nil {
+			return "", nil, err
+		}
+
+		prompt = guarded
+	}
+
+	return limit.Apply(prompt)
+}
+
+// Apply enforces the limit on prompt. It returns the prompt to send and a
+// Truncation report if the prompt was shortened. A nil limit or a zero
+// MaxTokens leaves the prompt unchanged.
+//
+// Returns ErrInputTooLarge if the prompt exceeds the limit and the strategy
+// is TruncateError, or if not even the marker fits, and
+// ErrInvalidInputLimit for an unknown strategy.
+func (l *InputLimit) Apply(prompt string) (string, *Truncation, error) {
+	if l == nil || l.MaxTokens <= 0 {
+		return prompt, nil, nil
+	}
+
+	counter := l.Counter
+	if counter == nil {
+		counter = EstimateTokens
+	}
+
+	tokens := counter(prompt)
+	if tokens <= l.MaxTokens {
+		return prompt, nil, nil
+	}
+
+	strategy := l.Strategy
+	if strategy == "" {
+		strategy = TruncateError
+	}
+
+	marker := l.Marker
+	if marker == "" {
+		marker = DefaultTruncationMarker
+	}
+
+	runes := []rune(prompt)
+	n := len(runes)
+
+	var build func(keep int) string
+
+	switch strategy {
+	case TruncateError:
+		return "", nil, fmt.Errorf("%w: %d tokens exceed the limit of %d", ErrInputTooLarge, tokens, l.MaxTokens)
+	case TruncateHead:
+		build = func(keep int) string { return marker + string(runes[n-keep:]) }
+	case TruncateTail:
+		build = func(keep int) string { return string(runes[:keep]) + marker }
+	case TruncateMiddle:
+		build = func(keep int) string {
+			head := (keep + 1) / 2
+			return string(runes[:head]) + marker + string(runes[n-(keep-head):])
+		}
+	default:
+		return "", nil, fmt.Errorf("%w: unknown truncation strategy %q", ErrInvalidInputLimit, strategy)
+	}
+
+	// The largest number of kept characters whose result fits the limit.
+	keep := sort.Search(n+1, func(k int) bool {
+		return counter(build(k)) > l.MaxTokens
+	}) - 1
+	if keep < 0 {
+		return "", nil, fmt.Errorf("%w: truncation marker exceeds the limit of %d tokens", ErrInputTooLarge, l.MaxTokens)
+	}
+
+	truncated := build(keep)
+
+	return truncated, &Truncation{
+		Strategy:       strategy,
+		OriginalTokens: tokens,
+		Tokens:         counter(truncated),
+		RemovedChars:   n - keep,
+	}, nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// promptModel is an llms.Model that records the last prompt.
+type promptModel struct {
+	prompt string
+}
+
+func (m *promptModel) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.prompt = messages[0].Parts[0].(llms.TextContent).Text
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		Content:        "answer",
+		GenerationInfo: map[string]any{"PromptTokens": 3, "CompletionTokens": 1},
+	}}}, nil
+}
+
+func (m *promptModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// runeCounter counts one token per character.
+func runeCounter(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+func TestInputLimit_Apply(t *testing.T) {
+	prompt := "0123456789abcdefghij"
+
+	tests := []struct {
+		name     string
+		limit    *InputLimit
+		want     string
+		wantCut  int
+		wantErr  error
+		notTrunc bool
+	}{
+		{name: "no limit", limit: nil, want: prompt, notTrunc: true},
+		{name: "within limit", limit: &InputLimit{MaxTokens: 20, Counter: runeCounter}, want: prompt, notTrunc: true},
+		{name: "error", limit: &InputLimit{MaxTokens: 10, Counter: runeCounter}, wantErr: ErrInputTooLarge},
+		{name: "head", limit: &InputLimit{MaxTokens: 10, Strategy: TruncateHead, Counter: runeCounter, Marker: ".."}, want: "..cdefghij", wantCut: 12},
+		{name: "tail", limit: &InputLimit{MaxTokens: 10, Strategy: TruncateTail, Counter: runeCounter, Marker: ".."}, want: "01234567..", wantCut: 12},
+		{name: "middle", limit: &InputLimit{MaxTokens: 9, Strategy: TruncateMiddle, Counter: runeCounter, Marker: ".."}, want: "0123..hij", wantCut: 13},
+		{name: "marker too long", limit: &InputLimit{MaxTokens: 1, Strategy: TruncateTail, Counter: runeCounter, Marker: ".."}, wantErr: ErrInputTooLarge},
+		{name: "unknown strategy", limit: &InputLimit{MaxTokens: 1, Strategy: "random", Counter: runeCounter}, wantErr: ErrInvalidInputLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncation, err := tt.limit.Apply(prompt)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+
+			if tt.notTrunc {
+				if truncation != nil {
+					t.Errorf("Expected no truncation, got %+v", truncation)
+				}
+
+				return
+			}
+
+			if truncation == nil {
+				t.Fatal("Expected a truncation report")
+			}
+
+			if truncation.Strategy != tt.limit.Strategy || truncation.OriginalTokens != 20 ||
+				truncation.Tokens != len(tt.want) || truncation.RemovedChars != tt.wantCut {
+				t.Errorf("Unexpected truncation report %+v", truncation)
+			}
+		})
+	}
+}
+
+func TestInputLimit_DefaultCounter(t *testing.T) {
+	limit := &InputLimit{MaxTokens: 100, Strategy: TruncateMiddle}
+
+	got, truncation, err := limit.Apply(strings.Repeat("word ", 200))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if EstimateTokens(got) > 100 || truncation.Tokens != EstimateTokens(got) {
+		t.Errorf("Expected at most 100 tokens, got %d", EstimateTokens(got))
+	}
+
+	if !strings.Contains(got, DefaultTruncationMarker) {
+		t.Error("Expected the default marker")
+	}
+}
+
+func TestClient_GenerateResponse(t *testing.T) {
+	model := &promptModel{}
+	c := (&Client{llmClient: model}).WithInputLimit(&InputLimit{MaxTokens: 5, Counter: runeCounter})
+
+	if _, err := c.Generate(context.Background(), "too long"); !errors.Is(err, ErrInputTooLarge) {
+		t.Fatalf("Expected ErrInputTooLarge from the default limit, got %v", err)
+	}
+
+	if _, _, err := c.GenerateWithUsage(context.Background(), "too long"); !errors.Is(err, ErrInputTooLarge) {
+		t.Fatalf("Expected ErrInputTooLarge from the default limit, got %v", err)
+	}
+
+	resp, err := c.GenerateResponse(context.Background(), "abcdefghij", &InputLimit{
+		MaxTokens: 6,
+		Strategy:  TruncateTail,
+		Counter:   runeCounter,
+		Marker:    "~",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if model.prompt != "abcde~" {
+		t.Errorf("Expected the truncated prompt to be sent, got %q", model.prompt)
+	}
+
+	if resp.Content != "answer" || resp.Usage.TotalTokens != 4 {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+
+	if resp.Truncation == nil || resp.Truncation.RemovedChars != 5 {
+		t.Errorf("Unexpected truncation %+v", resp.Truncation)
+	}
+
+	resp, err = c.GenerateResponse(context.Background(), "short", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.Truncation != nil {
+		t.Errorf("Expected no truncation, got %+v", resp.Truncation)
+	}
+}
@@ -40,6 +40,10 @@ func newAnthropicClient(cfg *Config) (llms.Model, error) {
 		opts = append(opts, anthropic.WithLegacyTextCompletionsAPI())
 	}
 
+	if cfg.HTTPClient != nil {
+		opts = append(opts, anthropic.WithHTTPClient(cfg.HTTPClient))
+	}
+
 	return anthropic.New(opts...)
 }
 
@@ -63,6 +67,10 @@ func newCloudflareClient(cfg *Config) (llms.Model, error) {
 		opts = append(opts, cloudflare.WithServerURL(cfg.URL))
 	}
 
+	if cfg.HTTPClient != nil {
+		opts = append(opts, cloudflare.WithHTTPClient(cfg.HTTPClient))
+	}
+
 	return cloudflare.New(opts...)
 }
 
@@ -111,6 +119,10 @@ func newGeminiClient(cfg *Config) (llms.Model, error) {
 		}
 	}
 
+	if cfg.HTTPClient != nil {
+		opts = append(opts, googleai.WithHTTPClient(cfg.HTTPClient))
+	}
+
 	return googleai.New(context.Background(), opts...)
 }
 
@@ -145,6 +157,10 @@ func newOllamaClient(cfg *Config) (llms.Model, error) {
 		opts = append(opts, ollama.WithServerURL(cfg.URL))
 	}
 
+	if cfg.HTTPClient != nil {
+		opts = append(opts, ollama.WithHTTPClient(cfg.HTTPClient))
+	}
+
 	return ollama.New(opts...)
 }
 
@@ -191,5 +207,9 @@ func newOpenAIClient(cfg *Config) (llms.Model, error) {
 		}
 	}
 
+	if cfg.HTTPClient != nil {
+		opts = append(opts, openai.WithHTTPClient(cfg.HTTPClient))
+	}
+
 	return openai.New(opts...)
 }
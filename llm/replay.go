@@ -0,0 +1,293 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RecordingSchemaVersion is the version of the golden file format written by
+// RecordingTransport. It must be bumped whenever the format or the request
+// normalization changes; ReplayTransport rejects files of other versions, so
+// that stale recordings are re-recorded instead of silently misread.
+const RecordingSchemaVersion = 1
+
+// recordingFileExt is the extension of golden files.
+const recordingFileExt = ".json"
+
+// recordedHeaders are the response headers kept in golden files. Other
+// headers carry request IDs, rate limits or organization names that are not
+// needed for replay.
+var recordedHeaders = []string{"Content-Type"}
+
+// sensitiveQueryParams are removed from recorded URLs and request keys, as
+// some providers pass API keys in the query string.
+var sensitiveQueryParams = []string{"key", "api_key", "apikey", "token"}
+
+// Recording is a single recorded request/response pair, stored as golden
+// file named after its Key.
+type Recording struct {
+	// SchemaVersion is the RecordingSchemaVersion the file was written with.
+	SchemaVersion int `json:"schema_version"`
+	// Key is the normalized request hash; see RequestKey.
+	Key string `json:"key"`
+	// Request is the normalized request.
+	Request RecordedRequest `json:"request"`
+	// Response is the response returned by the provider.
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the normalized form of a recorded request. Headers are
+// not recorded, so that credentials never end up in golden files.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	// URL is the path and query without host and sensitive parameters.
+	URL string `json:"url"`
+	// Body is the request body; JSON bodies are stored with sorted keys.
+	Body string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// RequestKey returns the hash identifying req in golden files, along with
+// the normalized request it is computed from. The key covers the method,
+// the path and query without host and sensitive parameters, and the body
+// with JSON object keys sorted; headers are ignored. Requests that differ
+// only in endpoint, credentials or JSON key order therefore share a key.
+// The body of req is consumed and replaced, so req can still be sent.
+func RequestKey(req *http.Request) (string, RecordedRequest, error) {
+	var body []byte
+
+	if req.Body != nil {
+		var err error
+
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return "", RecordedRequest{}, err
+		}
+
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	normalized := RecordedRequest{
+		Method: req.Method,
+		URL:    normalizeURL(req.URL),
+		Body:   normalizeBody(body),
+	}
+
+	sum := sha256.Sum256([]byte(normalized.Method + "\n" + normalized.URL + "\n" + normalized.Body))
+
+	return hex.EncodeToString(sum[:]), normalized, nil
+}
+
+// normalizeURL returns the path and sorted query of u without sensitive
+// parameters.
+func normalizeURL(u *url.URL) string {
+	query := u.Query()
+	for _, p := range sensitiveQueryParams {
+		query.Del(p)
+	}
+
+	if encoded := query.Encode(); encoded != "" {
+		return u.EscapedPath() + "?" + encoded
+	}
+
+	return u.EscapedPath()
+}
+
+// normalizeBody re-encodes JSON bodies with sorted keys; other bodies are
+// returned as is.
+func normalizeBody(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(normalized)
+}
+
+// recordingPath returns the golden file of key in dir.
+func recordingPath(dir, key string) string {
+	return filepath.Join(dir, key+recordingFileExt)
+}
+
+// RecordingTransport is an http.RoundTripper that sends requests with Next
+// and stores every request/response pair as golden file in Dir.
+type RecordingTransport struct {
+	// Dir is the directory golden files are written to. It is created if
+	// it does not exist.
+	Dir string
+	// Next sends the requests; http.DefaultTransport if nil.
+	Next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, normalized, err := RequestKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec := Recording{
+		SchemaVersion: RecordingSchemaVersion,
+		Key:           key,
+		Request:       normalized,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     make(http.Header),
+			Body:       string(body),
+		},
+	}
+
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Values(h); len(v) > 0 {
+			rec.Response.Header[h] = v
+		}
+	}
+
+	if err := writeRecording(t.Dir, rec); err != nil {
+		return nil, fmt.Errorf("failed to record LLM response: %w", err)
+	}
+
+	return resp, nil
+}
+
+// writeRecording stores rec as indented JSON, so that diffs of golden files
+// stay readable.
+func writeRecording(dir string, rec Recording) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:mnd
+		return err
+	}
+
+	return os.WriteFile(recordingPath(dir, rec.Key), append(data, '\n'), 0o644) //nolint:gosec,mnd
+}
+
+// ReplayTransport is an http.RoundTripper that answers requests from the
+// golden files in Dir without network access. Requests without recording
+// fail with ErrRecordingNotFound.
+type ReplayTransport struct {
+	// Dir is the directory golden files are read from.
+	Dir string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, normalized, err := RequestKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(recordingPath(t.Dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s (key %s)", ErrRecordingNotFound, normalized.Method, normalized.URL, key)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var rec Recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("invalid recording %s: %w", key, err)
+	}
+
+	if rec.SchemaVersion != RecordingSchemaVersion {
+		return nil, fmt.Errorf("%w: recording %s has version %d, want %d",
+			ErrRecordingSchemaVersion, key, rec.SchemaVersion, RecordingSchemaVersion)
+	}
+
+	header := rec.Response.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Response.StatusCode, http.StatusText(rec.Response.StatusCode)),
+		StatusCode:    rec.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(rec.Response.Body)),
+		ContentLength: int64(len(rec.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// ReplayMode selects whether NewReplayHTTPClient records or replays.
+type ReplayMode string
+
+const (
+	// ReplayModeReplay serves requests from golden files.
+	ReplayModeReplay ReplayMode = "replay"
+	// ReplayModeRecord sends requests to the provider and updates the
+	// golden files.
+	ReplayModeRecord ReplayMode = "record"
+)
+
+// NewReplayHTTPClient returns an HTTP client that records to or replays
+// from the golden files in dir. Pass it to WithHTTPClient.
+//
+// Example:
+//
+//	mode := llm.ReplayModeReplay
+//	if os.Getenv("LLM_RECORD") != "" {
+//		mode = llm.ReplayModeRecord
+//	}
+//
+//	client, err := llm.New(llm.NewConfig(
+//		llm.WithOpenAI("gpt-4", os.Getenv("OPENAI_API_KEY")),
+//		llm.WithHTTPClient(llm.NewReplayHTTPClient("testdata/llm", mode)),
+//	))
+func NewReplayHTTPClient(dir string, mode ReplayMode) *http.Client {
+	if mode == ReplayModeRecord {
+		return &http.Client{Transport: &RecordingTransport{Dir: dir}}
+	}
+
+	return &http.Client{Transport: &ReplayTransport{Dir: dir}}
+}
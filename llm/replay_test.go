@@ -0,0 +1,175 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const chatCompletion = `{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"gpt-4",` +
+	`"choices":[{"index":0,"message":{"role":"assistant","content":"recorded answer"},"finish_reason":"stop"}],` +
+	`"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`
+
+func newReplayTestClient(t *testing.T, url string, httpClient *http.Client) *Client {
+	t.Helper()
+
+	client, err := New(NewConfig(
+		WithOpenAI("gpt-4", "sk-secret", WithURL(url)),
+		WithHTTPClient(httpClient),
+	))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	return client
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		_, _ = w.Write([]byte(chatCompletion))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	recorder := newReplayTestClient(t, srv.URL, NewReplayHTTPClient(dir, ReplayModeRecord))
+
+	got, err := recorder.Generate(ctx, "Say hello")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if got != "recorded answer" {
+		t.Errorf("Generate() = %q, want %q", got, "recorded answer")
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("recorded %d files, want 1", len(files))
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("Failed to read recording: %v", err)
+	}
+
+	if bytes.Contains(data, []byte("sk-secret")) {
+		t.Error("recording contains the API key")
+	}
+
+	if bytes.Contains(data, []byte("req-123")) {
+		t.Error("recording contains unrecorded response headers")
+	}
+
+	// Replay against another endpoint; the server must not be contacted.
+	replayer := newReplayTestClient(t, "http://replay.invalid", NewReplayHTTPClient(dir, ReplayModeReplay))
+
+	got, err = replayer.Generate(ctx, "Say hello")
+	if err != nil {
+		t.Fatalf("replayed Generate() error = %v", err)
+	}
+
+	if got != "recorded answer" {
+		t.Errorf("replayed Generate() = %q, want %q", got, "recorded answer")
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("server called %d times, want 1", calls.Load())
+	}
+
+	_, err = replayer.Generate(ctx, "Say goodbye")
+	if !errors.Is(err, ErrRecordingNotFound) {
+		t.Errorf("Generate() error = %v, want ErrRecordingNotFound", err)
+	}
+}
+
+func TestRequestKey(t *testing.T) {
+	newRequest := func(url, body string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+url)
+
+		return req
+	}
+
+	key, normalized, err := RequestKey(newRequest("https://a.example/v1/chat?key=secret&b=2&a=1", `{"model":"x","messages":[]}`))
+	if err != nil {
+		t.Fatalf("RequestKey() error = %v", err)
+	}
+
+	if normalized.URL != "/v1/chat?a=1&b=2" {
+		t.Errorf("normalized URL = %q", normalized.URL)
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		body string
+		same bool
+	}{
+		{name: "other host and key order", url: "https://b.example/v1/chat?a=1&b=2&key=other", body: `{"messages":[],"model":"x"}`, same: true},
+		{name: "other body", url: "https://a.example/v1/chat?a=1&b=2", body: `{"model":"y","messages":[]}`},
+		{name: "other path", url: "https://a.example/v1/embed?a=1&b=2", body: `{"model":"x","messages":[]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest(tt.url, tt.body)
+
+			got, _, err := RequestKey(req)
+			if err != nil {
+				t.Fatalf("RequestKey() error = %v", err)
+			}
+
+			if (got == key) != tt.same {
+				t.Errorf("RequestKey() same = %v, want %v", got == key, tt.same)
+			}
+
+			// the body must still be readable after hashing
+			var buf bytes.Buffer
+			if _, err := buf.ReadFrom(req.Body); err != nil || buf.String() != tt.body {
+				t.Errorf("body after RequestKey() = %q, %v", buf.String(), err)
+			}
+		})
+	}
+}
+
+func TestReplayTransport_SchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	req, _ := http.NewRequest(http.MethodPost, "https://a.example/v1/chat", strings.NewReader(`{}`))
+
+	key, normalized, err := RequestKey(req)
+	if err != nil {
+		t.Fatalf("RequestKey() error = %v", err)
+	}
+
+	rec := Recording{SchemaVersion: RecordingSchemaVersion + 1, Key: key, Request: normalized}
+	if err := writeRecording(dir, rec); err != nil {
+		t.Fatalf("writeRecording() error = %v", err)
+	}
+
+	_, err = (&ReplayTransport{Dir: dir}).RoundTrip(req)
+	if !errors.Is(err, ErrRecordingSchemaVersion) {
+		t.Errorf("RoundTrip() error = %v, want ErrRecordingSchemaVersion", err)
+	}
+}
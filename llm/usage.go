@@ -0,0 +1,103 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package llm
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// TokenUsage reports the tokens consumed by a generation request.
+type TokenUsage struct {
+	// PromptTokens is the number of tokens in the prompt.
+	PromptTokens int
+	// CompletionTokens is the number of tokens in the generated text.
+	CompletionTokens int
+	// TotalTokens is the sum of prompt and completion tokens.
+	TotalTokens int
+}
+
+// Add returns the sum of u and o.
+func (u TokenUsage) Add(o TokenUsage) TokenUsage {
+	return TokenUsage{
+		PromptTokens:     u.PromptTokens + o.PromptTokens,
+		CompletionTokens: u.CompletionTokens + o.CompletionTokens,
+		TotalTokens:      u.TotalTokens + o.TotalTokens,
+	}
+}
+
+// GenerateWithUsage is like GenerateWithOptions but also reports the tokens
+// consumed. Providers that do not report usage yield a zero TokenUsage.
+func (c *Client) GenerateWithUsage(ctx context.Context, prompt string, options ...llms.CallOption) (string, TokenUsage, error) {
+	prompt, _, err := c.prepare(prompt, c.limit)
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+
+	if _, err := c.checkBudget(ctx, prompt); err != nil {
+		return "", TokenUsage{}, err
+	}
+
+	content, usage, err := c.generateContent(ctx, prompt, options...)
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+
+	return content, usage, c.recordBudget(ctx, prompt, content, usage)
+}
+
+// generateContent sends prompt as a single user message and returns the
+// first choice with its token usage.
+func (c *Client) generateContent(ctx context.Context, prompt string, options ...llms.CallOption) (string, TokenUsage, error) {
+	resp, err := c.llmClient.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	}, c.callOptions(options)...)
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", TokenUsage{}, ErrEmptyResponse
+	}
+
+	choice := resp.Choices[0]
+
+	return choice.Content, usageFromGenerationInfo(choice.GenerationInfo), nil
+}
+
+// usageFromGenerationInfo extracts the token usage from the provider specific
+// generation info. OpenAI style providers report prompt and completion
+// tokens, Anthropic reports input and output tokens.
+func usageFromGenerationInfo(info map[string]any) TokenUsage {
+	u := TokenUsage{
+		PromptTokens:     intValue(info, "PromptTokens", "InputTokens"),
+		CompletionTokens: intValue(info, "CompletionTokens", "OutputTokens"),
+		TotalTokens:      intValue(info, "TotalTokens"),
+	}
+
+	if u.TotalTokens == 0 {
+		u.TotalTokens = u.PromptTokens + u.CompletionTokens
+	}
+
+	return u
+}
+
+// intValue returns the first of keys present in info as int.
+func intValue(info map[string]any, keys ...string) int {
+	for _, k := range keys {
+		switch v := info[k].(type) {
+		case int:
+			return v
+		case int32:
+			return int(v)
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+	}
+
+	return 0
+}
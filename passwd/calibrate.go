@@ -0,0 +1,155 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package passwd
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// ===========================================================================
+// Parameter Calibration
+// ===========================================================================
+
+const (
+	// Argon2MinCalibrationMemory is the lowest memory cost (in KiB) CalibrateArgon2
+	// will recommend. It follows the OWASP minimum of 19 MiB for Argon2id.
+	Argon2MinCalibrationMemory = 19 * 1024
+	// Argon2MaxCalibrationTime is the highest number of iterations
+	// CalibrateArgon2 will recommend.
+	Argon2MaxCalibrationTime = 16
+
+	// calibrationSamples is the number of hashes measured per candidate; the
+	// fastest run is used to filter out scheduling noise.
+	calibrationSamples = 3
+)
+
+// calibrationPassword is the input hashed while benchmarking.
+var calibrationPassword = []byte("calibration-password") //nolint:gosec // not a credential
+
+// measureFunc returns the time needed to derive one key with config.
+type measureFunc func(config Argon2Config) time.Duration
+
+// CalibrateArgon2 benchmarks Argon2id on the current host and returns a
+// configuration whose hash time is as close as possible to, but not above,
+// targetDuration.
+//
+// Memory hardness is preferred over iterations: the memory cost starts at
+// maxMemory (in KiB) and is halved until a single iteration fits the target,
+// but not below Argon2MinCalibrationMemory. Remaining headroom is then spent
+// on additional iterations. If even the minimum configuration exceeds the
+// target, that minimum configuration is returned.
+//
+// Calibration hashes several times and blocks for a multiple of
+// targetDuration, so run it at startup or in the background, not per request.
+//
+// Example:
+//
+//	cfg, err := passwd.CalibrateArgon2(250*time.Millisecond, 256*1024)
+//	if err != nil {
+//	    // handle error
+//	}
+//	dk, err := passwd.CreateDerivedKeyWithConfig(password, cfg)
+func CalibrateArgon2(targetDuration time.Duration, maxMemory uint32) (Argon2Config, error) {
+	return calibrateArgon2(targetDuration, maxMemory, measureArgon2)
+}
+
+func calibrateArgon2(target time.Duration, maxMemory uint32, measure measureFunc) (Argon2Config, error) {
+	if target <= 0 || maxMemory < Argon2MinCalibrationMemory {
+		return Argon2Config{}, ErrInvalidCalibration
+	}
+
+	config := DefaultArgon2Config()
+	config.Time = 1
+	config.Memory = maxMemory
+
+	elapsed := measure(config)
+
+	for elapsed > target && config.Memory/2 >= Argon2MinCalibrationMemory {
+		config.Memory /= 2
+		elapsed = measure(config)
+	}
+
+	for elapsed < target && config.Time < Argon2MaxCalibrationTime {
+		next := config
+		next.Time++
+
+		nextElapsed := measure(next)
+		if nextElapsed > target {
+			break
+		}
+
+		config, elapsed = next, nextElapsed
+	}
+
+	return config, nil
+}
+
+// WatchArgon2Calibration re-runs CalibrateArgon2 every interval until ctx is
+// canceled and calls onChange whenever the recommended configuration differs
+// from the previous one. The first calibration runs immediately.
+//
+// Use it to pick up hardware changes (e.g. after a resize) and to decide when
+// stored derived keys should be rehashed on the next successful login.
+func WatchArgon2Calibration(
+	ctx context.Context,
+	interval, targetDuration time.Duration,
+	maxMemory uint32,
+	onChange func(Argon2Config),
+) error {
+	return watchArgon2Calibration(ctx, interval, targetDuration, maxMemory, onChange, measureArgon2)
+}
+
+func watchArgon2Calibration(
+	ctx context.Context,
+	interval, target time.Duration,
+	maxMemory uint32,
+	onChange func(Argon2Config),
+	measure measureFunc,
+) error {
+	if interval <= 0 || onChange == nil {
+		return ErrInvalidCalibration
+	}
+
+	var current Argon2Config
+
+	for {
+		config, err := calibrateArgon2(target, maxMemory, measure)
+		if err != nil {
+			return err
+		}
+
+		if config != current {
+			current = config
+			onChange(config)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// measureArgon2 returns the fastest of calibrationSamples key derivations.
+func measureArgon2(config Argon2Config) time.Duration {
+	salt := make([]byte, config.SaltLen)
+
+	var fastest time.Duration
+
+	for i := 0; i < calibrationSamples; i++ {
+		start := time.Now()
+
+		argon2.IDKey(calibrationPassword, salt, config.Time, config.Memory, config.Threads, config.KeyLen)
+
+		if elapsed := time.Since(start); i == 0 || elapsed < fastest {
+			fastest = elapsed
+		}
+	}
+
+	return fastest
+}
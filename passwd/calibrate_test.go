@@ -0,0 +1,160 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package passwd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// linearCost models a host on which one iteration over 1 MiB takes 1ms.
+func linearCost(config Argon2Config) time.Duration {
+	return time.Duration(config.Memory/1024) * time.Duration(config.Time) * time.Millisecond
+}
+
+func TestCalibrateArgon2(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     time.Duration
+		maxMemory  uint32
+		wantMemory uint32
+		wantTime   uint32
+	}{
+		{
+			name:       "reduces memory to fit target",
+			target:     100 * time.Millisecond,
+			maxMemory:  256 * 1024,
+			wantMemory: 64 * 1024,
+			wantTime:   1,
+		},
+		{
+			name:       "adds iterations to use headroom",
+			target:     300 * time.Millisecond,
+			maxMemory:  64 * 1024,
+			wantMemory: 64 * 1024,
+			wantTime:   4,
+		},
+		{
+			name:       "does not go below minimum memory",
+			target:     time.Millisecond,
+			maxMemory:  64 * 1024,
+			wantMemory: 32 * 1024,
+			wantTime:   1,
+		},
+		{
+			name:       "caps iterations",
+			target:     time.Hour,
+			maxMemory:  32 * 1024,
+			wantMemory: 32 * 1024,
+			wantTime:   Argon2MaxCalibrationTime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := calibrateArgon2(tt.target, tt.maxMemory, linearCost)
+			if err != nil {
+				t.Fatalf("calibrateArgon2() error = %v", err)
+			}
+
+			if config.Memory != tt.wantMemory {
+				t.Errorf("Memory = %v, want %v", config.Memory, tt.wantMemory)
+			}
+
+			if config.Time != tt.wantTime {
+				t.Errorf("Time = %v, want %v", config.Time, tt.wantTime)
+			}
+
+			if config.Threads == 0 || config.KeyLen == 0 || config.SaltLen == 0 {
+				t.Errorf("config = %+v, want defaults for threads, key and salt length", config)
+			}
+		})
+	}
+}
+
+func TestCalibrateArgon2_InvalidArguments(t *testing.T) {
+	if _, err := CalibrateArgon2(0, 64*1024); !errors.Is(err, ErrInvalidCalibration) {
+		t.Errorf("CalibrateArgon2(0) error = %v, want %v", err, ErrInvalidCalibration)
+	}
+
+	if _, err := CalibrateArgon2(time.Second, 1024); !errors.Is(err, ErrInvalidCalibration) {
+		t.Errorf("CalibrateArgon2(low memory) error = %v, want %v", err, ErrInvalidCalibration)
+	}
+}
+
+func TestCalibrateArgon2_Host(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping benchmark-based test in short mode")
+	}
+
+	config, err := CalibrateArgon2(5*time.Millisecond, Argon2MinCalibrationMemory)
+	if err != nil {
+		t.Fatalf("CalibrateArgon2() error = %v", err)
+	}
+
+	dk, err := CreateDerivedKeyWithConfig("password", config)
+	if err != nil {
+		t.Fatalf("CreateDerivedKeyWithConfig() error = %v", err)
+	}
+
+	if ok, err := VerifyDerivedKey(dk, "password"); err != nil || !ok {
+		t.Errorf("VerifyDerivedKey() = %v, %v, want true, nil", ok, err)
+	}
+}
+
+func TestWatchArgon2Calibration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		calls  int
+		slower bool
+	)
+
+	// The host gets twice as slow after the first round.
+	measure := func(config Argon2Config) time.Duration {
+		d := linearCost(config)
+		if slower {
+			d *= 2
+		}
+
+		return d
+	}
+
+	var got []Argon2Config
+
+	onChange := func(config Argon2Config) {
+		got = append(got, config)
+
+		calls++
+		if calls == 1 {
+			slower = true
+			return
+		}
+
+		cancel()
+	}
+
+	err := watchArgon2Calibration(ctx, time.Millisecond, 300*time.Millisecond, 64*1024, onChange, measure)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("watchArgon2Calibration() error = %v, want %v", err, context.Canceled)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("onChange called %d times, want 2", len(got))
+	}
+
+	if got[0].Time != 4 || got[1].Time != 2 {
+		t.Errorf("Time = %v then %v, want 4 then 2", got[0].Time, got[1].Time)
+	}
+}
+
+func TestWatchArgon2Calibration_InvalidArguments(t *testing.T) {
+	err := WatchArgon2Calibration(context.Background(), 0, time.Second, 64*1024, func(Argon2Config) {})
+	if !errors.Is(err, ErrInvalidCalibration) {
+		t.Errorf("WatchArgon2Calibration() error = %v, want %v", err, ErrInvalidCalibration)
+	}
+}
@@ -13,6 +13,7 @@ var (
 	ErrCannotParseDK        = fmt.Errorf("cannot parse derived key")
 	ErrCannotParseEncodedEK = fmt.Errorf("cannot parse encoded derived key")
 	ErrInvalidArgon2Config  = fmt.Errorf("invalid Argon2Config: all values must be > 0")
+	ErrInvalidCalibration   = fmt.Errorf("invalid calibration: target and interval must be > 0 and max memory at least Argon2MinCalibrationMemory")
 )
 
 // newParseError creates a new error for parsing failures
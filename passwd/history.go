@@ -0,0 +1,106 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package passwd
+
+import "slices"
+
+// ===========================================================================
+// Password History
+// ===========================================================================
+
+// PasswordHistory holds the derived keys of previously used passwords, newest
+// first, to enforce policies such as "must not reuse the last N passwords".
+//
+// The history stores derived keys only, never passwords. Persist Keys()
+// together with the user and restore it with NewPasswordHistory. A
+// PasswordHistory is not safe for concurrent use.
+//
+// Example:
+//
+//	history := passwd.NewPasswordHistory(10, user.PasswordHistory...)
+//
+//	reused, err := history.Contains(newPassword, 5)
+//	if err != nil {
+//	    // handle error
+//	}
+//	if reused {
+//	    // reject the new password
+//	}
+//
+//	dk, _ := passwd.CreateDerivedKey(newPassword)
+//	_ = history.Add(dk)
+//	user.PasswordHistory = history.Keys()
+type PasswordHistory struct {
+	keys []string
+	size int
+}
+
+// NewPasswordHistory returns a history holding at most size derived keys,
+// initialized with keys ordered newest first. A size <= 0 keeps all keys.
+func NewPasswordHistory(size int, keys ...string) *PasswordHistory {
+	h := &PasswordHistory{keys: slices.Clone(keys), size: size}
+	h.truncate()
+
+	return h
+}
+
+// Add records dk as the newest entry, dropping the oldest entries beyond the
+// size of the history. It returns ErrCannotParseDK if dk is not a derived key.
+func (h *PasswordHistory) Add(dk string) error {
+	if !IsDerivedKey(dk) {
+		return ErrCannotParseDK
+	}
+
+	h.keys = slices.Insert(h.keys, 0, dk)
+	h.truncate()
+
+	return nil
+}
+
+// Contains reports whether password matches one of the lastN newest derived
+// keys. A lastN <= 0 checks the whole history.
+//
+// Keys are verified newest first and verification stops at the first match,
+// since recent passwords are the most likely to be reused. Each verification
+// costs one Argon2 hash with the parameters of the stored key, so keep lastN
+// small on latency sensitive paths.
+func (h *PasswordHistory) Contains(password string, lastN int) (bool, error) {
+	if password == "" {
+		return false, ErrUnableToVerify
+	}
+
+	keys := h.keys
+	if lastN > 0 && lastN < len(keys) {
+		keys = keys[:lastN]
+	}
+
+	for _, dk := range keys {
+		ok, err := VerifyDerivedKey(dk, password)
+		if err != nil {
+			return false, err
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Keys returns a copy of the derived keys, newest first.
+func (h *PasswordHistory) Keys() []string {
+	return slices.Clone(h.keys)
+}
+
+// Len returns the number of derived keys in the history.
+func (h *PasswordHistory) Len() int {
+	return len(h.keys)
+}
+
+func (h *PasswordHistory) truncate() {
+	if h.size > 0 && len(h.keys) > h.size {
+		h.keys = h.keys[:h.size]
+	}
+}
@@ -0,0 +1,115 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package passwd
+
+import (
+	"errors"
+	"testing"
+)
+
+// historyTestConfig keeps hashing cheap in tests.
+var historyTestConfig = Argon2Config{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}
+
+func mustDerive(t *testing.T, password string) string {
+	t.Helper()
+
+	dk, err := CreateDerivedKeyWithConfig(password, historyTestConfig)
+	if err != nil {
+		t.Fatalf("CreateDerivedKeyWithConfig() error = %v", err)
+	}
+
+	return dk
+}
+
+func TestPasswordHistory(t *testing.T) {
+	h := NewPasswordHistory(3)
+
+	for _, pw := range []string{"first-Secret1!", "second-Secret2!", "third-Secret3!", "fourth-Secret4!"} {
+		if err := h.Add(mustDerive(t, pw)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	if h.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", h.Len())
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		lastN    int
+		want     bool
+	}{
+		{name: "newest", password: "fourth-Secret4!", lastN: 1, want: true},
+		{name: "within lastN", password: "third-Secret3!", lastN: 2, want: true},
+		{name: "outside lastN", password: "second-Secret2!", lastN: 2, want: false},
+		{name: "whole history", password: "second-Secret2!", lastN: 0, want: true},
+		{name: "lastN beyond history", password: "second-Secret2!", lastN: 10, want: true},
+		{name: "dropped", password: "first-Secret1!", lastN: 0, want: false},
+		{name: "never used", password: "other-Secret5!", lastN: 0, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := h.Contains(tt.password, tt.lastN)
+			if err != nil {
+				t.Fatalf("Contains() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Contains(%q, %d) = %v, want %v", tt.password, tt.lastN, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordHistory_EarlyExit(t *testing.T) {
+	// The malformed oldest entry is only reached if verification does not
+	// stop at the first match.
+	h := NewPasswordHistory(0, mustDerive(t, "newest-Secret1!"), "$argon2id$v=1$m=1,t=1,p=1$c2FsdA==$a2V5")
+
+	got, err := h.Contains("newest-Secret1!", 0)
+	if err != nil || !got {
+		t.Fatalf("Contains() = %v, %v, want true, nil", got, err)
+	}
+
+	if _, err := h.Contains("other-Secret2!", 0); !errors.Is(err, ErrCannotParseDK) {
+		t.Errorf("Contains() error = %v, want %v", err, ErrCannotParseDK)
+	}
+}
+
+func TestPasswordHistory_Restore(t *testing.T) {
+	keys := []string{mustDerive(t, "a-Secret1!"), mustDerive(t, "b-Secret2!"), mustDerive(t, "c-Secret3!")}
+
+	h := NewPasswordHistory(2, keys...)
+	if h.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", h.Len())
+	}
+
+	got := h.Keys()
+	if got[0] != keys[0] || got[1] != keys[1] {
+		t.Errorf("Keys() did not keep the newest entries")
+	}
+
+	got[0] = "modified"
+	if h.Keys()[0] != keys[0] {
+		t.Errorf("Keys() returned the internal slice")
+	}
+}
+
+func TestPasswordHistory_Errors(t *testing.T) {
+	h := NewPasswordHistory(5)
+
+	if err := h.Add("not-a-derived-key"); !errors.Is(err, ErrCannotParseDK) {
+		t.Errorf("Add() error = %v, want %v", err, ErrCannotParseDK)
+	}
+
+	if h.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", h.Len())
+	}
+
+	if _, err := h.Contains("", 0); !errors.Is(err, ErrUnableToVerify) {
+		t.Errorf("Contains() error = %v, want %v", err, ErrUnableToVerify)
+	}
+}
@@ -0,0 +1,295 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package passwd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// ===========================================================================
+// Throttled Verification
+// ===========================================================================
+
+const (
+	// DefaultThrottleAttempts is the number of attempts an identity may make
+	// in a burst before it is locked out.
+	DefaultThrottleAttempts = 5
+	// DefaultThrottleRefill is the time after which a locked out identity
+	// gains another attempt.
+	DefaultThrottleRefill = time.Minute
+	// DefaultFreeFailures is the number of consecutive failures that are
+	// answered without delay.
+	DefaultFreeFailures = 2
+	// DefaultFailureDelay is the delay after the first delayed failure. It
+	// doubles with every further failure up to DefaultMaxFailureDelay.
+	DefaultFailureDelay = 250 * time.Millisecond
+	// DefaultMaxFailureDelay caps the delay after a failure.
+	DefaultMaxFailureDelay = 4 * time.Second
+)
+
+// memoryLimiterSweepSize is the number of tracked identities above which the
+// MemoryLimiter drops identities with a full bucket.
+const memoryLimiterSweepSize = 10_000
+
+// Limiter tracks verification attempts per identity for VerifyWithThrottle.
+// Implementations backed by a shared store throttle across instances.
+type Limiter interface {
+	// Allow reserves an attempt for key. It returns the time until the next
+	// attempt is possible, or zero if the attempt may proceed.
+	Allow(ctx context.Context, key string) (retryAfter time.Duration, err error)
+	// Failure records a failed attempt and returns the number of consecutive
+	// failures of key.
+	Failure(ctx context.Context, key string) (failures int, err error)
+	// Reset forgets the attempts of key after a successful verification.
+	Reset(ctx context.Context, key string) error
+}
+
+// MemoryLimiter is an in-memory token bucket Limiter. Every identity starts
+// with a bucket of attempts; every attempt takes one, and one is added back
+// per refill interval. A successful verification refills the bucket. It is
+// safe for concurrent use, but only throttles attempts within one process.
+type MemoryLimiter struct {
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	attempts int
+	refill   time.Duration
+	now      func() time.Time
+}
+
+type bucket struct {
+	tokens   float64
+	last     time.Time
+	failures int
+}
+
+// NewMemoryLimiter returns a MemoryLimiter allowing attempts attempts in a
+// burst and one more attempt per refill. Values <= 0 select
+// DefaultThrottleAttempts and DefaultThrottleRefill.
+func NewMemoryLimiter(attempts int, refill time.Duration) *MemoryLimiter {
+	if attempts <= 0 {
+		attempts = DefaultThrottleAttempts
+	}
+
+	if refill <= 0 {
+		refill = DefaultThrottleRefill
+	}
+
+	return &MemoryLimiter{
+		buckets:  make(map[string]*bucket),
+		attempts: attempts,
+		refill:   refill,
+		now:      time.Now,
+	}
+}
+
+// Allow implements Limiter.
+func (l *MemoryLimiter) Allow(_ context.Context, key string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= memoryLimiterSweepSize {
+			l.sweep(now)
+		}
+
+		b = &bucket{tokens: float64(l.attempts), last: now}
+		l.buckets[key] = b
+	}
+
+	l.refillBucket(b, now)
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) * float64(l.refill)), nil
+	}
+
+	b.tokens--
+
+	return 0, nil
+}
+
+// Failure implements Limiter.
+func (l *MemoryLimiter) Failure(_ context.Context, key string) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.attempts), last: l.now()}
+		l.buckets[key] = b
+	}
+
+	b.failures++
+
+	return b.failures, nil
+}
+
+// Reset implements Limiter.
+func (l *MemoryLimiter) Reset(_ context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.buckets, key)
+
+	return nil
+}
+
+// refillBucket adds the attempts regained since the last update.
+func (l *MemoryLimiter) refillBucket(b *bucket, now time.Time) {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens = min(float64(l.attempts), b.tokens+float64(elapsed)/float64(l.refill))
+	b.last = now
+}
+
+// sweep drops identities whose bucket has been refilled completely, as they
+// behave like unknown identities.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		l.refillBucket(b, now)
+
+		if b.tokens >= float64(l.attempts) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// ThrottleOption configures VerifyWithThrottle.
+type ThrottleOption func(*throttleConfig)
+
+type throttleConfig struct {
+	identity     string
+	freeFailures int
+	delay        time.Duration
+	maxDelay     time.Duration
+}
+
+// WithThrottleIdentity sets the identity attempts are counted for, usually
+// the normalized login name. Without it, attempts are counted per derived
+// key, which does not throttle guessing across accounts or for unknown users.
+func WithThrottleIdentity(identity string) ThrottleOption {
+	return func(c *throttleConfig) {
+		c.identity = identity
+	}
+}
+
+// WithFailureDelay sets the number of consecutive failures answered without
+// delay, and the initial and maximum delay after further failures.
+func WithFailureDelay(freeFailures int, delay, maxDelay time.Duration) ThrottleOption {
+	return func(c *throttleConfig) {
+		c.freeFailures = freeFailures
+		c.delay = delay
+		c.maxDelay = maxDelay
+	}
+}
+
+// sleep waits for d or until ctx is done. It is a variable for tests.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// VerifyWithThrottle verifies password against dk like VerifyDerivedKey, and
+// slows down online guessing with limiter:
+//
+//   - an identity without remaining attempts is rejected with a
+//     TooManyRequests error carrying the retry delay, without verifying
+//   - after DefaultFreeFailures consecutive failures, every further failure
+//     is answered after an exponentially growing, jittered delay
+//   - a successful verification resets the attempts of the identity
+//
+// Example:
+//
+//	ok, err := passwd.VerifyWithThrottle(ctx, user.Password, password, limiter,
+//	    passwd.WithThrottleIdentity(strings.ToLower(email)))
+//	if errors.IsTooManyRequests(err) {
+//	    // respond with 429 and Retry-After
+//	}
+func VerifyWithThrottle(ctx context.Context, dk, password string, limiter Limiter, opts ...ThrottleOption) (bool, error) {
+	cfg := &throttleConfig{
+		freeFailures: DefaultFreeFailures,
+		delay:        DefaultFailureDelay,
+		maxDelay:     DefaultMaxFailureDelay,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	key := cfg.identity
+	if key == "" {
+		sum := sha256.Sum256([]byte(dk))
+		key = "dk:" + hex.EncodeToString(sum[:16])
+	}
+
+	retryAfter, err := limiter.Allow(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	if retryAfter > 0 {
+		return false, kerr.NewTooManyRequestsWithRetryAfter(retryAfter)
+	}
+
+	ok, err := VerifyDerivedKey(dk, password)
+	if err != nil {
+		return false, err
+	}
+
+	if ok {
+		return true, limiter.Reset(ctx, key)
+	}
+
+	failures, err := limiter.Failure(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	if d := cfg.failureDelay(failures); d > 0 {
+		if err := sleep(ctx, d); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// failureDelay returns the delay after the given number of consecutive
+// failures, with up to 20% jitter so that response times reveal less.
+func (c *throttleConfig) failureDelay(failures int) time.Duration {
+	n := failures - c.freeFailures
+	if n <= 0 || c.delay <= 0 {
+		return 0
+	}
+
+	d := c.delay
+	for i := 1; i < n && d < c.maxDelay; i++ {
+		d *= 2
+	}
+
+	if c.maxDelay > 0 {
+		d = min(d, c.maxDelay)
+	}
+
+	//nolint:gosec // jitter does not need a cryptographic source
+	return d - time.Duration(rand.Int64N(int64(d)/5+1))
+}
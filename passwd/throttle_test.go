@@ -0,0 +1,137 @@
+// Original Licenses under Apache-2.0 by the openlane https://github.com/theopenlane
+// SPDX-License-Identifier: Apache-2.0
+
+package passwd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kerr "github.com/kopexa-grc/common/errors"
+)
+
+// stubSleep records the delays instead of sleeping.
+func stubSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+
+	var delays []time.Duration
+
+	orig := sleep
+	sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	t.Cleanup(func() { sleep = orig })
+
+	return &delays
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC)
+
+	l := NewMemoryLimiter(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for i := range 2 {
+		if retryAfter, _ := l.Allow(ctx, "alice"); retryAfter != 0 {
+			t.Fatalf("attempt %d: expected to be allowed, got retry after %v", i+1, retryAfter)
+		}
+	}
+
+	if retryAfter, _ := l.Allow(ctx, "alice"); retryAfter != time.Minute {
+		t.Errorf("Expected retry after 1m, got %v", retryAfter)
+	}
+
+	if retryAfter, _ := l.Allow(ctx, "bob"); retryAfter != 0 {
+		t.Errorf("Expected other identity to be allowed, got retry after %v", retryAfter)
+	}
+
+	now = now.Add(30 * time.Second)
+	if retryAfter, _ := l.Allow(ctx, "alice"); retryAfter != 30*time.Second {
+		t.Errorf("Expected retry after 30s, got %v", retryAfter)
+	}
+
+	now = now.Add(30 * time.Second)
+	if retryAfter, _ := l.Allow(ctx, "alice"); retryAfter != 0 {
+		t.Errorf("Expected refilled attempt, got retry after %v", retryAfter)
+	}
+
+	if failures, _ := l.Failure(ctx, "alice"); failures != 1 {
+		t.Errorf("Expected 1 failure, got %d", failures)
+	}
+
+	_ = l.Reset(ctx, "alice")
+	if failures, _ := l.Failure(ctx, "alice"); failures != 1 {
+		t.Errorf("Expected failures to start over after reset, got %d", failures)
+	}
+}
+
+func TestVerifyWithThrottle(t *testing.T) {
+	delays := stubSleep(t)
+	ctx := context.Background()
+	dk := mustDerive(t, "correct-Secret1!")
+	limiter := NewMemoryLimiter(5, time.Hour)
+	identity := WithThrottleIdentity("alice@example.com")
+
+	for range 4 {
+		ok, err := VerifyWithThrottle(ctx, dk, "wrong-Secret1!", limiter, identity)
+		if err != nil || ok {
+			t.Fatalf("VerifyWithThrottle() = %v, %v, want false, nil", ok, err)
+		}
+	}
+
+	// the first DefaultFreeFailures failures are not delayed
+	if len(*delays) != 2 {
+		t.Fatalf("Expected 2 delays, got %v", *delays)
+	}
+
+	if (*delays)[0] > DefaultFailureDelay || (*delays)[0] < DefaultFailureDelay*4/5 {
+		t.Errorf("Expected first delay around %v, got %v", DefaultFailureDelay, (*delays)[0])
+	}
+
+	if (*delays)[1] < 2*DefaultFailureDelay*4/5 {
+		t.Errorf("Expected second delay to double, got %v", (*delays)[1])
+	}
+
+	ok, err := VerifyWithThrottle(ctx, dk, "correct-Secret1!", limiter, identity)
+	if err != nil || !ok {
+		t.Fatalf("VerifyWithThrottle() = %v, %v, want true, nil", ok, err)
+	}
+
+	// success refilled the bucket
+	for range 5 {
+		_, _ = VerifyWithThrottle(ctx, dk, "wrong-Secret1!", limiter, identity)
+	}
+
+	ok, err = VerifyWithThrottle(ctx, dk, "correct-Secret1!", limiter, identity)
+	if ok || !kerr.IsTooManyRequests(err) {
+		t.Fatalf("Expected lockout, got %v, %v", ok, err)
+	}
+
+	e, _ := err.(*kerr.Error)
+	if retryAfter, ok := e.RetryAfter(); !ok || retryAfter <= 0 {
+		t.Errorf("Expected retry after, got %v", err)
+	}
+
+	// attempts without identity are counted per derived key
+	ok, err = VerifyWithThrottle(ctx, dk, "correct-Secret1!", limiter)
+	if err != nil || !ok {
+		t.Errorf("VerifyWithThrottle() = %v, %v, want true, nil", ok, err)
+	}
+}
+
+func TestThrottleConfig_FailureDelay(t *testing.T) {
+	c := &throttleConfig{freeFailures: 0, delay: time.Second, maxDelay: 3 * time.Second}
+
+	if d := c.failureDelay(10); d > 3*time.Second || d < 3*time.Second*4/5 {
+		t.Errorf("Expected delay capped at 3s, got %v", d)
+	}
+
+	c = &throttleConfig{freeFailures: 1}
+	if d := c.failureDelay(5); d != 0 {
+		t.Errorf("Expected no delay without configured delay, got %v", d)
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Sensitivity is the classification label of a summarizer input.
+type Sensitivity string
+
+const (
+	// SensitivityPublic labels inputs that may be disclosed to anyone.
+	SensitivityPublic Sensitivity = "public"
+
+	// SensitivityInternal labels inputs that must stay within the
+	// organization and its contracted processors.
+	SensitivityInternal Sensitivity = "internal"
+
+	// SensitivityConfidential labels inputs that must not leave
+	// infrastructure operated by the organization, such as classified
+	// documents or special categories of personal data.
+	SensitivityConfidential Sensitivity = "confidential"
+)
+
+// rank orders the labels. Unknown labels rank as confidential, so that a
+// misbehaving Classifier fails closed.
+func (s Sensitivity) rank() int {
+	switch s {
+	case SensitivityPublic:
+		return 0
+	case SensitivityInternal:
+		return 1
+	default:
+		return 2 //nolint:mnd // highest rank
+	}
+}
+
+// valid reports whether s is one of the predefined labels.
+func (s Sensitivity) valid() bool {
+	switch s {
+	case SensitivityPublic, SensitivityInternal, SensitivityConfidential:
+		return true
+	default:
+		return false
+	}
+}
+
+// Classifier labels inputs before they are summarized.
+type Classifier interface {
+	// Classify returns the sensitivity of text.
+	Classify(ctx context.Context, text string) (Sensitivity, error)
+}
+
+// ClassifierFunc adapts a function to a Classifier.
+type ClassifierFunc func(ctx context.Context, text string) (Sensitivity, error)
+
+// Classify calls f.
+func (f ClassifierFunc) Classify(ctx context.Context, text string) (Sensitivity, error) {
+	return f(ctx, text)
+}
+
+// ClassificationRule labels inputs matched by Pattern with Sensitivity.
+type ClassificationRule struct {
+	Pattern     *regexp.Regexp
+	Sensitivity Sensitivity
+}
+
+// DefaultClassificationRules detect common classification markings of
+// English and German documents, such as "Confidential", "Streng
+// vertraulich", "VS-NfD" or "For internal use only".
+var DefaultClassificationRules = []ClassificationRule{
+	{
+		Pattern:     regexp.MustCompile(`(?i)\b(?:(?:strictly\s+)?confidential|top\s+secret|(?:streng\s+)?vertraulich|geheim|VS-(?:NfD|Vertraulich|Geheim))\b`),
+		Sensitivity: SensitivityConfidential,
+	},
+	{
+		Pattern:     regexp.MustCompile(`(?i)\b(?:(?:for\s+)?internal\s+(?:use\s+)?only|for\s+internal\s+use|nur\s+intern|nur\s+für\s+den\s+internen\s+gebrauch)\b`),
+		Sensitivity: SensitivityInternal,
+	},
+}
+
+// SpecialCategoryRules detect mentions of special categories of personal
+// data (Art. 9 GDPR) in English and German, e.g. health data, religious
+// beliefs or trade union membership. They match keywords and therefore
+// also label texts that merely discuss these categories, such as data
+// protection policies; add them only where that is acceptable.
+var SpecialCategoryRules = []ClassificationRule{
+	{
+		Pattern:     regexp.MustCompile(`(?i)\b(?:health\s+data|medical\s+(?:record|history|condition)s?|diagnos[ie]s|gesundheitsdaten|diagnosen?|krankheitsverlauf|religious\s+beliefs?|religionszugehörigkeit|ethnic\s+origin|ethnische\s+herkunft|trade\s+union\s+membership|gewerkschaftszugehörigkeit|sexual\s+orientation|sexuelle\s+orientierung|genetic\s+data|genetische\s+daten|biometric\s+data|biometrische\s+daten|political\s+opinions?|politische\s+meinung(?:en)?)\b`),
+		Sensitivity: SensitivityConfidential,
+	},
+}
+
+// PatternClassifier labels an input with the highest sensitivity of all
+// rules matching it, or with Default if no rule matches.
+type PatternClassifier struct {
+	// Rules are the classification rules.
+	Rules []ClassificationRule
+	// Default labels inputs no rule matches. Defaults to
+	// SensitivityInternal.
+	Default Sensitivity
+}
+
+// NewPatternClassifier creates a PatternClassifier with the given rules, or
+// with DefaultClassificationRules if none are given.
+//
+// Example:
+//
+//	classifier := summarizer.NewPatternClassifier(
+//		append(summarizer.DefaultClassificationRules, summarizer.SpecialCategoryRules...)...,
+//	)
+func NewPatternClassifier(rules ...ClassificationRule) *PatternClassifier {
+	if len(rules) == 0 {
+		rules = DefaultClassificationRules
+	}
+
+	return &PatternClassifier{Rules: rules, Default: SensitivityInternal}
+}
+
+// Classify returns the highest sensitivity of the rules matching text.
+func (p *PatternClassifier) Classify(_ context.Context, text string) (Sensitivity, error) {
+	result := p.Default
+	if result == "" {
+		result = SensitivityInternal
+	}
+
+	matched := false
+
+	for _, rule := range p.Rules {
+		if !rule.Pattern.MatchString(text) {
+			continue
+		}
+
+		if !matched || rule.Sensitivity.rank() > result.rank() {
+			result = rule.Sensitivity
+			matched = true
+		}
+	}
+
+	return result, nil
+}
+
+// WithClassifier labels every input with classifier before it is
+// summarized. Inputs labeled above the MaxExternalSensitivity are not sent
+// to the configured backend but summarized by the LocalBackend instead,
+// unless the configured backend is local itself. Classification errors fail
+// the summary, so that inputs are never sent unclassified.
+//
+// Example:
+//
+//	config := NewConfig(
+//		WithType(TypeLlm),
+//		WithOpenAI("gpt-4", "sk-..."),
+//		WithClassifier(NewPatternClassifier()),
+//		WithLocalBackend(NewConfig(WithType(TypeLlm), WithOllama("llama3", "http://localhost:11434"))),
+//	)
+func WithClassifier(classifier Classifier) Option {
+	return func(c *Config) {
+		c.Classifier = classifier
+	}
+}
+
+// WithMaxExternalSensitivity sets the highest sensitivity of inputs that
+// may be sent to external backends. Defaults to SensitivityInternal.
+func WithMaxExternalSensitivity(sensitivity Sensitivity) Option {
+	return func(c *Config) {
+		c.MaxExternalSensitivity = sensitivity
+	}
+}
+
+// WithLocalBackend sets the backend that summarizes inputs above the
+// MaxExternalSensitivity. It must be LexRank or Ollama; defaults to
+// LexRank. The glossary, redaction and output format settings of the outer
+// configuration apply to it as well.
+func WithLocalBackend(cfg *Config) Option {
+	return func(c *Config) {
+		c.LocalBackend = cfg
+	}
+}
+
+// isLocal reports whether cfg summarizes without sending inputs to a third
+// party. Ollama is considered local as it runs on infrastructure operated
+// by the organization.
+func isLocal(cfg *Config) bool {
+	switch cfg.Type {
+	case TypeLexrank:
+		return true
+	case TypeLlm:
+		return cfg.LLM != nil && cfg.LLM.Provider == LLMProviderOllama
+	default:
+		return false
+	}
+}
+
+// contentPolicy routes inputs to the local backend by their sensitivity.
+type contentPolicy struct {
+	classifier  Classifier
+	maxExternal Sensitivity
+	// local summarizes inputs above maxExternal; nil if the Client is local
+	// itself.
+	local *Client
+}
+
+// newContentPolicy creates the content policy of cfg, which must have a
+// Classifier.
+func newContentPolicy(cfg *Config) (*contentPolicy, error) {
+	policy := &contentPolicy{
+		classifier:  cfg.Classifier,
+		maxExternal: cfg.MaxExternalSensitivity,
+	}
+
+	if policy.maxExternal == "" {
+		policy.maxExternal = SensitivityInternal
+	}
+
+	if !policy.maxExternal.valid() {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSensitivity, policy.maxExternal)
+	}
+
+	if isLocal(cfg) {
+		return policy, nil
+	}
+
+	local := NewConfig()
+	if cfg.LocalBackend != nil {
+		copied := *cfg.LocalBackend
+		local = &copied
+	}
+
+	if !isLocal(local) {
+		return nil, fmt.Errorf("%w: %s", ErrBackendNotLocal, local.Type)
+	}
+
+	local.Glossary = cfg.Glossary
+	local.RedactionPassThrough = cfg.RedactionPassThrough
+	local.RedactionPattern = cfg.RedactionPattern
+	local.OutputFormat = cfg.OutputFormat
+	local.Classifier = nil
+	local.LocalBackend = nil
+
+	client, err := New(local)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create local summarizer: %w", err)
+	}
+
+	policy.local = client
+
+	return policy, nil
+}
+
+// route classifies texts after sanitizing and returns the Client that may
+// summarize them: s, or its local backend if any text is labeled above the
+// MaxExternalSensitivity. Unknown labels count as confidential. The
+// sensitivity is empty without a Classifier.
+func (s *Client) route(ctx context.Context, texts ...string) (*Client, Sensitivity, error) {
+	if s.policy == nil {
+		return s, "", nil
+	}
+
+	var sensitivity Sensitivity
+
+	for _, text := range texts {
+		label, err := s.policy.classifier.Classify(ctx, s.sanitizer.Sanitize(text))
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %w", ErrClassificationFailed, err)
+		}
+
+		if !label.valid() {
+			label = SensitivityConfidential
+		}
+
+		if sensitivity == "" || label.rank() > sensitivity.rank() {
+			sensitivity = label
+		}
+	}
+
+	if s.policy.local != nil && sensitivity.rank() > s.policy.maxExternal.rank() {
+		return s.policy.local, sensitivity, nil
+	}
+
+	return s, sensitivity, nil
+}
@@ -0,0 +1,171 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPatternClassifier_Classify(t *testing.T) {
+	classifier := NewPatternClassifier(append(DefaultClassificationRules, SpecialCategoryRules...)...)
+
+	tests := []struct {
+		name string
+		text string
+		want Sensitivity
+	}{
+		{name: "unmarked", text: "The vendor stores data in the EU.", want: SensitivityInternal},
+		{name: "confidential marking", text: "STRICTLY CONFIDENTIAL - Board minutes", want: SensitivityConfidential},
+		{name: "german marking", text: "VS-NfD: Lagebericht", want: SensitivityConfidential},
+		{name: "internal marking", text: "For internal use only. Roadmap 2026.", want: SensitivityInternal},
+		{name: "confidentiality is no marking", text: "The ISMS protects confidentiality and integrity.", want: SensitivityInternal},
+		{name: "special category", text: "The employee's diagnosis was shared with HR.", want: SensitivityConfidential},
+		{name: "highest match wins", text: "Internal use only. Contains health data of patients.", want: SensitivityConfidential},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := classifier.Classify(context.Background(), tt.text)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	public := &PatternClassifier{Rules: DefaultClassificationRules, Default: SensitivityPublic}
+	if got, _ := public.Classify(context.Background(), "Press release"); got != SensitivityPublic {
+		t.Errorf("Expected the default label, got %q", got)
+	}
+}
+
+func TestClient_ClassifierRoutesToLocalBackend(t *testing.T) {
+	fake := &echoLLM{fn: func(string) string { return "External summary." }}
+
+	client, err := New(NewConfig(
+		WithType(TypeLlm),
+		WithOpenAI("gpt-4", "test-api-key"),
+		WithClassifier(NewPatternClassifier()),
+	))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client.impl = NewLLMSummarizer(fake)
+
+	ctx := context.Background()
+
+	res, err := client.SummarizeDetailed(ctx, "The vendor stores data in the EU. The vendor encrypts data at rest.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if res.Summary != "External summary." || res.Backend != "openai" || res.Sensitivity != SensitivityInternal {
+		t.Errorf("Expected the external backend, got %q from %q (%q)", res.Summary, res.Backend, res.Sensitivity)
+	}
+
+	confidential := "CONFIDENTIAL. The merger with Acme closes in May. The price is not disclosed."
+
+	res, err = client.SummarizeDetailed(ctx, confidential)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if res.Backend != string(TypeLexrank) || res.Sensitivity != SensitivityConfidential {
+		t.Errorf("Expected the local backend, got %q (%q)", res.Backend, res.Sensitivity)
+	}
+
+	if _, err := client.Summarize(ctx, confidential); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := client.SummarizeMulti(ctx, []Document{{Content: "Public notes."}, {Content: confidential}}, MultiModeAggregate); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := client.SummarizeIncremental(ctx, IncrementalSummary{Summary: "Merger planned."}, confidential); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(fake.prompts) != 1 {
+		t.Errorf("Expected only the unmarked input to reach the external backend, got %d prompts", len(fake.prompts))
+	}
+}
+
+func TestClient_ClassifierFailsClosed(t *testing.T) {
+	classifyErr := errors.New("classifier unavailable")
+	fake := &echoLLM{fn: func(string) string { return "External summary." }}
+
+	client, err := New(NewConfig(
+		WithType(TypeLlm),
+		WithOpenAI("gpt-4", "test-api-key"),
+		WithClassifier(ClassifierFunc(func(context.Context, string) (Sensitivity, error) {
+			return "", classifyErr
+		})),
+	))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client.impl = NewLLMSummarizer(fake)
+
+	_, err = client.Summarize(context.Background(), "Some text.")
+	if !errors.Is(err, ErrClassificationFailed) || !errors.Is(err, classifyErr) {
+		t.Errorf("Expected ErrClassificationFailed, got %v", err)
+	}
+
+	client.policy.classifier = ClassifierFunc(func(context.Context, string) (Sensitivity, error) {
+		return "secret", nil
+	})
+
+	res, err := client.SummarizeDetailed(context.Background(), "Some text. More text.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if res.Sensitivity != SensitivityConfidential || res.Backend != string(TypeLexrank) {
+		t.Errorf("Expected unknown labels to be treated as confidential, got %q from %q", res.Sensitivity, res.Backend)
+	}
+
+	if len(fake.prompts) != 0 {
+		t.Errorf("Expected no input to reach the external backend, got %d prompts", len(fake.prompts))
+	}
+}
+
+func TestNew_ContentPolicyInvalid(t *testing.T) {
+	classifier := NewPatternClassifier()
+
+	_, err := New(NewConfig(
+		WithType(TypeLlm),
+		WithOpenAI("gpt-4", "test-api-key"),
+		WithClassifier(classifier),
+		WithLocalBackend(NewConfig(WithType(TypeLlm), WithAnthropic("claude-3-sonnet", "test-api-key"))),
+	))
+	if !errors.Is(err, ErrBackendNotLocal) {
+		t.Errorf("Expected ErrBackendNotLocal, got %v", err)
+	}
+
+	_, err = New(NewConfig(WithClassifier(classifier), WithMaxExternalSensitivity("secret")))
+	if !errors.Is(err, ErrUnknownSensitivity) {
+		t.Errorf("Expected ErrUnknownSensitivity, got %v", err)
+	}
+
+	local, err := New(NewConfig(
+		WithType(TypeLlm),
+		WithOllama("llama3", "http://localhost:11434"),
+		WithClassifier(classifier),
+	))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if local.policy.local != nil {
+		t.Error("Expected local backends to summarize all inputs themselves")
+	}
+}
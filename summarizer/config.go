@@ -17,6 +17,8 @@
 // Google Gemini, HuggingFace, Ollama, and Cloudflare.
 package summarizer
 
+import "regexp"
+
 // Type represents the type of summarization algorithm to use.
 type Type string
 
@@ -43,6 +45,34 @@ type Config struct {
 	// LLM contains the configuration for LLM-based summarization.
 	// Required when Type is TypeLlm, ignored otherwise.
 	LLM *LLMConfig
+
+	// Glossary maps disallowed synonyms to the preferred terminology that
+	// summaries must use. Optional; see WithGlossary.
+	Glossary map[string]string
+
+	// RedactionPassThrough preserves redacted placeholders verbatim in
+	// summaries. Optional; see WithRedactionPassThrough.
+	RedactionPassThrough bool
+
+	// RedactionPattern matches the placeholders preserved by
+	// RedactionPassThrough. Defaults to DefaultRedactionPattern.
+	RedactionPattern *regexp.Regexp
+
+	// OutputFormat selects the shape of the summaries. Optional; the
+	// summarizer's natural output is returned if empty. See WithOutputFormat.
+	OutputFormat OutputFormat
+
+	// Classifier labels inputs before summarization. Optional; see
+	// WithClassifier.
+	Classifier Classifier
+
+	// MaxExternalSensitivity is the highest sensitivity of inputs sent to
+	// external backends. Defaults to SensitivityInternal.
+	MaxExternalSensitivity Sensitivity
+
+	// LocalBackend summarizes inputs above MaxExternalSensitivity if a
+	// Classifier is set. Defaults to LexRank; see WithLocalBackend.
+	LocalBackend *Config
 }
 
 // LLMConfig contains all configuration parameters for LLM-based summarization.
@@ -10,4 +10,31 @@ var (
 	ErrConfigRequired    = errors.New("config must not be nil")
 	ErrLLMConfigRequired = errors.New("LLM config is required for LLM summarization")
 	ErrUnsupportedType   = errors.New("unsupported summarizer type")
+	// ErrUnsupportedMultiMode is returned for an unknown multi-document mode
+	ErrUnsupportedMultiMode = errors.New("unsupported multi-document mode")
+	// ErrMultiNotSupported is returned when the summarizer cannot handle multiple documents
+	ErrMultiNotSupported = errors.New("summarizer does not support multi-document summarization")
+	// ErrIncrementalNotSupported is returned when the summarizer cannot update an existing summary
+	ErrIncrementalNotSupported = errors.New("summarizer does not support incremental summarization")
+	// ErrInvalidGlossary is returned for glossary entries with an empty synonym or term
+	ErrInvalidGlossary = errors.New("invalid glossary entry")
+	// ErrRedactionAltered is returned when a summary alters or invents redacted placeholders
+	ErrRedactionAltered = errors.New("summary altered redacted placeholders")
+	// ErrResolverRequired is returned when a TenantSummarizer is created without a ConfigResolver
+	ErrResolverRequired = errors.New("config resolver must not be nil")
+	// ErrTenantRequired is returned when a tenant-scoped request has no tenant ID
+	ErrTenantRequired = errors.New("tenant ID is required")
+	// ErrTenantConfigNotFound is returned when neither the tenant nor the default has a config
+	ErrTenantConfigNotFound = errors.New("no summarizer config for tenant")
+	// ErrUnsupportedOutputFormat is returned for an unknown output format, or
+	// when typed sections are requested from a client with another format
+	ErrUnsupportedOutputFormat = errors.New("unsupported output format")
+	// ErrInvalidSections is returned when an LLM response is not a valid sections object
+	ErrInvalidSections = errors.New("summary is not a valid sections object")
+	// ErrClassificationFailed is returned when the Classifier cannot label an input
+	ErrClassificationFailed = errors.New("failed to classify input")
+	// ErrUnknownSensitivity is returned for a MaxExternalSensitivity that is not a predefined label
+	ErrUnknownSensitivity = errors.New("unknown sensitivity")
+	// ErrBackendNotLocal is returned when the LocalBackend would send inputs to a third party
+	ErrBackendNotLocal = errors.New("local backend must be LexRank or Ollama")
 )
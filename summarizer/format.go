@@ -0,0 +1,283 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OutputFormat selects the shape of the summaries returned by a Client.
+type OutputFormat string
+
+const (
+	// OutputFormatMarkdown returns the summary as Markdown.
+	OutputFormatMarkdown OutputFormat = "markdown"
+
+	// OutputFormatPlain returns the summary as plain text without any
+	// Markdown formatting.
+	OutputFormatPlain OutputFormat = "plain"
+
+	// OutputFormatSections returns the summary as a JSON encoded Sections
+	// value; see Client.SummarizeSections for the typed result.
+	OutputFormatSections OutputFormat = "sections"
+)
+
+const (
+	formatMarkdownEN = `Format the summary as Markdown. Use bullet lists where they help readability.`
+	formatMarkdownDE = `Formatiere die Zusammenfassung als Markdown. Verwende Aufzählungen, wo sie die Lesbarkeit verbessern.`
+
+	formatPlainEN = `Return plain text only. Do not use Markdown or any other formatting.`
+	formatPlainDE = `Gib nur reinen Text zurück. Verwende kein Markdown und keine andere Formatierung.`
+
+	formatSectionsEN = `Respond with a single JSON object and nothing else, using exactly these keys:
+{"overview": "<short overview>", "key_risks": ["<risk>"], "recommendations": ["<recommendation>"]}
+Use empty lists if the text names no risks or recommendations.`
+	formatSectionsDE = `Antworte ausschließlich mit einem einzelnen JSON-Objekt mit genau diesen Schlüsseln:
+{"overview": "<kurzer Überblick>", "key_risks": ["<Risiko>"], "recommendations": ["<Empfehlung>"]}
+Schreibe die Werte auf Deutsch. Verwende leere Listen, wenn der Text keine Risiken oder Empfehlungen nennt.`
+)
+
+// Sections is a summary split into an overview, the key risks and the
+// recommended actions.
+type Sections struct {
+	Overview        string   `json:"overview"`
+	KeyRisks        []string `json:"key_risks"`
+	Recommendations []string `json:"recommendations"`
+}
+
+// WithOutputFormat sets the shape of the summaries. LLM summarizers are
+// instructed to answer in the format; LexRank summaries are converted on a
+// best-effort basis, e.g. by mapping sentences that mention risks to
+// Sections.KeyRisks. The format applies to Summarize, SummarizeWithReport,
+// SummarizeDetailed and SummarizeSections.
+//
+// Example:
+//
+//	config := NewConfig(
+//		WithType(TypeLlm),
+//		WithOpenAI("gpt-4", "sk-..."),
+//		WithOutputFormat(OutputFormatSections),
+//	)
+func WithOutputFormat(format OutputFormat) Option {
+	return func(c *Config) {
+		c.OutputFormat = format
+	}
+}
+
+// validOutputFormat reports whether format is empty or a known format.
+func validOutputFormat(format OutputFormat) bool {
+	switch format {
+	case "", OutputFormatMarkdown, OutputFormatPlain, OutputFormatSections:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatInstructions returns the LLM instructions for format in lang, or an
+// empty string if the format is not set.
+func formatInstructions(format OutputFormat, lang string) string {
+	de := lang == "German"
+
+	switch {
+	case format == OutputFormatMarkdown && de:
+		return formatMarkdownDE
+	case format == OutputFormatMarkdown:
+		return formatMarkdownEN
+	case format == OutputFormatPlain && de:
+		return formatPlainDE
+	case format == OutputFormatPlain:
+		return formatPlainEN
+	case format == OutputFormatSections && de:
+		return formatSectionsDE
+	case format == OutputFormatSections:
+		return formatSectionsEN
+	default:
+		return ""
+	}
+}
+
+// SummarizeSections is like Summarize but returns the typed Sections. The
+// Client must be configured with OutputFormatSections.
+func (s *Client) SummarizeSections(ctx context.Context, sentence string) (*Sections, error) {
+	if s.format != OutputFormatSections {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedOutputFormat, s.format)
+	}
+
+	res, err := s.SummarizeDetailed(ctx, sentence)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Sections, nil
+}
+
+// applyFormat converts summary into the output format of the Client. For
+// OutputFormatSections the summary is returned as canonical JSON together
+// with the parsed Sections.
+func (s *Client) applyFormat(summary string) (string, *Sections, error) {
+	switch s.format {
+	case OutputFormatPlain:
+		return stripMarkdown(summary), nil, nil
+	case OutputFormatSections:
+		var (
+			sections *Sections
+			err      error
+		)
+
+		if _, ok := s.impl.(*lexRankSummarizer); ok {
+			sections = mapSections(summary)
+		} else if sections, err = parseSections(summary); err != nil {
+			return "", nil, err
+		}
+
+		b, err := json.Marshal(sections)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return string(b), sections, nil
+	default:
+		return summary, nil, nil
+	}
+}
+
+// parseSections parses the Sections from an LLM response. Code fences and
+// text around the JSON object are ignored.
+func parseSections(response string) (*Sections, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+
+	if start < 0 || end < start {
+		return nil, ErrInvalidSections
+	}
+
+	var sections Sections
+	if err := json.Unmarshal([]byte(response[start:end+1]), &sections); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSections, err)
+	}
+
+	sections.Overview = strings.TrimSpace(sections.Overview)
+	sections.KeyRisks = compactItems(sections.KeyRisks)
+	sections.Recommendations = compactItems(sections.Recommendations)
+
+	return &sections, nil
+}
+
+// compactItems trims items and drops empty ones. It never returns nil, so
+// that empty sections are encoded as empty lists.
+func compactItems(items []string) []string {
+	out := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
+
+var (
+	// riskPattern matches sentences that describe a risk.
+	riskPattern = regexp.MustCompile(`(?i)\b(risks?|threats?|vulnerab\w*|gaps?|breach\w*|non-?complian\w*|exposed|exposure|risiken|risiko|bedrohung\w*|schwachstelle\w*|lücken?|verstoß\w*)\b`)
+	// recommendationPattern matches sentences that recommend an action.
+	recommendationPattern = regexp.MustCompile(`(?i)\b(should|must|recommend\w*|need to|ensure|consider|sollten?|müssen|muss|empfehl\w*|empfohlen|sicherstellen)\b`)
+)
+
+// mapSections maps the sentences of an extractive summary to Sections:
+// sentences recommending an action become recommendations, sentences naming
+// a risk become key risks and the remaining sentences form the overview.
+// If every sentence was mapped to a list, the first one is also used as
+// overview.
+func mapSections(summary string) *Sections {
+	sections := &Sections{KeyRisks: []string{}, Recommendations: []string{}}
+
+	var overview []string
+
+	sentences := splitSentences(summary)
+
+	for _, sentence := range sentences {
+		switch {
+		case recommendationPattern.MatchString(sentence):
+			sections.Recommendations = append(sections.Recommendations, sentence)
+		case riskPattern.MatchString(sentence):
+			sections.KeyRisks = append(sections.KeyRisks, sentence)
+		default:
+			overview = append(overview, sentence)
+		}
+	}
+
+	if len(overview) == 0 && len(sentences) > 0 {
+		overview = sentences[:1]
+	}
+
+	sections.Overview = strings.Join(overview, " ")
+
+	return sections
+}
+
+// splitSentences splits text after sentence-ending punctuation that is
+// followed by whitespace.
+func splitSentences(text string) []string {
+	var (
+		sentences []string
+		start     int
+	)
+
+	runes := []rune(text)
+
+	for i, r := range runes {
+		if (r != '.' && r != '!' && r != '?') || i+1 >= len(runes) || !isSpace(runes[i+1]) {
+			continue
+		}
+
+		if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+			sentences = append(sentences, s)
+		}
+
+		start = i + 1
+	}
+
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		sentences = append(sentences, s)
+	}
+
+	return sentences
+}
+
+// isSpace reports whether r separates sentences.
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\n' || r == '\t' || r == '\r'
+}
+
+var (
+	// markdownLinePrefix matches headings, quotes and list markers.
+	markdownLinePrefix = regexp.MustCompile(`(?m)^[ \t]*(?:#{1,6}[ \t]+|>[ \t]?|[-*+][ \t]+)`)
+	// markdownLink matches inline links and images.
+	markdownLink = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	// markdownStrong matches bold, strikethrough and code markers.
+	markdownStrong = regexp.MustCompile("\\*\\*|__|~~|`")
+	// markdownEmphasis matches italic markers at word boundaries.
+	markdownEmphasis = regexp.MustCompile(`(?:^|\s)[*_]|[*_](?:\s|$|[.,;:!?])`)
+	// markdownFence matches code fence lines.
+	markdownFence = regexp.MustCompile("(?m)^[ \\t]*```.*$\\n?")
+)
+
+// stripMarkdown removes common Markdown formatting from text.
+func stripMarkdown(text string) string {
+	text = markdownFence.ReplaceAllString(text, "")
+	text = markdownLinePrefix.ReplaceAllString(text, "")
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = markdownStrong.ReplaceAllString(text, "")
+	text = markdownEmphasis.ReplaceAllStringFunc(text, func(m string) string {
+		return strings.Trim(m, "*_")
+	})
+
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+func newFormatClient(fake LLMClient, format OutputFormat) *Client {
+	impl := NewLLMSummarizer(fake)
+	impl.format = format
+
+	return &Client{impl: impl, sanitizer: bluemonday.StrictPolicy(), format: format}
+}
+
+func TestClient_OutputFormatSections(t *testing.T) {
+	fake := &fixedLLM{answer: "```json\n" + `{"overview": " Vendor review. ", "key_risks": ["No DPA signed", ""], "recommendations": ["Sign the DPA"]}` + "\n```"}
+	client := newFormatClient(fake, OutputFormatSections)
+
+	sections, err := client.SummarizeSections(context.Background(), "The vendor review found that no DPA was signed.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := &Sections{Overview: "Vendor review.", KeyRisks: []string{"No DPA signed"}, Recommendations: []string{"Sign the DPA"}}
+	if !reflect.DeepEqual(sections, want) {
+		t.Errorf("Unexpected sections: %+v", sections)
+	}
+
+	if !strings.Contains(fake.prompts[0], `"key_risks"`) {
+		t.Errorf("Expected the prompt to ask for sections, got %q", fake.prompts[0])
+	}
+
+	summary, err := client.Summarize(context.Background(), "The vendor review found that no DPA was signed.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if want := `{"overview":"Vendor review.","key_risks":["No DPA signed"],"recommendations":["Sign the DPA"]}`; summary != want {
+		t.Errorf("Expected %s, got %s", want, summary)
+	}
+
+	fake.answer = "I cannot summarize this."
+	if _, err := client.Summarize(context.Background(), "Some text."); !errors.Is(err, ErrInvalidSections) {
+		t.Errorf("Expected ErrInvalidSections, got %v", err)
+	}
+}
+
+func TestClient_OutputFormatSections_Glossary(t *testing.T) {
+	g, err := NewGlossary(map[string]string{"risks": "findings", "overview": "summary"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fake := &fixedLLM{answer: `{"overview": "An overview of the risks.", "key_risks": ["Unmanaged risks"], "recommendations": ["Track risks"]}`}
+	client := newFormatClient(fake, OutputFormatSections)
+	client.glossary = g
+
+	res, err := client.SummarizeDetailed(context.Background(), "The audit found several risks.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := &Sections{Overview: "An summary of the findings.", KeyRisks: []string{"Unmanaged findings"}, Recommendations: []string{"Track findings"}}
+	if !reflect.DeepEqual(res.Sections, want) {
+		t.Errorf("Unexpected sections: %+v", res.Sections)
+	}
+
+	if want := `{"overview":"An summary of the findings.","key_risks":["Unmanaged findings"],"recommendations":["Track findings"]}`; res.Summary != want {
+		t.Errorf("Expected %s, got %s", want, res.Summary)
+	}
+
+	counts := make(map[string]int)
+	for _, sub := range res.Substitutions {
+		counts[sub.From] = sub.Count
+	}
+
+	if counts["risks"] != 3 || counts["overview"] != 1 || len(res.Substitutions) != 2 {
+		t.Errorf("Unexpected substitutions: %v", res.Substitutions)
+	}
+}
+
+func TestClient_OutputFormatPlain(t *testing.T) {
+	fake := &fixedLLM{answer: "## Summary\n\n- **Access** reviews are _overdue_.\n- See [the policy](https://example.com) and `ISMS-7`."}
+	client := newFormatClient(fake, OutputFormatPlain)
+
+	summary, err := client.Summarize(context.Background(), "Access reviews are overdue.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "Summary\n\nAccess reviews are overdue.\nSee the policy and ISMS-7."
+	if summary != want {
+		t.Errorf("Expected %q, got %q", want, summary)
+	}
+
+	if !strings.Contains(fake.prompts[0], formatPlainEN) {
+		t.Errorf("Expected the prompt to ask for plain text, got %q", fake.prompts[0])
+	}
+}
+
+func TestClient_OutputFormatMarkdown(t *testing.T) {
+	fake := &fixedLLM{answer: "- **Access** reviews are overdue."}
+	client := newFormatClient(fake, OutputFormatMarkdown)
+
+	summary, err := client.Summarize(context.Background(), "Die Zugriffsüberprüfungen sind seit drei Monaten überfällig und müssen dringend nachgeholt werden.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if summary != fake.answer {
+		t.Errorf("Expected Markdown to be kept, got %q", summary)
+	}
+
+	if !strings.Contains(fake.prompts[0], formatMarkdownDE) {
+		t.Errorf("Expected German Markdown instructions, got %q", fake.prompts[0])
+	}
+
+	if _, err := client.SummarizeSections(context.Background(), "Some text."); !errors.Is(err, ErrUnsupportedOutputFormat) {
+		t.Errorf("Expected ErrUnsupportedOutputFormat, got %v", err)
+	}
+}
+
+func TestLexRank_OutputFormatSections(t *testing.T) {
+	client, err := New(NewConfig(WithType(TypeLexrank), WithOutputFormat(OutputFormatSections)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sections, err := client.SummarizeSections(context.Background(),
+		"The audit covered all production systems. Unpatched servers expose the company to a critical risk. The team should patch all servers within 30 days.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if sections.Overview == "" {
+		t.Error("Expected an overview")
+	}
+
+	if len(sections.KeyRisks) != 1 || !strings.Contains(sections.KeyRisks[0], "risk") {
+		t.Errorf("Unexpected key risks: %q", sections.KeyRisks)
+	}
+
+	if len(sections.Recommendations) != 1 || !strings.Contains(sections.Recommendations[0], "should") {
+		t.Errorf("Unexpected recommendations: %q", sections.Recommendations)
+	}
+}
+
+func TestMapSections(t *testing.T) {
+	got := mapSections("You should rotate keys. Keys are exposed in the repository!")
+	want := &Sections{
+		Overview:        "You should rotate keys.",
+		KeyRisks:        []string{"Keys are exposed in the repository!"},
+		Recommendations: []string{"You should rotate keys."},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestNew_InvalidOutputFormat(t *testing.T) {
+	if _, err := New(NewConfig(WithOutputFormat("html"))); !errors.Is(err, ErrUnsupportedOutputFormat) {
+		t.Errorf("Expected ErrUnsupportedOutputFormat, got %v", err)
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	glossaryInstructionsEN = "Use the following terminology. Never use the term on the left, always use the term on the right instead:\n%s"
+
+	glossaryInstructionsDE = "Verwende die folgende Terminologie. Verwende nie den Begriff links, sondern immer den Begriff rechts:\n%s"
+)
+
+// Substitution reports how often a disallowed synonym was replaced by its
+// preferred term in a summary.
+type Substitution struct {
+	// From is the disallowed synonym as configured in the glossary.
+	From string
+	// To is the preferred term it was replaced with.
+	To string
+	// Count is the number of replaced occurrences.
+	Count int
+}
+
+// SummaryReport is the result of SummarizeWithReport.
+type SummaryReport struct {
+	// Summary is the summary after glossary enforcement.
+	Summary string
+	// Substitutions lists the replacements made to enforce the glossary.
+	// It is empty if no glossary is configured or the LLM already used the
+	// preferred terminology.
+	Substitutions []Substitution
+}
+
+// glossaryTerm is a single compiled glossary entry.
+type glossaryTerm struct {
+	from    string
+	to      string
+	pattern *regexp.Regexp
+	target  *regexp.Regexp
+}
+
+// Glossary enforces customer-specific terminology. It maps disallowed
+// synonyms to the preferred term, e.g. "processing activity" to
+// "Verarbeitungstätigkeit".
+//
+// Synonyms are matched case-insensitively and only as whole words.
+type Glossary struct {
+	terms []glossaryTerm
+}
+
+// NewGlossary compiles a glossary from a map of disallowed synonyms to
+// preferred terms. Returns ErrInvalidGlossary if a synonym or term is empty.
+func NewGlossary(terms map[string]string) (*Glossary, error) {
+	g := &Glossary{terms: make([]glossaryTerm, 0, len(terms))}
+
+	for from, to := range terms {
+		from = strings.TrimSpace(from)
+		to = strings.TrimSpace(to)
+
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("%w: %q => %q", ErrInvalidGlossary, from, to)
+		}
+
+		g.terms = append(g.terms, glossaryTerm{
+			from:    from,
+			to:      to,
+			pattern: regexp.MustCompile(`(?i)` + regexp.QuoteMeta(from)),
+			target:  regexp.MustCompile(`(?i)` + regexp.QuoteMeta(to)),
+		})
+	}
+
+	// Replace longer synonyms first so that "data processing activity" wins
+	// over "processing activity".
+	sort.Slice(g.terms, func(i, j int) bool {
+		if len(g.terms[i].from) != len(g.terms[j].from) {
+			return len(g.terms[i].from) > len(g.terms[j].from)
+		}
+
+		return g.terms[i].from < g.terms[j].from
+	})
+
+	return g, nil
+}
+
+// Instructions returns the terminology constraints to add to a prompt in
+// the given language ("German" or any other value for English).
+func (g *Glossary) Instructions(lang string) string {
+	if g == nil || len(g.terms) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	for _, t := range g.terms {
+		fmt.Fprintf(&b, "- %q -> %q\n", t.from, t.to)
+	}
+
+	format := glossaryInstructionsEN
+	if lang == "German" {
+		format = glossaryInstructionsDE
+	}
+
+	return fmt.Sprintf(format, b.String())
+}
+
+// Apply replaces all disallowed synonyms in text with their preferred terms
+// and reports the substitutions made.
+func (g *Glossary) Apply(text string) (string, []Substitution) {
+	if g == nil {
+		return text, nil
+	}
+
+	var subs []Substitution
+
+	for _, t := range g.terms {
+		var (
+			out   strings.Builder
+			last  int
+			count int
+		)
+
+		protected := t.target.FindAllStringIndex(text, -1)
+
+		for _, m := range t.pattern.FindAllStringIndex(text, -1) {
+			match := text[m[0]:m[1]]
+
+			if match == t.to || !isWholeWord(text, m[0], m[1]) || within(protected, m) {
+				continue
+			}
+
+			out.WriteString(text[last:m[0]])
+			out.WriteString(matchCase(match, t.to))
+
+			last = m[1]
+			count++
+		}
+
+		if count == 0 {
+			continue
+		}
+
+		out.WriteString(text[last:])
+		text = out.String()
+
+		subs = append(subs, Substitution{From: t.from, To: t.to, Count: count})
+	}
+
+	return text, subs
+}
+
+// isWholeWord reports whether text[start:end] is not part of a longer word.
+func isWholeWord(text string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(text[:start])
+		if isWordRune(r) {
+			return false
+		}
+	}
+
+	if end < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[end:])
+		if isWordRune(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// within reports whether m lies inside one of the given ranges, i.e. the
+// synonym is part of an occurrence of the preferred term.
+func within(ranges [][]int, m []int) bool {
+	for _, r := range ranges {
+		if m[0] >= r[0] && m[1] <= r[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchCase capitalizes term if the replaced text started with an upper case
+// letter, e.g. at the beginning of a sentence.
+func matchCase(replaced, term string) string {
+	first, _ := utf8.DecodeRuneInString(replaced)
+	if !unicode.IsUpper(first) {
+		return term
+	}
+
+	r, size := utf8.DecodeRuneInString(term)
+
+	return string(unicode.ToUpper(r)) + term[size:]
+}
+
+// WithGlossary enforces customer-specific terminology in summaries. The map
+// keys are disallowed synonyms, the values the preferred terms.
+//
+// LLM prompts are extended with the terminology constraints and every
+// summary is post-processed to replace remaining synonyms; see
+// Client.SummarizeWithReport for the list of substitutions.
+//
+// Example:
+//
+//	config := NewConfig(
+//		WithType(TypeLlm),
+//		WithOpenAI("gpt-4", "sk-..."),
+//		WithGlossary(map[string]string{
+//			"processing activity": "Verarbeitungstätigkeit",
+//		}),
+//	)
+func WithGlossary(terms map[string]string) Option {
+	return func(c *Config) {
+		c.Glossary = make(map[string]string, len(terms))
+
+		for k, v := range terms {
+			c.Glossary[k] = v
+		}
+	}
+}
+
+// SummarizeWithReport is like Summarize but also reports the glossary
+// substitutions applied to the summary.
+func (s *Client) SummarizeWithReport(ctx context.Context, sentence string) (*SummaryReport, error) {
+	target, _, err := s.route(ctx, sentence)
+	if err != nil {
+		return nil, err
+	}
+
+	if target != s {
+		return target.SummarizeWithReport(ctx, sentence)
+	}
+
+	cleanInput := s.sanitizer.Sanitize(sentence)
+	if cleanInput == "" {
+		return nil, ErrSentenceEmpty
+	}
+
+	redactions := s.redactionMap(cleanInput)
+
+	summary, err := s.impl.Summarize(ctx, redactions.Protect(cleanInput))
+	if err != nil {
+		return nil, err
+	}
+
+	summary, _, subs, err := s.finishSummary(summary, redactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SummaryReport{Summary: summary, Substitutions: subs}, nil
+}
+
+// finishSummary converts the protected summary into the output format,
+// enforces the glossary and restores the redacted placeholders. The
+// glossary is applied while placeholders are protected, so that it cannot
+// rewrite them, and after parsing, so that for OutputFormatSections it only
+// touches the section values and never the JSON keys.
+func (s *Client) finishSummary(summary string, redactions *RedactionMap) (string, *Sections, []Substitution, error) {
+	summary, sections, err := s.applyFormat(summary)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var subs []Substitution
+
+	finish := func(text string) (string, error) {
+		text, applied := s.glossary.Apply(text)
+		subs = mergeSubstitutions(subs, applied)
+
+		return redactions.Restore(text)
+	}
+
+	if sections == nil {
+		if summary, err = finish(summary); err != nil {
+			return "", nil, nil, err
+		}
+
+		return summary, nil, subs, nil
+	}
+
+	if sections.Overview, err = finish(sections.Overview); err != nil {
+		return "", nil, nil, err
+	}
+
+	for _, items := range [][]string{sections.KeyRisks, sections.Recommendations} {
+		for i := range items {
+			if items[i], err = finish(items[i]); err != nil {
+				return "", nil, nil, err
+			}
+		}
+	}
+
+	b, err := json.Marshal(sections)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return string(b), sections, subs, nil
+}
+
+// mergeSubstitutions adds the counts of applied to subs, keeping the order
+// in which the terms were first substituted.
+func mergeSubstitutions(subs, applied []Substitution) []Substitution {
+	for _, a := range applied {
+		merged := false
+
+		for i := range subs {
+			if subs[i].From == a.From && subs[i].To == a.To {
+				subs[i].Count += a.Count
+				merged = true
+
+				break
+			}
+		}
+
+		if !merged {
+			subs = append(subs, a)
+		}
+	}
+
+	return subs
+}
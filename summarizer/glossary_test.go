@@ -0,0 +1,139 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// fixedLLM is a fake LLMClient that records prompts and returns a fixed answer.
+type fixedLLM struct {
+	answer  string
+	prompts []string
+}
+
+func (f *fixedLLM) Generate(_ context.Context, prompt string) (string, error) {
+	f.prompts = append(f.prompts, prompt)
+	return f.answer, nil
+}
+
+func TestGlossary_Apply(t *testing.T) {
+	g, err := NewGlossary(map[string]string{
+		"processing activity":      "Verarbeitungstätigkeit",
+		"data processing activity": "Verarbeitungstätigkeit",
+		"vendor":                   "supplier",
+		"activity":                 "Vorgang",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+		subs int
+	}{
+		{
+			name: "replaces synonyms case-insensitively",
+			in:   "The Processing Activity is documented.",
+			want: "The Verarbeitungstätigkeit is documented.",
+			subs: 1,
+		},
+		{
+			name: "prefers longer synonyms",
+			in:   "Each data processing activity has an owner.",
+			want: "Each Verarbeitungstätigkeit has an owner.",
+			subs: 1,
+		},
+		{
+			name: "keeps sentence case",
+			in:   "Vendor risk is high. The vendor is reviewed.",
+			want: "Supplier risk is high. The supplier is reviewed.",
+			subs: 1,
+		},
+		{
+			name: "matches whole words only",
+			in:   "Vendors and vendoring are unchanged.",
+			want: "Vendors and vendoring are unchanged.",
+		},
+		{
+			name: "does not touch preferred terms",
+			in:   "The supplier is fine.",
+			want: "The supplier is fine.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, subs := g.Apply(tt.in)
+			if got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+
+			if len(subs) != tt.subs {
+				t.Errorf("Expected %d substitutions, got %v", tt.subs, subs)
+			}
+		})
+	}
+}
+
+func TestGlossary_ApplyReportsCounts(t *testing.T) {
+	g, err := NewGlossary(map[string]string{"vendor": "supplier"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, subs := g.Apply("vendor, vendor and vendor")
+	if len(subs) != 1 || subs[0] != (Substitution{From: "vendor", To: "supplier", Count: 3}) {
+		t.Errorf("Unexpected substitutions: %v", subs)
+	}
+}
+
+func TestNewGlossary_Invalid(t *testing.T) {
+	_, err := NewGlossary(map[string]string{"vendor": " "})
+	if !errors.Is(err, ErrInvalidGlossary) {
+		t.Errorf("Expected ErrInvalidGlossary, got %v", err)
+	}
+
+	_, err = New(NewConfig(WithGlossary(map[string]string{"": "supplier"})))
+	if !errors.Is(err, ErrInvalidGlossary) {
+		t.Errorf("Expected ErrInvalidGlossary from New, got %v", err)
+	}
+}
+
+func TestClient_SummarizeWithReport_Glossary(t *testing.T) {
+	g, err := NewGlossary(map[string]string{"processing activity": "Verarbeitungstätigkeit"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fake := &fixedLLM{answer: "The processing activity lacks a legal basis."}
+	llmSummarizer := NewLLMSummarizer(fake)
+	llmSummarizer.glossary = g
+
+	client := &Client{impl: llmSummarizer, sanitizer: bluemonday.StrictPolicy(), glossary: g}
+
+	report, err := client.SummarizeWithReport(context.Background(), "A long text about processing activities and their legal basis.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.Summary != "The Verarbeitungstätigkeit lacks a legal basis." {
+		t.Errorf("Unexpected summary: %q", report.Summary)
+	}
+
+	if len(report.Substitutions) != 1 || report.Substitutions[0].Count != 1 {
+		t.Errorf("Unexpected substitutions: %v", report.Substitutions)
+	}
+
+	if len(fake.prompts) != 1 || !strings.Contains(fake.prompts[0], `"processing activity" -> "Verarbeitungstätigkeit"`) {
+		t.Errorf("Expected glossary instructions in prompt, got %v", fake.prompts)
+	}
+}
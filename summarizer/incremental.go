@@ -0,0 +1,186 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/abadojack/whatlanggo"
+)
+
+// DefaultResummarizeEvery is the number of incremental updates after which
+// SummarizeIncremental summarizes the full document again.
+const DefaultResummarizeEvery = 10
+
+const (
+	promptIncrementalEN = `Below is the summary of a document, followed by new content that was appended to the document. Update the summary in English so that it also covers the new content. Keep the facts of the existing summary unless the new content supersedes them. Be brief, concise and precise. Do not explain.
+
+Summary:
+%s
+
+New content:
+%s`
+
+	promptIncrementalDE = `Unten steht die Zusammenfassung eines Dokuments, gefolgt von neuem Inhalt, der an das Dokument angehängt wurde. Aktualisiere die Zusammenfassung auf Deutsch, sodass sie auch den neuen Inhalt abdeckt. Behalte die Fakten der bisherigen Zusammenfassung bei, sofern der neue Inhalt sie nicht ersetzt. Sei kurz, prägnant und präzise. Keine Erklärungen.
+
+Zusammenfassung:
+%s
+
+Neuer Inhalt:
+%s`
+)
+
+// IncrementalSummary is the state of an incrementally summarized, append-only
+// document such as meeting notes or an audit log. Callers store it with the
+// document and pass it to the next SummarizeIncremental call.
+type IncrementalSummary struct {
+	// Summary is the current summary of the document.
+	Summary string `json:"summary"`
+	// Updates is the number of increments folded into Summary since it was
+	// last produced from the full document.
+	Updates int `json:"updates"`
+}
+
+// IncrementalOption configures SummarizeIncremental.
+type IncrementalOption func(*incrementalConfig)
+
+type incrementalConfig struct {
+	every    int
+	fullText func(ctx context.Context) (string, error)
+}
+
+// WithFullText enables drift control: every ResummarizeEvery updates, the
+// summary is produced from scratch from the text returned by fn, the full
+// document including the new content. Without it, summaries are only ever
+// updated incrementally.
+func WithFullText(fn func(ctx context.Context) (string, error)) IncrementalOption {
+	return func(c *incrementalConfig) {
+		c.fullText = fn
+	}
+}
+
+// WithResummarizeEvery sets after how many incremental updates the summary
+// is produced from the full document again. Defaults to
+// DefaultResummarizeEvery; values below 1 are ignored.
+func WithResummarizeEvery(n int) IncrementalOption {
+	return func(c *incrementalConfig) {
+		if n > 0 {
+			c.every = n
+		}
+	}
+}
+
+// incrementalSummarizer is implemented by summarizers that can fold new
+// content into an existing summary.
+type incrementalSummarizer interface {
+	SummarizeIncremental(ctx context.Context, previous, newText string) (string, error)
+}
+
+// SummarizeIncremental folds newText, content appended to a document, into
+// the previous summary of the document instead of summarizing the whole
+// document again.
+//
+// Repeated incremental updates may drift away from what a summary of the full
+// document would say. With WithFullText, the summary is therefore produced
+// from the full document once previous.Updates reaches ResummarizeEvery, and
+// Updates starts over.
+//
+// If previous has no summary yet, newText is summarized like in Summarize.
+// If newText is empty after sanitizing, previous is returned unchanged.
+//
+// Example:
+//
+//	state, err := client.SummarizeIncremental(ctx, notes.Summary, entry.Text,
+//	    summarizer.WithFullText(func(ctx context.Context) (string, error) {
+//	        return notes.FullText(ctx)
+//	    }))
+//	notes.Summary = state
+func (s *Client) SummarizeIncremental(ctx context.Context, previous IncrementalSummary, newText string, opts ...IncrementalOption) (IncrementalSummary, error) {
+	target, _, err := s.route(ctx, previous.Summary, newText)
+	if err != nil {
+		return previous, err
+	}
+
+	if target != s {
+		return target.SummarizeIncremental(ctx, previous, newText, opts...)
+	}
+
+	cfg := &incrementalConfig{every: DefaultResummarizeEvery}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	cleanText := strings.TrimSpace(s.sanitizer.Sanitize(newText))
+	if cleanText == "" {
+		return previous, nil
+	}
+
+	if previous.Summary == "" {
+		summary, err := s.Summarize(ctx, cleanText)
+		if err != nil {
+			return previous, err
+		}
+
+		return IncrementalSummary{Summary: summary}, nil
+	}
+
+	if cfg.fullText != nil && previous.Updates >= cfg.every {
+		text, err := cfg.fullText(ctx)
+		if err != nil {
+			return previous, fmt.Errorf("failed to load full text: %w", err)
+		}
+
+		summary, err := s.Summarize(ctx, text)
+		if err != nil {
+			return previous, err
+		}
+
+		return IncrementalSummary{Summary: summary}, nil
+	}
+
+	impl, ok := s.impl.(incrementalSummarizer)
+	if !ok {
+		return previous, ErrIncrementalNotSupported
+	}
+
+	redactions := s.redactionMap(previous.Summary, cleanText)
+
+	summary, err := impl.SummarizeIncremental(ctx, redactions.Protect(previous.Summary), redactions.Protect(cleanText))
+	if err != nil {
+		return previous, err
+	}
+
+	summary, _ = s.glossary.Apply(summary)
+
+	if summary, err = redactions.Restore(summary); err != nil {
+		return previous, err
+	}
+
+	return IncrementalSummary{Summary: summary, Updates: previous.Updates + 1}, nil
+}
+
+// SummarizeIncremental asks the LLM to update the previous summary with the
+// new content, in the language of the new content.
+func (l *LLMSummarizer) SummarizeIncremental(ctx context.Context, previous, newText string) (string, error) {
+	lang := whatlanggo.Detect(newText).Lang.String()
+
+	prompt := promptIncrementalEN
+	if lang == "German" {
+		prompt = promptIncrementalDE
+	}
+
+	text := l.withGlossary(lang, withRedactionInstructions(lang, newText))
+
+	return l.llmClient.Generate(ctx, fmt.Sprintf(prompt, previous, text))
+}
+
+// SummarizeIncremental ranks the sentences of the previous summary together
+// with the new content. As the previous summary holds the most central
+// sentences of the document so far, this approximates ranking the full
+// document.
+func (l *lexRankSummarizer) SummarizeIncremental(ctx context.Context, previous, newText string) (string, error) {
+	return l.Summarize(ctx, strings.TrimSpace(previous)+"\n\n"+newText)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+func TestClient_SummarizeIncremental(t *testing.T) {
+	fake := &recordingLLM{}
+	c := &Client{impl: NewLLMSummarizer(fake), sanitizer: bluemonday.StrictPolicy()}
+	ctx := context.Background()
+
+	state, err := c.SummarizeIncremental(ctx, IncrementalSummary{}, "The audit started on Monday.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if state.Summary != "summary" || state.Updates != 0 {
+		t.Errorf("Expected initial summary without updates, got %+v", state)
+	}
+
+	state, err = c.SummarizeIncremental(ctx, IncrementalSummary{Summary: "The audit started on Monday.", Updates: 2}, "<p>Two findings were raised.</p>")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if state.Updates != 3 {
+		t.Errorf("Expected 3 updates, got %d", state.Updates)
+	}
+
+	prompt := fake.prompts[len(fake.prompts)-1]
+	if !strings.Contains(prompt, "The audit started on Monday.") || !strings.Contains(prompt, "Two findings were raised.") {
+		t.Errorf("Expected prompt to contain previous summary and new content, got %q", prompt)
+	}
+
+	if strings.Contains(prompt, "<p>") {
+		t.Errorf("Expected new content to be sanitized, got %q", prompt)
+	}
+
+	unchanged, err := c.SummarizeIncremental(ctx, state, "<b> </b>")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if unchanged != state {
+		t.Errorf("Expected empty content to keep the state, got %+v", unchanged)
+	}
+}
+
+func TestClient_SummarizeIncremental_DriftControl(t *testing.T) {
+	fake := &recordingLLM{}
+	c := &Client{impl: NewLLMSummarizer(fake), sanitizer: bluemonday.StrictPolicy()}
+	ctx := context.Background()
+
+	loads := 0
+	fullText := WithFullText(func(context.Context) (string, error) {
+		loads++
+		return "The audit started on Monday. Two findings were raised. Both were closed.", nil
+	})
+
+	state, err := c.SummarizeIncremental(ctx, IncrementalSummary{Summary: "previous", Updates: 2}, "Both were closed.", WithResummarizeEvery(3), fullText)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if loads != 0 || state.Updates != 3 {
+		t.Errorf("Expected incremental update, got %+v after %d loads", state, loads)
+	}
+
+	state, err = c.SummarizeIncremental(ctx, state, "Both were closed.", WithResummarizeEvery(3), fullText)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if loads != 1 || state.Updates != 0 {
+		t.Errorf("Expected full re-summarization, got %+v after %d loads", state, loads)
+	}
+
+	if !strings.Contains(fake.prompts[len(fake.prompts)-1], "Both were closed.") {
+		t.Errorf("Expected full text in prompt, got %q", fake.prompts[len(fake.prompts)-1])
+	}
+
+	// without a full text source, updates continue incrementally
+	state, err = c.SummarizeIncremental(ctx, IncrementalSummary{Summary: "previous", Updates: 50}, "More content.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if state.Updates != 51 {
+		t.Errorf("Expected 51 updates, got %d", state.Updates)
+	}
+
+	errLoad := errors.New("storage unavailable")
+	_, err = c.SummarizeIncremental(ctx, IncrementalSummary{Summary: "previous", Updates: 10}, "More content.",
+		WithFullText(func(context.Context) (string, error) { return "", errLoad }))
+	if !errors.Is(err, errLoad) {
+		t.Errorf("Expected load error, got %v", err)
+	}
+}
+
+func TestLexRankSummarizer_SummarizeIncremental(t *testing.T) {
+	c := &Client{impl: &lexRankSummarizer{maxSentences: 2}, sanitizer: bluemonday.StrictPolicy()}
+
+	state, err := c.SummarizeIncremental(context.Background(),
+		IncrementalSummary{Summary: "The audit started on Monday. The auditors reviewed access controls."},
+		"The auditors raised two findings on access controls. The findings were closed on Friday.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if state.Summary == "" || state.Updates != 1 {
+		t.Errorf("Expected updated summary, got %+v", state)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/abadojack/whatlanggo"
 
 	"github.com/kopexa-grc/common/llm"
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -34,6 +35,8 @@ type LLMClient interface {
 // LLMSummarizer implements summarization using LLM clients
 type LLMSummarizer struct {
 	llmClient LLMClient
+	glossary  *Glossary
+	format    OutputFormat
 }
 
 // NewLLMSummarizer creates a summarizer from an existing LLMClient
@@ -113,21 +116,84 @@ func NewLLMSummarizerFromConfig(cfg Config) (*LLMSummarizer, error) {
 		return nil, err
 	}
 
-	return NewLLMSummarizer(client), nil
+	s := NewLLMSummarizer(client)
+	s.format = cfg.OutputFormat
+
+	if len(cfg.Glossary) > 0 {
+		if s.glossary, err = NewGlossary(cfg.Glossary); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// usageLLMClient is implemented by LLM clients that report token usage, such
+// as *llm.Client.
+type usageLLMClient interface {
+	GenerateWithUsage(ctx context.Context, prompt string, options ...llms.CallOption) (string, llm.TokenUsage, error)
 }
 
 // Summarize returns a shortened version of the provided string using the selected llm
 func (l *LLMSummarizer) Summarize(ctx context.Context, s string) (string, error) {
-	langInfo := whatlanggo.Detect(s)
+	return l.llmClient.Generate(ctx, l.prompt(s))
+}
+
+// SummarizeWithUsage is like Summarize but also reports the tokens consumed.
+// The usage is zero if the LLM client does not report it.
+func (l *LLMSummarizer) SummarizeWithUsage(ctx context.Context, s string) (string, llm.TokenUsage, error) {
+	if c, ok := l.llmClient.(usageLLMClient); ok {
+		return c.GenerateWithUsage(ctx, l.prompt(s))
+	}
+
+	summary, err := l.Summarize(ctx, s)
+
+	return summary, llm.TokenUsage{}, err
+}
+
+// prompt builds the summarization prompt in the language of s.
+func (l *LLMSummarizer) prompt(s string) string {
+	lang := whatlanggo.Detect(s).Lang.String()
 
 	var prompt string
 
-	switch langInfo.Lang.String() {
+	switch lang {
 	case "German":
 		prompt = promptDE
 	default:
 		prompt = promptEN
 	}
 
-	return l.llmClient.Generate(ctx, fmt.Sprintf(prompt, s))
+	return fmt.Sprintf(prompt, l.withFormat(lang, l.withGlossary(lang, withRedactionInstructions(lang, s))))
+}
+
+// withRedactionInstructions prepends the instructions for protected
+// placeholders, if text contains any.
+func withRedactionInstructions(lang, text string) string {
+	instructions := redactionInstructions(lang, text)
+	if instructions == "" {
+		return text
+	}
+
+	return instructions + "\n" + text
+}
+
+// withFormat prepends the output format instructions, if any, to the text.
+func (l *LLMSummarizer) withFormat(lang, text string) string {
+	instructions := formatInstructions(l.format, lang)
+	if instructions == "" {
+		return text
+	}
+
+	return instructions + "\n\n" + text
+}
+
+// withGlossary prepends the glossary instructions, if any, to the text.
+func (l *LLMSummarizer) withGlossary(lang, text string) string {
+	instructions := l.glossary.Instructions(lang)
+	if instructions == "" {
+		return text
+	}
+
+	return instructions + "\n" + text
 }
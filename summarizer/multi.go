@@ -0,0 +1,228 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/didasy/tldr"
+)
+
+// MultiMode selects how multiple documents are summarized together.
+type MultiMode string
+
+const (
+	// MultiModeAggregate produces a single merged summary across all documents.
+	MultiModeAggregate MultiMode = "aggregate"
+
+	// MultiModeComparative produces a comparison that highlights similarities
+	// and differences between the documents.
+	MultiModeComparative MultiMode = "comparative"
+)
+
+const (
+	promptMapEN = `Summarize the following document titled %q in English. Be brief, concise and precise. Keep all facts that could matter when comparing it with other documents.
+
+%s`
+
+	promptReduceAggregateEN = `Below are summaries of several documents. Merge them into a single, coherent summary in English. Remove duplicated information. Be brief, concise and precise. Do not explain.
+
+%s`
+
+	promptReduceComparativeEN = `Below are summaries of several documents. Compare them in English: first state briefly what they have in common, then list the key differences per document. Be brief, concise and precise. Do not explain.
+
+%s`
+)
+
+// Document is a single input document for multi-document summarization.
+type Document struct {
+	// ID identifies the document. Optional.
+	ID string
+	// Title is a human-readable name used to label the document in the output.
+	// Defaults to ID, or "Document <n>" if both are empty.
+	Title string
+	// Content is the text to summarize.
+	Content string
+}
+
+// label returns the name used to refer to the document at position i.
+func (d Document) label(i int) string {
+	switch {
+	case d.Title != "":
+		return d.Title
+	case d.ID != "":
+		return d.ID
+	default:
+		return fmt.Sprintf("Document %d", i+1)
+	}
+}
+
+// multiSummarizer is implemented by summarizers that support multi-document
+// summarization.
+type multiSummarizer interface {
+	SummarizeMulti(ctx context.Context, docs []Document, mode MultiMode) (string, error)
+}
+
+// SummarizeMulti summarizes several documents at once. In MultiModeAggregate
+// a single merged summary is returned; in MultiModeComparative the result
+// compares the documents and highlights their differences.
+//
+// Document contents are sanitized like in Summarize; documents that are empty
+// after sanitizing are skipped.
+func (s *Client) SummarizeMulti(ctx context.Context, docs []Document, mode MultiMode) (string, error) {
+	if mode != MultiModeAggregate && mode != MultiModeComparative {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedMultiMode, mode)
+	}
+
+	texts := make([]string, len(docs))
+	for i, d := range docs {
+		texts[i] = d.Content
+	}
+
+	target, _, err := s.route(ctx, texts...)
+	if err != nil {
+		return "", err
+	}
+
+	if target != s {
+		return target.SummarizeMulti(ctx, docs, mode)
+	}
+
+	clean := make([]Document, 0, len(docs))
+	contents := make([]string, 0, len(docs))
+
+	for i, d := range docs {
+		content := strings.TrimSpace(s.sanitizer.Sanitize(d.Content))
+		if content == "" {
+			continue
+		}
+
+		clean = append(clean, Document{ID: d.ID, Title: d.label(i), Content: content})
+		contents = append(contents, content)
+	}
+
+	redactions := s.redactionMap(contents...)
+	for i := range clean {
+		clean[i].Content = redactions.Protect(clean[i].Content)
+	}
+
+	if len(clean) == 0 {
+		return "", ErrSentenceEmpty
+	}
+
+	m, ok := s.impl.(multiSummarizer)
+	if !ok {
+		return "", ErrMultiNotSupported
+	}
+
+	summary, err := m.SummarizeMulti(ctx, clean, mode)
+	if err != nil {
+		return "", err
+	}
+
+	summary, _ = s.glossary.Apply(summary)
+
+	return redactions.Restore(summary)
+}
+
+// SummarizeMulti implements map-reduce summarization: every document is
+// summarized on its own (map), then the partial summaries are merged or
+// compared in a final request (reduce).
+func (l *LLMSummarizer) SummarizeMulti(ctx context.Context, docs []Document, mode MultiMode) (string, error) {
+	if len(docs) == 0 {
+		return "", ErrSentenceEmpty
+	}
+
+	var b strings.Builder
+
+	for i, d := range docs {
+		partial, err := l.llmClient.Generate(ctx, fmt.Sprintf(promptMapEN, d.label(i), withRedactionInstructions("English", d.Content)))
+		if err != nil {
+			return "", fmt.Errorf("failed to summarize %q: %w", d.label(i), err)
+		}
+
+		fmt.Fprintf(&b, "## %s\n%s\n\n", d.label(i), strings.TrimSpace(partial))
+	}
+
+	prompt := promptReduceAggregateEN
+	if mode == MultiModeComparative {
+		prompt = promptReduceComparativeEN
+	}
+
+	return l.llmClient.Generate(ctx, fmt.Sprintf(prompt, l.withGlossary("English", withRedactionInstructions("English", strings.TrimSpace(b.String())))))
+}
+
+// SummarizeMulti implements cluster-based extractive summarization: each
+// document forms a cluster from which the most central sentences are
+// selected. In comparative mode the selections are returned per document;
+// in aggregate mode they are ranked again across documents and duplicates
+// are dropped.
+func (l *lexRankSummarizer) SummarizeMulti(ctx context.Context, docs []Document, mode MultiMode) (string, error) {
+	if len(docs) == 0 {
+		return "", ErrSentenceEmpty
+	}
+
+	selections := make([][]string, len(docs))
+
+	for i, d := range docs {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		sentences, err := tldr.New().Summarize(d.Content, l.maxSentences)
+		if err != nil {
+			return "", err
+		}
+
+		if len(sentences) == 0 {
+			sentences = []string{d.Content}
+		}
+
+		selections[i] = sentences
+	}
+
+	if mode == MultiModeComparative {
+		var b strings.Builder
+
+		for i, d := range docs {
+			fmt.Fprintf(&b, "%s: %s\n", d.label(i), strings.Join(selections[i], " "))
+		}
+
+		return strings.TrimSpace(b.String()), nil
+	}
+
+	seen := make(map[string]bool)
+
+	var candidates []string
+
+	for _, sel := range selections {
+		for _, s := range sel {
+			key := strings.ToLower(strings.TrimSpace(s))
+			if key == "" || seen[key] {
+				continue
+			}
+
+			seen[key] = true
+
+			candidates = append(candidates, strings.TrimSpace(s))
+		}
+	}
+
+	if len(candidates) <= l.maxSentences {
+		return strings.Join(candidates, " "), nil
+	}
+
+	ranked, err := tldr.New().Summarize(strings.Join(candidates, " "), l.maxSentences)
+	if err != nil {
+		return "", err
+	}
+
+	if len(ranked) == 0 {
+		ranked = candidates[:l.maxSentences]
+	}
+
+	return strings.Join(ranked, " "), nil
+}
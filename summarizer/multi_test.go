@@ -0,0 +1,145 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// recordingLLM is a fake LLMClient that records prompts and echoes a fixed answer.
+type recordingLLM struct {
+	prompts []string
+}
+
+func (r *recordingLLM) Generate(_ context.Context, prompt string) (string, error) {
+	r.prompts = append(r.prompts, prompt)
+	return "summary", nil
+}
+
+func TestLLMSummarizer_SummarizeMulti(t *testing.T) {
+	docs := []Document{
+		{Title: "Vendor A", Content: "Vendor A encrypts data at rest."},
+		{Title: "Vendor B", Content: "Vendor B does not encrypt data at rest."},
+	}
+
+	tests := []struct {
+		name       string
+		mode       MultiMode
+		wantReduce string
+	}{
+		{name: "aggregate", mode: MultiModeAggregate, wantReduce: "Merge them"},
+		{name: "comparative", mode: MultiModeComparative, wantReduce: "Compare them"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &recordingLLM{}
+			s := NewLLMSummarizer(fake)
+
+			result, err := s.SummarizeMulti(context.Background(), docs, tt.mode)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result != "summary" {
+				t.Errorf("Expected reduced summary, got %q", result)
+			}
+
+			// One map request per document plus one reduce request.
+			if len(fake.prompts) != len(docs)+1 {
+				t.Fatalf("Expected %d prompts, got %d", len(docs)+1, len(fake.prompts))
+			}
+
+			reduce := fake.prompts[len(fake.prompts)-1]
+			if !strings.Contains(reduce, tt.wantReduce) {
+				t.Errorf("Reduce prompt does not contain %q: %s", tt.wantReduce, reduce)
+			}
+
+			if !strings.Contains(reduce, "## Vendor A") || !strings.Contains(reduce, "## Vendor B") {
+				t.Errorf("Reduce prompt does not label documents: %s", reduce)
+			}
+		})
+	}
+}
+
+func TestLexRankSummarizer_SummarizeMulti(t *testing.T) {
+	s, err := newLexRankSummarizer(2)
+	if err != nil {
+		t.Fatalf("Failed to create summarizer: %v", err)
+	}
+
+	docs := []Document{
+		{Title: "A", Content: "The cat sat on the mat. The cat was happy. The weather was nice."},
+		{Title: "B", Content: "The dog ran in the park. The dog was tired. The cat sat on the mat."},
+	}
+
+	comparative, err := s.SummarizeMulti(context.Background(), docs, MultiModeComparative)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(comparative, "A: ") || !strings.Contains(comparative, "\nB: ") {
+		t.Errorf("Expected per-document sections, got %q", comparative)
+	}
+
+	aggregate, err := s.SummarizeMulti(context.Background(), docs, MultiModeAggregate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if aggregate == "" {
+		t.Error("Expected non-empty aggregate summary")
+	}
+
+	if strings.Count(aggregate, "The cat sat on the mat.") > 1 {
+		t.Errorf("Expected duplicate sentences to be removed, got %q", aggregate)
+	}
+}
+
+func TestClient_SummarizeMulti(t *testing.T) {
+	fake := &recordingLLM{}
+	c := &Client{impl: NewLLMSummarizer(fake), sanitizer: bluemonday.StrictPolicy()}
+
+	_, err := c.SummarizeMulti(context.Background(), []Document{{Content: "x"}}, "unknown")
+	if !errors.Is(err, ErrUnsupportedMultiMode) {
+		t.Errorf("Expected ErrUnsupportedMultiMode, got %v", err)
+	}
+
+	_, err = c.SummarizeMulti(context.Background(), []Document{{Content: "<b></b>"}, {Content: "  "}}, MultiModeAggregate)
+	if !errors.Is(err, ErrSentenceEmpty) {
+		t.Errorf("Expected ErrSentenceEmpty, got %v", err)
+	}
+
+	_, err = c.SummarizeMulti(context.Background(), []Document{{ID: "doc-1", Content: "<p>Some text.</p>"}}, MultiModeAggregate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if strings.Contains(fake.prompts[0], "<p>") {
+		t.Errorf("Expected sanitized content, got %q", fake.prompts[0])
+	}
+
+	if !strings.Contains(fake.prompts[0], `"doc-1"`) {
+		t.Errorf("Expected document ID as label, got %q", fake.prompts[0])
+	}
+
+	unsupported := &Client{impl: &plainSummarizer{}, sanitizer: bluemonday.StrictPolicy()}
+
+	_, err = unsupported.SummarizeMulti(context.Background(), []Document{{Content: "text"}}, MultiModeAggregate)
+	if !errors.Is(err, ErrMultiNotSupported) {
+		t.Errorf("Expected ErrMultiNotSupported, got %v", err)
+	}
+}
+
+// plainSummarizer only supports single-document summarization.
+type plainSummarizer struct{}
+
+func (plainSummarizer) Summarize(_ context.Context, s string) (string, error) {
+	return s, nil
+}
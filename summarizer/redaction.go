@@ -0,0 +1,208 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	redactionInstructionsEN = "The text contains placeholders for redacted data: %s. Copy every placeholder you use exactly as written. Never alter, merge, translate or invent placeholders, and never guess the data they replace."
+
+	redactionInstructionsDE = "Der Text enthält Platzhalter für geschwärzte Daten: %s. Übernimm jeden verwendeten Platzhalter exakt wie geschrieben. Verändere, verbinde, übersetze oder erfinde keine Platzhalter und errate nie die ersetzten Daten."
+
+	// protectedTokenFormat is the format of the tokens that stand in for
+	// placeholders while summarizing. They are single words, so that
+	// tokenizers, sentence splitters and the glossary leave them intact.
+	protectedTokenFormat = "RDCTD%03d"
+
+	// redactionMarker is the word every placeholder of
+	// DefaultRedactionPattern contains.
+	redactionMarker = "REDACTED"
+)
+
+// DefaultRedactionPattern matches placeholders such as "[REDACTED-EMAIL-1]".
+var DefaultRedactionPattern = regexp.MustCompile(`\[REDACTED-[A-Z]+(?:-[A-Z]+)*-\d+\]`)
+
+// protectedTokenPattern matches tokens created by RedactionMap.Protect.
+var protectedTokenPattern = regexp.MustCompile(`RDCTD\d{3,}`)
+
+// RedactionMap passes redacted placeholders through summarization
+// unchanged. Protect replaces every placeholder of the input with an opaque
+// token, Restore puts the placeholders back into the summary and verifies
+// that none was altered or invented.
+//
+// A nil *RedactionMap passes text through unchanged.
+type RedactionMap struct {
+	pattern      *regexp.Regexp
+	placeholders []string
+	tokens       map[string]string
+}
+
+// NewRedactionMap collects the placeholders of text matched by pattern, or
+// by DefaultRedactionPattern if pattern is nil.
+func NewRedactionMap(text string, pattern *regexp.Regexp) *RedactionMap {
+	if pattern == nil {
+		pattern = DefaultRedactionPattern
+	}
+
+	m := &RedactionMap{pattern: pattern, tokens: make(map[string]string)}
+	m.add(text)
+
+	return m
+}
+
+// add collects the placeholders of text that are not yet known.
+func (m *RedactionMap) add(text string) {
+	for _, p := range m.pattern.FindAllString(text, -1) {
+		if _, ok := m.tokens[p]; ok {
+			continue
+		}
+
+		m.placeholders = append(m.placeholders, p)
+		m.tokens[p] = fmt.Sprintf(protectedTokenFormat, len(m.placeholders))
+	}
+}
+
+// Placeholders returns the placeholders in order of first appearance.
+func (m *RedactionMap) Placeholders() []string {
+	if m == nil {
+		return nil
+	}
+
+	return m.placeholders
+}
+
+// Protect replaces all known placeholders in text with their tokens.
+func (m *RedactionMap) Protect(text string) string {
+	if m == nil || len(m.placeholders) == 0 {
+		return text
+	}
+
+	return m.pattern.ReplaceAllStringFunc(text, func(p string) string {
+		if token, ok := m.tokens[p]; ok {
+			return token
+		}
+
+		return p
+	})
+}
+
+// Restore replaces the tokens in summary with their placeholders. It returns
+// ErrRedactionAltered if the summary contains unknown tokens or
+// placeholders, or remnants of placeholders that were changed. Placeholders
+// missing from the summary are not an error.
+func (m *RedactionMap) Restore(summary string) (string, error) {
+	if m == nil {
+		return summary, nil
+	}
+
+	byToken := make(map[string]string, len(m.tokens))
+	for p, token := range m.tokens {
+		byToken[token] = p
+	}
+
+	var unknown []string
+
+	summary = protectedTokenPattern.ReplaceAllStringFunc(summary, func(token string) string {
+		if p, ok := byToken[token]; ok {
+			return p
+		}
+
+		unknown = append(unknown, token)
+
+		return token
+	})
+
+	// Everything that is left after removing the valid placeholders must
+	// not look like a placeholder.
+	rest := m.pattern.ReplaceAllStringFunc(summary, func(p string) string {
+		if _, ok := m.tokens[p]; !ok {
+			unknown = append(unknown, p)
+		}
+
+		return ""
+	})
+
+	if m.pattern == DefaultRedactionPattern && strings.Contains(strings.ToUpper(rest), redactionMarker) {
+		unknown = append(unknown, redactionMarker)
+	}
+
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrRedactionAltered, strings.Join(unknown, ", "))
+	}
+
+	return summary, nil
+}
+
+// redactionInstructions returns the instructions for keeping the protected
+// tokens of text intact, or "" if text contains none.
+func redactionInstructions(lang, text string) string {
+	tokens := protectedTokenPattern.FindAllString(text, -1)
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	seen := make(map[string]bool, len(tokens))
+	unique := tokens[:0]
+
+	for _, t := range tokens {
+		if !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+
+	format := redactionInstructionsEN
+	if lang == "German" {
+		format = redactionInstructionsDE
+	}
+
+	return fmt.Sprintf(format, strings.Join(unique, ", "))
+}
+
+// WithRedactionPassThrough preserves redacted placeholders such as
+// "[REDACTED-EMAIL-1]" verbatim in summaries. Placeholders are replaced with
+// opaque tokens before summarizing, LLM prompts are told to keep them, and
+// the summary fails with ErrRedactionAltered if a placeholder was changed or
+// invented. Use WithRedactionPattern for other placeholder formats.
+//
+// Example:
+//
+//	config := NewConfig(
+//		WithType(TypeLlm),
+//		WithOpenAI("gpt-4", "sk-..."),
+//		WithRedactionPassThrough(),
+//	)
+func WithRedactionPassThrough() Option {
+	return func(c *Config) {
+		c.RedactionPassThrough = true
+	}
+}
+
+// WithRedactionPattern enables WithRedactionPassThrough for placeholders
+// matched by pattern instead of DefaultRedactionPattern.
+func WithRedactionPattern(pattern *regexp.Regexp) Option {
+	return func(c *Config) {
+		c.RedactionPassThrough = true
+		c.RedactionPattern = pattern
+	}
+}
+
+// redactionMap returns the RedactionMap for texts if pass-through is
+// enabled, or nil.
+func (s *Client) redactionMap(texts ...string) *RedactionMap {
+	if !s.redaction {
+		return nil
+	}
+
+	m := NewRedactionMap("", s.redactionPattern)
+	for _, t := range texts {
+		m.add(t)
+	}
+
+	return m
+}
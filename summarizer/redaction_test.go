@@ -0,0 +1,173 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// echoLLM is a fake LLMClient that records prompts and answers with the
+// result of fn applied to the prompt.
+type echoLLM struct {
+	fn      func(prompt string) string
+	prompts []string
+}
+
+func (e *echoLLM) Generate(_ context.Context, prompt string) (string, error) {
+	e.prompts = append(e.prompts, prompt)
+	return e.fn(prompt), nil
+}
+
+func TestRedactionMap_ProtectRestore(t *testing.T) {
+	text := "Contact [REDACTED-EMAIL-1] or [REDACTED-PHONE-2]. Again [REDACTED-EMAIL-1]."
+	m := NewRedactionMap(text, nil)
+
+	if got := m.Placeholders(); len(got) != 2 || got[0] != "[REDACTED-EMAIL-1]" || got[1] != "[REDACTED-PHONE-2]" {
+		t.Fatalf("Placeholders() = %v", got)
+	}
+
+	protected := m.Protect(text)
+	if strings.Contains(protected, "REDACTED") {
+		t.Fatalf("Protect() left placeholders: %q", protected)
+	}
+
+	restored, err := m.Restore(protected)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if restored != text {
+		t.Errorf("Restore() = %q, want %q", restored, text)
+	}
+}
+
+func TestRedactionMap_RestoreDetectsAlterations(t *testing.T) {
+	m := NewRedactionMap("Mail [REDACTED-EMAIL-1] now.", nil)
+
+	tests := []struct {
+		name    string
+		summary string
+		wantErr bool
+	}{
+		{name: "token kept", summary: "Mail RDCTD001."},
+		{name: "placeholder dropped", summary: "Mail the contact."},
+		{name: "original placeholder copied", summary: "Mail [REDACTED-EMAIL-1]."},
+		{name: "unknown token", summary: "Mail RDCTD002.", wantErr: true},
+		{name: "invented placeholder", summary: "Mail [REDACTED-EMAIL-7].", wantErr: true},
+		{name: "altered placeholder", summary: "Mail [REDACTED EMAIL 1].", wantErr: true},
+		{name: "translated marker", summary: "Mail the redacted email.", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.Restore(tt.summary)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Restore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, ErrRedactionAltered) {
+				t.Errorf("Restore() error = %v, want ErrRedactionAltered", err)
+			}
+		})
+	}
+}
+
+func TestRedactionMap_Nil(t *testing.T) {
+	var m *RedactionMap
+
+	if got := m.Protect("[REDACTED-EMAIL-1]"); got != "[REDACTED-EMAIL-1]" {
+		t.Errorf("Protect() = %q", got)
+	}
+
+	if got, err := m.Restore("anything REDACTED"); err != nil || got != "anything REDACTED" {
+		t.Errorf("Restore() = %q, %v", got, err)
+	}
+}
+
+func TestRedactionMap_CustomPattern(t *testing.T) {
+	m := NewRedactionMap("Call <<PII:7>> today.", regexp.MustCompile(`<<PII:\d+>>`))
+
+	restored, err := m.Restore(m.Protect("Call <<PII:7>> today."))
+	if err != nil || restored != "Call <<PII:7>> today." {
+		t.Fatalf("Restore() = %q, %v", restored, err)
+	}
+}
+
+func TestClient_RedactionPassThrough(t *testing.T) {
+	g, err := NewGlossary(map[string]string{"email": "mail address"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fake := &echoLLM{fn: func(string) string {
+		return "The owner RDCTD001 reported an email issue."
+	}}
+
+	c := &Client{
+		impl:      NewLLMSummarizer(fake),
+		sanitizer: bluemonday.StrictPolicy(),
+		glossary:  g,
+		redaction: true,
+	}
+
+	summary, err := c.Summarize(context.Background(), "The owner [REDACTED-EMAIL-1] reported an email issue with the portal.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if want := "The owner [REDACTED-EMAIL-1] reported an mail address issue."; summary != want {
+		t.Errorf("Summarize() = %q, want %q", summary, want)
+	}
+
+	prompt := fake.prompts[0]
+	if strings.Contains(prompt, "[REDACTED-EMAIL-1]") {
+		t.Errorf("prompt contains the raw placeholder: %q", prompt)
+	}
+
+	if !strings.Contains(prompt, "placeholders for redacted data: RDCTD001") {
+		t.Errorf("prompt lacks placeholder instructions: %q", prompt)
+	}
+}
+
+func TestClient_RedactionPassThroughAltered(t *testing.T) {
+	c := &Client{
+		impl:      NewLLMSummarizer(&echoLLM{fn: func(string) string { return "The owner [REDACTED-EMAIL-2] reported." }}),
+		sanitizer: bluemonday.StrictPolicy(),
+		redaction: true,
+	}
+
+	_, err := c.SummarizeDetailed(context.Background(), "The owner [REDACTED-EMAIL-1] reported an issue.")
+	if !errors.Is(err, ErrRedactionAltered) {
+		t.Fatalf("SummarizeDetailed() error = %v, want ErrRedactionAltered", err)
+	}
+}
+
+func TestClient_RedactionPassThroughLexRank(t *testing.T) {
+	impl, err := newLexRankSummarizer(2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c := &Client{impl: impl, sanitizer: bluemonday.StrictPolicy(), redaction: true}
+
+	text := "The auditor [REDACTED-NAME-1] reviewed the access controls. " +
+		"Findings were sent to [REDACTED-EMAIL-2] for remediation. " +
+		"The review covered all production systems. " +
+		"No critical issues were found in the logging setup."
+
+	summary, err := c.Summarize(context.Background(), text)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if protectedTokenPattern.MatchString(summary) {
+		t.Errorf("summary contains protected tokens: %q", summary)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/kopexa-grc/common/llm"
+)
+
+// PromptVersion identifies the prompt templates used by the LLM summarizer.
+// It must be bumped whenever a prompt template changes, so that recorded
+// results can be traced back to the exact instructions the model received.
+const PromptVersion = "2025-09-01"
+
+// promptTemplates lists every template the LLM summarizer builds prompts
+// from. Their digest is part of Result.ParamsHash, so that results produced
+// with different instructions never share a fingerprint.
+var promptTemplates = []string{
+	promptEN, promptDE,
+	promptIncrementalEN, promptIncrementalDE,
+	promptMapEN, promptReduceAggregateEN, promptReduceComparativeEN,
+	formatMarkdownEN, formatMarkdownDE,
+	formatPlainEN, formatPlainDE,
+	formatSectionsEN, formatSectionsDE,
+	glossaryInstructionsEN, glossaryInstructionsDE,
+	redactionInstructionsEN, redactionInstructionsDE,
+}
+
+// promptDigest returns the hex encoded SHA-256 digest of promptTemplates.
+func promptDigest() string {
+	h := sha256.New()
+
+	for _, t := range promptTemplates {
+		h.Write([]byte(t))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// BackendLLM is reported as Result.Backend for LLM summarizers whose
+// provider is unknown, e.g. those created with NewFromLLM.
+const BackendLLM = "llm"
+
+// Result records a summary together with how it was produced, so that it
+// can be explained and reproduced later.
+type Result struct {
+	// Summary is the summary after glossary enforcement.
+	Summary string
+	// Backend is the summarization backend: TypeLexrank, or the LLM provider
+	// such as "openai". BackendLLM if the provider is unknown.
+	Backend string
+	// Model is the LLM model name; empty for extractive summarizers.
+	Model string
+	// ParamsHash is a SHA-256 hash over all parameters that influence the
+	// summary, e.g. model, options and glossary. Credentials and endpoints
+	// are not included.
+	ParamsHash string
+	// PromptVersion is the version of the prompt templates; empty for
+	// extractive summarizers.
+	PromptVersion string
+	// Duration is the time spent summarizing.
+	Duration time.Duration
+	// TokenUsage reports the tokens consumed. It is zero for extractive
+	// summarizers and for LLM clients that do not report usage.
+	TokenUsage llm.TokenUsage
+	// Substitutions lists the replacements made to enforce the glossary.
+	Substitutions []Substitution
+	// Sections holds the parsed summary if the Client is configured with
+	// OutputFormatSections; Summary then holds its JSON encoding.
+	Sections *Sections
+	// Sensitivity is the label the Classifier assigned to the input; empty
+	// without a Classifier. Backend reports the local backend if the input
+	// was routed to it.
+	Sensitivity Sensitivity
+}
+
+// usageSummarizer is implemented by summarizers that report token usage.
+type usageSummarizer interface {
+	SummarizeWithUsage(ctx context.Context, text string) (string, llm.TokenUsage, error)
+}
+
+// SummarizeDetailed is like Summarize but returns a Result that records the
+// backend, model, parameters and prompt version used, along with the time
+// and tokens spent.
+func (s *Client) SummarizeDetailed(ctx context.Context, sentence string) (*Result, error) {
+	target, sensitivity, err := s.route(ctx, sentence)
+	if err != nil {
+		return nil, err
+	}
+
+	if target != s {
+		res, err := target.SummarizeDetailed(ctx, sentence)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Sensitivity = sensitivity
+
+		return res, nil
+	}
+
+	cleanInput := s.sanitizer.Sanitize(sentence)
+	if cleanInput == "" {
+		return nil, ErrSentenceEmpty
+	}
+
+	redactions := s.redactionMap(cleanInput)
+	cleanInput = redactions.Protect(cleanInput)
+
+	start := time.Now()
+
+	var (
+		summary string
+		usage   llm.TokenUsage
+	)
+
+	if u, ok := s.impl.(usageSummarizer); ok {
+		summary, usage, err = u.SummarizeWithUsage(ctx, cleanInput)
+	} else {
+		summary, err = s.impl.Summarize(ctx, cleanInput)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Since(start)
+
+	summary, sections, subs, err := s.finishSummary(summary, redactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Summary:       summary,
+		Backend:       s.fingerprint.backend,
+		Model:         s.fingerprint.model,
+		ParamsHash:    s.fingerprint.paramsHash,
+		PromptVersion: s.fingerprint.promptVersion,
+		Duration:      duration,
+		TokenUsage:    usage,
+		Substitutions: subs,
+		Sections:      sections,
+		Sensitivity:   sensitivity,
+	}, nil
+}
+
+// fingerprint describes the configuration a Client summarizes with.
+type fingerprint struct {
+	backend       string
+	model         string
+	paramsHash    string
+	promptVersion string
+}
+
+// fingerprintParams holds the parameters hashed into Result.ParamsHash.
+// Maps are marshaled with sorted keys, so the hash is deterministic.
+type fingerprintParams struct {
+	Type         Type              `json:"type"`
+	Provider     LLMProvider       `json:"provider,omitempty"`
+	Model        string            `json:"model,omitempty"`
+	MaxTokens    int               `json:"maxTokens,omitempty"`
+	Options      map[string]any    `json:"options,omitempty"`
+	MaxSentences int               `json:"maxSentences,omitempty"`
+	Glossary     map[string]string `json:"glossary,omitempty"`
+	Redaction    string            `json:"redaction,omitempty"`
+	OutputFormat OutputFormat      `json:"outputFormat,omitempty"`
+	Prompts      string            `json:"prompts,omitempty"`
+}
+
+// newFingerprint derives the fingerprint of a Client created from cfg.
+func newFingerprint(cfg *Config) fingerprint {
+	params := fingerprintParams{Type: cfg.Type, Glossary: cfg.Glossary, OutputFormat: cfg.OutputFormat}
+
+	if cfg.RedactionPassThrough {
+		params.Redaction = DefaultRedactionPattern.String()
+		if cfg.RedactionPattern != nil {
+			params.Redaction = cfg.RedactionPattern.String()
+		}
+	}
+	f := fingerprint{backend: string(cfg.Type)}
+
+	switch cfg.Type {
+	case TypeLexrank:
+		params.MaxSentences = DefaultLexRankSentences
+	case TypeLlm:
+		f.backend = BackendLLM
+		f.promptVersion = PromptVersion
+		params.Prompts = promptDigest()
+
+		if cfg.LLM != nil {
+			params.Provider = cfg.LLM.Provider
+			params.Model = cfg.LLM.Model
+			params.MaxTokens = cfg.LLM.MaxTokens
+			params.Options = cfg.LLM.Options
+
+			f.backend = string(cfg.LLM.Provider)
+			f.model = cfg.LLM.Model
+		}
+	}
+
+	// Marshaling only fails for unsupported option values; such options
+	// are left out of the hash rather than failing summarization.
+	b, err := json.Marshal(params)
+	if err != nil {
+		params.Options = nil
+		b, _ = json.Marshal(params)
+	}
+
+	sum := sha256.Sum256(b)
+	f.paramsHash = hex.EncodeToString(sum[:])
+
+	return f
+}
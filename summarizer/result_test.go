@@ -0,0 +1,139 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kopexa-grc/common/llm"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// usageLLM is a fake LLMClient that also reports token usage.
+type usageLLM struct {
+	fixedLLM
+	usage llm.TokenUsage
+}
+
+func (u *usageLLM) GenerateWithUsage(ctx context.Context, prompt string, _ ...llms.CallOption) (string, llm.TokenUsage, error) {
+	answer, err := u.Generate(ctx, prompt)
+	return answer, u.usage, err
+}
+
+func TestClient_SummarizeDetailed(t *testing.T) {
+	cfg := NewConfig(WithType(TypeLlm), WithOpenAI("gpt-4", "sk-secret", WithMaxTokens(500)))
+
+	fake := &usageLLM{
+		fixedLLM: fixedLLM{answer: "A short summary."},
+		usage:    llm.TokenUsage{PromptTokens: 120, CompletionTokens: 8, TotalTokens: 128},
+	}
+
+	client := &Client{
+		impl:        NewLLMSummarizer(fake),
+		sanitizer:   bluemonday.StrictPolicy(),
+		fingerprint: newFingerprint(cfg),
+	}
+
+	res, err := client.SummarizeDetailed(context.Background(), "A long text that needs to be summarized.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if res.Summary != "A short summary." {
+		t.Errorf("Unexpected summary: %q", res.Summary)
+	}
+
+	if res.Backend != "openai" || res.Model != "gpt-4" {
+		t.Errorf("Unexpected backend/model: %q/%q", res.Backend, res.Model)
+	}
+
+	if res.PromptVersion != PromptVersion {
+		t.Errorf("Expected prompt version %q, got %q", PromptVersion, res.PromptVersion)
+	}
+
+	if res.TokenUsage != fake.usage {
+		t.Errorf("Unexpected token usage: %+v", res.TokenUsage)
+	}
+
+	if res.ParamsHash == "" {
+		t.Error("Expected a params hash")
+	}
+
+	// summarize via the string API keeps working
+	summary, err := client.Summarize(context.Background(), "A long text that needs to be summarized.")
+	if err != nil || summary != res.Summary {
+		t.Errorf("Unexpected Summarize result: %q, %v", summary, err)
+	}
+}
+
+func TestClient_SummarizeDetailed_LexRank(t *testing.T) {
+	client, err := New(NewConfig())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	res, err := client.SummarizeDetailed(context.Background(), "Security controls must be reviewed yearly. Access reviews happen quarterly. Backups are tested monthly.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if res.Backend != string(TypeLexrank) || res.Model != "" || res.PromptVersion != "" {
+		t.Errorf("Unexpected fingerprint: %+v", res)
+	}
+
+	if res.TokenUsage != (llm.TokenUsage{}) {
+		t.Errorf("Expected no token usage, got %+v", res.TokenUsage)
+	}
+}
+
+func TestNewFingerprint_ParamsHash(t *testing.T) {
+	base := newFingerprint(NewConfig(WithType(TypeLlm), WithOpenAI("gpt-4", "sk-one")))
+
+	// credentials do not influence the hash
+	if got := newFingerprint(NewConfig(WithType(TypeLlm), WithOpenAI("gpt-4", "sk-two"))); got.paramsHash != base.paramsHash {
+		t.Error("Expected equal hashes for different API keys")
+	}
+
+	changed := []*Config{
+		NewConfig(WithType(TypeLlm), WithOpenAI("gpt-4o", "sk-one")),
+		NewConfig(WithType(TypeLlm), WithOpenAI("gpt-4", "sk-one", WithMaxTokens(10))),
+		NewConfig(WithType(TypeLlm), WithOpenAI("gpt-4", "sk-one", WithOption("temperature", 0.2))),
+		NewConfig(WithType(TypeLlm), WithOpenAI("gpt-4", "sk-one"), WithGlossary(map[string]string{"vendor": "supplier"})),
+	}
+
+	for i, cfg := range changed {
+		if newFingerprint(cfg).paramsHash == base.paramsHash {
+			t.Errorf("Expected config %d to change the hash", i)
+		}
+	}
+}
+
+// TestPromptVersion guards against changing the prompt templates without
+// bumping PromptVersion. Update both the version and the digest below.
+func TestPromptVersion(t *testing.T) {
+	const (
+		wantVersion = "2025-09-01"
+		wantDigest  = "4bf03e39ceaee4aee8b017a667040afe56aae0c619e6bd8defbf989de15eb7bf"
+	)
+
+	if got := promptDigest(); got != wantDigest || PromptVersion != wantVersion {
+		t.Errorf("Prompt templates changed (digest %s); bump PromptVersion and update this test", got)
+	}
+}
+
+func TestFingerprint_PromptTemplates(t *testing.T) {
+	cfg := NewConfig(WithType(TypeLlm), WithOpenAI("gpt-4", "sk-one"))
+	base := newFingerprint(cfg)
+
+	templates := promptTemplates
+	t.Cleanup(func() { promptTemplates = templates })
+
+	promptTemplates = append([]string{"Summarize the following text."}, templates[1:]...)
+
+	if newFingerprint(cfg).paramsHash == base.paramsHash {
+		t.Error("Expected a changed prompt template to change the hash")
+	}
+}
@@ -5,6 +5,8 @@ package summarizer
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 
 	"github.com/kopexa-grc/common/llm"
 	"github.com/microcosm-cc/bluemonday"
@@ -25,14 +27,25 @@ type summarizer interface {
 type Client struct {
 	impl      summarizer
 	sanitizer *bluemonday.Policy
+	glossary  *Glossary
+	// redaction enables placeholder pass-through; see WithRedactionPassThrough.
+	redaction        bool
+	redactionPattern *regexp.Regexp
+	// fingerprint describes how summaries are produced; see Result.
+	fingerprint fingerprint
+	// format is the shape of the summaries; see WithOutputFormat.
+	format OutputFormat
+	// policy routes sensitive inputs to a local backend; see WithClassifier.
+	policy *contentPolicy
 }
 
 func NewFromLLM(llm *llm.Client) (*Client, error) {
 	summarizer := NewLLMSummarizer(llm)
 
 	return &Client{
-		impl:      summarizer,
-		sanitizer: bluemonday.StrictPolicy(),
+		impl:        summarizer,
+		sanitizer:   bluemonday.StrictPolicy(),
+		fingerprint: newFingerprint(&Config{Type: TypeLlm}),
 	}, nil
 }
 
@@ -44,10 +57,23 @@ func New(cfg *Config) (*Client, error) {
 		return nil, ErrConfigRequired
 	}
 
+	if !validOutputFormat(cfg.OutputFormat) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedOutputFormat, cfg.OutputFormat)
+	}
+
 	var impl summarizer
 
 	var err error
 
+	var glossary *Glossary
+
+	if len(cfg.Glossary) > 0 {
+		glossary, err = NewGlossary(cfg.Glossary)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	switch cfg.Type {
 	case TypeLexrank:
 		// Default: 3 Sätze, kann später erweitert werden
@@ -64,23 +90,35 @@ func New(cfg *Config) (*Client, error) {
 		return nil, ErrUnsupportedType
 	}
 
+	var policy *contentPolicy
+
+	if cfg.Classifier != nil {
+		policy, err = newContentPolicy(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Client{
-		impl:      impl,
-		sanitizer: sanitizer,
+		impl:        impl,
+		sanitizer:   sanitizer,
+		policy:      policy,
+		glossary:    glossary,
+		fingerprint: newFingerprint(cfg),
+		format:      cfg.OutputFormat,
+
+		redaction:        cfg.RedactionPassThrough,
+		redactionPattern: cfg.RedactionPattern,
 	}, nil
 }
 
-// Summarize cleans the input, runs the summarizer, and sanitizes the output
+// Summarize cleans the input, runs the summarizer, and enforces the glossary
+// on the output
 func (s *Client) Summarize(ctx context.Context, sentence string) (string, error) {
-	cleanInput := s.sanitizer.Sanitize(sentence)
-	if cleanInput == "" {
-		return "", ErrSentenceEmpty
-	}
-
-	summary, err := s.impl.Summarize(ctx, cleanInput)
+	report, err := s.SummarizeWithReport(ctx, sentence)
 	if err != nil {
 		return "", err
 	}
 
-	return summary, nil
+	return report.Summary, nil
 }
@@ -0,0 +1,311 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Defaults for the per-tenant client cache of TenantSummarizer.
+const (
+	// DefaultTenantCacheSize is the number of tenant clients kept in memory.
+	DefaultTenantCacheSize = 256
+	// DefaultTenantCacheTTL is how long a tenant client is reused before its
+	// configuration is resolved again.
+	DefaultTenantCacheTTL = 10 * time.Minute
+)
+
+// ConfigResolver returns the summarizer configuration of a tenant.
+type ConfigResolver interface {
+	// ConfigForTenant returns the configuration for tenantID, or nil if the
+	// tenant has no override and the default configuration applies.
+	ConfigForTenant(ctx context.Context, tenantID string) (*Config, error)
+}
+
+// ConfigResolverFunc adapts a function to a ConfigResolver.
+type ConfigResolverFunc func(ctx context.Context, tenantID string) (*Config, error)
+
+// ConfigForTenant calls f.
+func (f ConfigResolverFunc) ConfigForTenant(ctx context.Context, tenantID string) (*Config, error) {
+	return f(ctx, tenantID)
+}
+
+// StaticConfigResolver resolves tenant configurations from a fixed map.
+// Tenants that are not in the map use the default configuration.
+type StaticConfigResolver map[string]*Config
+
+// ConfigForTenant returns the configuration of tenantID or nil.
+func (r StaticConfigResolver) ConfigForTenant(_ context.Context, tenantID string) (*Config, error) {
+	return r[tenantID], nil
+}
+
+// TenantOption configures a TenantSummarizer.
+type TenantOption func(*TenantSummarizer)
+
+// WithDefaultConfig sets the configuration for tenants without an override.
+// Without a default configuration, such tenants fail with
+// ErrTenantConfigNotFound.
+func WithDefaultConfig(cfg *Config) TenantOption {
+	return func(t *TenantSummarizer) {
+		t.fallback = cfg
+	}
+}
+
+// WithTenantCacheSize limits the number of cached tenant clients. The least
+// recently used client is evicted when the limit is reached.
+func WithTenantCacheSize(n int) TenantOption {
+	return func(t *TenantSummarizer) {
+		if n > 0 {
+			t.size = n
+		}
+	}
+}
+
+// WithTenantCacheTTL sets how long a tenant client is reused. After the TTL
+// the tenant's configuration is resolved again, so configuration changes
+// take effect without a restart.
+func WithTenantCacheTTL(ttl time.Duration) TenantOption {
+	return func(t *TenantSummarizer) {
+		if ttl > 0 {
+			t.ttl = ttl
+		}
+	}
+}
+
+// WithClientFactory replaces New as the constructor for tenant clients, for
+// example to add options or to inject fakes in tests.
+func WithClientFactory(factory func(*Config) (*Client, error)) TenantOption {
+	return func(t *TenantSummarizer) {
+		if factory != nil {
+			t.factory = factory
+		}
+	}
+}
+
+// TenantSummarizer summarizes with the configuration of the requesting
+// tenant. Clients are constructed on first use and cached per tenant with
+// LRU eviction and a TTL. It is safe for concurrent use.
+type TenantSummarizer struct {
+	resolver ConfigResolver
+	fallback *Config
+	factory  func(*Config) (*Client, error)
+	size     int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front is most recently used
+	group   singleflight.Group
+}
+
+// tenantEntry is a cached tenant client.
+type tenantEntry struct {
+	tenantID  string
+	client    *Client
+	expiresAt time.Time
+}
+
+// NewTenantSummarizer creates a TenantSummarizer that resolves tenant
+// configurations with resolver.
+//
+// Example:
+//
+//	ts, err := summarizer.NewTenantSummarizer(resolver,
+//		summarizer.WithDefaultConfig(summarizer.NewConfig()),
+//	)
+//	summary, err := ts.Summarize(ctx, tenantID, text)
+func NewTenantSummarizer(resolver ConfigResolver, opts ...TenantOption) (*TenantSummarizer, error) {
+	if resolver == nil {
+		return nil, ErrResolverRequired
+	}
+
+	t := &TenantSummarizer{
+		resolver: resolver,
+		factory:  New,
+		size:     DefaultTenantCacheSize,
+		ttl:      DefaultTenantCacheTTL,
+		now:      time.Now,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t, nil
+}
+
+// Client returns the summarizer client of tenantID, constructing it if it
+// is not cached. Concurrent calls for the same tenant share one
+// construction.
+func (t *TenantSummarizer) Client(ctx context.Context, tenantID string) (*Client, error) {
+	if tenantID == "" {
+		return nil, ErrTenantRequired
+	}
+
+	if c, ok := t.cached(tenantID); ok {
+		return c, nil
+	}
+
+	v, err, _ := t.group.Do(tenantID, func() (any, error) {
+		if c, ok := t.cached(tenantID); ok {
+			return c, nil
+		}
+
+		c, err := t.build(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		t.store(tenantID, c)
+
+		return c, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*Client), nil //nolint:forcetypeassert // only *Client is stored
+}
+
+// Summarize summarizes text with the client of tenantID.
+func (t *TenantSummarizer) Summarize(ctx context.Context, tenantID, text string) (string, error) {
+	c, err := t.Client(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Summarize(ctx, text)
+}
+
+// SummarizeDetailed summarizes text with the client of tenantID and returns
+// the result with its provenance.
+func (t *TenantSummarizer) SummarizeDetailed(ctx context.Context, tenantID, text string) (*Result, error) {
+	c, err := t.Client(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SummarizeDetailed(ctx, text)
+}
+
+// SummarizeMulti summarizes several documents with the client of tenantID.
+func (t *TenantSummarizer) SummarizeMulti(ctx context.Context, tenantID string, docs []Document, mode MultiMode) (string, error) {
+	c, err := t.Client(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+
+	return c.SummarizeMulti(ctx, docs, mode)
+}
+
+// SummarizeIncremental folds newText into the previous summary with the
+// client of tenantID, see Client.SummarizeIncremental.
+func (t *TenantSummarizer) SummarizeIncremental(ctx context.Context, tenantID string, previous IncrementalSummary, newText string, opts ...IncrementalOption) (IncrementalSummary, error) {
+	c, err := t.Client(ctx, tenantID)
+	if err != nil {
+		return previous, err
+	}
+
+	return c.SummarizeIncremental(ctx, previous, newText, opts...)
+}
+
+// Evict removes the cached client of tenantID, for example after its
+// configuration changed. The next request resolves the configuration again.
+func (t *TenantSummarizer) Evict(tenantID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if el, ok := t.entries[tenantID]; ok {
+		t.remove(el)
+	}
+}
+
+// Len returns the number of cached tenant clients.
+func (t *TenantSummarizer) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.lru.Len()
+}
+
+// build resolves the configuration of tenantID and constructs its client.
+func (t *TenantSummarizer) build(ctx context.Context, tenantID string) (*Client, error) {
+	cfg, err := t.resolver.ConfigForTenant(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve summarizer config for tenant %q: %w", tenantID, err)
+	}
+
+	if cfg == nil {
+		cfg = t.fallback
+	}
+
+	if cfg == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTenantConfigNotFound, tenantID)
+	}
+
+	c, err := t.factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create summarizer for tenant %q: %w", tenantID, err)
+	}
+
+	return c, nil
+}
+
+// cached returns the unexpired client of tenantID and marks it as recently
+// used.
+func (t *TenantSummarizer) cached(tenantID string) (*Client, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	el, ok := t.entries[tenantID]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*tenantEntry) //nolint:forcetypeassert // only *tenantEntry is stored
+	if !t.now().Before(entry.expiresAt) {
+		t.remove(el)
+		return nil, false
+	}
+
+	t.lru.MoveToFront(el)
+
+	return entry.client, true
+}
+
+// store caches the client of tenantID and evicts the least recently used
+// clients beyond the cache size.
+func (t *TenantSummarizer) store(tenantID string, c *Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if el, ok := t.entries[tenantID]; ok {
+		t.remove(el)
+	}
+
+	t.entries[tenantID] = t.lru.PushFront(&tenantEntry{
+		tenantID:  tenantID,
+		client:    c,
+		expiresAt: t.now().Add(t.ttl),
+	})
+
+	for t.lru.Len() > t.size {
+		t.remove(t.lru.Back())
+	}
+}
+
+// remove drops el from the cache. The caller must hold t.mu.
+func (t *TenantSummarizer) remove(el *list.Element) {
+	t.lru.Remove(el)
+	delete(t.entries, el.Value.(*tenantEntry).tenantID) //nolint:forcetypeassert // only *tenantEntry is stored
+}
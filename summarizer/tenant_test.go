@@ -0,0 +1,229 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingFactory wraps New and counts constructed clients per config.
+type countingFactory struct {
+	mu     sync.Mutex
+	builds map[*Config]int
+}
+
+func (f *countingFactory) New(cfg *Config) (*Client, error) {
+	f.mu.Lock()
+	f.builds[cfg]++
+	f.mu.Unlock()
+
+	return New(cfg)
+}
+
+func (f *countingFactory) count(cfg *Config) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.builds[cfg]
+}
+
+func TestTenantSummarizer_ResolvesPerTenant(t *testing.T) {
+	fallback := NewConfig()
+	override := NewConfig(WithGlossary(map[string]string{"vendor": "supplier"}))
+	factory := &countingFactory{builds: map[*Config]int{}}
+
+	ts, err := NewTenantSummarizer(StaticConfigResolver{"acme": override},
+		WithDefaultConfig(fallback),
+		WithClientFactory(factory.New),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	acme, err := ts.Client(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if acme.glossary == nil {
+		t.Error("Expected the tenant override to be used")
+	}
+
+	other, err := ts.Client(ctx, "other")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if other.glossary != nil {
+		t.Error("Expected the default config to be used")
+	}
+
+	again, err := ts.Client(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if again != acme {
+		t.Error("Expected the cached client to be reused")
+	}
+
+	if factory.count(override) != 1 || factory.count(fallback) != 1 {
+		t.Errorf("Expected one client per tenant, got %v", factory.builds)
+	}
+
+	summary, err := ts.Summarize(ctx, "acme", "The vendor stores data in the EU. The vendor encrypts data.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if summary == "" {
+		t.Error("Expected a summary")
+	}
+}
+
+func TestTenantSummarizer_Eviction(t *testing.T) {
+	cfg := NewConfig()
+	factory := &countingFactory{builds: map[*Config]int{}}
+
+	ts, err := NewTenantSummarizer(StaticConfigResolver{},
+		WithDefaultConfig(cfg),
+		WithClientFactory(factory.New),
+		WithTenantCacheSize(2),
+		WithTenantCacheTTL(time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	ts.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for _, tenant := range []string{"a", "b", "a", "c"} {
+		if _, err := ts.Client(ctx, tenant); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// "b" was least recently used when "c" was added.
+	if ts.Len() != 2 {
+		t.Fatalf("Expected 2 cached clients, got %d", ts.Len())
+	}
+
+	if _, ok := ts.cached("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+
+	if factory.count(cfg) != 3 {
+		t.Errorf("Expected 3 builds, got %d", factory.count(cfg))
+	}
+
+	ts.Evict("a")
+
+	if _, ok := ts.cached("a"); ok {
+		t.Error("Expected a to be evicted")
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, ok := ts.cached("c"); ok {
+		t.Error("Expected c to expire after the TTL")
+	}
+
+	if ts.Len() != 0 {
+		t.Errorf("Expected an empty cache, got %d", ts.Len())
+	}
+}
+
+func TestTenantSummarizer_ConcurrentConstruction(t *testing.T) {
+	var resolved atomic.Int32
+
+	release := make(chan struct{})
+	resolver := ConfigResolverFunc(func(context.Context, string) (*Config, error) {
+		resolved.Add(1)
+		<-release
+
+		return NewConfig(), nil
+	})
+
+	ts, err := NewTenantSummarizer(resolver)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	clients := make([]*Client, 8)
+	for i := range clients {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			clients[i], _ = ts.Client(context.Background(), "acme")
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if resolved.Load() != 1 {
+		t.Errorf("Expected a single resolution, got %d", resolved.Load())
+	}
+
+	for _, c := range clients {
+		if c == nil || c != clients[0] {
+			t.Fatal("Expected all callers to share one client")
+		}
+	}
+}
+
+func TestTenantSummarizer_Errors(t *testing.T) {
+	if _, err := NewTenantSummarizer(nil); !errors.Is(err, ErrResolverRequired) {
+		t.Errorf("Expected ErrResolverRequired, got %v", err)
+	}
+
+	errLookup := errors.New("lookup failed")
+	ts, err := NewTenantSummarizer(ConfigResolverFunc(func(_ context.Context, tenantID string) (*Config, error) {
+		switch tenantID {
+		case "broken":
+			return nil, errLookup
+		case "invalid":
+			return &Config{Type: "unknown"}, nil
+		default:
+			return nil, nil
+		}
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		tenant string
+		want   error
+	}{
+		{tenant: "", want: ErrTenantRequired},
+		{tenant: "broken", want: errLookup},
+		{tenant: "invalid", want: ErrUnsupportedType},
+		{tenant: "unknown", want: ErrTenantConfigNotFound},
+	}
+
+	for _, tt := range tests {
+		if _, err := ts.Summarize(context.Background(), tt.tenant, "text"); !errors.Is(err, tt.want) {
+			t.Errorf("Tenant %q: expected %v, got %v", tt.tenant, tt.want, err)
+		}
+	}
+
+	if ts.Len() != 0 {
+		t.Errorf("Expected failed constructions not to be cached, got %d", ts.Len())
+	}
+}
@@ -9,8 +9,6 @@ import (
 	"errors"
 	"io"
 	"strings"
-
-	"github.com/rs/zerolog/log"
 )
 
 const (
@@ -119,9 +117,7 @@ func (a Address) String() string {
 // Parameters:
 //   - w: The writer to write the Address to
 func (a Address) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, a); err != nil {
-		log.Error().Err(err).Msg("failed to marshal address to GraphQL")
-	}
+	MarshalGQL(w, a)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface for Address.
@@ -133,7 +129,7 @@ func (a Address) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (a *Address) UnmarshalGQL(v interface{}) error {
-	return unmarshalGQLJSON(v, a)
+	return UnmarshalGQL(v, a)
 }
 
 // MarshalCSV implements the csvutil.Marshaler interface for Address.
@@ -0,0 +1,236 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kopexa-grc/common/krn"
+)
+
+// ErrInvalidAuditEvent is returned when an audit event is invalid
+var ErrInvalidAuditEvent = errors.New("invalid audit event")
+
+// RedactedValue replaces the values of sensitive keys in redacted audit events.
+const RedactedValue = "[REDACTED]"
+
+// AuditActorType is the kind of principal that performed an audited action.
+type AuditActorType string
+
+const (
+	// AuditActorUser is a human user.
+	AuditActorUser AuditActorType = "user"
+	// AuditActorService is a service account or API token.
+	AuditActorService AuditActorType = "service"
+	// AuditActorSystem is the platform itself, e.g. a scheduled job.
+	AuditActorSystem AuditActorType = "system"
+)
+
+// DefaultSensitiveAuditKeys are the metadata keys whose values are always
+// redacted by AuditEvent.Redact. Keys are matched case-insensitively.
+var DefaultSensitiveAuditKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"apiKey",
+	"accessToken",
+	"refreshToken",
+	"privateKey",
+}
+
+// reAuditAction matches action verbs such as "create" or "control.update".
+var reAuditAction = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// AuditActor is the principal that performed an audited action.
+type AuditActor struct {
+	// Type is the kind of principal
+	Type AuditActorType `json:"type" yaml:"type"`
+	// ID is the unique identifier of the principal
+	ID string `json:"id" yaml:"id"`
+	// Name is the optional display name of the principal
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// String returns the actor in the format "type:id".
+func (a AuditActor) String() string {
+	return fmt.Sprintf("%s:%s", a.Type, a.ID)
+}
+
+// AuditChange is a single changed key between the before and after state of
+// an audit event.
+type AuditChange struct {
+	// Key is the changed metadata key
+	Key string `json:"key"`
+	// Before is the previous value; empty if the key was added
+	Before string `json:"before,omitempty"`
+	// After is the new value; empty if the key was removed
+	After string `json:"after,omitempty"`
+}
+
+// AuditEvent is a single entry of the audit trail. It records who (Actor)
+// did what (Action) to which resource (Target) and when, together with the
+// state of the resource before and after the action.
+type AuditEvent struct {
+	// Actor is the principal that performed the action
+	Actor AuditActor `json:"actor" yaml:"actor"`
+	// Action is the verb describing the action, e.g. "create" or "control.update"
+	Action string `json:"action" yaml:"action"`
+	// Target is the KRN of the affected resource
+	Target krn.KRN `json:"target" yaml:"target"`
+	// Timestamp is the time the action was performed
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	// Before is the state of the resource before the action
+	Before Metadata `json:"before,omitempty" yaml:"before,omitempty"`
+	// After is the state of the resource after the action
+	After Metadata `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+// NewAuditEvent creates an audit event with the current UTC time as timestamp.
+//
+// Parameters:
+//   - actor: The principal that performed the action
+//   - action: The action verb
+//   - target: The KRN of the affected resource
+//
+// Returns:
+//   - AuditEvent: The new audit event without before and after state
+func NewAuditEvent(actor AuditActor, action string, target krn.KRN) AuditEvent {
+	return AuditEvent{
+		Actor:     actor,
+		Action:    action,
+		Target:    target,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// Validate checks if the audit event is valid.
+// An audit event is valid if it has an actor with type and ID, a lowercase
+// action verb, a target and a timestamp.
+//
+// Returns:
+//   - error: ErrInvalidAuditEvent if the audit event is invalid
+func (e AuditEvent) Validate() error {
+	switch e.Actor.Type {
+	case AuditActorUser, AuditActorService, AuditActorSystem:
+	case "":
+		return fmt.Errorf("%w: actor type is required", ErrInvalidAuditEvent)
+	default:
+		return fmt.Errorf("%w: unknown actor type %q", ErrInvalidAuditEvent, e.Actor.Type)
+	}
+
+	if e.Actor.ID == "" {
+		return fmt.Errorf("%w: actor id is required", ErrInvalidAuditEvent)
+	}
+
+	if !reAuditAction.MatchString(e.Action) {
+		return fmt.Errorf("%w: invalid action %q", ErrInvalidAuditEvent, e.Action)
+	}
+
+	if e.Target.IsZero() {
+		return fmt.Errorf("%w: target is required", ErrInvalidAuditEvent)
+	}
+
+	if e.Timestamp.IsZero() {
+		return fmt.Errorf("%w: timestamp is required", ErrInvalidAuditEvent)
+	}
+
+	return nil
+}
+
+// Changes returns the keys that differ between Before and After, sorted by key.
+func (e AuditEvent) Changes() []AuditChange {
+	var changes []AuditChange
+
+	for key, before := range e.Before {
+		if after, ok := e.After[key]; !ok || after != before {
+			changes = append(changes, AuditChange{Key: key, Before: before, After: after})
+		}
+	}
+
+	for key, after := range e.After {
+		if _, ok := e.Before[key]; !ok {
+			changes = append(changes, AuditChange{Key: key, After: after})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// Redact returns a copy of the audit event in which the values of sensitive
+// keys in Before and After are replaced with RedactedValue. The keys in
+// DefaultSensitiveAuditKeys are always redacted in addition to the given keys.
+// Keys are matched case-insensitively.
+//
+// Parameters:
+//   - keys: Additional sensitive keys
+//
+// Returns:
+//   - AuditEvent: The redacted copy
+func (e AuditEvent) Redact(keys ...string) AuditEvent {
+	sensitive := make([]string, 0, len(DefaultSensitiveAuditKeys)+len(keys))
+	for _, k := range slices.Concat(DefaultSensitiveAuditKeys, keys) {
+		sensitive = append(sensitive, strings.ToLower(k))
+	}
+
+	e.Before = redactMetadata(e.Before, sensitive)
+	e.After = redactMetadata(e.After, sensitive)
+
+	return e
+}
+
+// redactMetadata returns a copy of m with the values of sensitive keys redacted.
+func redactMetadata(m Metadata, sensitive []string) Metadata {
+	if m == nil {
+		return nil
+	}
+
+	out := make(Metadata, len(m))
+
+	for k, v := range m {
+		if slices.Contains(sensitive, strings.ToLower(k)) {
+			v = RedactedValue
+		}
+
+		out[k] = v
+	}
+
+	return out
+}
+
+// String returns a string representation of the audit event.
+func (e AuditEvent) String() string {
+	return fmt.Sprintf("%s %s %s at %s", e.Actor, e.Action, e.Target.String(), e.Timestamp.Format(time.RFC3339))
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for AuditEvent.
+// It allows AuditEvent to be used as a GraphQL scalar type.
+//
+// Parameters:
+//   - w: The writer to write the AuditEvent to
+func (e AuditEvent) MarshalGQL(w io.Writer) {
+	MarshalGQL(w, e)
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for AuditEvent.
+// It allows AuditEvent to be used as a GraphQL scalar type.
+//
+// Parameters:
+//   - v: The value to unmarshal
+//
+// Returns:
+//   - error: If unmarshaling fails
+func (e *AuditEvent) UnmarshalGQL(v interface{}) error {
+	return UnmarshalGQL(v, e)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/krn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAuditEvent() AuditEvent {
+	e := NewAuditEvent(
+		AuditActor{Type: AuditActorUser, ID: "user-1", Name: "John Doe"},
+		"control.update",
+		krn.MustParse("//kopexa.com/controls/ctrl-1234"),
+	)
+	e.Before = Metadata{"status": "draft", "owner": "alice", "apiKey": "abc"}
+	e.After = Metadata{"status": "approved", "reviewer": "bob", "apiKey": "def"}
+
+	return e
+}
+
+func TestAuditEvent_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(e *AuditEvent)
+		wantErr bool
+	}{
+		{name: "valid event", modify: func(*AuditEvent) {}},
+		{name: "missing actor type", modify: func(e *AuditEvent) { e.Actor.Type = "" }, wantErr: true},
+		{name: "unknown actor type", modify: func(e *AuditEvent) { e.Actor.Type = "robot" }, wantErr: true},
+		{name: "missing actor id", modify: func(e *AuditEvent) { e.Actor.ID = "" }, wantErr: true},
+		{name: "invalid action", modify: func(e *AuditEvent) { e.Action = "Update Control" }, wantErr: true},
+		{name: "missing target", modify: func(e *AuditEvent) { e.Target = krn.KRN{} }, wantErr: true},
+		{name: "missing timestamp", modify: func(e *AuditEvent) { e.Timestamp = time.Time{} }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := testAuditEvent()
+			tt.modify(&e)
+
+			err := e.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAuditEvent)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuditEvent_Changes(t *testing.T) {
+	e := testAuditEvent()
+
+	assert.Equal(t, []AuditChange{
+		{Key: "apiKey", Before: "abc", After: "def"},
+		{Key: "owner", Before: "alice"},
+		{Key: "reviewer", After: "bob"},
+		{Key: "status", Before: "draft", After: "approved"},
+	}, e.Changes())
+
+	assert.Empty(t, AuditEvent{}.Changes())
+}
+
+func TestAuditEvent_Redact(t *testing.T) {
+	e := testAuditEvent()
+
+	redacted := e.Redact("OWNER")
+
+	assert.Equal(t, RedactedValue, redacted.Before["apiKey"])
+	assert.Equal(t, RedactedValue, redacted.After["apiKey"])
+	assert.Equal(t, RedactedValue, redacted.Before["owner"])
+	assert.Equal(t, "approved", redacted.After["status"])
+
+	// the original event is not modified
+	assert.Equal(t, "abc", e.Before["apiKey"])
+	assert.Equal(t, "alice", e.Before["owner"])
+}
+
+func TestAuditEvent_JSON(t *testing.T) {
+	e := testAuditEvent()
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"target":"//kopexa.com/controls/ctrl-1234"`)
+
+	var decoded AuditEvent
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, e.Target, decoded.Target)
+	assert.Equal(t, e.Actor, decoded.Actor)
+	assert.True(t, e.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, e.After, decoded.After)
+}
+
+func TestAuditEvent_GQL(t *testing.T) {
+	e := testAuditEvent()
+
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+
+	var input map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &input))
+
+	var decoded AuditEvent
+	require.NoError(t, decoded.UnmarshalGQL(input))
+	assert.Equal(t, e.Action, decoded.Action)
+	assert.Equal(t, e.Target, decoded.Target)
+	assert.NoError(t, decoded.Validate())
+
+	assert.Error(t, decoded.UnmarshalGQL(nil))
+}
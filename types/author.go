@@ -8,8 +8,6 @@ import (
 	"fmt"
 	"io"
 	"strings"
-
-	"github.com/rs/zerolog/log"
 )
 
 // ErrInvalidAuthor is returned when an author is invalid
@@ -64,9 +62,7 @@ func (a Author) String() string {
 // Parameters:
 //   - w: The writer to write the Author to
 func (a Author) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, a); err != nil {
-		log.Error().Err(err).Msg("failed to marshal author to GraphQL")
-	}
+	MarshalGQL(w, a)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface for Author.
@@ -78,9 +74,5 @@ func (a Author) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (a *Author) UnmarshalGQL(v interface{}) error {
-	if err := unmarshalGQLJSON(v, a); err != nil {
-		return fmt.Errorf("failed to unmarshal author: %w", err)
-	}
-
-	return nil
+	return UnmarshalGQL(v, a)
 }
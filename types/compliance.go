@@ -3,11 +3,7 @@
 
 package types
 
-import (
-	"io"
-
-	"github.com/rs/zerolog/log"
-)
+import "io"
 
 // ExampleEvidence represents evidence for compliance documentation.
 // It is used to store information about documentation examples and their descriptions.
@@ -58,9 +54,7 @@ type ImplementationGuidance struct {
 // Parameters:
 //   - w: The writer to write the marshaled data to
 func (e ExampleEvidence) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, e); err != nil {
-		log.Error().Err(err).Msg("failed to marshal ExampleEvidence to GraphQL")
-	}
+	MarshalGQL(w, e)
 }
 
 // UnmarshalGQL implements the Unmarshaler interface for gqlgen.
@@ -72,7 +66,7 @@ func (e ExampleEvidence) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (e *ExampleEvidence) UnmarshalGQL(v any) error {
-	return unmarshalGQLJSON(v, e)
+	return UnmarshalGQL(v, e)
 }
 
 // MarshalGQL implements the Marshaler interface for gqlgen.
@@ -81,9 +75,7 @@ func (e *ExampleEvidence) UnmarshalGQL(v any) error {
 // Parameters:
 //   - w: The writer to write the marshaled data to
 func (i ImplementationGuidance) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, i); err != nil {
-		log.Error().Err(err).Msg("failed to marshal ImplementationGuidance to GraphQL")
-	}
+	MarshalGQL(w, i)
 }
 
 // UnmarshalGQL implements the Unmarshaler interface for gqlgen.
@@ -95,5 +87,5 @@ func (i ImplementationGuidance) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (i *ImplementationGuidance) UnmarshalGQL(v any) error {
-	return unmarshalGQLJSON(v, i)
+	return UnmarshalGQL(v, i)
 }
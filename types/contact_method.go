@@ -81,7 +81,7 @@ func ToContactMethod(r string) ContactMethod {
 // Parameters:
 //   - w: The writer to write the ContactMethod to
 func (r ContactMethod) MarshalGQL(w io.Writer) {
-	_, _ = w.Write([]byte(`"` + r.String() + `"`))
+	MarshalGQLString(w, r.String())
 }
 
 // UnmarshalGQL implements the gqlgen Unmarshaler interface.
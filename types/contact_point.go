@@ -8,8 +8,6 @@ import (
 	"fmt"
 	"io"
 	"strings"
-
-	"github.com/rs/zerolog/log"
 )
 
 // ErrInvalidContactPoint is returned when a contact point is invalid
@@ -115,9 +113,7 @@ func (c ContactPoint) String() string {
 // Parameters:
 //   - w: The writer to write the ContactPoint to
 func (c ContactPoint) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, c); err != nil {
-		log.Error().Err(err).Msg("failed to marshal contact point to GraphQL")
-	}
+	MarshalGQL(w, c)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface for ContactPoint.
@@ -129,9 +125,5 @@ func (c ContactPoint) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (c *ContactPoint) UnmarshalGQL(v interface{}) error {
-	if err := unmarshalGQLJSON(v, c); err != nil {
-		return fmt.Errorf("failed to unmarshal contact point: %w", err)
-	}
-
-	return c.Validate()
+	return UnmarshalValidGQL(v, c)
 }
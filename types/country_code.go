@@ -0,0 +1,232 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// ErrInvalidCountryCode is returned when a value is not an ISO 3166-1 alpha-2 code
+var ErrInvalidCountryCode = errors.New("invalid country code, expected an ISO 3166-1 alpha-2 code like \"DE\"")
+
+// isoCountryCodes lists all officially assigned ISO 3166-1 alpha-2 codes.
+// Reserved codes such as "UK" or "EU" are not included.
+const isoCountryCodes = "" +
+	"AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ " +
+	"BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ " +
+	"CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ " +
+	"DE DJ DK DM DO DZ " +
+	"EC EE EG EH ER ES ET " +
+	"FI FJ FK FM FO FR " +
+	"GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY " +
+	"HK HM HN HR HT HU " +
+	"ID IE IL IM IN IO IQ IR IS IT " +
+	"JE JM JO JP " +
+	"KE KG KH KI KM KN KP KR KW KY KZ " +
+	"LA LB LC LI LK LR LS LT LU LV LY " +
+	"MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ " +
+	"NA NC NE NF NG NI NL NO NP NR NU NZ " +
+	"OM " +
+	"PA PE PF PG PH PK PL PM PN PR PS PT PW PY " +
+	"QA " +
+	"RE RO RS RU RW " +
+	"SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ " +
+	"TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ " +
+	"UA UG UM US UY UZ " +
+	"VA VC VE VG VI VN VU " +
+	"WF WS " +
+	"YE YT " +
+	"ZA ZM ZW"
+
+var countryCodes = func() map[string]struct{} {
+	codes := make(map[string]struct{})
+	for _, c := range strings.Fields(isoCountryCodes) {
+		codes[c] = struct{}{}
+	}
+
+	return codes
+}()
+
+// CountryCode is an ISO 3166-1 alpha-2 country code such as "DE" or "US".
+//
+// Use NewCountryCode to create a validated value; input is case-folded to
+// upper case. Decoding from JSON, YAML, GraphQL or SQL validates the code as
+// well. Empty values decode to the zero CountryCode, so that optional fields
+// can be left empty.
+type CountryCode string
+
+// NewCountryCode canonicalizes and validates an ISO 3166-1 alpha-2 code.
+//
+// Parameters:
+//   - s: The country code, in any case, e.g. "de"
+//
+// Returns:
+//   - CountryCode: The canonical code, e.g. "DE"
+//   - error: ErrInvalidCountryCode if s is not an assigned code
+func NewCountryCode(s string) (CountryCode, error) {
+	c := CountryCode(strings.ToUpper(strings.TrimSpace(s)))
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+
+	return c, nil
+}
+
+// MustCountryCode is like NewCountryCode but panics on error.
+func MustCountryCode(s string) CountryCode {
+	c, err := NewCountryCode(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// CountryCodes returns all assigned ISO 3166-1 alpha-2 codes in alphabetical
+// order.
+func CountryCodes() []CountryCode {
+	fields := strings.Fields(isoCountryCodes)
+
+	codes := make([]CountryCode, 0, len(fields))
+	for _, c := range fields {
+		codes = append(codes, CountryCode(c))
+	}
+
+	return codes
+}
+
+// Validate checks that the code is an assigned ISO 3166-1 alpha-2 code in
+// canonical upper case.
+func (c CountryCode) Validate() error {
+	if _, ok := countryCodes[string(c)]; !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidCountryCode, string(c))
+	}
+
+	return nil
+}
+
+// IsZero reports whether the code is empty.
+func (c CountryCode) IsZero() bool {
+	return c == ""
+}
+
+// String returns the code.
+func (c CountryCode) String() string {
+	return string(c)
+}
+
+// Name returns the name of the country in the given language, e.g.
+// "Germany" for "DE" in "en" or "Deutschland" in "de". English is used if
+// lang is empty. It returns "" for invalid codes.
+func (c CountryCode) Name(lang LanguageCode) string {
+	if c.Validate() != nil {
+		return ""
+	}
+
+	region, err := language.ParseRegion(string(c))
+	if err != nil {
+		return ""
+	}
+
+	return display.Regions(lang.tag()).Name(region)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c CountryCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(c))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *CountryCode) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	return c.set(v)
+}
+
+// MarshalYAML implements the yaml.InterfaceMarshaler interface.
+func (c CountryCode) MarshalYAML() (any, error) {
+	return string(c), nil
+}
+
+// UnmarshalYAML implements the yaml.InterfaceUnmarshaler interface.
+func (c *CountryCode) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	return c.set(v)
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for CountryCode.
+func (c CountryCode) MarshalGQL(w io.Writer) {
+	MarshalGQLString(w, string(c))
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for CountryCode.
+func (c *CountryCode) UnmarshalGQL(v any) error {
+	return c.set(v)
+}
+
+// Scan implements the sql.Scanner interface for CountryCode.
+func (c *CountryCode) Scan(value any) error {
+	return c.set(value)
+}
+
+// Value implements the driver.Valuer interface for CountryCode.
+// The zero value is stored as NULL.
+func (c CountryCode) Value() (driver.Value, error) {
+	if c.IsZero() {
+		return nil, nil
+	}
+
+	return string(c), nil
+}
+
+// set parses and validates a decoded scalar.
+func (c *CountryCode) set(v any) error {
+	s, err := codeString(v, ErrInvalidCountryCode)
+	if err != nil {
+		return err
+	}
+
+	if s == "" {
+		*c = ""
+		return nil
+	}
+
+	parsed, err := NewCountryCode(s)
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+
+	return nil
+}
+
+// codeString converts a decoded scalar into a string; nil yields "".
+func codeString(v any, errInvalid error) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return strings.TrimSpace(val), nil
+	case []byte:
+		return strings.TrimSpace(string(val)), nil
+	default:
+		return "", fmt.Errorf("%w: unsupported type %T", errInvalid, v)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCountryCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    CountryCode
+		wantErr bool
+	}{
+		{name: "upper case", input: "DE", want: "DE"},
+		{name: "lower case", input: "de", want: "DE"},
+		{name: "whitespace", input: " us ", want: "US"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "alpha-3", input: "DEU", wantErr: true},
+		{name: "reserved", input: "UK", wantErr: true},
+		{name: "region", input: "EU", wantErr: true},
+		{name: "user assigned", input: "ZZ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCountryCode(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCountryCode)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCountryCodes(t *testing.T) {
+	codes := CountryCodes()
+	assert.Len(t, codes, 249)
+
+	for _, c := range codes {
+		assert.NoError(t, c.Validate())
+		assert.NotEmpty(t, c.Name(""), c)
+	}
+}
+
+func TestCountryCode_Name(t *testing.T) {
+	de := MustCountryCode("DE")
+
+	assert.Equal(t, "Germany", de.Name(""))
+	assert.Equal(t, "Germany", de.Name("en"))
+	assert.Equal(t, "Deutschland", de.Name("de"))
+	assert.Empty(t, CountryCode("XX").Name(""))
+}
+
+func TestCountryCode_JSON(t *testing.T) {
+	var v struct {
+		Country CountryCode `json:"country"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"country":"at"}`), &v))
+	assert.Equal(t, CountryCode("AT"), v.Country)
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"country":"AT"}`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"country":null}`), &v))
+	assert.True(t, v.Country.IsZero())
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"country":"Austria"}`), &v), ErrInvalidCountryCode)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"country":43}`), &v), ErrInvalidCountryCode)
+}
+
+func TestCountryCode_YAML(t *testing.T) {
+	var v struct {
+		Country CountryCode `yaml:"country"`
+	}
+
+	require.NoError(t, yaml.Unmarshal([]byte("country: ch"), &v))
+	assert.Equal(t, CountryCode("CH"), v.Country)
+
+	data, err := yaml.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, "country: CH\n", string(data))
+
+	assert.Error(t, yaml.Unmarshal([]byte("country: xx"), &v))
+}
+
+func TestCountryCode_GQL(t *testing.T) {
+	var c CountryCode
+	require.NoError(t, c.UnmarshalGQL("fr"))
+	assert.Equal(t, CountryCode("FR"), c)
+
+	var buf bytes.Buffer
+	c.MarshalGQL(&buf)
+	assert.Equal(t, `"FR"`, buf.String())
+
+	assert.ErrorIs(t, c.UnmarshalGQL(42), ErrInvalidCountryCode)
+}
+
+func TestCountryCode_SQL(t *testing.T) {
+	var c CountryCode
+	require.NoError(t, c.Scan([]byte("nl")))
+	assert.Equal(t, CountryCode("NL"), c)
+
+	v, err := c.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "NL", v)
+
+	require.NoError(t, c.Scan(nil))
+	assert.True(t, c.IsZero())
+
+	v, err = c.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
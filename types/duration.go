@@ -0,0 +1,293 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/invopop/jsonschema"
+	"github.com/kopexa-grc/common/duration"
+)
+
+// Duration wraps time.Duration so that it can be expressed in configuration
+// and API payloads as a human-friendly string such as "5m" or "1h30m".
+//
+// Besides Go duration strings, parsing accepts day ("d") and week ("w")
+// units (e.g. "1d12h") and ISO 8601 durations (e.g. "PT5M"). Values are
+// always written in Go duration notation.
+//
+// Example:
+//
+//	type Config struct {
+//		Timeout types.Duration `json:"timeout" yaml:"timeout"`
+//	}
+type Duration time.Duration
+
+var (
+	// ErrInvalidDuration is returned when a value cannot be parsed as a duration
+	ErrInvalidDuration = errors.New("invalid duration, expected a value like \"5m\" or \"1h30m\"")
+	// ErrDurationOutOfRange is returned when a duration is outside the allowed bounds
+	ErrDurationOutOfRange = errors.New("duration out of range")
+)
+
+// dayWeekUnit matches day and week components such as "2d" or "1.5w".
+var dayWeekUnit = regexp.MustCompile(`(\d+(?:\.\d+)?)([dw])`)
+
+// durationPattern is the JSON schema pattern for Duration strings.
+const durationPattern = `^-?(\d+(\.\d+)?(ns|us|µs|ms|s|m|h|d|w))+$|^-?P`
+
+// ParseDuration parses a human-friendly duration string.
+//
+// Parameters:
+//   - s: The string to parse (e.g. "90s", "1h30m", "2d", "PT5M")
+//
+// Returns:
+//   - Duration: The parsed duration
+//   - error: ErrInvalidDuration if the string cannot be parsed
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidDuration
+	}
+
+	if strings.HasPrefix(s, "P") || strings.HasPrefix(s, "-P") {
+		d, err := duration.Parse(s)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+
+		return Duration(d.ToTimeDuration()), nil
+	}
+
+	// time.ParseDuration does not know days and weeks; expand them to hours.
+	expanded := dayWeekUnit.ReplaceAllStringFunc(s, func(m string) string {
+		parts := dayWeekUnit.FindStringSubmatch(m)
+
+		n, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return m
+		}
+
+		hours := n * 24
+		if parts[2] == "w" {
+			hours *= 7
+		}
+
+		return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+	})
+
+	d, err := time.ParseDuration(expanded)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+
+	return Duration(d), nil
+}
+
+// MustParseDuration is like ParseDuration but panics on error.
+// It is intended for defaults and tests.
+func MustParseDuration(s string) Duration {
+	d, err := ParseDuration(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// Duration returns the value as time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String returns the duration in Go duration notation (e.g. "1h30m0s").
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// IsZero reports whether the duration is zero.
+func (d Duration) IsZero() bool {
+	return d == 0
+}
+
+// Validate checks that the duration lies within [minimum, maximum].
+// A zero maximum means there is no upper bound.
+//
+// Parameters:
+//   - minimum: The smallest allowed duration
+//   - maximum: The largest allowed duration, or 0 for no upper bound
+//
+// Returns:
+//   - error: ErrDurationOutOfRange if the duration is outside the bounds
+func (d Duration) Validate(minimum, maximum time.Duration) error {
+	v := time.Duration(d)
+
+	if v < minimum {
+		return fmt.Errorf("%w: %s is less than %s", ErrDurationOutOfRange, v, minimum)
+	}
+
+	if maximum > 0 && v > maximum {
+		return fmt.Errorf("%w: %s is greater than %s", ErrDurationOutOfRange, v, maximum)
+	}
+
+	return nil
+}
+
+// Clamp returns the duration limited to [minimum, maximum].
+// A zero maximum means there is no upper bound.
+func (d Duration) Clamp(minimum, maximum time.Duration) Duration {
+	v := time.Duration(d)
+
+	if v < minimum {
+		return Duration(minimum)
+	}
+
+	if maximum > 0 && v > maximum {
+		return Duration(maximum)
+	}
+
+	return d
+}
+
+// OrDefault returns def if the duration is zero, otherwise the duration itself.
+func (d Duration) OrDefault(def time.Duration) Duration {
+	if d == 0 {
+		return Duration(def)
+	}
+
+	return d
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The duration is written as a string, e.g. "1h30m0s".
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts duration strings as well as plain numbers, which are
+// interpreted as nanoseconds to stay compatible with time.Duration.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	return d.unmarshalAny(v)
+}
+
+// MarshalYAML implements the yaml.InterfaceMarshaler interface.
+func (d Duration) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+// UnmarshalYAML implements the yaml.InterfaceUnmarshaler interface.
+func (d *Duration) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	return d.unmarshalAny(v)
+}
+
+// Scan implements the sql.Scanner interface for Duration.
+// It accepts integers (nanoseconds) and duration strings.
+func (d *Duration) Scan(value any) error {
+	if value == nil {
+		*d = 0
+		return nil
+	}
+
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+
+	return d.unmarshalAny(value)
+}
+
+// Value implements the driver.Valuer interface for Duration.
+// The duration is stored as int64 nanoseconds.
+func (d Duration) Value() (driver.Value, error) {
+	return int64(d), nil
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for Duration.
+func (d Duration) MarshalGQL(w io.Writer) {
+	MarshalGQLString(w, d.String())
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for Duration.
+func (d *Duration) UnmarshalGQL(v any) error {
+	return d.unmarshalAny(v)
+}
+
+// JSONSchema returns the JSON schema describing the serialized form of
+// Duration. It is picked up by jsonschema.Reflector when generating the
+// schema of configuration structs.
+func (Duration) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Pattern:     durationPattern,
+		Description: "duration such as \"30s\", \"5m\", \"1h30m\" or \"2d\"",
+		Examples:    []any{"30s", "5m", "1h30m", "2d"},
+	}
+}
+
+// unmarshalAny converts a decoded scalar into a Duration.
+func (d *Duration) unmarshalAny(v any) error {
+	switch val := v.(type) {
+	case string:
+		parsed, err := ParseDuration(val)
+		if err != nil {
+			return err
+		}
+
+		*d = parsed
+	case float64:
+		*d = Duration(int64(val))
+	case int:
+		*d = Duration(int64(val))
+	case int64:
+		*d = Duration(val)
+	case uint64:
+		*d = Duration(int64(val)) //nolint:gosec // durations beyond int64 are invalid anyway
+	case json.Number:
+		n, err := val.Int64()
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidDuration, val)
+		}
+
+		*d = Duration(n)
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidDuration, v)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/invopop/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "minutes", input: "5m", want: 5 * time.Minute},
+		{name: "hours and minutes", input: "1h30m", want: 90 * time.Minute},
+		{name: "days", input: "2d", want: 48 * time.Hour},
+		{name: "days and hours", input: "1d12h", want: 36 * time.Hour},
+		{name: "weeks", input: "1w", want: 7 * 24 * time.Hour},
+		{name: "fractional days", input: "1.5d", want: 36 * time.Hour},
+		{name: "negative", input: "-30s", want: -30 * time.Second},
+		{name: "iso8601", input: "PT5M", want: 5 * time.Minute},
+		{name: "surrounding whitespace", input: " 10s ", want: 10 * time.Second},
+		{name: "empty", input: "", wantErr: true},
+		{name: "missing unit", input: "5", wantErr: true},
+		{name: "garbage", input: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidDuration)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Duration())
+		})
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	type config struct {
+		Timeout Duration `json:"timeout"`
+	}
+
+	var c config
+	require.NoError(t, json.Unmarshal([]byte(`{"timeout":"1h30m"}`), &c))
+	assert.Equal(t, 90*time.Minute, c.Timeout.Duration())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"timeout":1000000000}`), &c))
+	assert.Equal(t, time.Second, c.Timeout.Duration())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"timeout":"later"}`), &c))
+	assert.Error(t, json.Unmarshal([]byte(`{"timeout":true}`), &c))
+
+	out, err := json.Marshal(config{Timeout: Duration(5 * time.Minute)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"timeout":"5m0s"}`, string(out))
+}
+
+func TestDuration_YAML(t *testing.T) {
+	type config struct {
+		Timeout Duration `yaml:"timeout"`
+	}
+
+	var c config
+	require.NoError(t, yaml.Unmarshal([]byte("timeout: 1d\n"), &c))
+	assert.Equal(t, 24*time.Hour, c.Timeout.Duration())
+
+	out, err := yaml.Marshal(config{Timeout: Duration(90 * time.Second)})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "timeout: 1m30s")
+}
+
+func TestDuration_Text(t *testing.T) {
+	var d Duration
+	require.NoError(t, d.UnmarshalText([]byte("45s")))
+	assert.Equal(t, 45*time.Second, d.Duration())
+
+	text, err := d.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "45s", string(text))
+
+	assert.Error(t, d.UnmarshalText([]byte("x")))
+}
+
+func TestDuration_SQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   any
+		want    Duration
+		wantErr bool
+	}{
+		{name: "nil value", input: nil, want: 0},
+		{name: "int64", input: int64(time.Minute), want: Duration(time.Minute)},
+		{name: "string", input: "2h", want: Duration(2 * time.Hour)},
+		{name: "bytes", input: []byte("3s"), want: Duration(3 * time.Second)},
+		{name: "invalid type", input: time.Now(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Duration
+
+			err := d.Scan(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d)
+		})
+	}
+
+	v, err := Duration(time.Second).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(time.Second), v)
+}
+
+func TestDuration_GQL(t *testing.T) {
+	var buf bytes.Buffer
+
+	Duration(time.Minute).MarshalGQL(&buf)
+	assert.Equal(t, `"1m0s"`, buf.String())
+
+	var d Duration
+	require.NoError(t, d.UnmarshalGQL("10m"))
+	assert.Equal(t, 10*time.Minute, d.Duration())
+}
+
+func TestDuration_Bounds(t *testing.T) {
+	d := Duration(5 * time.Minute)
+
+	assert.NoError(t, d.Validate(time.Minute, time.Hour))
+	assert.NoError(t, d.Validate(time.Minute, 0))
+	assert.ErrorIs(t, d.Validate(10*time.Minute, time.Hour), ErrDurationOutOfRange)
+	assert.ErrorIs(t, d.Validate(0, time.Minute), ErrDurationOutOfRange)
+
+	assert.Equal(t, Duration(10*time.Minute), d.Clamp(10*time.Minute, time.Hour))
+	assert.Equal(t, Duration(time.Minute), d.Clamp(0, time.Minute))
+	assert.Equal(t, d, d.Clamp(0, 0))
+
+	assert.Equal(t, Duration(time.Second), Duration(0).OrDefault(time.Second))
+	assert.Equal(t, d, d.OrDefault(time.Second))
+}
+
+func TestDuration_JSONSchema(t *testing.T) {
+	type config struct {
+		Timeout Duration `json:"timeout"`
+	}
+
+	schema := (&jsonschema.Reflector{DoNotReference: true}).Reflect(&config{})
+
+	prop, ok := schema.Properties.Get("timeout")
+	require.True(t, ok)
+	assert.Equal(t, "string", prop.Type)
+	assert.NotEmpty(t, prop.Pattern)
+
+	pattern := regexp.MustCompile(prop.Pattern)
+	for _, example := range prop.Examples {
+		assert.Regexp(t, pattern, example)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalidEmail is returned when a value is not a valid email address
+var ErrInvalidEmail = errors.New("invalid email address")
+
+const (
+	// maxEmailLength is the maximum length of an address (RFC 5321).
+	maxEmailLength = 254
+	// maxEmailLocalLength is the maximum length of the local part (RFC 5321).
+	maxEmailLocalLength = 64
+)
+
+// emailDomain converts internationalized domains to their ASCII form and
+// validates them for use in email addresses.
+var emailDomain = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.VerifyDNSLength(true))
+
+// Email is a normalized email address such as "jane.doe@example.com".
+//
+// Use NewEmail to create a validated value. Addresses are lower-cased and
+// internationalized domains are converted to their ASCII (punycode) form,
+// so that equal addresses compare equal regardless of how they were typed.
+// Decoding from JSON, YAML, GraphQL or SQL normalizes and validates as well.
+// Empty values decode to the zero Email, so that optional fields can be
+// left empty.
+//
+// String redacts the local part, so that addresses do not leak into logs;
+// use Address for the full address and HashSHA256 to join on addresses
+// without handling PII.
+type Email string
+
+// NewEmail normalizes and validates an email address.
+//
+// Parameters:
+//   - s: The address without display name, e.g. "Jane.Doe@Example.com"
+//
+// Returns:
+//   - Email: The normalized address, e.g. "jane.doe@example.com"
+//   - error: ErrInvalidEmail if s is not a valid address
+func NewEmail(s string) (Email, error) {
+	s = strings.TrimSpace(s)
+
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s {
+		return "", fmt.Errorf("%w: %q", ErrInvalidEmail, s)
+	}
+
+	local, domain, _ := strings.Cut(strings.ToLower(addr.Address), "@")
+
+	domain, err = emailDomain.ToASCII(domain)
+	if err != nil || !strings.Contains(domain, ".") {
+		return "", fmt.Errorf("%w: invalid domain in %q", ErrInvalidEmail, s)
+	}
+
+	e := Email(local + "@" + domain)
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+
+	return e, nil
+}
+
+// MustEmail is like NewEmail but panics on error.
+func MustEmail(s string) Email {
+	e, err := NewEmail(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return e
+}
+
+// Validate checks that the address is normalized and within the length
+// limits of RFC 5321.
+func (e Email) Validate() error {
+	local, domain, ok := strings.Cut(string(e), "@")
+
+	switch {
+	case !ok || local == "" || domain == "":
+		return fmt.Errorf("%w: missing local part or domain", ErrInvalidEmail)
+	case len(e) > maxEmailLength || len(local) > maxEmailLocalLength:
+		return fmt.Errorf("%w: address too long", ErrInvalidEmail)
+	case string(e) != strings.ToLower(string(e)):
+		return fmt.Errorf("%w: address is not normalized", ErrInvalidEmail)
+	}
+
+	return nil
+}
+
+// IsZero reports whether the address is empty.
+func (e Email) IsZero() bool {
+	return e == ""
+}
+
+// Address returns the full normalized address.
+func (e Email) Address() string {
+	return string(e)
+}
+
+// LocalPart returns the part before the "@".
+func (e Email) LocalPart() string {
+	local, _, _ := strings.Cut(string(e), "@")
+	return local
+}
+
+// Domain returns the ASCII domain after the "@", e.g. to group users by
+// organization.
+func (e Email) Domain() string {
+	_, domain, _ := strings.Cut(string(e), "@")
+	return domain
+}
+
+// HashSHA256 returns the hex encoded SHA-256 hash of the normalized address.
+// The hash is stable across services and can be used to join analytics data
+// without storing the address. It is not a substitute for encryption:
+// known addresses can be hashed and compared. The zero Email hashes to "".
+func (e Email) HashSHA256() string {
+	if e.IsZero() {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(e))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// String returns the address with a redacted local part, e.g.
+// "j***@example.com", so that addresses can be logged safely.
+func (e Email) String() string {
+	if e.IsZero() {
+		return ""
+	}
+
+	local, domain, ok := strings.Cut(string(e), "@")
+	if !ok || local == "" {
+		return "***"
+	}
+
+	return local[:1] + "***@" + domain
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Email) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(e))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *Email) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	return e.set(v)
+}
+
+// MarshalYAML implements the yaml.InterfaceMarshaler interface.
+func (e Email) MarshalYAML() (any, error) {
+	return string(e), nil
+}
+
+// UnmarshalYAML implements the yaml.InterfaceUnmarshaler interface.
+func (e *Email) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	return e.set(v)
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for Email.
+func (e Email) MarshalGQL(w io.Writer) {
+	MarshalGQLString(w, string(e))
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for Email.
+func (e *Email) UnmarshalGQL(v any) error {
+	return e.set(v)
+}
+
+// Scan implements the sql.Scanner interface for Email.
+func (e *Email) Scan(value any) error {
+	return e.set(value)
+}
+
+// Value implements the driver.Valuer interface for Email.
+// The zero value is stored as NULL.
+func (e Email) Value() (driver.Value, error) {
+	if e.IsZero() {
+		return nil, nil
+	}
+
+	return string(e), nil
+}
+
+// set parses and validates a decoded scalar.
+func (e *Email) set(v any) error {
+	s, err := codeString(v, ErrInvalidEmail)
+	if err != nil {
+		return err
+	}
+
+	if s == "" {
+		*e = ""
+		return nil
+	}
+
+	parsed, err := NewEmail(s)
+	if err != nil {
+		return err
+	}
+
+	*e = parsed
+
+	return nil
+}
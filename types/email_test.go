@@ -0,0 +1,128 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Email
+		wantErr bool
+	}{
+		{name: "normalized", input: "jane.doe@example.com", want: "jane.doe@example.com"},
+		{name: "mixed case", input: "Jane.Doe@Example.COM", want: "jane.doe@example.com"},
+		{name: "whitespace", input: " jane@example.com ", want: "jane@example.com"},
+		{name: "plus tag", input: "jane+audit@example.com", want: "jane+audit@example.com"},
+		{name: "idn domain", input: "info@Bücher.de", want: "info@xn--bcher-kva.de"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "missing at", input: "jane.example.com", wantErr: true},
+		{name: "display name", input: "Jane <jane@example.com>", wantErr: true},
+		{name: "missing tld", input: "jane@localhost", wantErr: true},
+		{name: "two addresses", input: "a@example.com, b@example.com", wantErr: true},
+		{name: "local part too long", input: strings.Repeat("a", 65) + "@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEmail(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidEmail)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEmail_Accessors(t *testing.T) {
+	e := MustEmail("Jane.Doe@Example.com")
+
+	assert.Equal(t, "jane.doe@example.com", e.Address())
+	assert.Equal(t, "jane.doe", e.LocalPart())
+	assert.Equal(t, "example.com", e.Domain())
+	assert.Equal(t, "j***@example.com", e.String())
+	assert.Equal(t, "j***@example.com", fmt.Sprintf("%v", e))
+	assert.Empty(t, Email("").String())
+
+	assert.Equal(t, "86e0b9e56c17cc4d12387e1949b85053fbe73bc3ce5a1188713a9d300cc6133d", e.HashSHA256())
+	assert.Equal(t, e.HashSHA256(), MustEmail("JANE.DOE@EXAMPLE.COM").HashSHA256())
+	assert.Empty(t, Email("").HashSHA256())
+}
+
+func TestEmail_JSON(t *testing.T) {
+	var v struct {
+		Email Email `json:"email"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"email":"Jane@Example.com"}`), &v))
+	assert.Equal(t, Email("jane@example.com"), v.Email)
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"email":"jane@example.com"}`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"email":null}`), &v))
+	assert.True(t, v.Email.IsZero())
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"email":"jane"}`), &v), ErrInvalidEmail)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"email":42}`), &v), ErrInvalidEmail)
+}
+
+func TestEmail_YAML(t *testing.T) {
+	var v struct {
+		Email Email `yaml:"email"`
+	}
+
+	require.NoError(t, yaml.Unmarshal([]byte("email: Jane@Example.com"), &v))
+	assert.Equal(t, Email("jane@example.com"), v.Email)
+
+	data, err := yaml.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, "email: jane@example.com\n", string(data))
+
+	assert.Error(t, yaml.Unmarshal([]byte("email: jane"), &v))
+}
+
+func TestEmail_GQL(t *testing.T) {
+	var e Email
+	require.NoError(t, e.UnmarshalGQL("Jane@Example.com"))
+	assert.Equal(t, Email("jane@example.com"), e)
+
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	assert.Equal(t, `"jane@example.com"`, buf.String())
+
+	assert.ErrorIs(t, e.UnmarshalGQL(42), ErrInvalidEmail)
+}
+
+func TestEmail_SQL(t *testing.T) {
+	var e Email
+	require.NoError(t, e.Scan([]byte("Jane@Example.com")))
+	assert.Equal(t, Email("jane@example.com"), e)
+
+	v, err := e.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", v)
+
+	require.NoError(t, e.Scan(nil))
+	assert.True(t, e.IsZero())
+
+	v, err = e.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
@@ -0,0 +1,345 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	// ErrNoEncryptionKeyProvider is returned when an EncryptedString is
+	// encrypted or decrypted before SetEncryptionKeyProvider was called.
+	ErrNoEncryptionKeyProvider = errors.New("no encryption key provider configured")
+	// ErrUnknownEncryptionKey is returned when a ciphertext references a key
+	// the provider does not know.
+	ErrUnknownEncryptionKey = errors.New("unknown encryption key")
+	// ErrInvalidEncryptionKey is returned for keys that are not 32 bytes long.
+	ErrInvalidEncryptionKey = errors.New("encryption key must be 32 bytes")
+	// ErrInvalidCiphertext is returned when a ciphertext is malformed or
+	// fails authentication.
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// encryptedPrefix marks values encrypted by EncryptedString, followed by the
+// key ID and the base64 encoded nonce and ciphertext.
+const encryptedPrefix = "enc:v1:"
+
+// EncryptionKeySize is the size of AES-256 keys used by EncryptedString.
+const EncryptionKeySize = 32
+
+// EncryptionKeyProvider supplies the keys used by EncryptedString.
+type EncryptionKeyProvider interface {
+	// CurrentKey returns the ID and key used to encrypt new values.
+	CurrentKey() (id string, key []byte, err error)
+	// Key returns the key with the given ID, or ErrUnknownEncryptionKey.
+	Key(id string) ([]byte, error)
+}
+
+var encryptionKeys atomic.Pointer[EncryptionKeyProvider]
+
+// SetEncryptionKeyProvider configures the key provider used by all
+// EncryptedString values. It is typically called once on startup; passing
+// nil disables encryption, so that encoding and decoding fail.
+func SetEncryptionKeyProvider(p EncryptionKeyProvider) {
+	if p == nil {
+		encryptionKeys.Store(nil)
+		return
+	}
+
+	encryptionKeys.Store(&p)
+}
+
+func encryptionKeyProvider() (EncryptionKeyProvider, error) {
+	p := encryptionKeys.Load()
+	if p == nil {
+		return nil, ErrNoEncryptionKeyProvider
+	}
+
+	return *p, nil
+}
+
+// StaticKeyProvider is an EncryptionKeyProvider backed by a fixed set of
+// keys. Keep retired keys in the set until all values have been re-encrypted
+// with the current key.
+type StaticKeyProvider struct {
+	current string
+	keys    map[string][]byte
+}
+
+// NewStaticKeyProvider returns a provider that encrypts with the key
+// currentID and decrypts with any of keys. All keys must be
+// EncryptionKeySize bytes long.
+func NewStaticKeyProvider(currentID string, keys map[string][]byte) (*StaticKeyProvider, error) {
+	if _, ok := keys[currentID]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncryptionKey, currentID)
+	}
+
+	p := &StaticKeyProvider{current: currentID, keys: make(map[string][]byte, len(keys))}
+
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("%w: invalid key ID %q", ErrInvalidEncryptionKey, id)
+		}
+
+		if len(key) != EncryptionKeySize {
+			return nil, fmt.Errorf("%w: key %q has %d bytes", ErrInvalidEncryptionKey, id, len(key))
+		}
+
+		p.keys[id] = append([]byte(nil), key...)
+	}
+
+	return p, nil
+}
+
+// CurrentKey implements EncryptionKeyProvider.
+func (p *StaticKeyProvider) CurrentKey() (string, []byte, error) {
+	return p.current, p.keys[p.current], nil
+}
+
+// Key implements EncryptionKeyProvider.
+func (p *StaticKeyProvider) Key(id string) ([]byte, error) {
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncryptionKey, id)
+	}
+
+	return key, nil
+}
+
+// EncryptedString is a string that is encrypted whenever it leaves the
+// process, e.g. an API secret stored in an entity's JSON column.
+//
+// The value is held in plaintext in memory. Value and MarshalJSON encrypt it
+// with AES-256-GCM using the current key of the configured
+// EncryptionKeyProvider; Scan and UnmarshalJSON decrypt it with the key
+// referenced in the ciphertext, so keys can be rotated. Plaintext input is
+// accepted by UnmarshalJSON and UnmarshalGQL to support API input and
+// migrating existing data.
+//
+// String, MarshalGQL and the fmt verbs never reveal the value; use Reveal.
+// The empty string is stored as is.
+type EncryptedString string
+
+// NewEncryptedString returns an EncryptedString holding plaintext.
+func NewEncryptedString(plaintext string) EncryptedString {
+	return EncryptedString(plaintext)
+}
+
+// Reveal returns the plaintext.
+func (s EncryptedString) Reveal() string {
+	return string(s)
+}
+
+// IsZero reports whether the value is empty.
+func (s EncryptedString) IsZero() bool {
+	return s == ""
+}
+
+// String implements fmt.Stringer and returns RedactedValue for non-empty
+// values.
+func (s EncryptedString) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return RedactedValue
+}
+
+// GoString implements fmt.GoStringer so that %#v does not reveal the value.
+func (s EncryptedString) GoString() string {
+	return strconv.Quote(s.String())
+}
+
+// Encrypt returns the ciphertext of the value, or the empty string for an
+// empty value.
+func (s EncryptedString) Encrypt() (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	p, err := encryptionKeyProvider()
+	if err != nil {
+		return "", err
+	}
+
+	id, key, err := p.CurrentKey()
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(s), []byte(id))
+
+	return encryptedPrefix + id + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptString decrypts a ciphertext produced by Encrypt.
+func DecryptString(ciphertext string) (EncryptedString, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+
+	rest, ok := strings.CutPrefix(ciphertext, encryptedPrefix)
+	if !ok {
+		return "", ErrInvalidCiphertext
+	}
+
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrInvalidCiphertext
+	}
+
+	p, err := encryptionKeyProvider()
+	if err != nil {
+		return "", err
+	}
+
+	key, err := p.Key(id)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidCiphertext, err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, data := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, data, []byte(id))
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	return EncryptedString(plaintext), nil
+}
+
+// IsEncrypted reports whether v looks like a ciphertext produced by Encrypt.
+func IsEncrypted(v string) bool {
+	return strings.HasPrefix(v, encryptedPrefix)
+}
+
+// MarshalJSON implements the json.Marshaler interface and writes the
+// ciphertext as JSON string.
+func (s EncryptedString) MarshalJSON() ([]byte, error) {
+	ciphertext, err := s.Encrypt()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(ciphertext)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Ciphertexts are
+// decrypted; other strings are taken as plaintext.
+func (s *EncryptedString) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	return s.set(v)
+}
+
+// MarshalGQL implements the graphql.Marshaler interface. The value is
+// redacted; secrets are write-only through the API.
+func (s EncryptedString) MarshalGQL(w io.Writer) {
+	MarshalGQLString(w, s.String())
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface and accepts the
+// plaintext.
+func (s *EncryptedString) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("%w: expected string, got %T", ErrInvalidCiphertext, v)
+	}
+
+	*s = EncryptedString(str)
+
+	return nil
+}
+
+// Scan implements the sql.Scanner interface and decrypts the stored value.
+func (s *EncryptedString) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*s = ""
+		return nil
+	case string:
+		return s.decrypt(v)
+	case []byte:
+		return s.decrypt(string(v))
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidCiphertext, value)
+	}
+}
+
+// Value implements the driver.Valuer interface and returns the ciphertext.
+func (s EncryptedString) Value() (driver.Value, error) {
+	return s.Encrypt()
+}
+
+// set stores v, decrypting it if it is a ciphertext.
+func (s *EncryptedString) set(v string) error {
+	if !IsEncrypted(v) {
+		*s = EncryptedString(v)
+		return nil
+	}
+
+	return s.decrypt(v)
+}
+
+func (s *EncryptedString) decrypt(v string) error {
+	plaintext, err := DecryptString(v)
+	if err != nil {
+		return err
+	}
+
+	*s = plaintext
+
+	return nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
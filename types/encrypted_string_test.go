@@ -0,0 +1,135 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useTestKeys(t *testing.T, current string, ids ...string) {
+	t.Helper()
+
+	keys := make(map[string][]byte, len(ids))
+	for i, id := range ids {
+		keys[id] = bytes.Repeat([]byte{byte(i + 1)}, EncryptionKeySize)
+	}
+
+	p, err := NewStaticKeyProvider(current, keys)
+	require.NoError(t, err)
+
+	SetEncryptionKeyProvider(p)
+	t.Cleanup(func() { SetEncryptionKeyProvider(nil) })
+}
+
+type integrationConfig struct {
+	Name   string          `json:"name"`
+	Secret EncryptedString `json:"secret"`
+}
+
+func TestEncryptedString_JSON(t *testing.T) {
+	useTestKeys(t, "k1", "k1")
+
+	data, err := json.Marshal(integrationConfig{Name: "jira", Secret: "s3cr3t"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+	assert.Contains(t, string(data), `"enc:v1:k1:`)
+
+	var decoded integrationConfig
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "s3cr3t", decoded.Secret.Reveal())
+
+	// plaintext input is accepted
+	require.NoError(t, json.Unmarshal([]byte(`{"secret":"plain"}`), &decoded))
+	assert.Equal(t, "plain", decoded.Secret.Reveal())
+}
+
+func TestEncryptedString_SQL(t *testing.T) {
+	useTestKeys(t, "k1", "k1")
+
+	v, err := NewEncryptedString("s3cr3t").Value()
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(v.(string)))
+
+	var s EncryptedString
+	require.NoError(t, s.Scan([]byte(v.(string))))
+	assert.Equal(t, "s3cr3t", s.Reveal())
+
+	require.NoError(t, s.Scan(nil))
+	assert.True(t, s.IsZero())
+
+	// empty values stay empty
+	v, err = EncryptedString("").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "", v)
+}
+
+func TestEncryptedString_KeyRotation(t *testing.T) {
+	useTestKeys(t, "k1", "k1")
+
+	old, err := NewEncryptedString("s3cr3t").Encrypt()
+	require.NoError(t, err)
+
+	useTestKeys(t, "k2", "k1", "k2")
+
+	s, err := DecryptString(old)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", s.Reveal())
+
+	rotated, err := s.Encrypt()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rotated, "enc:v1:k2:"))
+
+	useTestKeys(t, "k2", "k2")
+
+	_, err = DecryptString(old)
+	assert.ErrorIs(t, err, ErrUnknownEncryptionKey)
+}
+
+func TestEncryptedString_Errors(t *testing.T) {
+	_, err := NewEncryptedString("s3cr3t").Encrypt()
+	assert.ErrorIs(t, err, ErrNoEncryptionKeyProvider)
+
+	useTestKeys(t, "k1", "k1")
+
+	ciphertext, err := NewEncryptedString("s3cr3t").Encrypt()
+	require.NoError(t, err)
+
+	tampered := ciphertext[:len(ciphertext)-2] + "AA"
+	_, err = DecryptString(tampered)
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	// the key ID is authenticated
+	useTestKeys(t, "k2", "k1", "k2")
+
+	_, err = DecryptString(strings.Replace(ciphertext, ":k1:", ":k2:", 1))
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	_, err = NewStaticKeyProvider("k1", map[string][]byte{"k1": []byte("short")})
+	assert.ErrorIs(t, err, ErrInvalidEncryptionKey)
+
+	_, err = NewStaticKeyProvider("missing", map[string][]byte{})
+	assert.ErrorIs(t, err, ErrUnknownEncryptionKey)
+}
+
+func TestEncryptedString_Redaction(t *testing.T) {
+	s := NewEncryptedString("s3cr3t")
+
+	assert.Equal(t, RedactedValue, s.String())
+	assert.NotContains(t, fmt.Sprintf("%v %s %#v %+v", s, s, s, integrationConfig{Secret: s}), "s3cr3t")
+
+	var buf bytes.Buffer
+	s.MarshalGQL(&buf)
+	assert.Equal(t, `"[REDACTED]"`, buf.String())
+
+	var in EncryptedString
+	require.NoError(t, in.UnmarshalGQL("new-secret"))
+	assert.Equal(t, "new-secret", in.Reveal())
+}
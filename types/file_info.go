@@ -11,8 +11,6 @@ import (
 	"mime"
 	"slices"
 	"strings"
-
-	"github.com/rs/zerolog/log"
 )
 
 const (
@@ -130,7 +128,7 @@ func (d FileInfo) String() string {
 // Returns:
 //   - error: If unmarshaling fails
 func (d *FileInfo) UnmarshalGQL(v any) error {
-	return unmarshalGQLJSON(v, d)
+	return UnmarshalGQL(v, d)
 }
 
 // MarshalGQL implements the graphql.Marshaler interface for FileInfo.
@@ -139,7 +137,5 @@ func (d *FileInfo) UnmarshalGQL(v any) error {
 // Parameters:
 //   - w: The writer to write the FileInfo to
 func (d FileInfo) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, d); err != nil {
-		log.Error().Err(err).Msg("failed to marshal file info to GraphQL")
-	}
+	MarshalGQL(w, d)
 }
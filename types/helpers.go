@@ -6,7 +6,10 @@ package types
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
+	"strconv"
 
 	"github.com/rs/zerolog/log"
 )
@@ -18,6 +21,62 @@ var (
 	ErrNilValue = errors.New("value cannot be nil")
 )
 
+// MarshalGQL writes v as a JSON object scalar. Errors are logged, as the
+// graphql.Marshaler interface cannot return them.
+//
+// Together with UnmarshalGQL it is the way to make a struct a GraphQL scalar;
+// the type only forwards its gqlgen methods:
+//
+//	func (a Address) MarshalGQL(w io.Writer) {
+//		MarshalGQL(w, a)
+//	}
+//
+//	func (a *Address) UnmarshalGQL(v any) error {
+//		return UnmarshalGQL(v, a)
+//	}
+//
+// Types with a Validate method use UnmarshalValidGQL instead.
+func MarshalGQL[T any](w io.Writer, v T) {
+	if err := marshalGQLJSON(w, v); err != nil {
+		log.Error().Err(err).Str("type", gqlTypeName[T]()).Msg("failed to marshal GraphQL scalar")
+	}
+}
+
+// UnmarshalGQL decodes the GraphQL input v into target. Errors are wrapped
+// with the name of T; a nil input returns an error wrapping ErrNilValue.
+func UnmarshalGQL[T any](v any, target *T) error {
+	if err := unmarshalGQLJSON(v, target); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", gqlTypeName[T](), err)
+	}
+
+	return nil
+}
+
+// UnmarshalValidGQL decodes v into target like UnmarshalGQL and validates
+// the result if T implements Validate() error.
+func UnmarshalValidGQL[T any](v any, target *T) error {
+	if err := UnmarshalGQL(v, target); err != nil {
+		return err
+	}
+
+	// The method set of *T includes methods with a value receiver.
+	if t, ok := any(target).(interface{ Validate() error }); ok {
+		return t.Validate()
+	}
+
+	return nil
+}
+
+// MarshalGQLString writes s as a quoted GraphQL string scalar.
+func MarshalGQLString(w io.Writer, s string) {
+	_, _ = io.WriteString(w, strconv.Quote(s))
+}
+
+// gqlTypeName returns the name of T for error messages.
+func gqlTypeName[T any]() string {
+	return reflect.TypeFor[T]().Name()
+}
+
 // marshalGQLJSON marshals the given type into JSON and writes it to the given writer.
 // It handles error cases and provides proper logging.
 //
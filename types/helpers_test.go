@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMarshalGQLJSON(t *testing.T) {
@@ -112,3 +113,48 @@ func TestUnmarshalGQLJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalGQL(t *testing.T) {
+	var buf bytes.Buffer
+
+	MarshalGQL(&buf, Links{"docs": "https://example.com"})
+	assert.JSONEq(t, `{"docs":"https://example.com"}`, buf.String())
+
+	// errors are logged, not returned
+	assert.NotPanics(t, func() { MarshalGQL(nil, Links{}) })
+
+	buf.Reset()
+	MarshalGQLString(&buf, `say "hi"`)
+	assert.Equal(t, `"say \"hi\""`, buf.String())
+}
+
+func TestUnmarshalGQL(t *testing.T) {
+	var l Links
+
+	require.NoError(t, UnmarshalGQL(map[string]any{"docs": "https://example.com"}, &l))
+	assert.Equal(t, Links{"docs": "https://example.com"}, l)
+
+	err := UnmarshalGQL(nil, &l)
+	require.ErrorIs(t, err, ErrNilValue)
+	assert.Contains(t, err.Error(), "failed to unmarshal Links")
+
+	err = UnmarshalGQL("not an object", &l)
+	assert.ErrorContains(t, err, "failed to unmarshal Links")
+}
+
+func TestUnmarshalValidGQL(t *testing.T) {
+	// value receiver
+	var p ContactPoint
+
+	assert.Error(t, UnmarshalValidGQL(map[string]any{}, &p))
+
+	// pointer receiver
+	var r Reference
+
+	assert.Error(t, UnmarshalValidGQL(map[string]any{}, &r))
+
+	// types without Validate are only decoded
+	var l Links
+
+	assert.NoError(t, UnmarshalValidGQL(map[string]any{}, &l))
+}
@@ -0,0 +1,264 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// ErrInvalidLanguageCode is returned when a value is not a supported BCP 47 language tag
+var ErrInvalidLanguageCode = errors.New("invalid language code, expected a BCP 47 tag like \"de\" or \"de-DE\"")
+
+// languageUndetermined is the BCP 47 code for an undetermined language.
+const languageUndetermined = "und"
+
+// LanguageCode is a BCP 47 language tag restricted to the subset
+// language[-Script][-REGION], e.g. "de", "de-DE" or "zh-Hans-CN". The
+// language is an ISO 639 code, the script an ISO 15924 code and the region
+// an ISO 3166-1 alpha-2 code. Extensions, variants and private use subtags
+// are not supported.
+//
+// Use NewLanguageCode to create a validated value; subtags are case-folded to
+// their canonical form ("EN-us" becomes "en-US"). Decoding from JSON, YAML,
+// GraphQL or SQL validates the tag as well. Empty values decode to the zero
+// LanguageCode, so that optional fields can be left empty.
+type LanguageCode string
+
+// NewLanguageCode canonicalizes and validates a language tag.
+//
+// Parameters:
+//   - s: The language tag, in any case, e.g. "EN-us"
+//
+// Returns:
+//   - LanguageCode: The canonical tag, e.g. "en-US"
+//   - error: ErrInvalidLanguageCode if s is not a supported tag
+func NewLanguageCode(s string) (LanguageCode, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) > 3 { //nolint:mnd
+		return "", fmt.Errorf("%w: %q", ErrInvalidLanguageCode, s)
+	}
+
+	canonical := []string{strings.ToLower(parts[0])}
+
+	for i, p := range parts[1:] {
+		switch {
+		case i == 0 && len(p) == 4: //nolint:mnd
+			canonical = append(canonical, strings.ToUpper(p[:1])+strings.ToLower(p[1:]))
+		case len(p) == 2: //nolint:mnd
+			canonical = append(canonical, strings.ToUpper(p))
+		default:
+			return "", fmt.Errorf("%w: %q", ErrInvalidLanguageCode, s)
+		}
+	}
+
+	l := LanguageCode(strings.Join(canonical, "-"))
+	if err := l.Validate(); err != nil {
+		return "", err
+	}
+
+	return l, nil
+}
+
+// MustLanguageCode is like NewLanguageCode but panics on error.
+func MustLanguageCode(s string) LanguageCode {
+	l, err := NewLanguageCode(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return l
+}
+
+// Validate checks that the tag is a supported language tag in canonical
+// form.
+func (l LanguageCode) Validate() error {
+	invalid := fmt.Errorf("%w: %q", ErrInvalidLanguageCode, string(l))
+
+	base, script, region := l.split()
+
+	if len(base) < 2 || len(base) > 3 || base != strings.ToLower(base) || base == languageUndetermined {
+		return invalid
+	}
+
+	if _, err := language.ParseBase(base); err != nil {
+		return invalid
+	}
+
+	if script != "" {
+		if script != strings.ToUpper(script[:1])+strings.ToLower(script[1:]) {
+			return invalid
+		}
+
+		if _, err := language.ParseScript(script); err != nil {
+			return invalid
+		}
+	}
+
+	if region != "" && region.Validate() != nil {
+		return invalid
+	}
+
+	return nil
+}
+
+// split returns the subtags of the tag. It does not validate them.
+func (l LanguageCode) split() (base, script string, region CountryCode) {
+	parts := strings.Split(string(l), "-")
+	base = parts[0]
+
+	for _, p := range parts[1:] {
+		if len(p) == 4 && script == "" && region == "" { //nolint:mnd
+			script = p
+		} else {
+			region = CountryCode(p)
+		}
+	}
+
+	return base, script, region
+}
+
+// Base returns the language without script and region, e.g. "de" for
+// "de-DE".
+func (l LanguageCode) Base() LanguageCode {
+	base, _, _ := l.split()
+	return LanguageCode(base)
+}
+
+// Region returns the region of the tag, e.g. "DE" for "de-DE", or the zero
+// CountryCode if the tag has none.
+func (l LanguageCode) Region() CountryCode {
+	_, _, region := l.split()
+	return region
+}
+
+// IsZero reports whether the tag is empty.
+func (l LanguageCode) IsZero() bool {
+	return l == ""
+}
+
+// String returns the tag.
+func (l LanguageCode) String() string {
+	return string(l)
+}
+
+// tag returns the tag for display lookups; English if l is empty or
+// invalid.
+func (l LanguageCode) tag() language.Tag {
+	if l.Validate() != nil {
+		return language.English
+	}
+
+	tag, err := language.Parse(string(l))
+	if err != nil {
+		return language.English
+	}
+
+	return tag
+}
+
+// Name returns the name of the language in the given language, e.g.
+// "German" for "de" in "en" or "Deutsch (Schweiz)" for "de-CH" in "de".
+// English is used if in is empty. It returns "" for invalid tags.
+func (l LanguageCode) Name(in LanguageCode) string {
+	if l.Validate() != nil {
+		return ""
+	}
+
+	return display.Tags(in.tag()).Name(l.tag())
+}
+
+// SelfName returns the name of the language in the language itself, e.g.
+// "Deutsch" for "de". It returns "" for invalid tags.
+func (l LanguageCode) SelfName() string {
+	if l.Validate() != nil {
+		return ""
+	}
+
+	return display.Self.Name(l.tag())
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (l LanguageCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(l))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (l *LanguageCode) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	return l.set(v)
+}
+
+// MarshalYAML implements the yaml.InterfaceMarshaler interface.
+func (l LanguageCode) MarshalYAML() (any, error) {
+	return string(l), nil
+}
+
+// UnmarshalYAML implements the yaml.InterfaceUnmarshaler interface.
+func (l *LanguageCode) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	return l.set(v)
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for LanguageCode.
+func (l LanguageCode) MarshalGQL(w io.Writer) {
+	MarshalGQLString(w, string(l))
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for LanguageCode.
+func (l *LanguageCode) UnmarshalGQL(v any) error {
+	return l.set(v)
+}
+
+// Scan implements the sql.Scanner interface for LanguageCode.
+func (l *LanguageCode) Scan(value any) error {
+	return l.set(value)
+}
+
+// Value implements the driver.Valuer interface for LanguageCode.
+// The zero value is stored as NULL.
+func (l LanguageCode) Value() (driver.Value, error) {
+	if l.IsZero() {
+		return nil, nil
+	}
+
+	return string(l), nil
+}
+
+// set parses and validates a decoded scalar.
+func (l *LanguageCode) set(v any) error {
+	s, err := codeString(v, ErrInvalidLanguageCode)
+	if err != nil {
+		return err
+	}
+
+	if s == "" {
+		*l = ""
+		return nil
+	}
+
+	parsed, err := NewLanguageCode(s)
+	if err != nil {
+		return err
+	}
+
+	*l = parsed
+
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLanguageCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    LanguageCode
+		wantErr bool
+	}{
+		{name: "language", input: "de", want: "de"},
+		{name: "upper case language", input: "DE", want: "de"},
+		{name: "three letter language", input: "gsw", want: "gsw"},
+		{name: "language and region", input: "en-us", want: "en-US"},
+		{name: "language and script", input: "SR-CYRL", want: "sr-Cyrl"},
+		{name: "language, script and region", input: "zh-hans-cn", want: "zh-Hans-CN"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "unknown language", input: "xx", wantErr: true},
+		{name: "undetermined", input: "und", wantErr: true},
+		{name: "unknown region", input: "de-XX", wantErr: true},
+		{name: "unknown script", input: "de-Abcd", wantErr: true},
+		{name: "region before script", input: "zh-CN-Hans", wantErr: true},
+		{name: "numeric region", input: "es-419", wantErr: true},
+		{name: "underscore", input: "de_DE", wantErr: true},
+		{name: "variant", input: "de-DE-1901", wantErr: true},
+		{name: "too many subtags", input: "de-Latn-DE-x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewLanguageCode(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidLanguageCode)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLanguageCode_Validate(t *testing.T) {
+	assert.NoError(t, LanguageCode("de-DE").Validate())
+	assert.ErrorIs(t, LanguageCode("DE-de").Validate(), ErrInvalidLanguageCode)
+	assert.ErrorIs(t, LanguageCode("sr-CYRL").Validate(), ErrInvalidLanguageCode)
+}
+
+func TestLanguageCode_Parts(t *testing.T) {
+	l := MustLanguageCode("zh-Hans-CN")
+
+	assert.Equal(t, LanguageCode("zh"), l.Base())
+	assert.Equal(t, CountryCode("CN"), l.Region())
+	assert.True(t, MustLanguageCode("de").Region().IsZero())
+}
+
+func TestLanguageCode_Name(t *testing.T) {
+	assert.Equal(t, "German", MustLanguageCode("de").Name(""))
+	assert.Equal(t, "Deutsch", MustLanguageCode("de").Name("de"))
+	assert.Equal(t, "Englisch", MustLanguageCode("en").Name("de"))
+	assert.Equal(t, "Deutsch", MustLanguageCode("de").SelfName())
+	assert.Empty(t, LanguageCode("xx").Name(""))
+	assert.Empty(t, LanguageCode("xx").SelfName())
+}
+
+func TestLanguageCode_Marshaling(t *testing.T) {
+	var v struct {
+		Language LanguageCode `json:"language" yaml:"language"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"language":"de-at"}`), &v))
+	assert.Equal(t, LanguageCode("de-AT"), v.Language)
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"language":"de-AT"}`, string(data))
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"language":"german"}`), &v), ErrInvalidLanguageCode)
+
+	require.NoError(t, yaml.Unmarshal([]byte("language: FR"), &v))
+	assert.Equal(t, LanguageCode("fr"), v.Language)
+
+	var buf bytes.Buffer
+	v.Language.MarshalGQL(&buf)
+	assert.Equal(t, `"fr"`, buf.String())
+
+	var l LanguageCode
+	require.NoError(t, l.UnmarshalGQL("EN-gb"))
+	assert.Equal(t, LanguageCode("en-GB"), l)
+
+	require.NoError(t, l.Scan("it"))
+	assert.Equal(t, LanguageCode("it"), l)
+
+	require.NoError(t, l.Scan(nil))
+
+	value, err := l.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
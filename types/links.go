@@ -0,0 +1,238 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Link relations used in Links.
+const (
+	// LinkSelf is the URL of the current resource or page.
+	LinkSelf = "self"
+	// LinkNext is the URL of the next page.
+	LinkNext = "next"
+	// LinkPrev is the URL of the previous page.
+	LinkPrev = "prev"
+	// LinkRelated is the URL of a related resource.
+	LinkRelated = "related"
+)
+
+// ResponseLinksKey is the ResponseData key under which links are stored.
+// The underscore keeps it apart from question keys, following the HAL
+// convention.
+const ResponseLinksKey = "_links"
+
+// DefaultCursorParam is the query parameter that carries pagination cursors.
+const DefaultCursorParam = "cursor"
+
+// Links maps link relations such as LinkSelf or LinkNext to absolute URLs.
+// It serializes as a flat JSON object, e.g. {"self": "https://..."}.
+type Links map[string]string
+
+// Self returns the LinkSelf URL.
+func (l Links) Self() string {
+	return l[LinkSelf]
+}
+
+// Next returns the LinkNext URL, empty on the last page.
+func (l Links) Next() string {
+	return l[LinkNext]
+}
+
+// Prev returns the LinkPrev URL, empty on the first page.
+func (l Links) Prev() string {
+	return l[LinkPrev]
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for Links.
+// It allows Links to be used as a GraphQL scalar type.
+//
+// Parameters:
+//   - w: The writer to write the Links to
+func (l Links) MarshalGQL(w io.Writer) {
+	MarshalGQL(w, l)
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for Links.
+// It allows Links to be used as a GraphQL scalar type.
+//
+// Parameters:
+//   - v: The value to unmarshal
+//
+// Returns:
+//   - error: If unmarshaling fails
+func (l *Links) UnmarshalGQL(v interface{}) error {
+	return UnmarshalGQL(v, l)
+}
+
+// SetLinks stores links in the response data under ResponseLinksKey, so
+// they are serialized the same way in JSON and GraphQL. Empty URLs are
+// dropped; empty links remove the key.
+//
+// Parameters:
+//   - links: The links to store
+func (rd *ResponseData) SetLinks(links Links) {
+	if *rd == nil {
+		*rd = make(ResponseData)
+	}
+
+	stored := make(map[string]string, len(links))
+
+	for rel, href := range links {
+		if href != "" {
+			stored[rel] = href
+		}
+	}
+
+	if len(stored) == 0 {
+		delete(*rd, ResponseLinksKey)
+		return
+	}
+
+	(*rd)[ResponseLinksKey] = stored
+}
+
+// Links returns the links stored with SetLinks, or nil if there are none.
+// It also reads links from decoded JSON and GraphQL input.
+//
+// Returns:
+//   - Links: The stored links
+func (rd ResponseData) Links() Links {
+	switch v := rd[ResponseLinksKey].(type) {
+	case map[string]string:
+		return Links(v)
+	case Links:
+		return v
+	case map[string]any:
+		links := make(Links, len(v))
+
+		for rel, href := range v {
+			if s, ok := href.(string); ok {
+				links[rel] = s
+			}
+		}
+
+		return links
+	default:
+		return nil
+	}
+}
+
+// LinkBuilder builds self and pagination links for HTTP responses.
+type LinkBuilder struct {
+	// BaseURL is the public URL of the API, e.g. "https://api.kopexa.com".
+	// It replaces the scheme and host of the request and is prefixed to its
+	// path. If empty, the scheme and host of the request are used;
+	// X-Forwarded-* headers are not trusted.
+	BaseURL string
+	// CursorParam is the query parameter for pagination cursors. Defaults
+	// to DefaultCursorParam.
+	CursorParam string
+}
+
+// Self returns the absolute URL of the request, including its query.
+//
+// Parameters:
+//   - r: The current request
+//
+// Returns:
+//   - string: The absolute URL
+func (b LinkBuilder) Self(r *http.Request) string {
+	return b.requestURL(r).String()
+}
+
+// Page returns the URL of the request with its cursor replaced by cursor.
+// Other query parameters, such as filters and page size, are kept.
+//
+// Parameters:
+//   - r: The current request
+//   - cursor: The cursor of the page
+//
+// Returns:
+//   - string: The absolute URL of the page
+func (b LinkBuilder) Page(r *http.Request, cursor string) string {
+	u := b.requestURL(r)
+
+	q := u.Query()
+	q.Set(b.cursorParam(), cursor)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// Links returns the LinkSelf link of the request and, for non-empty
+// cursors, the LinkNext and LinkPrev links.
+//
+// Example:
+//
+//	links := types.LinkBuilder{BaseURL: "https://api.kopexa.com"}.Links(r, page.NextCursor, page.PrevCursor)
+//	data.SetLinks(links)
+//
+// Parameters:
+//   - r: The current request
+//   - next: The cursor of the next page, empty on the last page
+//   - prev: The cursor of the previous page, empty on the first page
+//
+// Returns:
+//   - Links: The links
+func (b LinkBuilder) Links(r *http.Request, next, prev string) Links {
+	links := Links{LinkSelf: b.Self(r)}
+
+	if next != "" {
+		links[LinkNext] = b.Page(r, next)
+	}
+
+	if prev != "" {
+		links[LinkPrev] = b.Page(r, prev)
+	}
+
+	return links
+}
+
+// cursorParam returns the configured or default cursor parameter.
+func (b LinkBuilder) cursorParam() string {
+	if b.CursorParam != "" {
+		return b.CursorParam
+	}
+
+	return DefaultCursorParam
+}
+
+// requestURL returns the absolute URL of r.
+func (b LinkBuilder) requestURL(r *http.Request) *url.URL {
+	u := &url.URL{
+		Scheme:   "http",
+		Host:     r.Host,
+		Path:     r.URL.Path,
+		RawPath:  r.URL.RawPath,
+		RawQuery: r.URL.RawQuery,
+	}
+
+	if r.TLS != nil {
+		u.Scheme = "https"
+	}
+
+	if b.BaseURL == "" {
+		return u
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(b.BaseURL, "/"))
+	if err != nil {
+		log.Error().Err(err).Str("base_url", b.BaseURL).Msg("invalid link base URL")
+		return u
+	}
+
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	u.Path = base.Path + u.Path
+	u.RawPath = ""
+
+	return u
+}
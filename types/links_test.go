@@ -0,0 +1,117 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkBuilder_Links(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  LinkBuilder
+		target   string
+		tls      bool
+		next     string
+		prev     string
+		expected Links
+	}{
+		{
+			name:     "self only",
+			target:   "http://api.local/v1/controls?limit=10",
+			expected: Links{LinkSelf: "http://api.local/v1/controls?limit=10"},
+		},
+		{
+			name:   "next and prev replace cursor",
+			target: "http://api.local/v1/controls?limit=10&cursor=abc",
+			tls:    true,
+			next:   "def",
+			prev:   "xyz",
+			expected: Links{
+				LinkSelf: "https://api.local/v1/controls?limit=10&cursor=abc",
+				LinkNext: "https://api.local/v1/controls?cursor=def&limit=10",
+				LinkPrev: "https://api.local/v1/controls?cursor=xyz&limit=10",
+			},
+		},
+		{
+			name:    "base url and custom param",
+			builder: LinkBuilder{BaseURL: "https://api.kopexa.com/api/", CursorParam: "after"},
+			target:  "http://internal:8080/v1/risks",
+			next:    "c1",
+			expected: Links{
+				LinkSelf: "https://api.kopexa.com/api/v1/risks",
+				LinkNext: "https://api.kopexa.com/api/v1/risks?after=c1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.target, nil)
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+
+			links := tt.builder.Links(r, tt.next, tt.prev)
+			assert.Equal(t, tt.expected, links)
+			assert.Equal(t, tt.expected[LinkSelf], links.Self())
+			assert.Equal(t, tt.expected[LinkNext], links.Next())
+			assert.Equal(t, tt.expected[LinkPrev], links.Prev())
+		})
+	}
+}
+
+func TestResponseData_Links(t *testing.T) {
+	var rd ResponseData
+
+	assert.Nil(t, rd.Links())
+
+	rd.SetLinks(Links{LinkSelf: "https://api.kopexa.com/v1/a", LinkPrev: "", LinkRelated: "https://api.kopexa.com/v1/b"})
+	expected := Links{LinkSelf: "https://api.kopexa.com/v1/a", LinkRelated: "https://api.kopexa.com/v1/b"}
+	assert.Equal(t, expected, rd.Links())
+
+	t.Run("json round trip", func(t *testing.T) {
+		data, err := json.Marshal(rd)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"_links":{"self":"https://api.kopexa.com/v1/a","related":"https://api.kopexa.com/v1/b"}}`, string(data))
+
+		var decoded ResponseData
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, expected, decoded.Links())
+	})
+
+	t.Run("gql round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		rd.MarshalGQL(&buf)
+
+		var input map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &input))
+
+		var decoded ResponseData
+		require.NoError(t, decoded.UnmarshalGQL(input))
+		assert.Equal(t, expected, decoded.Links())
+	})
+
+	rd.SetLinks(nil)
+	assert.NotContains(t, rd, ResponseLinksKey)
+}
+
+func TestLinks_GQL(t *testing.T) {
+	links := Links{LinkSelf: "https://api.kopexa.com/v1/a"}
+
+	var buf bytes.Buffer
+	links.MarshalGQL(&buf)
+	assert.JSONEq(t, `{"self":"https://api.kopexa.com/v1/a"}`, buf.String())
+
+	var decoded Links
+	require.NoError(t, decoded.UnmarshalGQL(map[string]any{"self": "https://api.kopexa.com/v1/a"}))
+	assert.Equal(t, links, decoded)
+}
@@ -14,7 +14,6 @@ import (
 	"io"
 
 	"github.com/goccy/go-yaml"
-	"github.com/rs/zerolog/log"
 )
 
 var (
@@ -81,9 +80,7 @@ func (l LocalizedText) String() string {
 // Parameters:
 //   - w: The writer to write the serialized data to
 func (l LocalizedText) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, l); err != nil {
-		log.Error().Err(err).Msg("failed to marshal localized text to GraphQL")
-	}
+	MarshalGQL(w, l)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface.
@@ -97,7 +94,7 @@ func (l LocalizedText) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (l *LocalizedText) UnmarshalGQL(v interface{}) error {
-	return unmarshalGQLJSON(v, l)
+	return UnmarshalGQL(v, l)
 }
 
 func ToString(slice []LocalizedText, locale ...string) string {
@@ -367,9 +364,7 @@ func (l LocalizedTextSlice) Equal(other LocalizedTextSlice) bool {
 // Parameters:
 //   - w: The writer to write the serialized data to
 func (l LocalizedTextSlice) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, l); err != nil {
-		log.Error().Err(err).Msg("failed to marshal LocalizedTextSlice to GraphQL")
-	}
+	MarshalGQL(w, l)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface for LocalizedTextSlice.
@@ -383,5 +378,5 @@ func (l LocalizedTextSlice) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (l *LocalizedTextSlice) UnmarshalGQL(v interface{}) error {
-	return unmarshalGQLJSON(v, l)
+	return UnmarshalGQL(v, l)
 }
@@ -8,8 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-
-	"github.com/rs/zerolog/log"
 )
 
 // ErrKeyNotFound is returned when a key is not found in the metadata
@@ -53,9 +51,7 @@ func (m Metadata) Get(key string) (string, error) {
 // Parameters:
 //   - w: The writer to write the Metadata to
 func (m Metadata) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, m); err != nil {
-		log.Error().Err(err).Msg("failed to marshal metadata to GraphQL")
-	}
+	MarshalGQL(w, m)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface for Metadata.
@@ -67,7 +63,7 @@ func (m Metadata) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (m *Metadata) UnmarshalGQL(v interface{}) error {
-	return unmarshalGQLJSON(v, m)
+	return UnmarshalGQL(v, m)
 }
 
 // MarshalCSV implements the csvutil.Marshaler interface for Metadata.
@@ -0,0 +1,460 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrPercentageOutOfRange is returned when a percentage is not within 0 and 100
+	ErrPercentageOutOfRange = errors.New("percentage must be between 0 and 100")
+	// ErrRatioOutOfRange is returned when a ratio is not within 0 and 1
+	ErrRatioOutOfRange = errors.New("ratio must be between 0 and 1")
+	// ErrInvalidNumber is returned when a value cannot be interpreted as a number
+	ErrInvalidNumber = errors.New("invalid number")
+)
+
+// RoundingMode determines how Round treats values that lie between two
+// representable results.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value, ties away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, ties to the even neighbour.
+	RoundHalfEven
+	// RoundDown truncates towards zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+)
+
+// Percentage is a value between 0 and 100, e.g. a compliance score.
+//
+// Use NewPercentage to create a validated value. Decoding from JSON, YAML,
+// GraphQL or SQL validates the bounds as well.
+type Percentage float64
+
+// Ratio is a value between 0 and 1, e.g. a coverage or completion rate.
+//
+// Use NewRatio to create a validated value. Decoding from JSON, YAML,
+// GraphQL or SQL validates the bounds as well.
+type Ratio float64
+
+// NewPercentage creates a Percentage and validates that it is within 0 and 100.
+//
+// Parameters:
+//   - v: The percentage value
+//
+// Returns:
+//   - Percentage: The validated percentage
+//   - error: ErrPercentageOutOfRange if v is outside the bounds or not a number
+func NewPercentage(v float64) (Percentage, error) {
+	p := Percentage(v)
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+
+	return p, nil
+}
+
+// MustPercentage is like NewPercentage but panics on error.
+func MustPercentage(v float64) Percentage {
+	p, err := NewPercentage(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// PercentageOf returns part as a percentage of total, clamped to 0–100.
+// A zero total yields 0.
+func PercentageOf(part, total float64) Percentage {
+	if total == 0 {
+		return 0
+	}
+
+	return clampPercentage(part / total * 100)
+}
+
+// Validate checks that the percentage is within 0 and 100.
+func (p Percentage) Validate() error {
+	if !inRange(float64(p), 100) {
+		return fmt.Errorf("%w: %v", ErrPercentageOutOfRange, float64(p))
+	}
+
+	return nil
+}
+
+// Float64 returns the percentage as float64.
+func (p Percentage) Float64() float64 {
+	return float64(p)
+}
+
+// Ratio converts the percentage to a Ratio (e.g. 50 -> 0.5).
+func (p Percentage) Ratio() Ratio {
+	return Ratio(float64(p) / 100)
+}
+
+// Of applies the percentage to the given value (e.g. 20% of 50 is 10).
+func (p Percentage) Of(v float64) float64 {
+	return v * float64(p) / 100
+}
+
+// Add returns the sum of both percentages, capped at 100.
+func (p Percentage) Add(o Percentage) Percentage {
+	return clampPercentage(float64(p) + float64(o))
+}
+
+// Sub returns the difference of both percentages, floored at 0.
+func (p Percentage) Sub(o Percentage) Percentage {
+	return clampPercentage(float64(p) - float64(o))
+}
+
+// Mul scales the percentage by factor, clamped to 0–100.
+func (p Percentage) Mul(factor float64) Percentage {
+	return clampPercentage(float64(p) * factor)
+}
+
+// Round rounds the percentage to the given number of decimal places.
+func (p Percentage) Round(places int, mode RoundingMode) Percentage {
+	return clampPercentage(round(float64(p), places, mode))
+}
+
+// String returns the percentage formatted with a percent sign, e.g. "42.5%".
+func (p Percentage) String() string {
+	return strconv.FormatFloat(float64(p), 'f', -1, 64) + "%"
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The percentage is written as a plain number.
+func (p Percentage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(p))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *Percentage) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	return p.set(v)
+}
+
+// MarshalYAML implements the yaml.InterfaceMarshaler interface.
+func (p Percentage) MarshalYAML() (any, error) {
+	return float64(p), nil
+}
+
+// UnmarshalYAML implements the yaml.InterfaceUnmarshaler interface.
+func (p *Percentage) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	return p.set(v)
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for Percentage.
+func (p Percentage) MarshalGQL(w io.Writer) {
+	_, _ = io.WriteString(w, strconv.FormatFloat(float64(p), 'f', -1, 64))
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for Percentage.
+func (p *Percentage) UnmarshalGQL(v any) error {
+	return p.set(v)
+}
+
+// Scan implements the sql.Scanner interface for Percentage.
+func (p *Percentage) Scan(value any) error {
+	if value == nil {
+		*p = 0
+		return nil
+	}
+
+	return p.set(value)
+}
+
+// Value implements the driver.Valuer interface for Percentage.
+func (p Percentage) Value() (driver.Value, error) {
+	return float64(p), nil
+}
+
+// set parses and validates a decoded scalar.
+func (p *Percentage) set(v any) error {
+	f, err := toFloat(v)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := NewPercentage(f)
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+
+	return nil
+}
+
+// NewRatio creates a Ratio and validates that it is within 0 and 1.
+//
+// Parameters:
+//   - v: The ratio value
+//
+// Returns:
+//   - Ratio: The validated ratio
+//   - error: ErrRatioOutOfRange if v is outside the bounds or not a number
+func NewRatio(v float64) (Ratio, error) {
+	r := Ratio(v)
+	if err := r.Validate(); err != nil {
+		return 0, err
+	}
+
+	return r, nil
+}
+
+// MustRatio is like NewRatio but panics on error.
+func MustRatio(v float64) Ratio {
+	r, err := NewRatio(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// RatioOf returns part divided by total, clamped to 0–1.
+// A zero total yields 0.
+func RatioOf(part, total float64) Ratio {
+	if total == 0 {
+		return 0
+	}
+
+	return clampRatio(part / total)
+}
+
+// Validate checks that the ratio is within 0 and 1.
+func (r Ratio) Validate() error {
+	if !inRange(float64(r), 1) {
+		return fmt.Errorf("%w: %v", ErrRatioOutOfRange, float64(r))
+	}
+
+	return nil
+}
+
+// Float64 returns the ratio as float64.
+func (r Ratio) Float64() float64 {
+	return float64(r)
+}
+
+// Percentage converts the ratio to a Percentage (e.g. 0.5 -> 50).
+func (r Ratio) Percentage() Percentage {
+	return Percentage(float64(r) * 100)
+}
+
+// Of applies the ratio to the given value (e.g. 0.2 of 50 is 10).
+func (r Ratio) Of(v float64) float64 {
+	return v * float64(r)
+}
+
+// Add returns the sum of both ratios, capped at 1.
+func (r Ratio) Add(o Ratio) Ratio {
+	return clampRatio(float64(r) + float64(o))
+}
+
+// Sub returns the difference of both ratios, floored at 0.
+func (r Ratio) Sub(o Ratio) Ratio {
+	return clampRatio(float64(r) - float64(o))
+}
+
+// Mul scales the ratio by factor, clamped to 0–1.
+func (r Ratio) Mul(factor float64) Ratio {
+	return clampRatio(float64(r) * factor)
+}
+
+// Complement returns 1 - r.
+func (r Ratio) Complement() Ratio {
+	return clampRatio(1 - float64(r))
+}
+
+// Round rounds the ratio to the given number of decimal places.
+func (r Ratio) Round(places int, mode RoundingMode) Ratio {
+	return clampRatio(round(float64(r), places, mode))
+}
+
+// String returns the ratio as a plain number, e.g. "0.425".
+func (r Ratio) String() string {
+	return strconv.FormatFloat(float64(r), 'f', -1, 64)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r Ratio) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(r))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *Ratio) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	return r.set(v)
+}
+
+// MarshalYAML implements the yaml.InterfaceMarshaler interface.
+func (r Ratio) MarshalYAML() (any, error) {
+	return float64(r), nil
+}
+
+// UnmarshalYAML implements the yaml.InterfaceUnmarshaler interface.
+func (r *Ratio) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	return r.set(v)
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for Ratio.
+func (r Ratio) MarshalGQL(w io.Writer) {
+	_, _ = io.WriteString(w, r.String())
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for Ratio.
+func (r *Ratio) UnmarshalGQL(v any) error {
+	return r.set(v)
+}
+
+// Scan implements the sql.Scanner interface for Ratio.
+func (r *Ratio) Scan(value any) error {
+	if value == nil {
+		*r = 0
+		return nil
+	}
+
+	return r.set(value)
+}
+
+// Value implements the driver.Valuer interface for Ratio.
+func (r Ratio) Value() (driver.Value, error) {
+	return float64(r), nil
+}
+
+// set parses and validates a decoded scalar.
+func (r *Ratio) set(v any) error {
+	f, err := toFloat(v)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := NewRatio(f)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+
+	return nil
+}
+
+// inRange reports whether v is a finite number within [0, upper].
+func inRange(v, upper float64) bool {
+	return !math.IsNaN(v) && v >= 0 && v <= upper
+}
+
+func clampPercentage(v float64) Percentage {
+	return Percentage(clamp(v, 100))
+}
+
+func clampRatio(v float64) Ratio {
+	return Ratio(clamp(v, 1))
+}
+
+// clamp limits v to [0, upper]; NaN becomes 0.
+func clamp(v, upper float64) float64 {
+	switch {
+	case math.IsNaN(v), v < 0:
+		return 0
+	case v > upper:
+		return upper
+	default:
+		return v
+	}
+}
+
+// round rounds v to the given number of decimal places using mode.
+func round(v float64, places int, mode RoundingMode) float64 {
+	if places < 0 {
+		places = 0
+	}
+
+	scale := math.Pow(10, float64(places))
+	scaled := v * scale
+
+	// Remove floating point noise such as 0.285*100 = 28.499999999999996
+	// before deciding on ties.
+	scaled = math.Round(scaled*1e6) / 1e6
+
+	switch mode {
+	case RoundHalfEven:
+		scaled = math.RoundToEven(scaled)
+	case RoundDown:
+		scaled = math.Trunc(scaled)
+	case RoundUp:
+		if scaled < 0 {
+			scaled = math.Floor(scaled)
+		} else {
+			scaled = math.Ceil(scaled)
+		}
+	default:
+		scaled = math.Round(scaled)
+	}
+
+	return scaled / scale
+}
+
+// toFloat converts a decoded scalar into a float64.
+func toFloat(v any) (float64, error) {
+	switch val := v.(type) {
+	case float64:
+		return val, nil
+	case float32:
+		return float64(val), nil
+	case int:
+		return float64(val), nil
+	case int32:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
+	case uint64:
+		return float64(val), nil
+	case json.Number:
+		return val.Float64()
+	case []byte:
+		return toFloat(string(val))
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(val), "%"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, val)
+		}
+
+		return f, nil
+	default:
+		return 0, fmt.Errorf("%w: unsupported type %T", ErrInvalidNumber, v)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPercentage(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   float64
+		wantErr bool
+	}{
+		{name: "zero", input: 0},
+		{name: "hundred", input: 100},
+		{name: "fraction", input: 42.5},
+		{name: "negative", input: -0.1, wantErr: true},
+		{name: "above hundred", input: 100.01, wantErr: true},
+		{name: "nan", input: math.NaN(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPercentage(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrPercentageOutOfRange)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.input, p.Float64(), 1e-9)
+		})
+	}
+}
+
+func TestNewRatio(t *testing.T) {
+	_, err := NewRatio(0.5)
+	assert.NoError(t, err)
+
+	_, err = NewRatio(1.5)
+	assert.ErrorIs(t, err, ErrRatioOutOfRange)
+
+	_, err = NewRatio(-1)
+	assert.ErrorIs(t, err, ErrRatioOutOfRange)
+}
+
+func TestPercentage_Arithmetic(t *testing.T) {
+	p := MustPercentage(60)
+
+	assert.Equal(t, Percentage(100), p.Add(MustPercentage(50)))
+	assert.Equal(t, Percentage(0), p.Sub(MustPercentage(70)))
+	assert.Equal(t, Percentage(30), p.Mul(0.5))
+	assert.Equal(t, Percentage(100), p.Mul(2))
+	assert.InDelta(t, 30.0, p.Of(50), 1e-9)
+	assert.Equal(t, Ratio(0.6), p.Ratio())
+	assert.Equal(t, "60%", p.String())
+
+	assert.Equal(t, Percentage(25), PercentageOf(1, 4))
+	assert.Equal(t, Percentage(100), PercentageOf(5, 4))
+	assert.Equal(t, Percentage(0), PercentageOf(1, 0))
+}
+
+func TestRatio_Arithmetic(t *testing.T) {
+	r := MustRatio(0.75)
+
+	assert.Equal(t, Ratio(1), r.Add(MustRatio(0.5)))
+	assert.Equal(t, Ratio(0), r.Sub(MustRatio(1)))
+	assert.Equal(t, Ratio(0.25), r.Complement())
+	assert.InDelta(t, 7.5, r.Of(10), 1e-9)
+	assert.Equal(t, Percentage(75), r.Percentage())
+	assert.Equal(t, Ratio(0.5), RatioOf(2, 4))
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  float64
+		places int
+		mode   RoundingMode
+		want   float64
+	}{
+		{name: "half up", input: 28.5, places: 0, mode: RoundHalfUp, want: 29},
+		{name: "half even down", input: 28.5, places: 0, mode: RoundHalfEven, want: 28},
+		{name: "half even up", input: 29.5, places: 0, mode: RoundHalfEven, want: 30},
+		{name: "down", input: 28.99, places: 1, mode: RoundDown, want: 28.9},
+		{name: "up", input: 28.01, places: 1, mode: RoundUp, want: 28.1},
+		{name: "float noise", input: 0.285 * 100, places: 0, mode: RoundHalfUp, want: 29},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Percentage(tt.input).Round(tt.places, tt.mode)
+			assert.InDelta(t, tt.want, got.Float64(), 1e-9)
+		})
+	}
+
+	assert.InDelta(t, 0.33, MustRatio(1.0/3).Round(2, RoundHalfUp).Float64(), 1e-9)
+}
+
+func TestPercentage_JSON(t *testing.T) {
+	type score struct {
+		Value Percentage `json:"value"`
+	}
+
+	var s score
+	require.NoError(t, json.Unmarshal([]byte(`{"value":87.5}`), &s))
+	assert.Equal(t, Percentage(87.5), s.Value)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"value":120}`), &s), ErrPercentageOutOfRange)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"value":true}`), &s), ErrInvalidNumber)
+
+	out, err := json.Marshal(score{Value: 50})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value":50}`, string(out))
+}
+
+func TestRatio_YAML(t *testing.T) {
+	type config struct {
+		Threshold Ratio `yaml:"threshold"`
+	}
+
+	var c config
+	require.NoError(t, yaml.Unmarshal([]byte("threshold: 0.8\n"), &c))
+	assert.Equal(t, Ratio(0.8), c.Threshold)
+
+	assert.Error(t, yaml.Unmarshal([]byte("threshold: 2\n"), &c))
+
+	out, err := yaml.Marshal(config{Threshold: 0.25})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "threshold: 0.25")
+}
+
+func TestPercentage_GQL(t *testing.T) {
+	var buf bytes.Buffer
+
+	Percentage(12.5).MarshalGQL(&buf)
+	assert.Equal(t, "12.5", buf.String())
+
+	var p Percentage
+	require.NoError(t, p.UnmarshalGQL(int64(42)))
+	assert.Equal(t, Percentage(42), p)
+
+	require.NoError(t, p.UnmarshalGQL("12.5%"))
+	assert.Equal(t, Percentage(12.5), p)
+
+	assert.Error(t, p.UnmarshalGQL(json.Number("101")))
+}
+
+func TestRatio_SQL(t *testing.T) {
+	var r Ratio
+
+	require.NoError(t, r.Scan(0.5))
+	assert.Equal(t, Ratio(0.5), r)
+
+	require.NoError(t, r.Scan([]byte("0.25")))
+	assert.Equal(t, Ratio(0.25), r)
+
+	require.NoError(t, r.Scan(nil))
+	assert.Equal(t, Ratio(0), r)
+
+	assert.Error(t, r.Scan("abc"))
+	assert.Error(t, r.Scan(1.1))
+
+	v, err := Ratio(0.3).Value()
+	require.NoError(t, err)
+	assert.Equal(t, 0.3, v)
+}
@@ -6,8 +6,6 @@ package types
 import (
 	"fmt"
 	"io"
-
-	"github.com/rs/zerolog/log"
 )
 
 // Price represents a monetary value with associated metadata.
@@ -57,12 +55,7 @@ func (p Price) String() string {
 //
 // The method logs any errors that occur during marshaling.
 func (p Price) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, p); err != nil {
-		log.Error().
-			Err(err).
-			Interface("price", p).
-			Msg("failed to marshal price to GraphQL")
-	}
+	MarshalGQL(w, p)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface for GraphQL deserialization.
@@ -74,5 +67,5 @@ func (p Price) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If the unmarshaling fails
 func (p *Price) UnmarshalGQL(v interface{}) error {
-	return unmarshalGQLJSON(v, p)
+	return UnmarshalGQL(v, p)
 }
@@ -7,8 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-
-	"github.com/rs/zerolog/log"
 )
 
 // ErrInvalidReference is returned when a reference is invalid
@@ -49,11 +47,7 @@ func (r *Reference) Validate() error {
 // Returns:
 //   - error: If unmarshaling fails
 func (r *Reference) UnmarshalGQL(v interface{}) error {
-	if err := unmarshalGQLJSON(v, r); err != nil {
-		return fmt.Errorf("failed to unmarshal reference: %w", err)
-	}
-
-	return r.Validate()
+	return UnmarshalValidGQL(v, r)
 }
 
 // MarshalGQL implements the graphql.Marshaler interface for Reference.
@@ -62,7 +56,5 @@ func (r *Reference) UnmarshalGQL(v interface{}) error {
 // Parameters:
 //   - w: The writer to write the Reference to
 func (r Reference) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, r); err != nil {
-		log.Error().Err(err).Msg("failed to marshal reference to GraphQL")
-	}
+	MarshalGQL(w, r)
 }
@@ -7,8 +7,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-
-	"github.com/rs/zerolog/log"
 )
 
 // ErrInvalidResponseData is returned when the response data is invalid
@@ -130,9 +128,7 @@ func (rd *ResponseData) UnmarshalJSON(data []byte) error {
 // Parameters:
 //   - w: The writer to write the ResponseData to
 func (rd ResponseData) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, rd); err != nil {
-		log.Error().Err(err).Msg("failed to marshal response data to GraphQL")
-	}
+	MarshalGQL(w, rd)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface for ResponseData.
@@ -144,7 +140,7 @@ func (rd ResponseData) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (rd *ResponseData) UnmarshalGQL(v interface{}) error {
-	return unmarshalGQLJSON(v, rd)
+	return UnmarshalGQL(v, rd)
 }
 
 // MarshalGQL implements the graphql.Marshaler interface for ResponseMeta.
@@ -153,9 +149,7 @@ func (rd *ResponseData) UnmarshalGQL(v interface{}) error {
 // Parameters:
 //   - w: The writer to write the ResponseMeta to
 func (rm ResponseMeta) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, rm); err != nil {
-		log.Error().Err(err).Msg("failed to marshal response meta to GraphQL")
-	}
+	MarshalGQL(w, rm)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface for ResponseMeta.
@@ -167,5 +161,5 @@ func (rm ResponseMeta) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (rm *ResponseMeta) UnmarshalGQL(v interface{}) error {
-	return unmarshalGQLJSON(v, rm)
+	return UnmarshalGQL(v, rm)
 }
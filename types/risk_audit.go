@@ -6,8 +6,6 @@ package types
 import (
 	"fmt"
 	"io"
-
-	"github.com/rs/zerolog/log"
 )
 
 // RiskAudit represents a comprehensive risk assessment result containing score components,
@@ -39,11 +37,7 @@ func (r RiskAudit) String() string {
 // Returns:
 //   - error: An error if unmarshaling fails
 func (r *RiskAudit) UnmarshalGQL(v interface{}) error {
-	if err := unmarshalGQLJSON(v, r); err != nil {
-		return fmt.Errorf("failed to unmarshal reference: %w", err)
-	}
-
-	return nil
+	return UnmarshalGQL(v, r)
 }
 
 // MarshalGQL implements the graphql.Marshaler interface for RiskAudit.
@@ -66,7 +60,5 @@ func (r RiskAudit) MarshalGQL(w io.Writer) {
 		rCopy.UIHints = []string{}
 	}
 
-	if err := marshalGQLJSON(w, rCopy); err != nil {
-		log.Error().Err(err).Msg("failed to marshal reference to GraphQL")
-	}
+	MarshalGQL(w, rCopy)
 }
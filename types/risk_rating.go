@@ -7,8 +7,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-
-	"github.com/rs/zerolog/log"
 )
 
 const (
@@ -91,9 +89,7 @@ func (r *RiskRating) String() string {
 // Parameters:
 //   - w: The writer to write the RiskRating to
 func (r RiskRating) MarshalGQL(w io.Writer) {
-	if err := marshalGQLJSON(w, r); err != nil {
-		log.Error().Err(err).Msg("failed to marshal risk rating to GraphQL")
-	}
+	MarshalGQL(w, r)
 }
 
 // UnmarshalGQL implements the graphql.Unmarshaler interface for RiskRating.
@@ -105,7 +101,7 @@ func (r RiskRating) MarshalGQL(w io.Writer) {
 // Returns:
 //   - error: If unmarshaling fails
 func (r *RiskRating) UnmarshalGQL(v interface{}) error {
-	return unmarshalGQLJSON(v, r)
+	return UnmarshalGQL(v, r)
 }
 
 // MarshalCSV implements the csvutil.Marshaler interface for RiskRating.
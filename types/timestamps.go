@@ -0,0 +1,180 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"database/sql/driver"
+	"io"
+	"time"
+)
+
+// Timestamps bundles the creation, modification and soft-deletion times of
+// an entity. Entities embed it so that all of them behave the same:
+//
+//	type Control struct {
+//		ID string `json:"id"`
+//		types.Timestamps
+//	}
+//
+//	c.TouchCreated()  // on insert
+//	c.Touch()         // on update
+//	c.MarkDeleted()   // soft delete
+//
+// All times are stored in UTC. Zero times are omitted from JSON, so a record
+// that was never deleted has no "deletedAt". When embedded, the fields are
+// promoted and serialized inline with the entity; MarshalGQL and
+// UnmarshalGQL apply where Timestamps is used as a scalar field.
+type Timestamps struct {
+	// CreatedAt is the time the entity was created.
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+	// UpdatedAt is the time the entity was last modified, including its
+	// deletion and restoration.
+	UpdatedAt time.Time `json:"updatedAt,omitzero"`
+	// DeletedAt is the time the entity was soft-deleted, or zero.
+	DeletedAt time.Time `json:"deletedAt,omitzero"`
+}
+
+// NewTimestamps returns Timestamps of an entity created now.
+func NewTimestamps() Timestamps {
+	var t Timestamps
+	t.TouchCreated()
+
+	return t
+}
+
+// TouchCreated sets CreatedAt and UpdatedAt to the current time. CreatedAt is
+// kept if it is already set, e.g. for imported records.
+func (t *Timestamps) TouchCreated() {
+	t.TouchCreatedAt(time.Now())
+}
+
+// TouchCreatedAt is TouchCreated with the given time.
+func (t *Timestamps) TouchCreatedAt(now time.Time) {
+	now = now.UTC()
+
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = now
+	}
+
+	t.UpdatedAt = now
+}
+
+// Touch sets UpdatedAt to the current time.
+func (t *Timestamps) Touch() {
+	t.TouchAt(time.Now())
+}
+
+// TouchAt sets UpdatedAt to now.
+func (t *Timestamps) TouchAt(now time.Time) {
+	t.UpdatedAt = now.UTC()
+}
+
+// MarkDeleted soft-deletes the entity at the current time. Deleting an entity
+// that is already deleted keeps the original deletion time.
+func (t *Timestamps) MarkDeleted() {
+	t.MarkDeletedAt(time.Now())
+}
+
+// MarkDeletedAt is MarkDeleted with the given time.
+func (t *Timestamps) MarkDeletedAt(now time.Time) {
+	if t.IsDeleted() {
+		return
+	}
+
+	now = now.UTC()
+	t.DeletedAt = now
+	t.UpdatedAt = now
+}
+
+// Restore reverts a soft delete.
+func (t *Timestamps) Restore() {
+	t.RestoreAt(time.Now())
+}
+
+// RestoreAt is Restore with the given time.
+func (t *Timestamps) RestoreAt(now time.Time) {
+	if !t.IsDeleted() {
+		return
+	}
+
+	t.DeletedAt = time.Time{}
+	t.UpdatedAt = now.UTC()
+}
+
+// IsDeleted reports whether the entity is soft-deleted.
+func (t Timestamps) IsDeleted() bool {
+	return !t.DeletedAt.IsZero()
+}
+
+// TimestampColumns are the column names matching ScanDest and Values.
+var TimestampColumns = []string{"created_at", "updated_at", "deleted_at"}
+
+// ScanDest returns the scan destinations of the created_at, updated_at and
+// deleted_at columns, in this order. A NULL scans into a zero time.
+//
+// Example:
+//
+//	err := row.Scan(append([]any{&c.ID, &c.Name}, c.Timestamps.ScanDest()...)...)
+func (t *Timestamps) ScanDest() []any {
+	return []any{
+		(*nullTime)(&t.CreatedAt),
+		(*nullTime)(&t.UpdatedAt),
+		(*nullTime)(&t.DeletedAt),
+	}
+}
+
+// Values returns the values of the created_at, updated_at and deleted_at
+// columns, in this order. A zero time is stored as NULL.
+//
+// Example:
+//
+//	_, err := db.ExecContext(ctx, "INSERT INTO controls (id, created_at, updated_at, deleted_at) VALUES ($1, $2, $3, $4)",
+//	    append([]any{c.ID}, c.Timestamps.Values()...)...)
+func (t Timestamps) Values() []any {
+	return []any{
+		nullTime(t.CreatedAt),
+		nullTime(t.UpdatedAt),
+		nullTime(t.DeletedAt),
+	}
+}
+
+// MarshalGQL implements the graphql.Marshaler interface for Timestamps.
+func (t Timestamps) MarshalGQL(w io.Writer) {
+	MarshalGQL(w, t)
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface for Timestamps.
+func (t *Timestamps) UnmarshalGQL(v any) error {
+	return UnmarshalGQL(v, t)
+}
+
+// nullTime is a time.Time stored as NULL when zero.
+type nullTime time.Time
+
+// Scan implements the sql.Scanner interface for nullTime.
+func (n *nullTime) Scan(value any) error {
+	if value == nil {
+		*n = nullTime{}
+		return nil
+	}
+
+	t, ok := value.(time.Time)
+	if !ok {
+		return ErrUnsupportedDateTimeType
+	}
+
+	*n = nullTime(t.UTC())
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface for nullTime.
+func (n nullTime) Value() (driver.Value, error) {
+	t := time.Time(n)
+	if t.IsZero() {
+		return nil, nil
+	}
+
+	return t, nil
+}
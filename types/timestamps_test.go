@@ -0,0 +1,127 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamps_Lifecycle(t *testing.T) {
+	created := time.Date(2024, 3, 20, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	updated := created.Add(time.Hour)
+	deleted := created.Add(2 * time.Hour)
+	restored := created.Add(3 * time.Hour)
+
+	var ts Timestamps
+	ts.TouchCreatedAt(created)
+	assert.Equal(t, created.UTC(), ts.CreatedAt)
+	assert.Equal(t, time.UTC, ts.CreatedAt.Location())
+	assert.Equal(t, ts.CreatedAt, ts.UpdatedAt)
+
+	ts.TouchCreatedAt(updated)
+	assert.Equal(t, created.UTC(), ts.CreatedAt, "CreatedAt must be kept")
+
+	ts.TouchAt(updated)
+	assert.Equal(t, updated.UTC(), ts.UpdatedAt)
+	assert.False(t, ts.IsDeleted())
+
+	ts.MarkDeletedAt(deleted)
+	assert.True(t, ts.IsDeleted())
+	assert.Equal(t, deleted.UTC(), ts.DeletedAt)
+	assert.Equal(t, deleted.UTC(), ts.UpdatedAt)
+
+	ts.MarkDeletedAt(restored)
+	assert.Equal(t, deleted.UTC(), ts.DeletedAt, "deleting twice must keep the deletion time")
+
+	ts.RestoreAt(restored)
+	assert.False(t, ts.IsDeleted())
+	assert.Equal(t, restored.UTC(), ts.UpdatedAt)
+}
+
+func TestNewTimestamps(t *testing.T) {
+	before := time.Now()
+	ts := NewTimestamps()
+
+	assert.False(t, ts.CreatedAt.Before(before))
+	assert.Equal(t, ts.CreatedAt, ts.UpdatedAt)
+	assert.False(t, ts.IsDeleted())
+}
+
+func TestTimestamps_JSON(t *testing.T) {
+	type entity struct {
+		ID string `json:"id"`
+		Timestamps
+	}
+
+	e := entity{ID: "c1", Timestamps: Timestamps{
+		CreatedAt: time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 3, 21, 10, 0, 0, 0, time.UTC),
+	}}
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"c1","createdAt":"2024-03-20T10:00:00Z","updatedAt":"2024-03-21T10:00:00Z"}`, string(data))
+
+	var got entity
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, e, got)
+
+	data, err = json.Marshal(entity{ID: "c2"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"c2"}`, string(data))
+}
+
+func TestTimestamps_GQL(t *testing.T) {
+	ts := Timestamps{
+		CreatedAt: time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC),
+		DeletedAt: time.Date(2024, 3, 22, 10, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	ts.MarshalGQL(&buf)
+	assert.JSONEq(t, `{"createdAt":"2024-03-20T10:00:00Z","deletedAt":"2024-03-22T10:00:00Z"}`, buf.String())
+
+	var got Timestamps
+	require.NoError(t, got.UnmarshalGQL(map[string]any{
+		"createdAt": "2024-03-20T10:00:00Z",
+		"deletedAt": "2024-03-22T10:00:00Z",
+	}))
+	assert.Equal(t, ts, got)
+	assert.Error(t, got.UnmarshalGQL(nil))
+}
+
+func TestTimestamps_SQL(t *testing.T) {
+	created := time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC)
+
+	values := Timestamps{CreatedAt: created, UpdatedAt: created}.Values()
+	require.Len(t, values, len(TimestampColumns))
+
+	var got []driver.Value
+	for _, v := range values {
+		dv, err := v.(driver.Valuer).Value()
+		require.NoError(t, err)
+
+		got = append(got, dv)
+	}
+
+	assert.Equal(t, []driver.Value{created, created, nil}, got)
+
+	var ts Timestamps
+	dest := ts.ScanDest()
+	require.Len(t, dest, len(TimestampColumns))
+
+	for i, v := range []any{created.In(time.FixedZone("CET", 3600)), created, nil} {
+		require.NoError(t, dest[i].(interface{ Scan(any) error }).Scan(v))
+	}
+
+	assert.Equal(t, Timestamps{CreatedAt: created, UpdatedAt: created}, ts)
+	assert.ErrorIs(t, dest[0].(interface{ Scan(any) error }).Scan("2024-03-20"), ErrUnsupportedDateTimeType)
+}
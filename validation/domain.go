@@ -21,11 +21,44 @@
 //		log.Printf("Invalid URL: %v", err)
 //	}
 //
+//	// All problems of a URL at once, e.g. for form feedback
+//	if v := validation.ValidateURLAll(input); len(v) > 0 {
+//		return errors.NewValidation(v...)
+//	}
+//
 //	// URL reachability check with timeout
 //	if err := validation.CheckURLReachability("https://api.example.com"); err != nil {
 //		log.Printf("URL not reachable: %v", err)
 //	}
 //
+//	// Reachability check that keeps redirects within an allow-list
+//	result := validation.CheckURLReachabilityWithPolicy(ctx, "https://api.example.com",
+//		validation.RedirectPolicy{MaxRedirects: 3, Validator: allowed, BlockPrivateNetworks: true})
+//
+//	// Validators that follow the environment, e.g. http://localhost in development
+//	profile, _ := validation.ProfileFromEnv("VALIDATION")
+//	webhooks, _ := validation.NewURLValidatorWithProfile(validation.URLOptions{}, profile)
+//
+//	// Health checks for external dependencies
+//	checker := validation.NewDependencyChecker(
+//		&validation.TCPProbe{ProbeName: "postgres", Address: "db:5432"},
+//		&validation.HTTPProbe{ProbeName: "fga", URL: "http://fga:8080/healthz"},
+//	)
+//	report := checker.Check(ctx)
+//
+//	// Bounded GET of a customer endpoint asserting content type and body
+//	result := (&validation.HTTPProbe{URL: "https://status.example.com/health",
+//		Response: &validation.ResponseAssertions{
+//			MaxBodyBytes: 4096,
+//			ContentTypes: []string{"application/json"},
+//			Body:         validation.BodyContains(`"status":"ok"`),
+//		}}).Check(ctx)
+//
+//	// Screening vendor URLs against phishing and malware blocklists
+//	v := validation.NewReputationValidator(urlValidator,
+//		validation.NewCachedReputationChecker(validation.NewSafeBrowsingChecker(cfg), 0, 0),
+//		validation.ReputationOptions{})
+//
 // The package supports both HTTP and HTTPS schemes and includes protection against
 // common security issues such as overly long URLs, invalid domain names, and
 // network timeouts.
@@ -34,8 +67,6 @@ package validation
 import (
 	"context"
 	"fmt"
-	"net"
-	"net/http"
 	"net/url"
 	"regexp"
 	"slices"
@@ -167,7 +198,7 @@ func IsValidURL(inputURL string) error {
 	}
 
 	if len(inputURL) > MaxURLLength {
-		return errors.New(ErrCodeURLTooLong, fmt.Sprintf("URL length %d exceeds maximum allowed length of %d", len(inputURL), MaxURLLength))
+		return errors.New(ErrCodeURLTooLong, urlTooLongMessage(inputURL))
 	}
 
 	// Perform detailed URL validation
@@ -178,6 +209,40 @@ func IsValidURL(inputURL string) error {
 	return nil
 }
 
+// URLField is the field name of the violations returned by ValidateURLAll.
+const URLField = "url"
+
+// ValidateURLAll validates a URL like IsValidURL, but does not stop at the
+// first failure. It returns one field violation per problem, e.g. a URL that
+// is too long, uses an unsupported scheme and has an invalid domain yields
+// three violations with the codes ErrCodeURLTooLong, ErrCodeUnsupportedScheme
+// and ErrCodeInvalidDomain. The violations have the field URLField and are in
+// the order IsValidURL checks them, so the first one matches its error.
+//
+// Returns nil if the URL is valid.
+//
+// Example:
+//
+//	if v := validation.ValidateURLAll(form.Website); len(v) > 0 {
+//		for i := range v {
+//			v[i].Field = "website"
+//		}
+//		return errors.NewValidation(v...)
+//	}
+func ValidateURLAll(inputURL string) []errors.FieldViolation {
+	if inputURL == "" {
+		return []errors.FieldViolation{urlViolation(ErrCodeEmptyURL, "URL cannot be empty")}
+	}
+
+	var violations []errors.FieldViolation
+
+	if len(inputURL) > MaxURLLength {
+		violations = append(violations, urlViolation(ErrCodeURLTooLong, urlTooLongMessage(inputURL)))
+	}
+
+	return append(violations, urlSyntaxViolations(inputURL)...)
+}
+
 // validateURLSyntax performs detailed URL syntax validation.
 //
 // This internal function handles the core URL parsing and validation logic,
@@ -185,36 +250,58 @@ func IsValidURL(inputURL string) error {
 // It is separated from the public interface to allow for better testing
 // and code organization.
 func validateURLSyntax(inputURL string) error {
+	violations := urlSyntaxViolations(inputURL)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return errors.New(errors.ErrorCode(violations[0].Code), violations[0].Message)
+}
+
+// urlSyntaxViolations returns all syntax violations of inputURL. A URL that
+// cannot be parsed yields a single violation, as there is nothing left to
+// check; otherwise the host, scheme and domain are checked independently.
+func urlSyntaxViolations(inputURL string) []errors.FieldViolation {
 	// Parse the URL to validate its structure
 	parsedURL, err := url.Parse(inputURL)
 	if err != nil {
-		return errors.New(ErrCodeInvalidURL, fmt.Sprintf("URL parsing failed: %v", err))
+		return []errors.FieldViolation{urlViolation(ErrCodeInvalidURL, fmt.Sprintf("URL parsing failed: %v", err))}
 	}
 
 	// Handle URLs without scheme by adding default HTTP scheme
 	if parsedURL.Scheme == "" {
 		parsedURL, err = url.Parse("http://" + inputURL)
 		if err != nil {
-			return errors.New(ErrCodeInvalidURL, fmt.Sprintf("URL parsing with default scheme failed: %v", err))
+			return []errors.FieldViolation{urlViolation(ErrCodeInvalidURL, fmt.Sprintf("URL parsing with default scheme failed: %v", err))}
 		}
 	}
 
+	var violations []errors.FieldViolation
+
 	// Validate that the host is present
 	if parsedURL.Host == "" {
-		return errors.New(ErrCodeInvalidURL, "URL must contain a valid host")
+		violations = append(violations, urlViolation(ErrCodeInvalidURL, "URL must contain a valid host"))
 	}
 
 	// Validate the URL scheme
 	if !slices.Contains(supportedSchemes, parsedURL.Scheme) {
-		return errors.New(ErrCodeUnsupportedScheme, fmt.Sprintf("Unsupported URL scheme '%s'. Only %v are supported", parsedURL.Scheme, supportedSchemes))
+		violations = append(violations, urlViolation(ErrCodeUnsupportedScheme, fmt.Sprintf("Unsupported URL scheme '%s'. Only %v are supported", parsedURL.Scheme, supportedSchemes)))
 	}
 
 	// Validate the domain name format
-	if !isValidDomain(parsedURL.Host) {
-		return errors.New(ErrCodeInvalidDomain, fmt.Sprintf("Invalid domain name '%s'", parsedURL.Host))
+	if parsedURL.Host != "" && !isValidDomain(parsedURL.Host) {
+		violations = append(violations, urlViolation(ErrCodeInvalidDomain, fmt.Sprintf("Invalid domain name '%s'", parsedURL.Host)))
 	}
 
-	return nil
+	return violations
+}
+
+func urlViolation(code, message string) errors.FieldViolation {
+	return errors.FieldViolation{Field: URLField, Code: code, Message: message}
+}
+
+func urlTooLongMessage(inputURL string) string {
+	return fmt.Sprintf("URL length %d exceeds maximum allowed length of %d", len(inputURL), MaxURLLength)
 }
 
 // isValidDomain validates a domain name using regex pattern matching.
@@ -295,21 +382,9 @@ func CheckURLReachability(rawURL string) error {
 // which is a prerequisite for any network communication. DNS resolution failures
 // typically indicate either network connectivity issues or non-existent domains.
 func validateDNSResolution(hostname string) error {
-	// Perform DNS lookup with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultHTTPTimeout)
-	defer cancel()
-
-	// Use a custom resolver with timeout
-	resolver := &net.Resolver{
-		PreferGo: true,
-	}
-
-	_, err := resolver.LookupHost(ctx, hostname)
-	if err != nil {
-		return errors.New(ErrCodeHostNotFound, fmt.Sprintf("DNS resolution failed for '%s': %v", hostname, err))
-	}
+	probe := &DNSProbe{Host: hostname}
 
-	return nil
+	return probe.Check(context.Background()).Err
 }
 
 // validateHTTPReachability performs an HTTP HEAD request to verify endpoint accessibility.
@@ -322,50 +397,7 @@ func validateDNSResolution(hostname string) error {
 // HEAD requests are used instead of GET requests to minimize bandwidth usage
 // while still verifying that the service is operational.
 func validateHTTPReachability(rawURL string) error {
-	// Create context with timeout for the HTTP request
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultHTTPTimeout)
-	defer cancel()
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, rawURL, http.NoBody)
-	if err != nil {
-		return errors.New(ErrCodeRequestCreationFailed, fmt.Sprintf("Failed to create HTTP request: %v", err))
-	}
-
-	// Set user agent for request identification
-	req.Header.Set("User-Agent", DefaultUserAgent)
-
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: DefaultHTTPTimeout,
-		Transport: &http.Transport{
-			// Disable keep-alive to ensure fresh connections
-			DisableKeepAlives: true,
-			// Set reasonable timeouts for connection establishment
-			DialContext: (&net.Dialer{
-				Timeout:   DialTimeout,
-				KeepAlive: DialKeepAlive,
-			}).DialContext,
-			// Set timeouts for TLS handshake
-			TLSHandshakeTimeout: TLSHandshakeTimeout,
-			// Set timeouts for response headers
-			ResponseHeaderTimeout: ResponseHeaderTimeout,
-			// Set timeouts for idle connections
-			IdleConnTimeout: IdleConnTimeout,
-		},
-	}
+	probe := &HTTPProbe{URL: rawURL}
 
-	// Execute the HTTP request
-	resp, err := client.Do(req)
-	if err != nil {
-		return errors.New(ErrCodeHTTPRequestFailed, fmt.Sprintf("HTTP request failed: %v", err))
-	}
-	defer resp.Body.Close()
-
-	// Validate the response status code
-	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-		return errors.New(ErrCodeNonSuccessStatusCode, fmt.Sprintf("HTTP request returned non-success status code: %d", resp.StatusCode))
-	}
-
-	return nil
+	return probe.Check(context.Background()).Err
 }
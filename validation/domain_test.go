@@ -4,6 +4,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -248,6 +249,56 @@ func TestValidateURLSyntax(t *testing.T) {
 	})
 }
 
+func TestValidateURLAll(t *testing.T) {
+	t.Run("valid URL", func(t *testing.T) {
+		assert.Empty(t, ValidateURLAll("https://example.com/path"))
+		assert.Empty(t, ValidateURLAll("example.com"))
+	})
+
+	t.Run("empty URL", func(t *testing.T) {
+		v := ValidateURLAll("")
+		require.Len(t, v, 1)
+		assert.Equal(t, ErrCodeEmptyURL, v[0].Code)
+	})
+
+	t.Run("reports all problems", func(t *testing.T) {
+		input := "ftp://bad_domain.com/" + strings.Repeat("a", MaxURLLength)
+
+		v := ValidateURLAll(input)
+		require.Len(t, v, 3)
+		assert.Equal(t, ErrCodeURLTooLong, v[0].Code)
+		assert.Equal(t, ErrCodeUnsupportedScheme, v[1].Code)
+		assert.Equal(t, ErrCodeInvalidDomain, v[2].Code)
+
+		for _, violation := range v {
+			assert.Equal(t, URLField, violation.Field)
+			assert.NotEmpty(t, violation.Message)
+		}
+
+		// the first violation matches the error of IsValidURL
+		err := IsValidURL(input)
+		assert.Equal(t, v[0].Code, string(errors.Code(err)))
+	})
+
+	t.Run("missing host and unsupported scheme", func(t *testing.T) {
+		v := ValidateURLAll("mailto:someone")
+		require.Len(t, v, 2)
+		assert.Equal(t, ErrCodeInvalidURL, v[0].Code)
+		assert.Equal(t, ErrCodeUnsupportedScheme, v[1].Code)
+	})
+
+	t.Run("unparsable URL", func(t *testing.T) {
+		v := ValidateURLAll(string([]byte{0x00, 0x01, 0x02}))
+		require.Len(t, v, 1)
+		assert.Equal(t, ErrCodeInvalidURL, v[0].Code)
+	})
+
+	t.Run("as validation error", func(t *testing.T) {
+		err := errors.NewValidation(ValidateURLAll("ftp://bad_domain.com")...)
+		assert.Len(t, errors.ViolationsOf(err), 2)
+	})
+}
+
 func TestCheckURLReachability(t *testing.T) {
 	// Note: These tests require network connectivity and may be flaky
 	// In a real enterprise environment, these would be mocked or run in integration tests
@@ -0,0 +1,312 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/kopexa-grc/common/errors"
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
+)
+
+// Error codes for HTML validation.
+const (
+	// ErrCodeUnsafeHTML indicates that user-provided HTML contains elements,
+	// attributes or URLs that are not allowed by the HTML policy. The
+	// individual findings are reported as field violations.
+	ErrCodeUnsafeHTML = "VALIDATION_UNSAFE_HTML"
+)
+
+// Violation codes reported by ValidateHTML.
+const (
+	// HTMLViolationTagNotAllowed reports an element that is not on the allow-list.
+	HTMLViolationTagNotAllowed = "tag_not_allowed"
+	// HTMLViolationAttributeNotAllowed reports an attribute that is not allowed on its element.
+	HTMLViolationAttributeNotAllowed = "attribute_not_allowed"
+	// HTMLViolationURLNotAllowed reports a URL with a disallowed scheme, a
+	// relative URL where none are allowed, or a URL that cannot be parsed.
+	HTMLViolationURLNotAllowed = "url_not_allowed"
+)
+
+// MaxHTMLViolations caps the number of violations reported by ValidateHTML.
+const MaxHTMLViolations = 100
+
+// GlobalHTMLAttributes is the HTMLPolicy.AllowedAttributes key for
+// attributes allowed on every allowed element.
+const GlobalHTMLAttributes = "*"
+
+// HTMLPolicy is an allow-list for user-provided HTML. Everything that is not
+// listed is removed by SanitizeHTML and reported by ValidateHTML.
+type HTMLPolicy struct {
+	// AllowedTags lists the allowed elements, e.g. ["p", "a", "strong"].
+	AllowedTags []string `json:"allowedTags,omitempty" yaml:"allowedTags,omitempty"`
+
+	// AllowedAttributes maps an element to its allowed attributes. Attributes
+	// listed under GlobalHTMLAttributes are allowed on every allowed element.
+	AllowedAttributes map[string][]string `json:"allowedAttributes,omitempty" yaml:"allowedAttributes,omitempty"`
+
+	// AllowedURLSchemes restricts the schemes of href, src and cite
+	// attributes, e.g. ["https", "mailto"].
+	AllowedURLSchemes []string `json:"allowedUrlSchemes,omitempty" yaml:"allowedUrlSchemes,omitempty"`
+
+	// AllowRelativeURLs accepts URLs without a scheme, e.g. "/docs" or "#top".
+	AllowRelativeURLs bool `json:"allowRelativeUrls,omitempty" yaml:"allowRelativeUrls,omitempty"`
+}
+
+// DefaultHTMLPolicy returns a policy for rich-text fields: basic formatting,
+// lists, headings, tables and links to http, https and mailto URLs.
+func DefaultHTMLPolicy() HTMLPolicy {
+	return HTMLPolicy{
+		AllowedTags: []string{
+			"p", "br", "hr", "span", "strong", "b", "em", "i", "u", "s", "sub", "sup",
+			"blockquote", "code", "pre", "ul", "ol", "li",
+			"h1", "h2", "h3", "h4", "h5", "h6",
+			"table", "thead", "tbody", "tr", "th", "td", "a",
+		},
+		AllowedAttributes: map[string][]string{
+			"a": {"href", "title"},
+		},
+		AllowedURLSchemes: []string{"http", "https", "mailto"},
+		AllowRelativeURLs: true,
+	}
+}
+
+var (
+	// forbiddenHTMLTags can execute code or change how the document is
+	// interpreted and cannot be allowed by a policy.
+	forbiddenHTMLTags = []string{
+		"script", "style", "iframe", "frame", "frameset", "object", "embed",
+		"applet", "base", "link", "meta",
+	}
+
+	// forbiddenHTMLAttributes can execute code or carry URLs that are not
+	// checked against the allowed schemes. Event handlers (on*) are rejected
+	// separately.
+	forbiddenHTMLAttributes = []string{
+		"style", "srcdoc", "srcset", "action", "formaction", "poster",
+		"background", "longdesc", "xlink:href", "data", "codebase", "ping",
+	}
+
+	// forbiddenURLSchemes can execute code in the browser.
+	forbiddenURLSchemes = []string{"javascript", "vbscript", "data"}
+
+	// htmlURLAttributes are the attributes whose URLs are checked.
+	htmlURLAttributes = []string{"href", "src", "cite"}
+)
+
+// HTMLSanitizer sanitizes and validates HTML against a compiled HTMLPolicy.
+// It is safe for concurrent use.
+type HTMLSanitizer struct {
+	attrs    map[string]map[string]struct{}
+	global   map[string]struct{}
+	schemes  []string
+	relative bool
+	policy   *bluemonday.Policy
+}
+
+// NewHTMLSanitizer compiles policy into an HTMLSanitizer.
+//
+// Returns an error with code ErrCodeInvalidValidatorOptions if the policy
+// allows elements, attributes or URL schemes that are unsafe regardless of
+// context, such as <script>, event handlers or javascript: URLs.
+func NewHTMLSanitizer(policy HTMLPolicy) (*HTMLSanitizer, error) {
+	s := &HTMLSanitizer{
+		attrs:    make(map[string]map[string]struct{}, len(policy.AllowedTags)),
+		global:   make(map[string]struct{}),
+		relative: policy.AllowRelativeURLs,
+		policy:   bluemonday.NewPolicy(),
+	}
+
+	for _, tag := range policy.AllowedTags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if slices.Contains(forbiddenHTMLTags, tag) {
+			return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("Element <%s> cannot be allowed", tag))
+		}
+
+		s.attrs[tag] = make(map[string]struct{})
+		s.policy.AllowElements(tag)
+	}
+
+	for tag, attrs := range policy.AllowedAttributes {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+
+		allowed := s.global
+		if tag != GlobalHTMLAttributes {
+			var ok bool
+			if allowed, ok = s.attrs[tag]; !ok {
+				return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("Attributes configured for element <%s> which is not allowed", tag))
+			}
+		}
+
+		names := make([]string, 0, len(attrs))
+
+		for _, attr := range attrs {
+			attr = strings.ToLower(strings.TrimSpace(attr))
+			if strings.HasPrefix(attr, "on") || slices.Contains(forbiddenHTMLAttributes, attr) {
+				return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("Attribute '%s' cannot be allowed", attr))
+			}
+
+			allowed[attr] = struct{}{}
+			names = append(names, attr)
+		}
+
+		if tag == GlobalHTMLAttributes {
+			s.policy.AllowAttrs(names...).Globally()
+		} else {
+			s.policy.AllowAttrs(names...).OnElements(tag)
+		}
+	}
+
+	for _, scheme := range policy.AllowedURLSchemes {
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		if slices.Contains(forbiddenURLSchemes, scheme) {
+			return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("URL scheme '%s' cannot be allowed", scheme))
+		}
+
+		s.schemes = append(s.schemes, scheme)
+	}
+
+	s.policy.RequireParseableURLs(true)
+	s.policy.AllowRelativeURLs(policy.AllowRelativeURLs)
+
+	if len(s.schemes) > 0 {
+		s.policy.AllowURLSchemes(s.schemes...)
+	}
+
+	return s, nil
+}
+
+// Sanitize removes everything from input that the policy does not allow.
+// The content of removed elements is kept as text, except for elements like
+// <script> whose content is dropped.
+func (s *HTMLSanitizer) Sanitize(input string) string {
+	return s.policy.Sanitize(input)
+}
+
+// Validate reports everything in input that the policy does not allow
+// without modifying it. It returns an error with code ErrCodeUnsafeHTML that
+// carries one field violation per finding (at most MaxHTMLViolations); the
+// field is the element name, or "element[attribute]" for attributes.
+func (s *HTMLSanitizer) Validate(input string) error {
+	var violations []errors.FieldViolation
+
+	z := html.NewTokenizer(strings.NewReader(input))
+
+tokens:
+	for len(violations) < MaxHTMLViolations {
+		switch z.Next() {
+		case html.ErrorToken:
+			// io.EOF; reading from a string cannot fail otherwise.
+			break tokens
+		case html.StartTagToken, html.SelfClosingTagToken:
+			violations = append(violations, s.checkTag(z)...)
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if len(violations) > MaxHTMLViolations {
+		violations = violations[:MaxHTMLViolations]
+	}
+
+	return errors.New(ErrCodeUnsafeHTML, fmt.Sprintf("HTML contains %d disallowed element(s), attribute(s) or URL(s)", len(violations))).
+		WithViolations(violations...)
+}
+
+// checkTag checks the current start tag of z and its attributes.
+func (s *HTMLSanitizer) checkTag(z *html.Tokenizer) []errors.FieldViolation {
+	name, hasAttr := z.TagName()
+	tag := string(name)
+
+	allowed, ok := s.attrs[tag]
+	if !ok {
+		return []errors.FieldViolation{{
+			Field:   tag,
+			Code:    HTMLViolationTagNotAllowed,
+			Message: fmt.Sprintf("Element <%s> is not allowed", tag),
+		}}
+	}
+
+	var violations []errors.FieldViolation
+
+	for hasAttr {
+		var key, val []byte
+
+		key, val, hasAttr = z.TagAttr()
+		attr := string(key)
+		field := tag + "[" + attr + "]"
+
+		_, isAllowed := allowed[attr]
+		if _, isGlobal := s.global[attr]; !isAllowed && !isGlobal {
+			violations = append(violations, errors.FieldViolation{
+				Field:   field,
+				Code:    HTMLViolationAttributeNotAllowed,
+				Message: fmt.Sprintf("Attribute '%s' is not allowed on <%s>", attr, tag),
+			})
+
+			continue
+		}
+
+		if slices.Contains(htmlURLAttributes, attr) && !s.allowedURL(string(val)) {
+			violations = append(violations, errors.FieldViolation{
+				Field:   field,
+				Code:    HTMLViolationURLNotAllowed,
+				Message: fmt.Sprintf("URL '%s' is not allowed", string(val)),
+			})
+		}
+	}
+
+	return violations
+}
+
+// allowedURL applies the same rules to a URL attribute as the sanitizer.
+func (s *HTMLSanitizer) allowedURL(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	if strings.ContainsAny(raw, " \t\n") {
+		return false
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	if u.Scheme == "" {
+		return s.relative && raw != ""
+	}
+
+	return slices.Contains(s.schemes, u.Scheme)
+}
+
+// SanitizeHTML compiles policy and sanitizes input with it. Use
+// NewHTMLSanitizer to compile a policy once for repeated use.
+//
+// Example:
+//
+//	clean, err := validation.SanitizeHTML(input, validation.DefaultHTMLPolicy())
+func SanitizeHTML(input string, policy HTMLPolicy) (string, error) {
+	s, err := NewHTMLSanitizer(policy)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Sanitize(input), nil
+}
+
+// ValidateHTML compiles policy and reports everything in input it does not
+// allow, without modifying input. See HTMLSanitizer.Validate.
+func ValidateHTML(input string, policy HTMLPolicy) error {
+	s, err := NewHTMLSanitizer(policy)
+	if err != nil {
+		return err
+	}
+
+	return s.Validate(input)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "allowed markup", input: `<p><strong>bold</strong> <a href="https://kopexa.com">link</a></p>`, want: `<p><strong>bold</strong> <a href="https://kopexa.com">link</a></p>`},
+		{name: "script removed", input: `<p>hi</p><script>alert(1)</script>`, want: `<p>hi</p>`},
+		{name: "event handler removed", input: `<p onclick="alert(1)">hi</p>`, want: `<p>hi</p>`},
+		{name: "javascript url removed", input: `<a href="javascript:alert(1)">x</a>`, want: `x`},
+		{name: "entity encoded scheme removed", input: `<a href="&#106;avascript:alert(1)">x</a>`, want: `x`},
+		{name: "unknown tag unwrapped", input: `<div><em>text</em></div>`, want: `<em>text</em>`},
+		{name: "relative url kept", input: `<a href="/docs#top">docs</a>`, want: `<a href="/docs#top">docs</a>`},
+		{name: "img removed", input: `<img src=x onerror=alert(1)>`, want: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SanitizeHTML(tt.input, DefaultHTMLPolicy())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, ValidateHTML(got, DefaultHTMLPolicy()), "sanitized output must validate")
+		})
+	}
+}
+
+func TestValidateHTML(t *testing.T) {
+	policy := DefaultHTMLPolicy()
+
+	require.NoError(t, ValidateHTML(`<p>Hello <a href="mailto:a@kopexa.com" title="mail">mail</a></p>`, policy))
+	require.NoError(t, ValidateHTML("plain text", policy))
+
+	input := `<p onclick="x()">a</p><script>alert(1)</script><a href="javascript:alert(1)">b</a><a href="java script:x">c</a>`
+
+	err := ValidateHTML(input, policy)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrCodeUnsafeHTML))
+
+	assert.Equal(t, []errors.FieldViolation{
+		{Field: "p[onclick]", Code: HTMLViolationAttributeNotAllowed, Message: "Attribute 'onclick' is not allowed on <p>"},
+		{Field: "script", Code: HTMLViolationTagNotAllowed, Message: "Element <script> is not allowed"},
+		{Field: "a[href]", Code: HTMLViolationURLNotAllowed, Message: "URL 'javascript:alert(1)' is not allowed"},
+		{Field: "a[href]", Code: HTMLViolationURLNotAllowed, Message: "URL 'java script:x' is not allowed"},
+	}, errors.ViolationsOf(err))
+
+	policy.AllowRelativeURLs = false
+	err = ValidateHTML(`<a href="/docs">docs</a>`, policy)
+	require.Error(t, err)
+	assert.Equal(t, HTMLViolationURLNotAllowed, errors.ViolationsOf(err)[0].Code)
+}
+
+func TestValidateHTMLViolationLimit(t *testing.T) {
+	input := ""
+	for range MaxHTMLViolations + 10 {
+		input += "<div></div>"
+	}
+
+	err := ValidateHTML(input, DefaultHTMLPolicy())
+	require.Error(t, err)
+	assert.Len(t, errors.ViolationsOf(err), MaxHTMLViolations)
+}
+
+func TestHTMLPolicyGlobalAttributes(t *testing.T) {
+	policy := HTMLPolicy{
+		AllowedTags:       []string{"p", "span"},
+		AllowedAttributes: map[string][]string{GlobalHTMLAttributes: {"title"}},
+	}
+
+	require.NoError(t, ValidateHTML(`<p title="a"><span title="b">x</span></p>`, policy))
+
+	got, err := SanitizeHTML(`<p title="a" class="c">x</p>`, policy)
+	require.NoError(t, err)
+	assert.Equal(t, `<p title="a">x</p>`, got)
+}
+
+func TestNewHTMLSanitizerRejectsUnsafePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy HTMLPolicy
+	}{
+		{name: "script tag", policy: HTMLPolicy{AllowedTags: []string{"Script"}}},
+		{name: "event handler", policy: HTMLPolicy{AllowedTags: []string{"p"}, AllowedAttributes: map[string][]string{"p": {"onClick"}}}},
+		{name: "style attribute", policy: HTMLPolicy{AllowedTags: []string{"p"}, AllowedAttributes: map[string][]string{GlobalHTMLAttributes: {"style"}}}},
+		{name: "attributes for unknown tag", policy: HTMLPolicy{AllowedAttributes: map[string][]string{"a": {"href"}}}},
+		{name: "javascript scheme", policy: HTMLPolicy{AllowedURLSchemes: []string{"JavaScript"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHTMLSanitizer(tt.policy)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrCodeInvalidValidatorOptions))
+		})
+	}
+}
+
+func TestRegistryHTML(t *testing.T) {
+	r, err := NewRegistryFromYAML([]byte(`
+validators:
+  description:
+    type: html
+    html:
+      allowedTags: [p, a]
+      allowedAttributes:
+        a: [href]
+      allowedUrlSchemes: [https]
+`))
+	require.NoError(t, err)
+
+	require.NoError(t, r.Validate("description", `<p><a href="https://kopexa.com">x</a></p>`))
+
+	err = r.Validate("description", `<a href="http://kopexa.com">x</a>`)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrCodeUnsafeHTML))
+}
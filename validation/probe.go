@@ -0,0 +1,556 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kopexa-grc/common/errors"
+)
+
+// Error codes for health-check probes.
+const (
+	// ErrCodeTCPConnectFailed indicates that a TCP connection could not be established.
+	ErrCodeTCPConnectFailed = "VALIDATION_TCP_CONNECT_FAILED"
+
+	// ErrCodeTLSHandshakeFailed indicates that the TLS handshake failed, e.g.
+	// because the certificate is invalid for the server name.
+	ErrCodeTLSHandshakeFailed = "VALIDATION_TLS_HANDSHAKE_FAILED"
+
+	// ErrCodeCertificateExpiring indicates that the server certificate expires
+	// within the configured minimum validity.
+	ErrCodeCertificateExpiring = "VALIDATION_CERTIFICATE_EXPIRING"
+)
+
+// HealthStatus is the outcome of a probe or of an aggregated health report.
+type HealthStatus string
+
+const (
+	// StatusHealthy indicates that the dependency works as expected.
+	StatusHealthy HealthStatus = "healthy"
+	// StatusDegraded indicates that the dependency works but is slow or close
+	// to failing, e.g. a certificate that expires soon.
+	StatusDegraded HealthStatus = "degraded"
+	// StatusUnhealthy indicates that the dependency is not usable.
+	StatusUnhealthy HealthStatus = "unhealthy"
+)
+
+// severity orders statuses from best to worst.
+func (s HealthStatus) severity() int {
+	switch s {
+	case StatusHealthy:
+		return 0
+	case StatusDegraded:
+		return 1
+	default:
+		return 2 //nolint:mnd // unhealthy and unknown statuses are the worst
+	}
+}
+
+// Thresholds configure when a probe fails or is considered degraded.
+type Thresholds struct {
+	// Timeout bounds the duration of a single check. Defaults to DefaultHTTPTimeout.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// DegradedLatency marks successful checks slower than this value as
+	// degraded. Zero disables the latency threshold.
+	DegradedLatency time.Duration `json:"degradedLatency,omitempty" yaml:"degradedLatency,omitempty"`
+}
+
+// timeout returns the configured timeout or DefaultHTTPTimeout.
+func (t Thresholds) timeout() time.Duration {
+	if t.Timeout > 0 {
+		return t.Timeout
+	}
+
+	return DefaultHTTPTimeout
+}
+
+// ProbeResult is the result of a single probe.
+type ProbeResult struct {
+	// Name is the name of the probe.
+	Name string `json:"name"`
+	// Status is the health of the dependency.
+	Status HealthStatus `json:"status"`
+	// Latency is the duration of the check.
+	Latency time.Duration `json:"latency"`
+	// Error describes why the probe failed or is degraded.
+	Error string `json:"error,omitempty"`
+	// Details contains probe-specific information, e.g. the HTTP status code.
+	Details map[string]string `json:"details,omitempty"`
+	// Err is the underlying error with a validation error code.
+	Err error `json:"-"`
+}
+
+// Probe checks the health of a single external dependency.
+//
+// Implementations must be safe for concurrent use.
+type Probe interface {
+	// Name identifies the probe in health reports.
+	Name() string
+	// Check runs the probe. It must return within the deadline of ctx.
+	Check(ctx context.Context) ProbeResult
+}
+
+// newResult builds a ProbeResult, applying the latency threshold to
+// successful checks.
+func newResult(name string, start time.Time, th Thresholds, details map[string]string, err error) ProbeResult {
+	r := ProbeResult{
+		Name:    name,
+		Status:  StatusHealthy,
+		Latency: time.Since(start),
+		Details: details,
+		Err:     err,
+	}
+
+	switch {
+	case err != nil:
+		r.Status = StatusUnhealthy
+		r.Error = err.Error()
+	case th.DegradedLatency > 0 && r.Latency > th.DegradedLatency:
+		r.Status = StatusDegraded
+		r.Error = fmt.Sprintf("Latency %s exceeds threshold of %s", r.Latency, th.DegradedLatency)
+	}
+
+	return r
+}
+
+// HTTPProbe checks that an HTTP endpoint responds with an expected status code.
+type HTTPProbe struct {
+	// ProbeName is the name of the probe; defaults to the URL.
+	ProbeName string
+	// URL is the endpoint to request.
+	URL string
+	// Method is the HTTP method; defaults to HEAD, or to GET if Response
+	// is set.
+	Method string
+	// ExpectedStatus lists the accepted status codes. By default every
+	// status code below 400 is accepted.
+	ExpectedStatus []int
+	// ExpectedStatusRanges lists accepted ranges of status codes in
+	// addition to ExpectedStatus.
+	ExpectedStatusRanges []StatusRange
+	// Client is the HTTP client to use; defaults to a client without
+	// keep-alives and with DefaultHTTPTimeout.
+	Client *http.Client
+	// Thresholds configure timeout and degraded latency.
+	Thresholds Thresholds
+	// Redirects enforces a policy on redirects. If set, the final URL and
+	// the redirect chain are reported in the result details.
+	Redirects *RedirectPolicy
+	// Response verifies content type, size and body of the response.
+	Response *ResponseAssertions
+}
+
+// Name implements Probe.
+func (p *HTTPProbe) Name() string {
+	if p.ProbeName != "" {
+		return p.ProbeName
+	}
+
+	return p.URL
+}
+
+// Check implements Probe.
+func (p *HTTPProbe) Check(ctx context.Context) ProbeResult {
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, p.Thresholds.timeout())
+	defer cancel()
+
+	details := map[string]string{}
+	err := p.do(ctx, details)
+
+	return newResult(p.Name(), start, p.Thresholds, details, err)
+}
+
+func (p *HTTPProbe) do(ctx context.Context, details map[string]string) error {
+	method := p.Method
+	if method == "" {
+		method = http.MethodHead
+		if p.Response != nil {
+			method = http.MethodGet
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.URL, http.NoBody)
+	if err != nil {
+		return errors.New(ErrCodeRequestCreationFailed, fmt.Sprintf("Failed to create HTTP request: %v", err))
+	}
+
+	req.Header.Set("User-Agent", DefaultUserAgent)
+
+	client := p.Client
+	if client == nil {
+		client = newProbeHTTPClient(p.Redirects != nil && p.Redirects.BlockPrivateNetworks)
+	}
+
+	var tracker *redirectTracker
+
+	if p.Redirects != nil {
+		tracker = &redirectTracker{policy: p.Redirects, chain: []string{req.URL.String()}}
+		defer tracker.details(details)
+
+		if p.Redirects.BlockPrivateNetworks {
+			if err := checkPublicHost(ctx, req.URL.Hostname()); err != nil {
+				return err
+			}
+		}
+
+		client = tracker.client(client)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if e, ok := codedError(err); ok {
+			return e
+		}
+
+		return errors.New(ErrCodeHTTPRequestFailed, fmt.Sprintf("HTTP request failed: %v", err))
+	}
+	defer resp.Body.Close()
+
+	if tracker != nil {
+		details[DetailFinalURL] = resp.Request.URL.String()
+	}
+
+	details["statusCode"] = fmt.Sprint(resp.StatusCode)
+
+	if !p.acceptsStatus(resp.StatusCode) {
+		return errors.New(ErrCodeNonSuccessStatusCode, fmt.Sprintf("HTTP request returned non-success status code: %d", resp.StatusCode))
+	}
+
+	if p.Response != nil {
+		return p.Response.check(resp, details)
+	}
+
+	return nil
+}
+
+// acceptsStatus reports whether code is accepted by ExpectedStatus or
+// ExpectedStatusRanges, or is below 400 if neither is set.
+func (p *HTTPProbe) acceptsStatus(code int) bool {
+	if len(p.ExpectedStatus) == 0 && len(p.ExpectedStatusRanges) == 0 {
+		return code >= 200 && code < 400
+	}
+
+	if slices.Contains(p.ExpectedStatus, code) {
+		return true
+	}
+
+	return slices.ContainsFunc(p.ExpectedStatusRanges, func(r StatusRange) bool {
+		return r.Contains(code)
+	})
+}
+
+// newProbeHTTPClient creates the default HTTP client used by HTTPProbe. If
+// blockInternal is set, connections to internal addresses are refused.
+func newProbeHTTPClient(blockInternal bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   DialTimeout,
+		KeepAlive: DialKeepAlive,
+	}
+
+	if blockInternal {
+		dialer.Control = blockInternalDial
+	}
+
+	return &http.Client{
+		Timeout: DefaultHTTPTimeout,
+		Transport: &http.Transport{
+			// Disable keep-alive to ensure fresh connections
+			DisableKeepAlives:     true,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   TLSHandshakeTimeout,
+			ResponseHeaderTimeout: ResponseHeaderTimeout,
+			IdleConnTimeout:       IdleConnTimeout,
+		},
+	}
+}
+
+// TCPProbe checks that a TCP connection can be established, e.g. to a
+// database or message broker.
+type TCPProbe struct {
+	// ProbeName is the name of the probe; defaults to the address.
+	ProbeName string
+	// Address is the "host:port" to connect to.
+	Address string
+	// Thresholds configure timeout and degraded latency.
+	Thresholds Thresholds
+}
+
+// Name implements Probe.
+func (p *TCPProbe) Name() string {
+	if p.ProbeName != "" {
+		return p.ProbeName
+	}
+
+	return p.Address
+}
+
+// Check implements Probe.
+func (p *TCPProbe) Check(ctx context.Context) ProbeResult {
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, p.Thresholds.timeout())
+	defer cancel()
+
+	var err error
+
+	conn, dialErr := (&net.Dialer{}).DialContext(ctx, "tcp", p.Address)
+	if dialErr != nil {
+		err = errors.New(ErrCodeTCPConnectFailed, fmt.Sprintf("TCP connection to '%s' failed: %v", p.Address, dialErr))
+	} else {
+		_ = conn.Close()
+	}
+
+	return newResult(p.Name(), start, p.Thresholds, nil, err)
+}
+
+// DNSProbe checks that a hostname resolves.
+type DNSProbe struct {
+	// ProbeName is the name of the probe; defaults to the host.
+	ProbeName string
+	// Host is the hostname to resolve.
+	Host string
+	// Resolver is the resolver to use; defaults to the pure Go resolver.
+	Resolver *net.Resolver
+	// Thresholds configure timeout and degraded latency.
+	Thresholds Thresholds
+}
+
+// Name implements Probe.
+func (p *DNSProbe) Name() string {
+	if p.ProbeName != "" {
+		return p.ProbeName
+	}
+
+	return p.Host
+}
+
+// Check implements Probe.
+func (p *DNSProbe) Check(ctx context.Context) ProbeResult {
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, p.Thresholds.timeout())
+	defer cancel()
+
+	resolver := p.Resolver
+	if resolver == nil {
+		resolver = &net.Resolver{PreferGo: true}
+	}
+
+	var (
+		err     error
+		details map[string]string
+	)
+
+	addrs, lookupErr := resolver.LookupHost(ctx, p.Host)
+	if lookupErr != nil {
+		err = errors.New(ErrCodeHostNotFound, fmt.Sprintf("DNS resolution failed for '%s': %v", p.Host, lookupErr))
+	} else {
+		details = map[string]string{"addresses": fmt.Sprint(len(addrs))}
+	}
+
+	return newResult(p.Name(), start, p.Thresholds, details, err)
+}
+
+// TLSProbe checks that a TLS handshake succeeds and that the server
+// certificate is valid for at least MinValidity.
+type TLSProbe struct {
+	// ProbeName is the name of the probe; defaults to the address.
+	ProbeName string
+	// Address is the "host:port" to connect to.
+	Address string
+	// ServerName is the name to verify the certificate against; defaults to
+	// the host of Address.
+	ServerName string
+	// MinValidity marks the probe as degraded if the leaf certificate
+	// expires within this duration. Zero disables the check.
+	MinValidity time.Duration
+	// TLSConfig is the base TLS configuration, e.g. with custom root CAs.
+	TLSConfig *tls.Config
+	// Thresholds configure timeout and degraded latency.
+	Thresholds Thresholds
+}
+
+// Name implements Probe.
+func (p *TLSProbe) Name() string {
+	if p.ProbeName != "" {
+		return p.ProbeName
+	}
+
+	return p.Address
+}
+
+// Check implements Probe.
+func (p *TLSProbe) Check(ctx context.Context) ProbeResult {
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, p.Thresholds.timeout())
+	defer cancel()
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if p.TLSConfig != nil {
+		cfg = p.TLSConfig.Clone()
+	}
+
+	cfg.ServerName = p.ServerName
+	if cfg.ServerName == "" {
+		cfg.ServerName, _, _ = net.SplitHostPort(p.Address)
+	}
+
+	dialer := &tls.Dialer{Config: cfg}
+
+	conn, err := dialer.DialContext(ctx, "tcp", p.Address)
+	if err != nil {
+		err = errors.New(ErrCodeTLSHandshakeFailed, fmt.Sprintf("TLS handshake with '%s' failed: %v", p.Address, err))
+		return newResult(p.Name(), start, p.Thresholds, nil, err)
+	}
+	defer conn.Close()
+
+	tlsConn, _ := conn.(*tls.Conn)
+	state := tlsConn.ConnectionState()
+
+	details := map[string]string{"version": tls.VersionName(state.Version)}
+
+	result := newResult(p.Name(), start, p.Thresholds, details, nil)
+
+	if len(state.PeerCertificates) == 0 {
+		return result
+	}
+
+	notAfter := state.PeerCertificates[0].NotAfter
+	details["notAfter"] = notAfter.UTC().Format(time.RFC3339)
+
+	if p.MinValidity > 0 && time.Until(notAfter) < p.MinValidity {
+		err := errors.New(ErrCodeCertificateExpiring, fmt.Sprintf("Certificate expires at %s", notAfter.UTC().Format(time.RFC3339)))
+		result.Status = StatusDegraded
+		result.Error = err.Error()
+		result.Err = err
+	}
+
+	return result
+}
+
+// HealthReport is the aggregated result of a DependencyChecker.
+type HealthReport struct {
+	// Status is the overall status: the worst status of all required probes,
+	// or degraded if an optional probe is unhealthy.
+	Status HealthStatus `json:"status"`
+	// Results contains the result of every probe in registration order.
+	Results []ProbeResult `json:"results"`
+	// CheckedAt is the time the check started.
+	CheckedAt time.Time `json:"checkedAt"`
+	// Duration is the total duration of the check.
+	Duration time.Duration `json:"duration"`
+}
+
+// Healthy reports whether the overall status is healthy or degraded.
+func (r HealthReport) Healthy() bool {
+	return r.Status != StatusUnhealthy
+}
+
+// dependency is a probe registered with a DependencyChecker.
+type dependency struct {
+	probe    Probe
+	optional bool
+}
+
+// DependencyChecker runs a set of probes concurrently and aggregates their
+// results into a HealthReport. It is safe for concurrent use.
+//
+// Example:
+//
+//	checker := validation.NewDependencyChecker(
+//		&validation.TCPProbe{ProbeName: "postgres", Address: "db:5432"},
+//		&validation.HTTPProbe{ProbeName: "fga", URL: "http://fga:8080/healthz"},
+//	)
+//	checker.AddOptional(&validation.TLSProbe{Address: "api.example.com:443", MinValidity: 14 * 24 * time.Hour})
+//
+//	report := checker.Check(ctx)
+//	if !report.Healthy() {
+//		// report.Results explains which dependency failed
+//	}
+type DependencyChecker struct {
+	mu           sync.RWMutex
+	dependencies []dependency
+}
+
+// NewDependencyChecker creates a DependencyChecker with the given required probes.
+func NewDependencyChecker(probes ...Probe) *DependencyChecker {
+	c := &DependencyChecker{}
+
+	for _, p := range probes {
+		c.Add(p)
+	}
+
+	return c
+}
+
+// Add registers a required probe. A failing required probe makes the
+// report unhealthy.
+func (c *DependencyChecker) Add(p Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dependencies = append(c.dependencies, dependency{probe: p})
+}
+
+// AddOptional registers an optional probe. A failing optional probe only
+// degrades the report.
+func (c *DependencyChecker) AddOptional(p Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dependencies = append(c.dependencies, dependency{probe: p, optional: true})
+}
+
+// Check runs all probes concurrently and returns the aggregated report.
+func (c *DependencyChecker) Check(ctx context.Context) HealthReport {
+	c.mu.RLock()
+	deps := slices.Clone(c.dependencies)
+	c.mu.RUnlock()
+
+	report := HealthReport{
+		Status:    StatusHealthy,
+		Results:   make([]ProbeResult, len(deps)),
+		CheckedAt: time.Now(),
+	}
+
+	var wg sync.WaitGroup
+
+	for i, d := range deps {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			report.Results[i] = d.probe.Check(ctx)
+		}()
+	}
+
+	wg.Wait()
+
+	for i, d := range deps {
+		status := report.Results[i].Status
+		if d.optional && status == StatusUnhealthy {
+			status = StatusDegraded
+		}
+
+		if status.severity() > report.Status.severity() {
+			report.Status = status
+		}
+	}
+
+	report.Duration = time.Since(report.CheckedAt)
+
+	return report
+}
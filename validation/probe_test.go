@@ -0,0 +1,173 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticProbe returns a fixed status.
+type staticProbe struct {
+	name   string
+	status HealthStatus
+}
+
+func (p staticProbe) Name() string { return p.name }
+
+func (p staticProbe) Check(context.Context) ProbeResult {
+	return ProbeResult{Name: p.name, Status: p.status}
+}
+
+func TestHTTPProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(20 * time.Millisecond)
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("healthy", func(t *testing.T) {
+		res := (&HTTPProbe{ProbeName: "api", URL: srv.URL}).Check(t.Context())
+
+		assert.Equal(t, StatusHealthy, res.Status)
+		assert.Equal(t, "api", res.Name)
+		assert.Equal(t, "200", res.Details["statusCode"])
+		assert.NoError(t, res.Err)
+	})
+
+	t.Run("non-success status", func(t *testing.T) {
+		res := (&HTTPProbe{URL: srv.URL + "/down"}).Check(t.Context())
+
+		assert.Equal(t, StatusUnhealthy, res.Status)
+		assert.Equal(t, ErrCodeNonSuccessStatusCode, string(errors.Code(res.Err)))
+		assert.NotEmpty(t, res.Error)
+	})
+
+	t.Run("expected status", func(t *testing.T) {
+		res := (&HTTPProbe{URL: srv.URL + "/down", ExpectedStatus: []int{http.StatusServiceUnavailable}}).Check(t.Context())
+
+		assert.Equal(t, StatusHealthy, res.Status)
+	})
+
+	t.Run("degraded latency", func(t *testing.T) {
+		res := (&HTTPProbe{URL: srv.URL + "/slow", Thresholds: Thresholds{DegradedLatency: time.Millisecond}}).Check(t.Context())
+
+		assert.Equal(t, StatusDegraded, res.Status)
+		assert.NoError(t, res.Err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		res := (&HTTPProbe{URL: srv.URL + "/slow", Thresholds: Thresholds{Timeout: time.Millisecond}}).Check(t.Context())
+
+		assert.Equal(t, StatusUnhealthy, res.Status)
+		assert.Equal(t, ErrCodeHTTPRequestFailed, string(errors.Code(res.Err)))
+	})
+}
+
+func TestTCPProbe(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := ln.Addr().String()
+
+	res := (&TCPProbe{Address: addr}).Check(t.Context())
+	assert.Equal(t, StatusHealthy, res.Status)
+	assert.Equal(t, addr, res.Name)
+
+	require.NoError(t, ln.Close())
+
+	res = (&TCPProbe{Address: addr}).Check(t.Context())
+	assert.Equal(t, StatusUnhealthy, res.Status)
+	assert.Equal(t, ErrCodeTCPConnectFailed, string(errors.Code(res.Err)))
+}
+
+func TestDNSProbe(t *testing.T) {
+	res := (&DNSProbe{Host: "localhost"}).Check(t.Context())
+	assert.Equal(t, StatusHealthy, res.Status)
+
+	res = (&DNSProbe{Host: "does-not-exist.invalid"}).Check(t.Context())
+	assert.Equal(t, StatusUnhealthy, res.Status)
+	assert.Equal(t, ErrCodeHostNotFound, string(errors.Code(res.Err)))
+}
+
+func TestTLSProbe(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+
+	tlsConfig := srv.Client().Transport.(*http.Transport).TLSClientConfig
+	addr := strings.TrimPrefix(srv.URL, "https://")
+
+	t.Run("healthy", func(t *testing.T) {
+		res := (&TLSProbe{Address: addr, TLSConfig: tlsConfig, MinValidity: time.Hour}).Check(t.Context())
+
+		assert.Equal(t, StatusHealthy, res.Status, res.Error)
+		assert.NotEmpty(t, res.Details["notAfter"])
+	})
+
+	t.Run("certificate expiring", func(t *testing.T) {
+		res := (&TLSProbe{Address: addr, TLSConfig: tlsConfig, MinValidity: 100 * 365 * 24 * time.Hour}).Check(t.Context())
+
+		assert.Equal(t, StatusDegraded, res.Status)
+		assert.Equal(t, ErrCodeCertificateExpiring, string(errors.Code(res.Err)))
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		res := (&TLSProbe{Address: addr}).Check(t.Context())
+
+		assert.Equal(t, StatusUnhealthy, res.Status)
+		assert.Equal(t, ErrCodeTLSHandshakeFailed, string(errors.Code(res.Err)))
+	})
+}
+
+func TestDependencyChecker(t *testing.T) {
+	tests := []struct {
+		name     string
+		required []HealthStatus
+		optional []HealthStatus
+		want     HealthStatus
+	}{
+		{name: "all healthy", required: []HealthStatus{StatusHealthy, StatusHealthy}, want: StatusHealthy},
+		{name: "required degraded", required: []HealthStatus{StatusHealthy, StatusDegraded}, want: StatusDegraded},
+		{name: "required unhealthy", required: []HealthStatus{StatusDegraded, StatusUnhealthy}, want: StatusUnhealthy},
+		{name: "optional unhealthy", required: []HealthStatus{StatusHealthy}, optional: []HealthStatus{StatusUnhealthy}, want: StatusDegraded},
+		{name: "no probes", want: StatusHealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewDependencyChecker()
+
+			for i, s := range tt.required {
+				c.Add(staticProbe{name: "required-" + string(rune('a'+i)), status: s})
+			}
+
+			for _, s := range tt.optional {
+				c.AddOptional(staticProbe{name: "optional", status: s})
+			}
+
+			report := c.Check(t.Context())
+
+			assert.Equal(t, tt.want, report.Status)
+			assert.Equal(t, tt.want != StatusUnhealthy, report.Healthy())
+			require.Len(t, report.Results, len(tt.required)+len(tt.optional))
+
+			if len(tt.required) > 0 {
+				assert.Equal(t, "required-a", report.Results[0].Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kopexa-grc/common/errors"
+)
+
+// Error codes for validation profiles.
+const (
+	// ErrCodeInvalidProfile indicates that a validation profile is invalid,
+	// e.g. because it allows an unsupported scheme.
+	ErrCodeInvalidProfile = "VALIDATION_INVALID_PROFILE"
+
+	// ErrCodeProfileNotFound indicates that no profile is registered under the requested name.
+	ErrCodeProfileNotFound = "VALIDATION_PROFILE_NOT_FOUND"
+)
+
+// Names of the built-in profiles.
+const (
+	// ProfileDevelopment is the name of DevelopmentProfile.
+	ProfileDevelopment = "development"
+
+	// ProfileProduction is the name of ProductionProfile.
+	ProfileProduction = "production"
+)
+
+// PrivateNetworkPolicy controls how URLs pointing to internal hosts are
+// treated. Internal hosts are localhost and its subdomains as well as
+// loopback, private, link-local, multicast and unspecified IP addresses.
+type PrivateNetworkPolicy string
+
+const (
+	// PrivateNetworksUnchecked applies the syntax checks of IsValidURL only:
+	// IPv4 literals are accepted, single-label hosts like localhost are not.
+	PrivateNetworksUnchecked PrivateNetworkPolicy = ""
+
+	// PrivateNetworksAllow additionally accepts localhost and internal IP
+	// literals, e.g. http://localhost:8080 during development.
+	PrivateNetworksAllow PrivateNetworkPolicy = "allow"
+
+	// PrivateNetworksDeny rejects internal hosts with ErrCodePrivateNetwork.
+	// With reachability checks, hosts that resolve to internal addresses
+	// and redirects to them are rejected as well.
+	PrivateNetworksDeny PrivateNetworkPolicy = "deny"
+)
+
+// Profile is the environment-specific part of URL validation, e.g. that
+// development accepts http://localhost while production requires public
+// https URLs. A Registry applies its profile to all URL validators; see
+// Registry.UseProfile.
+//
+// Profiles are usually loaded from the service configuration with koanf or
+// from the environment with ProfileFromEnv. The zero value adds no
+// constraints.
+type Profile struct {
+	// Name identifies the profile in a Registry.
+	Name string `json:"name,omitempty" yaml:"name,omitempty" koanf:"name"`
+
+	// Schemes restricts the accepted schemes of all URL validators. The
+	// schemes of a validator are intersected with them.
+	Schemes []string `json:"schemes,omitempty" yaml:"schemes,omitempty" koanf:"schemes"`
+
+	// PrivateNetworks is the policy for URLs pointing to internal hosts.
+	PrivateNetworks PrivateNetworkPolicy `json:"privateNetworks,omitempty" yaml:"privateNetworks,omitempty" koanf:"privateNetworks"`
+
+	// MaxLength limits the URL length of all URL validators. It cannot
+	// exceed MaxURLLength.
+	MaxLength int `json:"maxLength,omitempty" yaml:"maxLength,omitempty" koanf:"maxLength"`
+
+	// CheckReachability makes URL validators check that the URL is
+	// reachable, like CheckURLReachabilityWithPolicy, after it passed all
+	// other checks. Redirects must pass the validator as well.
+	CheckReachability bool `json:"checkReachability,omitempty" yaml:"checkReachability,omitempty" koanf:"checkReachability"`
+}
+
+// DevelopmentProfile returns the profile for local development: http and
+// https URLs including localhost and private addresses are accepted.
+func DevelopmentProfile() Profile {
+	return Profile{
+		Name:            ProfileDevelopment,
+		Schemes:         []string{"http", "https"},
+		PrivateNetworks: PrivateNetworksAllow,
+	}
+}
+
+// ProductionProfile returns the profile for production: only https URLs of
+// public hosts are accepted.
+func ProductionProfile() Profile {
+	return Profile{
+		Name:            ProfileProduction,
+		Schemes:         []string{"https"},
+		PrivateNetworks: PrivateNetworksDeny,
+	}
+}
+
+// Validate returns an error with code ErrCodeInvalidProfile if the profile
+// lists an unsupported scheme, has an unknown private network policy or an
+// out-of-range MaxLength.
+func (p Profile) Validate() error {
+	for _, s := range p.Schemes {
+		if !slices.Contains(supportedSchemes, strings.ToLower(s)) {
+			return errors.New(ErrCodeInvalidProfile, fmt.Sprintf("Profile '%s': unsupported URL scheme '%s'. Only %v are supported", p.Name, s, supportedSchemes))
+		}
+	}
+
+	switch p.PrivateNetworks {
+	case PrivateNetworksUnchecked, PrivateNetworksAllow, PrivateNetworksDeny:
+	default:
+		return errors.New(ErrCodeInvalidProfile, fmt.Sprintf("Profile '%s': unknown private network policy '%s'", p.Name, p.PrivateNetworks))
+	}
+
+	if p.MaxLength < 0 || p.MaxLength > MaxURLLength {
+		return errors.New(ErrCodeInvalidProfile, fmt.Sprintf("Profile '%s': max length must be between 0 and %d", p.Name, MaxURLLength))
+	}
+
+	return nil
+}
+
+// ProfileFromEnv creates a profile from environment variables. The built-in
+// profile named by <prefix>_PROFILE (default: production) is the base;
+// <prefix>_SCHEMES (comma-separated), <prefix>_PRIVATE_NETWORKS,
+// <prefix>_MAX_LENGTH and <prefix>_CHECK_REACHABILITY override its fields
+// when set.
+//
+// Example:
+//
+//	// VALIDATION_PROFILE=development VALIDATION_CHECK_REACHABILITY=true
+//	profile, err := validation.ProfileFromEnv("VALIDATION")
+func ProfileFromEnv(prefix string) (Profile, error) {
+	env := func(name string) string {
+		return strings.TrimSpace(os.Getenv(prefix + "_" + name))
+	}
+
+	var p Profile
+
+	switch name := strings.ToLower(env("PROFILE")); name {
+	case "", ProfileProduction:
+		p = ProductionProfile()
+	case ProfileDevelopment:
+		p = DevelopmentProfile()
+	default:
+		return Profile{}, errors.New(ErrCodeProfileNotFound, fmt.Sprintf("Profile '%s' is not a built-in profile", name))
+	}
+
+	if v := env("SCHEMES"); v != "" {
+		p.Schemes = nil
+
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				p.Schemes = append(p.Schemes, strings.ToLower(s))
+			}
+		}
+	}
+
+	if v := env("PRIVATE_NETWORKS"); v != "" {
+		p.PrivateNetworks = PrivateNetworkPolicy(strings.ToLower(v))
+	}
+
+	if v := env("MAX_LENGTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Profile{}, errors.New(ErrCodeInvalidProfile, fmt.Sprintf("Invalid %s_MAX_LENGTH: %v", prefix, err))
+		}
+
+		p.MaxLength = n
+	}
+
+	if v := env("CHECK_REACHABILITY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return Profile{}, errors.New(ErrCodeInvalidProfile, fmt.Sprintf("Invalid %s_CHECK_REACHABILITY: %v", prefix, err))
+		}
+
+		p.CheckReachability = b
+	}
+
+	if err := p.Validate(); err != nil {
+		return Profile{}, err
+	}
+
+	return p, nil
+}
+
+// isInternalHost reports whether host is localhost, a subdomain of it or an
+// internal IP literal.
+func isInternalHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(host)
+
+	return err == nil && isInternalAddr(addr)
+}
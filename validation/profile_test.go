@@ -0,0 +1,164 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLValidatorWithProfile(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   Profile
+		input     string
+		errorCode string
+	}{
+		{name: "development localhost", profile: DevelopmentProfile(), input: "http://localhost:3000/callback"},
+		{name: "development loopback", profile: DevelopmentProfile(), input: "http://127.0.0.1:8080"},
+		{name: "development ipv6 loopback", profile: DevelopmentProfile(), input: "http://[::1]:8080"},
+		{name: "development public", profile: DevelopmentProfile(), input: "https://example.com"},
+		{name: "development ftp", profile: DevelopmentProfile(), input: "ftp://localhost", errorCode: ErrCodeUnsupportedScheme},
+		{name: "production localhost", profile: ProductionProfile(), input: "https://localhost", errorCode: ErrCodeInvalidDomain},
+		{name: "production localhost subdomain", profile: ProductionProfile(), input: "https://app.localhost", errorCode: ErrCodePrivateNetwork},
+		{name: "production private ip", profile: ProductionProfile(), input: "https://10.0.0.1", errorCode: ErrCodePrivateNetwork},
+		{name: "production http", profile: ProductionProfile(), input: "http://example.com", errorCode: ErrCodeUnsupportedScheme},
+		{name: "production public", profile: ProductionProfile(), input: "https://example.com"},
+		{name: "unchecked localhost", input: "http://localhost", errorCode: ErrCodeInvalidDomain},
+		{name: "unchecked private ip", input: "http://10.0.0.1"},
+		{name: "max length", profile: Profile{MaxLength: 20}, input: "https://example.com/long", errorCode: ErrCodeURLTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewURLValidatorWithProfile(URLOptions{}, tt.profile)
+			require.NoError(t, err)
+
+			err = v.Validate(tt.input)
+			if tt.errorCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, errors.ErrorCode(tt.errorCode)), "got %v", err)
+		})
+	}
+}
+
+func TestURLValidatorWithProfile_Options(t *testing.T) {
+	v, err := NewURLValidatorWithProfile(URLOptions{Schemes: []string{"http", "https"}}, ProductionProfile())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https"}, v.schemes)
+
+	_, err = NewURLValidatorWithProfile(URLOptions{Schemes: []string{"http"}}, ProductionProfile())
+	assert.True(t, errors.Is(err, ErrCodeInvalidValidatorOptions))
+
+	_, err = NewURLValidatorWithProfile(URLOptions{}, Profile{Schemes: []string{"ftp"}})
+	assert.True(t, errors.Is(err, ErrCodeInvalidProfile))
+
+	_, err = NewURLValidatorWithProfile(URLOptions{}, Profile{PrivateNetworks: "sometimes"})
+	assert.True(t, errors.Is(err, ErrCodeInvalidProfile))
+}
+
+func TestURLValidatorWithProfile_Reachability(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	profile := DevelopmentProfile()
+	profile.CheckReachability = true
+
+	v, err := NewURLValidatorWithProfile(URLOptions{}, profile)
+	require.NoError(t, err)
+	require.NoError(t, v.ValidateContext(context.Background(), srv.URL))
+
+	srv.Close()
+	assert.True(t, errors.Is(v.Validate(srv.URL), ErrCodeHTTPRequestFailed))
+}
+
+func TestProfileFromEnv(t *testing.T) {
+	p, err := ProfileFromEnv("TEST_VALIDATION")
+	require.NoError(t, err)
+	assert.Equal(t, ProductionProfile(), p)
+
+	t.Setenv("TEST_VALIDATION_PROFILE", "development")
+	t.Setenv("TEST_VALIDATION_SCHEMES", "https, http")
+	t.Setenv("TEST_VALIDATION_MAX_LENGTH", "512")
+	t.Setenv("TEST_VALIDATION_CHECK_REACHABILITY", "true")
+
+	p, err = ProfileFromEnv("TEST_VALIDATION")
+	require.NoError(t, err)
+	assert.Equal(t, Profile{
+		Name:              ProfileDevelopment,
+		Schemes:           []string{"https", "http"},
+		PrivateNetworks:   PrivateNetworksAllow,
+		MaxLength:         512,
+		CheckReachability: true,
+	}, p)
+
+	t.Setenv("TEST_VALIDATION_PRIVATE_NETWORKS", "maybe")
+	_, err = ProfileFromEnv("TEST_VALIDATION")
+	assert.True(t, errors.Is(err, ErrCodeInvalidProfile))
+
+	t.Setenv("TEST_VALIDATION_PROFILE", "staging")
+	_, err = ProfileFromEnv("TEST_VALIDATION")
+	assert.True(t, errors.Is(err, ErrCodeProfileNotFound))
+}
+
+func TestRegistryProfiles(t *testing.T) {
+	reg := NewRegistry()
+
+	p, ok := reg.Profile(ProfileDevelopment)
+	require.True(t, ok)
+	assert.Equal(t, DevelopmentProfile(), p)
+
+	assert.True(t, errors.Is(reg.UseProfile("staging"), ErrCodeProfileNotFound))
+	require.NoError(t, reg.UseProfile(ProfileDevelopment))
+	assert.Equal(t, ProfileDevelopment, reg.ActiveProfile().Name)
+
+	require.NoError(t, reg.RegisterURL("callback-url", URLOptions{}))
+	require.NoError(t, reg.Validate("callback-url", "http://localhost:3000"))
+
+	assert.True(t, errors.Is(reg.UseProfile(ProfileProduction), ErrCodeInvalidProfile))
+	assert.True(t, errors.Is(reg.RegisterProfile(Profile{}), ErrCodeInvalidProfile))
+}
+
+func TestNewRegistryFromYAML_Profile(t *testing.T) {
+	spec := []byte(`
+profile: production
+profiles:
+  production:
+    schemes: [https]
+    privateNetworks: deny
+    maxLength: 128
+validators:
+  webhook-url:
+    deniedHosts: ["*.internal"]
+  ticket-key:
+    type: pattern
+    pattern: "^[A-Z]+-[0-9]+$"
+`)
+
+	reg, err := NewRegistryFromYAML(spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, 128, reg.ActiveProfile().MaxLength)
+	assert.NoError(t, reg.Validate("webhook-url", "https://example.com"))
+	assert.True(t, errors.Is(reg.Validate("webhook-url", "http://example.com"), ErrCodeUnsupportedScheme))
+	assert.True(t, errors.Is(reg.Validate("webhook-url", "https://192.168.1.10"), ErrCodePrivateNetwork))
+	assert.NoError(t, reg.Validate("ticket-key", "GRC-7"))
+
+	_, err = NewRegistryFromYAML([]byte("profile: staging\nvalidators: {}\n"))
+	assert.True(t, errors.Is(err, ErrCodeInvalidRegistrySpec))
+
+	_, err = NewRegistryFromYAML([]byte("profiles:\n  staging:\n    schemes: [ftp]\nvalidators: {}\n"))
+	assert.True(t, errors.Is(err, ErrCodeInvalidRegistrySpec))
+}
@@ -0,0 +1,222 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/kopexa-grc/common/errors"
+)
+
+// Error codes for redirect policy enforcement.
+const (
+	// ErrCodeTooManyRedirects indicates that a request was redirected more
+	// often than the redirect policy allows.
+	ErrCodeTooManyRedirects = "VALIDATION_TOO_MANY_REDIRECTS"
+
+	// ErrCodeRedirectNotAllowed indicates that a redirect target was rejected
+	// by the validator of the redirect policy.
+	ErrCodeRedirectNotAllowed = "VALIDATION_REDIRECT_NOT_ALLOWED"
+
+	// ErrCodePrivateNetwork indicates that a URL resolves to a loopback,
+	// private, link-local or otherwise internal address.
+	ErrCodePrivateNetwork = "VALIDATION_PRIVATE_NETWORK_ADDRESS"
+)
+
+// DefaultMaxRedirects is the number of redirects followed if
+// RedirectPolicy.MaxRedirects is zero. It matches net/http.
+const DefaultMaxRedirects = 10
+
+// Detail keys reported by HTTPProbe when a RedirectPolicy is set.
+const (
+	// DetailFinalURL is the URL of the response that was evaluated.
+	DetailFinalURL = "finalURL"
+	// DetailRedirectCount is the number of redirects encountered, including
+	// a target rejected by the policy.
+	DetailRedirectCount = "redirectCount"
+	// DetailRedirectChain lists the initial URL and all redirect targets,
+	// separated by RedirectChainSeparator.
+	DetailRedirectChain = "redirectChain"
+
+	// RedirectChainSeparator separates the URLs in DetailRedirectChain.
+	RedirectChainSeparator = " -> "
+)
+
+// RedirectPolicy controls how HTTPProbe follows redirects. Without a policy,
+// redirects are followed like net/http does, without any checks.
+type RedirectPolicy struct {
+	// MaxRedirects caps the number of redirects followed. Zero defaults to
+	// DefaultMaxRedirects; a negative value disables following, so that the
+	// redirect response itself is evaluated.
+	MaxRedirects int `json:"maxRedirects,omitempty" yaml:"maxRedirects,omitempty"`
+
+	// Validator is applied to the URL of every redirect target, e.g. a
+	// URLValidator restricting schemes and hosts. Rejected targets fail the
+	// check with ErrCodeRedirectNotAllowed.
+	Validator Validator `json:"-" yaml:"-"`
+
+	// BlockPrivateNetworks rejects the initial URL and every redirect target
+	// that resolves to a loopback, private, link-local, multicast or
+	// unspecified address, to prevent server-side request forgery. The check
+	// is repeated when connecting if the probe uses its default client.
+	BlockPrivateNetworks bool `json:"blockPrivateNetworks,omitempty" yaml:"blockPrivateNetworks,omitempty"`
+}
+
+// maxRedirects returns the configured limit or DefaultMaxRedirects.
+func (p *RedirectPolicy) maxRedirects() int {
+	if p.MaxRedirects == 0 {
+		return DefaultMaxRedirects
+	}
+
+	return p.MaxRedirects
+}
+
+// redirectTracker enforces a RedirectPolicy for a single check and records
+// the redirect chain.
+type redirectTracker struct {
+	policy *RedirectPolicy
+	chain  []string
+}
+
+// checkRedirect implements http.Client.CheckRedirect.
+func (t *redirectTracker) checkRedirect(req *http.Request, via []*http.Request) error {
+	limit := t.policy.maxRedirects()
+	if limit < 0 {
+		return http.ErrUseLastResponse
+	}
+
+	if len(via) > limit {
+		return errors.New(ErrCodeTooManyRedirects, fmt.Sprintf("Stopped after %d redirects", limit))
+	}
+
+	target := req.URL.String()
+	t.chain = append(t.chain, target)
+
+	if t.policy.Validator != nil {
+		if err := t.policy.Validator.Validate(target); err != nil {
+			return errors.New(ErrCodeRedirectNotAllowed, fmt.Sprintf("Redirect to '%s' is not allowed: %v", target, err))
+		}
+	}
+
+	if t.policy.BlockPrivateNetworks {
+		return checkPublicHost(req.Context(), req.URL.Hostname())
+	}
+
+	return nil
+}
+
+// client returns a copy of base that enforces the policy.
+func (t *redirectTracker) client(base *http.Client) *http.Client {
+	c := *base
+	c.CheckRedirect = t.checkRedirect
+
+	return &c
+}
+
+// details adds the redirect chain to details.
+func (t *redirectTracker) details(details map[string]string) {
+	details[DetailRedirectCount] = strconv.Itoa(len(t.chain) - 1)
+	details[DetailRedirectChain] = strings.Join(t.chain, RedirectChainSeparator)
+}
+
+// checkPublicHost resolves host and fails with ErrCodePrivateNetwork if any
+// of its addresses is internal.
+func checkPublicHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return errors.New(ErrCodeHostNotFound, fmt.Sprintf("DNS resolution failed for '%s': %v", host, err))
+	}
+
+	for _, addr := range addrs {
+		if isInternalAddr(addr) {
+			return errors.New(ErrCodePrivateNetwork, fmt.Sprintf("Host '%s' resolves to internal address %s", host, addr))
+		}
+	}
+
+	return nil
+}
+
+// isInternalAddr reports whether addr must not be reached from a server-side
+// request.
+func isInternalAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified()
+}
+
+// blockInternalDial is a net.Dialer Control function rejecting connections to
+// internal addresses. It guards against DNS rebinding between the policy
+// check and the connection.
+func blockInternalDial(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	if isInternalAddr(addr) {
+		return errors.New(ErrCodePrivateNetwork, fmt.Sprintf("Connection to internal address %s is not allowed", addr))
+	}
+
+	return nil
+}
+
+// codedError returns the validation error wrapped in a transport error, if
+// any, so that its code is preserved.
+func codedError(err error) (*errors.Error, bool) {
+	var e *errors.Error
+	if stderrors.As(err, &e) {
+		return e, true
+	}
+
+	return nil, false
+}
+
+// CheckURLReachabilityWithPolicy checks like CheckURLReachability and
+// enforces policy on the initial URL and every redirect. The returned result
+// reports the final URL and the redirect chain in its details; Err carries
+// the validation error code if the check failed.
+//
+// Example:
+//
+//	allowed, _ := validation.NewURLValidator(validation.URLOptions{
+//		Schemes:      []string{"https"},
+//		AllowedHosts: []string{"*.example.com"},
+//	})
+//	result := validation.CheckURLReachabilityWithPolicy(ctx, "https://app.example.com", validation.RedirectPolicy{
+//		MaxRedirects:         3,
+//		Validator:            allowed,
+//		BlockPrivateNetworks: true,
+//	})
+//	if result.Err != nil {
+//		// Handle reachability error
+//	}
+//	log.Printf("resolved to %s", result.Details[validation.DetailFinalURL])
+func CheckURLReachabilityWithPolicy(ctx context.Context, rawURL string, policy RedirectPolicy) ProbeResult {
+	if err := IsValidURL(rawURL); err != nil {
+		return ProbeResult{Name: rawURL, Status: StatusUnhealthy, Error: err.Error(), Err: err}
+	}
+
+	probe := &HTTPProbe{URL: rawURL, Redirects: &policy}
+
+	return probe.Check(ctx)
+}
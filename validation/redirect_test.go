@@ -0,0 +1,147 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedirectServer serves /hop/N redirecting to /hop/N-1 until /hop/0, and
+// /away redirecting to target.
+func newRedirectServer(t *testing.T, target string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/away":
+			http.Redirect(w, r, target, http.StatusFound)
+		case r.URL.Path == "/hop/0":
+			w.WriteHeader(http.StatusOK)
+		case strings.HasPrefix(r.URL.Path, "/hop/"):
+			n := strings.TrimPrefix(r.URL.Path, "/hop/")
+			http.Redirect(w, r, "/hop/"+string(rune(n[0]-1)), http.StatusFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestHTTPProbeRedirectPolicy(t *testing.T) {
+	srv := newRedirectServer(t, "https://evil.example.org/")
+
+	t.Run("follows and reports chain", func(t *testing.T) {
+		p := &HTTPProbe{URL: srv.URL + "/hop/2", Redirects: &RedirectPolicy{}}
+		res := p.Check(context.Background())
+
+		require.NoError(t, res.Err)
+		assert.Equal(t, srv.URL+"/hop/0", res.Details[DetailFinalURL])
+		assert.Equal(t, "2", res.Details[DetailRedirectCount])
+		assert.Equal(t, strings.Join([]string{srv.URL + "/hop/2", srv.URL + "/hop/1", srv.URL + "/hop/0"}, RedirectChainSeparator), res.Details[DetailRedirectChain])
+	})
+
+	t.Run("caps redirects", func(t *testing.T) {
+		p := &HTTPProbe{URL: srv.URL + "/hop/3", Redirects: &RedirectPolicy{MaxRedirects: 2}}
+		res := p.Check(context.Background())
+
+		require.Error(t, res.Err)
+		assert.True(t, errors.Is(res.Err, ErrCodeTooManyRedirects))
+		assert.Equal(t, "2", res.Details[DetailRedirectCount])
+	})
+
+	t.Run("does not follow", func(t *testing.T) {
+		p := &HTTPProbe{URL: srv.URL + "/hop/1", Redirects: &RedirectPolicy{MaxRedirects: -1}}
+		res := p.Check(context.Background())
+
+		require.NoError(t, res.Err)
+		assert.Equal(t, "302", res.Details["statusCode"])
+		assert.Equal(t, srv.URL+"/hop/1", res.Details[DetailFinalURL])
+		assert.Equal(t, "0", res.Details[DetailRedirectCount])
+	})
+
+	t.Run("validates every hop", func(t *testing.T) {
+		v, err := NewURLValidator(URLOptions{DeniedHosts: []string{"*.example.org"}})
+		require.NoError(t, err)
+
+		p := &HTTPProbe{URL: srv.URL + "/away", Redirects: &RedirectPolicy{Validator: v}}
+		res := p.Check(context.Background())
+
+		require.Error(t, res.Err)
+		assert.True(t, errors.Is(res.Err, ErrCodeRedirectNotAllowed))
+		assert.Equal(t, srv.URL+"/away"+RedirectChainSeparator+"https://evil.example.org/", res.Details[DetailRedirectChain])
+	})
+
+	t.Run("blocks private networks", func(t *testing.T) {
+		p := &HTTPProbe{URL: srv.URL + "/hop/0", Redirects: &RedirectPolicy{BlockPrivateNetworks: true}}
+		res := p.Check(context.Background())
+
+		require.Error(t, res.Err)
+		assert.True(t, errors.Is(res.Err, ErrCodePrivateNetwork))
+	})
+}
+
+func TestRedirectToPrivateNetwork(t *testing.T) {
+	internal := newRedirectServer(t, "")
+	srv := newRedirectServer(t, internal.URL+"/hop/0")
+
+	// Follow the redirect with the SSRF guard but skip the check of the
+	// (also loopback) initial URL by calling the tracker directly.
+	tracker := &redirectTracker{policy: &RedirectPolicy{BlockPrivateNetworks: true}, chain: []string{srv.URL + "/away"}}
+	client := tracker.client(http.DefaultClient)
+
+	resp, err := client.Get(srv.URL + "/away")
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	require.Error(t, err)
+
+	e, ok := codedError(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrorCode(ErrCodePrivateNetwork), e.Code)
+}
+
+func TestBlockInternalDial(t *testing.T) {
+	require.Error(t, blockInternalDial("tcp", "127.0.0.1:80", nil))
+	require.Error(t, blockInternalDial("tcp", "[::1]:80", nil))
+	require.NoError(t, blockInternalDial("tcp", "93.184.216.34:443", nil))
+}
+
+func TestIsInternalAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"192.168.0.1":     true,
+		"169.254.169.254": true,
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true,
+		"93.184.216.34":   false,
+		"2606:4700::1":    false,
+	} {
+		assert.Equal(t, want, isInternalAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestCheckURLReachabilityWithPolicy(t *testing.T) {
+	res := CheckURLReachabilityWithPolicy(context.Background(), "", RedirectPolicy{})
+	require.Error(t, res.Err)
+	assert.True(t, errors.Is(res.Err, ErrCodeEmptyURL))
+	assert.Equal(t, StatusUnhealthy, res.Status)
+
+	srv := newRedirectServer(t, "")
+	res = CheckURLReachabilityWithPolicy(context.Background(), srv.URL+"/hop/1", RedirectPolicy{MaxRedirects: 1})
+	require.NoError(t, res.Err)
+	assert.Equal(t, srv.URL+"/hop/0", res.Details[DetailFinalURL])
+}
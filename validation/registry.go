@@ -0,0 +1,370 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+	"github.com/kopexa-grc/common/errors"
+)
+
+// Error codes for the validator registry.
+const (
+	// ErrCodeValidatorNotFound indicates that no validator is registered under the requested name.
+	ErrCodeValidatorNotFound = "VALIDATION_VALIDATOR_NOT_FOUND"
+
+	// ErrCodeValidatorExists indicates that a validator with the same name is already registered.
+	ErrCodeValidatorExists = "VALIDATION_VALIDATOR_EXISTS"
+
+	// ErrCodeInvalidRegistrySpec indicates that a registry specification could not be parsed or applied.
+	ErrCodeInvalidRegistrySpec = "VALIDATION_INVALID_REGISTRY_SPEC"
+)
+
+// Validator types supported in a RegistrySpec.
+const (
+	// ValidatorTypeURL builds a URLValidator from the spec's URLOptions.
+	ValidatorTypeURL = "url"
+
+	// ValidatorTypePattern builds a PatternValidator from the spec's Pattern.
+	ValidatorTypePattern = "pattern"
+
+	// ValidatorTypeHTML builds an HTMLSanitizer from the spec's HTML policy.
+	ValidatorTypeHTML = "html"
+)
+
+// Registry holds named, pre-compiled validators.
+//
+// Validators are registered once at startup, so regular expressions and
+// allow-lists are compiled a single time, and are then looked up by name on
+// the request path. A Registry is safe for concurrent use.
+//
+// Example:
+//
+//	reg := validation.NewRegistry()
+//	_ = reg.RegisterURL("webhook-url", validation.URLOptions{Schemes: []string{"https"}})
+//
+//	if err := reg.Validate("webhook-url", input); err != nil {
+//		// handle validation error
+//	}
+//
+// The URL validators of a Registry enforce its profile, so the same
+// validators accept http://localhost in development but not in production;
+// see UseProfile.
+type Registry struct {
+	mu         sync.RWMutex
+	validators map[string]Validator
+	profiles   map[string]Profile
+	profile    Profile
+}
+
+// NewRegistry creates an empty Registry with the built-in profiles
+// ProfileDevelopment and ProfileProduction. No profile is in use.
+func NewRegistry() *Registry {
+	return &Registry{
+		validators: make(map[string]Validator),
+		profiles: map[string]Profile{
+			ProfileDevelopment: DevelopmentProfile(),
+			ProfileProduction:  ProductionProfile(),
+		},
+	}
+}
+
+// RegisterProfile adds or replaces the profile named p.Name, e.g. to
+// tighten a built-in profile. It returns an error with code
+// ErrCodeInvalidProfile if the profile is invalid.
+func (r *Registry) RegisterProfile(p Profile) error {
+	if p.Name == "" {
+		return errors.New(ErrCodeInvalidProfile, "Profile name is required")
+	}
+
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profiles[p.Name] = p
+
+	return nil
+}
+
+// Profile returns the profile registered under name.
+func (r *Registry) Profile(name string) (Profile, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.profiles[name]
+
+	return p, ok
+}
+
+// ActiveProfile returns the profile in use, or the zero Profile.
+func (r *Registry) ActiveProfile() Profile {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.profile
+}
+
+// UseProfile selects the profile registered under name for all URL
+// validators. Since validators are compiled on registration, it must be
+// called before the first validator is registered; otherwise an error with
+// code ErrCodeInvalidProfile is returned. An unknown name yields
+// ErrCodeProfileNotFound.
+//
+// Example:
+//
+//	reg := validation.NewRegistry()
+//	if err := reg.UseProfile(cfg.Environment); err != nil {
+//		return err
+//	}
+//	_ = reg.RegisterURL("webhook-url", validation.URLOptions{})
+func (r *Registry) UseProfile(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.profiles[name]
+	if !ok {
+		return errors.New(ErrCodeProfileNotFound, fmt.Sprintf("Profile '%s' is not registered", name))
+	}
+
+	if len(r.validators) > 0 {
+		return errors.New(ErrCodeInvalidProfile, "Profile must be selected before validators are registered")
+	}
+
+	r.profile = p
+
+	return nil
+}
+
+// Register adds a validator under name. It returns an error with code
+// ErrCodeValidatorExists if the name is already taken.
+func (r *Registry) Register(name string, v Validator) error {
+	if name == "" || v == nil {
+		return errors.New(ErrCodeInvalidValidatorOptions, "Validator name and validator are required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.validators[name]; ok {
+		return errors.New(ErrCodeValidatorExists, fmt.Sprintf("Validator '%s' is already registered", name))
+	}
+
+	r.validators[name] = v
+
+	return nil
+}
+
+// RegisterURL compiles opts and the active profile into a URLValidator and
+// registers it under name.
+func (r *Registry) RegisterURL(name string, opts URLOptions) error {
+	v, err := NewURLValidatorWithProfile(opts, r.ActiveProfile())
+	if err != nil {
+		return err
+	}
+
+	return r.Register(name, v)
+}
+
+// RegisterURLWithReputation compiles opts and the active profile into a
+// URLValidator that screens accepted URLs with checker and registers it
+// under name.
+func (r *Registry) RegisterURLWithReputation(name string, opts URLOptions, checker ReputationChecker, ropts ReputationOptions) error {
+	if checker == nil {
+		return errors.New(ErrCodeInvalidValidatorOptions, "Reputation checker is required")
+	}
+
+	v, err := NewURLValidatorWithProfile(opts, r.ActiveProfile())
+	if err != nil {
+		return err
+	}
+
+	return r.Register(name, NewReputationValidator(v, checker, ropts))
+}
+
+// RegisterPattern compiles pattern into a PatternValidator and registers it under name.
+func (r *Registry) RegisterPattern(name, pattern string) error {
+	v, err := NewPatternValidator(pattern)
+	if err != nil {
+		return err
+	}
+
+	return r.Register(name, v)
+}
+
+// RegisterHTML compiles policy into an HTMLSanitizer and registers it under name.
+func (r *Registry) RegisterHTML(name string, policy HTMLPolicy) error {
+	v, err := NewHTMLSanitizer(policy)
+	if err != nil {
+		return err
+	}
+
+	return r.Register(name, v)
+}
+
+// Get returns the validator registered under name.
+func (r *Registry) Get(name string) (Validator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.validators[name]
+
+	return v, ok
+}
+
+// Validate validates value with the validator registered under name. It
+// returns an error with code ErrCodeValidatorNotFound if there is none.
+func (r *Registry) Validate(name, value string) error {
+	v, ok := r.Get(name)
+	if !ok {
+		return errors.New(ErrCodeValidatorNotFound, fmt.Sprintf("Validator '%s' is not registered", name))
+	}
+
+	return v.Validate(value)
+}
+
+// Names returns the names of all registered validators in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.validators))
+	for name := range r.validators {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// ValidatorSpec describes a single validator in a RegistrySpec.
+// The URLOptions fields are used for ValidatorTypeURL, Pattern is used for
+// ValidatorTypePattern and HTML for ValidatorTypeHTML.
+type ValidatorSpec struct {
+	// Type is the validator type; defaults to ValidatorTypeURL.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Pattern is the regular expression for ValidatorTypePattern.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// HTML is the policy for ValidatorTypeHTML.
+	HTML HTMLPolicy `json:"html,omitempty" yaml:"html,omitempty"`
+
+	URLOptions `yaml:",inline"`
+}
+
+// RegistrySpec is the configuration format for building a Registry.
+//
+// Example (YAML):
+//
+//	profile: production
+//	profiles:
+//	  production:
+//	    schemes: [https]
+//	    privateNetworks: deny
+//	    checkReachability: true
+//	validators:
+//	  webhook-url:
+//	    schemes: [https]
+//	    deniedHosts: ["localhost", "*.internal"]
+//	  vendor-url:
+//	    allowedHosts: ["*.vendor.com"]
+//	  ticket-key:
+//	    type: pattern
+//	    pattern: "^[A-Z]+-[0-9]+$"
+//	  description:
+//	    type: html
+//	    html:
+//	      allowedTags: [p, a, strong]
+//	      allowedAttributes: {a: [href]}
+//	      allowedUrlSchemes: [https]
+type RegistrySpec struct {
+	// Profile is the name of the profile used by all URL validators.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty" koanf:"profile"`
+
+	// Profiles adds profiles or replaces built-in ones; the map key is the
+	// profile name.
+	Profiles map[string]Profile `json:"profiles,omitempty" yaml:"profiles,omitempty" koanf:"profiles"`
+
+	Validators map[string]ValidatorSpec `json:"validators" yaml:"validators"`
+}
+
+// NewRegistryFromSpec builds a Registry from spec. All validators are
+// compiled up front; the first invalid entry aborts construction.
+func NewRegistryFromSpec(spec RegistrySpec) (*Registry, error) {
+	r := NewRegistry()
+
+	for _, name := range slices.Sorted(maps.Keys(spec.Profiles)) {
+		p := spec.Profiles[name]
+		p.Name = name
+		if err := r.RegisterProfile(p); err != nil {
+			return nil, errors.New(ErrCodeInvalidRegistrySpec, fmt.Sprintf("Profile '%s': %v", name, err)).With(err)
+		}
+	}
+
+	if spec.Profile != "" {
+		if err := r.UseProfile(spec.Profile); err != nil {
+			return nil, errors.New(ErrCodeInvalidRegistrySpec, err.Error()).With(err)
+		}
+	}
+
+	names := make([]string, 0, len(spec.Validators))
+	for name := range spec.Validators {
+		names = append(names, name)
+	}
+
+	// Sort so that errors are reported deterministically.
+	slices.Sort(names)
+
+	for _, name := range names {
+		vs := spec.Validators[name]
+
+		var err error
+
+		switch vs.Type {
+		case "", ValidatorTypeURL:
+			err = r.RegisterURL(name, vs.URLOptions)
+		case ValidatorTypePattern:
+			err = r.RegisterPattern(name, vs.Pattern)
+		case ValidatorTypeHTML:
+			err = r.RegisterHTML(name, vs.HTML)
+		default:
+			err = errors.New(ErrCodeInvalidRegistrySpec, fmt.Sprintf("Unknown validator type '%s'", vs.Type))
+		}
+
+		if err != nil {
+			return nil, errors.New(ErrCodeInvalidRegistrySpec, fmt.Sprintf("Validator '%s': %v", name, err)).With(err)
+		}
+	}
+
+	return r, nil
+}
+
+// NewRegistryFromJSON parses a JSON RegistrySpec and builds a Registry from it.
+func NewRegistryFromJSON(data []byte) (*Registry, error) {
+	var spec RegistrySpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, errors.New(ErrCodeInvalidRegistrySpec, fmt.Sprintf("Failed to parse registry spec: %v", err))
+	}
+
+	return NewRegistryFromSpec(spec)
+}
+
+// NewRegistryFromYAML parses a YAML RegistrySpec and builds a Registry from it.
+func NewRegistryFromYAML(data []byte) (*Registry, error) {
+	var spec RegistrySpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, errors.New(ErrCodeInvalidRegistrySpec, fmt.Sprintf("Failed to parse registry spec: %v", err))
+	}
+
+	return NewRegistryFromSpec(spec)
+}
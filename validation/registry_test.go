@@ -0,0 +1,172 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLValidator(t *testing.T) {
+	v, err := NewURLValidator(URLOptions{
+		Schemes:      []string{"https"},
+		AllowedHosts: []string{"vendor.com", "*.vendor.com"},
+		DeniedHosts:  []string{"internal.vendor.com"},
+		PathPattern:  `^/(api|hooks)/`,
+		MaxLength:    100,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		input     string
+		errorCode string
+	}{
+		{name: "allowed apex", input: "https://vendor.com/api/v1"},
+		{name: "allowed subdomain", input: "https://eu.vendor.com/hooks/x"},
+		{name: "case insensitive host", input: "https://EU.Vendor.com/api/"},
+		{name: "http not allowed", input: "http://vendor.com/api/", errorCode: ErrCodeUnsupportedScheme},
+		{name: "scheme-less assumes http", input: "vendor.com/api/", errorCode: ErrCodeUnsupportedScheme},
+		{name: "host not on allow-list", input: "https://evil.com/api/", errorCode: ErrCodeHostNotAllowed},
+		{name: "suffix trick", input: "https://evilvendor.com/api/", errorCode: ErrCodeHostNotAllowed},
+		{name: "denied host", input: "https://internal.vendor.com/api/", errorCode: ErrCodeHostNotAllowed},
+		{name: "path mismatch", input: "https://vendor.com/admin", errorCode: ErrCodePatternMismatch},
+		{name: "too long", input: "https://vendor.com/api/" + string(make([]byte, 100)), errorCode: ErrCodeURLTooLong},
+		{name: "empty", input: "", errorCode: ErrCodeEmptyURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.input)
+			if tt.errorCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, errors.ErrorCode(tt.errorCode)), "got %v", err)
+		})
+	}
+}
+
+func TestNewURLValidator_InvalidOptions(t *testing.T) {
+	_, err := NewURLValidator(URLOptions{Schemes: []string{"ftp"}})
+	assert.True(t, errors.Is(err, ErrCodeInvalidValidatorOptions))
+
+	_, err = NewURLValidator(URLOptions{PathPattern: "("})
+	assert.True(t, errors.Is(err, ErrCodeInvalidValidatorOptions))
+
+	_, err = NewURLValidator(URLOptions{MaxLength: MaxURLLength + 1})
+	assert.True(t, errors.Is(err, ErrCodeInvalidValidatorOptions))
+}
+
+func TestPatternValidator(t *testing.T) {
+	v, err := NewPatternValidator(`^[A-Z]+-\d+$`)
+	require.NoError(t, err)
+
+	assert.NoError(t, v.Validate("GRC-42"))
+	assert.True(t, errors.Is(v.Validate("grc-42"), ErrCodePatternMismatch))
+
+	_, err = NewPatternValidator("")
+	assert.True(t, errors.Is(err, ErrCodeInvalidValidatorOptions))
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+
+	require.NoError(t, reg.RegisterURL("webhook-url", URLOptions{Schemes: []string{"https"}}))
+	require.NoError(t, reg.RegisterPattern("ticket-key", `^[A-Z]+-\d+$`))
+	require.NoError(t, reg.Register("non-empty", ValidatorFunc(func(s string) error {
+		if s == "" {
+			return errors.New(ErrCodePatternMismatch, "empty")
+		}
+
+		return nil
+	})))
+
+	assert.Equal(t, []string{"non-empty", "ticket-key", "webhook-url"}, reg.Names())
+
+	assert.NoError(t, reg.Validate("webhook-url", "https://example.com/hook"))
+	assert.True(t, errors.Is(reg.Validate("webhook-url", "http://example.com/hook"), ErrCodeUnsupportedScheme))
+	assert.NoError(t, reg.Validate("ticket-key", "GRC-1"))
+	assert.Error(t, reg.Validate("non-empty", ""))
+
+	assert.True(t, errors.Is(reg.Validate("unknown", "x"), ErrCodeValidatorNotFound))
+	assert.True(t, errors.Is(reg.RegisterPattern("ticket-key", ".*"), ErrCodeValidatorExists))
+	assert.True(t, errors.Is(reg.Register("", nil), ErrCodeInvalidValidatorOptions))
+
+	_, ok := reg.Get("webhook-url")
+	assert.True(t, ok)
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	reg := NewRegistry()
+	require.NoError(t, reg.RegisterURL("vendor-url", URLOptions{AllowedHosts: []string{"*.vendor.com"}}))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, reg.Validate("vendor-url", "https://a.vendor.com"))
+		}()
+
+		go func(i int) {
+			defer wg.Done()
+
+			assert.NoError(t, reg.RegisterPattern(fmt.Sprintf("p-%d", i), ".*"))
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Len(t, reg.Names(), 51)
+}
+
+func TestNewRegistryFromYAML(t *testing.T) {
+	spec := []byte(`
+validators:
+  webhook-url:
+    schemes: [https]
+    deniedHosts: ["localhost", "*.internal"]
+  vendor-url:
+    type: url
+    allowedHosts: ["*.vendor.com"]
+  ticket-key:
+    type: pattern
+    pattern: "^[A-Z]+-[0-9]+$"
+`)
+
+	reg, err := NewRegistryFromYAML(spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ticket-key", "vendor-url", "webhook-url"}, reg.Names())
+	assert.NoError(t, reg.Validate("webhook-url", "https://example.com"))
+	assert.True(t, errors.Is(reg.Validate("webhook-url", "https://db.internal"), ErrCodeHostNotAllowed))
+	assert.True(t, errors.Is(reg.Validate("vendor-url", "https://example.com"), ErrCodeHostNotAllowed))
+	assert.NoError(t, reg.Validate("ticket-key", "GRC-7"))
+}
+
+func TestNewRegistryFromJSON(t *testing.T) {
+	reg, err := NewRegistryFromJSON([]byte(`{"validators":{"webhook-url":{"schemes":["https"],"maxLength":64}}}`))
+	require.NoError(t, err)
+
+	assert.True(t, errors.Is(reg.Validate("webhook-url", "http://example.com"), ErrCodeUnsupportedScheme))
+
+	_, err = NewRegistryFromJSON([]byte(`{"validators":{"x":{"type":"unknown"}}}`))
+	assert.True(t, errors.Is(err, ErrCodeInvalidRegistrySpec))
+
+	_, err = NewRegistryFromJSON([]byte(`{"validators":{"x":{"pathPattern":"("}}}`))
+	assert.True(t, errors.Is(err, ErrCodeInvalidRegistrySpec))
+
+	_, err = NewRegistryFromJSON([]byte(`not json`))
+	assert.True(t, errors.Is(err, ErrCodeInvalidRegistrySpec))
+}
@@ -0,0 +1,395 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kopexa-grc/common/errors"
+)
+
+// Error codes for reputation checks.
+const (
+	// ErrCodeUnsafeURL indicates that a URL is listed as malicious by a
+	// reputation source, e.g. as phishing or malware site.
+	ErrCodeUnsafeURL = "VALIDATION_UNSAFE_URL"
+
+	// ErrCodeReputationCheckFailed indicates that the reputation of a URL
+	// could not be determined, e.g. because the lookup service is unavailable.
+	ErrCodeReputationCheckFailed = "VALIDATION_REPUTATION_CHECK_FAILED"
+)
+
+// DetailThreats is the Details key listing the threats of an unsafe URL.
+const DetailThreats = "threats"
+
+// ThreatType classifies why a URL is considered unsafe. The values match the
+// threat types of Google Safe Browsing.
+type ThreatType string
+
+const (
+	// ThreatMalware marks sites distributing malware.
+	ThreatMalware ThreatType = "MALWARE"
+	// ThreatSocialEngineering marks phishing and other deceptive sites.
+	ThreatSocialEngineering ThreatType = "SOCIAL_ENGINEERING"
+	// ThreatUnwantedSoftware marks sites distributing unwanted software.
+	ThreatUnwantedSoftware ThreatType = "UNWANTED_SOFTWARE"
+	// ThreatPotentiallyHarmful marks potentially harmful applications.
+	ThreatPotentiallyHarmful ThreatType = "POTENTIALLY_HARMFUL_APPLICATION"
+)
+
+// Defaults for CachedReputationChecker.
+const (
+	// DefaultReputationCacheSize is the number of cached verdicts.
+	DefaultReputationCacheSize = 10000
+	// DefaultReputationCacheTTL is how long a verdict is reused.
+	DefaultReputationCacheTTL = 30 * time.Minute
+	// DefaultReputationTimeout bounds a lookup made by ReputationValidator.
+	DefaultReputationTimeout = DefaultHTTPTimeout
+)
+
+// ReputationResult is the verdict of a reputation lookup.
+type ReputationResult struct {
+	// Threats lists the threats the URL is known for; empty if it is safe.
+	Threats []ThreatType `json:"threats,omitempty"`
+	// Source names the checker that reported the threats.
+	Source string `json:"source,omitempty"`
+}
+
+// Safe reports whether no threats were found.
+func (r ReputationResult) Safe() bool {
+	return len(r.Threats) == 0
+}
+
+// ReputationChecker looks up the reputation of URLs, e.g. against phishing
+// and malware blocklists.
+//
+// Implementations must be safe for concurrent use.
+type ReputationChecker interface {
+	// CheckURL returns the verdict for rawURL. An error means the reputation
+	// could not be determined, not that the URL is unsafe.
+	CheckURL(ctx context.Context, rawURL string) (ReputationResult, error)
+}
+
+// ReputationCheckerFunc adapts an ordinary function to the ReputationChecker
+// interface.
+type ReputationCheckerFunc func(ctx context.Context, rawURL string) (ReputationResult, error)
+
+// CheckURL calls f(ctx, rawURL).
+func (f ReputationCheckerFunc) CheckURL(ctx context.Context, rawURL string) (ReputationResult, error) {
+	return f(ctx, rawURL)
+}
+
+// reputationHost returns the lower-case host of rawURL, assuming http for
+// URLs without scheme like IsValidURL does.
+func reputationHost(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err == nil && u.Scheme == "" {
+		u, err = url.Parse("http://" + rawURL)
+	}
+
+	if err != nil {
+		return "", errors.New(ErrCodeInvalidURL, fmt.Sprintf("URL parsing failed: %v", err))
+	}
+
+	return strings.ToLower(strings.TrimSuffix(u.Hostname(), ".")), nil
+}
+
+// StaticBlocklist is a local list of blocked domains. An entry blocks the
+// domain and all of its subdomains. It is safe for concurrent use.
+type StaticBlocklist struct {
+	threat  ThreatType
+	domains map[string]struct{}
+}
+
+// NewStaticBlocklist creates a blocklist reporting threat for domains.
+func NewStaticBlocklist(threat ThreatType, domains ...string) *StaticBlocklist {
+	b := &StaticBlocklist{threat: threat, domains: make(map[string]struct{}, len(domains))}
+
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(d), "."))
+		d = strings.TrimPrefix(d, "*.")
+
+		if d != "" {
+			b.domains[d] = struct{}{}
+		}
+	}
+
+	return b
+}
+
+// ParseStaticBlocklist reads a blocklist with one domain per line. Empty
+// lines and lines starting with '#' are ignored, as are hosts-file style
+// address prefixes such as "0.0.0.0 evil.example".
+func ParseStaticBlocklist(r io.Reader, threat ThreatType) (*StaticBlocklist, error) {
+	var domains []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		domains = append(domains, fields[len(fields)-1])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("Failed to read blocklist: %v", err))
+	}
+
+	return NewStaticBlocklist(threat, domains...), nil
+}
+
+// Len returns the number of blocked domains.
+func (b *StaticBlocklist) Len() int {
+	return len(b.domains)
+}
+
+// CheckURL implements ReputationChecker.
+func (b *StaticBlocklist) CheckURL(_ context.Context, rawURL string) (ReputationResult, error) {
+	host, err := reputationHost(rawURL)
+	if err != nil {
+		return ReputationResult{}, err
+	}
+
+	for d := host; d != ""; {
+		if _, ok := b.domains[d]; ok {
+			return ReputationResult{Threats: []ThreatType{b.threat}, Source: "blocklist"}, nil
+		}
+
+		_, parent, found := strings.Cut(d, ".")
+		if !found {
+			break
+		}
+
+		d = parent
+	}
+
+	return ReputationResult{}, nil
+}
+
+// ReputationCheckers combines checkers into one that consults them in order
+// and returns the first unsafe verdict. Cheap local checkers should come
+// first, so that remote lookups are skipped for known bad URLs.
+type ReputationCheckers []ReputationChecker
+
+// CheckURL implements ReputationChecker.
+func (c ReputationCheckers) CheckURL(ctx context.Context, rawURL string) (ReputationResult, error) {
+	for _, checker := range c {
+		res, err := checker.CheckURL(ctx, rawURL)
+		if err != nil {
+			return ReputationResult{}, err
+		}
+
+		if !res.Safe() {
+			return res, nil
+		}
+	}
+
+	return ReputationResult{}, nil
+}
+
+// CachedReputationChecker caches the verdicts of another checker with LRU
+// eviction and a TTL, so that repeated validations of the same URL do not
+// hit the lookup service. Errors are not cached. It is safe for concurrent
+// use.
+type CachedReputationChecker struct {
+	checker ReputationChecker
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front is most recently used
+}
+
+// reputationEntry is a cached verdict.
+type reputationEntry struct {
+	url       string
+	result    ReputationResult
+	expiresAt time.Time
+}
+
+// NewCachedReputationChecker wraps checker with a cache of size verdicts
+// kept for ttl. Non-positive values use DefaultReputationCacheSize and
+// DefaultReputationCacheTTL.
+func NewCachedReputationChecker(checker ReputationChecker, size int, ttl time.Duration) *CachedReputationChecker {
+	if size <= 0 {
+		size = DefaultReputationCacheSize
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultReputationCacheTTL
+	}
+
+	return &CachedReputationChecker{
+		checker: checker,
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// CheckURL implements ReputationChecker.
+func (c *CachedReputationChecker) CheckURL(ctx context.Context, rawURL string) (ReputationResult, error) {
+	if res, ok := c.cached(rawURL); ok {
+		return res, nil
+	}
+
+	res, err := c.checker.CheckURL(ctx, rawURL)
+	if err != nil {
+		return ReputationResult{}, err
+	}
+
+	c.store(rawURL, res)
+
+	return res, nil
+}
+
+// Len returns the number of cached verdicts.
+func (c *CachedReputationChecker) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// cached returns the unexpired verdict for rawURL.
+func (c *CachedReputationChecker) cached(rawURL string) (ReputationResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[rawURL]
+	if !ok {
+		return ReputationResult{}, false
+	}
+
+	entry := el.Value.(*reputationEntry) //nolint:forcetypeassert // only *reputationEntry is stored
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(el)
+		return ReputationResult{}, false
+	}
+
+	c.lru.MoveToFront(el)
+
+	return entry.result, true
+}
+
+// store caches the verdict for rawURL and evicts the least recently used
+// verdicts beyond the cache size.
+func (c *CachedReputationChecker) store(rawURL string, res ReputationResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[rawURL]; ok {
+		c.remove(el)
+	}
+
+	c.entries[rawURL] = c.lru.PushFront(&reputationEntry{
+		url:       rawURL,
+		result:    res,
+		expiresAt: c.now().Add(c.ttl),
+	})
+
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove drops el from the cache. The caller must hold c.mu.
+func (c *CachedReputationChecker) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*reputationEntry).url) //nolint:forcetypeassert // only *reputationEntry is stored
+}
+
+// ReputationOptions configures a ReputationValidator.
+type ReputationOptions struct {
+	// Timeout bounds a single lookup. Defaults to DefaultReputationTimeout.
+	Timeout time.Duration
+	// FailOpen accepts URLs whose reputation could not be determined. By
+	// default such URLs are rejected with ErrCodeReputationCheckFailed.
+	FailOpen bool
+}
+
+// ReputationValidator screens URLs against a ReputationChecker after they
+// passed a base validator, e.g. a URLValidator. It is safe for concurrent
+// use if the checker is.
+//
+// Example:
+//
+//	checker := validation.ReputationCheckers{
+//		validation.NewStaticBlocklist(validation.ThreatSocialEngineering, "phish.example"),
+//		validation.NewSafeBrowsingChecker(validation.SafeBrowsingConfig{APIKey: key}),
+//	}
+//	v := validation.NewReputationValidator(urlValidator,
+//		validation.NewCachedReputationChecker(checker, 0, 0), validation.ReputationOptions{})
+type ReputationValidator struct {
+	base    Validator
+	checker ReputationChecker
+	opts    ReputationOptions
+}
+
+// NewReputationValidator creates a ReputationValidator. A nil base validator
+// defaults to IsValidURL.
+func NewReputationValidator(base Validator, checker ReputationChecker, opts ReputationOptions) *ReputationValidator {
+	if base == nil {
+		base = ValidatorFunc(IsValidURL)
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultReputationTimeout
+	}
+
+	return &ReputationValidator{base: base, checker: checker, opts: opts}
+}
+
+// Validate implements Validator.
+func (v *ReputationValidator) Validate(rawURL string) error {
+	return v.ValidateContext(context.Background(), rawURL)
+}
+
+// ValidateContext validates rawURL with the base validator and then checks
+// its reputation within the deadline of ctx. Unsafe URLs are rejected with
+// ErrCodeUnsafeURL and the threats in Details under DetailThreats.
+func (v *ReputationValidator) ValidateContext(ctx context.Context, rawURL string) error {
+	if err := v.base.Validate(rawURL); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, v.opts.Timeout)
+	defer cancel()
+
+	res, err := v.checker.CheckURL(ctx, rawURL)
+	if err != nil {
+		if v.opts.FailOpen {
+			return nil
+		}
+
+		return errors.New(ErrCodeReputationCheckFailed, "URL reputation could not be determined").With(err)
+	}
+
+	if res.Safe() {
+		return nil
+	}
+
+	threats := make([]string, len(res.Threats))
+	for i, t := range res.Threats {
+		threats[i] = string(t)
+	}
+
+	return errors.New(ErrCodeUnsafeURL, fmt.Sprintf("URL is listed as unsafe (%s)", strings.Join(threats, ", "))).
+		WithDetails(DetailThreats, threats)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticBlocklist(t *testing.T) {
+	b, err := ParseStaticBlocklist(strings.NewReader(`
+# phishing domains
+phish.example
+0.0.0.0 Malware.Example.
+*.wild.example
+`), ThreatSocialEngineering)
+	require.NoError(t, err)
+	assert.Equal(t, 3, b.Len())
+
+	tests := []struct {
+		url    string
+		unsafe bool
+	}{
+		{url: "https://phish.example/login", unsafe: true},
+		{url: "https://login.phish.example", unsafe: true},
+		{url: "malware.example/x", unsafe: true},
+		{url: "https://wild.example", unsafe: true},
+		{url: "https://notphish.example", unsafe: false},
+		{url: "https://phish.example.com", unsafe: false},
+		{url: "https://vendor.com", unsafe: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			res, err := b.CheckURL(context.Background(), tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.unsafe, !res.Safe())
+
+			if tt.unsafe {
+				assert.Equal(t, []ThreatType{ThreatSocialEngineering}, res.Threats)
+			}
+		})
+	}
+}
+
+func TestReputationCheckers(t *testing.T) {
+	var remote atomic.Int32
+
+	checkers := ReputationCheckers{
+		NewStaticBlocklist(ThreatMalware, "malware.example"),
+		ReputationCheckerFunc(func(context.Context, string) (ReputationResult, error) {
+			remote.Add(1)
+			return ReputationResult{}, nil
+		}),
+	}
+
+	res, err := checkers.CheckURL(context.Background(), "https://malware.example")
+	require.NoError(t, err)
+	assert.False(t, res.Safe())
+	assert.Equal(t, int32(0), remote.Load())
+
+	res, err = checkers.CheckURL(context.Background(), "https://vendor.com")
+	require.NoError(t, err)
+	assert.True(t, res.Safe())
+	assert.Equal(t, int32(1), remote.Load())
+}
+
+func TestCachedReputationChecker(t *testing.T) {
+	var calls atomic.Int32
+
+	fail := false
+	inner := ReputationCheckerFunc(func(_ context.Context, rawURL string) (ReputationResult, error) {
+		calls.Add(1)
+
+		if fail {
+			return ReputationResult{}, stderrors.New("unavailable")
+		}
+
+		if strings.Contains(rawURL, "bad") {
+			return ReputationResult{Threats: []ThreatType{ThreatMalware}}, nil
+		}
+
+		return ReputationResult{}, nil
+	})
+
+	now := time.Now()
+	c := NewCachedReputationChecker(inner, 2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	for range 3 {
+		res, err := c.CheckURL(ctx, "https://bad.example")
+		require.NoError(t, err)
+		assert.False(t, res.Safe())
+	}
+
+	assert.Equal(t, int32(1), calls.Load())
+
+	// LRU eviction
+	_, _ = c.CheckURL(ctx, "https://a.example")
+	_, _ = c.CheckURL(ctx, "https://b.example")
+	assert.Equal(t, 2, c.Len())
+
+	_, _ = c.CheckURL(ctx, "https://bad.example")
+	assert.Equal(t, int32(4), calls.Load())
+
+	// expired verdicts are looked up again; errors are not cached
+	now = now.Add(2 * time.Minute)
+	fail = true
+
+	_, err := c.CheckURL(ctx, "https://bad.example")
+	require.Error(t, err)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestReputationValidator(t *testing.T) {
+	base, err := NewURLValidator(URLOptions{Schemes: []string{"https"}})
+	require.NoError(t, err)
+
+	var checkErr error
+
+	checker := ReputationCheckers{
+		NewStaticBlocklist(ThreatSocialEngineering, "phish.example"),
+		ReputationCheckerFunc(func(context.Context, string) (ReputationResult, error) {
+			return ReputationResult{}, checkErr
+		}),
+	}
+
+	v := NewReputationValidator(base, checker, ReputationOptions{})
+
+	require.NoError(t, v.Validate("https://vendor.com"))
+	assert.True(t, errors.Is(v.Validate("http://vendor.com"), ErrCodeUnsupportedScheme))
+
+	err = v.Validate("https://phish.example/login")
+	require.True(t, errors.Is(err, ErrCodeUnsafeURL), "got %v", err)
+
+	var e *errors.Error
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, []string{string(ThreatSocialEngineering)}, e.Details[DetailThreats])
+
+	checkErr = stderrors.New("unavailable")
+	assert.True(t, errors.Is(v.Validate("https://vendor.com"), ErrCodeReputationCheckFailed))
+
+	failOpen := NewReputationValidator(base, checker, ReputationOptions{FailOpen: true})
+	require.NoError(t, failOpen.Validate("https://vendor.com"))
+	assert.True(t, errors.Is(failOpen.Validate("https://phish.example"), ErrCodeUnsafeURL))
+}
+
+func TestRegistry_RegisterURLWithReputation(t *testing.T) {
+	reg := NewRegistry()
+
+	err := reg.RegisterURLWithReputation("vendor-url", URLOptions{},
+		NewStaticBlocklist(ThreatMalware, "malware.example"), ReputationOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, reg.Validate("vendor-url", "https://vendor.com"))
+	assert.True(t, errors.Is(reg.Validate("vendor-url", "https://malware.example"), ErrCodeUnsafeURL))
+
+	err = reg.RegisterURLWithReputation("other", URLOptions{}, nil, ReputationOptions{})
+	assert.True(t, errors.Is(err, ErrCodeInvalidValidatorOptions))
+}
+
+func TestSafeBrowsingChecker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "secret", r.URL.Query().Get("key"))
+
+		var req safeBrowsingRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "kopexa", req.Client.ClientID)
+		assert.Equal(t, defaultSafeBrowsingThreats, req.ThreatInfo.ThreatTypes)
+		require.Len(t, req.ThreatInfo.ThreatEntries, 1)
+
+		switch req.ThreatInfo.ThreatEntries[0]["url"] {
+		case "https://phish.example/":
+			_, _ = w.Write([]byte(`{"matches":[
+				{"threatType":"SOCIAL_ENGINEERING","platformType":"ANY_PLATFORM","threat":{"url":"https://phish.example/"}},
+				{"threatType":"SOCIAL_ENGINEERING","platformType":"WINDOWS","threat":{"url":"https://phish.example/"}}
+			]}`))
+		case "https://down.example/":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewSafeBrowsingChecker(SafeBrowsingConfig{APIKey: "secret", Endpoint: srv.URL, Client: srv.Client()})
+	ctx := context.Background()
+
+	res, err := c.CheckURL(ctx, "https://phish.example/")
+	require.NoError(t, err)
+	assert.Equal(t, []ThreatType{ThreatSocialEngineering}, res.Threats)
+	assert.Equal(t, "safebrowsing", res.Source)
+
+	res, err = c.CheckURL(ctx, "https://vendor.com/")
+	require.NoError(t, err)
+	assert.True(t, res.Safe())
+
+	_, err = c.CheckURL(ctx, "https://down.example/")
+	assert.True(t, errors.Is(err, ErrCodeNonSuccessStatusCode))
+
+	_, err = NewSafeBrowsingChecker(SafeBrowsingConfig{}).CheckURL(ctx, "https://vendor.com/")
+	assert.True(t, errors.Is(err, ErrCodeInvalidValidatorOptions))
+}
@@ -0,0 +1,183 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/kopexa-grc/common/errors"
+)
+
+// Error codes for response assertions.
+const (
+	// ErrCodeResponseTooLarge indicates that the response body exceeds the
+	// configured maximum size.
+	ErrCodeResponseTooLarge = "VALIDATION_RESPONSE_TOO_LARGE"
+
+	// ErrCodeUnexpectedContentType indicates that the response has a content
+	// type that is not accepted.
+	ErrCodeUnexpectedContentType = "VALIDATION_UNEXPECTED_CONTENT_TYPE"
+
+	// ErrCodeResponseBodyMismatch indicates that the response body does not
+	// match the configured body matcher.
+	ErrCodeResponseBodyMismatch = "VALIDATION_RESPONSE_BODY_MISMATCH"
+
+	// ErrCodeResponseReadFailed indicates that the response body could not
+	// be read.
+	ErrCodeResponseReadFailed = "VALIDATION_RESPONSE_READ_FAILED"
+)
+
+// DefaultMaxResponseBytes is the body size limit of a ResponseAssertions
+// without MaxBodyBytes.
+const DefaultMaxResponseBytes = 64 << 10
+
+// Detail keys reported by HTTPProbe when ResponseAssertions are set.
+const (
+	// DetailContentType is the media type of the response.
+	DetailContentType = "contentType"
+	// DetailBodyBytes is the number of body bytes read.
+	DetailBodyBytes = "bodyBytes"
+)
+
+// StatusRange is an inclusive range of HTTP status codes, e.g. {200, 299}.
+type StatusRange struct {
+	Min int `json:"min" yaml:"min"`
+	Max int `json:"max" yaml:"max"`
+}
+
+// Contains reports whether code is within the range.
+func (r StatusRange) Contains(code int) bool {
+	return code >= r.Min && code <= r.Max
+}
+
+// BodyMatcher verifies the body of a response, e.g. that a health endpoint
+// reports "ok". It returns an error describing the mismatch.
+type BodyMatcher interface {
+	MatchBody(body []byte) error
+}
+
+// BodyMatcherFunc adapts a function to the BodyMatcher interface.
+type BodyMatcherFunc func(body []byte) error
+
+// MatchBody implements BodyMatcher.
+func (f BodyMatcherFunc) MatchBody(body []byte) error {
+	return f(body)
+}
+
+// BodyContains returns a BodyMatcher that requires the body to contain s.
+func BodyContains(s string) BodyMatcher {
+	return BodyMatcherFunc(func(body []byte) error {
+		if !bytes.Contains(body, []byte(s)) {
+			return fmt.Errorf("body does not contain %q", s)
+		}
+
+		return nil
+	})
+}
+
+// BodyMatchesRegexp returns a BodyMatcher that requires the body to match re.
+func BodyMatchesRegexp(re *regexp.Regexp) BodyMatcher {
+	return BodyMatcherFunc(func(body []byte) error {
+		if !re.Match(body) {
+			return fmt.Errorf("body does not match %q", re.String())
+		}
+
+		return nil
+	})
+}
+
+// ResponseAssertions make HTTPProbe read the response and verify it beyond
+// the status code. A HEAD request may succeed while GET returns an error
+// page, so a probe with assertions defaults to GET. The body is read up to
+// MaxBodyBytes, so a misbehaving endpoint cannot exhaust memory.
+type ResponseAssertions struct {
+	// MaxBodyBytes fails the check if the body is larger. Zero defaults to
+	// DefaultMaxResponseBytes.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty"`
+
+	// ContentTypes lists the accepted media types, e.g. "application/json".
+	// Parameters such as the charset are ignored, and a subtype "*" accepts
+	// every subtype, e.g. "text/*". Empty accepts every content type.
+	ContentTypes []string `json:"contentTypes,omitempty" yaml:"contentTypes,omitempty"`
+
+	// Body verifies the response body if set.
+	Body BodyMatcher `json:"-" yaml:"-"`
+}
+
+// maxBodyBytes returns the configured limit or DefaultMaxResponseBytes.
+func (a *ResponseAssertions) maxBodyBytes() int64 {
+	if a.MaxBodyBytes > 0 {
+		return a.MaxBodyBytes
+	}
+
+	return DefaultMaxResponseBytes
+}
+
+// check verifies resp and records the content type and body size in details.
+func (a *ResponseAssertions) check(resp *http.Response, details map[string]string) error {
+	mediaType := ""
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		if mt, _, err := mime.ParseMediaType(ct); err == nil {
+			mediaType = mt
+		}
+	}
+
+	details[DetailContentType] = mediaType
+
+	if len(a.ContentTypes) > 0 && !matchesContentType(mediaType, a.ContentTypes) {
+		return errors.New(ErrCodeUnexpectedContentType, fmt.Sprintf("Unexpected content type '%s', expected one of %v", mediaType, a.ContentTypes))
+	}
+
+	limit := a.maxBodyBytes()
+	if resp.ContentLength > limit {
+		return errors.New(ErrCodeResponseTooLarge, fmt.Sprintf("Response body of %d bytes exceeds limit of %d bytes", resp.ContentLength, limit))
+	}
+
+	// read one byte more than allowed to detect oversized bodies
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	details[DetailBodyBytes] = fmt.Sprint(len(body))
+
+	if err != nil {
+		return errors.New(ErrCodeResponseReadFailed, fmt.Sprintf("Failed to read response body: %v", err))
+	}
+
+	if int64(len(body)) > limit {
+		return errors.New(ErrCodeResponseTooLarge, fmt.Sprintf("Response body exceeds limit of %d bytes", limit))
+	}
+
+	if a.Body != nil {
+		if err := a.Body.MatchBody(body); err != nil {
+			return errors.New(ErrCodeResponseBodyMismatch, fmt.Sprintf("Response body mismatch: %v", err))
+		}
+	}
+
+	return nil
+}
+
+// matchesContentType reports whether mediaType is one of accepted.
+func matchesContentType(mediaType string, accepted []string) bool {
+	for _, a := range accepted {
+		a = strings.ToLower(strings.TrimSpace(a))
+
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+
+			continue
+		}
+
+		if mediaType == a {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kopexa-grc/common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPProbe_ResponseAssertions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/health":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		case "/large":
+			w.Header().Set("Content-Type", "text/plain")
+			// no Content-Length, the limit must hold while reading
+			for range 10 {
+				_, _ = w.Write([]byte(strings.Repeat("x", 1024)))
+				w.(http.Flusher).Flush()
+			}
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		probe   HTTPProbe
+		code    string
+		details map[string]string
+	}{
+		{
+			name: "healthy json",
+			probe: HTTPProbe{URL: srv.URL + "/health", Response: &ResponseAssertions{
+				ContentTypes: []string{"application/json"},
+				Body:         BodyContains(`"status":"ok"`),
+			}},
+			details: map[string]string{DetailContentType: "application/json", DetailBodyBytes: "15"},
+		},
+		{
+			name: "wildcard content type",
+			probe: HTTPProbe{URL: srv.URL + "/health", Response: &ResponseAssertions{
+				ContentTypes: []string{"text/*", "application/*"},
+			}},
+		},
+		{
+			name: "unexpected content type",
+			probe: HTTPProbe{URL: srv.URL + "/health", Response: &ResponseAssertions{
+				ContentTypes: []string{"text/html"},
+			}},
+			code: ErrCodeUnexpectedContentType,
+		},
+		{
+			name: "body mismatch",
+			probe: HTTPProbe{URL: srv.URL + "/health", Response: &ResponseAssertions{
+				Body: BodyMatchesRegexp(regexp.MustCompile(`"status":"(up|healthy)"`)),
+			}},
+			code: ErrCodeResponseBodyMismatch,
+		},
+		{
+			name:  "body too large",
+			probe: HTTPProbe{URL: srv.URL + "/large", Response: &ResponseAssertions{MaxBodyBytes: 4096}},
+			code:  ErrCodeResponseTooLarge,
+		},
+		{
+			name:  "body within limit",
+			probe: HTTPProbe{URL: srv.URL + "/large", Response: &ResponseAssertions{MaxBodyBytes: 10 * 1024}},
+		},
+		{
+			name:  "status range",
+			probe: HTTPProbe{URL: srv.URL + "/created", ExpectedStatusRanges: []StatusRange{{Min: 200, Max: 201}}, Response: &ResponseAssertions{}},
+		},
+		{
+			name:  "status outside range",
+			probe: HTTPProbe{URL: srv.URL + "/created", ExpectedStatusRanges: []StatusRange{{Min: 200, Max: 200}}, Response: &ResponseAssertions{}},
+			code:  ErrCodeNonSuccessStatusCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := tt.probe.Check(t.Context())
+
+			if tt.code == "" {
+				assert.Equal(t, StatusHealthy, res.Status, res.Error)
+			} else {
+				assert.Equal(t, StatusUnhealthy, res.Status)
+				assert.Equal(t, tt.code, string(errors.Code(res.Err)))
+			}
+
+			for k, v := range tt.details {
+				assert.Equal(t, v, res.Details[k], k)
+			}
+		})
+	}
+}
+
+func TestStatusRange_Contains(t *testing.T) {
+	r := StatusRange{Min: 200, Max: 299}
+
+	assert.True(t, r.Contains(200))
+	assert.True(t, r.Contains(299))
+	assert.False(t, r.Contains(300))
+}
@@ -0,0 +1,162 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/kopexa-grc/common/errors"
+)
+
+// DefaultSafeBrowsingEndpoint is the Google Safe Browsing v4 Lookup API.
+const DefaultSafeBrowsingEndpoint = "https://safebrowsing.googleapis.com/v4/threatMatches:find"
+
+// maxSafeBrowsingResponse bounds the size of a lookup response.
+const maxSafeBrowsingResponse = 1 << 20
+
+// defaultSafeBrowsingThreats are looked up if SafeBrowsingConfig.ThreatTypes is empty.
+var defaultSafeBrowsingThreats = []ThreatType{
+	ThreatMalware,
+	ThreatSocialEngineering,
+	ThreatUnwantedSoftware,
+	ThreatPotentiallyHarmful,
+}
+
+// SafeBrowsingConfig configures a SafeBrowsingChecker.
+type SafeBrowsingConfig struct {
+	// APIKey is the Google API key with the Safe Browsing API enabled.
+	APIKey string `json:"-" yaml:"-"`
+	// ClientID identifies the client to Google; defaults to "kopexa".
+	ClientID string `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	// ClientVersion is reported with ClientID; defaults to "1.0".
+	ClientVersion string `json:"clientVersion,omitempty" yaml:"clientVersion,omitempty"`
+	// ThreatTypes lists the threats to look up; defaults to all ThreatType values.
+	ThreatTypes []ThreatType `json:"threatTypes,omitempty" yaml:"threatTypes,omitempty"`
+	// Endpoint overrides DefaultSafeBrowsingEndpoint, e.g. for tests.
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	// Client is the HTTP client to use; defaults to a client with DefaultHTTPTimeout.
+	Client *http.Client `json:"-" yaml:"-"`
+}
+
+// SafeBrowsingChecker looks up URLs with the Google Safe Browsing Lookup
+// API. Each lookup is a request to Google, so it should be wrapped in a
+// CachedReputationChecker. It is safe for concurrent use.
+type SafeBrowsingChecker struct {
+	cfg SafeBrowsingConfig
+}
+
+// NewSafeBrowsingChecker creates a SafeBrowsingChecker from cfg.
+func NewSafeBrowsingChecker(cfg SafeBrowsingConfig) *SafeBrowsingChecker {
+	if cfg.ClientID == "" {
+		cfg.ClientID = "kopexa"
+	}
+
+	if cfg.ClientVersion == "" {
+		cfg.ClientVersion = "1.0"
+	}
+
+	if len(cfg.ThreatTypes) == 0 {
+		cfg.ThreatTypes = defaultSafeBrowsingThreats
+	}
+
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultSafeBrowsingEndpoint
+	}
+
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+
+	return &SafeBrowsingChecker{cfg: cfg}
+}
+
+// safeBrowsingRequest is the body of a threatMatches:find request.
+type safeBrowsingRequest struct {
+	Client struct {
+		ClientID      string `json:"clientId"`
+		ClientVersion string `json:"clientVersion"`
+	} `json:"client"`
+	ThreatInfo struct {
+		ThreatTypes      []ThreatType        `json:"threatTypes"`
+		PlatformTypes    []string            `json:"platformTypes"`
+		ThreatEntryTypes []string            `json:"threatEntryTypes"`
+		ThreatEntries    []map[string]string `json:"threatEntries"`
+	} `json:"threatInfo"`
+}
+
+// safeBrowsingResponse is the body of a threatMatches:find response. It is
+// empty if the URL is not listed.
+type safeBrowsingResponse struct {
+	Matches []struct {
+		ThreatType ThreatType `json:"threatType"`
+	} `json:"matches"`
+}
+
+// CheckURL implements ReputationChecker.
+func (c *SafeBrowsingChecker) CheckURL(ctx context.Context, rawURL string) (ReputationResult, error) {
+	if c.cfg.APIKey == "" {
+		return ReputationResult{}, errors.New(ErrCodeInvalidValidatorOptions, "Safe Browsing API key is required")
+	}
+
+	var body safeBrowsingRequest
+
+	body.Client.ClientID = c.cfg.ClientID
+	body.Client.ClientVersion = c.cfg.ClientVersion
+	body.ThreatInfo.ThreatTypes = c.cfg.ThreatTypes
+	body.ThreatInfo.PlatformTypes = []string{"ANY_PLATFORM"}
+	body.ThreatInfo.ThreatEntryTypes = []string{"URL"}
+	body.ThreatInfo.ThreatEntries = []map[string]string{{"url": rawURL}}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return ReputationResult{}, err
+	}
+
+	endpoint := c.cfg.Endpoint + "?key=" + url.QueryEscape(c.cfg.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return ReputationResult{}, errors.New(ErrCodeRequestCreationFailed, fmt.Sprintf("Failed to create Safe Browsing request: %v", err))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", DefaultUserAgent)
+
+	resp, err := c.cfg.Client.Do(req)
+	if err != nil {
+		// The error may contain the request URL with the API key.
+		return ReputationResult{}, errors.New(ErrCodeHTTPRequestFailed, "Safe Browsing request failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ReputationResult{}, errors.New(ErrCodeNonSuccessStatusCode, fmt.Sprintf("Safe Browsing returned status %d", resp.StatusCode))
+	}
+
+	var out safeBrowsingResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSafeBrowsingResponse)).Decode(&out); err != nil {
+		return ReputationResult{}, errors.New(ErrCodeHTTPRequestFailed, fmt.Sprintf("Failed to decode Safe Browsing response: %v", err))
+	}
+
+	var res ReputationResult
+
+	for _, m := range out.Matches {
+		if !slices.Contains(res.Threats, m.ThreatType) {
+			res.Threats = append(res.Threats, m.ThreatType)
+		}
+	}
+
+	if !res.Safe() {
+		res.Source = "safebrowsing"
+	}
+
+	return res, nil
+}
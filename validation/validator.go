@@ -0,0 +1,310 @@
+// Copyright (c) Kopexa GmbH
+// SPDX-License-Identifier: BUSL-1.1
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kopexa-grc/common/errors"
+)
+
+// Error codes for compiled validators.
+const (
+	// ErrCodeHostNotAllowed indicates that the URL host is not on the allow-list
+	// or is on the deny-list of the validator.
+	ErrCodeHostNotAllowed = "VALIDATION_HOST_NOT_ALLOWED"
+
+	// ErrCodePatternMismatch indicates that a value does not match the pattern
+	// configured for the validator.
+	ErrCodePatternMismatch = "VALIDATION_PATTERN_MISMATCH"
+
+	// ErrCodeInvalidValidatorOptions indicates that a validator could not be
+	// compiled from its options, e.g. because of an invalid regular expression.
+	ErrCodeInvalidValidatorOptions = "VALIDATION_INVALID_VALIDATOR_OPTIONS"
+)
+
+// Validator validates a single string value.
+//
+// Implementations must be safe for concurrent use; all validators in this
+// package are immutable after construction.
+type Validator interface {
+	Validate(value string) error
+}
+
+// ValidatorFunc adapts an ordinary function to the Validator interface.
+type ValidatorFunc func(value string) error
+
+// Validate calls f(value).
+func (f ValidatorFunc) Validate(value string) error {
+	return f(value)
+}
+
+// URLOptions configures a URLValidator.
+//
+// All fields are optional; the zero value accepts every URL that passes
+// IsValidURL.
+type URLOptions struct {
+	// Schemes restricts the accepted schemes, e.g. ["https"].
+	// Only schemes supported by IsValidURL may be listed.
+	Schemes []string `json:"schemes,omitempty" yaml:"schemes,omitempty"`
+
+	// AllowedHosts is the allow-list of hosts. Entries are matched
+	// case-insensitively; an entry of the form "*.example.com" matches all
+	// subdomains of example.com but not example.com itself.
+	AllowedHosts []string `json:"allowedHosts,omitempty" yaml:"allowedHosts,omitempty"`
+
+	// DeniedHosts is the deny-list of hosts, using the same syntax as
+	// AllowedHosts. It takes precedence over AllowedHosts.
+	DeniedHosts []string `json:"deniedHosts,omitempty" yaml:"deniedHosts,omitempty"`
+
+	// PathPattern is a regular expression the URL path must match.
+	PathPattern string `json:"pathPattern,omitempty" yaml:"pathPattern,omitempty"`
+
+	// MaxLength limits the URL length. It cannot exceed MaxURLLength.
+	MaxLength int `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+}
+
+// hostMatcher matches hosts against exact names and wildcard suffixes.
+type hostMatcher struct {
+	exact    map[string]struct{}
+	suffixes []string
+}
+
+func newHostMatcher(hosts []string) *hostMatcher {
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	m := &hostMatcher{exact: make(map[string]struct{}, len(hosts))}
+
+	for _, h := range hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if suffix, ok := strings.CutPrefix(h, "*"); ok {
+			m.suffixes = append(m.suffixes, suffix)
+			continue
+		}
+
+		m.exact[h] = struct{}{}
+	}
+
+	return m
+}
+
+func (m *hostMatcher) match(host string) bool {
+	if _, ok := m.exact[host]; ok {
+		return true
+	}
+
+	for _, suffix := range m.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// URLValidator validates URLs against a compiled set of URLOptions and
+// an optional Profile. It is safe for concurrent use.
+type URLValidator struct {
+	schemes         []string
+	allowed         *hostMatcher
+	denied          *hostMatcher
+	path            *regexp.Regexp
+	maxLength       int
+	privateNetworks PrivateNetworkPolicy
+	reachability    bool
+}
+
+// NewURLValidator compiles the given options into a URLValidator.
+//
+// Returns an error with code ErrCodeInvalidValidatorOptions if the options
+// are invalid, e.g. an unsupported scheme or a malformed PathPattern.
+func NewURLValidator(opts URLOptions) (*URLValidator, error) {
+	return NewURLValidatorWithProfile(opts, Profile{})
+}
+
+// NewURLValidatorWithProfile compiles the given options into a URLValidator
+// that additionally enforces profile. The schemes of opts are intersected
+// with those of the profile, and the smaller MaxLength applies.
+//
+// Returns an error with code ErrCodeInvalidProfile if the profile is invalid
+// and ErrCodeInvalidValidatorOptions if the options are invalid or allow no
+// scheme of the profile.
+func NewURLValidatorWithProfile(opts URLOptions, profile Profile) (*URLValidator, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+
+	v := &URLValidator{
+		allowed:         newHostMatcher(opts.AllowedHosts),
+		denied:          newHostMatcher(opts.DeniedHosts),
+		maxLength:       MaxURLLength,
+		privateNetworks: profile.PrivateNetworks,
+		reachability:    profile.CheckReachability,
+	}
+
+	for _, s := range opts.Schemes {
+		s = strings.ToLower(s)
+		if !slices.Contains(supportedSchemes, s) {
+			return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("Unsupported URL scheme '%s'. Only %v are supported", s, supportedSchemes))
+		}
+
+		if len(profile.Schemes) > 0 && !slices.ContainsFunc(profile.Schemes, func(p string) bool { return strings.EqualFold(p, s) }) {
+			continue
+		}
+
+		v.schemes = append(v.schemes, s)
+	}
+
+	switch {
+	case len(opts.Schemes) == 0:
+		for _, s := range profile.Schemes {
+			v.schemes = append(v.schemes, strings.ToLower(s))
+		}
+	case len(v.schemes) == 0:
+		return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("None of the URL schemes %v is allowed by profile '%s'", opts.Schemes, profile.Name))
+	}
+
+	if opts.PathPattern != "" {
+		re, err := regexp.Compile(opts.PathPattern)
+		if err != nil {
+			return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("Invalid path pattern: %v", err))
+		}
+
+		v.path = re
+	}
+
+	if opts.MaxLength < 0 || opts.MaxLength > MaxURLLength {
+		return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("Max length must be between 0 and %d", MaxURLLength))
+	}
+
+	if opts.MaxLength > 0 {
+		v.maxLength = opts.MaxLength
+	}
+
+	if profile.MaxLength > 0 && profile.MaxLength < v.maxLength {
+		v.maxLength = profile.MaxLength
+	}
+
+	return v, nil
+}
+
+// Validate checks the URL syntax with IsValidURL and then applies the
+// compiled options. See ValidateContext for validators whose profile
+// checks reachability.
+func (v *URLValidator) Validate(rawURL string) error {
+	return v.ValidateContext(context.Background(), rawURL)
+}
+
+// ValidateContext validates rawURL like Validate. If the profile of the
+// validator checks reachability, the URL is then requested within the
+// deadline of ctx and every redirect target must pass the validator too.
+func (v *URLValidator) ValidateContext(ctx context.Context, rawURL string) error {
+	if err := v.validate(rawURL); err != nil {
+		return err
+	}
+
+	if !v.reachability {
+		return nil
+	}
+
+	redirects := *v
+	redirects.reachability = false
+
+	return CheckURLReachabilityWithPolicy(ctx, rawURL, RedirectPolicy{
+		Validator:            &redirects,
+		BlockPrivateNetworks: v.privateNetworks == PrivateNetworksDeny,
+	}).Err
+}
+
+// validate applies the syntax checks and compiled options to rawURL.
+func (v *URLValidator) validate(rawURL string) error {
+	if len(rawURL) > v.maxLength {
+		return errors.New(ErrCodeURLTooLong, fmt.Sprintf("URL length %d exceeds maximum allowed length of %d", len(rawURL), v.maxLength))
+	}
+
+	u, err := url.Parse(rawURL)
+	if err == nil && u.Scheme == "" {
+		u, err = url.Parse("http://" + rawURL)
+	}
+
+	var host string
+	if err == nil {
+		host = strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	}
+
+	switch {
+	case v.privateNetworks == PrivateNetworksAllow && isInternalHost(host):
+		// IsValidURL rejects single-label hosts like localhost.
+		if !slices.Contains(supportedSchemes, u.Scheme) {
+			return errors.New(ErrCodeUnsupportedScheme, fmt.Sprintf("Unsupported URL scheme '%s'. Only %v are supported", u.Scheme, supportedSchemes))
+		}
+	default:
+		if err := IsValidURL(rawURL); err != nil {
+			return err
+		}
+	}
+
+	if err != nil {
+		return errors.New(ErrCodeInvalidURL, fmt.Sprintf("URL parsing failed: %v", err))
+	}
+
+	if len(v.schemes) > 0 && !slices.Contains(v.schemes, strings.ToLower(u.Scheme)) {
+		return errors.New(ErrCodeUnsupportedScheme, fmt.Sprintf("Unsupported URL scheme '%s'. Only %v are allowed", u.Scheme, v.schemes))
+	}
+
+	if v.privateNetworks == PrivateNetworksDeny && isInternalHost(host) {
+		return errors.New(ErrCodePrivateNetwork, fmt.Sprintf("Host '%s' is an internal address", host))
+	}
+
+	if v.denied != nil && v.denied.match(host) {
+		return errors.New(ErrCodeHostNotAllowed, fmt.Sprintf("Host '%s' is not allowed", host))
+	}
+
+	if v.allowed != nil && !v.allowed.match(host) {
+		return errors.New(ErrCodeHostNotAllowed, fmt.Sprintf("Host '%s' is not allowed", host))
+	}
+
+	if v.path != nil && !v.path.MatchString(u.Path) {
+		return errors.New(ErrCodePatternMismatch, fmt.Sprintf("URL path '%s' does not match the required pattern", u.Path))
+	}
+
+	return nil
+}
+
+// PatternValidator validates values against a compiled regular expression.
+// It is safe for concurrent use.
+type PatternValidator struct {
+	re *regexp.Regexp
+}
+
+// NewPatternValidator compiles pattern into a PatternValidator.
+func NewPatternValidator(pattern string) (*PatternValidator, error) {
+	if pattern == "" {
+		return nil, errors.New(ErrCodeInvalidValidatorOptions, "Pattern cannot be empty")
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.New(ErrCodeInvalidValidatorOptions, fmt.Sprintf("Invalid pattern: %v", err))
+	}
+
+	return &PatternValidator{re: re}, nil
+}
+
+// Validate reports an error with code ErrCodePatternMismatch if value does
+// not match the pattern.
+func (v *PatternValidator) Validate(value string) error {
+	if !v.re.MatchString(value) {
+		return errors.New(ErrCodePatternMismatch, "Value does not match the required pattern")
+	}
+
+	return nil
+}